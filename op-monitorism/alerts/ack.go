@@ -0,0 +1,135 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrUnknownAlert is returned when acknowledging or resolving an alert that is not active.
+var ErrUnknownAlert = errors.New("unknown alert")
+
+// ActiveAlert is an unresolved alert re-notified or escalated by its route.
+type ActiveAlert struct {
+	ID         string    `json:"id"`
+	Alert      Alert     `json:"alert"`
+	Since      time.Time `json:"since"`
+	Renotified int       `json:"renotified"`
+	Escalated  bool      `json:"escalated"`
+
+	AcknowledgedBy string     `json:"acknowledgedBy,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+}
+
+// Active returns the active alerts, the oldest first.
+func (d *Dispatcher) Active() []ActiveAlert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	alerts := make([]ActiveAlert, 0, len(d.active))
+	for _, active := range d.active {
+		alert := ActiveAlert{
+			ID:             active.alert.ID(),
+			Alert:          active.alert,
+			Since:          active.since,
+			Renotified:     active.renotified,
+			Escalated:      active.escalated,
+			AcknowledgedBy: active.acknowledgedBy,
+		}
+		if !active.acknowledgedAt.IsZero() {
+			at := active.acknowledgedAt
+			alert.AcknowledgedAt = &at
+		}
+		alerts = append(alerts, alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if !alerts[i].Since.Equal(alerts[j].Since) {
+			return alerts[i].Since.Before(alerts[j].Since)
+		}
+		return alerts[i].ID < alerts[j].ID
+	})
+	return alerts
+}
+
+// lookup returns the key of the active alert of the ID.
+func (d *Dispatcher) lookup(id string) (string, *activeAlert, error) {
+	for key, active := range d.active {
+		if active.alert.ID() == id {
+			return key, active, nil
+		}
+	}
+	return "", nil, fmt.Errorf("%w %s", ErrUnknownAlert, id)
+}
+
+// Acknowledge stops the re-notifications and the pending escalation of the active alert, which stays active until
+// resolved so that its resolution reaches the notifiers it was escalated to.
+func (d *Dispatcher) Acknowledge(id string, by string, comment string) error {
+	d.mu.Lock()
+	_, active, err := d.lookup(id)
+	if err != nil {
+		d.mu.Unlock()
+		return err
+	}
+	now := d.now()
+	active.acknowledgedBy, active.acknowledgedAt = by, now
+	alert := active.alert
+	d.mu.Unlock()
+
+	d.log.Info("alert acknowledged", "key", alert.Key(), "by", by)
+	d.record(AuditRecord{Time: now, Action: AuditAcknowledged, Alert: alert, By: by, Comment: comment})
+	return nil
+}
+
+// OnResolve registers a function called with the alerts resolved via Resolve, e.g. the Tracker of the monitor
+// forgetting the condition so that it is alerted again while still holding rather than resolved twice.
+func (d *Dispatcher) OnResolve(fn func(alert Alert)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onResolve = append(d.onResolve, fn)
+}
+
+// Resolve resolves the active alert on behalf of the monitor, e.g. a detection the monitor never resolves, sending
+// the resolution to the notifiers of its route and of its escalation. The notifiers are collected under the lock and
+// the resolution delivered without it.
+func (d *Dispatcher) Resolve(ctx context.Context, id string, by string, comment string) error {
+	d.mu.Lock()
+	key, active, err := d.lookup(id)
+	if err != nil {
+		d.mu.Unlock()
+		return err
+	}
+	now := d.now()
+	alert := active.alert
+	alert.Resolved, alert.Time = true, now
+
+	notifiers := d.selectNotifiers(active.route.Notifiers)
+	for _, escalated := range d.selectNotifiers(active.escalatedTo) {
+		if !containsNotifier(notifiers, escalated) {
+			notifiers = append(notifiers, escalated)
+		}
+	}
+	onResolve := d.onResolve
+	d.mu.Unlock()
+
+	d.log.Info("alert resolved", "key", key, "by", by)
+	deliveries, err := d.deliver(ctx, alert, notifiers)
+	d.record(AuditRecord{Time: now, Action: AuditResolved, Alert: alert, Deliveries: deliveries, By: by, Comment: comment})
+	if err != nil {
+		// kept active, the resolution being retried by resolving it again.
+		return err
+	}
+
+	d.mu.Lock()
+	if d.active[key] == active {
+		delete(d.active, key)
+		active.resolution = &alert
+	}
+	d.mu.Unlock()
+	d.dedup.Notified(alert, now)
+	for _, fn := range onResolve {
+		fn(alert)
+	}
+	return nil
+}
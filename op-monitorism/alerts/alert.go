@@ -0,0 +1,173 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Priorities are the priorities of the alerts, from the most to the least urgent.
+var Priorities = []string{"P0", "P1", "P2", "P3", "P4", "P5"}
+
+// ValidPriority reports whether the priority is one of the Priorities.
+func ValidPriority(priority string) bool {
+	for _, valid := range Priorities {
+		if priority == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Alert is a detection emitted by a monitor, the notifiers deciding how it is delivered.
+type Alert struct {
+	// Monitor is the name of the monitor emitting the alert, e.g. `global_events`.
+	Monitor string `json:"monitor"`
+	// Rule is the name of the detection within the monitor.
+	Rule string `json:"rule"`
+	// Priority is the priority of the rule, from `P0` (the most urgent) to `P5`.
+	Priority string `json:"priority"`
+	// Entity is the object the alert is about, usually an address.
+	Entity string `json:"entity,omitempty"`
+
+	Summary string            `json:"summary,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+
+	// Resolved is set when a previously emitted detection clears.
+	Resolved bool      `json:"resolved"`
+	Time     time.Time `json:"time"`
+}
+
+// Key identifies the detection across its occurrences, a resolved alert sharing the key of the alert it clears.
+func (a Alert) Key() string {
+	return fmt.Sprintf("%s/%s/%s", a.Monitor, a.Rule, a.Entity)
+}
+
+// ID is the short identifier of the detection, the hash of its key, e.g. to acknowledge it via the API.
+func (a Alert) ID() string {
+	hash := sha256.Sum256([]byte(a.Key()))
+	return hex.EncodeToString(hash[:6])
+}
+
+// Title returns the one-line description of the alert.
+func (a Alert) Title() string {
+	status := a.Priority
+	if a.Resolved {
+		status = "RESOLVED"
+	}
+	return fmt.Sprintf("[%s] %s: %s", status, a.Monitor, a.Rule)
+}
+
+// SortedLabels returns the names of the labels in alphabetical order.
+func (a Alert) SortedLabels() []string {
+	names := make([]string, 0, len(a.Labels))
+	for name := range a.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// plainText formats the alert as plain text, e.g. sparing the escaping of the Markdown and HTML modes of Telegram.
+func plainText(alert Alert) string {
+	var b strings.Builder
+	b.WriteString(alert.Title())
+	if len(alert.Summary) > 0 {
+		b.WriteString("\n" + alert.Summary)
+	}
+	b.WriteString("\n")
+	if len(alert.Entity) > 0 {
+		b.WriteString("\nentity: " + alert.Entity)
+	}
+	for _, name := range alert.SortedLabels() {
+		b.WriteString(fmt.Sprintf("\n%s: %s", name, alert.Labels[name]))
+	}
+	return b.String()
+}
+
+// priorityColors are the colors of the alerts by priority, the less urgent priorities defaulting to yellow.
+var priorityColors = map[string]string{
+	"P0": "#b00020",
+	"P1": "#e01e5a",
+	"P2": "#ff8c00",
+}
+
+const (
+	defaultColor  = "#ecb22e"
+	resolvedColor = "#2eb67d"
+)
+
+// alertColor returns the color of the alert in the chat notifiers, green once resolved.
+func alertColor(alert Alert) string {
+	if alert.Resolved {
+		return resolvedColor
+	}
+	color, ok := priorityColors[alert.Priority]
+	if !ok {
+		return defaultColor
+	}
+	return color
+}
+
+// Notifier delivers the alerts to a destination.
+type Notifier interface {
+	// Name identifies the notifier in the logs and the metrics.
+	Name() string
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// Emitter is implemented by the monitors emitting alerts, the notifier being set before the monitor is started.
+type Emitter interface {
+	SetNotifier(notifier Notifier)
+}
+
+// NopNotifier drops the alerts, it is the notifier of the monitors when alerting is not configured.
+type NopNotifier struct{}
+
+func (NopNotifier) Name() string                            { return "nop" }
+func (NopNotifier) Notify(_ context.Context, _ Alert) error { return nil }
+
+// postJSON posts the payload encoded in JSON with the optional headers, the non-2xx statuses being errors.
+func postJSON(ctx context.Context, client *http.Client, target string, header http.Header, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode the payload: %w", err)
+	}
+	return post(ctx, client, target, header, body)
+}
+
+// post posts the JSON body as is with the optional headers, the non-2xx statuses being errors.
+func post(ctx context.Context, client *http.Client, target string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create the request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// the url of the webhooks embeds their secret, it is dropped from the error.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post the alert: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// alertmanagerLabelName matches the valid label names of Prometheus.
+var alertmanagerLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ParseAlertmanagerLabels parses the static labels formatted via `name=value`.
+func ParseAlertmanagerLabels(values []string) (map[string]string, error) {
+	labels := make(map[string]string, len(values))
+	for _, value := range values {
+		split := strings.SplitN(value, "=", 2)
+		if len(split) != 2 || !alertmanagerLabelName.MatchString(split[0]) {
+			return nil, fmt.Errorf("failed to parse `name=value`: %s", value)
+		}
+		switch split[0] {
+		case "alertname", "monitor", "priority", "entity":
+			return nil, fmt.Errorf("label %s is set from the alert", split[0])
+		}
+		if _, ok := labels[split[0]]; ok {
+			return nil, fmt.Errorf("duplicated label %s", split[0])
+		}
+		labels[split[0]] = split[1]
+	}
+	return labels, nil
+}
+
+type alertmanagerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    string            `json:"startsAt,omitempty"`
+	EndsAt      string            `json:"endsAt,omitempty"`
+}
+
+// AlertmanagerNotifier posts the alerts to the Alertmanager v2 API, reusing its routing, silences and inhibitions
+// without waiting for a scrape and a rule evaluation.
+//
+// The labels identify the detection: `alertname` is the rule, `monitor`, `priority` and `entity` come from the alert,
+// next to the static labels (e.g. the chain). The labels of the alert differing between the occurrences of a detection
+// (e.g. the transaction hash), they are annotations next to the `summary` and the `description`, so that a resolved
+// alert matches the alert it clears.
+type AlertmanagerNotifier struct {
+	url        string
+	labels     map[string]string
+	ttl        time.Duration
+	httpClient *http.Client
+}
+
+func NewAlertmanagerNotifier(url string, labels map[string]string, ttl time.Duration, timeout time.Duration) *AlertmanagerNotifier {
+	return &AlertmanagerNotifier{
+		url:        strings.TrimSuffix(url, "/") + "/api/v2/alerts",
+		labels:     labels,
+		ttl:        ttl,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *AlertmanagerNotifier) Name() string {
+	return "alertmanager"
+}
+
+func (n *AlertmanagerNotifier) Notify(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, n.httpClient, n.url, nil, []alertmanagerAlert{n.payload(alert)})
+}
+
+// payload returns the Alertmanager alert, a resolved alert ending at its time. Without a ttl, a firing alert has
+// no end and Alertmanager resolves it after its `resolve_timeout`.
+func (n *AlertmanagerNotifier) payload(alert Alert) alertmanagerAlert {
+	at := alert.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	labels := make(map[string]string, len(n.labels)+4)
+	for name, value := range n.labels {
+		labels[name] = value
+	}
+	labels["alertname"] = alert.Rule
+	labels["monitor"] = alert.Monitor
+	labels["priority"] = alert.Priority
+	if len(alert.Entity) > 0 {
+		labels["entity"] = alert.Entity
+	}
+
+	annotations := make(map[string]string, len(alert.Labels)+2)
+	for name, value := range alert.Labels {
+		annotations[name] = value
+	}
+	annotations["summary"] = alert.Title()
+	if len(alert.Summary) > 0 {
+		annotations["description"] = alert.Summary
+	}
+
+	payload := alertmanagerAlert{Labels: labels, Annotations: annotations}
+	if alert.Resolved {
+		payload.EndsAt = at.UTC().Format(time.RFC3339)
+		return payload
+	}
+	payload.StartsAt = at.UTC().Format(time.RFC3339)
+	if n.ttl > 0 {
+		payload.EndsAt = at.Add(n.ttl).UTC().Format(time.RFC3339)
+	}
+	return payload
+}
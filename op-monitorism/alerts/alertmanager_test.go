@@ -0,0 +1,100 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseAlertmanagerLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		ok     bool
+	}{
+		{"valid", []string{"chain=mainnet", "team=security"}, true},
+		{"empty value", []string{"chain="}, true},
+		{"invalid name", []string{"chain-id=1"}, false},
+		{"missing separator", []string{"chain"}, false},
+		{"reserved", []string{"alertname=other"}, false},
+		{"duplicated", []string{"chain=mainnet", "chain=sepolia"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseAlertmanagerLabels(test.values); (err == nil) != test.ok {
+				t.Errorf("Failed %s: expected ok=%v but got %v", test.name, test.ok, err)
+			}
+		})
+	}
+}
+
+func TestAlertmanagerPayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolved bool
+		ttl      time.Duration
+		startsAt string
+		endsAt   string
+	}{
+		{"firing", false, 0, "2023-11-14T22:13:20Z", ""},
+		{"firing with ttl", false, time.Hour, "2023-11-14T22:13:20Z", "2023-11-14T23:13:20Z"},
+		{"resolved", true, time.Hour, "", "2023-11-14T22:13:20Z"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n := NewAlertmanagerNotifier("http://127.0.0.1:9093", map[string]string{"chain": "mainnet"}, test.ttl, time.Second)
+			alert := testAlert()
+			alert.Resolved = test.resolved
+
+			payload := n.payload(alert)
+			if payload.StartsAt != test.startsAt || payload.EndsAt != test.endsAt {
+				t.Errorf("Failed %s: expected %s-%s but got %s-%s", test.name, test.startsAt, test.endsAt, payload.StartsAt, payload.EndsAt)
+			}
+			// the labels identify the detection, a resolved alert matching the firing one.
+			expected := map[string]string{
+				"alertname": "Safe owner changed",
+				"monitor":   "global_events",
+				"priority":  "P1",
+				"entity":    alert.Entity,
+				"chain":     "mainnet",
+			}
+			if len(payload.Labels) != len(expected) {
+				t.Errorf("Failed %s: expected the labels %v but got %v", test.name, expected, payload.Labels)
+			}
+			for name, value := range expected {
+				if payload.Labels[name] != value {
+					t.Errorf("Failed %s: expected the label %s=%s but got %s", test.name, name, value, payload.Labels[name])
+				}
+			}
+			if payload.Annotations["txHash"] != "0x01" || payload.Annotations["summary"] != alert.Title() || payload.Annotations["description"] != alert.Summary {
+				t.Errorf("Failed %s: unexpected annotations %v", test.name, payload.Annotations)
+			}
+		})
+	}
+}
+
+func TestAlertmanagerNotifier(t *testing.T) {
+	var received []alertmanagerAlert
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/alerts" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode the alerts: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	n := NewAlertmanagerNotifier(srv.URL+"/", nil, 0, time.Second)
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(received) != 1 || received[0].Labels["alertname"] != "Safe owner changed" {
+		t.Errorf("expected the alert to be posted but got %v", received)
+	}
+}
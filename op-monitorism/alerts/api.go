@@ -0,0 +1,159 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// AcknowledgeRequest is the body of the acknowledgment and the resolution of an active alert.
+type AcknowledgeRequest struct {
+	By      string `json:"by"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// APIHandler serves the active alerts of the dispatcher:
+//   - `GET /alerts` lists the active alerts.
+//   - `POST /alerts/{id}/ack` acknowledges the active alert.
+//   - `POST /alerts/{id}/resolve` resolves the active alert.
+//
+// The requests must carry the token as bearer when set.
+type APIHandler struct {
+	log        log.Logger
+	dispatcher *Dispatcher
+	token      string
+}
+
+func NewAPIHandler(log log.Logger, dispatcher *Dispatcher, token string) *APIHandler {
+	return &APIHandler{log: log, dispatcher: dispatcher, token: token}
+}
+
+func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if len(h.token) > 0 && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+h.token)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, apiError{Error: "unauthorized"})
+		return
+	}
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(path) == 1 && path[0] == "alerts":
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, apiError{Error: "method not allowed"})
+			return
+		}
+		writeJSON(w, http.StatusOK, h.dispatcher.Active())
+	case len(path) == 3 && path[0] == "alerts" && (path[2] == "ack" || path[2] == "resolve"):
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, apiError{Error: "method not allowed"})
+			return
+		}
+		var req AcknowledgeRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&req); err != nil || len(req.By) == 0 {
+			writeJSON(w, http.StatusBadRequest, apiError{Error: "expected a JSON body with `by`"})
+			return
+		}
+
+		var err error
+		if path[2] == "ack" {
+			err = h.dispatcher.Acknowledge(path[1], req.By, req.Comment)
+		} else {
+			err = h.dispatcher.Resolve(r.Context(), path[1], req.By, req.Comment)
+		}
+		switch {
+		case errors.Is(err, ErrUnknownAlert):
+			writeJSON(w, http.StatusNotFound, apiError{Error: err.Error()})
+		case err != nil:
+			h.log.Error("failed to resolve the alert", "id", path[1], "err", err)
+			writeJSON(w, http.StatusBadGateway, apiError{Error: err.Error()})
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		writeJSON(w, http.StatusNotFound, apiError{Error: "not found"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// APIClient lists, acknowledges and resolves the active alerts of a monitor via its API.
+type APIClient struct {
+	url        string
+	token      string
+	httpClient *http.Client
+}
+
+func NewAPIClient(url string, token string, timeout time.Duration) *APIClient {
+	return &APIClient{url: strings.TrimSuffix(url, "/"), token: token, httpClient: &http.Client{Timeout: timeout}}
+}
+
+func (c *APIClient) Active(ctx context.Context) ([]ActiveAlert, error) {
+	var alerts []ActiveAlert
+	if err := c.do(ctx, http.MethodGet, "/alerts", nil, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+func (c *APIClient) Acknowledge(ctx context.Context, id string, by string, comment string) error {
+	return c.do(ctx, http.MethodPost, "/alerts/"+id+"/ack", AcknowledgeRequest{By: by, Comment: comment}, nil)
+}
+
+func (c *APIClient) Resolve(ctx context.Context, id string, by string, comment string) error {
+	return c.do(ctx, http.MethodPost, "/alerts/"+id+"/resolve", AcknowledgeRequest{By: by, Comment: comment}, nil)
+}
+
+func (c *APIClient) do(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode the request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create the request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(c.token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var apiErr apiError
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err == nil && len(apiErr.Error) > 0 {
+			return fmt.Errorf("%s: %s", resp.Status, apiErr.Error)
+		}
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode the response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestAlertID(t *testing.T) {
+	alert := testAlert()
+	resolved := testAlert()
+	resolved.Resolved, resolved.Labels = true, nil
+	if alert.ID() != resolved.ID() || len(alert.ID()) != 12 {
+		t.Errorf("expected the alerts of a detection to share a 12 characters id but got %s and %s", alert.ID(), resolved.ID())
+	}
+	other := testAlert()
+	other.Entity = "0x02"
+	if alert.ID() == other.ID() {
+		t.Errorf("expected the alerts of different detections to have different ids")
+	}
+}
+
+func newEscalatingDispatcher(t *testing.T, audit *AuditLog) (*Dispatcher, *recordingNotifier, *recordingNotifier) {
+	pagerduty, slack := &recordingNotifier{name: "pagerduty"}, &recordingNotifier{name: "slack"}
+	notifiers := []Notifier{pagerduty, slack}
+	routing, err := LoadRouting(writeConfig(t, "routing.yaml", testEscalationRouting), notifiers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := NewDispatcher(log.New(), metrics.With(prometheus.NewRegistry()), notifiers, DispatcherConfig{Dedup: NewDeduplicator(time.Hour, nil), Routing: routing, Audit: audit})
+	return d, pagerduty, slack
+}
+
+func TestAPI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit, err := OpenAuditLog(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d, pagerduty, slack := newEscalatingDispatcher(t, audit)
+	start := time.Unix(1700000000, 0)
+
+	srv := httptest.NewServer(NewAPIHandler(log.New(), d, "secret"))
+	defer srv.Close()
+	client := NewAPIClient(srv.URL, "secret", time.Second)
+	ctx := context.Background()
+
+	acknowledged, escalated := testAlert(), testAlert()
+	escalated.Entity = "0x02"
+	for i, alert := range []Alert{acknowledged, escalated} {
+		d.now = func() time.Time { return start.Add(time.Duration(i) * time.Second) }
+		if err := d.Notify(ctx, alert); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	active, err := client.Active(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(active) != 2 {
+		t.Fatalf("expected 2 active alerts but got %v", active)
+	}
+
+	if _, err := NewAPIClient(srv.URL, "wrong", time.Second).Active(ctx); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected the request with a wrong token to be unauthorized but got %v", err)
+	}
+	if err := client.Acknowledge(ctx, "000000000000", "alice", ""); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected an unknown alert but got %v", err)
+	}
+	if err := client.Acknowledge(ctx, acknowledged.ID(), "", ""); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("expected the acknowledgment without `by` to be rejected but got %v", err)
+	}
+
+	// the acknowledged alert is neither re-notified nor escalated, unlike the other one.
+	if err := client.Acknowledge(ctx, acknowledged.ID(), "alice", "looking into it"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.now = func() time.Time { return start.Add(time.Hour) }
+	d.Escalate(ctx)
+	if len(slack.alerts) != 3 || len(pagerduty.alerts) != 1 || pagerduty.alerts[0].Entity != escalated.Entity {
+		t.Fatalf("expected the unacknowledged alert re-notified and escalated only but got %v and %v", slack.alerts, pagerduty.alerts)
+	}
+
+	if active, err = client.Active(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(active) != 2 || active[0].AcknowledgedBy != "alice" || active[0].AcknowledgedAt == nil || !active[1].Escalated {
+		t.Errorf("expected the acknowledged and the escalated alerts but got %v", active)
+	}
+
+	// resolving the escalated alert sends its resolution to slack and pagerduty.
+	if err := client.Resolve(ctx, escalated.ID(), "bob", "false positive"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(slack.alerts) != 4 || !slack.alerts[3].Resolved || len(pagerduty.alerts) != 2 || !pagerduty.alerts[1].Resolved {
+		t.Errorf("expected the resolution sent to slack and pagerduty but got %v and %v", slack.alerts, pagerduty.alerts)
+	}
+	if active, err = client.Active(ctx); err != nil || len(active) != 1 || active[0].ID != acknowledged.ID() {
+		t.Errorf("expected the acknowledged alert active only but got %v (%v)", active, err)
+	}
+
+	if err := audit.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var actions []string
+	for _, record := range readAuditLog(t, path) {
+		switch record.Action {
+		case AuditAcknowledged, AuditResolved:
+			actions = append(actions, record.Action+" by "+record.By+": "+record.Comment)
+		}
+	}
+	if strings.Join(actions, ", ") != "acknowledged by alice: looking into it, resolved by bob: false positive" {
+		t.Errorf("unexpected audit records %v", actions)
+	}
+}
+
+func TestResolveFailure(t *testing.T) {
+	d, _, slack := newEscalatingDispatcher(t, nil)
+	alert := testAlert()
+	if err := d.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a failed resolution keeps the alert active so that it can be resolved again.
+	slack.err = errors.New("unavailable")
+	if err := d.Resolve(context.Background(), alert.ID(), "alice", ""); err == nil {
+		t.Fatalf("expected the resolution to fail")
+	}
+	slack.err = nil
+	if err := d.Resolve(context.Background(), alert.ID(), "alice", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.Resolve(context.Background(), alert.ID(), "alice", ""); !errors.Is(err, ErrUnknownAlert) {
+		t.Errorf("expected the resolved alert to be unknown but got %v", err)
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		addr     string
+		loopback bool
+	}{
+		{"127.0.0.1:7310", true},
+		{"localhost:7310", true},
+		{"[::1]:7310", true},
+		{":7310", false},
+		{"0.0.0.0:7310", false},
+		{"10.0.0.1:7310", false},
+		{"monitor.internal:7310", false},
+		{"127.0.0.1", false},
+	}
+
+	for _, test := range tests {
+		if loopback := isLoopback(test.addr); loopback != test.loopback {
+			t.Errorf("expected %s loopback=%v but got %v", test.addr, test.loopback, loopback)
+		}
+	}
+}
+
+func TestResolveTracker(t *testing.T) {
+	d, _, slack := newEscalatingDispatcher(t, nil)
+	var tracker Tracker
+	tracker.SetNotifier(d)
+	ctx := context.Background()
+	alert := testAlert()
+
+	tracker.Update(ctx, alert, true)
+	if err := d.Resolve(ctx, alert.ID(), "alice", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(slack.alerts) != 2 || !slack.alerts[1].Resolved {
+		t.Fatalf("expected the alert and its resolution but got %v", slack.alerts)
+	}
+
+	// the condition still holding is alerted again, its resolution being sent once it clears.
+	tracker.Update(ctx, alert, true)
+	tracker.Update(ctx, alert, false)
+	if len(slack.alerts) != 4 || slack.alerts[2].Resolved || !slack.alerts[3].Resolved {
+		t.Fatalf("expected the alert again and its resolution but got %v", slack.alerts)
+	}
+
+	// a condition clearing after its resolution via the API is not resolved twice.
+	tracker.Update(ctx, alert, true)
+	if err := d.Resolve(ctx, alert.ID(), "alice", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tracker.Update(ctx, alert, false)
+	if len(slack.alerts) != 6 {
+		t.Errorf("expected a single resolution but got %v", slack.alerts)
+	}
+}
+
+func TestResolveUnlocked(t *testing.T) {
+	notifier := &activeNotifier{recordingNotifier: recordingNotifier{name: "slack"}}
+	routing, err := LoadRouting(writeConfig(t, "routing.yaml", testEscalationRouting), []Notifier{notifier, &recordingNotifier{name: "pagerduty"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := NewDispatcher(log.New(), metrics.With(prometheus.NewRegistry()), []Notifier{notifier}, DispatcherConfig{Routing: routing})
+	notifier.d = d
+
+	alert := testAlert()
+	if err := d.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done := make(chan error)
+	go func() { done <- d.Resolve(context.Background(), alert.ID(), "alice", "") }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the resolution to be delivered without holding the lock")
+	}
+}
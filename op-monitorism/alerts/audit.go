@@ -0,0 +1,83 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// AuditNotified records an alert sent to the notifiers of its route, next to the result of each delivery.
+	AuditNotified = "notified"
+	// AuditSuppressed records an alert suppressed within the cooldown of its detection.
+	AuditSuppressed = "suppressed"
+	// AuditSilenced records an alert held during a silence window.
+	AuditSilenced = "silenced"
+	// AuditRenotified records an unresolved alert re-sent to the notifiers of its route.
+	AuditRenotified = "renotified"
+	// AuditEscalated records an unresolved alert sent to the notifiers of the escalation of its route.
+	AuditEscalated = "escalated"
+	// AuditAcknowledged records an active alert acknowledged via the API, `by` being who acknowledged it.
+	AuditAcknowledged = "acknowledged"
+	// AuditResolved records an active alert resolved via the API, `by` being who resolved it.
+	AuditResolved = "resolved"
+	// AuditUnrouted records an alert matching no route.
+	AuditUnrouted = "unrouted"
+)
+
+// AuditDelivery is the result of the delivery of an alert to a notifier.
+type AuditDelivery struct {
+	Notifier string `json:"notifier"`
+	Result   string `json:"result"`
+	Error    string `json:"error,omitempty"`
+}
+
+// AuditRecord is a line of the audit log.
+type AuditRecord struct {
+	Time       time.Time       `json:"time"`
+	Action     string          `json:"action"`
+	Alert      Alert           `json:"alert"`
+	Silence    string          `json:"silence,omitempty"`
+	By         string          `json:"by,omitempty"`
+	Comment    string          `json:"comment,omitempty"`
+	Deliveries []AuditDelivery `json:"deliveries,omitempty"`
+}
+
+// AuditLog appends a JSON record per line to a file for every alert emitted by the monitor and the result of its
+// delivery, so that the detections can be reconstructed after an incident independently of the notifiers.
+type AuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenAuditLog opens the audit log, created if missing and appended to otherwise.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the audit log: %w", err)
+	}
+	return &AuditLog{file: file}, nil
+}
+
+// Record appends the record to the audit log in a single write, the records of concurrent alerts not interleaving.
+func (l *AuditLog) Record(record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode the audit record: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write the audit record: %w", err)
+	}
+	return nil
+}
+
+func (l *AuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
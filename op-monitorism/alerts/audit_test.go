@@ -0,0 +1,102 @@
+package alerts
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func readAuditLog(t *testing.T, path string) []AuditRecord {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open the audit log: %v", err)
+	}
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("failed to decode the audit record %s: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestDispatcherAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit, err := OpenAuditLog(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	failing, working := &recordingNotifier{name: "pagerduty", err: errors.New("unavailable")}, &recordingNotifier{name: "slack"}
+	notifiers := []Notifier{failing, working}
+	routing := &Routing{Routes: []Route{{Monitors: []string{"global_events"}, Notifiers: []string{"pagerduty", "slack"}}}}
+	d := NewDispatcher(log.New(), metrics.With(prometheus.NewRegistry()), notifiers, DispatcherConfig{Dedup: NewDeduplicator(time.Hour, nil), Routing: routing, Audit: audit})
+
+	alert := testAlert()
+	_ = d.Notify(context.Background(), alert)
+	failing.err = nil
+	_ = d.Notify(context.Background(), alert)
+	_ = d.Notify(context.Background(), alert)
+	_ = d.Notify(context.Background(), Alert{Monitor: "fault", Rule: "output root mismatch", Priority: "P0"})
+	if err := audit.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// reopening the audit log appends to it.
+	if audit, err = OpenAuditLog(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := audit.Record(AuditRecord{Time: alert.Time, Action: AuditSuppressed, Alert: alert}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := audit.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records := readAuditLog(t, path)
+	expected := []struct {
+		action     string
+		deliveries []AuditDelivery
+	}{
+		{AuditNotified, []AuditDelivery{{Notifier: "pagerduty", Result: "failed", Error: "unavailable"}, {Notifier: "slack", Result: "delivered"}}},
+		{AuditNotified, []AuditDelivery{{Notifier: "pagerduty", Result: "delivered"}, {Notifier: "slack", Result: "delivered"}}},
+		{AuditSuppressed, nil},
+		{AuditUnrouted, nil},
+		{AuditSuppressed, nil},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d audit records but got %d", len(expected), len(records))
+	}
+	for i, e := range expected {
+		record := records[i]
+		if record.Action != e.action {
+			t.Errorf("record %d: expected action %s but got %s", i, e.action, record.Action)
+		}
+		if len(record.Deliveries) != len(e.deliveries) {
+			t.Errorf("record %d: expected deliveries %v but got %v", i, e.deliveries, record.Deliveries)
+			continue
+		}
+		for j, delivery := range e.deliveries {
+			if record.Deliveries[j] != delivery {
+				t.Errorf("record %d: expected delivery %v but got %v", i, delivery, record.Deliveries[j])
+			}
+		}
+	}
+	if records[0].Alert.Key() != alert.Key() || records[0].Alert.Labels["txHash"] != "0x01" || !records[0].Alert.Time.Equal(alert.Time) {
+		t.Errorf("expected the alert %v to be recorded but got %v", alert, records[0].Alert)
+	}
+}
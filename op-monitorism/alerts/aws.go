@@ -0,0 +1,181 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	awscredentials "github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// snsMaxSubject is the maximum length of the subject of an SNS message.
+const snsMaxSubject = 100
+
+// AWSCredentials are the static or temporary credentials of the requests to AWS, the default credential chain of the
+// AWS SDK being used when not set: the environment, the shared configuration and credentials files, the web identity
+// of IRSA, and the roles of the ECS tasks and of the EC2 instances, the temporary credentials being refreshed.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// loadAWSConfig loads the configuration of the AWS SDK in the region, with the static credentials when set.
+func loadAWSConfig(region string, credentials AWSCredentials, timeout time.Duration) (aws.Config, error) {
+	options := []func(*config.LoadOptions) error{
+		config.WithRegion(region),
+		config.WithHTTPClient(awshttp.NewBuildableClient().WithTimeout(timeout)),
+	}
+	if len(credentials.AccessKeyID) > 0 {
+		options = append(options, config.WithCredentialsProvider(awscredentials.NewStaticCredentialsProvider(credentials.AccessKeyID, credentials.SecretAccessKey, credentials.SessionToken)))
+	}
+	cfg, err := config.LoadDefaultConfig(context.Background(), options...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load the AWS configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// awsMessage returns the alert encoded in JSON and the attributes of the message, e.g. to filter the alerts by
+// priority in the subscription of a topic.
+func awsMessage(alert Alert) (string, map[string]string, error) {
+	message, err := json.Marshal(alert)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode the alert: %w", err)
+	}
+	attributes := map[string]string{
+		"monitor":  alert.Monitor,
+		"rule":     alert.Rule,
+		"priority": alert.Priority,
+		"resolved": fmt.Sprint(alert.Resolved),
+	}
+	return string(message), attributes, nil
+}
+
+// awsDeduplicationID identifies an occurrence of the alert for the deduplication of the FIFO topics and queues.
+func awsDeduplicationID(alert Alert) string {
+	return fmt.Sprintf("%s-%d-%t", alert.ID(), alert.Time.UnixNano(), alert.Resolved)
+}
+
+// SNSNotifier publishes the alerts to an SNS topic, the alert being the message encoded in JSON, with the
+// monitor, the rule, the priority and the resolution as message attributes.
+type SNSNotifier struct {
+	client   *sns.Client
+	topicARN string
+}
+
+// NewSNSNotifier returns the notifier of the topic, the region being the one of the ARN and the endpoint being the
+// regional endpoint of SNS when not set, e.g. to publish to LocalStack.
+func NewSNSNotifier(topicARN string, endpoint string, credentials AWSCredentials, timeout time.Duration) (*SNSNotifier, error) {
+	arn := strings.Split(topicARN, ":")
+	if len(arn) != 6 || arn[0] != "arn" || arn[2] != "sns" || len(arn[3]) == 0 {
+		return nil, fmt.Errorf("invalid topic ARN %s", topicARN)
+	}
+	cfg, err := loadAWSConfig(arn[3], credentials, timeout)
+	if err != nil {
+		return nil, err
+	}
+	client := sns.NewFromConfig(cfg, func(o *sns.Options) {
+		if len(endpoint) > 0 {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+	return &SNSNotifier{client: client, topicARN: topicARN}, nil
+}
+
+func (n *SNSNotifier) Name() string {
+	return "sns"
+}
+
+func (n *SNSNotifier) Notify(ctx context.Context, alert Alert) error {
+	message, attributes, err := awsMessage(alert)
+	if err != nil {
+		return err
+	}
+	input := &sns.PublishInput{
+		TopicArn:          aws.String(n.topicARN),
+		Message:           aws.String(message),
+		Subject:           aws.String(truncate(alert.Title(), snsMaxSubject)),
+		MessageAttributes: make(map[string]snstypes.MessageAttributeValue, len(attributes)),
+	}
+	for name, value := range attributes {
+		input.MessageAttributes[name] = snstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+	if strings.HasSuffix(n.topicARN, ".fifo") {
+		// the alerts of a detection are delivered in order.
+		input.MessageGroupId, input.MessageDeduplicationId = aws.String(alert.ID()), aws.String(awsDeduplicationID(alert))
+	}
+	if _, err := n.client.Publish(ctx, input); err != nil {
+		return fmt.Errorf("failed to publish the alert: %w", err)
+	}
+	return nil
+}
+
+// SQSNotifier sends the alerts to an SQS queue, the alert being the body encoded in JSON, with the monitor, the
+// rule, the priority and the resolution as message attributes.
+type SQSNotifier struct {
+	client   *sqs.Client
+	queueURL string
+}
+
+// NewSQSNotifier returns the notifier of the queue, the region being the one of the queue URL when not set, e.g.
+// `https://sqs.us-east-1.amazonaws.com/123456789012/alerts`. The requests are sent to the host of the queue URL, e.g.
+// LocalStack.
+func NewSQSNotifier(queueURL string, region string, credentials AWSCredentials, timeout time.Duration) (*SQSNotifier, error) {
+	parsed, err := url.Parse(queueURL)
+	if err != nil || len(parsed.Host) == 0 {
+		return nil, fmt.Errorf("invalid queue URL %s", queueURL)
+	}
+	if len(region) == 0 {
+		host := strings.Split(parsed.Hostname(), ".")
+		if len(host) < 4 || host[0] != "sqs" {
+			return nil, errors.New("the region of the queue URL is unknown")
+		}
+		region = host[1]
+	}
+	cfg, err := loadAWSConfig(region, credentials, timeout)
+	if err != nil {
+		return nil, err
+	}
+	client := sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+		o.BaseEndpoint = aws.String(fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host))
+	})
+	return &SQSNotifier{client: client, queueURL: queueURL}, nil
+}
+
+func (n *SQSNotifier) Name() string {
+	return "sqs"
+}
+
+func (n *SQSNotifier) Notify(ctx context.Context, alert Alert) error {
+	message, attributes, err := awsMessage(alert)
+	if err != nil {
+		return err
+	}
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(n.queueURL),
+		MessageBody:       aws.String(message),
+		MessageAttributes: make(map[string]sqstypes.MessageAttributeValue, len(attributes)),
+	}
+	for name, value := range attributes {
+		input.MessageAttributes[name] = sqstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+	if strings.HasSuffix(n.queueURL, ".fifo") {
+		input.MessageGroupId, input.MessageDeduplicationId = aws.String(alert.ID()), aws.String(awsDeduplicationID(alert))
+	}
+	if _, err := n.client.SendMessage(ctx, input); err != nil {
+		return fmt.Errorf("failed to send the alert: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// awsRequest is a request received by the awsServer.
+type awsRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// form returns the form of a request of the Query API, e.g. of SNS.
+func (r awsRequest) form(t *testing.T) url.Values {
+	form, err := url.ParseQuery(string(r.body))
+	if err != nil {
+		t.Fatalf("failed to parse the form: %v", err)
+	}
+	return form
+}
+
+// awsServer records the requests to AWS, answering with the status and the response.
+func awsServer(t *testing.T, status int, response string) (*httptest.Server, *[]awsRequest) {
+	var requests []awsRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read the request: %v", err)
+		}
+		requests = append(requests, awsRequest{header: r.Header, body: body})
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	return srv, &requests
+}
+
+func TestSNSNotifier(t *testing.T) {
+	srv, requests := awsServer(t, http.StatusOK, `<PublishResponse xmlns="http://sns.amazonaws.com/doc/2010-03-31/"><PublishResult><MessageId>1</MessageId></PublishResult><ResponseMetadata><RequestId>r</RequestId></ResponseMetadata></PublishResponse>`)
+	defer srv.Close()
+
+	credentials := AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret", SessionToken: "token"}
+	n, err := NewSNSNotifier("arn:aws:sns:eu-west-1:123456789012:alerts.fifo", srv.URL, credentials, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	alert := testAlert()
+	if err := n.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	form, header := (*requests)[0].form(t), (*requests)[0].header
+	if form.Get("Action") != "Publish" || form.Get("TopicArn") != "arn:aws:sns:eu-west-1:123456789012:alerts.fifo" || form.Get("Subject") != alert.Title() {
+		t.Errorf("unexpected form %v", form)
+	}
+	var published Alert
+	if err := json.Unmarshal([]byte(form.Get("Message")), &published); err != nil || published.Key() != alert.Key() || published.Labels["txHash"] != "0x01" {
+		t.Errorf("expected the alert as message but got %s (%v)", form.Get("Message"), err)
+	}
+	attributes := make(map[string]string)
+	for i := 1; len(form.Get(awsEntry(i, "Name"))) > 0; i++ {
+		if form.Get(awsEntry(i, "Value.DataType")) != "String" {
+			t.Errorf("expected the string attribute %s but got %v", form.Get(awsEntry(i, "Name")), form)
+		}
+		attributes[form.Get(awsEntry(i, "Name"))] = form.Get(awsEntry(i, "Value.StringValue"))
+	}
+	if len(attributes) != 4 || attributes["priority"] != "P1" || attributes["rule"] != alert.Rule || attributes["resolved"] != "false" {
+		t.Errorf("unexpected attributes %v", attributes)
+	}
+	if form.Get("MessageGroupId") != alert.ID() || len(form.Get("MessageDeduplicationId")) == 0 {
+		t.Errorf("expected the group and the deduplication of the FIFO topic but got %v", form)
+	}
+	if !strings.HasPrefix(header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(header.Get("Authorization"), "/eu-west-1/sns/aws4_request") || header.Get("X-Amz-Security-Token") != "token" {
+		t.Errorf("unexpected signature headers %v", header)
+	}
+
+	for _, arn := range []string{"arn:aws:sqs:eu-west-1:123456789012:alerts", "alerts", "arn:aws:sns::123456789012:alerts"} {
+		if _, err := NewSNSNotifier(arn, "", credentials, time.Second); err == nil {
+			t.Errorf("expected the topic ARN %s to be invalid", arn)
+		}
+	}
+}
+
+// awsEntry is the name of a field of the i-th message attribute in the form of SNS.
+func awsEntry(i int, field string) string {
+	return fmt.Sprintf("MessageAttributes.entry.%d.%s", i, field)
+}
+
+func TestSQSNotifier(t *testing.T) {
+	srv, requests := awsServer(t, http.StatusBadRequest, `{"__type":"com.amazonaws.sqs#QueueDoesNotExist","message":"The specified queue does not exist."}`)
+	defer srv.Close()
+
+	n, err := NewSQSNotifier(srv.URL+"/123456789012/alerts", "us-east-1", AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = n.Notify(context.Background(), testAlert())
+	if err == nil || !strings.Contains(err.Error(), "QueueDoesNotExist") {
+		t.Errorf("expected the error of SQS but got %v", err)
+	}
+
+	var input struct {
+		QueueUrl          string
+		MessageBody       string
+		MessageGroupId    string
+		MessageAttributes map[string]struct{ DataType, StringValue string }
+	}
+	request := (*requests)[0]
+	if err := json.Unmarshal(request.body, &input); err != nil {
+		t.Fatalf("failed to decode the request: %v", err)
+	}
+	if request.header.Get("X-Amz-Target") != "AmazonSQS.SendMessage" || input.QueueUrl != srv.URL+"/123456789012/alerts" || len(input.MessageBody) == 0 || len(input.MessageGroupId) > 0 {
+		t.Errorf("unexpected request %s", request.body)
+	}
+	if rule := input.MessageAttributes["rule"]; rule.DataType != "String" || rule.StringValue != testAlert().Rule {
+		t.Errorf("expected the rule attribute but got %v", input.MessageAttributes)
+	}
+	if !strings.Contains(request.header.Get("Authorization"), "/us-east-1/sqs/aws4_request") {
+		t.Errorf("unexpected signature headers %v", request.header)
+	}
+
+	if n, err = NewSQSNotifier("https://sqs.ap-southeast-2.amazonaws.com/123456789012/alerts", "", AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}, time.Second); err != nil || n.client.Options().Region != "ap-southeast-2" {
+		t.Errorf("expected the region of the queue URL but got %v", err)
+	}
+	if _, err := NewSQSNotifier("http://localhost:4566/123456789012/alerts", "", AWSCredentials{}, time.Second); err == nil {
+		t.Errorf("expected the region of a custom queue URL to be required")
+	}
+}
@@ -0,0 +1,70 @@
+package alerts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Filter selects the alerts by monitor and priority, an empty set matching every value.
+type Filter struct {
+	Monitors   map[string]bool
+	Priorities map[string]bool
+}
+
+func (f Filter) Matches(alert Alert) bool {
+	if len(f.Monitors) > 0 && !f.Monitors[alert.Monitor] {
+		return false
+	}
+	if len(f.Priorities) > 0 && !f.Priorities[alert.Priority] {
+		return false
+	}
+	return true
+}
+
+// Channel is a destination of a chat notifier, e.g. a Discord webhook or a Telegram chat, receiving the alerts matching its filter.
+type Channel struct {
+	Filter      Filter
+	Destination string
+}
+
+// ParseChannels parses the channels formatted via `monitors[:priorities]=destination`, the monitors and the priorities
+// being comma-separated lists or `*` for any, e.g. `fault,global_events:P0,P1=https://...` or `*=-1001234`.
+func ParseChannels(values []string) ([]Channel, error) {
+	channels := make([]Channel, 0, len(values))
+	for _, value := range values {
+		split := strings.SplitN(value, "=", 2)
+		if len(split) != 2 || len(split[0]) == 0 || len(split[1]) == 0 {
+			return nil, fmt.Errorf("failed to parse `monitors[:priorities]=destination`: %s", value)
+		}
+
+		selectors := strings.SplitN(split[0], ":", 2)
+		monitors, err := parseSelector(selectors[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the monitors of %s: %w", value, err)
+		}
+		priorities := map[string]bool{}
+		if len(selectors) == 2 {
+			if priorities, err = parseSelector(selectors[1]); err != nil {
+				return nil, fmt.Errorf("failed to parse the priorities of %s: %w", value, err)
+			}
+		}
+		channels = append(channels, Channel{Filter: Filter{Monitors: monitors, Priorities: priorities}, Destination: split[1]})
+	}
+	return channels, nil
+}
+
+// parseSelector parses a comma-separated list, `*` selecting any value.
+func parseSelector(selector string) (map[string]bool, error) {
+	values := map[string]bool{}
+	if selector == "*" {
+		return values, nil
+	}
+	for _, value := range strings.Split(selector, ",") {
+		value = strings.TrimSpace(value)
+		if len(value) == 0 || value == "*" {
+			return nil, fmt.Errorf("invalid selector %q", selector)
+		}
+		values[value] = true
+	}
+	return values, nil
+}
@@ -0,0 +1,69 @@
+package alerts
+
+import (
+	"testing"
+)
+
+func TestParseChannels(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		ok          bool
+		destination string
+		monitors    int
+		priorities  int
+	}{
+		{"any", "*=https://discord.com/api/webhooks/1/abc", true, "https://discord.com/api/webhooks/1/abc", 0, 0},
+		{"monitors", "fault,global_events=-1001234", true, "-1001234", 2, 0},
+		{"monitors and priorities", "fault:P0,P1=-1001234", true, "-1001234", 1, 2},
+		{"any monitor with priorities", "*:P0=-1001234", true, "-1001234", 0, 1},
+		{"destination with separators", "*=https://example.com/hook?a=b", true, "https://example.com/hook?a=b", 0, 0},
+		{"missing destination", "fault=", false, "", 0, 0},
+		{"missing selector", "=-1001234", false, "", 0, 0},
+		{"missing separator", "https://discord.com/api/webhooks/1/abc", false, "", 0, 0},
+		{"empty monitor", "fault,=-1001234", false, "", 0, 0},
+		{"mixed wildcard", "fault,*=-1001234", false, "", 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			channels, err := ParseChannels([]string{test.value})
+			if (err == nil) != test.ok {
+				t.Fatalf("Failed %s: expected ok=%v but got %v", test.name, test.ok, err)
+			}
+			if err != nil {
+				return
+			}
+			channel := channels[0]
+			if channel.Destination != test.destination || len(channel.Filter.Monitors) != test.monitors || len(channel.Filter.Priorities) != test.priorities {
+				t.Errorf("Failed %s: expected %s with %d monitors and %d priorities but got %v", test.name, test.destination, test.monitors, test.priorities, channel)
+			}
+		})
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	filter := Filter{Monitors: map[string]bool{"fault": true}, Priorities: map[string]bool{"P0": true, "P1": true}}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		monitor  string
+		priority string
+		expected bool
+	}{
+		{"matching", filter, "fault", "P0", true},
+		{"other monitor", filter, "global_events", "P0", false},
+		{"other priority", filter, "fault", "P2", false},
+		{"any", Filter{}, "global_events", "P5", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alert := Alert{Monitor: test.monitor, Priority: test.priority}
+			if matches := test.filter.Matches(alert); matches != test.expected {
+				t.Errorf("Failed %s: expected %v but got %v", test.name, test.expected, matches)
+			}
+		})
+	}
+}
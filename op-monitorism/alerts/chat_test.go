@@ -0,0 +1,76 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTelegramNotifier(t *testing.T) {
+	var chats []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/botsecret/sendMessage" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var message telegramMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("failed to decode the message: %v", err)
+		}
+		if !strings.HasPrefix(message.Text, "[P1] global_events: Safe owner changed\n") {
+			t.Errorf("unexpected text %q", message.Text)
+		}
+		chats = append(chats, message.ChatID)
+	}))
+	defer srv.Close()
+
+	channels, err := ParseChannels([]string{"*:P0,P1=-100", "fault=-200", "global_events=-300"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n := NewTelegramNotifier(srv.URL+"/", "secret", channels, time.Second)
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chats) != 2 || chats[0] != "-100" || chats[1] != "-300" {
+		t.Errorf("expected the alert to be routed to the matching chats but got %v", chats)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	expected := "[P1] global_events: Safe owner changed\nAddedOwner(address) emitted\n\nentity: 0x9BA6e03D8B90dE867373Db8cF1A58d2F7F006b3A\nnickname: mainnet\ntxHash: 0x01"
+	if text := plainText(testAlert()); text != expected {
+		t.Errorf("expected %q but got %q", expected, text)
+	}
+}
+
+func TestDiscordNotifier(t *testing.T) {
+	var received []discordMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message discordMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("failed to decode the message: %v", err)
+		}
+		received = append(received, message)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	channels, err := ParseChannels([]string{"global_events:P1=" + srv.URL, "fault=" + srv.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n := NewDiscordNotifier(channels, time.Second)
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("expected the alert to be routed to the matching channel but got %v", received)
+	}
+	if embed := received[0].Embeds[0]; embed.Color != 0xe01e5a || embed.Timestamp != "2023-11-14T22:13:20Z" || len(embed.Fields) != 3 {
+		t.Errorf("unexpected embed %v", embed)
+	}
+}
@@ -0,0 +1,93 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a cron expression of 5 fields: minute, hour, day of month, month and day of week (0 being
+// Sunday), each field being `*`, a value, a range `a-b` or a comma-separated list of them, optionally stepped by `/n`.
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays uint64
+	// anyDay and anyWeekday are set when the field is `*`, the days of a schedule restricting both the day of
+	// month and the day of week matching either of them as for cron.
+	anyDay, anyWeekday bool
+}
+
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in the cron expression %q", expr)
+	}
+
+	var schedule cronSchedule
+	var err error
+	if schedule.minutes, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("invalid minutes: %w", err)
+	}
+	if schedule.hours, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("invalid hours: %w", err)
+	}
+	if schedule.days, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("invalid days of month: %w", err)
+	}
+	if schedule.months, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("invalid months: %w", err)
+	}
+	if schedule.weekdays, err = parseCronField(fields[4], 0, 6); err != nil {
+		return nil, fmt.Errorf("invalid days of week: %w", err)
+	}
+	schedule.anyDay, schedule.anyWeekday = fields[2] == "*", fields[4] == "*"
+	return &schedule, nil
+}
+
+// parseCronField returns the bitset of the values of the field within [min, max].
+func parseCronField(field string, min, max int) (uint64, error) {
+	var values uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if split := strings.SplitN(part, "/", 2); len(split) == 2 {
+			var err error
+			if step, err = strconv.Atoi(split[1]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			part = split[0]
+		}
+
+		low, high := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if low, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value in %q", part)
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid range in %q", part)
+				}
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%q out of [%d, %d]", part, min, max)
+		}
+		for value := low; value <= high; value += step {
+			values |= 1 << uint(value)
+		}
+	}
+	return values, nil
+}
+
+// Matches reports whether the schedule fires at the minute of the time, in its location.
+func (s *cronSchedule) Matches(t time.Time) bool {
+	if s.minutes&(1<<uint(t.Minute())) == 0 || s.hours&(1<<uint(t.Hour())) == 0 || s.months&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	day, weekday := s.days&(1<<uint(t.Day())) != 0, s.weekdays&(1<<uint(t.Weekday())) != 0
+	if !s.anyDay && !s.anyWeekday {
+		return day || weekday
+	}
+	return day && weekday
+}
@@ -0,0 +1,99 @@
+package alerts
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cooldown is the minimum duration between two notifications of a detection matching the filter.
+type Cooldown struct {
+	Filter   Filter
+	Duration time.Duration
+}
+
+// ParseCooldowns parses the cooldowns formatted via `monitors[:priorities]=duration`, as the channels.
+func ParseCooldowns(values []string) ([]Cooldown, error) {
+	channels, err := ParseChannels(values)
+	if err != nil {
+		return nil, err
+	}
+	cooldowns := make([]Cooldown, len(channels))
+	for i, channel := range channels {
+		duration, err := time.ParseDuration(channel.Destination)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the duration of %s: %w", values[i], err)
+		}
+		if duration < 0 {
+			return nil, fmt.Errorf("negative cooldown %s", values[i])
+		}
+		cooldowns[i] = Cooldown{Filter: channel.Filter, Duration: duration}
+	}
+	return cooldowns, nil
+}
+
+// Deduplicator suppresses the alerts of a detection, identified by the key of the alert (monitor, rule, entity),
+// notified less than a cooldown ago. A resolved alert is never suppressed and resets the cooldown of the detection.
+type Deduplicator struct {
+	mu sync.Mutex
+
+	defaultCooldown time.Duration
+	cooldowns       []Cooldown
+
+	// notified is the time of the last notification of the detections.
+	notified map[string]time.Time
+}
+
+func NewDeduplicator(defaultCooldown time.Duration, cooldowns []Cooldown) *Deduplicator {
+	return &Deduplicator{defaultCooldown: defaultCooldown, cooldowns: cooldowns, notified: map[string]time.Time{}}
+}
+
+// cooldown returns the duration of the first cooldown matching the alert, the default cooldown otherwise.
+func (d *Deduplicator) cooldown(alert Alert) time.Duration {
+	for _, cooldown := range d.cooldowns {
+		if cooldown.Filter.Matches(alert) {
+			return cooldown.Duration
+		}
+	}
+	return d.defaultCooldown
+}
+
+// Suppress reports whether the alert must be suppressed, the detection being notified within its cooldown.
+func (d *Deduplicator) Suppress(alert Alert, now time.Time) bool {
+	if alert.Resolved {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	last, ok := d.notified[alert.Key()]
+	return ok && now.Sub(last) < d.cooldown(alert)
+}
+
+// Notified records the notification of the alert, starting the cooldown of a firing detection.
+func (d *Deduplicator) Notified(alert Alert, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if alert.Resolved {
+		delete(d.notified, alert.Key())
+		return
+	}
+	d.notified[alert.Key()] = now
+
+	// forget the detections out of their cooldown, bounding the memory to the detections notified recently.
+	longest := d.maxCooldown()
+	for key, last := range d.notified {
+		if now.Sub(last) >= longest {
+			delete(d.notified, key)
+		}
+	}
+}
+
+func (d *Deduplicator) maxCooldown() time.Duration {
+	longest := d.defaultCooldown
+	for _, cooldown := range d.cooldowns {
+		longest = max(longest, cooldown.Duration)
+	}
+	return longest
+}
@@ -0,0 +1,104 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestParseCooldowns(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		ok     bool
+	}{
+		{"valid", []string{"fault:P0=5m", "*=0s"}, true},
+		{"invalid duration", []string{"fault=5"}, false},
+		{"negative duration", []string{"fault=-5m"}, false},
+		{"missing duration", []string{"fault"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseCooldowns(test.values); (err == nil) != test.ok {
+				t.Errorf("Failed %s: expected ok=%v but got %v", test.name, test.ok, err)
+			}
+		})
+	}
+}
+
+func TestDeduplicator(t *testing.T) {
+	cooldowns, err := ParseCooldowns([]string{"fault=10m", "*:P5=0s"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := NewDeduplicator(time.Hour, cooldowns)
+	start := time.Unix(1700000000, 0)
+
+	fault := Alert{Monitor: "fault", Rule: "output root mismatch", Priority: "P0", Entity: "0x01"}
+	event := testAlert()
+	info := testAlert()
+	info.Priority = "P5"
+
+	steps := []struct {
+		name     string
+		alert    Alert
+		elapsed  time.Duration
+		suppress bool
+	}{
+		{"first notification", event, 0, false},
+		{"within the default cooldown", event, 30 * time.Minute, true},
+		{"other entity", func() Alert { a := testAlert(); a.Entity = "0x02"; return a }(), 30 * time.Minute, false},
+		{"after the default cooldown", event, time.Hour, false},
+		{"first fault", fault, time.Hour, false},
+		{"within the fault cooldown", fault, time.Hour + 5*time.Minute, true},
+		{"after the fault cooldown", fault, time.Hour + 10*time.Minute, false},
+		{"resolved never suppressed", func() Alert { a := fault; a.Resolved = true; return a }(), time.Hour + 11*time.Minute, false},
+		{"firing again once resolved", fault, time.Hour + 12*time.Minute, false},
+		{"no cooldown", info, 2 * time.Hour, false},
+		{"no cooldown again", info, 2 * time.Hour, false},
+	}
+
+	for _, step := range steps {
+		now := start.Add(step.elapsed)
+		if suppress := d.Suppress(step.alert, now); suppress != step.suppress {
+			t.Fatalf("Failed %s: expected suppress=%v but got %v", step.name, step.suppress, suppress)
+		}
+		if !step.suppress {
+			d.Notified(step.alert, now)
+		}
+	}
+
+	// the detections out of their cooldown are forgotten.
+	if len(d.notified) != 2 {
+		t.Errorf("expected the fault and the info detections notified within the longest cooldown to be remembered but got %v", d.notified)
+	}
+}
+
+func TestDispatcherDedup(t *testing.T) {
+	notifier := &recordingNotifier{name: "recording"}
+	d := NewDispatcher(log.New(), metrics.With(prometheus.NewRegistry()), []Notifier{notifier}, DispatcherConfig{Dedup: NewDeduplicator(time.Hour, nil)})
+	now := time.Unix(1700000000, 0)
+	d.now = func() time.Time { return now }
+
+	// a failed delivery doesn't start the cooldown.
+	notifier.err = errors.New("unavailable")
+	if err := d.Notify(context.Background(), testAlert()); err == nil {
+		t.Fatalf("expected the delivery to fail")
+	}
+	notifier.err = nil
+	for i := 0; i < 3; i++ {
+		if err := d.Notify(context.Background(), testAlert()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		now = now.Add(time.Minute)
+	}
+	if len(notifier.alerts) != 2 {
+		t.Errorf("expected the failed and the retried alerts to be delivered only but got %d", len(notifier.alerts))
+	}
+}
@@ -0,0 +1,76 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Color       uint64         `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+type discordMessage struct {
+	Content string         `json:"content"`
+	Embeds  []discordEmbed `json:"embeds"`
+}
+
+// DiscordNotifier posts the alerts to the Discord webhooks of the channels matching them.
+type DiscordNotifier struct {
+	channels   []Channel
+	httpClient *http.Client
+}
+
+func NewDiscordNotifier(channels []Channel, timeout time.Duration) *DiscordNotifier {
+	return &DiscordNotifier{channels: channels, httpClient: &http.Client{Timeout: timeout}}
+}
+
+func (n *DiscordNotifier) Name() string {
+	return "discord"
+}
+
+func (n *DiscordNotifier) Notify(ctx context.Context, alert Alert) error {
+	var errs []error
+	for i, channel := range n.channels {
+		if !channel.Filter.Matches(alert) {
+			continue
+		}
+		if err := postJSON(ctx, n.httpClient, channel.Destination, nil, discordPayload(alert)); err != nil {
+			errs = append(errs, fmt.Errorf("channel %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func discordPayload(alert Alert) discordMessage {
+	// the colors are valid hex literals, the parse can't fail.
+	color, _ := strconv.ParseUint(strings.TrimPrefix(alertColor(alert), "#"), 16, 32)
+
+	fields := []discordField{}
+	if len(alert.Entity) > 0 {
+		fields = append(fields, discordField{Name: "entity", Value: alert.Entity})
+	}
+	for _, name := range alert.SortedLabels() {
+		fields = append(fields, discordField{Name: name, Value: alert.Labels[name], Inline: true})
+	}
+
+	embed := discordEmbed{Title: alert.Title(), Description: alert.Summary, Color: color, Fields: fields}
+	if !alert.Time.IsZero() {
+		embed.Timestamp = alert.Time.UTC().Format(time.RFC3339)
+	}
+	return discordMessage{Content: alert.Title(), Embeds: []discordEmbed{embed}}
+}
@@ -0,0 +1,230 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MetricsNamespace = "alerts"
+)
+
+// DispatcherConfig is the processing of the alerts by the Dispatcher before their delivery.
+type DispatcherConfig struct {
+	// Dedup suppresses the alerts notified within their cooldown, every alert being notified when nil.
+	Dedup *Deduplicator
+	// Silences hold the alerts during their windows, every alert being delivered when nil.
+	Silences *Silences
+	// Routing selects the notifiers of the alerts, every alert being sent to every notifier when nil.
+	Routing *Routing
+	// Templates render the message of the alerts by notifier and monitor.
+	Templates []MessageTemplate
+	// Audit records the alerts and their deliveries, nothing being recorded when nil.
+	Audit *AuditLog
+}
+
+// Dispatcher fans the alerts of a monitor out to the notifiers of their route, once deduplicated.
+// A failing notifier doesn't prevent the delivery to the others.
+type Dispatcher struct {
+	log       log.Logger
+	notifiers []Notifier
+	dedup     *Deduplicator
+	silences  *Silences
+	routing   *Routing
+	templates []MessageTemplate
+	audit     *AuditLog
+	now       func() time.Time
+
+	// mu guards the unresolved alerts re-notified or escalated, keyed by the key of the alert.
+	mu     sync.Mutex
+	active map[string]*activeAlert
+	// onResolve are called with the alerts resolved via the API.
+	onResolve []func(alert Alert)
+
+	// metrics
+	alerts        *prometheus.CounterVec
+	suppressed    *prometheus.CounterVec
+	silenced      *prometheus.CounterVec
+	unrouted      *prometheus.CounterVec
+	escalations   *prometheus.CounterVec
+	notifications *prometheus.CounterVec
+}
+
+func NewDispatcher(log log.Logger, m metrics.Factory, notifiers []Notifier, cfg DispatcherConfig) *Dispatcher {
+	dedup := cfg.Dedup
+	if dedup == nil {
+		dedup = NewDeduplicator(0, nil)
+	}
+	return &Dispatcher{
+		log:       log,
+		notifiers: notifiers,
+		dedup:     dedup,
+		silences:  cfg.Silences,
+		routing:   cfg.Routing,
+		templates: cfg.Templates,
+		audit:     cfg.Audit,
+		now:       time.Now,
+		active:    map[string]*activeAlert{},
+
+		alerts: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "alerts",
+			Help:      "number of alerts emitted by the monitor",
+		}, []string{"monitor", "rule", "priority", "resolved"}),
+		suppressed: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "suppressed",
+			Help:      "number of alerts suppressed within the cooldown of their detection",
+		}, []string{"monitor", "rule", "priority"}),
+		silenced: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "silenced",
+			Help:      "number of alerts held during a silence window",
+		}, []string{"monitor", "rule", "priority", "silence"}),
+		unrouted: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unrouted",
+			Help:      "number of alerts matching no route",
+		}, []string{"monitor", "rule", "priority"}),
+		escalations: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "escalations",
+			Help:      "number of unresolved alerts re-notified or escalated",
+		}, []string{"monitor", "rule", "action"}),
+		notifications: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "notifications",
+			Help:      "number of alerts delivered by notifier and result",
+		}, []string{"notifier", "result"}),
+	}
+}
+
+func (d *Dispatcher) Name() string {
+	return "dispatcher"
+}
+
+// Notify delivers the alert to every notifier, the returned error joining the failures. The cooldown of the
+// detection only starts once delivered to every notifier, a failed alert not being suppressed when retried. An alert
+// held during a silence window returns ErrSilenced, to be sent again while its condition holds.
+func (d *Dispatcher) Notify(ctx context.Context, alert Alert) error {
+	now := d.now()
+	if alert.Time.IsZero() {
+		alert.Time = now
+	}
+	d.alerts.WithLabelValues(alert.Monitor, alert.Rule, alert.Priority, fmt.Sprint(alert.Resolved)).Inc()
+	if d.silences != nil {
+		// the silenced alerts don't start the cooldown of their detection, the senders retrying them so that a
+		// condition still holding is notified once out of the window.
+		if silence, ok := d.silences.Silence(alert, now); ok {
+			d.log.Info("alert silenced", "key", alert.Key(), "silence", silence.Name)
+			d.silenced.WithLabelValues(alert.Monitor, alert.Rule, alert.Priority, silence.Name).Inc()
+			d.record(AuditRecord{Time: now, Action: AuditSilenced, Alert: alert, Silence: silence.Name})
+			return fmt.Errorf("%w by %s", ErrSilenced, silence.Name)
+		}
+	}
+	if d.dedup.Suppress(alert, now) {
+		d.log.Debug("alert suppressed", "key", alert.Key())
+		d.suppressed.WithLabelValues(alert.Monitor, alert.Rule, alert.Priority).Inc()
+		d.record(AuditRecord{Time: now, Action: AuditSuppressed, Alert: alert})
+		return nil
+	}
+
+	notifiers := d.notifiers
+	var route Route
+	if d.routing != nil {
+		var ok bool
+		route, ok = d.routing.Route(alert)
+		if !ok {
+			d.log.Warn("alert matching no route", "key", alert.Key(), "priority", alert.Priority)
+			d.unrouted.WithLabelValues(alert.Monitor, alert.Rule, alert.Priority).Inc()
+			d.record(AuditRecord{Time: now, Action: AuditUnrouted, Alert: alert})
+			return nil
+		}
+		if len(route.Priority) > 0 {
+			alert.Priority = route.Priority
+		}
+		notifiers = d.selectNotifiers(route.Notifiers)
+	}
+	if alert.Resolved {
+		for _, escalated := range d.untrack(alert) {
+			if !containsNotifier(notifiers, escalated) {
+				notifiers = append(notifiers, escalated)
+			}
+		}
+	} else {
+		// tracked whatever the result of the delivery, the re-notifications covering a failed notifier.
+		d.track(alert, route, now)
+	}
+
+	deliveries, err := d.deliver(ctx, alert, notifiers)
+	d.record(AuditRecord{Time: now, Action: AuditNotified, Alert: alert, Deliveries: deliveries})
+	if err != nil {
+		return err
+	}
+	d.dedup.Notified(alert, now)
+	return nil
+}
+
+// deliver delivers the alert to the notifiers, the returned error joining the failures.
+func (d *Dispatcher) deliver(ctx context.Context, alert Alert, notifiers []Notifier) ([]AuditDelivery, error) {
+	var errs []error
+	deliveries := make([]AuditDelivery, 0, len(notifiers))
+	for _, notifier := range notifiers {
+		message, err := renderMessage(d.templates, notifier.Name(), alert)
+		if err != nil {
+			// the alert is delivered with its own summary rather than lost to a broken template.
+			d.log.Error("failed to render the alert message", "notifier", notifier.Name(), "key", alert.Key(), "err", err)
+		}
+		if err := notifier.Notify(ctx, message); err != nil {
+			d.log.Error("failed to deliver the alert", "notifier", notifier.Name(), "key", alert.Key(), "err", err)
+			d.notifications.WithLabelValues(notifier.Name(), "failed").Inc()
+			errs = append(errs, fmt.Errorf("%s: %w", notifier.Name(), err))
+			deliveries = append(deliveries, AuditDelivery{Notifier: notifier.Name(), Result: "failed", Error: err.Error()})
+			continue
+		}
+		d.log.Info("alert delivered", "notifier", notifier.Name(), "key", alert.Key(), "resolved", alert.Resolved)
+		d.notifications.WithLabelValues(notifier.Name(), "delivered").Inc()
+		deliveries = append(deliveries, AuditDelivery{Notifier: notifier.Name(), Result: "delivered"})
+	}
+	return deliveries, errors.Join(errs...)
+}
+
+// selectNotifiers returns the notifiers of the names.
+func (d *Dispatcher) selectNotifiers(names []string) []Notifier {
+	notifiers := make([]Notifier, 0, len(names))
+	for _, notifier := range d.notifiers {
+		for _, name := range names {
+			if notifier.Name() == name {
+				notifiers = append(notifiers, notifier)
+				break
+			}
+		}
+	}
+	return notifiers
+}
+
+func containsNotifier(notifiers []Notifier, notifier Notifier) bool {
+	for _, n := range notifiers {
+		if n.Name() == notifier.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+// record appends the record to the audit log, a failure not preventing the delivery of the alert.
+func (d *Dispatcher) record(record AuditRecord) {
+	if d.audit == nil {
+		return
+	}
+	if err := d.audit.Record(record); err != nil {
+		d.log.Error("failed to record the alert in the audit log", "key", record.Alert.Key(), "err", err)
+	}
+}
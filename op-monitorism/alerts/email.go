@@ -0,0 +1,200 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	// EmailStartTLS, EmailTLS and EmailNoTLS are the ways of securing the connection to the SMTP server.
+	EmailStartTLS = "starttls"
+	EmailTLS      = "tls"
+	EmailNoTLS    = "none"
+
+	EmailDefaultSubject = "{{ .Title }}"
+)
+
+// EmailConfig configures the SMTP server and the messages of the email notifier.
+type EmailConfig struct {
+	// Addr is the `host:port` of the SMTP server.
+	Addr     string
+	TLSMode  string
+	Username string
+	Password string
+
+	From string
+	To   []string
+
+	// Subject and Body are templates over the Alert, the body defaulting to the plain text of the alert when nil.
+	Subject *template.Template
+	Body    *template.Template
+
+	// Priorities are the priorities of the alerts sent by email, every priority when empty.
+	Priorities []string
+}
+
+// EmailNotifier sends the alerts by email, e.g. the low-urgency detections not worth paging.
+type EmailNotifier struct {
+	cfg        EmailConfig
+	host       string
+	priorities map[string]bool
+
+	// sender and recipients are the bare addresses of the SMTP envelope.
+	sender     string
+	recipients []string
+
+	timeout time.Duration
+}
+
+func NewEmailNotifier(cfg EmailConfig, timeout time.Duration) (*EmailNotifier, error) {
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP server address %s: %w", cfg.Addr, err)
+	}
+	switch cfg.TLSMode {
+	case EmailStartTLS, EmailTLS, EmailNoTLS:
+	default:
+		return nil, fmt.Errorf("unknown TLS mode %s", cfg.TLSMode)
+	}
+	if len(cfg.To) == 0 {
+		return nil, fmt.Errorf("no recipient")
+	}
+	sender, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %s: %w", cfg.From, err)
+	}
+	recipients := make([]string, len(cfg.To))
+	for i, to := range cfg.To {
+		recipient, err := mail.ParseAddress(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %s: %w", to, err)
+		}
+		recipients[i] = recipient.Address
+	}
+	if cfg.Subject == nil {
+		if cfg.Subject, err = parseTemplate("subject", EmailDefaultSubject); err != nil {
+			return nil, err
+		}
+	}
+
+	priorities := make(map[string]bool, len(cfg.Priorities))
+	for _, priority := range cfg.Priorities {
+		priorities[priority] = true
+	}
+	return &EmailNotifier{cfg: cfg, host: host, priorities: priorities, sender: sender.Address, recipients: recipients, timeout: timeout}, nil
+}
+
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, alert Alert) error {
+	if len(n.priorities) > 0 && !n.priorities[alert.Priority] {
+		return nil
+	}
+	message, err := n.message(alert)
+	if err != nil {
+		return err
+	}
+	return n.send(ctx, message)
+}
+
+// message renders the RFC 5322 message of the alert.
+func (n *EmailNotifier) message(alert Alert) ([]byte, error) {
+	var subject bytes.Buffer
+	if err := n.cfg.Subject.Execute(&subject, alert); err != nil {
+		return nil, fmt.Errorf("failed to render the subject: %w", err)
+	}
+	body := plainText(alert)
+	if n.cfg.Body != nil {
+		var buf bytes.Buffer
+		if err := n.cfg.Body.Execute(&buf, alert); err != nil {
+			return nil, fmt.Errorf("failed to render the body: %w", err)
+		}
+		body = buf.String()
+	}
+
+	date := alert.Time
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.cfg.To, ", "))
+	// the subject is a single header line, the line breaks of the template are dropped.
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(subject.String()), " ")))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return msg.Bytes(), nil
+}
+
+// send delivers the message to every recipient through the SMTP server, the whole exchange being bounded by the timeout.
+func (n *EmailNotifier) send(ctx context.Context, message []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to dial the SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if n.cfg.TLSMode == EmailTLS {
+		conn = tls.Client(conn, &tls.Config{ServerName: n.host})
+	}
+
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to greet the SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if n.cfg.TLSMode == EmailStartTLS {
+		if err := client.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if len(n.cfg.Username) > 0 {
+		if err := client.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.host)); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(n.sender); err != nil {
+		return fmt.Errorf("failed to set the sender: %w", err)
+	}
+	for _, to := range n.recipients {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("failed to add the recipient %s: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start the data: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("failed to write the message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send the message: %w", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,161 @@
+package alerts
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpServer is a minimal SMTP server recording the envelope and the data of a single message.
+type smtpServer struct {
+	listener net.Listener
+
+	from string
+	to   []string
+	data string
+	done chan struct{}
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := &smtpServer{listener: listener, done: make(chan struct{})}
+	go srv.serve()
+	return srv
+}
+
+func (s *smtpServer) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			s.from = line
+			reply("250 OK")
+		case "RCPT":
+			s.to = append(s.to, line)
+			reply("250 OK")
+		case "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 unsupported")
+		}
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	srv := newSMTPServer(t)
+	defer srv.listener.Close()
+
+	subject, err := parseTemplate("subject", "[{{ .Priority }}] {{ .Rule }}\n on {{ .Labels.nickname }}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n, err := NewEmailNotifier(EmailConfig{
+		Addr:       srv.listener.Addr().String(),
+		TLSMode:    EmailNoTLS,
+		From:       "Monitorism <alerts@example.com>",
+		To:         []string{"security@example.com", "Oncall <oncall@example.com>"},
+		Subject:    subject,
+		Priorities: []string{"P1"},
+	}, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-srv.done
+
+	if srv.from != "MAIL FROM:<alerts@example.com>" {
+		t.Errorf("unexpected sender %s", srv.from)
+	}
+	if len(srv.to) != 2 || srv.to[0] != "RCPT TO:<security@example.com>" || srv.to[1] != "RCPT TO:<oncall@example.com>" {
+		t.Errorf("unexpected recipients %v", srv.to)
+	}
+	for _, expected := range []string{
+		"From: Monitorism <alerts@example.com>\r\n",
+		"To: security@example.com, Oncall <oncall@example.com>\r\n",
+		"Subject: [P1] Safe owner changed on mainnet\r\n",
+		"\r\n\r\n[P1] global_events: Safe owner changed\r\nAddedOwner(address) emitted\r\n",
+		"txHash: 0x01\r\n",
+	} {
+		if !strings.Contains(srv.data, expected) {
+			t.Errorf("expected %q in the message but got %q", expected, srv.data)
+		}
+	}
+}
+
+func TestEmailNotifierPriorities(t *testing.T) {
+	// no server listening, the alert must be skipped without dialing.
+	n, err := NewEmailNotifier(EmailConfig{Addr: "127.0.0.1:1", TLSMode: EmailNoTLS, From: "alerts@example.com", To: []string{"security@example.com"}, Priorities: []string{"P4", "P5"}}, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Errorf("expected the P1 alert to be skipped but got %v", err)
+	}
+}
+
+func TestNewEmailNotifier(t *testing.T) {
+	valid := EmailConfig{Addr: "smtp.example.com:587", TLSMode: EmailStartTLS, From: "alerts@example.com", To: []string{"security@example.com"}}
+	tests := []struct {
+		name   string
+		modify func(cfg *EmailConfig)
+		ok     bool
+	}{
+		{"valid", func(cfg *EmailConfig) {}, true},
+		{"missing port", func(cfg *EmailConfig) { cfg.Addr = "smtp.example.com" }, false},
+		{"unknown tls mode", func(cfg *EmailConfig) { cfg.TLSMode = "ssl" }, false},
+		{"invalid sender", func(cfg *EmailConfig) { cfg.From = "alerts" }, false},
+		{"no recipient", func(cfg *EmailConfig) { cfg.To = nil }, false},
+		{"invalid recipient", func(cfg *EmailConfig) { cfg.To = []string{"security@example.com", "oncall"} }, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := valid
+			test.modify(&cfg)
+			if _, err := NewEmailNotifier(cfg, time.Second); (err == nil) != test.ok {
+				t.Errorf("Failed %s: expected ok=%v but got %v", test.name, test.ok, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EscalationInterval is the interval at which the unresolved alerts are checked for re-notification and escalation.
+const EscalationInterval = 30 * time.Second
+
+// Renotify re-sends an unresolved alert to the notifiers of its route after `interval`, the interval being multiplied
+// by `factor` (2 by default) after every re-notification up to `max_interval`, at most `limit` times (0 for no limit).
+type Renotify struct {
+	Interval    time.Duration `yaml:"interval"`
+	Factor      float64       `yaml:"factor"`
+	MaxInterval time.Duration `yaml:"max_interval"`
+	Limit       int           `yaml:"limit"`
+}
+
+func (r *Renotify) check() error {
+	if r.Factor == 0 {
+		r.Factor = 2
+	}
+	switch {
+	case r.Interval <= 0:
+		return errors.New("interval must be positive")
+	case r.Factor < 1:
+		return errors.New("factor must be at least 1")
+	case r.MaxInterval != 0 && r.MaxInterval < r.Interval:
+		return errors.New("max_interval must not be lower than interval")
+	case r.Limit < 0:
+		return errors.New("limit must not be negative")
+	}
+	return nil
+}
+
+// next returns the interval following the interval, bounded by the max interval.
+func (r *Renotify) next(interval time.Duration) time.Duration {
+	next := time.Duration(float64(interval) * r.Factor)
+	if r.MaxInterval > 0 {
+		next = min(next, r.MaxInterval)
+	}
+	return next
+}
+
+// Escalation sends an alert still unresolved `after` its first notification to other notifiers, e.g. paging once a
+// chat message went unnoticed, with the priority overridden by `priority` when set.
+type Escalation struct {
+	After     time.Duration `yaml:"after"`
+	Notifiers []string      `yaml:"notifiers"`
+	Priority  string        `yaml:"priority"`
+}
+
+func (e *Escalation) check(names map[string]bool) error {
+	if e.After <= 0 {
+		return errors.New("after must be positive")
+	}
+	if len(e.Notifiers) == 0 {
+		return errors.New("no notifier")
+	}
+	for _, notifier := range e.Notifiers {
+		if !names[notifier] {
+			return fmt.Errorf("unconfigured notifier %s", notifier)
+		}
+	}
+	if len(e.Priority) > 0 && !ValidPriority(e.Priority) {
+		return fmt.Errorf("unknown priority %s", e.Priority)
+	}
+	return nil
+}
+
+// activeAlert is an unresolved alert of a route re-notifying or escalating its alerts.
+type activeAlert struct {
+	alert Alert
+	route Route
+	// since is the time of the first notification of the alert.
+	since time.Time
+
+	// next is the time of the next re-notification, zero when not re-notified anymore.
+	next       time.Time
+	interval   time.Duration
+	renotified int
+
+	// escalatedTo are the notifiers of the escalation the alert was delivered to, escalated once delivered to all of
+	// them, the escalation being retried for the others only.
+	escalatedTo []string
+	escalated   bool
+	// escalating is set once the escalation is first attempted, the escalation being counted once.
+	escalating bool
+
+	// resolution is the resolution of the alert once untracked, sent to the notifiers it is escalated to meanwhile.
+	resolution *Alert
+
+	// acknowledgedBy and acknowledgedAt are set once acknowledged, ending the re-notifications and the escalation.
+	acknowledgedBy string
+	acknowledgedAt time.Time
+}
+
+// track starts re-notifying and escalating the firing alert when its route does, refreshing the alert when
+// already tracked.
+func (d *Dispatcher) track(alert Alert, route Route, now time.Time) {
+	if route.Renotify == nil && route.Escalation == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if active, ok := d.active[alert.Key()]; ok {
+		active.alert = alert
+		return
+	}
+	active := &activeAlert{alert: alert, route: route, since: now}
+	if route.Renotify != nil {
+		active.interval = route.Renotify.Interval
+		active.next = now.Add(active.interval)
+	}
+	d.active[alert.Key()] = active
+}
+
+// untrack stops re-notifying and escalating the resolved alert, returning the notifiers it was escalated to.
+func (d *Dispatcher) untrack(alert Alert) []Notifier {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	active, ok := d.active[alert.Key()]
+	if !ok {
+		return nil
+	}
+	delete(d.active, alert.Key())
+	active.resolution = &alert
+	return d.selectNotifiers(active.escalatedTo)
+}
+
+// pendingDelivery is a re-notification or an escalation due, delivered without holding the lock of the dispatcher.
+type pendingDelivery struct {
+	key       string
+	active    *activeAlert
+	action    string
+	alert     Alert
+	notifiers []Notifier
+}
+
+// Escalate re-notifies and escalates the unresolved alerts due. The alerts due are collected under the lock and
+// delivered without it, a failed escalation being retried at the next check for the notifiers it failed for only.
+func (d *Dispatcher) Escalate(ctx context.Context) {
+	now := d.now()
+	pending := d.dueDeliveries(now)
+
+	for _, p := range pending {
+		if d.resolved(p.active) {
+			continue
+		}
+		if p.action == AuditEscalated {
+			d.log.Warn("escalating the unresolved alert", "key", p.key, "since", p.active.since, "notifiers", len(p.notifiers))
+		} else {
+			d.log.Info("re-notifying the unresolved alert", "key", p.key, "since", p.active.since, "renotification", p.alert.Labels["renotification"])
+		}
+		deliveries, _ := d.deliver(ctx, p.alert, p.notifiers)
+		d.record(AuditRecord{Time: now, Action: p.action, Alert: p.alert, Deliveries: deliveries})
+		if p.action != AuditEscalated {
+			continue
+		}
+
+		var delivered []string
+		for _, delivery := range deliveries {
+			if delivery.Result == "delivered" {
+				delivered = append(delivered, delivery.Notifier)
+			}
+		}
+		if resolution := d.recordEscalation(p.active, delivered); resolution != nil && len(delivered) > 0 {
+			// resolved during the escalation, the resolution missed the notifiers the alert was just escalated to.
+			resolved := *resolution
+			deliveries, _ := d.deliver(ctx, resolved, d.selectNotifiers(delivered))
+			d.record(AuditRecord{Time: now, Action: AuditNotified, Alert: resolved, Deliveries: deliveries})
+		}
+	}
+}
+
+// dueDeliveries returns the re-notifications and the escalations due, advancing the re-notifications and counting
+// the escalations first attempted.
+func (d *Dispatcher) dueDeliveries(now time.Time) []pendingDelivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var pending []pendingDelivery
+	for key, active := range d.active {
+		if !active.acknowledgedAt.IsZero() {
+			continue
+		}
+		if escalation := active.route.Escalation; escalation != nil && !active.escalated && now.Sub(active.since) >= escalation.After {
+			alert := active.alert
+			if len(escalation.Priority) > 0 {
+				alert.Priority = escalation.Priority
+			}
+			if !active.escalating {
+				active.escalating = true
+				d.escalations.WithLabelValues(alert.Monitor, alert.Rule, AuditEscalated).Inc()
+			}
+			var notifiers []Notifier
+			for _, notifier := range d.selectNotifiers(escalation.Notifiers) {
+				if !containsName(active.escalatedTo, notifier.Name()) {
+					notifiers = append(notifiers, notifier)
+				}
+			}
+			pending = append(pending, pendingDelivery{key: key, active: active, action: AuditEscalated, alert: alert, notifiers: notifiers})
+		}
+
+		if renotify := active.route.Renotify; renotify != nil && !active.next.IsZero() && !now.Before(active.next) {
+			active.renotified++
+			alert := active.alert
+			alert.Labels = make(map[string]string, len(active.alert.Labels)+1)
+			for name, value := range active.alert.Labels {
+				alert.Labels[name] = value
+			}
+			alert.Labels["renotification"] = fmt.Sprint(active.renotified)
+			d.escalations.WithLabelValues(alert.Monitor, alert.Rule, AuditRenotified).Inc()
+			pending = append(pending, pendingDelivery{key: key, active: active, action: AuditRenotified, alert: alert, notifiers: d.selectNotifiers(active.route.Notifiers)})
+
+			active.next = time.Time{}
+			if renotify.Limit == 0 || active.renotified < renotify.Limit {
+				active.interval = renotify.next(active.interval)
+				active.next = now.Add(active.interval)
+			}
+		}
+
+		// an escalated alert is kept until resolved, its resolution being sent to the notifiers it was escalated to.
+		if active.next.IsZero() && active.route.Escalation == nil {
+			delete(d.active, key)
+		}
+	}
+	return pending
+}
+
+// resolved returns true when the alert was resolved since its delivery was due.
+func (d *Dispatcher) resolved(active *activeAlert) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return active.resolution != nil
+}
+
+// recordEscalation records the notifiers the alert was escalated to, returning the resolution of the alert when it
+// was resolved during the escalation.
+func (d *Dispatcher) recordEscalation(active *activeAlert, delivered []string) *Alert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	active.escalatedTo = append(active.escalatedTo, delivered...)
+	active.escalated = len(d.selectNotifiers(active.route.Escalation.Notifiers)) == len(d.selectNotifiers(active.escalatedTo))
+	return active.resolution
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestLoadRoutingEscalation(t *testing.T) {
+	notifiers := []Notifier{&recordingNotifier{name: "pagerduty"}, &recordingNotifier{name: "slack"}}
+	tests := []struct {
+		name    string
+		content string
+		ok      bool
+	}{
+		{"valid", "routes:\n  - notifiers: [slack]\n    renotify:\n      interval: 15m\n      max_interval: 2h\n      limit: 5\n    escalation:\n      after: 1h\n      notifiers: [pagerduty]\n      priority: P0\n", true},
+		{"missing interval", "routes:\n  - notifiers: [slack]\n    renotify:\n      limit: 5\n", false},
+		{"decreasing intervals", "routes:\n  - notifiers: [slack]\n    renotify:\n      interval: 15m\n      factor: 0.5\n", false},
+		{"max interval lower than interval", "routes:\n  - notifiers: [slack]\n    renotify:\n      interval: 15m\n      max_interval: 5m\n", false},
+		{"negative limit", "routes:\n  - notifiers: [slack]\n    renotify:\n      interval: 15m\n      limit: -1\n", false},
+		{"missing after", "routes:\n  - notifiers: [slack]\n    escalation:\n      notifiers: [pagerduty]\n", false},
+		{"no escalation notifier", "routes:\n  - notifiers: [slack]\n    escalation:\n      after: 1h\n", false},
+		{"unconfigured escalation notifier", "routes:\n  - notifiers: [slack]\n    escalation:\n      after: 1h\n      notifiers: [opsgenie]\n", false},
+		{"unknown escalation priority", "default:\n  notifiers: [slack]\n  escalation:\n    after: 1h\n    notifiers: [pagerduty]\n    priority: critical\n", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := LoadRouting(writeConfig(t, "routing.yaml", test.content), notifiers); (err == nil) != test.ok {
+				t.Errorf("Failed %s: expected ok=%v but got %v", test.name, test.ok, err)
+			}
+		})
+	}
+}
+
+func TestRenotifyIntervals(t *testing.T) {
+	renotify := Renotify{Interval: 10 * time.Minute, MaxInterval: time.Hour}
+	if err := renotify.check(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	interval := renotify.Interval
+	for _, expected := range []time.Duration{20 * time.Minute, 40 * time.Minute, time.Hour, time.Hour} {
+		if interval = renotify.next(interval); interval != expected {
+			t.Fatalf("expected the interval %s but got %s", expected, interval)
+		}
+	}
+}
+
+const testEscalationRouting = `
+routes:
+  - monitors: [global_events]
+    notifiers: [slack]
+    renotify:
+      interval: 10m
+      limit: 2
+    escalation:
+      after: 30m
+      notifiers: [pagerduty]
+      priority: P0
+`
+
+func TestDispatcherEscalation(t *testing.T) {
+	pagerduty, slack := &recordingNotifier{name: "pagerduty", err: errors.New("unavailable")}, &recordingNotifier{name: "slack"}
+	notifiers := []Notifier{pagerduty, slack}
+	routing, err := LoadRouting(writeConfig(t, "routing.yaml", testEscalationRouting), notifiers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := NewDispatcher(log.New(), metrics.With(prometheus.NewRegistry()), notifiers, DispatcherConfig{Dedup: NewDeduplicator(time.Hour, nil), Routing: routing})
+
+	start := time.Unix(1700000000, 0)
+	at := func(elapsed time.Duration) { d.now = func() time.Time { return start.Add(elapsed) } }
+	alert := testAlert()
+
+	at(0)
+	if err := d.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	steps := []struct {
+		elapsed   time.Duration
+		slack     int
+		pagerduty int
+	}{
+		{5 * time.Minute, 1, 0},
+		{10 * time.Minute, 2, 0},
+		// the next re-notification is 20 minutes after the first one.
+		{20 * time.Minute, 2, 0},
+		{30 * time.Minute, 3, 1},
+		// the failed escalation is retried, the re-notifications being over.
+		{31 * time.Minute, 3, 2},
+		{2 * time.Hour, 3, 2},
+	}
+	for _, step := range steps {
+		if step.elapsed == 31*time.Minute {
+			pagerduty.err = nil
+		}
+		at(step.elapsed)
+		d.Escalate(context.Background())
+		if len(slack.alerts) != step.slack || len(pagerduty.alerts) != step.pagerduty {
+			t.Fatalf("after %s: expected %d slack and %d pagerduty alerts but got %d and %d", step.elapsed, step.slack, step.pagerduty, len(slack.alerts), len(pagerduty.alerts))
+		}
+	}
+	if renotification := slack.alerts[2].Labels["renotification"]; renotification != "2" {
+		t.Errorf("expected the second re-notification but got %q", renotification)
+	}
+	if _, ok := alert.Labels["renotification"]; ok {
+		t.Errorf("expected the labels of the alert to be left untouched but got %v", alert.Labels)
+	}
+	if escalated := pagerduty.alerts[1]; escalated.Priority != "P0" || escalated.Key() != alert.Key() {
+		t.Errorf("expected the alert escalated as P0 but got %v", escalated)
+	}
+
+	// the resolution is sent to the notifiers the alert was escalated to, ending the escalation.
+	at(3 * time.Hour)
+	alert.Resolved = true
+	if err := d.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(slack.alerts) != 4 || len(pagerduty.alerts) != 3 || !pagerduty.alerts[2].Resolved {
+		t.Errorf("expected the resolution sent to slack and pagerduty but got %v and %v", slack.alerts, pagerduty.alerts)
+	}
+	if len(d.active) != 0 {
+		t.Errorf("expected no unresolved alert but got %v", d.active)
+	}
+}
+
+func TestDispatcherEscalationResolved(t *testing.T) {
+	pagerduty, slack := &recordingNotifier{name: "pagerduty"}, &recordingNotifier{name: "slack"}
+	notifiers := []Notifier{pagerduty, slack}
+	routing, err := LoadRouting(writeConfig(t, "routing.yaml", testEscalationRouting), notifiers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := NewDispatcher(log.New(), metrics.With(prometheus.NewRegistry()), notifiers, DispatcherConfig{Routing: routing})
+
+	alert := testAlert()
+	if err := d.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	alert.Resolved = true
+	if err := d.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// resolved before its escalation, the alert is neither re-notified nor escalated.
+	now := time.Now()
+	d.now = func() time.Time { return now.Add(time.Hour) }
+	d.Escalate(context.Background())
+	if len(slack.alerts) != 2 || len(pagerduty.alerts) != 0 {
+		t.Errorf("expected the firing and the resolved alerts sent to slack only but got %v and %v", slack.alerts, pagerduty.alerts)
+	}
+}
+
+// activeNotifier lists the active alerts of the dispatcher when notified, deadlocking if delivered under its lock.
+type activeNotifier struct {
+	recordingNotifier
+	d *Dispatcher
+}
+
+func (n *activeNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.d.Active()
+	return n.recordingNotifier.Notify(ctx, alert)
+}
+
+func TestDispatcherEscalationRetry(t *testing.T) {
+	slack, opsgenie := &recordingNotifier{name: "slack"}, &recordingNotifier{name: "opsgenie", err: errors.New("unavailable")}
+	pagerduty := &activeNotifier{recordingNotifier: recordingNotifier{name: "pagerduty"}}
+	notifiers := []Notifier{pagerduty, opsgenie, slack}
+	routing, err := LoadRouting(writeConfig(t, "routing.yaml", "default:\n  notifiers: [slack]\n  escalation:\n    after: 30m\n    notifiers: [pagerduty, opsgenie]\n"), notifiers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := NewDispatcher(log.New(), metrics.With(prometheus.NewRegistry()), notifiers, DispatcherConfig{Routing: routing})
+	pagerduty.d = d
+
+	start := time.Unix(1700000000, 0)
+	at := func(elapsed time.Duration) { d.now = func() time.Time { return start.Add(elapsed) } }
+	alert := testAlert()
+
+	at(0)
+	if err := d.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the escalation failing for opsgenie is retried for opsgenie only, and counted once.
+	at(30 * time.Minute)
+	d.Escalate(context.Background())
+	at(31 * time.Minute)
+	d.Escalate(context.Background())
+	if len(pagerduty.alerts) != 1 || len(opsgenie.alerts) != 2 {
+		t.Fatalf("expected 1 pagerduty and 2 opsgenie alerts but got %d and %d", len(pagerduty.alerts), len(opsgenie.alerts))
+	}
+	if active := d.Active(); len(active) != 1 || active[0].Escalated {
+		t.Errorf("expected the alert not escalated to every notifier yet but got %v", active)
+	}
+
+	opsgenie.err = nil
+	at(32 * time.Minute)
+	d.Escalate(context.Background())
+	at(33 * time.Minute)
+	d.Escalate(context.Background())
+	if len(pagerduty.alerts) != 1 || len(opsgenie.alerts) != 3 {
+		t.Fatalf("expected 1 pagerduty and 3 opsgenie alerts but got %d and %d", len(pagerduty.alerts), len(opsgenie.alerts))
+	}
+	if active := d.Active(); len(active) != 1 || !active[0].Escalated {
+		t.Errorf("expected the alert escalated but got %v", active)
+	}
+	if escalations := testutil.ToFloat64(d.escalations.WithLabelValues(alert.Monitor, alert.Rule, AuditEscalated)); escalations != 1 {
+		t.Errorf("expected the escalation counted once but got %v", escalations)
+	}
+
+	// the resolution is sent to both escalation notifiers.
+	alert.Resolved = true
+	if err := d.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pagerduty.alerts) != 2 || !pagerduty.alerts[1].Resolved || len(opsgenie.alerts) != 4 || !opsgenie.alerts[3].Resolved {
+		t.Errorf("expected the resolution sent to pagerduty and opsgenie but got %v and %v", pagerduty.alerts, opsgenie.alerts)
+	}
+}
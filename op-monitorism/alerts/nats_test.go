@@ -0,0 +1,206 @@
+package alerts
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// natsConnect is the connect sent by the client.
+type natsConnect struct {
+	Verbose   bool   `json:"verbose"`
+	Name      string `json:"name"`
+	User      string `json:"user"`
+	Pass      string `json:"pass"`
+	AuthToken string `json:"auth_token"`
+}
+
+// natsMessage is a message published to the natsServer.
+type natsMessage struct {
+	subject string
+	payload []byte
+}
+
+// natsServer is a minimal NATS server recording the connects and the publications, replying to the flush of a
+// publication with the error when set.
+type natsServer struct {
+	listener net.Listener
+	err      string
+
+	mu          sync.Mutex
+	connections int
+	connect     natsConnect
+	messages    []natsMessage
+}
+
+func newNATSServer(t *testing.T, err string) *natsServer {
+	listener, lerr := net.Listen("tcp", "127.0.0.1:0")
+	if lerr != nil {
+		t.Fatalf("failed to listen: %v", lerr)
+	}
+	srv := &natsServer{listener: listener, err: err}
+	go srv.serve()
+	t.Cleanup(func() { listener.Close() })
+	return srv
+}
+
+func (s *natsServer) url(userinfo string) string {
+	return fmt.Sprintf("nats://%s%s", userinfo, s.listener.Addr())
+}
+
+func (s *natsServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.connections++
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *natsServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reply(`INFO {"server_id":"test","version":"2.10.0","proto":1,"max_payload":1048576}`)
+	published := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.SplitN(strings.TrimRight(line, "\r\n"), " ", 2)
+		switch fields[0] {
+		case "CONNECT":
+			s.mu.Lock()
+			_ = json.Unmarshal([]byte(fields[1]), &s.connect)
+			s.mu.Unlock()
+		case "PUB":
+			args := strings.Fields(fields[1])
+			size, _ := strconv.Atoi(args[len(args)-1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, natsMessage{subject: args[0], payload: payload[:size]})
+			s.mu.Unlock()
+			published = true
+		case "PING":
+			if published && len(s.err) > 0 {
+				reply("-ERR '" + s.err + "'")
+			}
+			reply("PONG")
+		}
+	}
+}
+
+func TestNATSNotifier(t *testing.T) {
+	srv := newNATSServer(t, "")
+	n, err := NewNATSNotifier(srv.url("user:pass@"), "monitorism.alerts", time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer n.Close()
+
+	alert := testAlert()
+	for i := 0; i < 2; i++ {
+		if err := n.Notify(context.Background(), alert); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.connections != 1 {
+		t.Errorf("expected the alerts to share a connection but got %d connections", srv.connections)
+	}
+	if srv.connect.User != "user" || srv.connect.Pass != "pass" || srv.connect.Name != "monitorism" || srv.connect.Verbose {
+		t.Errorf("unexpected connect %+v", srv.connect)
+	}
+	if len(srv.messages) != 2 || srv.messages[0].subject != "monitorism.alerts.global_events.P1" {
+		t.Fatalf("unexpected messages %v", srv.messages)
+	}
+	var message NATSMessage
+	if err := json.Unmarshal(srv.messages[0].payload, &message); err != nil {
+		t.Fatalf("failed to decode the message %q: %v", srv.messages[0].payload, err)
+	}
+	if message.Version != NATSMessageVersion || message.ID != alert.ID() || message.Alert.Rule != alert.Rule || message.Alert.Labels["txHash"] != "0x01" {
+		t.Errorf("unexpected message %s", srv.messages[0].payload)
+	}
+}
+
+func TestNATSNotifierError(t *testing.T) {
+	srv := newNATSServer(t, "Permissions Violation for Publish to monitorism.alerts.global_events.P1")
+	n, err := NewNATSNotifier(srv.url("token@"), "monitorism.alerts", time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer n.Close()
+
+	err = n.Notify(context.Background(), testAlert())
+	if err == nil || !strings.Contains(err.Error(), "Permissions Violation") {
+		t.Errorf("expected the server error but got %v", err)
+	}
+	srv.mu.Lock()
+	if srv.connect.AuthToken != "token" {
+		t.Errorf("unexpected connect %+v", srv.connect)
+	}
+	srv.mu.Unlock()
+
+	// the connection being unavailable, the alert fails until the server is reachable.
+	srv.listener.Close()
+	unreachable, err := NewNATSNotifier(srv.url(""), "monitorism.alerts", time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := unreachable.Notify(context.Background(), testAlert()); err == nil || !strings.Contains(err.Error(), "failed to connect") {
+		t.Errorf("expected the connection to fail but got %v", err)
+	}
+}
+
+func TestNewNATSNotifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		subject string
+		ok      bool
+	}{
+		{"default port", "nats://localhost", "alerts", true},
+		{"tls", "tls://nats.example.com:4443", "alerts", true},
+		{"unknown scheme", "http://localhost:4222", "alerts", false},
+		{"no host", "nats://", "alerts", false},
+		{"no subject", "nats://localhost", "", false},
+		{"wildcard subject", "nats://localhost", "alerts.>", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewNATSNotifier(test.url, test.subject, time.Second); (err == nil) != test.ok {
+				t.Errorf("Failed %s: expected ok=%v but got %v", test.name, test.ok, err)
+			}
+		})
+	}
+}
+
+func TestNATSSubject(t *testing.T) {
+	n, err := NewNATSNotifier("nats://localhost", "monitorism.alerts", time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	alert := Alert{Monitor: "balance mon.itor", Priority: ""}
+	if subject := n.Subject(alert); subject != "monitorism.alerts.balance_mon_itor._" {
+		t.Errorf("unexpected subject %s", subject)
+	}
+}
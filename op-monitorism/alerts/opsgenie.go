@@ -0,0 +1,123 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	OpsgenieAPIURL = "https://api.opsgenie.com"
+
+	// opsgenieMaxMessage and opsgenieMaxTag are the limits of the Opsgenie API, the longer values being truncated.
+	opsgenieMaxMessage = 130
+	opsgenieMaxTag     = 50
+)
+
+// opsgeniePriorities maps the priorities of the rules to the priorities of Opsgenie, which has no `P0`.
+var opsgeniePriorities = map[string]string{
+	"P0": "P1",
+	"P1": "P1",
+	"P2": "P2",
+	"P3": "P3",
+	"P4": "P4",
+	"P5": "P5",
+}
+
+// OpsgeniePriority returns the Opsgenie priority of a priority, the unknown priorities defaulting to `P3` as in Opsgenie.
+func OpsgeniePriority(priority string) string {
+	opsgenie, ok := opsgeniePriorities[priority]
+	if !ok {
+		return "P3"
+	}
+	return opsgenie
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Entity      string            `json:"entity,omitempty"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"`
+}
+
+type opsgenieClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+// OpsgenieNotifier creates the alerts of the selected priorities in Opsgenie, closing them once resolved.
+// The alias being the key of the alert, Opsgenie deduplicates the occurrences of a detection into a single alert.
+type OpsgenieNotifier struct {
+	url        string
+	header     http.Header
+	priorities map[string]bool
+	httpClient *http.Client
+}
+
+// NewOpsgenieNotifier creates an Opsgenie notifier, every priority being selected when `priorities` is empty.
+func NewOpsgenieNotifier(apiURL string, apiKey string, priorities []string, timeout time.Duration) *OpsgenieNotifier {
+	selected := make(map[string]bool, len(priorities))
+	for _, priority := range priorities {
+		selected[priority] = true
+	}
+	return &OpsgenieNotifier{
+		url:        strings.TrimSuffix(apiURL, "/") + "/v2/alerts",
+		header:     http.Header{"Authorization": []string{"GenieKey " + apiKey}},
+		priorities: selected,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *OpsgenieNotifier) Name() string {
+	return "opsgenie"
+}
+
+func (n *OpsgenieNotifier) Notify(ctx context.Context, alert Alert) error {
+	if len(n.priorities) > 0 && !n.priorities[alert.Priority] {
+		return nil
+	}
+	if alert.Resolved {
+		target := fmt.Sprintf("%s/%s/close?identifierType=alias", n.url, url.PathEscape(alert.Key()))
+		return postJSON(ctx, n.httpClient, target, n.header, opsgenieClose{Source: alert.Monitor, Note: alert.Summary})
+	}
+	return postJSON(ctx, n.httpClient, n.url, n.header, opsgeniePayload(alert))
+}
+
+func opsgeniePayload(alert Alert) opsgenieAlert {
+	tags := []string{truncate("monitor:"+alert.Monitor, opsgenieMaxTag)}
+	details := map[string]string{"rule": alert.Rule, "priority": alert.Priority}
+	for _, name := range alert.SortedLabels() {
+		tags = append(tags, truncate(fmt.Sprintf("%s:%s", name, alert.Labels[name]), opsgenieMaxTag))
+		details[name] = alert.Labels[name]
+	}
+
+	return opsgenieAlert{
+		Message:     truncate(alert.Title(), opsgenieMaxMessage),
+		Alias:       alert.Key(),
+		Description: alert.Summary,
+		Tags:        tags,
+		Details:     details,
+		Entity:      alert.Entity,
+		Source:      alert.Monitor,
+		Priority:    OpsgeniePriority(alert.Priority),
+	}
+}
+
+// truncate truncates the value to `limit` bytes, cutting at a rune boundary.
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	for limit > 0 && !utf8.RuneStart(value[limit]) {
+		limit--
+	}
+	return value[:limit]
+}
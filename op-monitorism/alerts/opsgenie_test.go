@@ -0,0 +1,92 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpsgeniePayload(t *testing.T) {
+	alert := testAlert()
+	alert.Priority = "P0"
+	alert.Labels["txHash"] = "0x" + strings.Repeat("ab", 32)
+
+	payload := opsgeniePayload(alert)
+	if payload.Alias != alert.Key() || payload.Priority != "P1" || payload.Entity != alert.Entity {
+		t.Errorf("unexpected payload %v", payload)
+	}
+	expectedTags := []string{"monitor:global_events", "nickname:mainnet", "txHash:0x" + strings.Repeat("ab", 32)[:41]} // truncated to 50 bytes.
+	if len(payload.Tags) != len(expectedTags) {
+		t.Fatalf("expected the tags %v but got %v", expectedTags, payload.Tags)
+	}
+	for i, tag := range expectedTags {
+		if payload.Tags[i] != tag {
+			t.Errorf("expected the tag %s but got %s", tag, payload.Tags[i])
+		}
+	}
+	if payload.Details["txHash"] != alert.Labels["txHash"] || payload.Details["rule"] != alert.Rule {
+		t.Errorf("expected the labels in the details but got %v", payload.Details)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		limit    int
+		expected string
+	}{
+		{"short", "abc", 5, "abc"},
+		{"long", "abcdef", 4, "abcd"},
+		{"multi-byte rune", "abécd", 3, "ab"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if truncated := truncate(test.value, test.limit); truncated != test.expected {
+				t.Errorf("Failed %s: expected %q but got %q", test.name, test.expected, truncated)
+			}
+		})
+	}
+}
+
+func TestOpsgenieNotifier(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "GenieKey secret" {
+			t.Errorf("unexpected authorization %s", auth)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode the body: %v", err)
+		}
+		paths = append(paths, r.URL.EscapedPath()+"?"+r.URL.RawQuery)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	n := NewOpsgenieNotifier(srv.URL, "secret", []string{"P0", "P1"}, time.Second)
+	alert := testAlert()
+	for _, priority := range []string{"P1", "P3"} { // P3 not selected.
+		alert.Priority = priority
+		if err := n.Notify(context.Background(), alert); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	alert.Priority, alert.Resolved = "P1", true
+	if err := n.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"/v2/alerts?",
+		"/v2/alerts/global_events%2FSafe%20owner%20changed%2F0x9BA6e03D8B90dE867373Db8cF1A58d2F7F006b3A/close?identifierType=alias",
+	}
+	if len(paths) != len(expected) || paths[0] != expected[0] || paths[1] != expected[1] {
+		t.Errorf("expected the creation and the close of the alert %v but got %v", expected, paths)
+	}
+}
@@ -0,0 +1,136 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+)
+
+// pagerDutySeverities maps the priorities of the rules to the severities of PagerDuty, the unknown priorities being warnings.
+var pagerDutySeverities = map[string]string{
+	"P0": "critical",
+	"P1": "critical",
+	"P2": "error",
+	"P3": "warning",
+	"P4": "info",
+	"P5": "info",
+}
+
+// PagerDutySeverity returns the PagerDuty severity of a priority.
+func PagerDutySeverity(priority string) string {
+	severity, ok := pagerDutySeverities[priority]
+	if !ok {
+		return "warning"
+	}
+	return severity
+}
+
+// ParsePagerDutyRoutingKeys parses the routing keys formatted via `severity=key`.
+func ParsePagerDutyRoutingKeys(values []string) (map[string]string, error) {
+	keys := make(map[string]string, len(values))
+	for _, value := range values {
+		split := strings.SplitN(value, "=", 2)
+		if len(split) != 2 || len(split[1]) == 0 {
+			return nil, fmt.Errorf("failed to parse `severity=key`: %s", value)
+		}
+		switch split[0] {
+		case "critical", "error", "warning", "info":
+		default:
+			return nil, fmt.Errorf("unknown PagerDuty severity %s", split[0])
+		}
+		if _, ok := keys[split[0]]; ok {
+			return nil, fmt.Errorf("duplicated PagerDuty severity %s", split[0])
+		}
+		keys[split[0]] = split[1]
+	}
+	return keys, nil
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp,omitempty"`
+	Component     string            `json:"component,omitempty"`
+	Class         string            `json:"class,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+// PagerDutyNotifier triggers and resolves PagerDuty incidents via the Events API v2.
+// The routing key is selected by the severity of the alert, the alerts of a severity without a routing key being dropped.
+type PagerDutyNotifier struct {
+	url         string
+	routingKeys map[string]string
+	httpClient  *http.Client
+}
+
+func NewPagerDutyNotifier(url string, routingKeys map[string]string, timeout time.Duration) *PagerDutyNotifier {
+	return &PagerDutyNotifier{url: url, routingKeys: routingKeys, httpClient: &http.Client{Timeout: timeout}}
+}
+
+func (n *PagerDutyNotifier) Name() string {
+	return "pagerduty"
+}
+
+func (n *PagerDutyNotifier) Notify(ctx context.Context, alert Alert) error {
+	event, ok := n.event(alert)
+	if !ok {
+		return nil
+	}
+	return postJSON(ctx, n.httpClient, n.url, nil, event)
+}
+
+// event returns the event of the alert, `false` when no routing key is configured for its severity.
+// The dedup key being the key of the alert, the resolve event clears the incident triggered for the same rule and entity.
+func (n *PagerDutyNotifier) event(alert Alert) (pagerDutyEvent, bool) {
+	severity := PagerDutySeverity(alert.Priority)
+	routingKey, ok := n.routingKeys[severity]
+	if !ok {
+		return pagerDutyEvent{}, false
+	}
+
+	event := pagerDutyEvent{RoutingKey: routingKey, DedupKey: alert.Key()}
+	if alert.Resolved {
+		event.EventAction = "resolve"
+		return event, true
+	}
+
+	details := make(map[string]string, len(alert.Labels)+2)
+	for name, value := range alert.Labels {
+		details[name] = value
+	}
+	details["priority"] = alert.Priority
+	if len(alert.Summary) > 0 {
+		details["summary"] = alert.Summary
+	}
+
+	event.EventAction = "trigger"
+	event.Payload = &pagerDutyPayload{
+		Summary:       alert.Title(),
+		Source:        alert.Entity,
+		Severity:      severity,
+		Component:     alert.Monitor,
+		Class:         alert.Rule,
+		CustomDetails: details,
+	}
+	if len(event.Payload.Source) == 0 {
+		event.Payload.Source = alert.Monitor
+	}
+	if !alert.Time.IsZero() {
+		event.Payload.Timestamp = alert.Time.UTC().Format(time.RFC3339)
+	}
+	return event, true
+}
@@ -0,0 +1,116 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParsePagerDutyRoutingKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		ok     bool
+	}{
+		{"valid", []string{"critical=abc", "warning=def"}, true},
+		{"empty", []string{}, true},
+		{"missing key", []string{"critical="}, false},
+		{"missing separator", []string{"critical"}, false},
+		{"unknown severity", []string{"high=abc"}, false},
+		{"duplicated severity", []string{"critical=abc", "critical=def"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keys, err := ParsePagerDutyRoutingKeys(test.values)
+			if (err == nil) != test.ok {
+				t.Errorf("Failed %s: expected ok=%v but got %v", test.name, test.ok, err)
+			}
+			if err == nil && len(keys) != len(test.values) {
+				t.Errorf("Failed %s: expected %d keys but got %v", test.name, len(test.values), keys)
+			}
+		})
+	}
+}
+
+func TestPagerDutyEvent(t *testing.T) {
+	n := NewPagerDutyNotifier(PagerDutyEventsURL, map[string]string{"critical": "crit-key", "warning": "warn-key"}, time.Second)
+
+	tests := []struct {
+		name     string
+		priority string
+		resolved bool
+		sent     bool
+		key      string
+		action   string
+		severity string
+	}{
+		{"P0 triggers a critical incident", "P0", false, true, "crit-key", "trigger", "critical"},
+		{"P1 triggers a critical incident", "P1", false, true, "crit-key", "trigger", "critical"},
+		{"P2 without error routing key", "P2", false, false, "", "", ""},
+		{"P3 triggers a warning incident", "P3", false, true, "warn-key", "trigger", "warning"},
+		{"unknown priority is a warning", "", false, true, "warn-key", "trigger", "warning"},
+		{"P4 without info routing key", "P4", false, false, "", "", ""},
+		{"resolved", "P0", true, true, "crit-key", "resolve", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alert := testAlert()
+			alert.Priority, alert.Resolved = test.priority, test.resolved
+			event, sent := n.event(alert)
+			if sent != test.sent {
+				t.Fatalf("Failed %s: expected sent=%v but got %v", test.name, test.sent, sent)
+			}
+			if !sent {
+				return
+			}
+			if event.RoutingKey != test.key || event.EventAction != test.action || event.DedupKey != alert.Key() {
+				t.Errorf("Failed %s: expected %s %s with the alert key but got %v", test.name, test.action, test.key, event)
+			}
+			if test.resolved != (event.Payload == nil) {
+				t.Errorf("Failed %s: expected a payload only on trigger but got %v", test.name, event.Payload)
+			}
+			if event.Payload != nil && (event.Payload.Severity != test.severity || event.Payload.Source != alert.Entity) {
+				t.Errorf("Failed %s: expected a %s payload from %s but got %v", test.name, test.severity, alert.Entity, event.Payload)
+			}
+		})
+	}
+}
+
+func TestPagerDutyNotifier(t *testing.T) {
+	var received []pagerDutyEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event pagerDutyEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("failed to decode the event: %v", err)
+		}
+		received = append(received, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	n := NewPagerDutyNotifier(srv.URL, map[string]string{"critical": "crit-key"}, time.Second)
+	alert := testAlert()
+	if err := n.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	alert.Priority = "P3" // no routing key, dropped.
+	if err := n.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	alert.Priority, alert.Resolved = "P1", true
+	if err := n.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(received) != 2 || received[0].EventAction != "trigger" || received[1].EventAction != "resolve" || received[0].DedupKey != received[1].DedupKey {
+		t.Errorf("expected a trigger then a resolve sharing the dedup key but got %v", received)
+	}
+	if received[0].Payload.Timestamp != "2023-11-14T22:13:20Z" {
+		t.Errorf("expected the timestamp of the alert but got %s", received[0].Payload.Timestamp)
+	}
+}
@@ -0,0 +1,118 @@
+package alerts
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Route sends the alerts matching its monitors, rules and priorities (any when empty) to its notifiers.
+type Route struct {
+	Monitors   []string `yaml:"monitors"`
+	Rules      []string `yaml:"rules"`
+	Priorities []string `yaml:"priorities"`
+
+	// Notifiers are the names of the notifiers of the route, e.g. `pagerduty` or `slack`.
+	Notifiers []string `yaml:"notifiers"`
+	// Priority overrides the priority of the alerts of the route when set, e.g. to page on a rule of lower priority.
+	Priority string `yaml:"priority"`
+
+	// Renotify re-sends the unresolved alerts of the route, and Escalation sends them to other notifiers after a while.
+	Renotify   *Renotify   `yaml:"renotify"`
+	Escalation *Escalation `yaml:"escalation"`
+}
+
+func (r Route) Matches(alert Alert) bool {
+	return matchesAny(r.Monitors, alert.Monitor) && matchesAny(r.Rules, alert.Rule) && matchesAny(r.Priorities, alert.Priority)
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Routing is the routing config of the alerts: the first matching route applies, the alerts matching none being
+// sent to the default route. Without a default route, the alerts matching no route are dropped.
+type Routing struct {
+	Routes  []Route `yaml:"routes"`
+	Default *Route  `yaml:"default"`
+}
+
+// LoadRouting reads the routing config from a YAML file, the notifiers of the routes being checked against the
+// names of the configured notifiers.
+func LoadRouting(path string, notifiers []Notifier) (*Routing, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the routing config: %w", err)
+	}
+	var routing Routing
+	if err := yaml.Unmarshal(data, &routing); err != nil {
+		return nil, fmt.Errorf("failed to parse the routing config: %w", err)
+	}
+
+	names := make(map[string]bool, len(notifiers))
+	for _, notifier := range notifiers {
+		names[notifier.Name()] = true
+	}
+	check := func(name string, route Route) error {
+		if len(route.Notifiers) == 0 {
+			return fmt.Errorf("%s has no notifier", name)
+		}
+		for _, notifier := range route.Notifiers {
+			if !names[notifier] {
+				return fmt.Errorf("%s has the unconfigured notifier %s", name, notifier)
+			}
+		}
+		for _, priority := range route.Priorities {
+			if !ValidPriority(priority) {
+				return fmt.Errorf("%s has the unknown priority %s", name, priority)
+			}
+		}
+		if len(route.Priority) > 0 && !ValidPriority(route.Priority) {
+			return fmt.Errorf("%s overrides the priority with the unknown priority %s", name, route.Priority)
+		}
+		if route.Renotify != nil {
+			if err := route.Renotify.check(); err != nil {
+				return fmt.Errorf("%s has an invalid renotify: %w", name, err)
+			}
+		}
+		if route.Escalation != nil {
+			if err := route.Escalation.check(names); err != nil {
+				return fmt.Errorf("%s has an invalid escalation: %w", name, err)
+			}
+		}
+		return nil
+	}
+	for i, route := range routing.Routes {
+		if err := check(fmt.Sprintf("route %d", i), route); err != nil {
+			return nil, err
+		}
+	}
+	if routing.Default != nil {
+		if err := check("the default route", *routing.Default); err != nil {
+			return nil, err
+		}
+	}
+	return &routing, nil
+}
+
+// Route returns the route of the alert, `false` when the alert matches no route and there is no default route.
+func (r *Routing) Route(alert Alert) (Route, bool) {
+	for _, route := range r.Routes {
+		if route.Matches(alert) {
+			return route, true
+		}
+	}
+	if r.Default != nil {
+		return *r.Default, true
+	}
+	return Route{}, false
+}
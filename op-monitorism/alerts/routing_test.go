@@ -0,0 +1,110 @@
+package alerts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const testRouting = `
+routes:
+  - monitors: [fault]
+    notifiers: [pagerduty, slack]
+  - rules: [Safe owner changed]
+    notifiers: [pagerduty]
+    priority: P0
+  - priorities: [P4, P5]
+    notifiers: [slack]
+default:
+  notifiers: [slack]
+`
+
+// writeConfig writes the content to a file of a temporary directory, returning its path.
+func writeConfig(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadRouting(t *testing.T) {
+	notifiers := []Notifier{&recordingNotifier{name: "pagerduty"}, &recordingNotifier{name: "slack"}}
+	tests := []struct {
+		name    string
+		content string
+		ok      bool
+	}{
+		{"valid", testRouting, true},
+		{"no default", "routes:\n  - notifiers: [slack]\n", true},
+		{"invalid yaml", "routes: [", false},
+		{"no notifier", "routes:\n  - monitors: [fault]\n", false},
+		{"unconfigured notifier", "routes:\n  - notifiers: [opsgenie]\n", false},
+		{"unknown priority", "routes:\n  - priorities: [critical]\n    notifiers: [slack]\n", false},
+		{"unknown priority override", "routes:\n  - notifiers: [slack]\n    priority: critical\n", false},
+		{"unconfigured default notifier", "default:\n  notifiers: [discord]\n", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := LoadRouting(writeConfig(t, "routing.yaml", test.content), notifiers); (err == nil) != test.ok {
+				t.Errorf("Failed %s: expected ok=%v but got %v", test.name, test.ok, err)
+			}
+		})
+	}
+}
+
+func TestDispatcherRouting(t *testing.T) {
+	pagerduty, slack := &recordingNotifier{name: "pagerduty"}, &recordingNotifier{name: "slack"}
+	notifiers := []Notifier{pagerduty, slack}
+	routing, err := LoadRouting(writeConfig(t, "routing.yaml", testRouting), notifiers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := NewDispatcher(log.New(), metrics.With(prometheus.NewRegistry()), notifiers, DispatcherConfig{Routing: routing})
+
+	tests := []struct {
+		name      string
+		alert     Alert
+		pagerduty bool
+		slack     bool
+		priority  string
+	}{
+		{"monitor route", Alert{Monitor: "fault", Rule: "output root mismatch", Priority: "P0"}, true, true, "P0"},
+		{"priority override", testAlert(), true, false, "P0"},
+		{"priority route", Alert{Monitor: "global_events", Rule: "Upgrade", Priority: "P5"}, false, true, "P5"},
+		{"default route", Alert{Monitor: "global_events", Rule: "Upgrade", Priority: "P2"}, false, true, "P2"},
+	}
+
+	for _, test := range tests {
+		pagerduty.alerts, slack.alerts = nil, nil
+		if err := d.Notify(context.Background(), test.alert); err != nil {
+			t.Fatalf("Failed %s: unexpected error %v", test.name, err)
+		}
+		for _, check := range []struct {
+			notifier *recordingNotifier
+			expected bool
+		}{{pagerduty, test.pagerduty}, {slack, test.slack}} {
+			if delivered := len(check.notifier.alerts) == 1; delivered != check.expected {
+				t.Errorf("Failed %s: expected delivered=%v to %s but got %v", test.name, check.expected, check.notifier.name, check.notifier.alerts)
+			} else if delivered && check.notifier.alerts[0].Priority != test.priority {
+				t.Errorf("Failed %s: expected priority %s but got %s", test.name, test.priority, check.notifier.alerts[0].Priority)
+			}
+		}
+	}
+
+	// without a default route, the alerts matching no route are dropped.
+	routing.Default = nil
+	slack.alerts = nil
+	if err := d.Notify(context.Background(), Alert{Monitor: "global_events", Rule: "Upgrade", Priority: "P2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(slack.alerts) != 0 {
+		t.Errorf("expected the unrouted alert to be dropped but got %v", slack.alerts)
+	}
+}
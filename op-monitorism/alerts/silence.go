@@ -0,0 +1,118 @@
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrSilenced is returned by the Dispatcher for an alert held during a silence window, the Tracker sending the alert of
+// a condition still holding again on its next update so that it is delivered once out of the window.
+var ErrSilenced = errors.New("alert silenced")
+
+// maxSilenceDuration bounds the duration of a recurring silence, the schedule being scanned minute by minute.
+const maxSilenceDuration = 7 * 24 * time.Hour
+
+// Silence is a window during which the alerts of its monitors and rules (any when empty) are recorded but not
+// delivered, e.g. a planned upgrade. The window is either a one-off time range from `start` to `end`, or recurring
+// for `duration` from every minute matched by the cron `schedule` in the `timezone` (UTC by default).
+type Silence struct {
+	Name     string   `yaml:"name"`
+	Monitors []string `yaml:"monitors"`
+	Rules    []string `yaml:"rules"`
+
+	Start time.Time `yaml:"start"`
+	End   time.Time `yaml:"end"`
+
+	Schedule string        `yaml:"schedule"`
+	Duration time.Duration `yaml:"duration"`
+	Timezone string        `yaml:"timezone"`
+
+	schedule *cronSchedule
+	location *time.Location
+}
+
+func (s *Silence) check() error {
+	if len(s.Name) == 0 {
+		return errors.New("missing name")
+	}
+	oneOff, recurring := !s.Start.IsZero() || !s.End.IsZero(), len(s.Schedule) > 0
+	switch {
+	case oneOff && recurring:
+		return errors.New("both a time range and a schedule")
+	case oneOff:
+		if !s.End.After(s.Start) {
+			return errors.New("end not after start")
+		}
+	case recurring:
+		var err error
+		if s.schedule, err = parseCron(s.Schedule); err != nil {
+			return err
+		}
+		if s.Duration <= 0 || s.Duration > maxSilenceDuration {
+			return fmt.Errorf("duration out of (0, %s]", maxSilenceDuration)
+		}
+		if s.location, err = time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+	default:
+		return errors.New("neither a time range nor a schedule")
+	}
+	return nil
+}
+
+// Active reports whether the silence applies to the alert at the time.
+func (s *Silence) Active(alert Alert, at time.Time) bool {
+	if !matchesAny(s.Monitors, alert.Monitor) || !matchesAny(s.Rules, alert.Rule) {
+		return false
+	}
+	if s.schedule == nil {
+		return !at.Before(s.Start) && at.Before(s.End)
+	}
+	for start := at.Truncate(time.Minute); at.Sub(start) < s.Duration; start = start.Add(-time.Minute) {
+		if s.schedule.Matches(start.In(s.location)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Silences are the silence windows of the alerts.
+type Silences struct {
+	Silences []*Silence `yaml:"silences"`
+}
+
+// LoadSilences reads the silence windows from a YAML file.
+func LoadSilences(path string) (*Silences, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the silences: %w", err)
+	}
+	var silences Silences
+	if err := yaml.Unmarshal(data, &silences); err != nil {
+		return nil, fmt.Errorf("failed to parse the silences: %w", err)
+	}
+	for i, silence := range silences.Silences {
+		if err := silence.check(); err != nil {
+			return nil, fmt.Errorf("invalid silence %d %s: %w", i, silence.Name, err)
+		}
+	}
+	return &silences, nil
+}
+
+// Silence returns the first silence active for the alert at the time, a resolved alert never being silenced so that
+// the incidents opened before a window are closed.
+func (s *Silences) Silence(alert Alert, at time.Time) (*Silence, bool) {
+	if alert.Resolved {
+		return nil, false
+	}
+	for _, silence := range s.Silences {
+		if silence.Active(alert, at) {
+			return silence, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,201 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCronSchedule(t *testing.T) {
+	tests := []struct {
+		expr    string
+		at      string
+		matches bool
+	}{
+		{"* * * * *", "2024-05-02T09:12:00Z", true},
+		{"0 2 * * *", "2024-05-02T02:00:00Z", true},
+		{"0 2 * * *", "2024-05-02T02:01:00Z", false},
+		{"*/15 9-17 * * 1-5", "2024-05-02T09:45:00Z", true},  // Thursday
+		{"*/15 9-17 * * 1-5", "2024-05-04T09:45:00Z", false}, // Saturday
+		{"0,30 * * 1,7 *", "2024-07-10T11:30:00Z", true},
+		{"0 0 1 * 0", "2024-05-05T00:00:00Z", true}, // a Sunday, either of the days matching
+		{"0 0 1 * 0", "2024-05-01T00:00:00Z", true},
+		{"0 0 1 * 0", "2024-05-02T00:00:00Z", false},
+	}
+
+	for _, test := range tests {
+		schedule, err := parseCron(test.expr)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		at, _ := time.Parse(time.RFC3339, test.at)
+		if matches := schedule.Matches(at); matches != test.matches {
+			t.Errorf("Failed %s at %s: expected matches=%v but got %v", test.expr, test.at, test.matches, matches)
+		}
+	}
+
+	for _, expr := range []string{"* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 7", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("expected %q to fail", expr)
+		}
+	}
+}
+
+func TestLoadSilences(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		ok      bool
+	}{
+		{"one-off", "silences:\n  - name: upgrade\n    start: 2024-05-02T09:00:00Z\n    end: 2024-05-02T11:00:00Z\n", true},
+		{"recurring", "silences:\n  - name: maintenance\n    monitors: [fault]\n    schedule: 0 2 * * 6\n    duration: 2h\n    timezone: UTC\n", true},
+		{"missing name", "silences:\n  - start: 2024-05-02T09:00:00Z\n    end: 2024-05-02T11:00:00Z\n", false},
+		{"end before start", "silences:\n  - name: upgrade\n    start: 2024-05-02T11:00:00Z\n    end: 2024-05-02T09:00:00Z\n", false},
+		{"missing end", "silences:\n  - name: upgrade\n    start: 2024-05-02T09:00:00Z\n", false},
+		{"range and schedule", "silences:\n  - name: upgrade\n    start: 2024-05-02T09:00:00Z\n    end: 2024-05-02T11:00:00Z\n    schedule: 0 2 * * 6\n    duration: 2h\n", false},
+		{"no window", "silences:\n  - name: upgrade\n    monitors: [fault]\n", false},
+		{"invalid schedule", "silences:\n  - name: maintenance\n    schedule: 0 2 * *\n    duration: 2h\n", false},
+		{"missing duration", "silences:\n  - name: maintenance\n    schedule: 0 2 * * 6\n", false},
+		{"duration too long", "silences:\n  - name: maintenance\n    schedule: 0 2 * * 6\n    duration: 200h\n", false},
+		{"invalid timezone", "silences:\n  - name: maintenance\n    schedule: 0 2 * * 6\n    duration: 2h\n    timezone: Mars/Olympus\n", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := LoadSilences(writeConfig(t, "silences.yaml", test.content)); (err == nil) != test.ok {
+				t.Errorf("Failed %s: expected ok=%v but got %v", test.name, test.ok, err)
+			}
+		})
+	}
+}
+
+const testSilences = `
+silences:
+  - name: upgrade
+    monitors: [fault]
+    start: 2024-05-02T09:00:00Z
+    end: 2024-05-02T11:00:00Z
+  - name: weekly maintenance
+    rules: [Safe owner changed]
+    schedule: 30 23 * * 6
+    duration: 2h
+`
+
+func TestSilences(t *testing.T) {
+	silences, err := LoadSilences(writeConfig(t, "silences.yaml", testSilences))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fault := Alert{Monitor: "fault", Rule: "output root mismatch", Priority: "P0"}
+	tests := []struct {
+		name    string
+		alert   Alert
+		at      string
+		silence string
+	}{
+		{"within the range", fault, "2024-05-02T09:00:00Z", "upgrade"},
+		{"before the range", fault, "2024-05-02T08:59:59Z", ""},
+		{"at the end of the range", fault, "2024-05-02T11:00:00Z", ""},
+		{"resolved within the range", Alert{Monitor: "fault", Rule: "output root mismatch", Priority: "P0", Resolved: true}, "2024-05-02T10:00:00Z", ""},
+		{"other monitor within the range", testAlert(), "2024-05-02T10:00:00Z", ""},
+		{"at the schedule", testAlert(), "2024-05-04T23:30:00Z", "weekly maintenance"},
+		{"within the duration, the next day", testAlert(), "2024-05-05T01:29:59Z", "weekly maintenance"},
+		{"after the duration", testAlert(), "2024-05-05T01:30:00Z", ""},
+		{"other rule at the schedule", fault, "2024-05-04T23:45:00Z", ""},
+	}
+
+	for _, test := range tests {
+		at, _ := time.Parse(time.RFC3339, test.at)
+		name := ""
+		if silence, ok := silences.Silence(test.alert, at); ok {
+			name = silence.Name
+		}
+		if name != test.silence {
+			t.Errorf("Failed %s: expected silence %q but got %q", test.name, test.silence, name)
+		}
+	}
+}
+
+func TestDispatcherSilences(t *testing.T) {
+	silences, err := LoadSilences(writeConfig(t, "silences.yaml", testSilences))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notifier := &recordingNotifier{name: "slack"}
+	d := NewDispatcher(log.New(), metrics.With(prometheus.NewRegistry()), []Notifier{notifier}, DispatcherConfig{Dedup: NewDeduplicator(time.Hour, nil), Silences: silences})
+
+	start, _ := time.Parse(time.RFC3339, "2024-05-02T10:00:00Z")
+	fault := Alert{Monitor: "fault", Rule: "output root mismatch", Priority: "P0"}
+	steps := []struct {
+		alert   Alert
+		at      time.Time
+		deliver bool
+	}{
+		{fault, start, false},
+		// the silenced alert didn't start the cooldown of the detection.
+		{fault, start.Add(time.Hour), true},
+	}
+	for i, step := range steps {
+		d.now = func() time.Time { return step.at }
+		notifier.alerts = nil
+		if err := d.Notify(context.Background(), step.alert); (err != nil) != !step.deliver {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		} else if err != nil && !errors.Is(err, ErrSilenced) {
+			t.Errorf("step %d: expected ErrSilenced but got %v", i, err)
+		}
+		if delivered := len(notifier.alerts) == 1; delivered != step.deliver {
+			t.Errorf("step %d: expected delivered=%v but got %v", i, step.deliver, delivered)
+		}
+	}
+}
+
+func TestTrackerSilences(t *testing.T) {
+	silences, err := LoadSilences(writeConfig(t, "silences.yaml", testSilences))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notifier := &recordingNotifier{name: "slack"}
+	d := NewDispatcher(log.New(), metrics.With(prometheus.NewRegistry()), []Notifier{notifier}, DispatcherConfig{Silences: silences})
+	var tracker Tracker
+	tracker.SetNotifier(d)
+
+	start, _ := time.Parse(time.RFC3339, "2024-05-02T10:00:00Z")
+	fault := Alert{Monitor: "fault", Rule: "output root mismatch", Priority: "P0"}
+	steps := []struct {
+		name     string
+		at       time.Time
+		firing   bool
+		expected int // number of alerts delivered after the update.
+	}{
+		{"condition starting within the window", start, true, 0},
+		{"condition still holding within the window", start.Add(30 * time.Minute), true, 0},
+		{"condition still holding once out of the window", start.Add(time.Hour), true, 1},
+		{"condition still holding", start.Add(90 * time.Minute), true, 1},
+		{"condition clearing", start.Add(2 * time.Hour), false, 2},
+	}
+	for _, step := range steps {
+		d.now = func() time.Time { return step.at }
+		tracker.Update(context.Background(), fault, step.firing)
+		if len(notifier.alerts) != step.expected {
+			t.Fatalf("Failed %s: expected %d alerts but got %d", step.name, step.expected, len(notifier.alerts))
+		}
+	}
+	if !notifier.alerts[1].Resolved {
+		t.Errorf("expected the resolution to be delivered once the condition cleared")
+	}
+
+	// a condition clearing within the window was never delivered, its resolution is not sent either.
+	other := Alert{Monitor: "fault", Rule: "output root mismatch", Priority: "P0", Entity: "other"}
+	d.now = func() time.Time { return start }
+	tracker.Update(context.Background(), other, true)
+	tracker.Update(context.Background(), other, false)
+	if len(notifier.alerts) != 2 {
+		t.Errorf("expected no resolution of the silenced alert but got %d alerts", len(notifier.alerts))
+	}
+}
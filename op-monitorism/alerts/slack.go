@@ -0,0 +1,65 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+type slackAttachment struct {
+	Color     string       `json:"color"`
+	Title     string       `json:"title"`
+	Text      string       `json:"text,omitempty"`
+	Fields    []slackField `json:"fields,omitempty"`
+	Timestamp int64        `json:"ts"`
+}
+
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+// SlackNotifier posts the alerts to a Slack incoming webhook.
+type SlackNotifier struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+func NewSlackNotifier(webhookURL string, timeout time.Duration) *SlackNotifier {
+	return &SlackNotifier{webhookURL: webhookURL, httpClient: &http.Client{Timeout: timeout}}
+}
+
+func (n *SlackNotifier) Name() string {
+	return "slack"
+}
+
+func (n *SlackNotifier) Notify(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, n.httpClient, n.webhookURL, nil, slackPayload(alert))
+}
+
+func slackPayload(alert Alert) slackMessage {
+	fields := []slackField{}
+	if len(alert.Entity) > 0 {
+		fields = append(fields, slackField{Title: "entity", Value: alert.Entity})
+	}
+	for _, name := range alert.SortedLabels() {
+		fields = append(fields, slackField{Title: name, Value: alert.Labels[name], Short: true})
+	}
+
+	return slackMessage{
+		Text: alert.Title(),
+		Attachments: []slackAttachment{{
+			Color:     alertColor(alert),
+			Title:     alert.Title(),
+			Text:      alert.Summary,
+			Fields:    fields,
+			Timestamp: alert.Time.Unix(),
+		}},
+	}
+}
@@ -0,0 +1,115 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func testAlert() Alert {
+	return Alert{
+		Monitor:  "global_events",
+		Rule:     "Safe owner changed",
+		Priority: "P1",
+		Entity:   "0x9BA6e03D8B90dE867373Db8cF1A58d2F7F006b3A",
+		Summary:  "AddedOwner(address) emitted",
+		Labels:   map[string]string{"txHash": "0x01", "nickname": "mainnet"},
+		Time:     time.Unix(1700000000, 0),
+	}
+}
+
+func TestSlackPayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		alert    func() Alert
+		title    string
+		color    string
+		labelsAt int
+	}{
+		{"firing", testAlert, "[P1] global_events: Safe owner changed", "#e01e5a", 1},
+		{"unknown priority", func() Alert { a := testAlert(); a.Priority = "P4"; return a }, "[P4] global_events: Safe owner changed", defaultColor, 1},
+		{"resolved", func() Alert { a := testAlert(); a.Resolved = true; return a }, "[RESOLVED] global_events: Safe owner changed", resolvedColor, 1},
+		{"no entity", func() Alert { a := testAlert(); a.Entity = ""; return a }, "[P1] global_events: Safe owner changed", "#e01e5a", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			payload := slackPayload(test.alert())
+			attachment := payload.Attachments[0]
+			if payload.Text != test.title || attachment.Title != test.title {
+				t.Errorf("Failed %s: expected the title %s but got %s", test.name, test.title, payload.Text)
+			}
+			if attachment.Color != test.color {
+				t.Errorf("Failed %s: expected the color %s but got %s", test.name, test.color, attachment.Color)
+			}
+			// the labels follow the entity, in alphabetical order.
+			if len(attachment.Fields) != test.labelsAt+2 || attachment.Fields[test.labelsAt].Title != "nickname" || attachment.Fields[test.labelsAt+1].Title != "txHash" {
+				t.Errorf("Failed %s: expected the sorted labels after %d fields but got %v", test.name, test.labelsAt, attachment.Fields)
+			}
+		})
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	var received slackMessage
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode the payload: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	notifier := NewSlackNotifier(srv.URL, time.Second)
+	if err := notifier.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received.Text != testAlert().Title() || received.Attachments[0].Timestamp != 1700000000 {
+		t.Errorf("expected the alert to be posted but got %v", received)
+	}
+
+	status = http.StatusForbidden
+	if err := notifier.Notify(context.Background(), testAlert()); err == nil {
+		t.Errorf("expected an error on an unexpected status")
+	}
+}
+
+type recordingNotifier struct {
+	name   string
+	err    error
+	alerts []Alert
+}
+
+func (n *recordingNotifier) Name() string { return n.name }
+
+func (n *recordingNotifier) Notify(_ context.Context, alert Alert) error {
+	n.alerts = append(n.alerts, alert)
+	return n.err
+}
+
+func TestDispatcher(t *testing.T) {
+	failing := &recordingNotifier{name: "failing", err: errors.New("unavailable")}
+	working := &recordingNotifier{name: "working"}
+	d := NewDispatcher(log.New(), metrics.With(prometheus.NewRegistry()), []Notifier{failing, working}, DispatcherConfig{})
+
+	alert := testAlert()
+	alert.Time = time.Time{}
+	if err := d.Notify(context.Background(), alert); err == nil {
+		t.Errorf("expected the error of the failing notifier")
+	}
+	if len(working.alerts) != 1 {
+		t.Fatalf("expected the alert to be delivered despite the failing notifier")
+	}
+	if working.alerts[0].Time.IsZero() {
+		t.Errorf("expected the time of the alert to be set")
+	}
+}
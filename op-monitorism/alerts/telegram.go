@@ -0,0 +1,53 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	TelegramAPIURL = "https://api.telegram.org"
+)
+
+type telegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+// TelegramNotifier sends the alerts through a Telegram bot to the chats of the channels matching them.
+type TelegramNotifier struct {
+	url        string
+	channels   []Channel
+	httpClient *http.Client
+}
+
+func NewTelegramNotifier(apiURL string, botToken string, channels []Channel, timeout time.Duration) *TelegramNotifier {
+	return &TelegramNotifier{
+		url:        fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimSuffix(apiURL, "/"), botToken),
+		channels:   channels,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *TelegramNotifier) Name() string {
+	return "telegram"
+}
+
+func (n *TelegramNotifier) Notify(ctx context.Context, alert Alert) error {
+	var errs []error
+	for _, channel := range n.channels {
+		if !channel.Filter.Matches(alert) {
+			continue
+		}
+		message := telegramMessage{ChatID: channel.Destination, Text: plainText(alert), DisableWebPagePreview: true}
+		if err := postJSON(ctx, n.httpClient, n.url, nil, message); err != nil {
+			errs = append(errs, fmt.Errorf("chat %s: %w", channel.Destination, err))
+		}
+	}
+	return errors.Join(errs...)
+}
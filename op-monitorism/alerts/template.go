@@ -0,0 +1,162 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// templateFuncs are the functions available to the templates rendering the alerts.
+var templateFuncs = template.FuncMap{
+	// json encodes a value, e.g. `{{ json .Summary }}` for a quoted and escaped string.
+	"json": func(v interface{}) (string, error) {
+		encoded, err := json.Marshal(v)
+		return string(encoded), err
+	},
+	"shortAddress": shortAddress,
+	"etherscan":    etherscan,
+	"tokenValue":   tokenValue,
+}
+
+// shortAddress shortens an address or a hash to its first and last characters, e.g. `0x9BA6…6b3A`.
+func shortAddress(value string) string {
+	if !strings.HasPrefix(value, "0x") || len(value) <= 12 {
+		return value
+	}
+	return value[:6] + "…" + value[len(value)-4:]
+}
+
+// etherscan returns the link of an address, a transaction hash or a block number on the explorer, Etherscan by
+// default, e.g. `{{ etherscan .Labels.txHash "https://optimistic.etherscan.io" }}`.
+func etherscan(value string, explorer ...string) (string, error) {
+	base := "https://etherscan.io"
+	if len(explorer) > 0 {
+		base = strings.TrimSuffix(explorer[0], "/")
+	}
+	switch {
+	case common.IsHexAddress(value):
+		return fmt.Sprintf("%s/address/%s", base, value), nil
+	case len(value) == 2*common.HashLength+2 && strings.HasPrefix(value, "0x"):
+		return fmt.Sprintf("%s/tx/%s", base, value), nil
+	}
+	if _, ok := new(big.Int).SetString(value, 10); ok {
+		return fmt.Sprintf("%s/block/%s", base, value), nil
+	}
+	return "", fmt.Errorf("%q is neither an address, a transaction hash nor a block number", value)
+}
+
+// tokenValue formats an amount in the base unit of a token with its decimals, the integer part being grouped by
+// thousands, e.g. `{{ tokenValue .Labels.amount 18 }}` renders `1234500000000000000000` as `1,234.5`.
+func tokenValue(value string, decimals int) (string, error) {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return "", fmt.Errorf("invalid amount %q", value)
+	}
+	if decimals < 0 {
+		return "", fmt.Errorf("negative decimals %d", decimals)
+	}
+
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+		amount.Neg(amount)
+	}
+	integer, fraction := new(big.Int).QuoRem(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil), new(big.Int))
+
+	digits := integer.String()
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	if fraction.Sign() == 0 {
+		return sign + grouped.String(), nil
+	}
+	decimalsPart := strings.TrimRight(fmt.Sprintf("%0*s", decimals, fraction.String()), "0")
+	return sign + grouped.String() + "." + decimalsPart, nil
+}
+
+// parseTemplate parses a template over the Alert, the missing labels rendering as empty strings.
+func parseTemplate(name string, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+}
+
+func parseTemplateFile(name string, path string) (*template.Template, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the template: %w", err)
+	}
+	return parseTemplate(name, string(text))
+}
+
+// MessageTemplate renders the message of the alerts of the selected monitors sent to the selected notifiers, an
+// empty set matching every value.
+type MessageTemplate struct {
+	Notifiers map[string]bool
+	Monitors  map[string]bool
+	Template  *template.Template
+}
+
+func (t MessageTemplate) Matches(notifier string, alert Alert) bool {
+	if len(t.Notifiers) > 0 && !t.Notifiers[notifier] {
+		return false
+	}
+	if len(t.Monitors) > 0 && !t.Monitors[alert.Monitor] {
+		return false
+	}
+	return true
+}
+
+// ParseMessageTemplates parses the message templates formatted via `notifiers[:monitors]=path`, the notifiers and
+// the monitors being comma-separated lists or `*` for any, e.g. `slack,discord:global_events=events.tmpl`.
+func ParseMessageTemplates(values []string) ([]MessageTemplate, error) {
+	templates := make([]MessageTemplate, 0, len(values))
+	for _, value := range values {
+		split := strings.SplitN(value, "=", 2)
+		if len(split) != 2 || len(split[0]) == 0 || len(split[1]) == 0 {
+			return nil, fmt.Errorf("failed to parse `notifiers[:monitors]=path`: %s", value)
+		}
+
+		selectors := strings.SplitN(split[0], ":", 2)
+		notifiers, err := parseSelector(selectors[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the notifiers of %s: %w", value, err)
+		}
+		monitors := map[string]bool{}
+		if len(selectors) == 2 {
+			if monitors, err = parseSelector(selectors[1]); err != nil {
+				return nil, fmt.Errorf("failed to parse the monitors of %s: %w", value, err)
+			}
+		}
+		tmpl, err := parseTemplateFile("message", split[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the template of %s: %w", value, err)
+		}
+		templates = append(templates, MessageTemplate{Notifiers: notifiers, Monitors: monitors, Template: tmpl})
+	}
+	return templates, nil
+}
+
+// renderMessage returns the alert whose summary is the message rendered by the first template matching the notifier
+// and the monitor of the alert, the alert as is when none matches.
+func renderMessage(templates []MessageTemplate, notifier string, alert Alert) (Alert, error) {
+	for _, t := range templates {
+		if !t.Matches(notifier, alert) {
+			continue
+		}
+		var message strings.Builder
+		if err := t.Template.Execute(&message, alert); err != nil {
+			return alert, fmt.Errorf("failed to render the message: %w", err)
+		}
+		alert.Summary = strings.TrimSpace(message.String())
+		return alert, nil
+	}
+	return alert, nil
+}
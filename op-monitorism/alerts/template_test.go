@@ -0,0 +1,134 @@
+package alerts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"short address", `{{ shortAddress .Entity }}`, "0x9BA6…6b3A"},
+		{"short label", `{{ shortAddress .Labels.txHash }}`, "0x01"},
+		{"address link", `{{ etherscan .Entity }}`, "https://etherscan.io/address/0x9BA6e03D8B90dE867373Db8cF1A58d2F7F006b3A"},
+		{"transaction link", `{{ etherscan "0x3f5e0ba6bbcb8b0e2db8b24cd8a7e6d5b3b1d5fa27b6a0d4c8e5f1a2b3c4d5e6" "https://optimistic.etherscan.io/" }}`, "https://optimistic.etherscan.io/tx/0x3f5e0ba6bbcb8b0e2db8b24cd8a7e6d5b3b1d5fa27b6a0d4c8e5f1a2b3c4d5e6"},
+		{"block link", `{{ etherscan "19000000" }}`, "https://etherscan.io/block/19000000"},
+		{"token value", `{{ tokenValue "1234500000000000000000" 18 }}`, "1,234.5"},
+		{"token value without fraction", `{{ tokenValue "1000000" 6 }}`, "1"},
+		{"token value below one", `{{ tokenValue "1500" 6 }}`, "0.0015"},
+		{"negative token value", `{{ tokenValue "-123456789" 0 }}`, "-123,456,789"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpl, err := parseTemplate(test.name, test.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			alert, err := renderMessage([]MessageTemplate{{Template: tmpl}}, "slack", testAlert())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if alert.Summary != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, alert.Summary)
+			}
+		})
+	}
+
+	for _, text := range []string{`{{ etherscan "mainnet" }}`, `{{ tokenValue "1.5" 18 }}`, `{{ tokenValue "1" -1 }}`} {
+		tmpl, err := parseTemplate("invalid", text)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := renderMessage([]MessageTemplate{{Template: tmpl}}, "slack", testAlert()); err == nil {
+			t.Errorf("expected %s to fail", text)
+		}
+	}
+}
+
+func TestParseMessageTemplates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "message.tmpl")
+	if err := os.WriteFile(path, []byte("{{ .Summary }}"), 0o600); err != nil {
+		t.Fatalf("failed to write the template: %v", err)
+	}
+	tests := []struct {
+		name   string
+		values []string
+		ok     bool
+	}{
+		{"valid", []string{"slack,discord:global_events=" + path, "*=" + path}, true},
+		{"missing path", []string{"slack"}, false},
+		{"unknown path", []string{"slack=" + path + ".missing"}, false},
+		{"invalid selector", []string{"slack,:fault=" + path}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseMessageTemplates(test.values); (err == nil) != test.ok {
+				t.Errorf("Failed %s: expected ok=%v but got %v", test.name, test.ok, err)
+			}
+		})
+	}
+}
+
+func TestDispatcherTemplates(t *testing.T) {
+	events, err := parseTemplate("events", "{{ .Labels.signature }} on {{ .Labels.nickname }}: {{ etherscan .Labels.txHash }}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fallback, err := parseTemplate("fallback", "{{ .Summary }} ({{ shortAddress .Entity }})")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	broken, err := parseTemplate("broken", "{{ etherscan .Summary }}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	templates := []MessageTemplate{
+		{Notifiers: map[string]bool{"slack": true}, Monitors: map[string]bool{"global_events": true}, Template: events},
+		{Notifiers: map[string]bool{"telegram": true}, Template: broken},
+		{Notifiers: map[string]bool{"slack": true}, Template: fallback},
+	}
+
+	slack, telegram, pagerduty := &recordingNotifier{name: "slack"}, &recordingNotifier{name: "telegram"}, &recordingNotifier{name: "pagerduty"}
+	d := NewDispatcher(log.New(), metrics.With(prometheus.NewRegistry()), []Notifier{slack, telegram, pagerduty}, DispatcherConfig{Templates: templates})
+
+	alert := testAlert()
+	alert.Labels["txHash"] = "0x3f5e0ba6bbcb8b0e2db8b24cd8a7e6d5b3b1d5fa27b6a0d4c8e5f1a2b3c4d5e6"
+	alert.Labels["signature"] = "AddedOwner(address)"
+	fault := Alert{Monitor: "fault", Rule: "output root mismatch", Priority: "P0", Entity: "0xdfe97868233d1aa22e815a266982f2cf17685a27", Summary: "output root 1 mismatch"}
+	for _, a := range []Alert{alert, fault} {
+		if err := d.Notify(context.Background(), a); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := []struct {
+		notifier *recordingNotifier
+		messages []string
+	}{
+		{slack, []string{"AddedOwner(address) on mainnet: https://etherscan.io/tx/0x3f5e0ba6bbcb8b0e2db8b24cd8a7e6d5b3b1d5fa27b6a0d4c8e5f1a2b3c4d5e6", "output root 1 mismatch (0xdfe9…5a27)"}},
+		// the broken template falls back to the summary of the alert.
+		{telegram, []string{"AddedOwner(address) emitted", "output root 1 mismatch"}},
+		{pagerduty, []string{"AddedOwner(address) emitted", "output root 1 mismatch"}},
+	}
+	for _, e := range expected {
+		if len(e.notifier.alerts) != len(e.messages) {
+			t.Fatalf("expected %d alerts sent to %s but got %d", len(e.messages), e.notifier.name, len(e.notifier.alerts))
+		}
+		for i, message := range e.messages {
+			if e.notifier.alerts[i].Summary != message {
+				t.Errorf("expected the message %q sent to %s but got %q", message, e.notifier.name, e.notifier.alerts[i].Summary)
+			}
+		}
+	}
+}
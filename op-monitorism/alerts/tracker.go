@@ -0,0 +1,84 @@
+package alerts
+
+import (
+	"context"
+	"sync"
+)
+
+// Tracker emits the alerts of a monitor checking its conditions on every iteration: the alert of a condition is sent
+// once when the condition starts holding and resolved once it clears, rather than on every iteration. Embedded in a
+// monitor, it implements the Emitter, the alerts being dropped until a notifier is set.
+//
+// The failed deliveries are logged by the Dispatcher, the transition of a condition being sent again on its next update.
+// A silenced alert is not delivered either, so that a condition still holding once out of the window is delivered and
+// a condition clearing within the window isn't resolved without ever being delivered.
+type Tracker struct {
+	mu       sync.Mutex
+	notifier Notifier
+	// firing are the keys of the alerts whose condition holds.
+	firing map[string]bool
+}
+
+// resolveNotifier is implemented by the notifiers whose alerts are resolved on behalf of the monitors, e.g. the
+// Dispatcher via its API.
+type resolveNotifier interface {
+	OnResolve(fn func(alert Alert))
+}
+
+// SetNotifier sets the notifier receiving the alerts of the monitor, the alerts resolved on behalf of the monitor
+// being forgotten so that a condition still holding is alerted again and a condition clearing isn't resolved twice.
+func (t *Tracker) SetNotifier(notifier Notifier) {
+	t.mu.Lock()
+	t.notifier = notifier
+	t.mu.Unlock()
+	if resolver, ok := notifier.(resolveNotifier); ok {
+		resolver.OnResolve(t.forget)
+	}
+}
+
+// forget clears the state of the condition of the alert.
+func (t *Tracker) forget(alert Alert) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.firing, alert.Key())
+}
+
+// Update sends the alert when its condition starts holding and its resolution once the condition clears, the updates
+// not changing the state of the condition being ignored.
+func (t *Tracker) Update(ctx context.Context, alert Alert, firing bool) {
+	key := alert.Key()
+	t.mu.Lock()
+	notifier := t.notifier
+	changed := t.firing[key] != firing
+	t.mu.Unlock()
+	if notifier == nil || !changed {
+		return
+	}
+
+	alert.Resolved = !firing
+	if err := notifier.Notify(ctx, alert); err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.firing == nil {
+		t.firing = map[string]bool{}
+	}
+	if firing {
+		t.firing[key] = true
+	} else {
+		delete(t.firing, key)
+	}
+}
+
+// Emit sends the alert of a one-off detection, e.g. an event, which is never resolved.
+func (t *Tracker) Emit(ctx context.Context, alert Alert) {
+	t.mu.Lock()
+	notifier := t.notifier
+	t.mu.Unlock()
+	if notifier == nil {
+		return
+	}
+	_ = notifier.Notify(ctx, alert)
+}
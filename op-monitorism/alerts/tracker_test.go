@@ -0,0 +1,48 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTracker(t *testing.T) {
+	var tracker Tracker
+	alert := testAlert()
+
+	// dropped until a notifier is set, the condition being sent once set.
+	tracker.Update(context.Background(), alert, true)
+	notifier := &recordingNotifier{name: "recording"}
+	tracker.SetNotifier(notifier)
+
+	steps := []struct {
+		name     string
+		firing   bool
+		err      error
+		expected int  // number of alerts received by the notifier after the update.
+		resolved bool // resolution of the last alert received.
+	}{
+		{name: "Condition starting", firing: true, expected: 1},
+		{name: "Condition still holding", firing: true, expected: 1},
+		{name: "Failed resolution", firing: false, err: errors.New("unavailable"), expected: 2, resolved: true},
+		{name: "Resolution retried", firing: false, expected: 3, resolved: true},
+		{name: "Condition still cleared", firing: false, expected: 3, resolved: true},
+		{name: "Condition holding again", firing: true, expected: 4},
+	}
+	for _, step := range steps {
+		notifier.err = step.err
+		tracker.Update(context.Background(), alert, step.firing)
+		if len(notifier.alerts) != step.expected {
+			t.Fatalf("Failed %s: expected %d alerts but got %d", step.name, step.expected, len(notifier.alerts))
+		}
+		if last := notifier.alerts[len(notifier.alerts)-1]; last.Resolved != step.resolved {
+			t.Errorf("Failed %s: expected resolved=%v but got %v", step.name, step.resolved, last.Resolved)
+		}
+	}
+
+	tracker.Emit(context.Background(), alert)
+	tracker.Emit(context.Background(), alert)
+	if len(notifier.alerts) != 6 {
+		t.Errorf("expected every one-off alert to be sent but got %d alerts", len(notifier.alerts))
+	}
+}
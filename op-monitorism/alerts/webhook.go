@@ -0,0 +1,118 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/retry"
+)
+
+const (
+	WebhookSignatureHeader = "X-Monitorism-Signature"
+)
+
+// ParseWebhookTemplate parses the template of the webhook body from a file.
+func ParseWebhookTemplate(path string) (*template.Template, error) {
+	return parseTemplateFile("webhook", path)
+}
+
+// ParseWebhookHeaders parses the headers formatted via `name=value`.
+func ParseWebhookHeaders(values []string) (http.Header, error) {
+	header := http.Header{}
+	for _, value := range values {
+		split := strings.SplitN(value, "=", 2)
+		if len(split) != 2 || len(split[0]) == 0 {
+			return nil, fmt.Errorf("failed to parse `name=value`: %s", value)
+		}
+		header.Add(split[0], split[1])
+	}
+	return header, nil
+}
+
+// WebhookNotifier posts the alerts to an arbitrary endpoint, the escape hatch for the systems without a dedicated notifier.
+// The JSON body is rendered by the template over the Alert, the alert itself being encoded without a template. When a
+// secret is configured, the body is signed with HMAC-SHA256 into the `X-Monitorism-Signature` header as `sha256=<hex>`.
+type WebhookNotifier struct {
+	url      string
+	template *template.Template
+	header   http.Header
+	secret   []byte
+
+	attempts   int
+	strategy   retry.Strategy
+	httpClient *http.Client
+}
+
+func NewWebhookNotifier(url string, template *template.Template, header http.Header, secret string, attempts int, timeout time.Duration) *WebhookNotifier {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &WebhookNotifier{
+		url:      url,
+		template: template,
+		header:   header,
+		secret:   []byte(secret),
+
+		attempts:   attempts,
+		strategy:   retry.Exponential(),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := n.body(alert)
+	if err != nil {
+		return err
+	}
+
+	header := n.header.Clone()
+	if len(n.secret) > 0 {
+		header.Set(WebhookSignatureHeader, Sign(n.secret, body))
+	}
+
+	_, err = retry.Do(ctx, n.attempts, n.strategy, func() (struct{}, error) {
+		return struct{}{}, post(ctx, n.httpClient, n.url, header, body)
+	})
+	var permanent *retry.ErrFailedPermanently
+	if errors.As(err, &permanent) {
+		return fmt.Errorf("failed after %d attempts: %w", n.attempts, permanent.LastErr)
+	}
+	return err
+}
+
+// body renders the body of the alert, failing when the template doesn't produce valid JSON.
+func (n *WebhookNotifier) body(alert Alert) ([]byte, error) {
+	if n.template == nil {
+		return json.Marshal(alert)
+	}
+
+	var buf bytes.Buffer
+	if err := n.template.Execute(&buf, alert); err != nil {
+		return nil, fmt.Errorf("failed to render the template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("the template rendered invalid JSON: %s", buf.String())
+	}
+	return buf.Bytes(), nil
+}
+
+// Sign returns the HMAC-SHA256 signature of the body formatted as `sha256=<hex>`, for the receivers to authenticate the webhook.
+func Sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,110 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/retry"
+)
+
+func writeTemplate(t *testing.T, text string) string {
+	path := filepath.Join(t.TempDir(), "webhook.tmpl")
+	if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+		t.Fatalf("failed to write the template: %v", err)
+	}
+	return path
+}
+
+func TestWebhookBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string
+		ok       bool
+	}{
+		{"no template", "", `{"monitor":"global_events","rule":"Safe owner changed","priority":"P1","entity":"0x9BA6e03D8B90dE867373Db8cF1A58d2F7F006b3A","summary":"AddedOwner(address) emitted","labels":{"nickname":"mainnet","txHash":"0x01"},"resolved":false,"time":"2023-11-14T22:13:20Z"}`, true},
+		{"template", `{"text": {{ json .Title }}, "tx": {{ json .Labels.txHash }}, "missing": {{ json .Labels.missing }}}`, `{"text": "[P1] global_events: Safe owner changed", "tx": "0x01", "missing": ""}`, true},
+		{"invalid json", `{"text": {{ .Title }}}`, "", false},
+		{"unknown field", `{"text": {{ json .Unknown }}}`, "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n := NewWebhookNotifier("", nil, nil, "", 1, time.Second)
+			if len(test.template) > 0 {
+				tmpl, err := ParseWebhookTemplate(writeTemplate(t, test.template))
+				if err != nil {
+					t.Fatalf("Failed %s: unexpected error: %v", test.name, err)
+				}
+				n.template = tmpl
+			}
+
+			alert := testAlert()
+			alert.Time = alert.Time.UTC()
+			body, err := n.body(alert)
+			if (err == nil) != test.ok {
+				t.Fatalf("Failed %s: expected ok=%v but got %v", test.name, test.ok, err)
+			}
+			if err == nil && string(body) != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, body)
+			}
+		})
+	}
+}
+
+func TestParseWebhookHeaders(t *testing.T) {
+	header, err := ParseWebhookHeaders([]string{"Authorization=Bearer a=b", "X-Team=security"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if header.Get("Authorization") != "Bearer a=b" || header.Get("X-Team") != "security" {
+		t.Errorf("unexpected headers %v", header)
+	}
+	if _, err := ParseWebhookHeaders([]string{"=value"}); err == nil {
+		t.Errorf("expected an error without a name")
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	failures := 2
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		if !json.Valid(body) {
+			t.Errorf("invalid body %s", body)
+		}
+		if signature := r.Header.Get(WebhookSignatureHeader); signature != Sign([]byte("secret"), body) {
+			t.Errorf("unexpected signature %s", signature)
+		}
+		if team := r.Header.Get("X-Team"); team != "security" {
+			t.Errorf("unexpected header %s", team)
+		}
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	n := NewWebhookNotifier(srv.URL, nil, http.Header{"X-Team": []string{"security"}}, "secret", 3, time.Second)
+	n.strategy = retry.Fixed(0)
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("expected the alert to be delivered on the third attempt but got %d requests", requests)
+	}
+
+	failures = 3
+	if err := n.Notify(context.Background(), testAlert()); err == nil {
+		t.Errorf("expected an error once the attempts are exhausted")
+	}
+}
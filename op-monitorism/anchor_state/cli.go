@@ -0,0 +1,106 @@
+package anchor_state
+
+import (
+	"fmt"
+	"time"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+	L2NodeURLFlagName = "l2.node.url"
+
+	RollupNodeURLFlagName = "rollup.node.url"
+
+	AnchorStateRegistryAddressFlagName = "anchorstateregistry.address"
+	GameTypesFlagName                  = "game.types"
+	StallDurationFlagName              = "stall.duration"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+	L2NodeURL string
+
+	// RollupNodeURL is the trusted rollup node computing the expected output roots through `optimism_outputAtBlock`.
+	// The output roots are reconstructed from the L2 node when empty.
+	RollupNodeURL string
+
+	AnchorStateRegistryAddress common.Address
+	GameTypes                  []uint32
+
+	// StallDuration is the duration after which an anchor not advancing is reported as stalled.
+	StallDuration time.Duration
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:     ctx.String(L1NodeURLFlagName),
+		L2NodeURL:     ctx.String(L2NodeURLFlagName),
+		RollupNodeURL: ctx.String(RollupNodeURLFlagName),
+		StallDuration: ctx.Duration(StallDurationFlagName),
+	}
+
+	registryAddress := ctx.String(AnchorStateRegistryAddressFlagName)
+	if !common.IsHexAddress(registryAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", AnchorStateRegistryAddressFlagName)
+	}
+	cfg.AnchorStateRegistryAddress = common.HexToAddress(registryAddress)
+
+	for _, gameType := range ctx.UintSlice(GameTypesFlagName) {
+		cfg.GameTypes = append(cfg.GameTypes, uint32(gameType))
+	}
+	if len(cfg.GameTypes) == 0 {
+		return cfg, fmt.Errorf("--%s must have at least one game type", GameTypesFlagName)
+	}
+
+	if cfg.StallDuration <= 0 {
+		return cfg, fmt.Errorf("--%s must be positive", StallDurationFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    L2NodeURLFlagName,
+			Usage:   "Node URL of L2 peer",
+			Value:   "127.0.0.1:9545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    RollupNodeURLFlagName,
+			Usage:   "Node URL of a trusted rollup node computing the expected output roots with optimism_outputAtBlock. The output roots are reconstructed from the L2 peer when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "ROLLUP_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     AnchorStateRegistryAddressFlagName,
+			Usage:    "Address of the AnchorStateRegistry contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "ANCHOR_STATE_REGISTRY"),
+			Required: true,
+		},
+		&cli.UintSliceFlag{
+			Name:    GameTypesFlagName,
+			Usage:   "Game types whose anchor is tracked",
+			Value:   cli.NewUintSlice(0, 1),
+			EnvVars: opservice.PrefixEnvVar(envVar, "GAME_TYPES"),
+		},
+		&cli.DurationFlag{
+			Name:    StallDurationFlagName,
+			Usage:   "Duration after which an anchor not advancing is reported as stalled",
+			Value:   24 * time.Hour,
+			EnvVars: opservice.PrefixEnvVar(envVar, "STALL_DURATION"),
+		},
+	}
+}
@@ -0,0 +1,297 @@
+package anchor_state
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "anchor_state"
+	MetricsNamespace = "anchor_state_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the game type.
+	RootMismatchRule = "anchor root mismatch"
+	StalledRule      = "anchor not advancing"
+	NotAdvancedRule  = "anchor updated without advancing"
+
+	// AnchorStateRegistryABI is the subset of the AnchorStateRegistry used by the monitor.
+	AnchorStateRegistryABI = `[{"inputs":[{"name":"","type":"uint32"}],"name":"anchors","outputs":[{"name":"root","type":"bytes32"},{"name":"l2BlockNumber","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+)
+
+// anchor is the anchor of a game type along with its verification against the trusted node.
+type anchor struct {
+	root          common.Hash
+	l2BlockNumber uint64
+
+	// advancedAt is the time the anchor was observed advancing, the first observation when it didn't advance since.
+	advancedAt time.Time
+
+	// verified is true once the root was compared with the expected output root, l2BlockTime and mismatch are then set.
+	verified    bool
+	l2BlockTime uint64
+	mismatch    bool
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+	l2Client *ethclient.Client
+
+	// rollupClient is `nil` when the output roots are reconstructed from the L2 node.
+	rollupClient client.RPC
+
+	registry      *bind.BoundContract
+	gameTypes     []uint32
+	stallDuration time.Duration
+
+	anchors map[uint32]*anchor
+
+	// metrics
+	anchorL2BlockNumber *prometheus.GaugeVec
+	anchorAge           *prometheus.GaugeVec
+	sinceLastAdvance    *prometheus.GaugeVec
+	stalled             *prometheus.GaugeVec
+	rootMismatch        *prometheus.GaugeVec
+	anchorUpdates       *prometheus.CounterVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating anchor state monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	l2Client, err := ethclient.Dial(cfg.L2NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l2: %w", err)
+	}
+
+	var rollupClient client.RPC
+	if len(cfg.RollupNodeURL) > 0 {
+		rollupClient, err = client.NewRPC(ctx, log, cfg.RollupNodeURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial the rollup node: %w", err)
+		}
+		log.Info("computing the expected output roots from the rollup node")
+	}
+
+	registryABI, err := abi.JSON(strings.NewReader(AnchorStateRegistryABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the AnchorStateRegistry ABI: %w", err)
+	}
+	log.Info("configured anchor state registry", "address", cfg.AnchorStateRegistryAddress, "game_types", cfg.GameTypes, "stall_duration", cfg.StallDuration)
+
+	return &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+		l2Client: l2Client,
+
+		rollupClient: rollupClient,
+
+		registry:      bind.NewBoundContract(cfg.AnchorStateRegistryAddress, registryABI, l1Client, nil, nil),
+		gameTypes:     cfg.GameTypes,
+		stallDuration: cfg.StallDuration,
+
+		anchors: make(map[uint32]*anchor),
+
+		anchorL2BlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "anchorL2BlockNumber",
+			Help:      "l2 block number of the anchor of the game type",
+		}, []string{"gameType"}),
+		anchorAge: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "anchorAge",
+			Help:      "seconds since the timestamp of the l2 block of the anchor of the game type",
+		}, []string{"gameType"}),
+		sinceLastAdvance: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "sinceLastAdvance",
+			Help:      "seconds since the anchor of the game type was observed advancing",
+		}, []string{"gameType"}),
+		stalled: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "stalled",
+			Help:      "1 if the anchor of the game type didn't advance for the stall duration, 0 otherwise",
+		}, []string{"gameType"}),
+		rootMismatch: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "rootMismatch",
+			Help:      "1 if the anchor root of the game type doesn't match the output root of the trusted node, 0 otherwise",
+		}, []string{"gameType"}),
+		anchorUpdates: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "anchorUpdates",
+			Help:      "number of updates of the anchor of the game type by kind (advanced, rewound, replaced)",
+		}, []string{"gameType", "kind"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	now := time.Now()
+	for _, gameType := range m.gameTypes {
+		label := strconv.FormatUint(uint64(gameType), 10)
+
+		root, l2BlockNumber, err := m.readAnchor(ctx, gameType)
+		if err != nil {
+			m.log.Error("failed to query the anchor", "game_type", gameType, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("AnchorStateRegistry", "anchors").Inc()
+			continue
+		}
+		if root == (common.Hash{}) {
+			m.log.Warn("no anchor for the game type", "game_type", gameType)
+			continue
+		}
+
+		current, ok := m.anchors[gameType]
+		if !ok {
+			current = &anchor{root: root, l2BlockNumber: l2BlockNumber, advancedAt: now}
+			m.anchors[gameType] = current
+		} else if current.root != root || current.l2BlockNumber != l2BlockNumber {
+			kind := updateKind(current.l2BlockNumber, l2BlockNumber)
+			m.anchorUpdates.WithLabelValues(label, kind).Inc()
+			if kind == "advanced" {
+				m.log.Info("anchor advanced", "game_type", gameType, "previous_l2_block", current.l2BlockNumber, "l2_block", l2BlockNumber, "root", root)
+			} else {
+				m.log.Warn("anchor updated without advancing", "game_type", gameType, "kind", kind, "previous_l2_block", current.l2BlockNumber, "previous_root", current.root, "l2_block", l2BlockNumber, "root", root)
+				m.Emit(ctx, alert(NotAdvancedRule, "P1", label, fmt.Sprintf("anchor of game type %d %s from l2 block %d to %d, root %s", gameType, kind, current.l2BlockNumber, l2BlockNumber, root)))
+			}
+			*current = anchor{root: root, l2BlockNumber: l2BlockNumber, advancedAt: now}
+		}
+
+		// The root is verified once per anchor, the output of a block doesn't change.
+		if !current.verified {
+			expected, l2BlockTime, ok := m.expectedOutputRoot(ctx, new(big.Int).SetUint64(l2BlockNumber))
+			if ok {
+				current.verified, current.l2BlockTime = true, l2BlockTime
+				current.mismatch = common.Hash(expected) != root
+				if current.mismatch {
+					m.log.Error("anchor root doesn't match the output root of the trusted node!", "game_type", gameType, "l2_block", l2BlockNumber, "root", root, "expected_root", common.Hash(expected))
+				}
+			}
+		}
+
+		sinceLastAdvance := now.Sub(current.advancedAt)
+		stalled := sinceLastAdvance >= m.stallDuration
+		if stalled {
+			m.log.Warn("anchor not advancing", "game_type", gameType, "l2_block", l2BlockNumber, "since", sinceLastAdvance)
+		}
+
+		m.anchorL2BlockNumber.WithLabelValues(label).Set(float64(l2BlockNumber))
+		m.sinceLastAdvance.WithLabelValues(label).Set(sinceLastAdvance.Seconds())
+		m.stalled.WithLabelValues(label).Set(util.BoolToFloat(stalled))
+		m.Update(ctx, alert(StalledRule, "P1", label, fmt.Sprintf("anchor of game type %d not advancing from l2 block %d since %s", gameType, l2BlockNumber, sinceLastAdvance.Truncate(time.Second))), stalled)
+		if current.verified {
+			m.anchorAge.WithLabelValues(label).Set(float64(now.Unix() - int64(current.l2BlockTime)))
+			m.rootMismatch.WithLabelValues(label).Set(util.BoolToFloat(current.mismatch))
+			m.Update(ctx, alert(RootMismatchRule, "P0", label, fmt.Sprintf("anchor root %s of game type %d at l2 block %d doesn't match the output root of the trusted node", root, gameType, l2BlockNumber)), current.mismatch)
+		}
+	}
+}
+
+// alert returns the alert of the rule about the game type.
+func alert(rule string, priority string, gameType string, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   gameType,
+		Summary:  summary,
+		Labels:   map[string]string{"game_type": gameType},
+	}
+}
+
+// readAnchor returns the anchor root and its l2 block number for the game type, a zero root when not set.
+func (m *Monitor) readAnchor(ctx context.Context, gameType uint32) (common.Hash, uint64, error) {
+	var out []interface{}
+	if err := m.registry.Call(&bind.CallOpts{Context: ctx}, &out, "anchors", gameType); err != nil {
+		return common.Hash{}, 0, fmt.Errorf("failed to call `anchors`: %w", err)
+	}
+	root := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+	l2BlockNumber := *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	return root, l2BlockNumber.Uint64(), nil
+}
+
+// expectedOutputRoot returns the output root and the timestamp of the L2 block computed by the rollup node when configured,
+// reconstructed from the L2 node otherwise. The failures are logged and counted, `false` is returned to retry on the next tick.
+func (m *Monitor) expectedOutputRoot(ctx context.Context, l2BlockNumber *big.Int) (eth.Bytes32, uint64, bool) {
+	if m.rollupClient != nil {
+		var response eth.OutputResponse
+		if err := m.rollupClient.CallContext(ctx, &response, "optimism_outputAtBlock", hexutil.EncodeBig(l2BlockNumber)); err != nil {
+			m.log.Error("failed to query the output at block from the rollup node", "height", l2BlockNumber, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("rollup", "outputAtBlock").Inc()
+			return eth.Bytes32{}, 0, false
+		}
+		return response.OutputRoot, response.BlockRef.Time, true
+	}
+
+	block, err := m.l2Client.BlockByNumber(ctx, l2BlockNumber)
+	if err != nil {
+		m.log.Error("failed to query l2 block", "height", l2BlockNumber, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "blockByNumber").Inc()
+		return eth.Bytes32{}, 0, false
+	}
+	proof := struct{ StorageHash common.Hash }{}
+	if err := m.l2Client.Client().CallContext(ctx, &proof, "eth_getProof",
+		predeploys.L2ToL1MessagePasserAddr, nil, hexutil.EncodeBig(block.Number())); err != nil {
+		m.log.Error("failed to query for proof response of l2ToL1MP contract", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "getProof").Inc()
+		return eth.Bytes32{}, 0, false
+	}
+
+	outputRoot := eth.OutputRoot(&eth.OutputV0{StateRoot: eth.Bytes32(block.Root()), MessagePasserStorageRoot: eth.Bytes32(proof.StorageHash), BlockHash: block.Hash()})
+	return outputRoot, block.Time(), true
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	m.l2Client.Close()
+	if m.rollupClient != nil {
+		m.rollupClient.Close()
+	}
+	return nil
+}
+
+// updateKind returns the kind of an anchor update from its previous l2 block: advanced, rewound or replaced (same block, other root).
+func updateKind(previous uint64, next uint64) string {
+	switch {
+	case next > previous:
+		return "advanced"
+	case next < previous:
+		return "rewound"
+	default:
+		return "replaced"
+	}
+}
@@ -0,0 +1,154 @@
+package anchor_state
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestUpdateKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous uint64
+		next     uint64
+		expected string
+	}{
+		{name: "advanced", previous: 100, next: 200, expected: "advanced"},
+		{name: "rewound", previous: 200, next: 100, expected: "rewound"},
+		{name: "same block", previous: 100, next: 100, expected: "replaced"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := updateKind(test.previous, test.next); got != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+// anchorNode serves the anchors of the registry and the output roots of the rollup node.
+type anchorNode struct {
+	*fake.Node
+
+	root          common.Hash
+	l2BlockNumber uint64
+	outputRoot    common.Hash
+}
+
+func newAnchorNode(t *testing.T, registry common.Address) *anchorNode {
+	n := &anchorNode{Node: fake.NewNode(t)}
+	n.Contract(registry, util.MustParseJSONABI(AnchorStateRegistryABI)).Handle("anchors", func(_ []interface{}) ([]interface{}, error) {
+		return []interface{}{[32]byte(n.root), new(big.Int).SetUint64(n.l2BlockNumber)}, nil
+	})
+	n.Handle("optimism_outputAtBlock", func(_ []json.RawMessage) (interface{}, error) {
+		return eth.OutputResponse{OutputRoot: eth.Bytes32(n.outputRoot), BlockRef: eth.L2BlockRef{Time: uint64(time.Now().Unix()) - 60}}, nil
+	})
+	return n
+}
+
+// newTestMonitor returns the monitor of the game type 0 against the node, and the notifier of its alerts.
+func newTestMonitor(t *testing.T) (*Monitor, *anchorNode, *fake.Notifier) {
+	registry := common.HexToAddress("0x18DAc71c228D1C32c99489B7323d441E1175e443")
+	node := newAnchorNode(t, registry)
+	cfg := CLIConfig{L1NodeURL: node.URL, L2NodeURL: node.URL, RollupNodeURL: node.URL, AnchorStateRegistryAddress: registry, GameTypes: []uint32{0}, StallDuration: time.Hour}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, node, notifier
+}
+
+func TestRun(t *testing.T) {
+	m, node, _ := newTestMonitor(t)
+	ctx := context.Background()
+
+	node.root, node.l2BlockNumber, node.outputRoot = common.HexToHash("0x01"), 100, common.HexToHash("0x01")
+	m.Run(ctx)
+	if l2BlockNumber, mismatch := testutil.ToFloat64(m.anchorL2BlockNumber.WithLabelValues("0")), testutil.ToFloat64(m.rootMismatch.WithLabelValues("0")); l2BlockNumber != 100 || mismatch != 0 {
+		t.Errorf("expected the matching anchor at the l2 block 100 but got %v and %v", l2BlockNumber, mismatch)
+	}
+	if age := testutil.ToFloat64(m.anchorAge.WithLabelValues("0")); age < 60 {
+		t.Errorf("expected the anchor a minute old but got %v", age)
+	}
+
+	// an advanced anchor not matching the output root is counted and reported.
+	node.root, node.l2BlockNumber = common.HexToHash("0x02"), 200
+	m.Run(ctx)
+	if updates, mismatch := testutil.ToFloat64(m.anchorUpdates.WithLabelValues("0", "advanced")), testutil.ToFloat64(m.rootMismatch.WithLabelValues("0")); updates != 1 || mismatch != 1 {
+		t.Errorf("expected 1 advance to a mismatched root but got %v and %v", updates, mismatch)
+	}
+
+	// an anchor not advancing for the stall duration is reported stalled.
+	m.anchors[0].advancedAt = time.Now().Add(-2 * time.Hour)
+	m.Run(ctx)
+	if since, stalled := testutil.ToFloat64(m.sinceLastAdvance.WithLabelValues("0")), testutil.ToFloat64(m.stalled.WithLabelValues("0")); since < 7200 || stalled != 1 {
+		t.Errorf("expected the anchor stalled for 2 hours but got %v and %v", since, stalled)
+	}
+
+	// a rewound anchor is counted.
+	node.l2BlockNumber = 150
+	m.Run(ctx)
+	if updates := testutil.ToFloat64(m.anchorUpdates.WithLabelValues("0", "rewound")); updates != 1 {
+		t.Errorf("expected 1 rewound anchor but got %v", updates)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	m, node, notifier := newTestMonitor(t)
+	ctx := context.Background()
+
+	node.root, node.l2BlockNumber, node.outputRoot = common.HexToHash("0x01"), 100, common.HexToHash("0x01")
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a root not matching the output root fires once, and resolves once the anchor advances to a matching root.
+	node.root, node.l2BlockNumber = common.HexToHash("0x02"), 200
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(RootMismatchRule); len(alerts) != 1 || alerts[0].Entity != "0" || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the root mismatch alert once but got %v", alerts)
+	}
+	node.root, node.l2BlockNumber, node.outputRoot = common.HexToHash("0x03"), 300, common.HexToHash("0x03")
+	m.Run(ctx)
+	if firing := notifier.Firing(RootMismatchRule); len(firing) != 0 {
+		t.Fatalf("expected the root mismatch resolved but got %v", firing)
+	}
+
+	// an anchor not advancing for the stall duration fires, and resolves once it advances.
+	m.anchors[0].advancedAt = time.Now().Add(-2 * time.Hour)
+	m.Run(ctx)
+	if firing := notifier.Firing(StalledRule); len(firing) != 1 {
+		t.Fatalf("expected the stalled alert but got %v", firing)
+	}
+	node.root, node.l2BlockNumber, node.outputRoot = common.HexToHash("0x04"), 400, common.HexToHash("0x04")
+	m.Run(ctx)
+	if firing := notifier.Firing(StalledRule); len(firing) != 0 {
+		t.Fatalf("expected the stalled alert resolved but got %v", firing)
+	}
+
+	// a rewound anchor is reported on every update.
+	node.l2BlockNumber = 350
+	m.Run(ctx)
+	if alerts := notifier.Alerts(NotAdvancedRule); len(alerts) != 1 || alerts[0].Resolved {
+		t.Fatalf("expected the rewound anchor alert but got %v", alerts)
+	}
+}
@@ -0,0 +1,91 @@
+package archive
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodesFlagName        = "nodes"
+	SamplesFlagName      = "samples"
+	RecentBlocksFlagName = "recent.blocks"
+	FirstHeightFlagName  = "first.height"
+)
+
+// Node is a node expected to serve the archive traffic.
+type Node struct {
+	Name string
+	URL  string
+}
+
+type CLIConfig struct {
+	Nodes []Node
+
+	// Samples is the number of random heights probed per iteration, in addition to the first height.
+	Samples uint64
+
+	// RecentBlocks is the number of blocks behind the head kept by any node, the recent heights are not probed.
+	RecentBlocks uint64
+
+	// FirstHeight is the first height the nodes are expected to serve, e.g. the Bedrock height of a migrated chain.
+	FirstHeight uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		Samples:      ctx.Uint64(SamplesFlagName),
+		RecentBlocks: ctx.Uint64(RecentBlocksFlagName),
+		FirstHeight:  ctx.Uint64(FirstHeightFlagName),
+	}
+
+	names := make(map[string]bool)
+	for _, node := range ctx.StringSlice(NodesFlagName) {
+		name, url, err := util.ParseNamedValue(node, "name=url")
+		if err != nil {
+			return cfg, err
+		}
+		if names[name] {
+			return cfg, fmt.Errorf("duplicated node %s", name)
+		}
+		names[name] = true
+		cfg.Nodes = append(cfg.Nodes, Node{Name: name, URL: url})
+	}
+
+	if cfg.Samples == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", SamplesFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     NodesFlagName,
+			Usage:    "One or more nodes expected to serve the archive traffic formatted via `name=url`",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "NODES"),
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:    SamplesFlagName,
+			Usage:   "Number of random heights probed per iteration, in addition to the first height",
+			Value:   5,
+			EnvVars: opservice.PrefixEnvVar(envVar, "SAMPLES"),
+		},
+		&cli.Uint64Flag{
+			Name:    RecentBlocksFlagName,
+			Usage:   "Number of blocks behind the head kept by any node, the recent heights are not probed",
+			Value:   128,
+			EnvVars: opservice.PrefixEnvVar(envVar, "RECENT_BLOCKS"),
+		},
+		&cli.Uint64Flag{
+			Name:    FirstHeightFlagName,
+			Usage:   "First height the nodes are expected to serve, e.g. the Bedrock height of a migrated chain",
+			EnvVars: opservice.PrefixEnvVar(envVar, "FIRST_HEIGHT"),
+		},
+	}
+}
@@ -0,0 +1,237 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "archive"
+	MetricsNamespace = "archive_mon"
+
+	// MissingDataRule is the rule of the alert emitted while historical data is missing at the probed heights.
+	MissingDataRule = "historical data missing"
+)
+
+var (
+	// errNotFound is returned when the node answers without the data, a `null` result.
+	errNotFound = errors.New("not found")
+
+	// data are the kinds of historical data probed at each height.
+	data = []string{"block", "receipts", "state"}
+)
+
+// probedBlock is the subset of a block used to probe the receipts and the state at its height.
+type probedBlock struct {
+	Miner        common.Address `json:"miner"`
+	Transactions []common.Hash  `json:"transactions"`
+}
+
+type node struct {
+	Node
+	client *rpc.Client
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	nodes []*node
+	rng   *rand.Rand
+
+	samples      uint64
+	recentBlocks uint64
+	firstHeight  uint64
+
+	// metrics
+	probes               *prometheus.CounterVec
+	missingHeights       *prometheus.GaugeVec
+	highestMissingHeight *prometheus.GaugeVec
+	unexpectedRpcErrors  *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating archive monitor...")
+
+	nodes := make([]*node, len(cfg.Nodes))
+	for i, n := range cfg.Nodes {
+		// the dial is lazy for http, a node down at startup is reported by the probes.
+		client, err := rpc.DialContext(ctx, n.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial the node %s: %w", n.Name, err)
+		}
+		nodes[i] = &node{Node: n, client: client}
+		log.Info("configured node", "name", n.Name)
+	}
+
+	return &Monitor{
+		log: log,
+
+		nodes: nodes,
+		rng:   rand.New(rand.NewSource(rand.Int63())),
+
+		samples:      cfg.Samples,
+		recentBlocks: cfg.RecentBlocks,
+		firstHeight:  cfg.FirstHeight,
+
+		probes: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "probes",
+			Help:      "number of probes of the historical data (block, receipts, state) by result (available, missing)",
+		}, []string{"node", "data", "result"}),
+		missingHeights: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "missingHeights",
+			Help:      "number of heights probed during the last iteration where the data is missing",
+		}, []string{"node", "data"}),
+		highestMissingHeight: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestMissingHeight",
+			Help:      "highest height probed during the last iteration where the data is missing, 0 when none",
+		}, []string{"node", "data"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	for _, n := range m.nodes {
+		var latest hexutil.Uint64
+		if err := n.client.CallContext(ctx, &latest, "eth_blockNumber"); err != nil {
+			m.log.Error("failed to query latest block number", "node", n.Name, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues(n.Name, "eth_blockNumber").Inc()
+			continue
+		}
+
+		missing := make(map[string][]uint64)
+		for _, height := range sampleHeights(m.rng, m.firstHeight, uint64(latest), m.recentBlocks, m.samples) {
+			results, err := m.probeHeight(ctx, n, height)
+			if err != nil {
+				m.log.Error("failed to probe the height", "node", n.Name, "height", height, "err", err)
+				m.unexpectedRpcErrors.WithLabelValues(n.Name, "probe").Inc()
+				continue
+			}
+			for _, d := range data {
+				result, ok := results[d]
+				if !ok { // not probed, e.g. the receipts of a block without transaction.
+					continue
+				}
+				m.probes.WithLabelValues(n.Name, d, result).Inc()
+				if result == "missing" {
+					missing[d] = append(missing[d], height)
+				}
+			}
+		}
+
+		for _, d := range data {
+			heights := missing[d]
+			sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+			highest := uint64(0)
+			if len(heights) > 0 {
+				highest = heights[len(heights)-1]
+				m.log.Error("historical data missing", "node", n.Name, "data", d, "heights", heights)
+			}
+			m.missingHeights.WithLabelValues(n.Name, d).Set(float64(len(heights)))
+			m.highestMissingHeight.WithLabelValues(n.Name, d).Set(float64(highest))
+			m.Update(ctx, alerts.Alert{
+				Monitor:  MonitorName,
+				Rule:     MissingDataRule,
+				Priority: "P2",
+				Entity:   fmt.Sprintf("%s/%s", n.Name, d),
+				Summary:  fmt.Sprintf("%s missing on the node %s at %d of the probed heights, the highest being %d", d, n.Name, len(heights), highest),
+				Labels:   map[string]string{"node": n.Name, "data": d},
+			}, len(heights) > 0)
+		}
+	}
+}
+
+// probeHeight probes the block, the receipts of its first transaction and the balance of its miner at the height, and returns the
+// result (available, missing) by kind of data. A transport error is returned, the node being down rather than missing the data.
+func (m *Monitor) probeHeight(ctx context.Context, n *node, height uint64) (map[string]string, error) {
+	results := make(map[string]string)
+	tag := hexutil.EncodeUint64(height)
+
+	var block *probedBlock
+	err := n.client.CallContext(ctx, &block, "eth_getBlockByNumber", tag, false)
+	if err == nil && block == nil {
+		err = errNotFound
+	}
+	result, err := classify(err)
+	if err != nil {
+		return nil, err
+	}
+	results["block"] = result
+	if result == "missing" { // the receipts and the state can't be probed without the block.
+		return results, nil
+	}
+
+	if len(block.Transactions) > 0 {
+		var receipt map[string]interface{}
+		err := n.client.CallContext(ctx, &receipt, "eth_getTransactionReceipt", block.Transactions[0])
+		if err == nil && receipt == nil {
+			err = errNotFound
+		}
+		if results["receipts"], err = classify(err); err != nil {
+			return nil, err
+		}
+	}
+
+	var balance hexutil.Big
+	if results["state"], err = classify(n.client.CallContext(ctx, &balance, "eth_getBalance", block.Miner, tag)); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	for _, n := range m.nodes {
+		n.client.Close()
+	}
+	return nil
+}
+
+// classify returns `available` when the node answered with the data and `missing` when it answered without it, a JSON-RPC error
+// (e.g. `missing trie node`) or a `null` result. A transport error is returned as is.
+func classify(err error) (string, error) {
+	if err == nil {
+		return "available", nil
+	}
+	var rpcErr rpc.Error
+	if errors.Is(err, errNotFound) || errors.As(err, &rpcErr) {
+		return "missing", nil
+	}
+	return "", err
+}
+
+// sampleHeights returns the first height along with random heights between the first height and the recent blocks, in ascending order.
+func sampleHeights(rng *rand.Rand, first uint64, latest uint64, recentBlocks uint64, samples uint64) []uint64 {
+	if latest < recentBlocks || latest-recentBlocks < first {
+		return nil
+	}
+	last := latest - recentBlocks
+
+	heights := []uint64{first}
+	for i := uint64(0); i < samples; i++ {
+		heights = append(heights, first+uint64(rng.Int63n(int64(last-first+1))))
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights
+}
@@ -0,0 +1,169 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type testRPCError struct{}
+
+func (testRPCError) Error() string  { return "missing trie node" }
+func (testRPCError) ErrorCode() int { return -32000 }
+
+var _ rpc.Error = testRPCError{}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+		fails    bool
+	}{
+		{name: "available", err: nil, expected: "available"},
+		{name: "null result", err: errNotFound, expected: "missing"},
+		{name: "pruned state", err: fmt.Errorf("call: %w", testRPCError{}), expected: "missing"},
+		{name: "node down", err: errors.New("connection refused"), fails: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := classify(test.err)
+			if (err != nil) != test.fails || got != test.expected {
+				t.Errorf("Failed %s: expected %q (fails %t) but got %q (%v)", test.name, test.expected, test.fails, got, err)
+			}
+		})
+	}
+}
+
+func TestSampleHeights(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	heights := sampleHeights(rng, 100, 1000, 128, 20)
+	if len(heights) != 21 || heights[0] != 100 {
+		t.Fatalf("expected the first height and 20 samples but got %v", heights)
+	}
+	for i, height := range heights {
+		if height < 100 || height > 1000-128 {
+			t.Errorf("height %d out of the probed range", height)
+		}
+		if i > 0 && heights[i-1] > height {
+			t.Errorf("heights are not sorted: %v", heights)
+		}
+	}
+
+	if heights := sampleHeights(rng, 100, 200, 128, 5); heights != nil {
+		t.Errorf("expected no height before the recent blocks but got %v", heights)
+	}
+	if heights := sampleHeights(rng, 0, 128, 128, 1); len(heights) != 2 || heights[1] != 0 {
+		t.Errorf("expected the genesis only but got %v", heights)
+	}
+}
+
+// archiveNode serves 20 blocks, the state being pruned below the pruned height.
+type archiveNode struct {
+	*fake.Node
+	pruned uint64
+}
+
+func newArchiveNode(t *testing.T) *archiveNode {
+	n := &archiveNode{Node: fake.NewNode(t)}
+	for i := 0; i < 20; i++ {
+		n.AddBlock(&types.Header{Number: big.NewInt(int64(i))})
+	}
+	n.Handle("eth_getBalance", func(params []json.RawMessage) (interface{}, error) {
+		var height hexutil.Uint64
+		if err := fake.Param(params, 1, &height); err != nil {
+			return nil, err
+		}
+		if uint64(height) < n.pruned {
+			return nil, errors.New("missing trie node")
+		}
+		return (*hexutil.Big)(big.NewInt(1)), nil
+	})
+	return n
+}
+
+// newTestMonitor returns the monitor of the node, probing every height up to 15, and the notifier of its alerts.
+func newTestMonitor(t *testing.T) (*Monitor, *archiveNode, *fake.Notifier) {
+	node := newArchiveNode(t)
+	cfg := CLIConfig{Nodes: []Node{{Name: "archive", URL: node.URL}}, Samples: 50, RecentBlocks: 4}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, node, notifier
+}
+
+func TestRun(t *testing.T) {
+	m, node, _ := newTestMonitor(t)
+	ctx := context.Background()
+
+	// the first height and the 50 samples are probed.
+	m.Run(ctx)
+	if blocks, states := testutil.ToFloat64(m.probes.WithLabelValues("archive", "block", "available")), testutil.ToFloat64(m.probes.WithLabelValues("archive", "state", "available")); blocks != 51 || states != 51 {
+		t.Errorf("expected 51 blocks and states available but got %v and %v", blocks, states)
+	}
+	if missing := testutil.ToFloat64(m.missingHeights.WithLabelValues("archive", "state")); missing != 0 {
+		t.Errorf("expected no missing state but got %v", missing)
+	}
+
+	// the state missing at the first height is reported as missing heights.
+	node.pruned = 1
+	m.Run(ctx)
+	missing, probes := testutil.ToFloat64(m.missingHeights.WithLabelValues("archive", "state")), testutil.ToFloat64(m.probes.WithLabelValues("archive", "state", "missing"))
+	if missing == 0 || missing != probes {
+		t.Errorf("expected the missing heights probed missing but got %v and %v", missing, probes)
+	}
+	if highest, blocks := testutil.ToFloat64(m.highestMissingHeight.WithLabelValues("archive", "state")), testutil.ToFloat64(m.missingHeights.WithLabelValues("archive", "block")); highest != 0 || blocks != 0 {
+		t.Errorf("expected the state only missing at the height 0 but got %v and %v", highest, blocks)
+	}
+
+	// the blocks missing leave the state unprobed.
+	node.Fail("eth_getBlockByNumber", errors.New("header not found"))
+	m.Run(ctx)
+	if blocks, states := testutil.ToFloat64(m.missingHeights.WithLabelValues("archive", "block")), testutil.ToFloat64(m.missingHeights.WithLabelValues("archive", "state")); blocks != 51 || states != 0 {
+		t.Errorf("expected the 51 blocks missing without state probed but got %v and %v", blocks, states)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	m, node, notifier := newTestMonitor(t)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// the state missing at the first height fires once for the state only, and resolves once served.
+	node.pruned = 1
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(MissingDataRule); len(alerts) != 1 || alerts[0].Entity != "archive/state" || alerts[0].Labels["data"] != "state" {
+		t.Fatalf("expected the missing state alert once but got %v", alerts)
+	}
+	node.pruned = 0
+	m.Run(ctx)
+	if firing := notifier.Firing(MissingDataRule); len(firing) != 0 {
+		t.Fatalf("expected the missing state resolved but got %v", firing)
+	}
+}
@@ -12,8 +12,9 @@ import (
 )
 
 const (
-	NodeURLFlagName  = "node.url"
-	AccountsFlagName = "accounts"
+	NodeURLFlagName        = "node.url"
+	AccountsFlagName       = "accounts"
+	AccountsConfigFlagName = "accounts.config"
 )
 
 type CLIConfig struct {
@@ -24,8 +25,8 @@ type CLIConfig struct {
 func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
 	cfg := CLIConfig{NodeUrl: ctx.String(NodeURLFlagName)}
 	accounts := ctx.StringSlice(AccountsFlagName)
-	if len(accounts) == 0 {
-		return cfg, fmt.Errorf("--%s must have at least one account", AccountsFlagName)
+	if len(accounts) == 0 && !ctx.IsSet(AccountsConfigFlagName) {
+		return cfg, fmt.Errorf("--%s or --%s must have at least one account", AccountsFlagName, AccountsConfigFlagName)
 	}
 
 	for _, account := range accounts {
@@ -42,7 +43,18 @@ func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
 			return cfg, fmt.Errorf("nickname for %s not set", addr)
 		}
 
-		cfg.Accounts = append(cfg.Accounts, Account{common.HexToAddress(addr), nickname})
+		cfg.Accounts = append(cfg.Accounts, Account{Address: common.HexToAddress(addr), Nickname: nickname})
+	}
+
+	if path := ctx.String(AccountsConfigFlagName); len(path) > 0 {
+		configured, err := ReadAccountsConfigFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Accounts = append(cfg.Accounts, configured...)
+	}
+	if len(cfg.Accounts) == 0 {
+		return cfg, fmt.Errorf("no account configured")
 	}
 
 	return cfg, nil
@@ -57,10 +69,14 @@ func CLIFlags(envPrefix string) []cli.Flag {
 			EnvVars: opservice.PrefixEnvVar(envPrefix, "NODE_URL"),
 		},
 		&cli.StringSliceFlag{
-			Name:     AccountsFlagName,
-			Usage:    "One or accounts formatted via `address:nickname`",
-			EnvVars:  opservice.PrefixEnvVar(envPrefix, "ACCOUNTS"),
-			Required: true,
+			Name:    AccountsFlagName,
+			Usage:   "One or accounts formatted via `address:nickname`",
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "ACCOUNTS"),
+		},
+		&cli.StringFlag{
+			Name:    AccountsConfigFlagName,
+			Usage:   "YAML file listing the accounts along with their low-watermark thresholds and ERC-20 tokens",
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "ACCOUNTS_CONFIG"),
 		},
 	}
 }
@@ -0,0 +1,60 @@
+package balances
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// Token is an ERC-20 balance monitored for an account.
+type Token struct {
+	Address common.Address `yaml:"address"`
+	Symbol  string         `yaml:"symbol"`
+
+	// Threshold is the low-watermark of the balance in token units, 0 to disable.
+	Threshold float64 `yaml:"threshold"`
+}
+
+// AccountsConfiguration is the content of the YAML file given with `--accounts.config`.
+//
+//	accounts:
+//	  - address: 0x6887246668a3b87F54DeB3b94Ba47a6f63F32985
+//	    nickname: batcher
+//	    threshold: 10
+//	  - address: 0x473300df21D047806A082244b417f96b32f13A33
+//	    nickname: proposer
+//	    threshold: 5
+//	    tokens:
+//	      - address: 0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48
+//	        symbol: USDC
+//	        threshold: 1000
+type AccountsConfiguration struct {
+	Accounts []Account `yaml:"accounts"`
+}
+
+// ReadAccountsConfigFile reads the list of accounts to monitor from a YAML file.
+func ReadAccountsConfigFile(path string) ([]Account, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the accounts config file %s: %w", path, err)
+	}
+
+	var config AccountsConfiguration
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse the accounts config file %s: %w", path, err)
+	}
+
+	for _, account := range config.Accounts {
+		if len(account.Nickname) == 0 {
+			return nil, fmt.Errorf("nickname for %s not set", account.Address)
+		}
+		for _, token := range account.Tokens {
+			if len(token.Symbol) == 0 {
+				return nil, fmt.Errorf("symbol of the token %s of %s not set", token.Address, account.Nickname)
+			}
+		}
+	}
+	return config.Accounts, nil
+}
@@ -0,0 +1,80 @@
+package balances
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const accountsConfigData = `
+accounts:
+  - address: 0x6887246668a3b87F54DeB3b94Ba47a6f63F32985
+    nickname: batcher
+    threshold: 10
+  - address: 0x473300df21D047806A082244b417f96b32f13A33
+    nickname: faucet
+    tokens:
+      - address: 0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48
+        symbol: USDC
+        threshold: 1000.5
+`
+
+func TestReadAccountsConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.yaml")
+	if err := os.WriteFile(path, []byte(accountsConfigData), 0644); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	accounts, err := ReadAccountsConfigFile(path)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(accounts) != 2 {
+		t.Fatalf("expected 2 accounts but got %d", len(accounts))
+	}
+	if accounts[0].Address != common.HexToAddress("0x6887246668a3b87F54DeB3b94Ba47a6f63F32985") || accounts[0].Nickname != "batcher" || accounts[0].Threshold != 10 {
+		t.Errorf("unexpected account %v", accounts[0])
+	}
+	if accounts[1].Threshold != 0 || len(accounts[1].Tokens) != 1 {
+		t.Fatalf("unexpected account %v", accounts[1])
+	}
+	if token := accounts[1].Tokens[0]; token.Symbol != "USDC" || token.Threshold != 1000.5 {
+		t.Errorf("unexpected token %v", token)
+	}
+}
+
+func TestReadAccountsConfigFileMissingNickname(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.yaml")
+	if err := os.WriteFile(path, []byte("accounts:\n  - address: 0x6887246668a3b87F54DeB3b94Ba47a6f63F32985\n"), 0644); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if _, err := ReadAccountsConfigFile(path); err == nil {
+		t.Errorf("expected an error for an account without nickname")
+	}
+}
+
+func TestIsBelowThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		balance   float64
+		threshold float64
+		expected  bool
+	}{
+		{name: "No threshold", balance: 0, threshold: 0, expected: false},
+		{name: "Above the threshold", balance: 11, threshold: 10, expected: false},
+		{name: "Equal to the threshold", balance: 10, threshold: 10, expected: false},
+		{name: "Below the threshold", balance: 9.5, threshold: 10, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isBelowThreshold(test.balance, test.threshold)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, output)
+			}
+		})
+	}
+}
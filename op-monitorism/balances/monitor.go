@@ -2,37 +2,59 @@ package balances
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
+	MonitorName      = "balances"
 	MetricsNamespace = "balance_mon"
+
+	// BelowThresholdRule is the rule of the alert emitted while a balance is below its threshold.
+	BelowThresholdRule = "balance below threshold"
+
+	// EtherAsset is the `asset` label of the ETH balances.
+	EtherAsset = "ETH"
 )
 
 type Account struct {
-	Address  common.Address
-	Nickname string
+	Address  common.Address `yaml:"address"`
+	Nickname string         `yaml:"nickname"`
+
+	// Threshold is the low-watermark of the ETH balance, 0 to disable.
+	Threshold float64 `yaml:"threshold"`
+	Tokens    []Token `yaml:"tokens"`
 }
 
 type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
 	log log.Logger
 
 	rpc      client.RPC
+	erc20    *abi.ABI
 	accounts []Account
+	decimals map[common.Address]uint8
 
 	// metrics
 	balances            *prometheus.GaugeVec
+	tokenBalances       *prometheus.GaugeVec
+	belowThreshold      *prometheus.GaugeVec
 	unexpectedRpcErrors *prometheus.CounterVec
 }
 
@@ -43,13 +65,19 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 		return nil, err
 	}
 
+	erc20, err := bindings.ERC20MetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the ERC20 ABI: %w", err)
+	}
+
 	for _, account := range cfg.Accounts {
-		log.Info("configured account", "address", account.Address, "nickname", account.Nickname)
+		log.Info("configured account", "address", account.Address, "nickname", account.Nickname, "threshold", account.Threshold, "tokens", len(account.Tokens))
 	}
 
-	return &Monitor{
+	monitor := &Monitor{
 		log:      log,
 		rpc:      rpc,
+		erc20:    erc20,
 		accounts: cfg.Accounts,
 
 		balances: m.NewGaugeVec(prometheus.GaugeOpts{
@@ -57,22 +85,103 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 			Name:      "balances",
 			Help:      "balances held by accounts registered with the monitor",
 		}, []string{"address", "nickname"}),
+		tokenBalances: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "tokenBalances",
+			Help:      "ERC-20 balances held by accounts registered with the monitor, in token units",
+		}, []string{"address", "nickname", "token", "symbol"}),
+		belowThreshold: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "belowThreshold",
+			Help:      "1 if the balance of the asset is below the low-watermark threshold of the account, 0 otherwise",
+		}, []string{"address", "nickname", "asset"}),
 		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
 			Namespace: MetricsNamespace,
 			Name:      "unexpectedRpcErrors",
 			Help:      "number of unexpcted rpc errors",
 		}, []string{"section", "name"}),
+	}
+
+	if err := monitor.readDecimals(ctx); err != nil {
+		rpc.Close()
+		return nil, err
+	}
+	return monitor, nil
+}
+
+// readDecimals reads once the decimals of every configured token.
+func (m *Monitor) readDecimals(ctx context.Context) error {
+	m.decimals = make(map[common.Address]uint8)
+	var tokens []common.Address
+	for _, account := range m.accounts {
+		for _, token := range account.Tokens {
+			if _, ok := m.decimals[token.Address]; !ok {
+				m.decimals[token.Address] = 0
+				tokens = append(tokens, token.Address)
+			}
+		}
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	batchElems := make([]rpc.BatchElem, len(tokens))
+	for i, token := range tokens {
+		elem, err := m.callElem(token, "decimals")
+		if err != nil {
+			return err
+		}
+		batchElems[i] = elem
+	}
+	if err := m.rpc.BatchCallContext(ctx, batchElems); err != nil {
+		return fmt.Errorf("failed decimals batch request: %w", err)
+	}
+
+	for i, token := range tokens {
+		if batchElems[i].Error != nil {
+			return fmt.Errorf("failed to query the decimals of %s: %w", token, batchElems[i].Error)
+		}
+		out, err := m.erc20.Unpack("decimals", *batchElems[i].Result.(*hexutil.Bytes))
+		if err != nil {
+			return fmt.Errorf("failed to unpack the decimals of %s: %w", token, err)
+		}
+		m.decimals[token] = *abi.ConvertType(out[0], new(uint8)).(*uint8)
+		m.log.Info("configured token", "token", token, "decimals", m.decimals[token])
+	}
+	return nil
+}
+
+// callElem returns the `eth_call` batch element of a method of an ERC-20 token at the latest block.
+func (m *Monitor) callElem(token common.Address, method string, args ...interface{}) (rpc.BatchElem, error) {
+	data, err := m.erc20.Pack(method, args...)
+	if err != nil {
+		return rpc.BatchElem{}, fmt.Errorf("failed to pack `%s`: %w", method, err)
+	}
+	return rpc.BatchElem{
+		Method: "eth_call",
+		Args:   []interface{}{map[string]interface{}{"to": token, "data": hexutil.Bytes(data)}, "latest"},
+		Result: new(hexutil.Bytes),
 	}, nil
 }
 
 func (m *Monitor) Run(ctx context.Context) {
 	m.log.Info("querying balances...")
-	batchElems := make([]rpc.BatchElem, len(m.accounts))
+	batchElems := make([]rpc.BatchElem, 0, len(m.accounts))
 	for i := 0; i < len(m.accounts); i++ {
-		batchElems[i] = rpc.BatchElem{
+		batchElems = append(batchElems, rpc.BatchElem{
 			Method: "eth_getBalance",
 			Args:   []interface{}{m.accounts[i].Address, "latest"},
 			Result: new(hexutil.Big),
+		})
+	}
+	for _, account := range m.accounts {
+		for _, token := range account.Tokens {
+			elem, err := m.callElem(token.Address, "balanceOf", account.Address)
+			if err != nil {
+				m.log.Error("failed to create the balanceOf request", "err", err)
+				return
+			}
+			batchElems = append(batchElems, elem)
 		}
 	}
 	if err := m.rpc.BatchCallContext(ctx, batchElems); err != nil {
@@ -89,10 +198,56 @@ func (m *Monitor) Run(ctx context.Context) {
 			continue
 		}
 
-		ethBalance := weiToEther((batchElems[i].Result).(*hexutil.Big).ToInt())
+		ethBalance := util.WeiToEther((batchElems[i].Result).(*hexutil.Big).ToInt())
 		m.balances.WithLabelValues(account.Address.String(), account.Nickname).Set(ethBalance)
+		m.setBelowThreshold(ctx, account, EtherAsset, ethBalance, account.Threshold)
 		m.log.Info("set balance", "address", account.Address, "nickname", account.Nickname, "balance", ethBalance)
 	}
+
+	i := len(m.accounts)
+	for _, account := range m.accounts {
+		for _, token := range account.Tokens {
+			elem := batchElems[i]
+			i++
+			if elem.Error != nil {
+				m.log.Error("failed to query token balance", "address", account.Address, "nickname", account.Nickname, "token", token.Symbol, "err", elem.Error)
+				m.unexpectedRpcErrors.WithLabelValues("balances", "balanceOf").Inc()
+				continue
+			}
+			out, err := m.erc20.Unpack("balanceOf", *elem.Result.(*hexutil.Bytes))
+			if err != nil {
+				m.log.Error("failed to unpack token balance", "address", account.Address, "nickname", account.Nickname, "token", token.Symbol, "err", err)
+				m.unexpectedRpcErrors.WithLabelValues("balances", "balanceOf").Inc()
+				continue
+			}
+
+			tokenBalance := util.ToUnits(*abi.ConvertType(out[0], new(*big.Int)).(**big.Int), m.decimals[token.Address])
+			m.tokenBalances.WithLabelValues(account.Address.String(), account.Nickname, token.Address.String(), token.Symbol).Set(tokenBalance)
+			m.setBelowThreshold(ctx, account, token.Symbol, tokenBalance, token.Threshold)
+			m.log.Info("set token balance", "address", account.Address, "nickname", account.Nickname, "token", token.Symbol, "balance", tokenBalance)
+		}
+	}
+}
+
+// setBelowThreshold sets the belowThreshold gauge and the alert of an asset, nothing is exported when the asset has no threshold.
+func (m *Monitor) setBelowThreshold(ctx context.Context, account Account, asset string, balance float64, threshold float64) {
+	if threshold <= 0 {
+		return
+	}
+
+	below := isBelowThreshold(balance, threshold)
+	if below {
+		m.log.Warn("balance below threshold", "address", account.Address, "nickname", account.Nickname, "asset", asset, "balance", balance, "threshold", threshold)
+	}
+	m.belowThreshold.WithLabelValues(account.Address.String(), account.Nickname, asset).Set(util.BoolToFloat(below))
+	m.Update(ctx, alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     BelowThresholdRule,
+		Priority: "P1",
+		Entity:   fmt.Sprintf("%s/%s", account.Address, asset),
+		Summary:  fmt.Sprintf("%s balance of %s is %g, below the threshold of %g", asset, account.Nickname, balance, threshold),
+		Labels:   map[string]string{"nickname": account.Nickname, "asset": asset},
+	}, below)
 }
 
 func (m *Monitor) Close(_ context.Context) error {
@@ -100,10 +255,7 @@ func (m *Monitor) Close(_ context.Context) error {
 	return nil
 }
 
-func weiToEther(wei *big.Int) float64 {
-	num := new(big.Rat).SetInt(wei)
-	denom := big.NewRat(params.Ether, 1)
-	num = num.Quo(num, denom)
-	f, _ := num.Float64()
-	return f
+// isBelowThreshold returns true when a threshold is set and the balance is strictly below it.
+func isBelowThreshold(balance float64, threshold float64) bool {
+	return threshold > 0 && balance < threshold
 }
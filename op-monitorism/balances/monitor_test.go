@@ -0,0 +1,73 @@
+package balances
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	batcher = common.HexToAddress("0x6887246668a3b87F54DeB3b94Ba47a6f63F32985")
+	usdc    = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+)
+
+// newTestMonitor returns the monitor of the batcher with a threshold of 10 ETH and of 1000 USDC, the token being served by
+// the returned contract, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *fake.Node) (*Monitor, *fake.Contract, *fake.Notifier) {
+	erc20, err := bindings.ERC20MetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	token := node.Contract(usdc, erc20).Returns("decimals", uint8(6)).Returns("balanceOf", big.NewInt(5000e6))
+	cfg := CLIConfig{NodeUrl: node.URL, Accounts: []Account{{Address: batcher, Nickname: "batcher", Threshold: 10, Tokens: []Token{{Address: usdc, Symbol: "USDC", Threshold: 1000}}}}}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, token, notifier
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := fake.NewNode(t)
+	node.Result("eth_getBalance", (*hexutil.Big)(new(big.Int).Mul(big.NewInt(20), big.NewInt(1e18))))
+	m, token, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// an ETH balance below its threshold fires once, resolved once refilled.
+	node.Result("eth_getBalance", (*hexutil.Big)(big.NewInt(5e18)))
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(BelowThresholdRule); len(alerts) != 1 || alerts[0].Entity != batcher.String()+"/"+EtherAsset || alerts[0].Priority != "P1" || alerts[0].Labels["nickname"] != "batcher" {
+		t.Fatalf("expected the below threshold alert once but got %v", alerts)
+	}
+	node.Result("eth_getBalance", (*hexutil.Big)(new(big.Int).Mul(big.NewInt(20), big.NewInt(1e18))))
+	m.Run(ctx)
+	if firing := notifier.Firing(BelowThresholdRule); len(firing) != 0 {
+		t.Fatalf("expected the below threshold alert resolved but got %v", firing)
+	}
+
+	// a token balance below its threshold fires for the token.
+	token.Returns("balanceOf", big.NewInt(500e6))
+	m.Run(ctx)
+	if firing := notifier.Firing(BelowThresholdRule); len(firing) != 1 || firing[0] != batcher.String()+"/USDC" {
+		t.Fatalf("expected the below threshold alert of the token but got %v", firing)
+	}
+}
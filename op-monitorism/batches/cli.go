@@ -0,0 +1,122 @@
+package batches
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName   = "l1.node.url"
+	L1BeaconURLFlagName = "l1.beacon.url"
+
+	BatchInboxAddressFlagName = "batchinbox.address"
+	BatcherAddressFlagName    = "batcher.address"
+	StartBlockHeightFlagName  = "start.block.height"
+	BlockRangeFlagName        = "block.range"
+	ChannelTimeoutFlagName    = "channel.timeout"
+	ChannelMarginFlagName     = "channel.timeout.margin"
+)
+
+type CLIConfig struct {
+	L1NodeURL   string
+	L1BeaconURL string
+
+	BatchInboxAddress common.Address
+	BatcherAddress    common.Address
+	StartBlockHeight  uint64
+	BlockRange        uint64
+
+	// ChannelTimeout is the number of L1 blocks after which an incomplete channel is dropped by the derivation.
+	ChannelTimeout uint64
+
+	// ChannelMargin is the number of L1 blocks before the channel timeout from which an open channel is reported as nearing it.
+	ChannelMargin uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:        ctx.String(L1NodeURLFlagName),
+		L1BeaconURL:      ctx.String(L1BeaconURLFlagName),
+		StartBlockHeight: ctx.Uint64(StartBlockHeightFlagName),
+		BlockRange:       ctx.Uint64(BlockRangeFlagName),
+		ChannelTimeout:   ctx.Uint64(ChannelTimeoutFlagName),
+		ChannelMargin:    ctx.Uint64(ChannelMarginFlagName),
+	}
+
+	batchInboxAddress := ctx.String(BatchInboxAddressFlagName)
+	if !common.IsHexAddress(batchInboxAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", BatchInboxAddressFlagName)
+	}
+	cfg.BatchInboxAddress = common.HexToAddress(batchInboxAddress)
+
+	batcherAddress := ctx.String(BatcherAddressFlagName)
+	if !common.IsHexAddress(batcherAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", BatcherAddressFlagName)
+	}
+	cfg.BatcherAddress = common.HexToAddress(batcherAddress)
+
+	if cfg.BlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", BlockRangeFlagName)
+	}
+	if cfg.ChannelMargin >= cfg.ChannelTimeout {
+		return cfg, fmt.Errorf("--%s must be lower than --%s", ChannelMarginFlagName, ChannelTimeoutFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    L1BeaconURLFlagName,
+			Usage:   "URL of the L1 beacon node API to fetch the blobs, the blob transactions are skipped when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_BEACON_URL"),
+		},
+		&cli.StringFlag{
+			Name:     BatchInboxAddressFlagName,
+			Usage:    "Address of the batch inbox",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "BATCH_INBOX"),
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     BatcherAddressFlagName,
+			Usage:    "Address of the batcher",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "BATCHER"),
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:    StartBlockHeightFlagName,
+			Usage:   "Starting height to scan for batcher transactions, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    BlockRangeFlagName,
+			Usage:   "Max number of blocks processed per iteration",
+			Value:   10,
+			EnvVars: opservice.PrefixEnvVar(envVar, "BLOCK_RANGE"),
+		},
+		&cli.Uint64Flag{
+			Name:    ChannelTimeoutFlagName,
+			Usage:   "Number of L1 blocks after which an incomplete channel is dropped, the channel_timeout of the rollup config",
+			Value:   300,
+			EnvVars: opservice.PrefixEnvVar(envVar, "CHANNEL_TIMEOUT"),
+		},
+		&cli.Uint64Flag{
+			Name:    ChannelMarginFlagName,
+			Usage:   "Number of L1 blocks before the channel timeout from which an open channel is reported as nearing it",
+			Value:   50,
+			EnvVars: opservice.PrefixEnvVar(envVar, "CHANNEL_TIMEOUT_MARGIN"),
+		},
+	}
+}
@@ -0,0 +1,430 @@
+package batches
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/beacon"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "batches"
+	MetricsNamespace = "batches_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the channel.
+	InvalidChannelRule = "undecodable channel"
+	TimedOutRule       = "channel timed out"
+	NearingTimeoutRule = "channel nearing the timeout"
+)
+
+// openChannel is a channel with frames received and not yet complete.
+type openChannel struct {
+	channel   *derive.Channel
+	openBlock uint64
+
+	// startedBefore is true when the first frames of the channel were submitted before the starting height.
+	startedBefore bool
+
+	// nearingTimeout is true once the channel is reported as nearing the channel timeout.
+	nearingTimeout bool
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+	beacon   *beacon.Client // nil when the blob transactions are skipped.
+	signer   types.Signer
+
+	batchInboxAddress common.Address
+	batcherAddress    common.Address
+
+	startingL1Height uint64
+	nextL1Height     uint64
+	blockRange       uint64
+	channelTimeout   uint64
+	channelMargin    uint64
+
+	channels map[derive.ChannelID]*openChannel
+
+	// metrics
+	highestBlockNumber  *prometheus.GaugeVec
+	batcherTxs          *prometheus.CounterVec
+	skippedTxs          *prometheus.CounterVec
+	frames              prometheus.Counter
+	invalidFrames       prometheus.Counter
+	channelsRead        prometheus.Counter
+	invalidChannels     prometheus.Counter
+	timedOutChannels    prometheus.Counter
+	openChannels        prometheus.Gauge
+	channelsNearTimeout prometheus.Gauge
+	minBlocksToTimeout  prometheus.Gauge
+	batches             *prometheus.CounterVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating batches monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	chainID, err := l1Client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the chain id: %w", err)
+	}
+
+	var beaconClient *beacon.Client
+	if len(cfg.L1BeaconURL) > 0 {
+		beaconClient = beacon.NewClient(cfg.L1BeaconURL)
+		if err := beaconClient.Init(ctx); err != nil {
+			return nil, fmt.Errorf("failed to initialize the beacon client: %w", err)
+		}
+	} else {
+		log.Warn("no beacon node configured, the blob transactions are skipped")
+	}
+
+	startingL1Height := cfg.StartBlockHeight
+	if startingL1Height == 0 {
+		if startingL1Height, err = l1Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+	log.Info("configured starting height", "height", startingL1Height, "batch_inbox", cfg.BatchInboxAddress, "batcher", cfg.BatcherAddress)
+
+	return &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+		beacon:   beaconClient,
+		signer:   types.LatestSignerForChainID(chainID),
+
+		batchInboxAddress: cfg.BatchInboxAddress,
+		batcherAddress:    cfg.BatcherAddress,
+
+		startingL1Height: startingL1Height,
+		nextL1Height:     startingL1Height,
+		blockRange:       cfg.BlockRange,
+		channelTimeout:   cfg.ChannelTimeout,
+		channelMargin:    cfg.ChannelMargin,
+
+		channels: make(map[derive.ChannelID]*openChannel),
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed l1 heights (checked and known)",
+		}, []string{"type"}),
+		batcherTxs: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "batcherTxs",
+			Help:      "number of batcher transactions by type (calldata, blob)",
+		}, []string{"type"}),
+		skippedTxs: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "skippedTxs",
+			Help:      "number of batcher transactions whose data could not be fetched",
+		}, []string{"reason"}),
+		frames: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "frames",
+			Help:      "number of valid frames",
+		}),
+		invalidFrames: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "invalidFrames",
+			Help:      "number of batcher transactions with undecodable frames, and of frames rejected by their channel",
+		}),
+		channelsRead: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "channelsRead",
+			Help:      "number of complete channels read",
+		}),
+		invalidChannels: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "invalidChannels",
+			Help:      "number of complete channels with undecodable batches",
+		}),
+		timedOutChannels: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "timedOutChannels",
+			Help:      "number of channels not complete before the channel timeout",
+		}),
+		openChannels: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "openChannels",
+			Help:      "number of channels with frames received and not yet complete",
+		}),
+		channelsNearTimeout: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "channelsNearTimeout",
+			Help:      "number of open channels within the margin of the channel timeout",
+		}),
+		minBlocksToTimeout: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "minBlocksToTimeout",
+			Help:      "lowest number of L1 blocks left before an open channel times out, the channel timeout when none is open",
+		}),
+		batches: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "batches",
+			Help:      "number of batches decoded by type (singular, span)",
+		}, []string{"type"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestL1Height))
+
+	toBlock := min(latestL1Height, m.nextL1Height+m.blockRange-1)
+	for ; m.nextL1Height <= toBlock; m.nextL1Height++ {
+		if err := m.processBlock(ctx, m.nextL1Height); err != nil {
+			m.log.Error("failed to process the block", "block", m.nextL1Height, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l1", "processBlock").Inc()
+			return
+		}
+		m.highestBlockNumber.WithLabelValues("checked").Set(float64(m.nextL1Height))
+	}
+}
+
+// processBlock decodes the frames of the batcher transactions of the L1 block, and reads the channels they complete.
+func (m *Monitor) processBlock(ctx context.Context, number uint64) error {
+	block, err := m.l1Client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return fmt.Errorf("failed to query the block: %w", err)
+	}
+	ref := eth.L1BlockRef{Hash: block.Hash(), Number: block.NumberU64(), ParentHash: block.ParentHash(), Time: block.Time()}
+
+	var sidecars map[common.Hash]*eth.APIBlobSidecar
+	for _, tx := range block.Transactions() {
+		if tx.To() == nil || *tx.To() != m.batchInboxAddress {
+			continue
+		}
+		sender, err := types.Sender(m.signer, tx)
+		if err != nil || sender != m.batcherAddress {
+			continue
+		}
+
+		if tx.Type() != types.BlobTxType {
+			m.batcherTxs.WithLabelValues("calldata").Inc()
+			m.processData(ctx, tx.Hash(), tx.Data(), ref)
+			continue
+		}
+
+		m.batcherTxs.WithLabelValues("blob").Inc()
+		if m.beacon == nil {
+			m.skippedTxs.WithLabelValues("noBeacon").Inc()
+			continue
+		}
+		if sidecars == nil {
+			if sidecars, err = m.beacon.Sidecars(ctx, m.beacon.Slot(block.Time())); err != nil {
+				return fmt.Errorf("failed to fetch the blobs: %w", err)
+			}
+		}
+		for _, blobHash := range tx.BlobHashes() {
+			sidecar, ok := sidecars[blobHash]
+			if !ok {
+				m.log.Error("blob not found", "tx", tx.Hash(), "blob_hash", blobHash, "block", number)
+				m.skippedTxs.WithLabelValues("blobNotFound").Inc()
+				continue
+			}
+			data, err := sidecar.Blob.ToData()
+			if err != nil {
+				m.log.Error("undecodable blob", "tx", tx.Hash(), "blob_hash", blobHash, "err", err)
+				m.invalidFrames.Inc()
+				continue
+			}
+			m.processData(ctx, tx.Hash(), data, ref)
+		}
+	}
+
+	m.expireChannels(ctx, number)
+	m.checkOpenChannels(ctx, number)
+	return nil
+}
+
+// processData parses the frames of the batcher data and adds them to their channels.
+func (m *Monitor) processData(ctx context.Context, txHash common.Hash, data []byte, ref eth.L1BlockRef) {
+	frames, err := derive.ParseFrames(data)
+	if err != nil {
+		m.log.Error("undecodable frames", "tx", txHash, "block", ref.Number, "err", err)
+		m.invalidFrames.Inc()
+		return
+	}
+
+	for _, frame := range frames {
+		open, ok := m.channels[frame.ID]
+		if !ok {
+			open = &openChannel{
+				channel:       derive.NewChannel(frame.ID, ref),
+				openBlock:     ref.Number,
+				startedBefore: frame.FrameNumber != 0 && ref.Number < m.startingL1Height+m.channelTimeout,
+			}
+			m.channels[frame.ID] = open
+		}
+		if err := open.channel.AddFrame(frame, ref); err != nil {
+			m.log.Error("frame rejected by its channel", "tx", txHash, "channel", frame.ID, "frame", frame.FrameNumber, "err", err)
+			m.invalidFrames.Inc()
+			continue
+		}
+		m.frames.Inc()
+
+		if open.channel.IsReady() {
+			m.readChannel(ctx, frame.ID, open)
+			delete(m.channels, frame.ID)
+		}
+	}
+	m.openChannels.Set(float64(len(m.channels)))
+}
+
+// readChannel decodes the batches of a complete channel.
+func (m *Monitor) readChannel(ctx context.Context, id derive.ChannelID, open *openChannel) {
+	m.channelsRead.Inc()
+	counts, err := readBatches(open.channel.Reader())
+	for batchType, count := range counts {
+		m.batches.WithLabelValues(batchType).Add(float64(count))
+	}
+	if err != nil {
+		m.log.Error("undecodable batches in the channel", "channel", id, "open_block", open.openBlock, "batches", counts, "err", err)
+		m.invalidChannels.Inc()
+		m.Emit(ctx, m.alert(InvalidChannelRule, "P1", id, fmt.Sprintf("channel %s opened at l1 block %d has undecodable batches: %v", id, open.openBlock, err)))
+		return
+	}
+	m.log.Info("channel read", "channel", id, "open_block", open.openBlock, "batches", counts)
+}
+
+// expireChannels drops the channels not complete before the channel timeout, as the derivation does.
+func (m *Monitor) expireChannels(ctx context.Context, l1Height uint64) {
+	for id, open := range m.channels {
+		if !isTimedOut(open.openBlock, l1Height, m.channelTimeout) {
+			continue
+		}
+		if open.startedBefore {
+			m.log.Info("channel started before the starting height dropped", "channel", id, "open_block", open.openBlock)
+		} else {
+			m.log.Error("channel timed out", "channel", id, "open_block", open.openBlock, "l1_height", l1Height)
+			m.timedOutChannels.Inc()
+			m.Emit(ctx, m.alert(TimedOutRule, "P1", id, fmt.Sprintf("channel %s opened at l1 block %d timed out at l1 block %d", id, open.openBlock, l1Height)))
+		}
+		delete(m.channels, id)
+	}
+	m.openChannels.Set(float64(len(m.channels)))
+}
+
+// checkOpenChannels reports the open channels nearing the channel timeout, which would be dropped and resubmitted by the batcher
+// if not completed in time. The channels started before the starting height are skipped as their first frames are unknown.
+func (m *Monitor) checkOpenChannels(ctx context.Context, l1Height uint64) {
+	nearing, minBlocks := 0, m.channelTimeout
+	for id, open := range m.channels {
+		if open.startedBefore {
+			continue
+		}
+		blocks := blocksToTimeout(open.openBlock, l1Height, m.channelTimeout)
+		minBlocks = min(minBlocks, blocks)
+		if blocks > m.channelMargin {
+			continue
+		}
+		nearing++
+		if !open.nearingTimeout {
+			m.log.Warn("channel nearing the timeout", "channel", id, "open_block", open.openBlock, "l1_height", l1Height, "blocks_left", blocks)
+			m.Emit(ctx, m.alert(NearingTimeoutRule, "P2", id, fmt.Sprintf("channel %s opened at l1 block %d times out in %d blocks", id, open.openBlock, blocks)))
+			open.nearingTimeout = true
+		}
+	}
+	m.channelsNearTimeout.Set(float64(nearing))
+	m.minBlocksToTimeout.Set(float64(minBlocks))
+}
+
+// alert returns the alert of the rule about the channel.
+func (m *Monitor) alert(rule string, priority string, id derive.ChannelID, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   id.String(),
+		Summary:  summary,
+		Labels:   map[string]string{"batcher": m.batcherAddress.String()},
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	return nil
+}
+
+// readBatches decodes the batches of the channel data, returning the number of batches by type decoded before an error.
+func readBatches(r io.Reader) (map[string]int, error) {
+	counts := make(map[string]int)
+	next, err := derive.BatchReader(r)
+	if err != nil {
+		return counts, fmt.Errorf("failed to decompress the channel: %w", err)
+	}
+	for {
+		batchData, err := next()
+		if errors.Is(err, io.EOF) {
+			return counts, nil
+		}
+		if err != nil {
+			return counts, err
+		}
+		counts[batchType(batchData)]++
+	}
+}
+
+// batchType returns the name of the type of the batch, the first byte of its encoding.
+func batchType(batchData *derive.BatchData) string {
+	data, err := batchData.MarshalBinary()
+	if err != nil || len(data) == 0 {
+		return "unknown"
+	}
+	switch data[0] {
+	case derive.SingularBatchType:
+		return "singular"
+	case derive.SpanBatchType:
+		return "span"
+	default:
+		return "unknown"
+	}
+}
+
+// isTimedOut returns true when the channel opened at `openBlock` can't be completed anymore at `l1Height`.
+func isTimedOut(openBlock uint64, l1Height uint64, channelTimeout uint64) bool {
+	return l1Height > openBlock+channelTimeout
+}
+
+// blocksToTimeout returns the number of L1 blocks left at `l1Height` to complete the channel opened at `openBlock`.
+func blocksToTimeout(openBlock uint64, l1Height uint64, channelTimeout uint64) uint64 {
+	if isTimedOut(openBlock, l1Height, channelTimeout) {
+		return 0
+	}
+	return openBlock + channelTimeout - l1Height
+}
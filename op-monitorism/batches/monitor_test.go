@@ -0,0 +1,272 @@
+package batches
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var batchInbox = common.HexToAddress("0xff00000000000000000000000000000000000010")
+
+// compressBatches returns the channel data of the batches, RLP-encoded and zlib-compressed as the batcher does.
+func compressBatches(t *testing.T, batches ...*derive.BatchData) []byte {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	for _, batch := range batches {
+		if err := rlp.Encode(zw, batch); err != nil {
+			t.Fatalf("error: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadBatches(t *testing.T) {
+	singular := derive.NewBatchData(&derive.SingularBatch{
+		ParentHash: common.HexToHash("0x01"),
+		EpochNum:   10,
+		EpochHash:  common.HexToHash("0x02"),
+		Timestamp:  1000,
+	})
+	valid := compressBatches(t, singular, singular)
+
+	counts, err := readBatches(bytes.NewReader(valid))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if counts["singular"] != 2 {
+		t.Errorf("expected 2 singular batches but got %v", counts)
+	}
+
+	if _, err := readBatches(bytes.NewReader([]byte{0x01, 0x02, 0x03})); err == nil {
+		t.Errorf("expected an error for undecompressable data")
+	}
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	_, _ = zw.Write([]byte{0x83, 0x05, 0x01, 0x02}) // RLP string of an unknown batch type.
+	_ = zw.Close()
+	if _, err := readBatches(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Errorf("expected an error for an unknown batch type")
+	}
+}
+
+func TestIsTimedOut(t *testing.T) {
+	tests := []struct {
+		name     string
+		l1Height uint64
+		expected bool
+	}{
+		{name: "Within the timeout", l1Height: 1100, expected: false},
+		{name: "On the timeout", l1Height: 1300, expected: false},
+		{name: "After the timeout", l1Height: 1301, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isTimedOut(1000, test.l1Height, 300)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestBlocksToTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		l1Height uint64
+		expected uint64
+	}{
+		{name: "Opening block", l1Height: 1000, expected: 300},
+		{name: "Within the timeout", l1Height: 1260, expected: 40},
+		{name: "On the timeout", l1Height: 1300, expected: 0},
+		{name: "After the timeout", l1Height: 1301, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := blocksToTimeout(1000, test.l1Height, 300)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %d but got %d", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+// batcherNode is an L1 node whose blocks include the frames submitted by the batcher in calldata.
+type batcherNode struct {
+	*fake.Node
+
+	key   *ecdsa.PrivateKey
+	nonce uint64
+}
+
+func newBatcherNode(t *testing.T) *batcherNode {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	n := &batcherNode{Node: fake.NewNode(t), key: key}
+	n.AddBlock(&types.Header{})
+	return n
+}
+
+// addBlock appends a block with a batcher transaction per frame.
+func (n *batcherNode) addBlock(t *testing.T, frames ...derive.Frame) {
+	txs := make([]*types.Transaction, len(frames))
+	for i, frame := range frames {
+		data := bytes.NewBuffer([]byte{derive.DerivationVersion0})
+		if err := frame.MarshalBinary(data); err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		txs[i] = types.MustSignNewTx(n.key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+			ChainID: big.NewInt(1), Nonce: n.nonce, To: &batchInbox, Gas: 100_000, GasFeeCap: big.NewInt(1e9), Data: data.Bytes(),
+		})
+		n.nonce++
+	}
+	n.AddBlock(&types.Header{}, txs...)
+}
+
+// newTestMonitor returns the monitor of the batcher of the node from the block 1, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *batcherNode) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{
+		L1NodeURL:         node.URL,
+		BatchInboxAddress: batchInbox,
+		BatcherAddress:    crypto.PubkeyToAddress(node.key.PublicKey),
+		StartBlockHeight:  1,
+		BlockRange:        100,
+		ChannelTimeout:    5,
+		ChannelMargin:     2,
+	}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := newBatcherNode(t)
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// the frames of a channel split over two blocks are counted, and its batches once complete.
+	singular := derive.NewBatchData(&derive.SingularBatch{ParentHash: common.HexToHash("0x01"), EpochNum: 10, EpochHash: common.HexToHash("0x02"), Timestamp: 1000})
+	data := compressBatches(t, singular, singular)
+	id := derive.ChannelID{0x01}
+	node.addBlock(t, derive.Frame{ID: id, FrameNumber: 0, Data: data[:len(data)/2]})
+	m.Run(ctx)
+	if txs, frames, open := testutil.ToFloat64(m.batcherTxs.WithLabelValues("calldata")), testutil.ToFloat64(m.frames), testutil.ToFloat64(m.openChannels); txs != 1 || frames != 1 || open != 1 {
+		t.Errorf("expected 1 batcher tx with 1 frame of an open channel but got %v, %v and %v", txs, frames, open)
+	}
+	node.addBlock(t, derive.Frame{ID: id, FrameNumber: 1, Data: data[len(data)/2:], IsLast: true})
+	m.Run(ctx)
+	if read, open, batches := testutil.ToFloat64(m.channelsRead), testutil.ToFloat64(m.openChannels), testutil.ToFloat64(m.batches.WithLabelValues("singular")); read != 1 || open != 0 || batches != 2 {
+		t.Errorf("expected the channel read with 2 singular batches but got %v read, %v open and %v batches", read, open, batches)
+	}
+	if checked, invalid := testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("checked")), testutil.ToFloat64(m.invalidChannels); checked != 2 || invalid != 0 {
+		t.Errorf("expected the block 2 checked without invalid channel but got %v and %v", checked, invalid)
+	}
+
+	// a complete channel with undecodable batches is counted as invalid, and a frame repeated as rejected.
+	node.addBlock(t, derive.Frame{ID: derive.ChannelID{0x02}, FrameNumber: 0, Data: []byte{0x01, 0x02}, IsLast: true})
+	node.addBlock(t, derive.Frame{ID: derive.ChannelID{0x03}, FrameNumber: 1, Data: []byte{0x01}}, derive.Frame{ID: derive.ChannelID{0x03}, FrameNumber: 1, Data: []byte{0x01}})
+	m.Run(ctx)
+	if invalid, rejected := testutil.ToFloat64(m.invalidChannels), testutil.ToFloat64(m.invalidFrames); invalid != 1 || rejected != 1 {
+		t.Errorf("expected 1 invalid channel and 1 rejected frame but got %v and %v", invalid, rejected)
+	}
+}
+
+func TestRunChannelTimeout(t *testing.T) {
+	node := newBatcherNode(t)
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// a channel opened at the block 1 has 4 blocks left at the block 2.
+	node.addBlock(t, derive.Frame{ID: derive.ChannelID{0x01}, FrameNumber: 0, Data: []byte{0x01}})
+	node.addBlock(t)
+	m.Run(ctx)
+	if nearing, minBlocks := testutil.ToFloat64(m.channelsNearTimeout), testutil.ToFloat64(m.minBlocksToTimeout); nearing != 0 || minBlocks != 4 {
+		t.Errorf("expected no channel nearing the timeout with 4 blocks left but got %v and %v", nearing, minBlocks)
+	}
+
+	// the channel is nearing the timeout within the margin of 2 blocks.
+	node.addBlock(t)
+	node.addBlock(t)
+	m.Run(ctx)
+	if nearing, minBlocks := testutil.ToFloat64(m.channelsNearTimeout), testutil.ToFloat64(m.minBlocksToTimeout); nearing != 1 || minBlocks != 2 {
+		t.Errorf("expected 1 channel nearing the timeout with 2 blocks left but got %v and %v", nearing, minBlocks)
+	}
+
+	// the channel not complete at the block 6 times out at the block 7, counted and no longer open.
+	node.addBlock(t)
+	node.addBlock(t)
+	node.addBlock(t)
+	m.Run(ctx)
+	if timedOut, open, nearing := testutil.ToFloat64(m.timedOutChannels), testutil.ToFloat64(m.openChannels), testutil.ToFloat64(m.channelsNearTimeout); timedOut != 1 || open != 0 || nearing != 0 {
+		t.Errorf("expected 1 channel timed out and none open but got %v, %v and %v", timedOut, open, nearing)
+	}
+	if minBlocks := testutil.ToFloat64(m.minBlocksToTimeout); minBlocks != 5 {
+		t.Errorf("expected the channel timeout without open channel but got %v", minBlocks)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newBatcherNode(t)
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// a complete channel with undecodable batches fires once, as the channel is dropped.
+	open, invalid := derive.ChannelID{0x01}, derive.ChannelID{0x02}
+	node.addBlock(t, derive.Frame{ID: open, FrameNumber: 0, Data: []byte{0x01}}, derive.Frame{ID: invalid, FrameNumber: 0, Data: []byte{0x01, 0x02}, IsLast: true})
+	node.addBlock(t)
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(InvalidChannelRule); len(alerts) != 1 || alerts[0].Entity != invalid.String() || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the invalid channel alert once but got %v", alerts)
+	}
+	if alerts := notifier.Alerts(NearingTimeoutRule); len(alerts) != 0 {
+		t.Fatalf("expected no channel nearing the timeout but got %v", alerts)
+	}
+
+	// the open channel nearing the timeout fires once over the blocks of the margin.
+	node.addBlock(t)
+	node.addBlock(t)
+	m.Run(ctx)
+	node.addBlock(t)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(NearingTimeoutRule); len(alerts) != 1 || alerts[0].Entity != open.String() || alerts[0].Priority != "P2" {
+		t.Fatalf("expected the nearing timeout alert once but got %v", alerts)
+	}
+
+	// the channel not complete before the timeout fires, and is dropped.
+	node.addBlock(t)
+	node.addBlock(t)
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(TimedOutRule); len(alerts) != 1 || alerts[0].Entity != open.String() || len(m.channels) != 0 {
+		t.Fatalf("expected the timed out alert once but got %v", alerts)
+	}
+}
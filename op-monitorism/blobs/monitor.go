@@ -0,0 +1,283 @@
+package blobs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/beacon"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "blobs"
+	MetricsNamespace = "blobs_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the blob hash.
+	MissingBlobRule = "blob not served"
+	ExpiredBlobRule = "blob missing until the end of its retention window"
+	InvalidBlobRule = "blob with an invalid KZG proof"
+)
+
+// missingBlob is a blob of a batcher transaction not served by the beacon node, checked again until it is or its retention window ends.
+type missingBlob struct {
+	txHash common.Hash
+	block  uint64
+	slot   uint64
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+	beacon   *beacon.Client
+	signer   types.Signer
+
+	batchInboxAddress common.Address
+	batcherAddress    common.Address
+
+	nextL1Height   uint64
+	blockRange     uint64
+	retentionSlots uint64
+
+	missing map[common.Hash]*missingBlob
+
+	// metrics
+	highestBlockNumber  *prometheus.GaugeVec
+	blobs               *prometheus.CounterVec
+	missingBlobs        prometheus.Gauge
+	recoveredBlobs      prometheus.Counter
+	expiredBlobs        prometheus.Counter
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating blobs monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	chainID, err := l1Client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the chain id: %w", err)
+	}
+
+	beaconClient := beacon.NewClient(cfg.L1BeaconURL)
+	if err := beaconClient.Init(ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize the beacon client: %w", err)
+	}
+
+	startingL1Height := cfg.StartBlockHeight
+	if startingL1Height == 0 {
+		if startingL1Height, err = l1Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+	log.Info("configured starting height", "height", startingL1Height, "batch_inbox", cfg.BatchInboxAddress, "batcher", cfg.BatcherAddress, "retention_slots", cfg.RetentionSlots)
+
+	return &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+		beacon:   beaconClient,
+		signer:   types.LatestSignerForChainID(chainID),
+
+		batchInboxAddress: cfg.BatchInboxAddress,
+		batcherAddress:    cfg.BatcherAddress,
+
+		nextL1Height:   startingL1Height,
+		blockRange:     cfg.BlockRange,
+		retentionSlots: cfg.RetentionSlots,
+
+		missing: make(map[common.Hash]*missingBlob),
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed l1 heights (checked and known)",
+		}, []string{"type"}),
+		blobs: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "blobs",
+			Help:      "number of blobs of the batcher checked by status (available, missing, invalid, outOfRetention)",
+		}, []string{"status"}),
+		missingBlobs: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "missingBlobs",
+			Help:      "number of blobs of the batcher not served by the beacon node within their retention window",
+		}),
+		recoveredBlobs: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "recoveredBlobs",
+			Help:      "number of missing blobs served by the beacon node afterwards",
+		}),
+		expiredBlobs: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "expiredBlobs",
+			Help:      "number of blobs missing until the end of their retention window",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestL1Height))
+
+	currentSlot := m.beacon.Slot(uint64(time.Now().Unix()))
+	m.checkMissingBlobs(ctx, currentSlot)
+
+	toBlock := min(latestL1Height, m.nextL1Height+m.blockRange-1)
+	for ; m.nextL1Height <= toBlock; m.nextL1Height++ {
+		if err := m.processBlock(ctx, m.nextL1Height, currentSlot); err != nil {
+			m.log.Error("failed to process the block", "block", m.nextL1Height, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l1", "processBlock").Inc()
+			break
+		}
+		m.highestBlockNumber.WithLabelValues("checked").Set(float64(m.nextL1Height))
+	}
+
+	m.missingBlobs.Set(float64(len(m.missing)))
+}
+
+// processBlock checks the blobs of the batcher transactions of the L1 block against the sidecars served by the beacon node.
+func (m *Monitor) processBlock(ctx context.Context, number uint64, currentSlot uint64) error {
+	block, err := m.l1Client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return fmt.Errorf("failed to query the block: %w", err)
+	}
+	slot := m.beacon.Slot(block.Time())
+
+	var sidecars map[common.Hash]*eth.APIBlobSidecar
+	for _, tx := range block.Transactions() {
+		if tx.Type() != types.BlobTxType || tx.To() == nil || *tx.To() != m.batchInboxAddress {
+			continue
+		}
+		sender, err := types.Sender(m.signer, tx)
+		if err != nil || sender != m.batcherAddress {
+			continue
+		}
+
+		if !isWithinRetention(slot, currentSlot, m.retentionSlots) {
+			m.blobs.WithLabelValues("outOfRetention").Add(float64(len(tx.BlobHashes())))
+			continue
+		}
+		if sidecars == nil {
+			if sidecars, err = m.beacon.Sidecars(ctx, slot); err != nil {
+				return err
+			}
+		}
+
+		for _, blobHash := range tx.BlobHashes() {
+			status, err := blobStatus(sidecars[blobHash])
+			m.blobs.WithLabelValues(status).Inc()
+			switch status {
+			case "missing":
+				m.log.Error("blob not served by the beacon node", "tx", tx.Hash(), "blob_hash", blobHash, "block", number, "slot", slot)
+				m.missing[blobHash] = &missingBlob{txHash: tx.Hash(), block: number, slot: slot}
+				m.Update(ctx, m.alert(MissingBlobRule, "P1", blobHash, fmt.Sprintf("blob %s of the batcher transaction %s at l1 block %d not served by the beacon node", blobHash, tx.Hash(), number)), true)
+			case "invalid":
+				m.log.Error("blob with an invalid KZG proof", "tx", tx.Hash(), "blob_hash", blobHash, "block", number, "slot", slot, "err", err)
+				m.Emit(ctx, m.alert(InvalidBlobRule, "P1", blobHash, fmt.Sprintf("blob %s of the batcher transaction %s at l1 block %d served with an invalid KZG proof", blobHash, tx.Hash(), number)))
+			}
+		}
+	}
+	return nil
+}
+
+// checkMissingBlobs checks again the missing blobs, forgetting the ones served by the beacon node or out of their retention window.
+func (m *Monitor) checkMissingBlobs(ctx context.Context, currentSlot uint64) {
+	sidecarsBySlot := make(map[uint64]map[common.Hash]*eth.APIBlobSidecar)
+	for blobHash, blob := range m.missing {
+		if !isWithinRetention(blob.slot, currentSlot, m.retentionSlots) {
+			m.log.Error("blob missing until the end of its retention window", "tx", blob.txHash, "blob_hash", blobHash, "block", blob.block, "slot", blob.slot)
+			m.expiredBlobs.Inc()
+			m.Emit(ctx, m.alert(ExpiredBlobRule, "P1", blobHash, fmt.Sprintf("blob %s of the batcher transaction %s at l1 block %d missing until the end of its retention window", blobHash, blob.txHash, blob.block)))
+			m.Update(ctx, m.alert(MissingBlobRule, "P1", blobHash, fmt.Sprintf("blob %s out of its retention window", blobHash)), false)
+			delete(m.missing, blobHash)
+			continue
+		}
+
+		sidecars, ok := sidecarsBySlot[blob.slot]
+		if !ok {
+			var err error
+			if sidecars, err = m.beacon.Sidecars(ctx, blob.slot); err != nil {
+				m.log.Error("failed to query the blob sidecars", "slot", blob.slot, "err", err)
+				m.unexpectedRpcErrors.WithLabelValues("beacon", "blobSidecars").Inc()
+				continue
+			}
+			sidecarsBySlot[blob.slot] = sidecars
+		}
+
+		status, err := blobStatus(sidecars[blobHash])
+		switch status {
+		case "available":
+			m.log.Info("missing blob now served by the beacon node", "tx", blob.txHash, "blob_hash", blobHash, "block", blob.block, "slot", blob.slot)
+			m.recoveredBlobs.Inc()
+			m.Update(ctx, m.alert(MissingBlobRule, "P1", blobHash, fmt.Sprintf("blob %s now served by the beacon node", blobHash)), false)
+			delete(m.missing, blobHash)
+		case "invalid":
+			m.log.Error("blob with an invalid KZG proof", "tx", blob.txHash, "blob_hash", blobHash, "block", blob.block, "slot", blob.slot, "err", err)
+			m.blobs.WithLabelValues(status).Inc()
+			m.Emit(ctx, m.alert(InvalidBlobRule, "P1", blobHash, fmt.Sprintf("blob %s of the batcher transaction %s at l1 block %d served with an invalid KZG proof", blobHash, blob.txHash, blob.block)))
+			m.Update(ctx, m.alert(MissingBlobRule, "P1", blobHash, fmt.Sprintf("blob %s now served by the beacon node", blobHash)), false)
+			delete(m.missing, blobHash)
+		}
+	}
+}
+
+// alert returns the alert of the rule about the blob.
+func (m *Monitor) alert(rule string, priority string, blobHash common.Hash, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   blobHash.String(),
+		Summary:  summary,
+		Labels:   map[string]string{"batcher": m.batcherAddress.String()},
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	return nil
+}
+
+// blobStatus returns the status of the blob given its sidecar, `nil` when not served by the beacon node.
+func blobStatus(sidecar *eth.APIBlobSidecar) (string, error) {
+	if sidecar == nil {
+		return "missing", nil
+	}
+	if err := beacon.VerifySidecar(sidecar); err != nil {
+		return "invalid", err
+	}
+	return "available", nil
+}
+
+// isWithinRetention returns true when the blob sidecars of the slot are still expected to be served at `currentSlot`.
+func isWithinRetention(slot uint64, currentSlot uint64, retentionSlots uint64) bool {
+	return currentSlot <= slot+retentionSlots
+}
@@ -0,0 +1,243 @@
+package blobs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/beacon"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/holiman/uint256"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var batchInbox = common.HexToAddress("0xff00000000000000000000000000000000000010")
+
+// newSidecar returns the sidecar of a blob whose first field element is `value`, along with its commitment and proof.
+func newSidecar(t *testing.T, value byte) *eth.APIBlobSidecar {
+	var blob kzg4844.Blob
+	blob[31] = value
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	proof, err := kzg4844.ComputeBlobProof(blob, commitment)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	return &eth.APIBlobSidecar{Blob: eth.Blob(blob), KZGCommitment: eth.Bytes48(commitment), KZGProof: eth.Bytes48(proof)}
+}
+
+func TestBlobStatus(t *testing.T) {
+	valid := newSidecar(t, 1)
+	invalid := newSidecar(t, 2)
+	invalid.KZGProof = valid.KZGProof
+
+	tests := []struct {
+		name     string
+		sidecar  *eth.APIBlobSidecar
+		expected string
+	}{
+		{name: "missing", sidecar: nil, expected: "missing"},
+		{name: "valid", sidecar: valid, expected: "available"},
+		{name: "invalid proof", sidecar: invalid, expected: "invalid"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if status, _ := blobStatus(test.sidecar); status != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, status)
+			}
+		})
+	}
+}
+
+func TestVersionedHash(t *testing.T) {
+	sidecar := newSidecar(t, 1)
+	hash := beacon.VersionedHash(sidecar.KZGCommitment)
+	if hash[0] != 0x01 {
+		t.Errorf("expected the version 0x01 but got %#x", hash[0])
+	}
+	if !kzg4844.IsValidVersionedHash(hash[:]) {
+		t.Errorf("expected a valid versioned hash but got %s", hash)
+	}
+}
+
+func TestIsWithinRetention(t *testing.T) {
+	tests := []struct {
+		name        string
+		slot        uint64
+		currentSlot uint64
+		retention   uint64
+		expected    bool
+	}{
+		{name: "recent", slot: 100, currentSlot: 110, retention: 131072, expected: true},
+		{name: "last slot of the window", slot: 100, currentSlot: 200, retention: 100, expected: true},
+		{name: "out of the window", slot: 100, currentSlot: 201, retention: 100, expected: false},
+		{name: "future slot", slot: 300, currentSlot: 200, retention: 100, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isWithinRetention(test.slot, test.currentSlot, test.retention); got != test.expected {
+				t.Errorf("Failed %s: expected %v but got %v", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+// beaconNode serves the sidecars of the slots, the genesis being at the time 0 and the slots lasting 12 seconds.
+type beaconNode struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	sidecars map[string][]*eth.APIBlobSidecar
+}
+
+func newBeaconNode(t *testing.T) *beaconNode {
+	n := &beaconNode{sidecars: make(map[string][]*eth.APIBlobSidecar)}
+	n.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		switch {
+		case r.URL.Path == "/eth/v1/beacon/genesis":
+			_, _ = w.Write([]byte(`{"data":{"genesis_time":"0"}}`))
+		case r.URL.Path == "/eth/v1/config/spec":
+			_, _ = w.Write([]byte(`{"data":{"SECONDS_PER_SLOT":"12"}}`))
+		case strings.HasPrefix(r.URL.Path, "/eth/v1/beacon/blob_sidecars/"):
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": n.sidecars[strings.TrimPrefix(r.URL.Path, "/eth/v1/beacon/blob_sidecars/")]})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(n.Close)
+	return n
+}
+
+// serve serves the sidecar at the slot of the time.
+func (n *beaconNode) serve(timestamp uint64, sidecar *eth.APIBlobSidecar) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	slot := strconv.FormatUint(timestamp/12, 10)
+	n.sidecars[slot] = append(n.sidecars[slot], sidecar)
+}
+
+// addBlobBlock appends a block at the time with a batcher transaction carrying the blobs of the sidecars.
+func addBlobBlock(t *testing.T, node *fake.Node, key *ecdsa.PrivateKey, nonce uint64, timestamp uint64, sidecars ...*eth.APIBlobSidecar) {
+	hashes := make([]common.Hash, len(sidecars))
+	for i, sidecar := range sidecars {
+		hashes[i] = beacon.VersionedHash(sidecar.KZGCommitment)
+	}
+	tx := types.MustSignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.BlobTx{
+		ChainID: uint256.NewInt(1), Nonce: nonce, To: batchInbox, Gas: 100_000, GasFeeCap: uint256.NewInt(1e9), BlobFeeCap: uint256.NewInt(1), BlobHashes: hashes,
+	})
+	node.AddBlock(&types.Header{Time: timestamp}, tx)
+}
+
+// newTestMonitor returns the monitor of the batcher from the block 1 with a day of retention, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *fake.Node, beaconNode *beaconNode, batcher common.Address) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{L1NodeURL: node.URL, L1BeaconURL: beaconNode.URL, BatchInboxAddress: batchInbox, BatcherAddress: batcher, StartBlockHeight: 1, BlockRange: 100, RetentionSlots: 7200}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	node, beaconNode := fake.NewNode(t), newBeaconNode(t)
+	node.AddBlock(&types.Header{})
+	m, _ := newTestMonitor(t, node, beaconNode, crypto.PubkeyToAddress(key.PublicKey))
+	ctx := context.Background()
+
+	// the blobs are counted by status, the missing ones being tracked.
+	now := uint64(time.Now().Unix())
+	served, missing := newSidecar(t, 1), newSidecar(t, 2)
+	beaconNode.serve(now, served)
+	addBlobBlock(t, node, key, 0, now, served, missing)
+	m.Run(ctx)
+	if available, missingCount, tracked := testutil.ToFloat64(m.blobs.WithLabelValues("available")), testutil.ToFloat64(m.blobs.WithLabelValues("missing")), testutil.ToFloat64(m.missingBlobs); available != 1 || missingCount != 1 || tracked != 1 {
+		t.Errorf("expected 1 available and 1 missing blob tracked but got %v, %v and %v", available, missingCount, tracked)
+	}
+
+	// the missing blob served later is recovered.
+	beaconNode.serve(now, missing)
+	m.Run(ctx)
+	if recovered, tracked := testutil.ToFloat64(m.recoveredBlobs), testutil.ToFloat64(m.missingBlobs); recovered != 1 || tracked != 0 {
+		t.Errorf("expected the missing blob recovered but got %v recovered and %v tracked", recovered, tracked)
+	}
+
+	// the blobs of a block out of the retention window are not checked.
+	addBlobBlock(t, node, key, 1, 0, newSidecar(t, 3))
+	m.Run(ctx)
+	if outOfRetention, checked := testutil.ToFloat64(m.blobs.WithLabelValues("outOfRetention")), testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("checked")); outOfRetention != 1 || checked != 2 {
+		t.Errorf("expected 1 blob out of retention at the block 2 but got %v at %v", outOfRetention, checked)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	node, beaconNode := fake.NewNode(t), newBeaconNode(t)
+	node.AddBlock(&types.Header{})
+	m, notifier := newTestMonitor(t, node, beaconNode, crypto.PubkeyToAddress(key.PublicKey))
+	ctx := context.Background()
+
+	// a blob not served fires once, and resolves once served.
+	now := uint64(time.Now().Unix())
+	served, missing := newSidecar(t, 1), newSidecar(t, 2)
+	beaconNode.serve(now, served)
+	addBlobBlock(t, node, key, 0, now, served, missing)
+	m.Run(ctx)
+	m.Run(ctx)
+	missingHash := beacon.VersionedHash(missing.KZGCommitment).String()
+	if alerts := notifier.Alerts(MissingBlobRule); len(alerts) != 1 || alerts[0].Entity != missingHash || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the missing blob alert once but got %v", alerts)
+	}
+	beaconNode.serve(now, missing)
+	m.Run(ctx)
+	if firing := notifier.Firing(MissingBlobRule); len(firing) != 0 || len(m.missing) != 0 {
+		t.Fatalf("expected the missing blob alert resolved but got %v", firing)
+	}
+
+	// a blob missing until the end of its retention window fires the expiry, and resolves the missing blob.
+	expired := newSidecar(t, 3)
+	addBlobBlock(t, node, key, 1, now, expired)
+	m.Run(ctx)
+	if firing := notifier.Firing(MissingBlobRule); len(firing) != 1 {
+		t.Fatalf("expected the missing blob alert but got %v", firing)
+	}
+	m.retentionSlots = 0
+	m.missing[beacon.VersionedHash(expired.KZGCommitment)].slot = m.beacon.Slot(now) - 1
+	m.Run(ctx)
+	if alerts := notifier.Alerts(ExpiredBlobRule); len(alerts) != 1 || len(notifier.Firing(MissingBlobRule)) != 0 {
+		t.Fatalf("expected the expired blob alert and the missing blob alert resolved but got %v", alerts)
+	}
+}
@@ -0,0 +1,109 @@
+package bridge_supply
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+	L2NodeURLFlagName = "l2.node.url"
+
+	OptimismPortalAddressFlagName   = "optimismportal.address"
+	L1StandardBridgeAddressFlagName = "l1standardbridge.address"
+	TokensFlagName                  = "tokens"
+)
+
+// Token is an ERC-20 bridged through the standard bridge, native on L1.
+type Token struct {
+	Symbol  string
+	L1Token common.Address
+	L2Token common.Address
+}
+
+type CLIConfig struct {
+	L1NodeURL string
+	L2NodeURL string
+
+	OptimismPortalAddress   common.Address
+	L1StandardBridgeAddress common.Address
+	Tokens                  []Token
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL: ctx.String(L1NodeURLFlagName),
+		L2NodeURL: ctx.String(L2NodeURLFlagName),
+	}
+
+	portalAddress := ctx.String(OptimismPortalAddressFlagName)
+	if !common.IsHexAddress(portalAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", OptimismPortalAddressFlagName)
+	}
+	cfg.OptimismPortalAddress = common.HexToAddress(portalAddress)
+
+	bridgeAddress := ctx.String(L1StandardBridgeAddressFlagName)
+	if !common.IsHexAddress(bridgeAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", L1StandardBridgeAddressFlagName)
+	}
+	cfg.L1StandardBridgeAddress = common.HexToAddress(bridgeAddress)
+
+	for _, token := range ctx.StringSlice(TokensFlagName) {
+		parsed, err := parseToken(token)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Tokens = append(cfg.Tokens, parsed)
+	}
+
+	return cfg, nil
+}
+
+// parseToken parses a token formatted via `symbol:l1Address:l2Address`.
+func parseToken(token string) (Token, error) {
+	symbol, l1Token, l2Token, err := util.ParseBridgedToken(token)
+	if err != nil {
+		return Token{}, err
+	}
+	return Token{Symbol: symbol, L1Token: l1Token, L2Token: l2Token}, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    L2NodeURLFlagName,
+			Usage:   "Node URL of L2 peer",
+			Value:   "127.0.0.1:9545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     OptimismPortalAddressFlagName,
+			Usage:    "Address of the OptimismPortal contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "OPTIMISM_PORTAL"),
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     L1StandardBridgeAddressFlagName,
+			Usage:    "Address of the L1StandardBridge contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "L1_STANDARD_BRIDGE"),
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:    TokensFlagName,
+			Usage:   "One or more tokens native on L1 formatted via `symbol:l1Address:l2Address`",
+			EnvVars: opservice.PrefixEnvVar(envVar, "TOKENS"),
+		},
+	}
+}
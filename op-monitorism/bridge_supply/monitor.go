@@ -0,0 +1,210 @@
+package bridge_supply
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "bridge_supply"
+	MetricsNamespace = "bridge_supply_mon"
+
+	// UndercollateralizedRule is the rule of the alert emitted while the L2 supply of a token exceeds its L1 collateral.
+	UndercollateralizedRule = "L2 supply exceeds the L1 collateral"
+)
+
+// bridgedToken is a token along with its bindings and decimals.
+type bridgedToken struct {
+	Token
+	l1Token  *bindings.ERC20Caller
+	l2Token  *bindings.ERC20Caller
+	decimals uint8
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+	l2Client *ethclient.Client
+
+	portalAddress common.Address
+	bridgeAddress common.Address
+	bridge        *bindings.L1StandardBridgeCaller
+	tokens        []*bridgedToken
+
+	// metrics
+	l1EthLocked         *prometheus.GaugeVec
+	l1Collateral        *prometheus.GaugeVec
+	l1EscrowBalance     *prometheus.GaugeVec
+	l2Supply            *prometheus.GaugeVec
+	undercollateralized *prometheus.GaugeVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating bridge supply monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	l2Client, err := ethclient.Dial(cfg.L2NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l2: %w", err)
+	}
+
+	bridge, err := bindings.NewL1StandardBridgeCaller(cfg.L1StandardBridgeAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L1StandardBridge: %w", err)
+	}
+
+	tokens := make([]*bridgedToken, len(cfg.Tokens))
+	for i, token := range cfg.Tokens {
+		l1Token, err := bindings.NewERC20Caller(token.L1Token, l1Client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bind to the L1 token %s: %w", token.Symbol, err)
+		}
+		l2Token, err := bindings.NewERC20Caller(token.L2Token, l2Client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bind to the L2 token %s: %w", token.Symbol, err)
+		}
+		decimals, err := l2Token.Decimals(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the decimals of %s: %w", token.Symbol, err)
+		}
+
+		tokens[i] = &bridgedToken{Token: token, l1Token: l1Token, l2Token: l2Token, decimals: decimals}
+		log.Info("configured token", "symbol", token.Symbol, "l1_token", token.L1Token, "l2_token", token.L2Token, "decimals", decimals)
+	}
+
+	return &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+		l2Client: l2Client,
+
+		portalAddress: cfg.OptimismPortalAddress,
+		bridgeAddress: cfg.L1StandardBridgeAddress,
+		bridge:        bridge,
+		tokens:        tokens,
+
+		l1EthLocked: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "l1EthLocked",
+			Help:      "ETH held on L1 by the bridge contracts",
+		}, []string{"contract"}),
+		l1Collateral: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "l1Collateral",
+			Help:      "amount of the token deposited through the L1StandardBridge for the L2 token, in token units",
+		}, []string{"symbol"}),
+		l1EscrowBalance: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "l1EscrowBalance",
+			Help:      "balance of the token held by the L1StandardBridge, in token units",
+		}, []string{"symbol"}),
+		l2Supply: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "l2Supply",
+			Help:      "total supply of the token on L2, in token units",
+		}, []string{"symbol"}),
+		undercollateralized: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "undercollateralized",
+			Help:      "1 if the L2 supply of the token exceeds its L1 collateral, 0 otherwise",
+		}, []string{"symbol"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	for contract, address := range map[string]common.Address{"OptimismPortal": m.portalAddress, "L1StandardBridge": m.bridgeAddress} {
+		balance, err := m.l1Client.BalanceAt(ctx, address, nil)
+		if err != nil {
+			m.log.Error("failed to query the ETH balance", "contract", contract, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l1", "balanceAt").Inc()
+			continue
+		}
+		m.l1EthLocked.WithLabelValues(contract).Set(util.WeiToEther(balance))
+	}
+
+	for _, token := range m.tokens {
+		m.checkToken(ctx, token)
+	}
+}
+
+// checkToken compares the L2 supply of the token against the amount deposited for it on L1.
+// The deposits are recorded on L1 before being minted on L2, and the withdrawals are burnt on L2 before being released on L1,
+// so the L2 supply never exceeds the L1 collateral unless the token is minted without a deposit.
+func (m *Monitor) checkToken(ctx context.Context, token *bridgedToken) {
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	collateral, err := m.bridge.Deposits(callOpts, token.L1Token, token.L2Token)
+	if err != nil {
+		m.log.Error("failed to query the deposits of the token", "symbol", token.Symbol, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "deposits").Inc()
+		return
+	}
+	escrow, err := token.l1Token.BalanceOf(callOpts, m.bridgeAddress)
+	if err != nil {
+		m.log.Error("failed to query the escrow balance of the token", "symbol", token.Symbol, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "balanceOf").Inc()
+		return
+	}
+	supply, err := token.l2Token.TotalSupply(callOpts)
+	if err != nil {
+		m.log.Error("failed to query the L2 supply of the token", "symbol", token.Symbol, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "totalSupply").Inc()
+		return
+	}
+
+	m.l1Collateral.WithLabelValues(token.Symbol).Set(util.ToUnits(collateral, token.decimals))
+	m.l1EscrowBalance.WithLabelValues(token.Symbol).Set(util.ToUnits(escrow, token.decimals))
+	m.l2Supply.WithLabelValues(token.Symbol).Set(util.ToUnits(supply, token.decimals))
+
+	under := isUndercollateralized(supply, collateral)
+	if under {
+		m.log.Error("L2 supply exceeds the L1 collateral", "symbol", token.Symbol, "l2_supply", supply, "l1_collateral", collateral)
+	}
+	m.undercollateralized.WithLabelValues(token.Symbol).Set(util.BoolToFloat(under))
+	m.Update(ctx, alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     UndercollateralizedRule,
+		Priority: "P0",
+		Entity:   token.L2Token.String(),
+		Summary:  fmt.Sprintf("L2 supply of %s is %g, exceeding the L1 collateral of %g", token.Symbol, util.ToUnits(supply, token.decimals), util.ToUnits(collateral, token.decimals)),
+		Labels:   map[string]string{"symbol": token.Symbol},
+	}, under)
+	m.log.Info("checked token", "symbol", token.Symbol, "l2_supply", supply, "l1_collateral", collateral, "l1_escrow", escrow)
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	m.l2Client.Close()
+	return nil
+}
+
+// isUndercollateralized returns true when the L2 supply exceeds the L1 collateral.
+func isUndercollateralized(l2Supply *big.Int, l1Collateral *big.Int) bool {
+	return l2Supply.Cmp(l1Collateral) > 0
+}
@@ -0,0 +1,131 @@
+package bridge_supply
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var (
+	portal = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+	bridge = common.HexToAddress("0x99C9fc46f92E8a1c0deC1b1747d010903E884bE1")
+	usdc   = Token{Symbol: "USDC", L1Token: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), L2Token: common.HexToAddress("0x7F5c764cBc14f9669B88837ca1490cCa17c31607")}
+)
+
+func TestParseToken(t *testing.T) {
+	token, err := parseToken("USDC:0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48:0x7F5c764cBc14f9669B88837ca1490cCa17c31607")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if token.Symbol != "USDC" || token.L1Token != common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48") || token.L2Token != common.HexToAddress("0x7F5c764cBc14f9669B88837ca1490cCa17c31607") {
+		t.Errorf("unexpected token %v", token)
+	}
+
+	for _, invalid := range []string{"USDC:0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", ":0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48:0x7F5c764cBc14f9669B88837ca1490cCa17c31607", "USDC:0x01:invalid"} {
+		if _, err := parseToken(invalid); err == nil {
+			t.Errorf("expected an error for %s", invalid)
+		}
+	}
+}
+
+func TestIsUndercollateralized(t *testing.T) {
+	tests := []struct {
+		name       string
+		supply     int64
+		collateral int64
+		expected   bool
+	}{
+		{name: "Supply below the collateral", supply: 90, collateral: 100, expected: false},
+		{name: "Supply equal to the collateral", supply: 100, collateral: 100, expected: false},
+		{name: "Supply above the collateral", supply: 101, collateral: 100, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isUndercollateralized(big.NewInt(test.supply), big.NewInt(test.collateral))
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+// newTestMonitor returns the monitor of the USDC bridged through the node serving L1 and L2, and the notifier of its alerts.
+// The L2 supply of the USDC is served by the returned contract, the collateral of 100 USDC being held by the bridge.
+func newTestMonitor(t *testing.T) (*Monitor, *fake.Contract, *fake.Notifier) {
+	node := fake.NewNode(t)
+	node.Result("eth_getBalance", (*hexutil.Big)(big.NewInt(1e18)))
+	node.Contract(bridge, util.MustParseABI(bindings.L1StandardBridgeMetaData)).Returns("deposits", big.NewInt(100e6))
+	node.Contract(usdc.L1Token, util.MustParseABI(bindings.ERC20MetaData)).Returns("balanceOf", big.NewInt(100e6))
+	l2Token := node.Contract(usdc.L2Token, util.MustParseABI(bindings.ERC20MetaData)).Returns("decimals", uint8(6)).Returns("totalSupply", big.NewInt(100e6))
+
+	cfg := CLIConfig{L1NodeURL: node.URL, L2NodeURL: node.URL, OptimismPortalAddress: portal, L1StandardBridgeAddress: bridge, Tokens: []Token{usdc}}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, l2Token, notifier
+}
+
+func TestRun(t *testing.T) {
+	m, l2Token, _ := newTestMonitor(t)
+	ctx := context.Background()
+
+	// the ETH locked in the contracts and the balances of the token are reported in units.
+	m.Run(ctx)
+	if portalLocked, bridgeLocked := testutil.ToFloat64(m.l1EthLocked.WithLabelValues("OptimismPortal")), testutil.ToFloat64(m.l1EthLocked.WithLabelValues("L1StandardBridge")); portalLocked != 1 || bridgeLocked != 1 {
+		t.Errorf("expected 1 ETH locked in each contract but got %v and %v", portalLocked, bridgeLocked)
+	}
+	collateral, escrow, supply := testutil.ToFloat64(m.l1Collateral.WithLabelValues("USDC")), testutil.ToFloat64(m.l1EscrowBalance.WithLabelValues("USDC")), testutil.ToFloat64(m.l2Supply.WithLabelValues("USDC"))
+	if collateral != 100 || escrow != 100 || supply != 100 {
+		t.Errorf("expected 100 USDC of collateral, escrow and supply but got %v, %v and %v", collateral, escrow, supply)
+	}
+	if under := testutil.ToFloat64(m.undercollateralized.WithLabelValues("USDC")); under != 0 {
+		t.Errorf("expected the token collateralized but got %v", under)
+	}
+
+	// an L2 supply exceeding the collateral is reported as undercollateralized.
+	l2Token.Returns("totalSupply", big.NewInt(101e6))
+	m.Run(ctx)
+	if supply, under := testutil.ToFloat64(m.l2Supply.WithLabelValues("USDC")), testutil.ToFloat64(m.undercollateralized.WithLabelValues("USDC")); supply != 101 || under != 1 {
+		t.Errorf("expected a supply of 101 USDC undercollateralized but got %v and %v", supply, under)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	m, l2Token, notifier := newTestMonitor(t)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// an L2 supply exceeding the collateral fires once, and resolves once back within it.
+	l2Token.Returns("totalSupply", big.NewInt(101e6))
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(UndercollateralizedRule); len(alerts) != 1 || alerts[0].Entity != usdc.L2Token.String() || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the undercollateralized alert once but got %v", alerts)
+	}
+	l2Token.Returns("totalSupply", big.NewInt(99e6))
+	m.Run(ctx)
+	if firing := notifier.Firing(UndercollateralizedRule); len(firing) != 0 {
+		t.Fatalf("expected the undercollateralized alert resolved but got %v", firing)
+	}
+}
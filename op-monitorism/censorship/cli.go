@@ -0,0 +1,94 @@
+package censorship
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodeURLFlagName          = "node.url"
+	ContractsFlagName        = "contracts"
+	CensorshipBlocksFlagName = "censorship.blocks"
+	BlockRangeFlagName       = "block.range"
+)
+
+// Contract is a protocol contract whose pending transactions are watched.
+type Contract struct {
+	Name    string
+	Address common.Address
+}
+
+type CLIConfig struct {
+	NodeURL string
+
+	Contracts []Contract
+
+	// CensorshipBlocks is the number of blocks with room for a pending transaction before it is reported as censored.
+	CensorshipBlocks uint64
+	BlockRange       uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		NodeURL:          ctx.String(NodeURLFlagName),
+		CensorshipBlocks: ctx.Uint64(CensorshipBlocksFlagName),
+		BlockRange:       ctx.Uint64(BlockRangeFlagName),
+	}
+
+	names := make(map[string]bool)
+	for _, contract := range ctx.StringSlice(ContractsFlagName) {
+		name, address, err := util.ParseNamedValue(contract, "name=address")
+		if err != nil || !common.IsHexAddress(address) {
+			return cfg, fmt.Errorf("failed to parse `name=address`: %s", contract)
+		}
+		if names[name] {
+			return cfg, fmt.Errorf("duplicated contract %s", name)
+		}
+		names[name] = true
+		cfg.Contracts = append(cfg.Contracts, Contract{Name: name, Address: common.HexToAddress(address)})
+	}
+
+	if cfg.CensorshipBlocks == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", CensorshipBlocksFlagName)
+	}
+	if cfg.BlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", BlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    NodeURLFlagName,
+			Usage:   "Node URL of the chain, exposing the txpool namespace of its public mempool",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "NODE_URL"),
+		},
+		&cli.StringSliceFlag{
+			Name:     ContractsFlagName,
+			Usage:    "One or more protocol contracts whose pending transactions are watched, formatted via `name=address`",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "CONTRACTS"),
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:    CensorshipBlocksFlagName,
+			Usage:   "Number of blocks with room for a pending transaction before it is reported as censored",
+			Value:   10,
+			EnvVars: opservice.PrefixEnvVar(envVar, "CENSORSHIP_BLOCKS"),
+		},
+		&cli.Uint64Flag{
+			Name:    BlockRangeFlagName,
+			Usage:   "Max number of blocks checked per iteration",
+			Value:   100,
+			EnvVars: opservice.PrefixEnvVar(envVar, "BLOCK_RANGE"),
+		},
+	}
+}
@@ -0,0 +1,221 @@
+package censorship
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "censorship"
+	MetricsNamespace = "censorship_mon"
+
+	// CensoredRule is the rule of the alert emitted while a censored transaction is pending, the entity being the transaction.
+	CensoredRule = "transaction censored"
+)
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	client *ethclient.Client
+
+	// contracts are the names of the watched contracts by address.
+	contracts        map[common.Address]string
+	censorshipBlocks uint64
+
+	nextHeight uint64
+	blockRange uint64
+
+	// pending are the pending transactions to the contracts, by hash.
+	pending map[common.Hash]*trackedTx
+
+	// metrics
+	highestBlockNumber   *prometheus.GaugeVec
+	pendingTransactions  *prometheus.GaugeVec
+	censoredTransactions *prometheus.GaugeVec
+	maxRoomyBlocks       *prometheus.GaugeVec
+	censorships          *prometheus.CounterVec
+	unexpectedRpcErrors  *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating censorship monitor...")
+
+	client, err := ethclient.Dial(cfg.NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial node: %w", err)
+	}
+
+	startingHeight, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest block number: %w", err)
+	}
+
+	contracts := make(map[common.Address]string, len(cfg.Contracts))
+	for _, contract := range cfg.Contracts {
+		contracts[contract.Address] = contract.Name
+		log.Info("configured contract", "name", contract.Name, "address", contract.Address)
+	}
+
+	return &Monitor{
+		log: log,
+
+		client: client,
+
+		contracts:        contracts,
+		censorshipBlocks: cfg.CensorshipBlocks,
+
+		nextHeight: startingHeight + 1,
+		blockRange: cfg.BlockRange,
+
+		pending: make(map[common.Hash]*trackedTx),
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed heights (checked and known)",
+		}, []string{"type"}),
+		pendingTransactions: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "pendingTransactions",
+			Help:      "number of executable transactions to the contract pending in the mempool",
+		}, []string{"contract"}),
+		censoredTransactions: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "censoredTransactions",
+			Help:      "number of pending transactions to the contract left out of too many blocks with room for them",
+		}, []string{"contract"}),
+		maxRoomyBlocks: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "maxRoomyBlocks",
+			Help:      "max number of blocks with room for a pending transaction to the contract which didn't include it",
+		}, []string{"contract"}),
+		censorships: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "censorships",
+			Help:      "number of transactions to the contract reported as censored",
+		}, []string{"contract"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestHeight, err := m.client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("node", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestHeight))
+
+	// the blocks are checked before refreshing the mempool, a transaction included meanwhile is then no longer tracked.
+	if m.nextHeight <= latestHeight {
+		toHeight := min(latestHeight, m.nextHeight+m.blockRange-1)
+		for height := m.nextHeight; height <= toHeight; height++ {
+			header, err := m.client.HeaderByNumber(ctx, new(big.Int).SetUint64(height))
+			if err != nil {
+				m.log.Error("failed to query the header", "height", height, "err", err)
+				m.unexpectedRpcErrors.WithLabelValues("node", "headerByNumber").Inc()
+				return
+			}
+			for _, t := range m.pending {
+				if censored := t.observe(header, m.censorshipBlocks); censored && !t.censored {
+					t.censored = true
+					m.censorships.WithLabelValues(t.contract).Inc()
+					m.log.Error("transaction censored", "contract", t.contract, "tx", t.tx.Hash, "from", t.tx.From, "nonce", uint64(t.tx.Nonce), "first_seen", t.firstSeen, "roomy_blocks", t.roomyBlocks, "block", height)
+					m.Update(ctx, censoredAlert(t, fmt.Sprintf("transaction %s to %s from %s pending since block %d, %d blocks having had room for it", t.tx.Hash, t.contract, t.tx.From, t.firstSeen, t.roomyBlocks)), true)
+				}
+			}
+			m.highestBlockNumber.WithLabelValues("checked").Set(float64(height))
+			m.nextHeight = height + 1
+		}
+	}
+
+	var content txPoolContent
+	if err := m.client.Client().CallContext(ctx, &content, "txpool_content"); err != nil {
+		m.log.Error("failed to query the mempool", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("node", "txpool_content").Inc()
+		return
+	}
+	m.refreshPending(ctx, content)
+
+	pending, censored, maxRoomy := make(map[string]int), make(map[string]int), make(map[string]uint64)
+	for _, t := range m.pending {
+		pending[t.contract]++
+		if t.censored {
+			censored[t.contract]++
+		}
+		maxRoomy[t.contract] = max(maxRoomy[t.contract], t.roomyBlocks)
+	}
+	for _, name := range m.contracts {
+		m.pendingTransactions.WithLabelValues(name).Set(float64(pending[name]))
+		m.censoredTransactions.WithLabelValues(name).Set(float64(censored[name]))
+		m.maxRoomyBlocks.WithLabelValues(name).Set(float64(maxRoomy[name]))
+	}
+}
+
+// refreshPending tracks the new executable transactions to the contracts, first seen at the last checked block, and stops tracking
+// the ones which left the mempool.
+func (m *Monitor) refreshPending(ctx context.Context, content txPoolContent) {
+	current := make(map[common.Hash]bool)
+	for _, txs := range content["pending"] {
+		for _, tx := range txs {
+			if tx.To == nil {
+				continue
+			}
+			contract, ok := m.contracts[*tx.To]
+			if !ok {
+				continue
+			}
+			current[tx.Hash] = true
+			if _, ok := m.pending[tx.Hash]; !ok {
+				m.pending[tx.Hash] = &trackedTx{tx: tx, contract: contract, firstSeen: m.nextHeight - 1}
+				m.log.Info("pending transaction", "contract", contract, "tx", tx.Hash, "from", tx.From, "nonce", uint64(tx.Nonce))
+			}
+		}
+	}
+
+	for hash, t := range m.pending {
+		if current[hash] {
+			continue
+		}
+		if t.censored {
+			m.log.Warn("censored transaction left the mempool", "contract", t.contract, "tx", hash, "roomy_blocks", t.roomyBlocks)
+			m.Update(ctx, censoredAlert(t, fmt.Sprintf("censored transaction %s to %s left the mempool", hash, t.contract)), false)
+		}
+		delete(m.pending, hash)
+	}
+}
+
+// censoredAlert returns the alert of the censored transaction.
+func censoredAlert(t *trackedTx, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     CensoredRule,
+		Priority: "P1",
+		Entity:   t.tx.Hash.String(),
+		Summary:  summary,
+		Labels:   map[string]string{"contract": t.contract, "from": t.tx.From.String()},
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.client.Close()
+	return nil
+}
@@ -0,0 +1,125 @@
+package censorship
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var portal = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+
+// mempoolNode is a node whose mempool holds the pending transactions.
+type mempoolNode struct {
+	*fake.Node
+
+	pending []*poolTx
+}
+
+func newMempoolNode(t *testing.T) *mempoolNode {
+	n := &mempoolNode{Node: fake.NewNode(t)}
+	n.AddBlock(&types.Header{GasLimit: 30_000_000})
+	n.Handle("txpool_content", func(_ []json.RawMessage) (interface{}, error) {
+		content := txPoolContent{"pending": {}, "queued": {}}
+		for _, tx := range n.pending {
+			if content["pending"][tx.From] == nil {
+				content["pending"][tx.From] = make(map[string]*poolTx)
+			}
+			content["pending"][tx.From][hexutil.Uint64(tx.Nonce).String()] = tx
+		}
+		return content, nil
+	})
+	return n
+}
+
+// newTestMonitor returns the monitor of the transactions to the portal censored after 2 blocks with room for them, and the
+// notifier of its alerts.
+func newTestMonitor(t *testing.T, node *mempoolNode) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{NodeURL: node.URL, Contracts: []Contract{{Name: "OptimismPortal", Address: portal}}, CensorshipBlocks: 2, BlockRange: 100}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := newMempoolNode(t)
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	tx := &poolTx{Hash: common.HexToHash("0x01"), From: common.HexToAddress("0x02"), To: &portal, Gas: 100_000, MaxFeePerGas: (*hexutil.Big)(big.NewInt(2e9))}
+	node.pending = []*poolTx{tx}
+	m.Run(ctx)
+	if pending, censored := testutil.ToFloat64(m.pendingTransactions.WithLabelValues("OptimismPortal")), testutil.ToFloat64(m.censoredTransactions.WithLabelValues("OptimismPortal")); pending != 1 || censored != 0 {
+		t.Errorf("expected 1 pending transaction not censored but got %v and %v", pending, censored)
+	}
+
+	// only the blocks with room for the transaction are counted, the censorship once.
+	node.AddBlock(&types.Header{GasLimit: 30_000_000})
+	node.AddBlock(&types.Header{GasLimit: 30_000_000, GasUsed: 30_000_000})
+	m.Run(ctx)
+	if roomy, checked := testutil.ToFloat64(m.maxRoomyBlocks.WithLabelValues("OptimismPortal")), testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("checked")); roomy != 1 || checked != 2 {
+		t.Errorf("expected 1 roomy block out of the 2 checked but got %v and %v", roomy, checked)
+	}
+	node.AddBlock(&types.Header{GasLimit: 30_000_000})
+	m.Run(ctx)
+	node.AddBlock(&types.Header{GasLimit: 30_000_000})
+	m.Run(ctx)
+	if censorships, censored, roomy := testutil.ToFloat64(m.censorships.WithLabelValues("OptimismPortal")), testutil.ToFloat64(m.censoredTransactions.WithLabelValues("OptimismPortal")), testutil.ToFloat64(m.maxRoomyBlocks.WithLabelValues("OptimismPortal")); censorships != 1 || censored != 1 || roomy != 3 {
+		t.Errorf("expected 1 censorship after 3 roomy blocks but got %v, %v and %v", censorships, censored, roomy)
+	}
+
+	// the transaction leaving the mempool is no longer reported.
+	node.pending = nil
+	m.Run(ctx)
+	if pending, censored := testutil.ToFloat64(m.pendingTransactions.WithLabelValues("OptimismPortal")), testutil.ToFloat64(m.censoredTransactions.WithLabelValues("OptimismPortal")); pending != 0 || censored != 0 {
+		t.Errorf("expected no pending transaction but got %v and %v", pending, censored)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newMempoolNode(t)
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	tx := &poolTx{Hash: common.HexToHash("0x01"), From: common.HexToAddress("0x02"), To: &portal, Gas: 100_000, MaxFeePerGas: (*hexutil.Big)(big.NewInt(2e9))}
+	node.pending = []*poolTx{tx}
+	m.Run(ctx)
+
+	// a transaction left out of blocks with room for it fires once.
+	node.AddBlock(&types.Header{GasLimit: 30_000_000})
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert after a single roomy block but got %v", alerts)
+	}
+	node.AddBlock(&types.Header{GasLimit: 30_000_000, GasUsed: 30_000_000})
+	node.AddBlock(&types.Header{GasLimit: 30_000_000})
+	m.Run(ctx)
+	node.AddBlock(&types.Header{GasLimit: 30_000_000})
+	m.Run(ctx)
+	if alerts := notifier.Alerts(CensoredRule); len(alerts) != 1 || alerts[0].Entity != tx.Hash.String() || alerts[0].Labels["contract"] != "OptimismPortal" {
+		t.Fatalf("expected the censored alert once but got %v", alerts)
+	}
+
+	// the censored transaction leaving the mempool resolves the alert.
+	node.pending = nil
+	m.Run(ctx)
+	if firing := notifier.Firing(CensoredRule); len(firing) != 0 || len(m.pending) != 0 {
+		t.Fatalf("expected the censored alert resolved but got %v", firing)
+	}
+}
@@ -0,0 +1,62 @@
+package censorship
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// poolTx is the subset of a transaction of the mempool used by the monitor.
+type poolTx struct {
+	Hash         common.Hash     `json:"hash"`
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasPrice     *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas *hexutil.Big    `json:"maxFeePerGas"`
+}
+
+// txPoolContent is the result of `txpool_content`, the transactions by pool (pending, queued), account and nonce.
+type txPoolContent map[string]map[common.Address]map[string]*poolTx
+
+// trackedTx is a pending transaction to a protocol contract along with the blocks which could have included it.
+type trackedTx struct {
+	tx       *poolTx
+	contract string
+
+	// firstSeen is the latest block when the transaction was first seen pending, only the next blocks are checked.
+	firstSeen uint64
+
+	// roomyBlocks is the number of blocks with room for the transaction since it was first seen.
+	roomyBlocks uint64
+	censored    bool
+}
+
+// hasRoom returns whether the block could have included the transaction: enough gas left and a fee cap paying the base fee.
+// A legacy transaction pays its gas price as fee cap.
+func hasRoom(header *types.Header, tx *poolTx) bool {
+	if header.GasLimit < header.GasUsed || header.GasLimit-header.GasUsed < uint64(tx.Gas) {
+		return false
+	}
+
+	feeCap := tx.MaxFeePerGas
+	if feeCap == nil {
+		feeCap = tx.GasPrice
+	}
+	if header.BaseFee != nil && (feeCap == nil || feeCap.ToInt().Cmp(header.BaseFee) < 0) {
+		return false
+	}
+	return true
+}
+
+// observe counts the block when it is after the transaction was first seen and had room for it, and returns whether the transaction
+// is now censored: left out of at least `censorshipBlocks` blocks with room for it.
+func (t *trackedTx) observe(header *types.Header, censorshipBlocks uint64) bool {
+	if header.Number.Cmp(new(big.Int).SetUint64(t.firstSeen)) > 0 && hasRoom(header, t.tx) {
+		t.roomyBlocks++
+	}
+	return t.roomyBlocks >= censorshipBlocks
+}
@@ -0,0 +1,62 @@
+package censorship
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestHasRoom(t *testing.T) {
+	dynamic := &poolTx{Gas: 100_000, MaxFeePerGas: (*hexutil.Big)(big.NewInt(10))}
+	legacy := &poolTx{Gas: 100_000, GasPrice: (*hexutil.Big)(big.NewInt(10))}
+
+	tests := []struct {
+		name     string
+		header   *types.Header
+		tx       *poolTx
+		expected bool
+	}{
+		{name: "empty block", header: &types.Header{GasLimit: 30_000_000, BaseFee: big.NewInt(5)}, tx: dynamic, expected: true},
+		{name: "full block", header: &types.Header{GasLimit: 30_000_000, GasUsed: 29_950_000, BaseFee: big.NewInt(5)}, tx: dynamic, expected: false},
+		{name: "exactly enough gas", header: &types.Header{GasLimit: 30_000_000, GasUsed: 29_900_000, BaseFee: big.NewInt(5)}, tx: dynamic, expected: true},
+		{name: "fee cap below the base fee", header: &types.Header{GasLimit: 30_000_000, BaseFee: big.NewInt(11)}, tx: dynamic, expected: false},
+		{name: "legacy gas price", header: &types.Header{GasLimit: 30_000_000, BaseFee: big.NewInt(10)}, tx: legacy, expected: true},
+		{name: "no fee", header: &types.Header{GasLimit: 30_000_000, BaseFee: big.NewInt(1)}, tx: &poolTx{Gas: 21_000}, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := hasRoom(test.header, test.tx); got != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestObserve(t *testing.T) {
+	tx := &poolTx{Gas: 21_000, MaxFeePerGas: (*hexutil.Big)(big.NewInt(10))}
+	tracked := &trackedTx{tx: tx, firstSeen: 100}
+
+	roomy := func(number int64) *types.Header {
+		return &types.Header{Number: big.NewInt(number), GasLimit: 30_000_000, BaseFee: big.NewInt(1)}
+	}
+	full := func(number int64) *types.Header {
+		return &types.Header{Number: big.NewInt(number), GasLimit: 30_000_000, GasUsed: 30_000_000, BaseFee: big.NewInt(1)}
+	}
+
+	// the block where the transaction was first seen is not counted.
+	if tracked.observe(roomy(100), 2) || tracked.roomyBlocks != 0 {
+		t.Fatalf("expected the first seen block to be ignored but got %d roomy blocks", tracked.roomyBlocks)
+	}
+	if tracked.observe(roomy(101), 2) {
+		t.Fatalf("expected the transaction not to be censored after a single roomy block")
+	}
+	if tracked.observe(full(102), 2) {
+		t.Fatalf("expected a full block not to be counted")
+	}
+	if !tracked.observe(roomy(103), 2) || tracked.roomyBlocks != 2 {
+		t.Fatalf("expected the transaction to be censored after 2 roomy blocks but got %d", tracked.roomyBlocks)
+	}
+}
@@ -0,0 +1,93 @@
+package challenger
+
+import (
+	"fmt"
+	"time"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName     = "l1.node.url"
+	RollupNodeURLFlagName = "rollup.node.url"
+
+	DisputeGameFactoryAddressFlagName = "disputegamefactory.address"
+	ChallengerAddressFlagName         = "challenger.address"
+	GameWindowFlagName                = "game.window"
+)
+
+type CLIConfig struct {
+	L1NodeURL     string
+	RollupNodeURL string
+
+	DisputeGameFactoryAddress common.Address
+	ChallengerAddress         common.Address
+
+	// GameWindow is the age of the oldest games monitored, the older games are ignored.
+	GameWindow time.Duration
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:     ctx.String(L1NodeURLFlagName),
+		RollupNodeURL: ctx.String(RollupNodeURLFlagName),
+		GameWindow:    ctx.Duration(GameWindowFlagName),
+	}
+
+	factoryAddress := ctx.String(DisputeGameFactoryAddressFlagName)
+	if !common.IsHexAddress(factoryAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", DisputeGameFactoryAddressFlagName)
+	}
+	cfg.DisputeGameFactoryAddress = common.HexToAddress(factoryAddress)
+
+	challengerAddress := ctx.String(ChallengerAddressFlagName)
+	if !common.IsHexAddress(challengerAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", ChallengerAddressFlagName)
+	}
+	cfg.ChallengerAddress = common.HexToAddress(challengerAddress)
+
+	if cfg.GameWindow <= 0 {
+		return cfg, fmt.Errorf("--%s must be positive", GameWindowFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    RollupNodeURLFlagName,
+			Usage:   "Node URL of a trusted rollup node used to find the games with an invalid root claim",
+			Value:   "127.0.0.1:7545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "ROLLUP_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     DisputeGameFactoryAddressFlagName,
+			Usage:    "Address of the DisputeGameFactory contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "DISPUTE_GAME_FACTORY"),
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     ChallengerAddressFlagName,
+			Usage:    "Address of the challenger",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "CHALLENGER"),
+			Required: true,
+		},
+		&cli.DurationFlag{
+			Name:    GameWindowFlagName,
+			Usage:   "Age of the oldest games monitored, the credits of the challenger are tracked for the games within this window",
+			Value:   28 * 24 * time.Hour,
+			EnvVars: opservice.PrefixEnvVar(envVar, "GAME_WINDOW"),
+		},
+	}
+}
@@ -0,0 +1,374 @@
+package challenger
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "challenger"
+	MetricsNamespace = "challenger_mon"
+
+	// UncontestedRule is the rule of the alert emitted while the challenger didn't contest an invalid game in progress.
+	UncontestedRule = "invalid game not contested"
+
+	// GameStatusInProgress is the status of a dispute game that is not resolved.
+	GameStatusInProgress = 0
+
+	// CounterRootPosition is the generalized index of a claim countering the root claim.
+	CounterRootPosition = 2
+)
+
+// game is a dispute game tracked by the monitor.
+type game struct {
+	address   common.Address
+	caller    *bindings.FaultDisputeGameCaller
+	createdAt uint64
+
+	rootClaim     common.Hash
+	l2BlockNumber *big.Int
+	invalid       *bool // `nil` until the root claim is compared against the trusted rollup node.
+
+	status uint8
+
+	// claims made by the challenger, the claims of a game are append-only.
+	claimsRead uint64
+	moves      uint64
+	bonds      *big.Int
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client     *ethclient.Client
+	rollupClient client.RPC
+
+	factory    *bindings.DisputeGameFactoryCaller
+	challenger common.Address
+	gameWindow uint64
+
+	games       map[common.Address]*game
+	nextIndex   uint64
+	counterBond *big.Int // bond of a move countering a root claim, `nil` until a game is tracked.
+
+	// metrics
+	gamesInProgress         prometheus.Gauge
+	movesMade               prometheus.Gauge
+	bondsPosted             prometheus.Gauge
+	claimCredit             prometheus.Gauge
+	balance                 prometheus.Gauge
+	runway                  prometheus.Gauge
+	uncontestedInvalidGames prometheus.Gauge
+	unexpectedRpcErrors     *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating challenger monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	rollupClient, err := client.NewRPC(ctx, log, cfg.RollupNodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial the rollup node: %w", err)
+	}
+
+	factory, err := bindings.NewDisputeGameFactoryCaller(cfg.DisputeGameFactoryAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the DisputeGameFactory: %w", err)
+	}
+
+	log.Info("configured challenger", "address", cfg.ChallengerAddress, "game_window", cfg.GameWindow)
+	monitor := &Monitor{
+		log: log,
+
+		l1Client:     l1Client,
+		rollupClient: rollupClient,
+
+		factory:    factory,
+		challenger: cfg.ChallengerAddress,
+		gameWindow: uint64(cfg.GameWindow / time.Second),
+
+		games: make(map[common.Address]*game),
+
+		gamesInProgress: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "gamesInProgress",
+			Help:      "Number of dispute games in progress within the game window",
+		}),
+		movesMade: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "movesMade",
+			Help:      "Number of moves made by the challenger in the games in progress",
+		}),
+		bondsPosted: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "bondsPosted",
+			Help:      "Bonds in ETH posted by the challenger in the games in progress",
+		}),
+		claimCredit: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "claimCredit",
+			Help:      "Credit in ETH of the challenger awaiting withdrawal in the games within the game window",
+		}),
+		balance: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "balance",
+			Help:      "ETH balance of the challenger",
+		}),
+		runway: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "runway",
+			Help:      "Number of moves countering a root claim the challenger can afford with its balance",
+		}),
+		uncontestedInvalidGames: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "uncontestedInvalidGames",
+			Help:      "Number of games in progress with an invalid root claim where the challenger made no move",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}
+
+	gameCount, err := factory.GameCount(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the game count: %w", err)
+	}
+	nextIndex, err := monitor.findFirstGameInWindow(ctx, gameCount.Uint64())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the first game within the window: %w", err)
+	}
+	log.Info("configured starting game index", "index", nextIndex, "game_count", gameCount)
+	monitor.nextIndex = nextIndex
+	return monitor, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	header, err := m.l1Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		m.log.Error("failed to query latest header", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "headerByNumber").Inc()
+		return
+	}
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: header.Number}
+
+	if err := m.trackNewGames(callOpts); err != nil {
+		m.log.Error("failed to track the new games", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("factory", "gameAtIndex").Inc()
+		return
+	}
+
+	inProgress, moves, uncontested := 0, uint64(0), 0
+	bonds, credit := new(big.Int), new(big.Int)
+	for address, g := range m.games {
+		if g.createdAt+m.gameWindow < header.Time {
+			m.log.Info("game left the window", "game", address)
+			m.Update(ctx, m.uncontestedAlert(g, fmt.Sprintf("game %s left the window", address)), false)
+			delete(m.games, address)
+			continue
+		}
+
+		gameCredit, err := m.updateGame(ctx, callOpts, g)
+		if err != nil {
+			m.log.Error("failed to update the game", "game", address, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("game", "update").Inc()
+			return // the totals would be partial.
+		}
+		credit.Add(credit, gameCredit)
+		contest := g.status == GameStatusInProgress && shouldContest(g.invalid, g.moves)
+		m.Update(ctx, m.uncontestedAlert(g, fmt.Sprintf("challenger did not contest the game %s with the invalid root claim %s at l2 block %d", address, g.rootClaim, g.l2BlockNumber)), contest)
+		if g.status != GameStatusInProgress {
+			continue
+		}
+
+		inProgress++
+		moves += g.moves
+		bonds.Add(bonds, g.bonds)
+		if contest {
+			uncontested++
+			m.log.Error("challenger did not contest an invalid game", "game", address, "root_claim", g.rootClaim, "l2_block_number", g.l2BlockNumber)
+		}
+	}
+
+	m.gamesInProgress.Set(float64(inProgress))
+	m.movesMade.Set(float64(moves))
+	m.bondsPosted.Set(util.WeiToEther(bonds))
+	m.claimCredit.Set(util.WeiToEther(credit))
+	m.uncontestedInvalidGames.Set(float64(uncontested))
+
+	balance, err := m.l1Client.BalanceAt(ctx, m.challenger, header.Number)
+	if err != nil {
+		m.log.Error("failed to query the balance of the challenger", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "balanceAt").Inc()
+		return
+	}
+	m.balance.Set(util.WeiToEther(balance))
+	if m.counterBond != nil && m.counterBond.Sign() > 0 {
+		m.runway.Set(float64(new(big.Int).Div(balance, m.counterBond).Uint64()))
+	}
+
+	m.log.Info("checked challenger", "games_in_progress", inProgress, "moves", moves, "bonds", bonds, "credit", credit, "balance", balance, "uncontested_invalid_games", uncontested)
+}
+
+// uncontestedAlert returns the alert of the game not contested by the challenger.
+func (m *Monitor) uncontestedAlert(g *game, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     UncontestedRule,
+		Priority: "P0",
+		Entity:   g.address.String(),
+		Summary:  summary,
+		Labels:   map[string]string{"challenger": m.challenger.String()},
+	}
+}
+
+// trackNewGames tracks the games created since the previous iteration.
+func (m *Monitor) trackNewGames(callOpts *bind.CallOpts) error {
+	gameCount, err := m.factory.GameCount(callOpts)
+	if err != nil {
+		return fmt.Errorf("failed to query the game count: %w", err)
+	}
+
+	for ; m.nextIndex < gameCount.Uint64(); m.nextIndex++ {
+		gameAtIndex, err := m.factory.GameAtIndex(callOpts, new(big.Int).SetUint64(m.nextIndex))
+		if err != nil {
+			return fmt.Errorf("failed to query the game at index %d: %w", m.nextIndex, err)
+		}
+		caller, err := bindings.NewFaultDisputeGameCaller(gameAtIndex.Proxy, m.l1Client)
+		if err != nil {
+			return fmt.Errorf("failed to bind to the game %s: %w", gameAtIndex.Proxy, err)
+		}
+		rootClaim, err := caller.RootClaim(callOpts)
+		if err != nil {
+			return fmt.Errorf("failed to query the root claim of %s: %w", gameAtIndex.Proxy, err)
+		}
+		extraData, err := caller.ExtraData(callOpts)
+		if err != nil {
+			return fmt.Errorf("failed to query the extra data of %s: %w", gameAtIndex.Proxy, err)
+		}
+		if m.counterBond == nil {
+			if m.counterBond, err = caller.GetRequiredBond(callOpts, big.NewInt(CounterRootPosition)); err != nil {
+				return fmt.Errorf("failed to query the required bond of %s: %w", gameAtIndex.Proxy, err)
+			}
+		}
+
+		m.games[gameAtIndex.Proxy] = &game{
+			address:       gameAtIndex.Proxy,
+			caller:        caller,
+			createdAt:     gameAtIndex.Timestamp,
+			rootClaim:     rootClaim,
+			l2BlockNumber: new(big.Int).SetBytes(extraData[:min(len(extraData), 32)]),
+			bonds:         new(big.Int),
+		}
+		m.log.Info("tracking new game", "index", m.nextIndex, "game", gameAtIndex.Proxy, "game_type", gameAtIndex.GameType, "root_claim", common.Hash(rootClaim))
+	}
+	return nil
+}
+
+// updateGame reads the status of the game, the new claims made by the challenger and returns its credit in the game.
+func (m *Monitor) updateGame(ctx context.Context, callOpts *bind.CallOpts, g *game) (*big.Int, error) {
+	status, err := g.caller.Status(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the status: %w", err)
+	}
+	g.status = status
+
+	credit, err := g.caller.Credit(callOpts, m.challenger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the credit: %w", err)
+	}
+	if status != GameStatusInProgress {
+		return credit, nil
+	}
+
+	claims, err := g.caller.ClaimDataLen(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the number of claims: %w", err)
+	}
+	for ; g.claimsRead < claims.Uint64(); g.claimsRead++ {
+		claim, err := g.caller.ClaimData(callOpts, new(big.Int).SetUint64(g.claimsRead))
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the claim %d: %w", g.claimsRead, err)
+		}
+		if claim.Claimant == m.challenger {
+			g.moves++
+			g.bonds.Add(g.bonds, claim.Bond)
+		}
+	}
+
+	if g.invalid == nil {
+		var output eth.OutputResponse
+		if err := m.rollupClient.CallContext(ctx, &output, "optimism_outputAtBlock", hexutil.EncodeBig(g.l2BlockNumber)); err != nil {
+			// the rollup node may not be synced yet, retried on the next iteration.
+			m.log.Warn("failed to query the output of the game from the rollup node", "game", g.address, "l2_block_number", g.l2BlockNumber, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("rollup", "outputAtBlock").Inc()
+			return credit, nil
+		}
+		invalid := common.Hash(output.OutputRoot) != g.rootClaim
+		g.invalid = &invalid
+		if invalid {
+			m.log.Warn("game with an invalid root claim", "game", g.address, "root_claim", g.rootClaim, "expected", common.Hash(output.OutputRoot))
+		}
+	}
+	return credit, nil
+}
+
+// findFirstGameInWindow binary searches the index of the first game created within the game window.
+func (m *Monitor) findFirstGameInWindow(ctx context.Context, gameCount uint64) (uint64, error) {
+	callOpts := &bind.CallOpts{Context: ctx}
+	header, err := m.l1Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query latest header: %w", err)
+	}
+
+	low, high := uint64(0), gameCount
+	for low < high {
+		mid := (low + high) / 2
+		gameAtIndex, err := m.factory.GameAtIndex(callOpts, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, fmt.Errorf("failed to query the game at index %d: %w", mid, err)
+		}
+
+		if gameAtIndex.Timestamp+m.gameWindow < header.Time {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	m.rollupClient.Close()
+	return nil
+}
+
+// shouldContest returns true when the root claim of a game in progress is known to be invalid and the challenger made no move.
+func shouldContest(invalid *bool, moves uint64) bool {
+	return invalid != nil && *invalid && moves == 0
+}
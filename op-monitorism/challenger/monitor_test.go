@@ -0,0 +1,171 @@
+package challenger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var (
+	factory        = common.HexToAddress("0xe5965Ab5962eDc7477C8520243A95517CD252fA9")
+	challengerAddr = common.HexToAddress("0x9BA6e03D8B90dE867373Db8cF1A58d2F7F006b3A")
+)
+
+// gameNode serves a dispute game of the factory along with its claims, and the output roots of the rollup node.
+type gameNode struct {
+	*fake.Node
+
+	game       *fake.Contract
+	claimants  []common.Address
+	outputRoot common.Hash
+}
+
+// newGameNode returns the node of a game created now at the address with the root claim at the l2 block 100.
+func newGameNode(t *testing.T, address common.Address, rootClaim common.Hash) *gameNode {
+	n := &gameNode{Node: fake.NewNode(t)}
+	now := uint64(time.Now().Unix())
+	n.AddBlock(&types.Header{Time: now})
+	n.Result("eth_getBalance", (*hexutil.Big)(big.NewInt(1e18)))
+	n.Handle("optimism_outputAtBlock", func(_ []json.RawMessage) (interface{}, error) {
+		return eth.OutputResponse{OutputRoot: eth.Bytes32(n.outputRoot)}, nil
+	})
+
+	n.Contract(factory, util.MustParseABI(bindings.DisputeGameFactoryMetaData)).
+		Returns("gameCount", big.NewInt(1)).
+		Returns("gameAtIndex", uint32(0), now, address)
+	n.game = n.Contract(address, util.MustParseABI(bindings.FaultDisputeGameMetaData)).
+		Returns("rootClaim", [32]byte(rootClaim)).
+		Returns("extraData", common.BigToHash(big.NewInt(100)).Bytes()).
+		Returns("getRequiredBond", big.NewInt(1e17)).
+		Returns("status", uint8(GameStatusInProgress)).
+		Returns("credit", new(big.Int)).
+		Handle("claimDataLen", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{big.NewInt(int64(len(n.claimants)))}, nil
+		}).
+		Handle("claimData", func(args []interface{}) ([]interface{}, error) {
+			claimant := n.claimants[args[0].(*big.Int).Int64()]
+			return []interface{}{uint32(0), common.Address{}, claimant, big.NewInt(1e17), [32]byte{}, big.NewInt(1), new(big.Int)}, nil
+		})
+	n.claimants = []common.Address{common.HexToAddress("0x01")}
+	return n
+}
+
+// newTestMonitor returns the monitor of the challenger within a day of games of the node, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *gameNode) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{L1NodeURL: node.URL, RollupNodeURL: node.URL, DisputeGameFactoryAddress: factory, ChallengerAddress: challengerAddr, GameWindow: 24 * time.Hour}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestShouldContest(t *testing.T) {
+	valid, invalid := false, true
+	tests := []struct {
+		name     string
+		invalid  *bool
+		moves    uint64
+		expected bool
+	}{
+		{name: "Unknown validity", invalid: nil, moves: 0, expected: false},
+		{name: "Valid root claim", invalid: &valid, moves: 0, expected: false},
+		{name: "Invalid root claim contested", invalid: &invalid, moves: 2, expected: false},
+		{name: "Invalid root claim not contested", invalid: &invalid, moves: 0, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := shouldContest(test.invalid, test.moves)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	game := common.HexToAddress("0x0100000000000000000000000000000000000001")
+	node := newGameNode(t, game, common.HexToHash("0x01"))
+	node.outputRoot = common.HexToHash("0x01")
+	node.claimants = append(node.claimants, challengerAddr)
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// the game in progress is reported with the move of the challenger and its bond, the balance covering 10 counter bonds.
+	m.Run(ctx)
+	if inProgress, moves := testutil.ToFloat64(m.gamesInProgress), testutil.ToFloat64(m.movesMade); inProgress != 1 || moves != 1 {
+		t.Errorf("expected 1 game in progress with 1 move but got %v games and %v moves", inProgress, moves)
+	}
+	if bonds := testutil.ToFloat64(m.bondsPosted); bonds != 0.1 {
+		t.Errorf("expected 0.1 ETH of bonds but got %v", bonds)
+	}
+	if balance, runway := testutil.ToFloat64(m.balance), testutil.ToFloat64(m.runway); balance != 1 || runway != 10 {
+		t.Errorf("expected a balance of 1 ETH and a runway of 10 moves but got %v and %v", balance, runway)
+	}
+	if uncontested := testutil.ToFloat64(m.uncontestedInvalidGames); uncontested != 0 {
+		t.Errorf("expected no uncontested invalid game but got %v", uncontested)
+	}
+
+	// a failed balance query is counted, the metrics of the games being kept.
+	node.Fail("eth_getBalance", errors.New("connection refused"))
+	m.Run(ctx)
+	if failures := testutil.ToFloat64(m.unexpectedRpcErrors.WithLabelValues("l1", "balanceAt")); failures != 1 {
+		t.Errorf("expected 1 failed balance query but got %v", failures)
+	}
+	if inProgress := testutil.ToFloat64(m.gamesInProgress); inProgress != 1 {
+		t.Errorf("expected 1 game in progress but got %v", inProgress)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	game := common.HexToAddress("0x0100000000000000000000000000000000000001")
+	node := newGameNode(t, game, common.HexToHash("0x02"))
+	node.outputRoot = common.HexToHash("0x01")
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// an invalid game without a move of the challenger fires once, and resolves once countered.
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(UncontestedRule); len(alerts) != 1 || alerts[0].Entity != game.String() || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the uncontested alert once but got %v", alerts)
+	}
+	node.claimants = append(node.claimants, challengerAddr)
+	m.Run(ctx)
+	if firing := notifier.Firing(UncontestedRule); len(firing) != 0 {
+		t.Fatalf("expected the uncontested alert resolved but got %v", firing)
+	}
+
+	// an uncontested game leaving the window resolves the alert.
+	m.games[game].moves = 0
+	m.Run(ctx)
+	if firing := notifier.Firing(UncontestedRule); len(firing) != 1 {
+		t.Fatalf("expected the uncontested alert but got %v", firing)
+	}
+	m.games[game].createdAt = 0
+	m.Run(ctx)
+	if firing := notifier.Firing(UncontestedRule); len(firing) != 0 || len(m.games) != 0 {
+		t.Fatalf("expected the uncontested alert resolved once the game left the window but got %v", firing)
+	}
+}
@@ -3,15 +3,57 @@ package main
 import (
 	"context"
 	"fmt"
+	"text/tabwriter"
+	"time"
 
 	monitorism "github.com/ethereum-optimism/monitorism/op-monitorism"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/anchor_state"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/archive"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/balances"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/batches"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/blobs"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/bridge_supply"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/censorship"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/challenger"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/codehash"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/conductor"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/conservation"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/delayed_weth"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/deposits"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/drippie"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/fault"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/finalization"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/game_registry"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/gas_oracle"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/global_events"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/hardforks"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/heartbeat"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/interop"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/liveness_expiration"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/messages"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/mint_burn"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/multisig"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/nft_bridge"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/nonces"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/output_verifier"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/p2p"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/pause"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/preimages"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/price_feeds"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/proposer"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/protocol_versions"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/proxy_admin"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/replicas"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/roles"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/rpc_health"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/runway"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/safes"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/secrets"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/signer"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/storage"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/timelock"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/unsafe_reorgs"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/withdrawals"
 	"github.com/ethereum-optimism/optimism/op-service/cliapp"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
@@ -89,7 +131,307 @@ func newCli(GitCommit string, GitDate string) *cli.App {
 				Usage:       "Monitor the liveness expiration on Gnosis Safe.",
 				Description: "Monitor the liveness expiration on Gnosis Safe.",
 				Flags:       append(liveness_expiration.CLIFlags("LIVENESS_EXPIRATION_MON"), defaultFlags...),
-				Action:      cliapp.LifecycleCmd(LivenessExpirationMain),
+				Action:      LivenessExpirationAction,
+			},
+			{
+				Name:        "challenger",
+				Usage:       "Monitors the participation of the challenger in the dispute games",
+				Description: "Monitors the participation of the challenger in the dispute games",
+				Flags:       append(challenger.CLIFlags("CHALLENGER_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(ChallengerMain),
+			},
+			{
+				Name:        "proposer",
+				Usage:       "Monitors the cadence, the gas costs and the balance of the proposer",
+				Description: "Monitors the cadence, the gas costs and the balance of the proposer",
+				Flags:       append(proposer.CLIFlags("PROPOSER_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(ProposerMain),
+			},
+			{
+				Name:        "deposits",
+				Usage:       "Monitors the relay of the deposits from L1 to L2",
+				Description: "Monitors the relay of the deposits from L1 to L2",
+				Flags:       append(deposits.CLIFlags("DEPOSIT_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(DepositsMain),
+			},
+			{
+				Name:        "bridge_supply",
+				Usage:       "Monitors the L2 supply of the bridged tokens against their L1 collateral",
+				Description: "Monitors the L2 supply of the bridged tokens against their L1 collateral",
+				Flags:       append(bridge_supply.CLIFlags("BRIDGE_SUPPLY_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(BridgeSupplyMain),
+			},
+			{
+				Name:        "protocol_versions",
+				Usage:       "Monitors the required and recommended protocol versions against the managed nodes",
+				Description: "Monitors the required and recommended protocol versions against the managed nodes",
+				Flags:       append(protocol_versions.CLIFlags("PROTOCOL_VERSIONS_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(ProtocolVersionsMain),
+			},
+			{
+				Name:        "timelock",
+				Usage:       "Monitors the operations queued in a timelock",
+				Description: "Monitors the operations queued in a timelock",
+				Flags:       append(timelock.CLIFlags("TIMELOCK_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(TimelockMain),
+			},
+			{
+				Name:        "gas_oracle",
+				Usage:       "Monitors the fee parameters of the L1Block and the GasPriceOracle",
+				Description: "Monitors the fee parameters of the L1Block and the GasPriceOracle",
+				Flags:       append(gas_oracle.CLIFlags("GAS_ORACLE_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(GasOracleMain),
+			},
+			{
+				Name:        "conservation",
+				Usage:       "Monitors the conservation of the ETH moved through the OptimismPortal",
+				Description: "Monitors the conservation of the ETH moved through the OptimismPortal",
+				Flags:       append(conservation.CLIFlags("CONSERVATION_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(ConservationMain),
+			},
+			{
+				Name:        "heartbeat",
+				Usage:       "Monitors the inclusion of self-transfers sent at regular interval",
+				Description: "Monitors the inclusion of self-transfers sent at regular interval",
+				Flags:       append(heartbeat.CLIFlags("HEARTBEAT_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(HeartbeatMain),
+			},
+			{
+				Name:        "rpc_health",
+				Usage:       "Monitors the availability, the latency and the capabilities of RPC endpoints",
+				Description: "Monitors the availability, the latency and the capabilities of RPC endpoints",
+				Flags:       append(rpc_health.CLIFlags("RPC_HEALTH_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(RpcHealthMain),
+			},
+			{
+				Name:        "p2p",
+				Usage:       "Monitors the peers, the gossip scores and the bandwidth of op-nodes",
+				Description: "Monitors the peers, the gossip scores and the bandwidth of op-nodes",
+				Flags:       append(p2p.CLIFlags("P2P_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(P2PMain),
+			},
+			{
+				Name:        "batches",
+				Usage:       "Monitors the validity of the data posted by the batcher",
+				Description: "Monitors the validity of the data posted by the batcher",
+				Flags:       append(batches.CLIFlags("BATCHES_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(BatchesMain),
+			},
+			{
+				Name:        "blobs",
+				Usage:       "Monitors the availability of the blobs posted by the batcher",
+				Description: "Monitors the availability of the blobs posted by the batcher",
+				Flags:       append(blobs.CLIFlags("BLOBS_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(BlobsMain),
+			},
+			{
+				Name:        "hardforks",
+				Usage:       "Monitors the readiness of the op-nodes for the upcoming hardforks",
+				Description: "Monitors the readiness of the op-nodes for the upcoming hardforks",
+				Flags:       append(hardforks.CLIFlags("HARDFORKS_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(HardforksMain),
+			},
+			{
+				Name:        "mint_burn",
+				Usage:       "Monitors the mints and burns of the bridged tokens against the standard bridge",
+				Description: "Monitors the mints and burns of the bridged tokens against the standard bridge",
+				Flags:       append(mint_burn.CLIFlags("MINT_BURN_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(MintBurnMain),
+			},
+			{
+				Name:        "messages",
+				Usage:       "Monitors the relay latency of the messages sent from L1 to L2",
+				Description: "Monitors the relay latency of the messages sent from L1 to L2",
+				Flags:       append(messages.CLIFlags("MESSAGES_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(MessagesMain),
+			},
+			{
+				Name:        "finalization",
+				Usage:       "Monitors the finalization of the proven withdrawals",
+				Description: "Monitors the finalization of the proven withdrawals",
+				Flags:       append(finalization.CLIFlags("FINALIZATION_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(FinalizationMain),
+			},
+			{
+				Name:        "game_registry",
+				Usage:       "Monitors the dispute game implementations and the roles of the permissioned games",
+				Description: "Monitors the dispute game implementations and the roles of the permissioned games",
+				Flags:       append(game_registry.CLIFlags("GAME_REGISTRY_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(GameRegistryMain),
+			},
+			{
+				Name:        "roles",
+				Usage:       "Monitors the privileged roles of the protocol contracts",
+				Description: "Monitors the privileged roles of the protocol contracts",
+				Flags:       append(roles.CLIFlags("ROLES_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(RolesMain),
+			},
+			{
+				Name:        "proxy_admin",
+				Usage:       "Monitors the ownership of the ProxyAdmin and the upgrades of its proxies",
+				Description: "Monitors the ownership of the ProxyAdmin and the upgrades of its proxies",
+				Flags:       append(proxy_admin.CLIFlags("PROXY_ADMIN_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(ProxyAdminMain),
+			},
+			{
+				Name:        "codehash",
+				Usage:       "Monitors the codehashes of the contracts against a manifest",
+				Description: "Monitors the codehashes of the contracts against a manifest",
+				Flags:       append(codehash.CLIFlags("CODEHASH_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(CodeHashMain),
+			},
+			{
+				Name:        "nonces",
+				Usage:       "Monitors the nonces and the stuck transactions of the operational accounts",
+				Description: "Monitors the nonces and the stuck transactions of the operational accounts",
+				Flags:       append(nonces.CLIFlags("NONCES_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(NoncesMain),
+			},
+			{
+				Name:        "preimages",
+				Usage:       "Monitors the large preimage proposals of the PreimageOracle",
+				Description: "Monitors the large preimage proposals of the PreimageOracle",
+				Flags:       append(preimages.CLIFlags("PREIMAGES_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(PreimagesMain),
+			},
+			{
+				Name:        "anchor_state",
+				Usage:       "Monitors the anchor roots of the AnchorStateRegistry",
+				Description: "Monitors the anchor roots of the AnchorStateRegistry",
+				Flags:       append(anchor_state.CLIFlags("ANCHOR_STATE_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(AnchorStateMain),
+			},
+			{
+				Name:        "delayed_weth",
+				Usage:       "Monitors the withdrawals of the dispute game bonds and the recovery actions of the DelayedWETH",
+				Description: "Monitors the withdrawals of the dispute game bonds and the recovery actions of the DelayedWETH",
+				Flags:       append(delayed_weth.CLIFlags("DELAYED_WETH_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(DelayedWETHMain),
+			},
+			{
+				Name:        "interop",
+				Usage:       "Monitors the dependency set of an interop chain and the validity of its executing messages",
+				Description: "Monitors the dependency set of an interop chain and the validity of its executing messages",
+				Flags:       append(interop.CLIFlags("INTEROP_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(InteropMain),
+			},
+			{
+				Name:        "safes",
+				Usage:       "Monitors the owners, threshold, modules, guard and fallback handler of the safes of the organization against an expected state",
+				Description: "Monitors the owners, threshold, modules, guard and fallback handler of the safes of the organization against an expected state",
+				Flags:       append(safes.CLIFlags("SAFES_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(SafesMain),
+			},
+			{
+				Name:        "signer",
+				Usage:       "Monitors the availability of the external signers by signing a test payload",
+				Description: "Monitors the availability of the external signers by signing a test payload",
+				Flags:       append(signer.CLIFlags("SIGNER_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(SignerMain),
+			},
+			{
+				Name:        "storage",
+				Usage:       "Monitors invariants of storage slots without events",
+				Description: "Monitors invariants of storage slots without events",
+				Flags:       append(storage.CLIFlags("STORAGE_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(StorageMain),
+			},
+			{
+				Name:        "price_feeds",
+				Usage:       "Monitors the staleness and the answers of Chainlink-style price feeds",
+				Description: "Monitors the staleness and the answers of Chainlink-style price feeds",
+				Flags:       append(price_feeds.CLIFlags("PRICE_FEEDS_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(PriceFeedsMain),
+			},
+			{
+				Name:        "censorship",
+				Usage:       "Monitors the pending transactions to the protocol contracts left out of blocks with room for them",
+				Description: "Monitors the pending transactions to the protocol contracts left out of blocks with room for them",
+				Flags:       append(censorship.CLIFlags("CENSORSHIP_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(CensorshipMain),
+			},
+			{
+				Name:        "replicas",
+				Usage:       "Monitors the execution consistency of the nodes of a chain",
+				Description: "Monitors the execution consistency of the nodes of a chain",
+				Flags:       append(replicas.CLIFlags("REPLICAS_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(ReplicasMain),
+			},
+			{
+				Name:        "archive",
+				Usage:       "Monitors the historical data availability of archive nodes",
+				Description: "Monitors the historical data availability of archive nodes",
+				Flags:       append(archive.CLIFlags("ARCHIVE_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(ArchiveMain),
+			},
+			{
+				Name:        "output_verifier",
+				Usage:       "Recomputes the proposed output roots from verified proofs of a trusted L2 archive node",
+				Description: "Recomputes the proposed output roots from verified proofs of a trusted L2 archive node",
+				Flags:       append(output_verifier.CLIFlags("OUTPUT_VERIFIER_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(OutputVerifierMain),
+			},
+			{
+				Name:        "runway",
+				Usage:       "Forecasts the runway of the faucets and Drippie-funded accounts",
+				Description: "Forecasts the runway of the faucets and Drippie-funded accounts",
+				Flags:       append(runway.CLIFlags("RUNWAY_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(RunwayMain),
+			},
+			{
+				Name:        "conductor",
+				Usage:       "Monitors the leadership and the membership of an op-conductor cluster",
+				Description: "Monitors the leadership and the membership of an op-conductor cluster",
+				Flags:       append(conductor.CLIFlags("CONDUCTOR_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(ConductorMain),
+			},
+			{
+				Name:        "unsafe_reorgs",
+				Usage:       "Monitors the reorgs of the unsafe L2 chain",
+				Description: "Monitors the reorgs of the unsafe L2 chain",
+				Flags:       append(unsafe_reorgs.CLIFlags("UNSAFE_REORGS_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(UnsafeReorgsMain),
+			},
+			{
+				Name:        "pause",
+				Usage:       "Verifies the effects of the pause by simulating the guarded actions",
+				Description: "Verifies the effects of the pause by simulating the guarded actions",
+				Flags:       append(pause.CLIFlags("PAUSE_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(PauseMain),
+			},
+			{
+				Name:        "nft_bridge",
+				Usage:       "Monitors the consistency of the NFTs bridged through the ERC-721 bridges",
+				Description: "Monitors the consistency of the NFTs bridged through the ERC-721 bridges",
+				Flags:       append(nft_bridge.CLIFlags("NFT_BRIDGE_MON"), defaultFlags...),
+				Action:      cliapp.LifecycleCmd(NftBridgeMain),
+			},
+			{
+				Name:        "alerts",
+				Usage:       "Lists, acknowledges and resolves the active alerts of a monitor via its API",
+				Description: "Lists, acknowledges and resolves the active alerts of a monitor via its API",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "Lists the active alerts",
+						Flags:  alerts.APIClientFlags(EnvVarPrefix),
+						Action: AlertsListAction,
+					},
+					{
+						Name:      "ack",
+						Usage:     "Acknowledges an active alert, stopping its re-notifications and its escalation",
+						ArgsUsage: "<id>",
+						Flags:     alerts.APIClientFlags(EnvVarPrefix),
+						Action:    AlertsAckAction,
+					},
+					{
+						Name:      "resolve",
+						Usage:     "Resolves an active alert",
+						ArgsUsage: "<id>",
+						Flags:     alerts.APIClientFlags(EnvVarPrefix),
+						Action:    AlertsResolveAction,
+					},
+				},
 			},
 			{
 				Name:        "version",
@@ -104,6 +446,87 @@ func newCli(GitCommit string, GitDate string) *cli.App {
 	}
 }
 
+func alertsAPIClient(ctx *cli.Context) *alerts.APIClient {
+	return alerts.NewAPIClient(ctx.String(alerts.APIURLFlagName), ctx.String(alerts.APIClientTokenFlagName), 10*time.Second)
+}
+
+// AlertsListAction prints the active alerts of the monitor, the oldest first.
+func AlertsListAction(ctx *cli.Context) error {
+	active, err := alertsAPIClient(ctx).Active(ctx.Context)
+	if err != nil {
+		return fmt.Errorf("failed to list the active alerts: %w", err)
+	}
+
+	w := tabwriter.NewWriter(ctx.App.Writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSINCE\tSTATUS\tALERT\tENTITY")
+	for _, a := range active {
+		status := "firing"
+		switch {
+		case len(a.AcknowledgedBy) > 0:
+			status = "acknowledged by " + a.AcknowledgedBy
+		case a.Escalated:
+			status = "escalated"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.ID, a.Since.UTC().Format(time.RFC3339), status, a.Alert.Title(), a.Alert.Entity)
+	}
+	return w.Flush()
+}
+
+// AlertsAckAction acknowledges the active alert of the ID.
+func AlertsAckAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 || len(ctx.String(alerts.ByFlagName)) == 0 {
+		return fmt.Errorf("expected the id of the alert and --%s", alerts.ByFlagName)
+	}
+	if err := alertsAPIClient(ctx).Acknowledge(ctx.Context, ctx.Args().First(), ctx.String(alerts.ByFlagName), ctx.String(alerts.CommentFlagName)); err != nil {
+		return fmt.Errorf("failed to acknowledge the alert: %w", err)
+	}
+	return nil
+}
+
+// AlertsResolveAction resolves the active alert of the ID.
+func AlertsResolveAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 || len(ctx.String(alerts.ByFlagName)) == 0 {
+		return fmt.Errorf("expected the id of the alert and --%s", alerts.ByFlagName)
+	}
+	if err := alertsAPIClient(ctx).Resolve(ctx.Context, ctx.Args().First(), ctx.String(alerts.ByFlagName), ctx.String(alerts.CommentFlagName)); err != nil {
+		return fmt.Errorf("failed to resolve the alert: %w", err)
+	}
+	return nil
+}
+
+// LivenessExpirationAction runs a single evaluation when `--one-shot` is set, the monitoring loop otherwise.
+func LivenessExpirationAction(ctx *cli.Context) error {
+	if ctx.Bool(liveness_expiration.OneShotFlagName) {
+		return LivenessExpirationOneShot(ctx)
+	}
+	return cliapp.LifecycleCmd(LivenessExpirationMain)(ctx)
+}
+
+// LivenessExpirationOneShot evaluates the safes once and exits with the resulting status as exit code.
+func LivenessExpirationOneShot(ctx *cli.Context) error {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := liveness_expiration.ReadCLIFlags(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to parse LivenessExpiration config from flags: %w", err)
+	}
+
+	monitor, err := liveness_expiration.NewMonitor(ctx.Context, log, opmetrics.With(opmetrics.NewRegistry()), cfg)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("failed to create LivenessExpiration monitor: %s", err), int(liveness_expiration.StatusFailure))
+	}
+	defer monitor.Close(ctx.Context)
+
+	status, err := monitor.RunOnce(ctx.Context)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("failed to evaluate the safes: %s", err), int(status))
+	}
+	log.Info("one-shot evaluation done", "status", status)
+	if status != liveness_expiration.StatusOK {
+		return cli.Exit("", int(status))
+	}
+	return nil
+}
+
 func LivenessExpirationMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
 	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
 	cfg, err := liveness_expiration.ReadCLIFlags(ctx)
@@ -229,3 +652,627 @@ func SecretsMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lif
 
 	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
 }
+
+func ChallengerMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := challenger.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse challenger config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := challenger.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create challenger monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func ProposerMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := proposer.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proposer config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := proposer.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create proposer monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func DepositsMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := deposits.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse deposits config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := deposits.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deposits monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func BridgeSupplyMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := bridge_supply.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bridge_supply config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := bridge_supply.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bridge_supply monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func ProtocolVersionsMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := protocol_versions.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse protocol_versions config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := protocol_versions.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create protocol_versions monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func TimelockMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := timelock.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timelock config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := timelock.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create timelock monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func GasOracleMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := gas_oracle.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gas_oracle config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := gas_oracle.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gas_oracle monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func ConservationMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := conservation.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse conservation config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := conservation.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create conservation monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func HeartbeatMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := heartbeat.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse heartbeat config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := heartbeat.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create heartbeat monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func RpcHealthMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := rpc_health.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rpc_health config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := rpc_health.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rpc_health monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func P2PMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := p2p.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse p2p config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := p2p.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create p2p monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func BatchesMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := batches.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse batches config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := batches.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batches monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func BlobsMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := blobs.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse blobs config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := blobs.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blobs monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func HardforksMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := hardforks.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse hardforks config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := hardforks.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create hardforks monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func MintBurnMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := mint_burn.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse mint_burn config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := mint_burn.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mint_burn monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func MessagesMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := messages.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse messages config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := messages.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create messages monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func FinalizationMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := finalization.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse finalization config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := finalization.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create finalization monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func GameRegistryMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := game_registry.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse game_registry config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := game_registry.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create game_registry monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func RolesMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := roles.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse roles config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := roles.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create roles monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func ProxyAdminMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := proxy_admin.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proxy_admin config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := proxy_admin.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create proxy_admin monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func CodeHashMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := codehash.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse codehash config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := codehash.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create codehash monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func NoncesMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := nonces.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse nonces config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := nonces.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nonces monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func PreimagesMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := preimages.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse preimages config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := preimages.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create preimages monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func AnchorStateMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := anchor_state.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse anchor_state config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := anchor_state.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create anchor_state monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func DelayedWETHMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := delayed_weth.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse delayed_weth config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := delayed_weth.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create delayed_weth monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func InteropMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := interop.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse interop config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := interop.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create interop monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func SafesMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := safes.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse safes config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := safes.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create safes monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func SignerMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := signer.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signer config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := signer.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func StorageMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := storage.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse storage config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := storage.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func PriceFeedsMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := price_feeds.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse price_feeds config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := price_feeds.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create price_feeds monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func CensorshipMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := censorship.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse censorship config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := censorship.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create censorship monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func ReplicasMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := replicas.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse replicas config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := replicas.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replicas monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func ArchiveMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := archive.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse archive config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := archive.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func OutputVerifierMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := output_verifier.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse output_verifier config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := output_verifier.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output_verifier monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func RunwayMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := runway.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse runway config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := runway.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runway monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func ConductorMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := conductor.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse conductor config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := conductor.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create conductor monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func UnsafeReorgsMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := unsafe_reorgs.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse unsafe_reorgs config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := unsafe_reorgs.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create unsafe_reorgs monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func PauseMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := pause.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pause config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := pause.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pause monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
+
+func NftBridgeMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	cfg, err := nft_bridge.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse nft_bridge config from flags: %w", err)
+	}
+
+	metricsRegistry := opmetrics.NewRegistry()
+	monitor, err := nft_bridge.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nft_bridge monitor: %w", err)
+	}
+
+	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+}
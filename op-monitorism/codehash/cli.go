@@ -0,0 +1,79 @@
+package codehash
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodeURLFlagName           = "node.url"
+	ManifestFlagName          = "manifest"
+	SuperchainChainIDFlagName = "superchain.chain.id"
+)
+
+type CLIConfig struct {
+	NodeURL string
+
+	// Contracts are the contracts of the manifest, then the ones of the superchain registry.
+	Contracts []Contract
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{NodeURL: ctx.String(NodeURLFlagName)}
+
+	if path := ctx.String(ManifestFlagName); len(path) > 0 {
+		manifest, err := ReadManifestFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Contracts = manifest.Contracts
+	}
+
+	if ctx.IsSet(SuperchainChainIDFlagName) {
+		registry, err := superchainContracts(ctx.Uint64(SuperchainChainIDFlagName))
+		if err != nil {
+			return cfg, fmt.Errorf("--%s: %w", SuperchainChainIDFlagName, err)
+		}
+		cfg.Contracts = mergeContracts(cfg.Contracts, registry)
+	}
+
+	if len(cfg.Contracts) == 0 {
+		return cfg, fmt.Errorf("no contract configured, --%s or --%s must be set", ManifestFlagName, SuperchainChainIDFlagName)
+	}
+	names := make(map[string]bool)
+	for _, contract := range cfg.Contracts {
+		if len(contract.Name) == 0 {
+			return cfg, fmt.Errorf("the contract %s has no name", contract.Address)
+		}
+		if names[contract.Name] {
+			return cfg, fmt.Errorf("duplicated contract %s", contract.Name)
+		}
+		names[contract.Name] = true
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    NodeURLFlagName,
+			Usage:   "Node URL of the chain of the contracts",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    ManifestFlagName,
+			Usage:   "Path to a YAML file with the contracts and their expected codehashes",
+			EnvVars: opservice.PrefixEnvVar(envVar, "MANIFEST"),
+		},
+		&cli.Uint64Flag{
+			Name:    SuperchainChainIDFlagName,
+			Usage:   "Chain ID of an OP chain of the superchain registry whose L1 contracts are verified in addition to the manifest",
+			EnvVars: opservice.PrefixEnvVar(envVar, "SUPERCHAIN_CHAIN_ID"),
+		},
+	}
+}
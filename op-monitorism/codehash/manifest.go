@@ -0,0 +1,113 @@
+package codehash
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum-optimism/superchain-registry/superchain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// Contract is a contract along with its expected codehash.
+type Contract struct {
+	Name    string         `yaml:"name"`
+	Address common.Address `yaml:"address"`
+
+	// CodeHash is the expected codehash, the first one observed is used when not set.
+	CodeHash common.Hash `yaml:"codehash"`
+}
+
+// Manifest is the content of the YAML file given with `--manifest`.
+//
+//	contracts:
+//	  - name: OptimismPortal
+//	    address: 0xe2F826324b2faf99E513D16D266c3F80aE87832B
+//	    codehash: <keccak256 of the runtime bytecode>
+//	  - name: OptimismPortalProxy
+//	    address: 0xbEb5Fc579115071764c7423A4f12eDde41f106Ed
+type Manifest struct {
+	Contracts []Contract `yaml:"contracts"`
+}
+
+// ReadManifestFile reads the contracts and their expected codehashes from a YAML file.
+func ReadManifestFile(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to read the manifest %s: %w", path, err)
+	}
+
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("failed to parse the manifest %s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+// superchainContracts returns the L1 contracts of the chain from the superchain registry: the proxies, the ProxyAdmin,
+// the AddressManager and the implementations of the approved contract versions of its superchain.
+// The registry has no codehash, the codehashes are not set.
+func superchainContracts(chainID uint64) ([]Contract, error) {
+	chain, ok := superchain.OPChains[chainID]
+	if !ok {
+		return nil, fmt.Errorf("unknown chain %d in the superchain registry", chainID)
+	}
+	addresses, ok := superchain.Addresses[chainID]
+	if !ok {
+		return nil, fmt.Errorf("no addresses for the chain %d in the superchain registry", chainID)
+	}
+
+	contracts := []Contract{
+		{Name: "AddressManager", Address: common.Address(addresses.AddressManager)},
+		{Name: "ProxyAdmin", Address: common.Address(addresses.ProxyAdmin)},
+		{Name: "L1CrossDomainMessengerProxy", Address: common.Address(addresses.L1CrossDomainMessengerProxy)},
+		{Name: "L1ERC721BridgeProxy", Address: common.Address(addresses.L1ERC721BridgeProxy)},
+		{Name: "L1StandardBridgeProxy", Address: common.Address(addresses.L1StandardBridgeProxy)},
+		{Name: "L2OutputOracleProxy", Address: common.Address(addresses.L2OutputOracleProxy)},
+		{Name: "OptimismMintableERC20FactoryProxy", Address: common.Address(addresses.OptimismMintableERC20FactoryProxy)},
+		{Name: "OptimismPortalProxy", Address: common.Address(addresses.OptimismPortalProxy)},
+		{Name: "SystemConfigProxy", Address: common.Address(addresses.SystemConfigProxy)},
+	}
+
+	if sc, ok := superchain.Superchains[chain.Superchain]; ok {
+		implementations, err := superchain.Implementations[sc.Config.L1.ChainID].Resolve(superchain.SuperchainSemver[chain.Superchain])
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the implementations of the superchain %s: %w", chain.Superchain, err)
+		}
+		contracts = append(contracts,
+			Contract{Name: "L1CrossDomainMessenger", Address: common.Address(implementations.L1CrossDomainMessenger.Address)},
+			Contract{Name: "L1ERC721Bridge", Address: common.Address(implementations.L1ERC721Bridge.Address)},
+			Contract{Name: "L1StandardBridge", Address: common.Address(implementations.L1StandardBridge.Address)},
+			Contract{Name: "L2OutputOracle", Address: common.Address(implementations.L2OutputOracle.Address)},
+			Contract{Name: "OptimismMintableERC20Factory", Address: common.Address(implementations.OptimismMintableERC20Factory.Address)},
+			Contract{Name: "OptimismPortal", Address: common.Address(implementations.OptimismPortal.Address)},
+			Contract{Name: "SystemConfig", Address: common.Address(implementations.SystemConfig.Address)},
+		)
+	}
+
+	// The contracts not deployed by the chain are left unset in the registry.
+	filtered := contracts[:0]
+	for _, contract := range contracts {
+		if contract.Address != (common.Address{}) {
+			filtered = append(filtered, contract)
+		}
+	}
+	return filtered, nil
+}
+
+// mergeContracts merges the contracts of the registry into the ones of the manifest by address, the manifest wins.
+func mergeContracts(manifest []Contract, registry []Contract) []Contract {
+	merged := append([]Contract{}, manifest...)
+	known := make(map[common.Address]bool, len(manifest))
+	for _, contract := range manifest {
+		known[contract.Address] = true
+	}
+	for _, contract := range registry {
+		if !known[contract.Address] {
+			merged = append(merged, contract)
+			known[contract.Address] = true
+		}
+	}
+	return merged
+}
@@ -0,0 +1,56 @@
+package codehash
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSuperchainContracts(t *testing.T) {
+	contracts, err := superchainContracts(10)
+	if err != nil {
+		t.Fatalf("failed to read the contracts of OP Mainnet: %v", err)
+	}
+
+	portal := common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+	names := make(map[string]common.Address)
+	for _, contract := range contracts {
+		if contract.Address == (common.Address{}) {
+			t.Errorf("Failed %s: expected an address", contract.Name)
+		}
+		if contract.CodeHash != (common.Hash{}) {
+			t.Errorf("Failed %s: expected no codehash but got %s", contract.Name, contract.CodeHash)
+		}
+		names[contract.Name] = contract.Address
+	}
+	if names["OptimismPortalProxy"] != portal {
+		t.Errorf("Failed OptimismPortalProxy: expected %s but got %s", portal, names["OptimismPortalProxy"])
+	}
+	if _, ok := names["OptimismPortal"]; !ok {
+		t.Errorf("Failed OptimismPortal: expected the implementation of the approved version")
+	}
+
+	if _, err := superchainContracts(0); err == nil {
+		t.Errorf("Failed unknown chain: expected an error")
+	}
+}
+
+func TestMergeContracts(t *testing.T) {
+	pinned := common.HexToHash("0x01")
+	manifest := []Contract{{Name: "Portal", Address: common.HexToAddress("0x1"), CodeHash: pinned}}
+	registry := []Contract{
+		{Name: "OptimismPortalProxy", Address: common.HexToAddress("0x1")},
+		{Name: "SystemConfigProxy", Address: common.HexToAddress("0x2")},
+	}
+
+	merged := mergeContracts(manifest, registry)
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 contracts but got %d", len(merged))
+	}
+	if merged[0].Name != "Portal" || merged[0].CodeHash != pinned {
+		t.Errorf("Failed manifest: expected the contract of the manifest to win but got %v", merged[0])
+	}
+	if merged[1].Name != "SystemConfigProxy" {
+		t.Errorf("Failed registry: expected SystemConfigProxy but got %s", merged[1].Name)
+	}
+}
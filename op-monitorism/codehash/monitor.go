@@ -0,0 +1,185 @@
+package codehash
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "codehash"
+	MetricsNamespace = "codehash_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the contract.
+	EmptyCodeRule       = "contract without code"
+	CodeHashDriftRule   = "codehash drifted"
+	CodeHashChangedRule = "codehash changed"
+)
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	client *ethclient.Client
+
+	contracts []Contract
+
+	// codeHashes are the codehashes observed at the previous iteration, by contract index.
+	codeHashes map[int]common.Hash
+
+	// metrics
+	codeHash            *prometheus.GaugeVec
+	codeHashMismatch    *prometheus.GaugeVec
+	emptyCode           *prometheus.GaugeVec
+	codeHashChanges     *prometheus.CounterVec
+	driftedContracts    prometheus.Gauge
+	checkedBlockNumber  prometheus.Gauge
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating codehash monitor...")
+
+	client, err := ethclient.Dial(cfg.NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial node: %w", err)
+	}
+
+	for _, contract := range cfg.Contracts {
+		log.Info("configured contract", "name", contract.Name, "address", contract.Address, "codehash", contract.CodeHash)
+	}
+
+	return &Monitor{
+		log: log,
+
+		client: client,
+
+		contracts:  cfg.Contracts,
+		codeHashes: make(map[int]common.Hash),
+
+		codeHash: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "codeHash",
+			Help:      "codehash of the contract",
+		}, []string{"contract", "address", "codehash"}),
+		codeHashMismatch: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "codeHashMismatch",
+			Help:      "1 if the codehash of the contract is not the expected one, 0 otherwise",
+		}, []string{"contract"}),
+		emptyCode: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "emptyCode",
+			Help:      "1 if the contract has no code (selfdestructed or not deployed), 0 otherwise",
+		}, []string{"contract"}),
+		codeHashChanges: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "codeHashChanges",
+			Help:      "number of changes of the codehash of the contract observed between two iterations",
+		}, []string{"contract"}),
+		driftedContracts: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "driftedContracts",
+			Help:      "number of contracts whose codehash is not the expected one",
+		}),
+		checkedBlockNumber: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "checkedBlockNumber",
+			Help:      "block height at which the codehashes were last verified",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	blockNumber, err := m.client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("node", "blockNumber").Inc()
+		return
+	}
+
+	drifted := 0
+	for i := range m.contracts {
+		contract := &m.contracts[i]
+		code, err := m.client.CodeAt(ctx, contract.Address, new(big.Int).SetUint64(blockNumber))
+		if err != nil {
+			m.log.Error("failed to query the code of the contract", "contract", contract.Name, "address", contract.Address, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("node", "CodeAt").Inc()
+			continue
+		}
+		codeHash := crypto.Keccak256Hash(code)
+
+		previous, known := m.codeHashes[i]
+		if known && previous != codeHash {
+			m.log.Warn("codehash of the contract changed", "contract", contract.Name, "address", contract.Address, "previous", previous, "codehash", codeHash, "block", blockNumber)
+			m.codeHashChanges.WithLabelValues(contract.Name).Inc()
+			m.Emit(ctx, alert(CodeHashChangedRule, "P1", contract, fmt.Sprintf("codehash of %s changed from %s to %s at block %d", contract.Name, previous, codeHash, blockNumber)))
+			m.codeHash.DeleteLabelValues(contract.Name, contract.Address.String(), previous.String())
+		}
+		m.codeHashes[i] = codeHash
+		m.codeHash.WithLabelValues(contract.Name, contract.Address.String(), codeHash.String()).Set(1)
+
+		// An empty code is never used as reference, the contract is expected to be deployed.
+		m.emptyCode.WithLabelValues(contract.Name).Set(util.BoolToFloat(len(code) == 0))
+		m.Update(ctx, alert(EmptyCodeRule, "P0", contract, fmt.Sprintf("%s at %s has no code", contract.Name, contract.Address)), len(code) == 0)
+		if len(code) == 0 {
+			m.log.Error("the contract has no code!", "contract", contract.Name, "address", contract.Address)
+		} else if contract.CodeHash == (common.Hash{}) {
+			m.log.Info("no codehash configured, the current one is used as reference", "contract", contract.Name, "address", contract.Address, "codehash", codeHash)
+			contract.CodeHash = codeHash
+		}
+
+		mismatch := isDrift(contract.CodeHash, code)
+		if mismatch {
+			drifted++
+			if len(code) > 0 {
+				m.log.Error("the codehash of the contract drifted!", "contract", contract.Name, "address", contract.Address, "expected_codehash", contract.CodeHash, "codehash", codeHash)
+			}
+		}
+		m.codeHashMismatch.WithLabelValues(contract.Name).Set(util.BoolToFloat(mismatch))
+		m.Update(ctx, alert(CodeHashDriftRule, "P0", contract, fmt.Sprintf("codehash of %s at %s is %s, expected %s", contract.Name, contract.Address, codeHash, contract.CodeHash)), mismatch)
+	}
+
+	m.driftedContracts.Set(float64(drifted))
+	m.checkedBlockNumber.Set(float64(blockNumber))
+}
+
+// alert returns the alert of the rule about the contract.
+func alert(rule string, priority string, contract *Contract, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   contract.Address.String(),
+		Summary:  summary,
+		Labels:   map[string]string{"contract": contract.Name},
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.client.Close()
+	return nil
+}
+
+// isDrift returns true when the code doesn't match the expected codehash, an empty code always drifts.
+func isDrift(expected common.Hash, code []byte) bool {
+	return len(code) == 0 || crypto.Keccak256Hash(code) != expected
+}
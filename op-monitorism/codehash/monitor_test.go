@@ -0,0 +1,130 @@
+package codehash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var portal = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+
+// codeNode is a node serving the code of the contracts.
+type codeNode struct {
+	*fake.Node
+
+	code map[common.Address][]byte
+}
+
+func newCodeNode(t *testing.T) *codeNode {
+	n := &codeNode{Node: fake.NewNode(t), code: make(map[common.Address][]byte)}
+	n.AddBlock(&types.Header{})
+	n.Handle("eth_getCode", func(params []json.RawMessage) (interface{}, error) {
+		var address common.Address
+		if err := fake.Param(params, 0, &address); err != nil {
+			return nil, err
+		}
+		return hexutil.Bytes(n.code[address]), nil
+	})
+	return n
+}
+
+// newTestMonitor returns the monitor of the contracts of the node, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *codeNode, contracts ...Contract) (*Monitor, *fake.Notifier) {
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), CLIConfig{NodeURL: node.URL, Contracts: contracts})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := newCodeNode(t)
+	node.code[portal] = []byte{0x60, 0x01}
+	m, _ := newTestMonitor(t, node, Contract{Name: "OptimismPortal", Address: portal, CodeHash: crypto.Keccak256Hash([]byte{0x60, 0x01})})
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if drifted, checked := testutil.ToFloat64(m.driftedContracts), testutil.ToFloat64(m.checkedBlockNumber); drifted != 0 || checked != 0 {
+		t.Errorf("expected no drifted contract at the block 0 but got %v and %v", drifted, checked)
+	}
+
+	// a changed code is counted and replaces the codehash series.
+	node.code[portal] = []byte{0x60, 0x02}
+	m.Run(ctx)
+	if changes, mismatch, drifted := testutil.ToFloat64(m.codeHashChanges.WithLabelValues("OptimismPortal")), testutil.ToFloat64(m.codeHashMismatch.WithLabelValues("OptimismPortal")), testutil.ToFloat64(m.driftedContracts); changes != 1 || mismatch != 1 || drifted != 1 {
+		t.Errorf("expected 1 change drifting the contract but got %v, %v and %v", changes, mismatch, drifted)
+	}
+	if count := testutil.CollectAndCount(m.codeHash); count != 1 {
+		t.Errorf("expected the series of the current codehash only but got %d", count)
+	}
+
+	// a contract without code is reported as empty.
+	delete(node.code, portal)
+	m.Run(ctx)
+	if empty := testutil.ToFloat64(m.emptyCode.WithLabelValues("OptimismPortal")); empty != 1 {
+		t.Errorf("expected the empty code reported but got %v", empty)
+	}
+
+	// an rpc error is counted.
+	node.Fail("eth_getCode", errors.New("unavailable"))
+	m.Run(ctx)
+	if errs := testutil.ToFloat64(m.unexpectedRpcErrors.WithLabelValues("node", "CodeAt")); errs != 1 {
+		t.Errorf("expected 1 rpc error but got %v", errs)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newCodeNode(t)
+	node.code[portal] = []byte{0x60, 0x01}
+	m, notifier := newTestMonitor(t, node, Contract{Name: "OptimismPortal", Address: portal, CodeHash: crypto.Keccak256Hash([]byte{0x60, 0x01})})
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a changed code fires the change once and the drift until the expected code is back.
+	node.code[portal] = []byte{0x60, 0x02}
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(CodeHashChangedRule); len(alerts) != 1 || alerts[0].Entity != portal.String() || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the changed codehash alert once but got %v", alerts)
+	}
+	if alerts := notifier.Alerts(CodeHashDriftRule); len(alerts) != 1 || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the drift alert once but got %v", alerts)
+	}
+	node.code[portal] = []byte{0x60, 0x01}
+	m.Run(ctx)
+	if firing := notifier.Firing(CodeHashDriftRule); len(firing) != 0 {
+		t.Fatalf("expected the drift alert resolved but got %v", firing)
+	}
+
+	// a contract without code fires both the empty code and the drift, resolved once deployed.
+	delete(node.code, portal)
+	m.Run(ctx)
+	if firing := notifier.Firing(EmptyCodeRule); len(firing) != 1 || len(notifier.Firing(CodeHashDriftRule)) != 1 {
+		t.Fatalf("expected the empty code and the drift alerts but got %v", firing)
+	}
+	node.code[portal] = []byte{0x60, 0x01}
+	m.Run(ctx)
+	if firing := append(notifier.Firing(EmptyCodeRule), notifier.Firing(CodeHashDriftRule)...); len(firing) != 0 {
+		t.Fatalf("expected the alerts resolved but got %v", firing)
+	}
+}
@@ -0,0 +1,75 @@
+package conductor
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	ConductorsFlagName = "conductors"
+	SequencersFlagName = "sequencers"
+)
+
+// Endpoint is a named RPC endpoint, an op-conductor or the admin RPC of a sequencer op-node.
+type Endpoint struct {
+	Name string
+	URL  string
+}
+
+type CLIConfig struct {
+	Conductors []Endpoint
+	Sequencers []Endpoint
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{}
+
+	var err error
+	if cfg.Conductors, err = parseEndpoints(ctx.StringSlice(ConductorsFlagName), "conductor"); err != nil {
+		return cfg, err
+	}
+	if cfg.Sequencers, err = parseEndpoints(ctx.StringSlice(SequencersFlagName), "sequencer"); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// parseEndpoints parses the `name=url` endpoints, the names being unique.
+func parseEndpoints(values []string, kind string) ([]Endpoint, error) {
+	var endpoints []Endpoint
+	names := make(map[string]bool)
+	for _, value := range values {
+		name, url, err := util.ParseNamedValue(value, "name=url")
+		if err != nil {
+			return nil, err
+		}
+		if names[name] {
+			return nil, fmt.Errorf("duplicated %s %s", kind, name)
+		}
+		names[name] = true
+		endpoints = append(endpoints, Endpoint{Name: name, URL: url})
+	}
+	return endpoints, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     ConductorsFlagName,
+			Usage:    "The op-conductor RPC of every member of the cluster formatted via `name=url`",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "CONDUCTORS"),
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:     SequencersFlagName,
+			Usage:    "The op-node RPC of every sequencer of the cluster, with the admin namespace enabled, formatted via `name=url`",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "SEQUENCERS"),
+			Required: true,
+		},
+	}
+}
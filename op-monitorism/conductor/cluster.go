@@ -0,0 +1,75 @@
+package conductor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// serverInfo is a member of the raft cluster, as returned by `conductor_leaderWithID` and `conductor_clusterMembership`.
+type serverInfo struct {
+	ID       string `json:"id"`
+	Addr     string `json:"addr"`
+	Suffrage int    `json:"suffrage"` // 0 for a voter, 1 for a non-voter.
+}
+
+// conductorStatus is the state of the cluster as seen by a reachable op-conductor.
+type conductorStatus struct {
+	name     string
+	leader   bool
+	leaderID string
+	members  []serverInfo
+}
+
+// clusterCheck is the health of the cluster derived from the reachable op-conductors and sequencers.
+type clusterCheck struct {
+	leaders          int
+	activeSequencers int
+
+	// splitBrain is true when two conductors claim the leadership, or two sequencers are active.
+	splitBrain bool
+	// noLeader is true when no reachable conductor claims the leadership.
+	noLeader bool
+	// noActiveSequencer is true when no reachable sequencer is active.
+	noActiveSequencer bool
+	// leaderDisagreement is true when the conductors don't report the same leader.
+	leaderDisagreement bool
+	// membershipMismatch is true when the conductors don't report the same cluster membership.
+	membershipMismatch bool
+}
+
+func checkCluster(conductors []conductorStatus, sequencersActive []bool) clusterCheck {
+	check := clusterCheck{}
+
+	leaderIDs := make(map[string]bool)
+	memberships := make(map[string]bool)
+	for _, c := range conductors {
+		if c.leader {
+			check.leaders++
+		}
+		leaderIDs[c.leaderID] = true
+		memberships[membershipKey(c.members)] = true
+	}
+	for _, active := range sequencersActive {
+		if active {
+			check.activeSequencers++
+		}
+	}
+
+	check.splitBrain = check.leaders > 1 || check.activeSequencers > 1
+	check.noLeader = check.leaders == 0
+	check.noActiveSequencer = check.activeSequencers == 0
+	check.leaderDisagreement = len(leaderIDs) > 1
+	check.membershipMismatch = len(memberships) > 1
+	return check
+}
+
+// membershipKey returns a canonical representation of the cluster membership, independent of the order of the members.
+func membershipKey(members []serverInfo) string {
+	keys := make([]string, len(members))
+	for i, member := range members {
+		keys[i] = fmt.Sprintf("%s@%s/%d", member.ID, member.Addr, member.Suffrage)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
@@ -0,0 +1,66 @@
+package conductor
+
+import (
+	"testing"
+)
+
+func TestCheckCluster(t *testing.T) {
+	members := []serverInfo{{ID: "a", Addr: "a:50050"}, {ID: "b", Addr: "b:50050"}, {ID: "c", Addr: "c:50050", Suffrage: 1}}
+	reordered := []serverInfo{members[2], members[0], members[1]}
+
+	tests := []struct {
+		name       string
+		conductors []conductorStatus
+		sequencers []bool
+		expected   clusterCheck
+	}{
+		{
+			name: "Healthy",
+			conductors: []conductorStatus{
+				{name: "a", leader: true, leaderID: "a", members: members},
+				{name: "b", leaderID: "a", members: reordered},
+			},
+			sequencers: []bool{true, false},
+			expected:   clusterCheck{leaders: 1, activeSequencers: 1},
+		},
+		{
+			name: "Two leaders",
+			conductors: []conductorStatus{
+				{name: "a", leader: true, leaderID: "a", members: members},
+				{name: "b", leader: true, leaderID: "b", members: members},
+			},
+			sequencers: []bool{true, false},
+			expected:   clusterCheck{leaders: 2, activeSequencers: 1, splitBrain: true, leaderDisagreement: true},
+		},
+		{
+			name: "Two active sequencers",
+			conductors: []conductorStatus{
+				{name: "a", leader: true, leaderID: "a", members: members},
+			},
+			sequencers: []bool{true, true},
+			expected:   clusterCheck{leaders: 1, activeSequencers: 2, splitBrain: true},
+		},
+		{
+			name: "No leader",
+			conductors: []conductorStatus{
+				{name: "a", members: members},
+				{name: "b", members: members[:2]},
+			},
+			sequencers: []bool{false, false},
+			expected:   clusterCheck{noLeader: true, noActiveSequencer: true, membershipMismatch: true},
+		},
+		{
+			name:     "Unreachable cluster",
+			expected: clusterCheck{noLeader: true, noActiveSequencer: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := checkCluster(test.conductors, test.sequencers)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %+v but got %+v", test.name, test.expected, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package conductor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "conductor"
+	MetricsNamespace = "conductor_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the cluster or the unreachable endpoint.
+	SplitBrainRule        = "split-brain"
+	NoLeaderRule          = "no leader"
+	NoActiveSequencerRule = "no active sequencer"
+	DisagreementRule      = "conductors disagree"
+	UnreachableRule       = "endpoint unreachable"
+
+	// ClusterEntity is the entity of the alerts about the whole cluster.
+	ClusterEntity = "cluster"
+)
+
+type endpoint struct {
+	Endpoint
+	client *rpc.Client
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	conductors []*endpoint
+	sequencers []*endpoint
+
+	// metrics
+	up                  *prometheus.GaugeVec
+	leader              *prometheus.GaugeVec
+	active              *prometheus.GaugeVec
+	sequencerHealthy    *prometheus.GaugeVec
+	members             *prometheus.GaugeVec
+	sequencerActive     *prometheus.GaugeVec
+	leaders             prometheus.Gauge
+	activeSequencers    prometheus.Gauge
+	splitBrain          prometheus.Gauge
+	noLeader            prometheus.Gauge
+	noActiveSequencer   prometheus.Gauge
+	leaderDisagreement  prometheus.Gauge
+	membershipMismatch  prometheus.Gauge
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating conductor monitor...")
+
+	dial := func(endpoints []Endpoint, kind string) ([]*endpoint, error) {
+		dialed := make([]*endpoint, len(endpoints))
+		for i, e := range endpoints {
+			client, err := rpc.DialContext(ctx, e.URL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to dial the %s %s: %w", kind, e.Name, err)
+			}
+			dialed[i] = &endpoint{Endpoint: e, client: client}
+			log.Info("configured "+kind, "name", e.Name)
+		}
+		return dialed, nil
+	}
+
+	conductors, err := dial(cfg.Conductors, "conductor")
+	if err != nil {
+		return nil, err
+	}
+	sequencers, err := dial(cfg.Sequencers, "sequencer")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Monitor{
+		log: log,
+
+		conductors: conductors,
+		sequencers: sequencers,
+
+		up: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "up",
+			Help:      "1 if the op-conductor or the sequencer answered during the last iteration",
+		}, []string{"kind", "name"}),
+		leader: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "leader",
+			Help:      "1 if the op-conductor claims the leadership of the cluster",
+		}, []string{"conductor"}),
+		active: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "active",
+			Help:      "1 if the op-conductor is active",
+		}, []string{"conductor"}),
+		sequencerHealthy: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "sequencerHealthy",
+			Help:      "1 if the op-conductor reports its sequencer as healthy",
+		}, []string{"conductor"}),
+		members: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "members",
+			Help:      "number of members of the cluster seen by the op-conductor by suffrage (voter, nonvoter)",
+		}, []string{"conductor", "suffrage"}),
+		sequencerActive: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "sequencerActive",
+			Help:      "1 if the sequencer is actively sequencing",
+		}, []string{"sequencer"}),
+		leaders: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "leaders",
+			Help:      "number of op-conductors claiming the leadership of the cluster",
+		}),
+		activeSequencers: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "activeSequencers",
+			Help:      "number of sequencers actively sequencing",
+		}),
+		splitBrain: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "splitBrain",
+			Help:      "1 if two op-conductors claim the leadership, or two sequencers are active",
+		}),
+		noLeader: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "noLeader",
+			Help:      "1 if no reachable op-conductor claims the leadership",
+		}),
+		noActiveSequencer: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "noActiveSequencer",
+			Help:      "1 if no reachable sequencer is active",
+		}),
+		leaderDisagreement: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "leaderDisagreement",
+			Help:      "1 if the op-conductors don't report the same leader",
+		}),
+		membershipMismatch: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "membershipMismatch",
+			Help:      "1 if the op-conductors don't report the same cluster membership",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	var conductors []conductorStatus
+	for _, c := range m.conductors {
+		status, ok := m.readConductor(ctx, c)
+		m.up.WithLabelValues("conductor", c.Name).Set(util.BoolToFloat(ok))
+		m.Update(ctx, alert(UnreachableRule, "P2", c.Name, fmt.Sprintf("op-conductor %s unreachable", c.Name)), !ok)
+		if ok {
+			conductors = append(conductors, status)
+		}
+	}
+
+	var sequencersActive []bool
+	for _, s := range m.sequencers {
+		var active bool
+		if err := s.client.CallContext(ctx, &active, "admin_sequencerActive"); err != nil {
+			m.log.Error("failed to query the sequencer status", "sequencer", s.Name, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues(s.Name, "admin_sequencerActive").Inc()
+			m.up.WithLabelValues("sequencer", s.Name).Set(0)
+			m.Update(ctx, alert(UnreachableRule, "P2", s.Name, fmt.Sprintf("sequencer %s unreachable", s.Name)), true)
+			continue
+		}
+		m.up.WithLabelValues("sequencer", s.Name).Set(1)
+		m.Update(ctx, alert(UnreachableRule, "P2", s.Name, fmt.Sprintf("sequencer %s unreachable", s.Name)), false)
+		m.sequencerActive.WithLabelValues(s.Name).Set(util.BoolToFloat(active))
+		sequencersActive = append(sequencersActive, active)
+	}
+
+	check := checkCluster(conductors, sequencersActive)
+	if check.splitBrain {
+		m.log.Error("split-brain!!!", "leaders", check.leaders, "active_sequencers", check.activeSequencers)
+	}
+	if check.noLeader {
+		m.log.Error("no leader", "reachable_conductors", len(conductors))
+	}
+	if check.noActiveSequencer {
+		m.log.Error("no active sequencer", "reachable_sequencers", len(sequencersActive))
+	}
+	if check.leaderDisagreement || check.membershipMismatch {
+		m.log.Warn("conductors disagree", "leader", check.leaderDisagreement, "membership", check.membershipMismatch)
+	}
+
+	m.leaders.Set(float64(check.leaders))
+	m.activeSequencers.Set(float64(check.activeSequencers))
+	m.splitBrain.Set(util.BoolToFloat(check.splitBrain))
+	m.noLeader.Set(util.BoolToFloat(check.noLeader))
+	m.noActiveSequencer.Set(util.BoolToFloat(check.noActiveSequencer))
+	m.leaderDisagreement.Set(util.BoolToFloat(check.leaderDisagreement))
+	m.membershipMismatch.Set(util.BoolToFloat(check.membershipMismatch))
+
+	m.Update(ctx, alert(SplitBrainRule, "P0", ClusterEntity, fmt.Sprintf("%d conductors claim the leadership and %d sequencers are active", check.leaders, check.activeSequencers)), check.splitBrain)
+	m.Update(ctx, alert(NoLeaderRule, "P0", ClusterEntity, fmt.Sprintf("none of the %d reachable conductors claims the leadership", len(conductors))), check.noLeader)
+	m.Update(ctx, alert(NoActiveSequencerRule, "P0", ClusterEntity, fmt.Sprintf("none of the %d reachable sequencers is active", len(sequencersActive))), check.noActiveSequencer)
+	m.Update(ctx, alert(DisagreementRule, "P2", ClusterEntity, fmt.Sprintf("conductors disagree on the leader (%t) or the cluster membership (%t)", check.leaderDisagreement, check.membershipMismatch)), check.leaderDisagreement || check.membershipMismatch)
+}
+
+// alert returns the alert of the rule about the cluster or one of its endpoints.
+func alert(rule string, priority string, entity string, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   entity,
+		Summary:  summary,
+	}
+}
+
+// readConductor reads the view of the cluster of the op-conductor, `false` being returned when it's unreachable.
+func (m *Monitor) readConductor(ctx context.Context, c *endpoint) (conductorStatus, bool) {
+	status := conductorStatus{name: c.Name}
+
+	var leaderInfo serverInfo
+	var active, healthy bool
+	calls := []struct {
+		method string
+		result interface{}
+	}{
+		{"conductor_leader", &status.leader},
+		{"conductor_leaderWithID", &leaderInfo},
+		{"conductor_clusterMembership", &status.members},
+		{"conductor_active", &active},
+		{"conductor_sequencerHealthy", &healthy},
+	}
+	for _, call := range calls {
+		if err := c.client.CallContext(ctx, call.result, call.method); err != nil {
+			m.log.Error("failed to query the conductor", "conductor", c.Name, "method", call.method, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues(c.Name, call.method).Inc()
+			return status, false
+		}
+	}
+	status.leaderID = leaderInfo.ID
+
+	voters := 0
+	for _, member := range status.members {
+		if member.Suffrage == 0 {
+			voters++
+		}
+	}
+	m.leader.WithLabelValues(c.Name).Set(util.BoolToFloat(status.leader))
+	m.active.WithLabelValues(c.Name).Set(util.BoolToFloat(active))
+	m.sequencerHealthy.WithLabelValues(c.Name).Set(util.BoolToFloat(healthy))
+	m.members.WithLabelValues(c.Name, "voter").Set(float64(voters))
+	m.members.WithLabelValues(c.Name, "nonvoter").Set(float64(len(status.members) - voters))
+	m.log.Info("checked conductor", "conductor", c.Name, "leader", status.leader, "leader_id", status.leaderID, "members", len(status.members), "active", active, "healthy", healthy)
+	return status, true
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	for _, c := range m.conductors {
+		c.client.Close()
+	}
+	for _, s := range m.sequencers {
+		s.client.Close()
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package conductor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// sequencerNode serves the op-conductor and the sequencer of a member of a cluster of two members, 0 and 1.
+type sequencerNode struct {
+	*fake.Node
+
+	id       string
+	leaderID string
+	active   bool
+	down     bool
+}
+
+func newSequencerNode(t *testing.T, id string) *sequencerNode {
+	n := &sequencerNode{Node: fake.NewNode(t), id: id, leaderID: "0"}
+	serve := func(method string, result func() interface{}) {
+		n.Handle(method, func(_ []json.RawMessage) (interface{}, error) {
+			if n.down {
+				return nil, errors.New("connection refused")
+			}
+			return result(), nil
+		})
+	}
+	serve("conductor_leader", func() interface{} { return n.active })
+	serve("conductor_leaderWithID", func() interface{} { return serverInfo{ID: n.leaderID} })
+	serve("conductor_clusterMembership", func() interface{} { return []serverInfo{{ID: "0"}, {ID: "1"}} })
+	serve("conductor_active", func() interface{} { return true })
+	serve("conductor_sequencerHealthy", func() interface{} { return true })
+	serve("admin_sequencerActive", func() interface{} { return n.active })
+	return n
+}
+
+// newTestMonitor returns the monitor of the cluster of the nodes, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, nodes ...*sequencerNode) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{}
+	for _, n := range nodes {
+		cfg.Conductors = append(cfg.Conductors, Endpoint{Name: "conductor-" + n.id, URL: n.URL})
+		cfg.Sequencers = append(cfg.Sequencers, Endpoint{Name: "sequencer-" + n.id, URL: n.URL})
+	}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	leader, follower := newSequencerNode(t, "0"), newSequencerNode(t, "1")
+	leader.active = true
+	m, _ := newTestMonitor(t, leader, follower)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if leaders, active, splitBrain := testutil.ToFloat64(m.leaders), testutil.ToFloat64(m.activeSequencers), testutil.ToFloat64(m.splitBrain); leaders != 1 || active != 1 || splitBrain != 0 {
+		t.Errorf("expected a single leader and active sequencer but got %v, %v and %v", leaders, active, splitBrain)
+	}
+	if isLeader, voters := testutil.ToFloat64(m.leader.WithLabelValues("conductor-0")), testutil.ToFloat64(m.members.WithLabelValues("conductor-0", "voter")); isLeader != 1 || voters != 2 {
+		t.Errorf("expected the conductor 0 leading 2 voters but got %v and %v", isLeader, voters)
+	}
+
+	// two leaders with active sequencers are reported as a split-brain.
+	follower.active = true
+	m.Run(ctx)
+	if leaders, active, splitBrain := testutil.ToFloat64(m.leaders), testutil.ToFloat64(m.activeSequencers), testutil.ToFloat64(m.splitBrain); leaders != 2 || active != 2 || splitBrain != 1 {
+		t.Errorf("expected the split-brain of 2 leaders and active sequencers but got %v, %v and %v", leaders, active, splitBrain)
+	}
+
+	// an unreachable member is reported down and counted as rpc errors.
+	follower.active, follower.down = false, true
+	m.Run(ctx)
+	if conductor, sequencer := testutil.ToFloat64(m.up.WithLabelValues("conductor", "conductor-1")), testutil.ToFloat64(m.up.WithLabelValues("sequencer", "sequencer-1")); conductor != 0 || sequencer != 0 {
+		t.Errorf("expected the member 1 down but got %v and %v", conductor, sequencer)
+	}
+	if leaderErrs, sequencerErrs := testutil.ToFloat64(m.unexpectedRpcErrors.WithLabelValues("conductor-1", "conductor_leader")), testutil.ToFloat64(m.unexpectedRpcErrors.WithLabelValues("sequencer-1", "admin_sequencerActive")); leaderErrs != 1 || sequencerErrs != 1 {
+		t.Errorf("expected 1 rpc error of the conductor and the sequencer but got %v and %v", leaderErrs, sequencerErrs)
+	}
+
+	// a member seeing another leader is reported as a disagreement.
+	follower.down, follower.leaderID = false, "1"
+	m.Run(ctx)
+	if disagreement, splitBrain := testutil.ToFloat64(m.leaderDisagreement), testutil.ToFloat64(m.splitBrain); disagreement != 1 || splitBrain != 0 {
+		t.Errorf("expected the leader disagreement without split-brain but got %v and %v", disagreement, splitBrain)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	leader, follower := newSequencerNode(t, "0"), newSequencerNode(t, "1")
+	leader.active = true
+	m, notifier := newTestMonitor(t, leader, follower)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// two leaders with active sequencers fire the split-brain once, resolved once a single one is left.
+	follower.active = true
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(SplitBrainRule); len(alerts) != 1 || alerts[0].Entity != ClusterEntity || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the split-brain alert once but got %v", alerts)
+	}
+	follower.active = false
+	m.Run(ctx)
+	if firing := notifier.Firing(SplitBrainRule); len(firing) != 0 {
+		t.Fatalf("expected the split-brain alert resolved but got %v", firing)
+	}
+
+	// an unreachable member fires for its conductor and its sequencer, without a disagreement.
+	follower.down = true
+	m.Run(ctx)
+	if firing := notifier.Firing(UnreachableRule); len(firing) != 2 || len(notifier.Firing(DisagreementRule)) != 0 {
+		t.Fatalf("expected the unreachable alerts but got %v", firing)
+	}
+	follower.down = false
+	m.Run(ctx)
+	if firing := notifier.Firing(UnreachableRule); len(firing) != 0 {
+		t.Fatalf("expected the unreachable alerts resolved but got %v", firing)
+	}
+
+	// a leader lost by all the members fires the no leader and the no active sequencer alerts.
+	leader.active = false
+	m.Run(ctx)
+	if len(notifier.Firing(NoLeaderRule)) != 1 || len(notifier.Firing(NoActiveSequencerRule)) != 1 {
+		t.Fatalf("expected the no leader and no active sequencer alerts but got %v", notifier.Alerts(""))
+	}
+
+	// a member seeing another leader fires the disagreement.
+	leader.active, follower.leaderID = true, "1"
+	m.Run(ctx)
+	if len(notifier.Firing(DisagreementRule)) != 1 || len(notifier.Firing(NoLeaderRule)) != 0 {
+		t.Fatalf("expected the disagreement alert but got %v", notifier.Alerts(""))
+	}
+}
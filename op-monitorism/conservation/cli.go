@@ -0,0 +1,101 @@
+package conservation
+
+import (
+	"fmt"
+	"math/big"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+	L2NodeURLFlagName = "l2.node.url"
+
+	OptimismPortalAddressFlagName = "optimismportal.address"
+	StartBlockHeightFlagName      = "start.block.height"
+	WindowBlockRangeFlagName      = "window.block.range"
+	ToleranceFlagName             = "tolerance"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+	L2NodeURL string
+
+	OptimismPortalAddress common.Address
+	StartBlockHeight      uint64
+	WindowBlockRange      uint64
+
+	// Tolerance is the discrepancy in wei allowed over a window, e.g. for the ETH donated to the portal.
+	Tolerance *big.Int
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:        ctx.String(L1NodeURLFlagName),
+		L2NodeURL:        ctx.String(L2NodeURLFlagName),
+		StartBlockHeight: ctx.Uint64(StartBlockHeightFlagName),
+		WindowBlockRange: ctx.Uint64(WindowBlockRangeFlagName),
+	}
+
+	portalAddress := ctx.String(OptimismPortalAddressFlagName)
+	if !common.IsHexAddress(portalAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", OptimismPortalAddressFlagName)
+	}
+	cfg.OptimismPortalAddress = common.HexToAddress(portalAddress)
+
+	if cfg.WindowBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", WindowBlockRangeFlagName)
+	}
+
+	tolerance, ok := new(big.Int).SetString(ctx.String(ToleranceFlagName), 10)
+	if !ok || tolerance.Sign() < 0 {
+		return cfg, fmt.Errorf("--%s is not a positive amount of wei", ToleranceFlagName)
+	}
+	cfg.Tolerance = tolerance
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    L2NodeURLFlagName,
+			Usage:   "Node URL of L2 peer",
+			Value:   "127.0.0.1:9545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     OptimismPortalAddressFlagName,
+			Usage:    "Address of the OptimismPortal contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "OPTIMISM_PORTAL"),
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:    StartBlockHeightFlagName,
+			Usage:   "Starting height of the first window, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    WindowBlockRangeFlagName,
+			Usage:   "Max number of blocks of a window",
+			Value:   100,
+			EnvVars: opservice.PrefixEnvVar(envVar, "WINDOW_BLOCK_RANGE"),
+		},
+		&cli.StringFlag{
+			Name:    ToleranceFlagName,
+			Usage:   "Discrepancy in wei allowed over a window",
+			Value:   "0",
+			EnvVars: opservice.PrefixEnvVar(envVar, "TOLERANCE"),
+		},
+	}
+}
@@ -0,0 +1,509 @@
+package conservation
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "conservation"
+	MetricsNamespace = "conservation_mon"
+
+	// ViolatedRule is the rule of the alert emitted while the last window violates the invariant, the entity being the OptimismPortal.
+	ViolatedRule = "ETH conservation invariant violated"
+)
+
+// window is the ETH moved through the OptimismPortal over a range of L1 blocks, and the L2 blocks derived from them.
+type window struct {
+	fromBlock uint64
+	toBlock   uint64
+
+	l2FromBlock uint64
+	l2ToBlock   uint64
+
+	deposited *big.Int // sent to the portal by the deposits.
+	withdrawn *big.Int // released on L1 by the successful withdrawals.
+
+	minted    *big.Int // minted on L2 by the deposit transactions.
+	initiated *big.Int // sent to the L2ToL1MessagePasser by the withdrawals initiated on L2.
+
+	// initiatedWithdrawals is the value of the withdrawals initiated on L2 by withdrawal hash, finalizedWithdrawals the
+	// hashes of the withdrawals finalized on L1.
+	initiatedWithdrawals map[common.Hash]*big.Int
+	finalizedWithdrawals []common.Hash
+
+	// undecoded is the number of successful withdrawals whose value is neither known from L2 nor read from the calldata.
+	undecoded int
+}
+
+// supplyDelta is the change of the ETH supply of L2 over the window.
+func (w *window) supplyDelta() *big.Int {
+	return new(big.Int).Sub(w.minted, w.initiated)
+}
+
+// pendingDelta is the change of the ETH of the withdrawals initiated on L2 but not finalized on L1 over the window.
+func (w *window) pendingDelta() *big.Int {
+	return new(big.Int).Sub(w.initiated, w.withdrawn)
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+	l2Client *ethclient.Client
+
+	portalAddress    common.Address
+	portalABI        *abi.ABI
+	messagePasserABI *abi.ABI
+
+	nextL1Height     uint64
+	nextL2Height     uint64
+	windowBlockRange uint64
+	tolerance        *big.Int
+
+	// pendingWithdrawals is the value of the withdrawals initiated on L2 and not finalized on L1 by withdrawal hash.
+	pendingWithdrawals map[common.Hash]*big.Int
+
+	// metrics
+	highestBlockNumber  *prometheus.GaugeVec
+	portalBalance       prometheus.Gauge
+	windowDeposited     prometheus.Gauge
+	windowWithdrawn     prometheus.Gauge
+	windowSupplyDelta   prometheus.Gauge
+	pendingWithdrawn    prometheus.Gauge
+	windowDiscrepancy   prometheus.Gauge
+	invariantViolated   prometheus.Gauge
+	violations          prometheus.Counter
+	skippedWindows      prometheus.Counter
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating conservation monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	l2Client, err := ethclient.Dial(cfg.L2NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l2: %w", err)
+	}
+
+	portalABI, err := bindings.OptimismPortalMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the OptimismPortal ABI: %w", err)
+	}
+	messagePasserABI, err := bindings.L2ToL1MessagePasserMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the L2ToL1MessagePasser ABI: %w", err)
+	}
+
+	startingL1Height := cfg.StartBlockHeight
+	if startingL1Height == 0 {
+		if startingL1Height, err = l1Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+	startingL2Height, err := firstL2Block(ctx, l2Client, startingL1Height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the first L2 block derived from the starting height: %w", err)
+	}
+	log.Info("configured starting height", "height", startingL1Height, "l2_height", startingL2Height, "tolerance", cfg.Tolerance)
+
+	return &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+		l2Client: l2Client,
+
+		portalAddress:    cfg.OptimismPortalAddress,
+		portalABI:        portalABI,
+		messagePasserABI: messagePasserABI,
+
+		nextL1Height:     startingL1Height,
+		nextL2Height:     startingL2Height,
+		windowBlockRange: cfg.WindowBlockRange,
+		tolerance:        cfg.Tolerance,
+
+		pendingWithdrawals: make(map[common.Hash]*big.Int),
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed l1 heights (checked and known)",
+		}, []string{"type"}),
+		portalBalance: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "portalBalance",
+			Help:      "ETH held by the OptimismPortal at the end of the last window",
+		}),
+		windowDeposited: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "windowDeposited",
+			Help:      "ETH sent to the portal by the deposits of the last window",
+		}),
+		windowWithdrawn: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "windowWithdrawn",
+			Help:      "ETH released by the withdrawals finalized in the last window",
+		}),
+		windowSupplyDelta: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "windowSupplyDelta",
+			Help:      "change of the ETH supply of L2 over the last window, minted by the deposits minus withdrawn to L1",
+		}),
+		pendingWithdrawn: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "pendingWithdrawn",
+			Help:      "ETH of the withdrawals initiated on L2 since the start of the monitor and not finalized on L1",
+		}),
+		windowDiscrepancy: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "windowDiscrepancy",
+			Help:      "change of the balance of the OptimismPortal minus the change of the L2 supply and of the pending withdrawals over the last window",
+		}),
+		invariantViolated: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "invariantViolated",
+			Help:      "1 if the discrepancy of the last window exceeds the tolerance, 0 otherwise",
+		}),
+		violations: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "violations",
+			Help:      "number of windows with a discrepancy exceeding the tolerance",
+		}),
+		skippedWindows: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "skippedWindows",
+			Help:      "number of windows not evaluated because of withdrawals whose value is not known",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestL1Height))
+
+	if m.nextL1Height > latestL1Height {
+		return
+	}
+
+	toBlock := min(latestL1Height, m.nextL1Height+m.windowBlockRange-1)
+	w, err := m.readL2Window(ctx, m.nextL1Height, toBlock)
+	if err != nil {
+		m.log.Error("failed to read the L2 window", "from", m.nextL1Height, "to", toBlock, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "window").Inc()
+		return
+	}
+	if w == nil {
+		m.log.Info("waiting for L2 to be derived past the window", "from", m.nextL1Height)
+		return
+	}
+	if err := m.readL1Window(ctx, w); err != nil {
+		m.log.Error("failed to read the window", "from", w.fromBlock, "to", w.toBlock, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "window").Inc()
+		return
+	}
+
+	before, err := m.l1Client.BalanceAt(ctx, m.portalAddress, new(big.Int).SetUint64(w.fromBlock-1))
+	if err != nil {
+		m.log.Error("failed to query the balance of the portal", "block", w.fromBlock-1, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "balanceAt").Inc()
+		return
+	}
+	after, err := m.l1Client.BalanceAt(ctx, m.portalAddress, new(big.Int).SetUint64(w.toBlock))
+	if err != nil {
+		m.log.Error("failed to query the balance of the portal", "block", w.toBlock, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "balanceAt").Inc()
+		return
+	}
+
+	m.portalBalance.Set(util.WeiToEther(after))
+	m.windowDeposited.Set(util.WeiToEther(w.deposited))
+	m.windowWithdrawn.Set(util.WeiToEther(w.withdrawn))
+	m.windowSupplyDelta.Set(util.WeiToEther(w.supplyDelta()))
+	m.highestBlockNumber.WithLabelValues("checked").Set(float64(w.toBlock))
+	m.nextL1Height, m.nextL2Height = w.toBlock+1, w.l2ToBlock+1
+	for withdrawalHash, value := range w.initiatedWithdrawals {
+		m.pendingWithdrawals[withdrawalHash] = value
+	}
+	for _, withdrawalHash := range w.finalizedWithdrawals {
+		delete(m.pendingWithdrawals, withdrawalHash)
+	}
+	pending := new(big.Int)
+	for _, value := range m.pendingWithdrawals {
+		pending.Add(pending, value)
+	}
+	m.pendingWithdrawn.Set(util.WeiToEther(pending))
+
+	if w.undecoded > 0 {
+		m.log.Warn("window skipped, withdrawals not decoded", "from", w.fromBlock, "to", w.toBlock, "undecoded", w.undecoded)
+		m.skippedWindows.Inc()
+		return
+	}
+
+	diff := discrepancy(before, after, w.supplyDelta(), w.pendingDelta())
+	violated := isViolated(diff, m.tolerance)
+	if violated {
+		m.log.Error("ETH conservation invariant violated", "from", w.fromBlock, "to", w.toBlock, "l2_from", w.l2FromBlock, "l2_to", w.l2ToBlock, "discrepancy", diff, "balance_before", before, "balance_after", after, "deposited", w.deposited, "minted", w.minted, "initiated", w.initiated, "withdrawn", w.withdrawn)
+		m.violations.Inc()
+	}
+	m.windowDiscrepancy.Set(util.WeiToEther(diff))
+	m.invariantViolated.Set(util.BoolToFloat(violated))
+	m.Update(ctx, alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     ViolatedRule,
+		Priority: "P0",
+		Entity:   m.portalAddress.String(),
+		Summary:  fmt.Sprintf("balance of the OptimismPortal from block %d to %d off by %g ETH from the L2 supply and the pending withdrawals", w.fromBlock, w.toBlock, util.WeiToEther(diff)),
+		Labels:   map[string]string{"from": strconv.FormatUint(w.fromBlock, 10), "to": strconv.FormatUint(w.toBlock, 10)},
+	}, violated)
+	m.log.Info("checked window", "from", w.fromBlock, "to", w.toBlock, "l2_from", w.l2FromBlock, "l2_to", w.l2ToBlock, "deposited", w.deposited, "minted", w.minted, "initiated", w.initiated, "withdrawn", w.withdrawn, "discrepancy", diff)
+}
+
+// readL2Window returns the window of the L2 blocks derived from the two L1 blocks (inclusive), with the ETH minted and
+// withdrawn on L2. The window ends at the last L1 block whose L2 blocks are all known, nil when L2 is not derived past
+// the first L1 block yet.
+func (m *Monitor) readL2Window(ctx context.Context, fromBlock uint64, toBlock uint64) (*window, error) {
+	head, err := m.l2Client.BlockByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the latest L2 block: %w", err)
+	}
+	headOrigin, err := l1Origin(head)
+	if err != nil {
+		return nil, err
+	}
+	// the L2 blocks derived from the origin of the head may not all be known yet.
+	if headOrigin <= fromBlock {
+		return nil, nil
+	}
+	toBlock = min(toBlock, headOrigin-1)
+
+	w := &window{
+		fromBlock: fromBlock, toBlock: toBlock, l2FromBlock: m.nextL2Height, l2ToBlock: m.nextL2Height - 1,
+		deposited: new(big.Int), withdrawn: new(big.Int), minted: new(big.Int), initiated: new(big.Int),
+		initiatedWithdrawals: make(map[common.Hash]*big.Int),
+	}
+	for number := m.nextL2Height; ; number++ {
+		block := head
+		if number != head.NumberU64() {
+			if block, err = m.l2Client.BlockByNumber(ctx, new(big.Int).SetUint64(number)); err != nil {
+				return nil, fmt.Errorf("failed to query the L2 block %d: %w", number, err)
+			}
+		}
+		origin, err := l1Origin(block)
+		if err != nil {
+			return nil, err
+		}
+		if origin > toBlock {
+			break
+		}
+		for _, tx := range block.Transactions() {
+			if tx.IsDepositTx() && tx.Mint() != nil {
+				w.minted.Add(w.minted, tx.Mint())
+			}
+		}
+		w.l2ToBlock = number
+	}
+	if w.l2ToBlock < w.l2FromBlock {
+		return w, nil
+	}
+
+	logs, err := m.l2Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(w.l2FromBlock),
+		ToBlock:   new(big.Int).SetUint64(w.l2ToBlock),
+		Addresses: []common.Address{predeploys.L2ToL1MessagePasserAddr},
+		Topics:    [][]common.Hash{{m.messagePasserABI.Events["MessagePassed"].ID}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the MessagePassed events: %w", err)
+	}
+	for _, vLog := range logs {
+		out, err := m.messagePasserABI.Unpack("MessagePassed", vLog.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack the withdrawal %s: %w", vLog.TxHash, err)
+		}
+		value := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+		w.initiated.Add(w.initiated, value)
+		w.initiatedWithdrawals[common.Hash(out[3].([32]byte))] = value
+	}
+	return w, nil
+}
+
+// readL1Window sums the ETH deposited and withdrawn through the portal over the L1 blocks of the window. The value of a
+// finalized withdrawal is the one initiated on L2, or decoded from the calldata when initiated before the monitor started.
+func (m *Monitor) readL1Window(ctx context.Context, w *window) error {
+	logs, err := m.l1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(w.fromBlock),
+		ToBlock:   new(big.Int).SetUint64(w.toBlock),
+		Addresses: []common.Address{m.portalAddress},
+		Topics:    [][]common.Hash{{m.portalABI.Events["TransactionDeposited"].ID, m.portalABI.Events["WithdrawalFinalized"].ID}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the portal events: %w", err)
+	}
+
+	unknown := make(map[common.Hash]int) // number of successful withdrawals of unknown value by transaction.
+	for _, vLog := range logs {
+		switch vLog.Topics[0] {
+		case m.portalABI.Events["TransactionDeposited"].ID:
+			out, err := m.portalABI.Unpack("TransactionDeposited", vLog.Data)
+			if err != nil {
+				return fmt.Errorf("failed to unpack the deposit %s: %w", vLog.TxHash, err)
+			}
+			w.deposited.Add(w.deposited, mintOf(out[0].([]byte)))
+
+		case m.portalABI.Events["WithdrawalFinalized"].ID:
+			out, err := m.portalABI.Unpack("WithdrawalFinalized", vLog.Data)
+			if err != nil {
+				return fmt.Errorf("failed to unpack the withdrawal %s: %w", vLog.TxHash, err)
+			}
+			// the value of a failed withdrawal stays in the portal.
+			if !out[0].(bool) {
+				continue
+			}
+			withdrawalHash := vLog.Topics[1]
+			w.finalizedWithdrawals = append(w.finalizedWithdrawals, withdrawalHash)
+			if value, ok := m.pendingWithdrawals[withdrawalHash]; ok {
+				w.withdrawn.Add(w.withdrawn, value)
+			} else {
+				unknown[vLog.TxHash]++
+			}
+		}
+	}
+
+	for txHash, count := range unknown {
+		tx, _, err := m.l1Client.TransactionByHash(ctx, txHash)
+		if err != nil {
+			return fmt.Errorf("failed to query the transaction %s: %w", txHash, err)
+		}
+		value, ok := m.withdrawalValue(tx)
+		if !ok || count > 1 {
+			m.log.Warn("failed to decode the value of the withdrawal", "tx", txHash, "withdrawals", count)
+			w.undecoded += count
+			continue
+		}
+		w.withdrawn.Add(w.withdrawn, value)
+	}
+	return nil
+}
+
+// withdrawalValue returns the value of the withdrawal finalized by a direct call to `finalizeWithdrawalTransaction`.
+func (m *Monitor) withdrawalValue(tx *types.Transaction) (*big.Int, bool) {
+	method := m.portalABI.Methods["finalizeWithdrawalTransaction"]
+	if tx.To() == nil || *tx.To() != m.portalAddress || len(tx.Data()) < 4 || !bytes.Equal(tx.Data()[:4], method.ID) {
+		return nil, false
+	}
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
+	if err != nil || len(args) != 1 {
+		return nil, false
+	}
+	withdrawal := *abi.ConvertType(args[0], new(bindings.TypesWithdrawalTransaction)).(*bindings.TypesWithdrawalTransaction)
+	return withdrawal.Value, true
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	m.l2Client.Close()
+	return nil
+}
+
+// l1Origin returns the number of the L1 block the L2 block is derived from, read from its L1 info deposit.
+func l1Origin(block *types.Block) (uint64, error) {
+	txs := block.Transactions()
+	if len(txs) == 0 || !txs[0].IsDepositTx() {
+		return 0, fmt.Errorf("no L1 info deposit in the L2 block %d", block.NumberU64())
+	}
+	data := txs[0].Data()
+	switch {
+	// the number is the first argument of the Bedrock format, and follows the scalars, the sequence number and the
+	// timestamp packed by the Ecotone format, both ending at the byte 36.
+	case len(data) == derive.L1InfoBedrockLen && bytes.Equal(data[:4], derive.L1InfoFuncBedrockBytes4),
+		len(data) == derive.L1InfoEcotoneLen && bytes.Equal(data[:4], derive.L1InfoFuncEcotoneBytes4):
+		return binary.BigEndian.Uint64(data[28:36]), nil
+	default:
+		return 0, fmt.Errorf("unknown L1 info format in the L2 block %d", block.NumberU64())
+	}
+}
+
+// firstL2Block returns the first L2 block derived from the L1 block or a later one, the next L2 block when the L1 block
+// is not derived yet. The L1 origin never decreasing along L2, the block is found by a binary search skipping the genesis.
+func firstL2Block(ctx context.Context, l2Client *ethclient.Client, l1Height uint64) (uint64, error) {
+	latest, err := l2Client.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query the latest L2 block number: %w", err)
+	}
+	low, high := uint64(1), latest+1
+	for low < high {
+		mid := low + (high-low)/2
+		block, err := l2Client.BlockByNumber(ctx, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, fmt.Errorf("failed to query the L2 block %d: %w", mid, err)
+		}
+		origin, err := l1Origin(block)
+		if err != nil {
+			return 0, err
+		}
+		if origin >= l1Height {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low, nil
+}
+
+// mintOf returns the ETH minted on L2 by a deposit, the first 32 bytes of the opaque data of `TransactionDeposited`.
+func mintOf(opaqueData []byte) *big.Int {
+	if len(opaqueData) < 32 {
+		return new(big.Int)
+	}
+	return new(big.Int).SetBytes(opaqueData[:32])
+}
+
+// discrepancy returns the change of the balance of the portal that is not explained by the change of the ETH supply of
+// L2 and of the ETH of the withdrawals pending between L2 and L1.
+func discrepancy(before *big.Int, after *big.Int, supplyDelta *big.Int, pendingDelta *big.Int) *big.Int {
+	expected := new(big.Int).Add(before, supplyDelta)
+	expected.Add(expected, pendingDelta)
+	return new(big.Int).Sub(after, expected)
+}
+
+// isViolated returns true when the discrepancy exceeds the tolerance in either direction.
+func isViolated(discrepancy *big.Int, tolerance *big.Int) bool {
+	return new(big.Int).Abs(discrepancy).Cmp(tolerance) > 0
+}
@@ -0,0 +1,307 @@
+package conservation
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var portal = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+
+// portalNode is an L1 node with the balances of the portal by block, the balance of a block being the last one set.
+type portalNode struct {
+	*fake.Node
+
+	balances map[uint64]*big.Int
+}
+
+func newPortalNode(t *testing.T) *portalNode {
+	n := &portalNode{Node: fake.NewNode(t), balances: map[uint64]*big.Int{0: new(big.Int)}}
+	n.AddBlock(&types.Header{})
+	n.Handle("eth_getBalance", func(params []json.RawMessage) (interface{}, error) {
+		var tag string
+		if err := fake.Param(params, 1, &tag); err != nil {
+			return nil, err
+		}
+		number, err := hexutil.DecodeUint64(tag)
+		if err != nil {
+			return nil, err
+		}
+		for n.balances[number] == nil {
+			number--
+		}
+		return (*hexutil.Big)(n.balances[number]), nil
+	})
+	return n
+}
+
+// addBlock appends a block where the balance of the portal is set, with the deposits minting the values.
+func (n *portalNode) addBlock(t *testing.T, balance int64, deposits ...int64) *types.Header {
+	header := n.AddBlock(&types.Header{})
+	n.balances[header.Number.Uint64()] = big.NewInt(balance)
+
+	event := util.MustParseABI(bindings.OptimismPortalMetaData).Events["TransactionDeposited"]
+	for _, mint := range deposits {
+		opaqueData := append(common.BigToHash(big.NewInt(mint)).Bytes(), make([]byte, 41)...)
+		data, err := event.Inputs.NonIndexed().Pack(opaqueData)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		n.AddLogs(types.Log{Address: portal, Topics: []common.Hash{event.ID, {}, {}, {}}, Data: data, BlockNumber: header.Number.Uint64(), BlockHash: header.Hash()})
+	}
+	return header
+}
+
+// finalize adds the successful finalization of the withdrawal to the block.
+func (n *portalNode) finalize(t *testing.T, header *types.Header, withdrawalHash common.Hash) {
+	event := util.MustParseABI(bindings.OptimismPortalMetaData).Events["WithdrawalFinalized"]
+	data, err := event.Inputs.NonIndexed().Pack(true)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	n.AddLogs(types.Log{Address: portal, Topics: []common.Hash{event.ID, withdrawalHash}, Data: data, BlockNumber: header.Number.Uint64(), BlockHash: header.Hash()})
+}
+
+// l1InfoTx returns the L1 info deposit of an L2 block derived from the L1 block, in the Ecotone format.
+func l1InfoTx(origin uint64) *types.Transaction {
+	data := make([]byte, derive.L1InfoEcotoneLen)
+	copy(data, derive.L1InfoFuncEcotoneBytes4)
+	binary.BigEndian.PutUint64(data[28:36], origin)
+	return types.NewTx(&types.DepositTx{SourceHash: common.BigToHash(new(big.Int).SetUint64(origin)), From: derive.L1InfoDepositerAddress, To: &predeploys.L1BlockAddr, Gas: 1_000_000, Data: data})
+}
+
+// l2Node is an L2 node whose blocks are derived from the L1 blocks.
+type l2Node struct {
+	*fake.Node
+}
+
+func newL2Node(t *testing.T) *l2Node {
+	n := &l2Node{Node: fake.NewNode(t)}
+	n.AddBlock(&types.Header{})
+	return n
+}
+
+// addBlock appends a block derived from the L1 block, with the deposits minting the values.
+func (n *l2Node) addBlock(origin uint64, deposits ...int64) *types.Header {
+	txs := []*types.Transaction{l1InfoTx(origin)}
+	for i, mint := range deposits {
+		txs = append(txs, types.NewTx(&types.DepositTx{SourceHash: common.BigToHash(big.NewInt(int64(origin)<<8 + int64(i) + 1)), Mint: big.NewInt(mint), Value: big.NewInt(mint), Gas: 100_000}))
+	}
+	return n.AddBlock(&types.Header{}, txs...)
+}
+
+// initiate adds the withdrawal of the value to the block.
+func (n *l2Node) initiate(t *testing.T, header *types.Header, withdrawalHash common.Hash, value int64) {
+	event := util.MustParseABI(bindings.L2ToL1MessagePasserMetaData).Events["MessagePassed"]
+	data, err := event.Inputs.NonIndexed().Pack(big.NewInt(value), big.NewInt(100_000), []byte{}, withdrawalHash)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	n.AddLogs(types.Log{Address: predeploys.L2ToL1MessagePasserAddr, Topics: []common.Hash{event.ID, {}, {}, {}}, Data: data, BlockNumber: header.Number.Uint64(), BlockHash: header.Hash()})
+}
+
+// newTestMonitor returns the monitor of the portal from the block 1 over windows of 2 blocks, and the notifier of its alerts.
+// The L2 node has a block derived from the L1 block 0, before the monitored blocks.
+func newTestMonitor(t *testing.T, node *portalNode, l2 *l2Node) (*Monitor, *fake.Notifier) {
+	l2.addBlock(0)
+	cfg := CLIConfig{L1NodeURL: node.URL, L2NodeURL: l2.URL, OptimismPortalAddress: portal, StartBlockHeight: 1, WindowBlockRange: 2, Tolerance: big.NewInt(10)}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestMintOf(t *testing.T) {
+	opaqueData := append(common.BigToHash(big.NewInt(1e18)).Bytes(), common.BigToHash(big.NewInt(5e17)).Bytes()...)
+	if mint := mintOf(opaqueData); mint.Cmp(big.NewInt(1e18)) != 0 {
+		t.Errorf("expected %d but got %d", int64(1e18), mint)
+	}
+	if mint := mintOf(nil); mint.Sign() != 0 {
+		t.Errorf("expected 0 but got %d", mint)
+	}
+}
+
+func TestL1Origin(t *testing.T) {
+	bedrock := make([]byte, derive.L1InfoBedrockLen)
+	copy(bedrock, derive.L1InfoFuncBedrockBytes4)
+	bedrock[35] = 7
+
+	tests := []struct {
+		name     string
+		txs      []*types.Transaction
+		expected uint64
+		err      bool
+	}{
+		{name: "Ecotone", txs: []*types.Transaction{l1InfoTx(7)}, expected: 7},
+		{name: "Bedrock", txs: []*types.Transaction{types.NewTx(&types.DepositTx{Data: bedrock})}, expected: 7},
+		{name: "No transaction", err: true},
+		{name: "No deposit", txs: []*types.Transaction{types.NewTx(&types.LegacyTx{Data: bedrock})}, err: true},
+		{name: "Unknown format", txs: []*types.Transaction{types.NewTx(&types.DepositTx{Data: bedrock[:36]})}, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := l1Origin(types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody(test.txs, nil))
+			if test.err {
+				if err == nil {
+					t.Errorf("Failed %s: expected an error but got %d", test.name, output)
+				}
+				return
+			}
+			if err != nil || output != test.expected {
+				t.Errorf("Failed %s: expected %d but got %d (%v)", test.name, test.expected, output, err)
+			}
+		})
+	}
+}
+
+func TestDiscrepancy(t *testing.T) {
+	tolerance := big.NewInt(10)
+	tests := []struct {
+		name             string
+		before           int64
+		after            int64
+		supplyDelta      int64
+		pendingDelta     int64
+		expected         int64
+		expectedViolated bool
+	}{
+		{name: "Conserved", before: 1000, after: 1400, supplyDelta: 300, pendingDelta: 100, expected: 0, expectedViolated: false},
+		{name: "Within tolerance", before: 1000, after: 1405, supplyDelta: 300, pendingDelta: 100, expected: 5, expectedViolated: false},
+		{name: "ETH missing", before: 1000, after: 1300, supplyDelta: 300, pendingDelta: 100, expected: -100, expectedViolated: true},
+		{name: "ETH in excess", before: 1000, after: 1500, supplyDelta: 300, pendingDelta: 100, expected: 100, expectedViolated: true},
+		{name: "Withdrawals finalized", before: 1000, after: 900, supplyDelta: 0, pendingDelta: -100, expected: 0, expectedViolated: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := discrepancy(big.NewInt(test.before), big.NewInt(test.after), big.NewInt(test.supplyDelta), big.NewInt(test.pendingDelta))
+			if output.Int64() != test.expected {
+				t.Errorf("Failed %s: expected %d but got %d", test.name, test.expected, output)
+			}
+			if violated := isViolated(output, tolerance); violated != test.expectedViolated {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expectedViolated, violated)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	node := newPortalNode(t)
+	l2 := newL2Node(t)
+	m, _ := newTestMonitor(t, node, l2)
+	ctx := context.Background()
+
+	// the window waits for L2 to be derived past its blocks.
+	node.addBlock(t, 1000, 1000)
+	node.addBlock(t, 1000)
+	l2.addBlock(1, 1000)
+	m.Run(ctx)
+	if checked := testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("checked")); checked != 0 || m.nextL1Height != 1 {
+		t.Errorf("expected the window waiting for L2 but got checked %v", checked)
+	}
+
+	// a conserved window reports the deposited ETH, the L2 supply and the balance of the portal.
+	l2.addBlock(2)
+	l2.addBlock(3)
+	m.Run(ctx)
+	if deposited, supply, balance := testutil.ToFloat64(m.windowDeposited), testutil.ToFloat64(m.windowSupplyDelta), testutil.ToFloat64(m.portalBalance); deposited != util.WeiToEther(big.NewInt(1000)) || supply != util.WeiToEther(big.NewInt(1000)) || balance != util.WeiToEther(big.NewInt(1000)) {
+		t.Errorf("expected 1000 wei deposited, minted on L2 and held by the portal but got %v, %v and %v", deposited, supply, balance)
+	}
+	if discrepancy, violated, checked := testutil.ToFloat64(m.windowDiscrepancy), testutil.ToFloat64(m.invariantViolated), testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("checked")); discrepancy != 0 || violated != 0 || checked != 2 || m.nextL2Height != 4 {
+		t.Errorf("expected the window to the block 2 conserved but got the discrepancy %v, violated %v, checked %v and the next L2 block %d", discrepancy, violated, checked, m.nextL2Height)
+	}
+
+	// a withdrawal initiated on L2 moves the ETH from the L2 supply to the pending withdrawals.
+	withdrawalHash := common.HexToHash("0x01")
+	node.addBlock(t, 1000)
+	node.addBlock(t, 1000)
+	l2.initiate(t, l2.addBlock(4), withdrawalHash, 100)
+	l2.addBlock(5)
+	m.Run(ctx)
+	if supply, pending, discrepancy := testutil.ToFloat64(m.windowSupplyDelta), testutil.ToFloat64(m.pendingWithdrawn), testutil.ToFloat64(m.windowDiscrepancy); supply != util.WeiToEther(big.NewInt(-100)) || pending != util.WeiToEther(big.NewInt(100)) || discrepancy != 0 {
+		t.Errorf("expected 100 wei withdrawn from L2 and pending but got %v, %v and the discrepancy %v", supply, pending, discrepancy)
+	}
+
+	// the withdrawal finalized on L1 releases the value initiated on L2.
+	node.finalize(t, node.addBlock(t, 900), withdrawalHash)
+	node.addBlock(t, 900)
+	l2.addBlock(6)
+	l2.addBlock(7)
+	m.Run(ctx)
+	if withdrawn, pending, discrepancy := testutil.ToFloat64(m.windowWithdrawn), testutil.ToFloat64(m.pendingWithdrawn), testutil.ToFloat64(m.windowDiscrepancy); withdrawn != util.WeiToEther(big.NewInt(100)) || pending != 0 || discrepancy != 0 {
+		t.Errorf("expected 100 wei withdrawn and no pending withdrawal but got %v, %v and the discrepancy %v", withdrawn, pending, discrepancy)
+	}
+
+	// a deposit to the portal not minted on L2 is counted as a violation.
+	node.addBlock(t, 1000, 100)
+	node.addBlock(t, 1000)
+	l2.addBlock(8)
+	l2.addBlock(9)
+	m.Run(ctx)
+	if discrepancy, violated, violations := testutil.ToFloat64(m.windowDiscrepancy), testutil.ToFloat64(m.invariantViolated), testutil.ToFloat64(m.violations); discrepancy != util.WeiToEther(big.NewInt(100)) || violated != 1 || violations != 1 {
+		t.Errorf("expected 100 wei not minted in violation but got the discrepancy %v, violated %v and %v violations", discrepancy, violated, violations)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newPortalNode(t)
+	l2 := newL2Node(t)
+	m, notifier := newTestMonitor(t, node, l2)
+	ctx := context.Background()
+
+	node.addBlock(t, 1000, 1000)
+	node.addBlock(t, 1000)
+	l2.addBlock(1, 1000)
+	l2.addBlock(2)
+	l2.addBlock(3)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// the windows with ETH missing from the portal fire once, resolved by a conserved window.
+	node.addBlock(t, 900)
+	node.addBlock(t, 900)
+	l2.addBlock(4)
+	l2.addBlock(5)
+	m.Run(ctx)
+	node.addBlock(t, 800)
+	node.addBlock(t, 800)
+	l2.addBlock(6)
+	l2.addBlock(7)
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(ViolatedRule); len(alerts) != 1 || alerts[0].Entity != portal.String() || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the violation alert once but got %v", alerts)
+	}
+	node.addBlock(t, 1300, 500)
+	node.addBlock(t, 1300)
+	l2.addBlock(8, 500)
+	l2.addBlock(9)
+	m.Run(ctx)
+	if firing := notifier.Firing(ViolatedRule); len(firing) != 0 {
+		t.Fatalf("expected the violation alert resolved but got %v", firing)
+	}
+}
@@ -0,0 +1,76 @@
+package delayed_weth
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+
+	DelayedWETHAddressFlagName = "delayedweth.address"
+	StartBlockHeightFlagName   = "start.block.height"
+	EventBlockRangeFlagName    = "event.block.range"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+
+	DelayedWETHAddress common.Address
+
+	StartBlockHeight uint64
+	EventBlockRange  uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:        ctx.String(L1NodeURLFlagName),
+		StartBlockHeight: ctx.Uint64(StartBlockHeightFlagName),
+		EventBlockRange:  ctx.Uint64(EventBlockRangeFlagName),
+	}
+
+	wethAddress := ctx.String(DelayedWETHAddressFlagName)
+	if !common.IsHexAddress(wethAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", DelayedWETHAddressFlagName)
+	}
+	cfg.DelayedWETHAddress = common.HexToAddress(wethAddress)
+
+	if cfg.EventBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", EventBlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     DelayedWETHAddressFlagName,
+			Usage:    "Address of the DelayedWETH contract holding the bonds of the dispute games",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "DELAYED_WETH"),
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:    StartBlockHeightFlagName,
+			Usage:   "Starting height to scan for the DelayedWETH activity, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    EventBlockRangeFlagName,
+			Usage:   "Max block range when scanning for events",
+			Value:   1000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EVENT_BLOCK_RANGE"),
+		},
+	}
+}
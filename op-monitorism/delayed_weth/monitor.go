@@ -0,0 +1,370 @@
+package delayed_weth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "delayed_weth"
+	MetricsNamespace = "delayed_weth_mon"
+
+	// Rules of the alerts emitted by the monitor.
+	DelayBypassedRule     = "withdrawal bypassing the delay"
+	HoldRule              = "funds held by the owner"
+	RecoveryRule          = "funds swept by the owner"
+	OwnershipTransferRule = "ownership transferred"
+)
+
+var (
+	// claimCreditSelector is the selector of `claimCredit(address)` of the dispute games, withdrawing the bonds of the recipient.
+	claimCreditSelector = crypto.Keccak256([]byte("claimCredit(address)"))[:4]
+)
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+
+	wethAddress common.Address
+	weth        *bindings.DelayedWETHCaller
+	wethEvents  *bindings.DelayedWETHFilterer
+	wethABI     *abi.ABI
+
+	delay uint64
+
+	nextL1Height    uint64
+	eventBlockRange uint64
+
+	// metrics
+	deposits            prometheus.Counter
+	withdrawals         *prometheus.CounterVec
+	holds               prometheus.Counter
+	recoveredEther      prometheus.Counter
+	ownershipTransfers  prometheus.Counter
+	balance             prometheus.Gauge
+	delaySeconds        prometheus.Gauge
+	highestBlockNumber  *prometheus.GaugeVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating delayed weth monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+
+	weth, err := bindings.NewDelayedWETHCaller(cfg.DelayedWETHAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the DelayedWETH: %w", err)
+	}
+	wethEvents, err := bindings.NewDelayedWETHFilterer(cfg.DelayedWETHAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the DelayedWETH: %w", err)
+	}
+	wethABI, err := bindings.DelayedWETHMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the DelayedWETH ABI: %w", err)
+	}
+	delay, err := weth.Delay(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the delay: %w", err)
+	}
+
+	startingL1Height := cfg.StartBlockHeight
+	if startingL1Height == 0 {
+		if startingL1Height, err = l1Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+	log.Info("configured delayed weth", "address", cfg.DelayedWETHAddress, "delay", time.Duration(delay.Uint64())*time.Second, "height", startingL1Height)
+
+	monitor := &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+
+		wethAddress: cfg.DelayedWETHAddress,
+		weth:        weth,
+		wethEvents:  wethEvents,
+		wethABI:     wethABI,
+
+		delay: delay.Uint64(),
+
+		nextL1Height:    startingL1Height,
+		eventBlockRange: cfg.EventBlockRange,
+
+		deposits: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "deposits",
+			Help:      "number of deposits, the bonds of the games",
+		}),
+		withdrawals: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "withdrawals",
+			Help:      "number of withdrawals by check (delayed, early, notUnlocked, exceedsUnlocked, unattributed)",
+		}, []string{"check"}),
+		holds: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "holds",
+			Help:      "number of funds held by the owner (hold), a recovery action",
+		}),
+		recoveredEther: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "recoveredEther",
+			Help:      "ETH leaving the contract not explained by the withdrawals, swept by the owner (recover)",
+		}),
+		ownershipTransfers: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "ownershipTransfers",
+			Help:      "number of ownership transfers",
+		}),
+		balance: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "balance",
+			Help:      "ETH balance of the contract at the last checked height",
+		}),
+		delaySeconds: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "delay",
+			Help:      "delay between the unlock and the withdrawal of the bonds in seconds",
+		}),
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed l1 heights (checked and known)",
+		}, []string{"type"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}
+	monitor.delaySeconds.Set(float64(monitor.delay))
+	return monitor, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestL1Height))
+
+	if m.nextL1Height > latestL1Height {
+		m.log.Info("no new blocks", "next_height", m.nextL1Height, "latest_height", latestL1Height)
+		return
+	}
+
+	toBlock := min(latestL1Height, m.nextL1Height+m.eventBlockRange-1)
+	if err := m.processRange(ctx, m.nextL1Height, toBlock); err != nil {
+		m.log.Error("failed to process the DelayedWETH activity", "from", m.nextL1Height, "to", toBlock, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("DelayedWETH", "processRange").Inc()
+		return
+	}
+
+	m.log.Info("checked blocks", "from", m.nextL1Height, "to", toBlock)
+	m.highestBlockNumber.WithLabelValues("checked").Set(float64(toBlock))
+	m.nextL1Height = toBlock + 1
+}
+
+// processRange reports the activity of the DelayedWETH between the two L1 blocks (inclusive).
+// The ETH leaving the contract without a withdrawal is swept by the owner, `recover` emitting no event.
+func (m *Monitor) processRange(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	toOpts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(toBlock)}
+	owner, err := m.weth.Owner(toOpts)
+	if err != nil {
+		return fmt.Errorf("failed to query the owner: %w", err)
+	}
+	startBalance, err := m.l1Client.BalanceAt(ctx, m.wethAddress, new(big.Int).SetUint64(fromBlock-1))
+	if err != nil {
+		return fmt.Errorf("failed to query the balance at %d: %w", fromBlock-1, err)
+	}
+	endBalance, err := m.l1Client.BalanceAt(ctx, m.wethAddress, toOpts.BlockNumber)
+	if err != nil {
+		return fmt.Errorf("failed to query the balance at %d: %w", toBlock, err)
+	}
+
+	logs, err := m.l1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   toOpts.BlockNumber,
+		Addresses: []common.Address{m.wethAddress},
+		Topics: [][]common.Hash{{
+			m.wethABI.Events["Deposit"].ID,
+			m.wethABI.Events["Withdrawal"].ID,
+			m.wethABI.Events["Approval"].ID,
+			m.wethABI.Events["OwnershipTransferred"].ID,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the events: %w", err)
+	}
+
+	deposited, withdrawn := new(big.Int), new(big.Int)
+	for _, vLog := range logs {
+		switch vLog.Topics[0] {
+		case m.wethABI.Events["Deposit"].ID:
+			deposit, err := m.wethEvents.ParseDeposit(vLog)
+			if err != nil {
+				return fmt.Errorf("failed to parse the deposit %s: %w", vLog.TxHash, err)
+			}
+			deposited.Add(deposited, deposit.Wad)
+			m.deposits.Inc()
+
+		case m.wethABI.Events["Withdrawal"].ID:
+			withdrawal, err := m.wethEvents.ParseWithdrawal(vLog)
+			if err != nil {
+				return fmt.Errorf("failed to parse the withdrawal %s: %w", vLog.TxHash, err)
+			}
+			withdrawn.Add(withdrawn, withdrawal.Wad)
+			if err := m.checkWithdrawal(ctx, withdrawal); err != nil {
+				return err
+			}
+
+		case m.wethABI.Events["Approval"].ID:
+			approval, err := m.wethEvents.ParseApproval(vLog)
+			if err != nil {
+				return fmt.Errorf("failed to parse the approval %s: %w", vLog.TxHash, err)
+			}
+			// `hold` approves the owner to transfer the funds of an account.
+			if approval.Guy == owner && approval.Src != owner {
+				m.holds.Inc()
+				m.log.Error("funds held by the owner, a recovery action!", "account", approval.Src, "owner", owner, "amount", util.WeiToEther(approval.Wad), "l1_tx", vLog.TxHash, "l1_block", vLog.BlockNumber)
+				m.Emit(ctx, m.alert(HoldRule, "P1", approval.Src, vLog.TxHash, fmt.Sprintf("%g WETH of %s held by the owner %s", util.WeiToEther(approval.Wad), approval.Src, owner)))
+			}
+
+		case m.wethABI.Events["OwnershipTransferred"].ID:
+			transfer, err := m.wethEvents.ParseOwnershipTransferred(vLog)
+			if err != nil {
+				return fmt.Errorf("failed to parse the ownership transfer %s: %w", vLog.TxHash, err)
+			}
+			m.ownershipTransfers.Inc()
+			m.log.Warn("ownership transferred", "previous", transfer.PreviousOwner, "owner", transfer.NewOwner, "l1_tx", vLog.TxHash, "l1_block", vLog.BlockNumber)
+			m.Emit(ctx, m.alert(OwnershipTransferRule, "P1", m.wethAddress, vLog.TxHash, fmt.Sprintf("ownership of DelayedWETH transferred from %s to %s", transfer.PreviousOwner, transfer.NewOwner)))
+		}
+	}
+
+	if recovered := unexplainedOutflow(startBalance, endBalance, deposited, withdrawn); recovered.Sign() > 0 {
+		m.recoveredEther.Add(util.WeiToEther(recovered))
+		m.log.Error("funds swept by the owner, a recovery action!", "owner", owner, "amount", util.WeiToEther(recovered), "from", fromBlock, "to", toBlock)
+		m.Emit(ctx, m.alert(RecoveryRule, "P1", m.wethAddress, common.Hash{}, fmt.Sprintf("%g ETH swept by the owner %s from block %d to %d", util.WeiToEther(recovered), owner, fromBlock, toBlock)))
+	}
+	m.balance.Set(util.WeiToEther(endBalance))
+	return nil
+}
+
+// checkWithdrawal verifies the withdrawal of a game against its unlocked request before the block.
+// The recipient is decoded from the `claimCredit` call of the transaction, the withdrawal is unattributed otherwise.
+func (m *Monitor) checkWithdrawal(ctx context.Context, withdrawal *bindings.DelayedWETHWithdrawal) error {
+	vLog := withdrawal.Raw
+	tx, _, err := m.l1Client.TransactionByHash(ctx, vLog.TxHash)
+	if err != nil {
+		return fmt.Errorf("failed to query the transaction %s: %w", vLog.TxHash, err)
+	}
+	recipient, ok := claimCreditRecipient(tx, withdrawal.Src)
+	if !ok {
+		m.withdrawals.WithLabelValues("unattributed").Inc()
+		m.log.Warn("unattributed withdrawal, not a direct claimCredit of the game", "game", withdrawal.Src, "amount", util.WeiToEther(withdrawal.Wad), "l1_tx", vLog.TxHash, "l1_block", vLog.BlockNumber)
+		return nil
+	}
+
+	request, err := m.weth.Withdrawals(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(vLog.BlockNumber - 1)}, withdrawal.Src, recipient)
+	if err != nil {
+		return fmt.Errorf("failed to query the withdrawal request of %s: %w", recipient, err)
+	}
+	header, err := m.l1Client.HeaderByNumber(ctx, new(big.Int).SetUint64(vLog.BlockNumber))
+	if err != nil {
+		return fmt.Errorf("failed to query the header %d: %w", vLog.BlockNumber, err)
+	}
+
+	check := withdrawalCheck(request.Amount, request.Timestamp, withdrawal.Wad, header.Time, m.delay)
+	m.withdrawals.WithLabelValues(check).Inc()
+	if check != "delayed" {
+		m.log.Error("withdrawal bypassing the delay!", "check", check, "game", withdrawal.Src, "recipient", recipient, "amount", util.WeiToEther(withdrawal.Wad),
+			"unlocked", util.WeiToEther(request.Amount), "unlocked_at", request.Timestamp, "delay", m.delay, "l1_tx", vLog.TxHash, "l1_block", vLog.BlockNumber)
+		m.Emit(ctx, m.alert(DelayBypassedRule, "P0", withdrawal.Src, vLog.TxHash, fmt.Sprintf("withdrawal of %g WETH by the game %s for %s bypassing the delay (%s)", util.WeiToEther(withdrawal.Wad), withdrawal.Src, recipient, check)))
+	}
+	return nil
+}
+
+// alert returns the alert of the rule about the address, labeled by the transaction when known.
+func (m *Monitor) alert(rule string, priority string, address common.Address, txHash common.Hash, summary string) alerts.Alert {
+	labels := map[string]string{"weth": m.wethAddress.String()}
+	if txHash != (common.Hash{}) {
+		labels["l1_tx"] = txHash.String()
+	}
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   address.String(),
+		Summary:  summary,
+		Labels:   labels,
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	return nil
+}
+
+// claimCreditRecipient returns the recipient of a `claimCredit` call on the game, false when the transaction is not one.
+func claimCreditRecipient(tx *types.Transaction, game common.Address) (common.Address, bool) {
+	data := tx.Data()
+	if tx.To() == nil || *tx.To() != game || len(data) != 4+32 || !bytes.Equal(data[:4], claimCreditSelector) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(data[4:]), true
+}
+
+// withdrawalCheck checks a withdrawal against the request unlocked before it:
+// delayed when the amount was unlocked for the delay, early, notUnlocked or exceedsUnlocked otherwise.
+func withdrawalCheck(unlocked *big.Int, unlockedAt *big.Int, amount *big.Int, blockTime uint64, delay uint64) string {
+	switch {
+	case unlockedAt.Sign() == 0:
+		return "notUnlocked"
+	case unlocked.Cmp(amount) < 0:
+		return "exceedsUnlocked"
+	case unlockedAt.Uint64()+delay > blockTime:
+		return "early"
+	default:
+		return "delayed"
+	}
+}
+
+// unexplainedOutflow returns the ETH that left the contract over the range without a withdrawal, zero when none.
+func unexplainedOutflow(startBalance *big.Int, endBalance *big.Int, deposited *big.Int, withdrawn *big.Int) *big.Int {
+	expected := new(big.Int).Add(startBalance, deposited)
+	expected.Sub(expected, withdrawn)
+	outflow := expected.Sub(expected, endBalance)
+	if outflow.Sign() < 0 {
+		return new(big.Int)
+	}
+	return outflow
+}
@@ -0,0 +1,267 @@
+package delayed_weth
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const delay = 7 * 24 * 60 * 60
+
+var (
+	wethAddress = common.HexToAddress("0x21429aF66058BC3e4aE4a8f2EC4531AaC433ecbC")
+	owner       = common.HexToAddress("0x5a0Aae59D09fccBdDb6C6CcEB07B7279367C3d2A")
+	wethABI     = util.MustParseABI(bindings.DelayedWETHMetaData)
+)
+
+func TestWithdrawalCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		unlocked   int64
+		unlockedAt int64
+		amount     int64
+		blockTime  uint64
+		expected   string
+	}{
+		{name: "delayed", unlocked: 100, unlockedAt: 1000, amount: 100, blockTime: 1000 + delay, expected: "delayed"},
+		{name: "partial", unlocked: 100, unlockedAt: 1000, amount: 50, blockTime: 2000 + delay, expected: "delayed"},
+		{name: "early", unlocked: 100, unlockedAt: 1000, amount: 100, blockTime: 999 + delay, expected: "early"},
+		{name: "not unlocked", unlocked: 0, unlockedAt: 0, amount: 100, blockTime: 1000 + delay, expected: "notUnlocked"},
+		{name: "exceeds unlocked", unlocked: 100, unlockedAt: 1000, amount: 101, blockTime: 1000 + delay, expected: "exceedsUnlocked"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := withdrawalCheck(big.NewInt(test.unlocked), big.NewInt(test.unlockedAt), big.NewInt(test.amount), test.blockTime, delay)
+			if got != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnexplainedOutflow(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     int64
+		end       int64
+		deposited int64
+		withdrawn int64
+		expected  int64
+	}{
+		{name: "explained", start: 100, end: 120, deposited: 50, withdrawn: 30, expected: 0},
+		{name: "recovered", start: 100, end: 20, deposited: 50, withdrawn: 30, expected: 100},
+		{name: "forced inflow", start: 100, end: 130, deposited: 0, withdrawn: 0, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := unexplainedOutflow(big.NewInt(test.start), big.NewInt(test.end), big.NewInt(test.deposited), big.NewInt(test.withdrawn))
+			if got.Int64() != test.expected {
+				t.Errorf("Failed %s: expected %d but got %s", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestClaimCreditRecipient(t *testing.T) {
+	game := common.HexToAddress("0x1")
+	recipient := common.HexToAddress("0x2")
+	data := append(append([]byte{}, claimCreditSelector...), common.LeftPadBytes(recipient.Bytes(), 32)...)
+
+	tests := []struct {
+		name  string
+		to    common.Address
+		data  []byte
+		valid bool
+	}{
+		{name: "claimCredit", to: game, data: data, valid: true},
+		{name: "other game", to: common.HexToAddress("0x3"), data: data, valid: false},
+		{name: "other call", to: game, data: append([]byte{0, 0, 0, 0}, data[4:]...), valid: false},
+		{name: "no calldata", to: game, data: nil, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			to := test.to
+			tx := types.NewTx(&types.LegacyTx{To: &to, Data: test.data})
+			got, ok := claimCreditRecipient(tx, game)
+			if ok != test.valid || (ok && got != recipient) {
+				t.Errorf("Failed %s: expected %t but got %s, %t", test.name, test.valid, got, ok)
+			}
+		})
+	}
+}
+
+// wethNode is an L1 node with the events of the DelayedWETH and its balances by block, the balance of a block being
+// the last one set.
+type wethNode struct {
+	*fake.Node
+
+	balances map[uint64]*big.Int
+}
+
+func newWETHNode(t *testing.T) *wethNode {
+	n := &wethNode{Node: fake.NewNode(t), balances: map[uint64]*big.Int{0: big.NewInt(5e18)}}
+	n.AddBlock(&types.Header{})
+	n.Contract(wethAddress, wethABI).
+		Returns("delay", big.NewInt(delay)).
+		Returns("owner", owner).
+		Returns("withdrawals", new(big.Int), new(big.Int))
+	n.Handle("eth_getBalance", func(params []json.RawMessage) (interface{}, error) {
+		var tag string
+		if err := fake.Param(params, 1, &tag); err != nil {
+			return nil, err
+		}
+		number, err := hexutil.DecodeUint64(tag)
+		if err != nil {
+			return nil, err
+		}
+		for n.balances[number] == nil {
+			number--
+		}
+		return (*hexutil.Big)(n.balances[number]), nil
+	})
+	return n
+}
+
+// addBlock appends a block with the transactions where the balance of the DelayedWETH is set, returning its header.
+func (n *wethNode) addBlock(balance int64, txs ...*types.Transaction) *types.Header {
+	header := n.AddBlock(&types.Header{}, txs...)
+	n.balances[header.Number.Uint64()] = big.NewInt(balance)
+	return header
+}
+
+// addEvent adds the event of the DelayedWETH emitted by the transaction of the block.
+func (n *wethNode) addEvent(t *testing.T, header *types.Header, txHash common.Hash, name string, indexed []common.Address, args ...interface{}) {
+	event := wethABI.Events[name]
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	topics := []common.Hash{event.ID}
+	for _, address := range indexed {
+		topics = append(topics, common.BytesToHash(address.Bytes()))
+	}
+	n.AddLogs(types.Log{Address: wethAddress, Topics: topics, Data: data, BlockNumber: header.Number.Uint64(), BlockHash: header.Hash(), TxHash: txHash})
+}
+
+// newTestMonitor returns the monitor of the DelayedWETH from the block 1, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *wethNode) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{L1NodeURL: node.URL, DelayedWETHAddress: wethAddress, StartBlockHeight: 1, EventBlockRange: 100}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := newWETHNode(t)
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// the deposit, the hold and the ownership transfer are counted, explaining the balance.
+	account := common.HexToAddress("0x01")
+	header := node.addBlock(6e18)
+	node.addEvent(t, header, common.HexToHash("0x0a"), "Deposit", []common.Address{account}, big.NewInt(1e18))
+	node.addEvent(t, header, common.HexToHash("0x0b"), "Approval", []common.Address{account, owner}, big.NewInt(1e18))
+	node.addEvent(t, header, common.HexToHash("0x0c"), "OwnershipTransferred", []common.Address{common.HexToAddress("0x02"), owner})
+	m.Run(ctx)
+	if deposits, holds, transfers := testutil.ToFloat64(m.deposits), testutil.ToFloat64(m.holds), testutil.ToFloat64(m.ownershipTransfers); deposits != 1 || holds != 1 || transfers != 1 {
+		t.Errorf("expected 1 deposit, hold and ownership transfer but got %v, %v and %v", deposits, holds, transfers)
+	}
+	if balance, recovered, checked := testutil.ToFloat64(m.balance), testutil.ToFloat64(m.recoveredEther), testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("checked")); balance != 6 || recovered != 0 || checked != 1 {
+		t.Errorf("expected a balance of 6 ETH, nothing recovered, at the block 1 but got %v, %v and %v", balance, recovered, checked)
+	}
+	if seconds := testutil.ToFloat64(m.delaySeconds); seconds != delay {
+		t.Errorf("expected a delay of %d seconds but got %v", delay, seconds)
+	}
+
+	// the ETH leaving the contract without a withdrawal is reported recovered.
+	node.addBlock(5e18)
+	m.Run(ctx)
+	if balance, recovered := testutil.ToFloat64(m.balance), testutil.ToFloat64(m.recoveredEther); balance != 5 || recovered != 1 {
+		t.Errorf("expected 1 ETH recovered, leaving 5 ETH, but got %v and %v", recovered, balance)
+	}
+
+	// the withdrawal of a game without an unlocked request is counted by its check.
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	game, recipient := common.HexToAddress("0x03"), common.HexToAddress("0x04")
+	tx := types.MustSignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(1), To: &game, Gas: 100_000, GasFeeCap: big.NewInt(1e9), Data: append(claimCreditSelector, common.BytesToHash(recipient.Bytes()).Bytes()...),
+	})
+	header = node.addBlock(4e18, tx)
+	node.addEvent(t, header, tx.Hash(), "Withdrawal", []common.Address{game}, big.NewInt(1e18))
+	m.Run(ctx)
+	if withdrawals, recovered := testutil.ToFloat64(m.withdrawals.WithLabelValues("notUnlocked")), testutil.ToFloat64(m.recoveredEther); withdrawals != 1 || recovered != 1 {
+		t.Errorf("expected 1 withdrawal not unlocked, explaining the outflow, but got %v and %v", withdrawals, recovered)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newWETHNode(t)
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// the ownership transfer and the hold of the funds of an account fire once.
+	account := common.HexToAddress("0x01")
+	header := node.addBlock(5e18)
+	node.addEvent(t, header, common.HexToHash("0x0a"), "OwnershipTransferred", []common.Address{common.HexToAddress("0x02"), owner})
+	node.addEvent(t, header, common.HexToHash("0x0b"), "Approval", []common.Address{account, owner}, big.NewInt(1e18))
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(OwnershipTransferRule); len(alerts) != 1 || alerts[0].Entity != wethAddress.String() || alerts[0].Labels["l1_tx"] != common.HexToHash("0x0a").String() {
+		t.Fatalf("expected the ownership transfer alert once but got %v", alerts)
+	}
+	if alerts := notifier.Alerts(HoldRule); len(alerts) != 1 || alerts[0].Entity != account.String() || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the hold alert once but got %v", alerts)
+	}
+
+	// the ETH leaving the contract without a withdrawal is swept by the owner.
+	node.addBlock(4e18)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(RecoveryRule); len(alerts) != 1 || alerts[0].Entity != wethAddress.String() {
+		t.Fatalf("expected the recovery alert once but got %v", alerts)
+	}
+
+	// the withdrawal of a game without an unlocked request bypasses the delay.
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	game, recipient := common.HexToAddress("0x03"), common.HexToAddress("0x04")
+	tx := types.MustSignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(1), To: &game, Gas: 100_000, GasFeeCap: big.NewInt(1e9), Data: append(claimCreditSelector, common.BytesToHash(recipient.Bytes()).Bytes()...),
+	})
+	header = node.addBlock(3e18, tx)
+	node.addEvent(t, header, tx.Hash(), "Withdrawal", []common.Address{game}, big.NewInt(1e18))
+	m.Run(ctx)
+	if alerts := notifier.Alerts(DelayBypassedRule); len(alerts) != 1 || alerts[0].Entity != game.String() || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the delay bypassed alert once but got %v", alerts)
+	}
+	if alerts := notifier.Alerts(RecoveryRule); len(alerts) != 1 {
+		t.Fatalf("expected the withdrawal to explain the outflow but got %v", alerts)
+	}
+}
@@ -0,0 +1,95 @@
+package deposits
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+	L2NodeURLFlagName = "l2.node.url"
+
+	OptimismPortalAddressFlagName = "optimismportal.address"
+	StartBlockHeightFlagName      = "start.block.height"
+	EventBlockRangeFlagName       = "event.block.range"
+	RelayBlockThresholdFlagName   = "relay.block.threshold"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+	L2NodeURL string
+
+	OptimismPortalAddress common.Address
+	StartBlockHeight      uint64
+	EventBlockRange       uint64
+
+	// RelayBlockThreshold is the number of L1 blocks after which a deposit not found on L2 is missed.
+	RelayBlockThreshold uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:           ctx.String(L1NodeURLFlagName),
+		L2NodeURL:           ctx.String(L2NodeURLFlagName),
+		StartBlockHeight:    ctx.Uint64(StartBlockHeightFlagName),
+		EventBlockRange:     ctx.Uint64(EventBlockRangeFlagName),
+		RelayBlockThreshold: ctx.Uint64(RelayBlockThresholdFlagName),
+	}
+
+	portalAddress := ctx.String(OptimismPortalAddressFlagName)
+	if !common.IsHexAddress(portalAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", OptimismPortalAddressFlagName)
+	}
+	cfg.OptimismPortalAddress = common.HexToAddress(portalAddress)
+
+	if cfg.EventBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", EventBlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    L2NodeURLFlagName,
+			Usage:   "Node URL of L2 peer",
+			Value:   "127.0.0.1:9545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     OptimismPortalAddressFlagName,
+			Usage:    "Address of the OptimismPortal contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "OPTIMISM_PORTAL"),
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:    StartBlockHeightFlagName,
+			Usage:   "Starting height to scan for deposits, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    EventBlockRangeFlagName,
+			Usage:   "Max block range when scanning for deposits",
+			Value:   1000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EVENT_BLOCK_RANGE"),
+		},
+		&cli.Uint64Flag{
+			Name:    RelayBlockThresholdFlagName,
+			Usage:   "Number of L1 blocks after which a deposit not included on L2 is reported as missed",
+			Value:   20,
+			EnvVars: opservice.PrefixEnvVar(envVar, "RELAY_BLOCK_THRESHOLD"),
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package deposits
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// UserDepositSourceDomain is the domain of the source hash of the user deposits.
+	UserDepositSourceDomain = 0
+)
+
+var (
+	// DepositEventVersion0 is the only version of `TransactionDeposited` supported.
+	DepositEventVersion0 = common.Hash{}
+)
+
+// userDepositSourceHash returns the source hash of the deposit emitted by the log at `logIndex` of the L1 block.
+func userDepositSourceHash(l1BlockHash common.Hash, logIndex uint64) common.Hash {
+	var input [32 * 2]byte
+	copy(input[:32], l1BlockHash[:])
+	binary.BigEndian.PutUint64(input[32*2-8:], logIndex)
+	depositIDHash := crypto.Keccak256Hash(input[:])
+
+	var domainInput [32 * 2]byte
+	binary.BigEndian.PutUint64(domainInput[32-8:32], UserDepositSourceDomain)
+	copy(domainInput[32:], depositIDHash[:])
+	return crypto.Keccak256Hash(domainInput[:])
+}
+
+// depositTx returns the L2 deposit transaction derived from a `TransactionDeposited` log, whose `opaqueData` is already unpacked.
+// The opaque data is packed as `mint (uint256) | value (uint256) | gas (uint64) | isCreation (bool) | data`.
+func depositTx(vLog *types.Log, opaqueData []byte) (*types.DepositTx, error) {
+	if len(vLog.Topics) != 4 {
+		return nil, fmt.Errorf("unexpected number of topics: %d", len(vLog.Topics))
+	}
+	if vLog.Topics[3] != DepositEventVersion0 {
+		return nil, fmt.Errorf("unsupported deposit version: %s", vLog.Topics[3])
+	}
+	if len(opaqueData) < 32+32+8+1 {
+		return nil, fmt.Errorf("unexpected opaqueData length: %d", len(opaqueData))
+	}
+
+	dep := &types.DepositTx{
+		SourceHash: userDepositSourceHash(vLog.BlockHash, uint64(vLog.Index)),
+		From:       common.BytesToAddress(vLog.Topics[1][12:]),
+	}
+
+	// a mint of 0 is represented as nil.
+	dep.Mint = new(big.Int).SetBytes(opaqueData[0:32])
+	if dep.Mint.Sign() == 0 {
+		dep.Mint = nil
+	}
+	dep.Value = new(big.Int).SetBytes(opaqueData[32:64])
+	dep.Gas = binary.BigEndian.Uint64(opaqueData[64:72])
+	if opaqueData[72] == 0 {
+		to := common.BytesToAddress(vLog.Topics[2][12:])
+		dep.To = &to
+	}
+	dep.Data = opaqueData[73:]
+	return dep, nil
+}
@@ -0,0 +1,270 @@
+package deposits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "deposits"
+	MetricsNamespace = "deposit_mon"
+
+	// MissedRule is the rule of the alert emitted while a deposit is not relayed to L2, the entity being the L2 transaction.
+	MissedRule = "deposit not relayed"
+)
+
+// pendingDeposit is a deposit emitted on L1 that is not yet found on L2.
+type pendingDeposit struct {
+	l1BlockNumber uint64
+	l1Timestamp   uint64
+	l1TxHash      common.Hash
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+	l2Client *ethclient.Client
+
+	portalAddress common.Address
+	portalABI     *abi.ABI
+
+	nextL1Height        uint64
+	eventBlockRange     uint64
+	relayBlockThreshold uint64
+
+	pending map[common.Hash]*pendingDeposit // indexed by the hash of the L2 deposit transaction.
+
+	// metrics
+	highestBlockNumber  *prometheus.GaugeVec
+	deposits            prometheus.Counter
+	relayedDeposits     prometheus.Counter
+	relayLatency        prometheus.Histogram
+	pendingDeposits     prometheus.Gauge
+	missedDeposits      prometheus.Gauge
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating deposits monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	l2Client, err := ethclient.Dial(cfg.L2NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l2: %w", err)
+	}
+
+	portalABI, err := bindings.OptimismPortalMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the OptimismPortal ABI: %w", err)
+	}
+
+	startingL1Height := cfg.StartBlockHeight
+	if startingL1Height == 0 {
+		if startingL1Height, err = l1Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+	log.Info("configured starting height", "height", startingL1Height, "relay_block_threshold", cfg.RelayBlockThreshold)
+
+	return &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+		l2Client: l2Client,
+
+		portalAddress: cfg.OptimismPortalAddress,
+		portalABI:     portalABI,
+
+		nextL1Height:        startingL1Height,
+		eventBlockRange:     cfg.EventBlockRange,
+		relayBlockThreshold: cfg.RelayBlockThreshold,
+
+		pending: make(map[common.Hash]*pendingDeposit),
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed l1 heights (checked and known)",
+		}, []string{"type"}),
+		deposits: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "deposits",
+			Help:      "number of deposits observed on L1",
+		}),
+		relayedDeposits: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "relayedDeposits",
+			Help:      "number of deposits found on L2",
+		}),
+		relayLatency: m.NewHistogram(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Name:      "relayLatency",
+			Help:      "seconds between the L1 block of the deposit and the L2 block including it",
+			Buckets:   []float64{12, 30, 60, 120, 300, 600, 1800, 3600},
+		}),
+		pendingDeposits: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "pendingDeposits",
+			Help:      "number of deposits not yet found on L2",
+		}),
+		missedDeposits: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "missedDeposits",
+			Help:      "number of deposits not found on L2 after the relay block threshold",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestL1Height))
+
+	if m.nextL1Height <= latestL1Height {
+		toBlock := min(latestL1Height, m.nextL1Height+m.eventBlockRange-1)
+		if err := m.processDeposits(ctx, m.nextL1Height, toBlock); err != nil {
+			m.log.Error("failed to process the deposits", "from", m.nextL1Height, "to", toBlock, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l1", "TransactionDeposited").Inc()
+			return
+		}
+		m.highestBlockNumber.WithLabelValues("checked").Set(float64(toBlock))
+		m.nextL1Height = toBlock + 1
+	}
+
+	m.checkPendingDeposits(ctx, latestL1Height)
+}
+
+// processDeposits tracks the deposits emitted between the two L1 blocks (inclusive).
+func (m *Monitor) processDeposits(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	logs, err := m.l1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{m.portalAddress},
+		Topics:    [][]common.Hash{{m.portalABI.Events["TransactionDeposited"].ID}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the TransactionDeposited events: %w", err)
+	}
+
+	timestamps := make(map[common.Hash]uint64)
+	for i := range logs {
+		vLog := &logs[i]
+		out, err := m.portalABI.Unpack("TransactionDeposited", vLog.Data)
+		if err != nil {
+			return fmt.Errorf("failed to unpack the deposit %s: %w", vLog.TxHash, err)
+		}
+		dep, err := depositTx(vLog, out[0].([]byte))
+		if err != nil {
+			m.log.Error("failed to derive the deposit transaction", "tx", vLog.TxHash, "log_index", vLog.Index, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l1", "depositTx").Inc()
+			continue
+		}
+
+		timestamp, ok := timestamps[vLog.BlockHash]
+		if !ok {
+			header, err := m.l1Client.HeaderByHash(ctx, vLog.BlockHash)
+			if err != nil {
+				return fmt.Errorf("failed to query the header of %s: %w", vLog.BlockHash, err)
+			}
+			timestamp, timestamps[vLog.BlockHash] = header.Time, header.Time
+		}
+
+		l2TxHash := types.NewTx(dep).Hash()
+		m.pending[l2TxHash] = &pendingDeposit{l1BlockNumber: vLog.BlockNumber, l1Timestamp: timestamp, l1TxHash: vLog.TxHash}
+		m.deposits.Inc()
+		m.log.Info("deposit", "l1_tx", vLog.TxHash, "l1_block", vLog.BlockNumber, "l2_tx", l2TxHash, "from", dep.From)
+	}
+	return nil
+}
+
+// checkPendingDeposits looks for the pending deposits on L2 and reports the ones missed after the relay block threshold.
+func (m *Monitor) checkPendingDeposits(ctx context.Context, latestL1Height uint64) {
+	missed := 0
+	for l2TxHash, deposit := range m.pending {
+		receipt, err := m.l2Client.TransactionReceipt(ctx, l2TxHash)
+		if errors.Is(err, ethereum.NotFound) {
+			if isMissed(deposit.l1BlockNumber, latestL1Height, m.relayBlockThreshold) {
+				missed++
+				m.log.Error("deposit not relayed to L2", "l1_tx", deposit.l1TxHash, "l1_block", deposit.l1BlockNumber, "l2_tx", l2TxHash)
+				m.Update(ctx, missedAlert(l2TxHash, deposit, fmt.Sprintf("deposit of the L1 transaction %s at block %d not relayed to L2 after %d blocks", deposit.l1TxHash, deposit.l1BlockNumber, latestL1Height-deposit.l1BlockNumber)), true)
+			}
+			continue
+		}
+		if err != nil {
+			m.log.Error("failed to query the deposit on L2", "l2_tx", l2TxHash, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l2", "transactionReceipt").Inc()
+			continue
+		}
+
+		header, err := m.l2Client.HeaderByHash(ctx, receipt.BlockHash)
+		if err != nil {
+			m.log.Error("failed to query the L2 block of the deposit", "l2_tx", l2TxHash, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l2", "headerByHash").Inc()
+			continue
+		}
+
+		latency := float64(header.Time) - float64(deposit.l1Timestamp)
+		m.relayLatency.Observe(latency)
+		m.relayedDeposits.Inc()
+		m.Update(ctx, missedAlert(l2TxHash, deposit, fmt.Sprintf("deposit of the L1 transaction %s relayed to L2", deposit.l1TxHash)), false)
+		m.log.Info("deposit relayed", "l1_tx", deposit.l1TxHash, "l2_tx", l2TxHash, "l2_block", receipt.BlockNumber, "latency", latency)
+		delete(m.pending, l2TxHash)
+	}
+
+	m.pendingDeposits.Set(float64(len(m.pending)))
+	m.missedDeposits.Set(float64(missed))
+}
+
+// missedAlert returns the alert of the deposit not relayed to L2.
+func missedAlert(l2TxHash common.Hash, deposit *pendingDeposit, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     MissedRule,
+		Priority: "P1",
+		Entity:   l2TxHash.String(),
+		Summary:  summary,
+		Labels:   map[string]string{"l1_tx": deposit.l1TxHash.String()},
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	m.l2Client.Close()
+	return nil
+}
+
+// isMissed returns true when the deposit emitted at `l1BlockNumber` should have been included on L2.
+func isMissed(l1BlockNumber uint64, latestL1Height uint64, threshold uint64) bool {
+	return latestL1Height > l1BlockNumber+threshold
+}
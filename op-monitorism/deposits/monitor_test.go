@@ -0,0 +1,198 @@
+package deposits
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var portal = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+
+// addDeposit appends an L1 block with a deposit of 1 ETH of the account to itself, returning the hash of its L2 transaction.
+func addDeposit(t *testing.T, l1 *fake.Node, account common.Address) common.Hash {
+	header := l1.AddBlock(&types.Header{})
+	opaqueData := make([]byte, 73)
+	big.NewInt(1e18).FillBytes(opaqueData[0:32])
+	big.NewInt(21000).FillBytes(opaqueData[64:72])
+
+	event := util.MustParseABI(bindings.OptimismPortalMetaData).Events["TransactionDeposited"]
+	data, err := event.Inputs.NonIndexed().Pack(opaqueData)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	vLog := types.Log{
+		Address:     portal,
+		Topics:      []common.Hash{event.ID, common.BytesToHash(account.Bytes()), common.BytesToHash(account.Bytes()), DepositEventVersion0},
+		Data:        data,
+		BlockNumber: header.Number.Uint64(),
+		BlockHash:   header.Hash(),
+		TxHash:      common.HexToHash("0x0d"),
+	}
+	l1.AddLogs(vLog)
+	dep, err := depositTx(&vLog, opaqueData)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	return types.NewTx(dep).Hash()
+}
+
+// newTestMonitor returns the monitor of the deposits from the block 1 missed after 2 blocks, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, l1 *fake.Node, l2 *fake.Node) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{L1NodeURL: l1.URL, L2NodeURL: l2.URL, OptimismPortalAddress: portal, StartBlockHeight: 1, EventBlockRange: 100, RelayBlockThreshold: 2}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestDepositTx(t *testing.T) {
+	from := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	to := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	opaqueData := make([]byte, 73, 75)
+	big.NewInt(5).FillBytes(opaqueData[0:32])
+	big.NewInt(3).FillBytes(opaqueData[32:64])
+	big.NewInt(100000).FillBytes(opaqueData[64:72])
+	opaqueData = append(opaqueData, 0xca, 0xfe)
+
+	vLog := &types.Log{
+		Topics:    []common.Hash{{}, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes()), DepositEventVersion0},
+		BlockHash: common.HexToHash("0x01"),
+		Index:     7,
+	}
+	dep, err := depositTx(vLog, opaqueData)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if dep.From != from || dep.To == nil || *dep.To != to {
+		t.Errorf("unexpected from %s or to %v", dep.From, dep.To)
+	}
+	if dep.Mint.Cmp(big.NewInt(5)) != 0 || dep.Value.Cmp(big.NewInt(3)) != 0 || dep.Gas != 100000 {
+		t.Errorf("unexpected mint %s, value %s or gas %d", dep.Mint, dep.Value, dep.Gas)
+	}
+	if string(dep.Data) != "\xca\xfe" {
+		t.Errorf("unexpected data %x", dep.Data)
+	}
+	if dep.SourceHash != userDepositSourceHash(vLog.BlockHash, 7) || dep.SourceHash == userDepositSourceHash(vLog.BlockHash, 8) {
+		t.Errorf("unexpected source hash %s", dep.SourceHash)
+	}
+
+	// contract creation without mint.
+	big.NewInt(0).FillBytes(opaqueData[0:32])
+	opaqueData[72] = 1
+	if dep, err = depositTx(vLog, opaqueData); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if dep.Mint != nil || dep.To != nil {
+		t.Errorf("expected no mint and no recipient but got %v and %v", dep.Mint, dep.To)
+	}
+
+	if _, err := depositTx(vLog, opaqueData[:72]); err == nil {
+		t.Errorf("expected an error for a short opaqueData")
+	}
+	vLog.Topics[3] = common.HexToHash("0x01")
+	if _, err := depositTx(vLog, opaqueData); err == nil {
+		t.Errorf("expected an error for an unsupported version")
+	}
+}
+
+func TestIsMissed(t *testing.T) {
+	tests := []struct {
+		name           string
+		l1BlockNumber  uint64
+		latestL1Height uint64
+		expected       bool
+	}{
+		{name: "Just emitted", l1BlockNumber: 100, latestL1Height: 100, expected: false},
+		{name: "At the threshold", l1BlockNumber: 100, latestL1Height: 120, expected: false},
+		{name: "After the threshold", l1BlockNumber: 100, latestL1Height: 121, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isMissed(test.l1BlockNumber, test.latestL1Height, 20)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	l1, l2 := fake.NewNode(t), fake.NewNode(t)
+	l1.AddBlock(&types.Header{})
+	m, _ := newTestMonitor(t, l1, l2)
+	ctx := context.Background()
+
+	// a deposit is counted and pending until relayed.
+	l2TxHash := addDeposit(t, l1, common.HexToAddress("0x01"))
+	m.Run(ctx)
+	if deposits, pending := testutil.ToFloat64(m.deposits), testutil.ToFloat64(m.pendingDeposits); deposits != 1 || pending != 1 {
+		t.Errorf("expected 1 deposit pending but got %v deposits and %v pending", deposits, pending)
+	}
+	if checked, known := testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("checked")), testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("known")); checked != 1 || known != 1 {
+		t.Errorf("expected the block 1 checked and known but got %v and %v", checked, known)
+	}
+
+	// the deposit is missed after the threshold.
+	for i := 0; i < 3; i++ {
+		l1.AddBlock(&types.Header{})
+	}
+	m.Run(ctx)
+	if missed := testutil.ToFloat64(m.missedDeposits); missed != 1 {
+		t.Errorf("expected 1 missed deposit but got %v", missed)
+	}
+
+	// the deposit relayed is no longer pending nor missed.
+	header := l2.AddBlock(&types.Header{})
+	l2.AddReceipt(&types.Receipt{TxHash: l2TxHash, Status: types.ReceiptStatusSuccessful, BlockHash: header.Hash(), BlockNumber: header.Number, Logs: []*types.Log{}})
+	m.Run(ctx)
+	if relayed, pending, missed := testutil.ToFloat64(m.relayedDeposits), testutil.ToFloat64(m.pendingDeposits), testutil.ToFloat64(m.missedDeposits); relayed != 1 || pending != 0 || missed != 0 {
+		t.Errorf("expected the deposit relayed but got %v relayed, %v pending and %v missed", relayed, pending, missed)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	l1, l2 := fake.NewNode(t), fake.NewNode(t)
+	l1.AddBlock(&types.Header{})
+	m, notifier := newTestMonitor(t, l1, l2)
+	ctx := context.Background()
+
+	l2TxHash := addDeposit(t, l1, common.HexToAddress("0x01"))
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 || len(m.pending) != 1 {
+		t.Fatalf("expected the deposit pending without alert but got %v", alerts)
+	}
+
+	// a deposit not relayed after the threshold fires once, and resolves once relayed.
+	for i := 0; i < 3; i++ {
+		l1.AddBlock(&types.Header{})
+	}
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(MissedRule); len(alerts) != 1 || alerts[0].Entity != l2TxHash.String() || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the missed deposit alert once but got %v", alerts)
+	}
+	header := l2.AddBlock(&types.Header{})
+	l2.AddReceipt(&types.Receipt{TxHash: l2TxHash, Status: types.ReceiptStatusSuccessful, BlockHash: header.Hash(), BlockNumber: header.Number, Logs: []*types.Log{}})
+	m.Run(ctx)
+	if firing := notifier.Firing(MissedRule); len(firing) != 0 || len(m.pending) != 0 {
+		t.Fatalf("expected the missed deposit alert resolved but got %v", firing)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/drippie/bindings"
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
 
@@ -17,10 +18,17 @@ import (
 )
 
 const (
+	MonitorName      = "drippie"
 	MetricsNamespace = "drippie_mon"
+
+	// ExecutableRule is the rule of the alert emitted while a drip is executable.
+	ExecutableRule = "drip executable"
 )
 
 type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
 	log log.Logger
 
 	l1Client *ethclient.Client
@@ -161,6 +169,16 @@ func (m *Monitor) Run(ctx context.Context) {
 		} else {
 			m.dripExecutableState.WithLabelValues(name).Set(1)
 		}
+		if err == nil {
+			m.Update(ctx, alerts.Alert{
+				Monitor:  MonitorName,
+				Rule:     ExecutableRule,
+				Priority: "P2",
+				Entity:   name,
+				Summary:  fmt.Sprintf("drip %s of Drippie %s is executable", name, m.drippieAddress),
+				Labels:   map[string]string{"drippie": m.drippieAddress.String()},
+			}, executable)
+		}
 
 		// Log so we know what's happening.
 		m.log.Info("updated metrics for drip", "name", name, "count", drip.Count, "last", drip.Last, "executable", executable)
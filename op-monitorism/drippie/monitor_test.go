@@ -0,0 +1,65 @@
+package drippie
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/drippie/bindings"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var drippie = common.HexToAddress("0x03d7a5f5fb4e8ae56a5d2dae1d4b7a0c8de8b9a1")
+
+// newTestMonitor returns the monitor of the Drippie with the drip `faucet`, the Drippie being served by the returned
+// contract, and the notifier of its alerts.
+func newTestMonitor(t *testing.T) (*Monitor, *fake.Contract, *fake.Notifier) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{})
+	config := bindings.DrippieDripConfig{Interval: big.NewInt(3600), Actions: []bindings.DrippieDripAction{}}
+	contract := node.Contract(drippie, util.MustParseABI(bindings.DrippieMetaData)).
+		Returns("getDripCount", big.NewInt(1)).
+		Returns("created", "faucet").
+		Returns("drips", uint8(2), config, big.NewInt(1000), big.NewInt(3)).
+		Returns("executable", false)
+
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), CLIConfig{L1NodeURL: node.URL, DrippieAddress: drippie})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, contract, notifier
+}
+
+func TestRunAlerts(t *testing.T) {
+	m, contract, notifier := newTestMonitor(t)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// an executable drip fires once, resolved once executed.
+	contract.Returns("executable", true)
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(ExecutableRule); len(alerts) != 1 || alerts[0].Entity != "faucet" || alerts[0].Priority != "P2" || alerts[0].Labels["drippie"] != drippie.String() {
+		t.Fatalf("expected the executable alert once but got %v", alerts)
+	}
+	contract.Returns("executable", false)
+	m.Run(ctx)
+	if firing := notifier.Firing(ExecutableRule); len(firing) != 0 {
+		t.Fatalf("expected the executable alert resolved but got %v", firing)
+	}
+}
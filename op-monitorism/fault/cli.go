@@ -14,6 +14,8 @@ const (
 	L1NodeURLFlagName = "l1.node.url"
 	L2NodeURLFlagName = "l2.node.url"
 
+	RollupNodeURLFlagName = "rollup.node.url"
+
 	OptimismPortalAddressFlagName = "optimismportal.address"
 	StartOutputIndexFlagName      = "start.output.index"
 )
@@ -22,6 +24,10 @@ type CLIConfig struct {
 	L1NodeURL string
 	L2NodeURL string
 
+	// RollupNodeURL is the trusted rollup node computing the expected output roots through `optimism_outputAtBlock`.
+	// The output roots are reconstructed from the L2 node when empty.
+	RollupNodeURL string
+
 	OptimismPortalAddress common.Address
 	StartOutputIndex      int64
 }
@@ -30,6 +36,7 @@ func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
 	cfg := CLIConfig{
 		L1NodeURL:        ctx.String(L1NodeURLFlagName),
 		L2NodeURL:        ctx.String(L2NodeURLFlagName),
+		RollupNodeURL:    ctx.String(RollupNodeURLFlagName),
 		StartOutputIndex: ctx.Int64(StartOutputIndexFlagName),
 	}
 
@@ -56,6 +63,11 @@ func CLIFlags(envVar string) []cli.Flag {
 			Value:   "127.0.0.1:9545",
 			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL"),
 		},
+		&cli.StringFlag{
+			Name:    RollupNodeURLFlagName,
+			Usage:   "Node URL of a trusted rollup node computing the expected output roots with optimism_outputAtBlock. The output roots are reconstructed from the L2 peer when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "ROLLUP_NODE_URL"),
+		},
 		&cli.Int64Flag{
 			Name:    StartOutputIndexFlagName,
 			Usage:   "Output index to start from. -1 to find first unfinalized index",
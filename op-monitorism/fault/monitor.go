@@ -2,12 +2,16 @@ package fault
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
 
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/prometheus/client_golang/prometheus"
@@ -21,6 +25,10 @@ import (
 
 const (
 	MetricsNamespace = "fault_detector"
+	MonitorName      = "fault"
+
+	// OutputRootMismatchRule is the rule of the alert emitted while an output root is mismatched.
+	OutputRootMismatchRule = "output root mismatch"
 )
 
 type Monitor struct {
@@ -29,10 +37,18 @@ type Monitor struct {
 	l1Client *ethclient.Client
 	l2Client *ethclient.Client
 
+	// rollupClient is `nil` when the output roots are reconstructed from the L2 node.
+	rollupClient client.RPC
+
 	currOutputIndex  uint64
 	faultProofWindow uint64
 
-	l2OO *bindings.L2OutputOracleCaller
+	l2OO        *bindings.L2OutputOracleCaller
+	l2OOAddress common.Address
+
+	notifier alerts.Notifier
+	// mismatched is set once the mismatch of the current output has been notified.
+	mismatched bool
 
 	// metrics
 	highestOutputIndex     *prometheus.GaugeVec
@@ -52,6 +68,15 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 		return nil, fmt.Errorf("failed to dial l2: %w", err)
 	}
 
+	var rollupClient client.RPC
+	if len(cfg.RollupNodeURL) > 0 {
+		rollupClient, err = client.NewRPC(ctx, log, cfg.RollupNodeURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial the rollup node: %w", err)
+		}
+		log.Info("computing the expected output roots from the rollup node")
+	}
+
 	optimismPortal, err := bindings.NewOptimismPortalCaller(cfg.OptimismPortalAddress, l1Client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to bind to the OptimismPortal: %w", err)
@@ -78,9 +103,14 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 		l1Client: l1Client,
 		l2Client: l2Client,
 
+		rollupClient: rollupClient,
+
 		l2OO:             l2OO,
+		l2OOAddress:      l2OOAddress,
 		faultProofWindow: faultProofWindow.Uint64(),
 
+		notifier: alerts.NopNotifier{},
+
 		highestOutputIndex: m.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: MetricsNamespace,
 			Name:      "highestOutputIndex",
@@ -113,6 +143,11 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 	return monitor, nil
 }
 
+// SetNotifier sets the notifier alerted while an output root is mismatched.
+func (m *Monitor) SetNotifier(notifier alerts.Notifier) {
+	m.notifier = notifier
+}
+
 func (m *Monitor) Run(ctx context.Context) {
 	callOpts := &bind.CallOpts{Context: ctx}
 
@@ -151,49 +186,106 @@ func (m *Monitor) Run(ctx context.Context) {
 		return
 	}
 
-	// Fetch pre-image information for the output root from L2 to reconstruct
+	// Compute the expected output root & verify
 
-	block, err := m.l2Client.BlockByNumber(ctx, output.L2BlockNumber)
-	if err != nil {
-		m.log.Error("failed to query l2 block", "height", output.L2BlockNumber, "err", err)
-		m.nodeConnectionFailures.WithLabelValues("l2", "blockByNumber").Inc()
+	outputRoot, blockTime, ok := m.expectedOutputRoot(ctx, output.L2BlockNumber)
+	if !ok {
 		return
 	}
-	proof := struct{ StorageHash common.Hash }{}
-	if err := m.l2Client.Client().CallContext(ctx, &proof, "eth_getProof",
-		predeploys.L2ToL1MessagePasserAddr, nil, hexutil.EncodeBig(block.Number())); err != nil {
-		m.log.Error("failed to query for proof response of l2ToL1MP contract", "err", err)
-		m.nodeConnectionFailures.WithLabelValues("l2", "getProof").Inc()
-		return
-	}
-
-	// Reconstruct & verify
-
-	outputRoot := eth.OutputRoot(&eth.OutputV0{StateRoot: eth.Bytes32(block.Root()), MessagePasserStorageRoot: eth.Bytes32(proof.StorageHash), BlockHash: block.Hash()})
 	if outputRoot != eth.Bytes32(output.OutputRoot) {
 		m.log.Error("output root mismatch!!!",
 			"index", m.currOutputIndex,
 			"expected_output_root", outputRoot.String(),
 			"actual_output_root", common.Hash(output.OutputRoot).String(),
-			"finalization_time", time.Unix(int64(block.Time()+m.faultProofWindow), 0).String(),
+			"finalization_time", time.Unix(int64(blockTime+m.faultProofWindow), 0).String(),
 		)
 
 		m.isCurrentlyMismatched.Set(1)
+		m.notifyMismatch(ctx, false, map[string]string{
+			"index":              fmt.Sprint(m.currOutputIndex),
+			"l2BlockNumber":      output.L2BlockNumber.String(),
+			"expectedOutputRoot": outputRoot.String(),
+			"actualOutputRoot":   common.Hash(output.OutputRoot).String(),
+		})
 		return
 	}
 
 	// Continue
 
-	m.log.Info("validated output", "index", m.currOutputIndex, "output_root", outputRoot.String(), "finalization_time", time.Unix(int64(block.Time()+m.faultProofWindow), 0).String())
+	m.log.Info("validated output", "index", m.currOutputIndex, "output_root", outputRoot.String(), "finalization_time", time.Unix(int64(blockTime+m.faultProofWindow), 0).String())
 	m.highestOutputIndex.WithLabelValues("checked").Set(float64(m.currOutputIndex))
 
 	m.currOutputIndex++
 	m.isCurrentlyMismatched.Set(0)
+	m.notifyMismatch(ctx, true, map[string]string{"index": fmt.Sprint(m.currOutputIndex - 1)})
+}
+
+// notifyMismatch alerts on the transitions of the mismatch, the failed notifications being retried on the next tick.
+func (m *Monitor) notifyMismatch(ctx context.Context, resolved bool, labels map[string]string) {
+	if m.mismatched != resolved {
+		return
+	}
+
+	alert := alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     OutputRootMismatchRule,
+		Priority: "P0",
+		Entity:   m.l2OOAddress.String(),
+		Summary:  fmt.Sprintf("output root %s of the L2OutputOracle doesn't match the L2 chain", labels["index"]),
+		Labels:   labels,
+		Resolved: resolved,
+	}
+	if resolved {
+		alert.Summary = fmt.Sprintf("output root %s of the L2OutputOracle validated", labels["index"])
+	}
+	if err := m.notifier.Notify(ctx, alert); err != nil {
+		if !errors.Is(err, alerts.ErrSilenced) {
+			m.log.Error("failed to notify the output root mismatch", "resolved", resolved, "err", err)
+		}
+		return
+	}
+	m.mismatched = !resolved
+}
+
+// expectedOutputRoot returns the output root and the timestamp of the L2 block computed by the rollup node when configured,
+// reconstructed from the L2 node otherwise. The failures are logged and counted, `false` is returned to retry on the next tick.
+func (m *Monitor) expectedOutputRoot(ctx context.Context, l2BlockNumber *big.Int) (eth.Bytes32, uint64, bool) {
+	if m.rollupClient != nil {
+		var response eth.OutputResponse
+		if err := m.rollupClient.CallContext(ctx, &response, "optimism_outputAtBlock", hexutil.EncodeBig(l2BlockNumber)); err != nil {
+			m.log.Error("failed to query the output at block from the rollup node", "height", l2BlockNumber, "err", err)
+			m.nodeConnectionFailures.WithLabelValues("rollup", "outputAtBlock").Inc()
+			return eth.Bytes32{}, 0, false
+		}
+		return response.OutputRoot, response.BlockRef.Time, true
+	}
+
+	// Fetch pre-image information for the output root from L2 to reconstruct
+
+	block, err := m.l2Client.BlockByNumber(ctx, l2BlockNumber)
+	if err != nil {
+		m.log.Error("failed to query l2 block", "height", l2BlockNumber, "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2", "blockByNumber").Inc()
+		return eth.Bytes32{}, 0, false
+	}
+	proof := struct{ StorageHash common.Hash }{}
+	if err := m.l2Client.Client().CallContext(ctx, &proof, "eth_getProof",
+		predeploys.L2ToL1MessagePasserAddr, nil, hexutil.EncodeBig(block.Number())); err != nil {
+		m.log.Error("failed to query for proof response of l2ToL1MP contract", "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2", "getProof").Inc()
+		return eth.Bytes32{}, 0, false
+	}
+
+	outputRoot := eth.OutputRoot(&eth.OutputV0{StateRoot: eth.Bytes32(block.Root()), MessagePasserStorageRoot: eth.Bytes32(proof.StorageHash), BlockHash: block.Hash()})
+	return outputRoot, block.Time(), true
 }
 
 func (m *Monitor) Close(_ context.Context) error {
 	m.l1Client.Close()
 	m.l2Client.Close()
+	if m.rollupClient != nil {
+		m.rollupClient.Close()
+	}
 	return nil
 }
 
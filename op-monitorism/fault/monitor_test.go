@@ -0,0 +1,77 @@
+package fault
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	portal         = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+	l2OutputOracle = common.HexToAddress("0xdfe97868233d1aa22e815a266982f2cf17685a27")
+	outputRoot     = common.HexToHash("0x01")
+)
+
+// newTestMonitor returns the monitor of the output of the block 1 proposed to the L2OutputOracle with the output root 0x01,
+// the rollup node computing the expected output roots from the returned variable, and the notifier of its alerts.
+func newTestMonitor(t *testing.T) (*Monitor, *common.Hash, *fake.Notifier) {
+	l1 := fake.NewNode(t)
+	l1.AddBlock(&types.Header{})
+	l1.Contract(portal, util.MustParseABI(bindings.OptimismPortalMetaData)).Returns("L2_ORACLE", l2OutputOracle)
+	l1.Contract(l2OutputOracle, util.MustParseABI(bindings.L2OutputOracleMetaData)).
+		Returns("finalizationPeriodSeconds", big.NewInt(604800)).
+		Returns("nextOutputIndex", big.NewInt(1)).
+		Returns("getL2Output", bindings.TypesOutputProposal{OutputRoot: outputRoot, Timestamp: big.NewInt(1000), L2BlockNumber: big.NewInt(1)})
+
+	l2 := fake.NewNode(t)
+	l2.AddBlock(&types.Header{})
+	l2.AddBlock(&types.Header{})
+	expected := outputRoot
+	rollup := fake.NewNode(t)
+	rollup.Handle("optimism_outputAtBlock", func(_ []json.RawMessage) (interface{}, error) {
+		return eth.OutputResponse{OutputRoot: eth.Bytes32(expected), BlockRef: eth.L2BlockRef{Time: 1000}}, nil
+	})
+
+	cfg := CLIConfig{L1NodeURL: l1.URL, L2NodeURL: l2.URL, RollupNodeURL: rollup.URL, OptimismPortalAddress: portal}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, &expected, notifier
+}
+
+func TestRunAlerts(t *testing.T) {
+	m, expected, notifier := newTestMonitor(t)
+	ctx := context.Background()
+
+	// a mismatched output root fires once while the monitor retries the output.
+	*expected = common.HexToHash("0x02")
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(OutputRootMismatchRule); len(alerts) != 1 || alerts[0].Entity != l2OutputOracle.String() || alerts[0].Priority != "P0" || alerts[0].Labels["index"] != "0" {
+		t.Fatalf("expected the mismatch alert once but got %v", alerts)
+	}
+
+	// the output root validated resolves the alert and moves to the next output.
+	*expected = outputRoot
+	m.Run(ctx)
+	if firing := notifier.Firing(OutputRootMismatchRule); len(firing) != 0 || m.currOutputIndex != 1 {
+		t.Fatalf("expected the mismatch alert resolved but got %v", firing)
+	}
+}
@@ -0,0 +1,96 @@
+package finalization
+
+import (
+	"fmt"
+	"time"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+
+	OptimismPortalAddressFlagName = "optimismportal.address"
+	StartBlockHeightFlagName      = "start.block.height"
+	EventBlockRangeFlagName       = "event.block.range"
+	ProofExpiryFlagName           = "proof.expiry"
+	ExpiryWarningFlagName         = "expiry.warning"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+
+	OptimismPortalAddress common.Address
+	StartBlockHeight      uint64
+	EventBlockRange       uint64
+
+	// ProofExpiry is the age after which the proof of a withdrawal not finalized is considered expired, disabled when 0.
+	ProofExpiry time.Duration
+	// ExpiryWarning is the time before the expiry of a proof from which the withdrawal is reported as nearing expiry.
+	ExpiryWarning time.Duration
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:        ctx.String(L1NodeURLFlagName),
+		StartBlockHeight: ctx.Uint64(StartBlockHeightFlagName),
+		EventBlockRange:  ctx.Uint64(EventBlockRangeFlagName),
+		ProofExpiry:      ctx.Duration(ProofExpiryFlagName),
+		ExpiryWarning:    ctx.Duration(ExpiryWarningFlagName),
+	}
+
+	portalAddress := ctx.String(OptimismPortalAddressFlagName)
+	if !common.IsHexAddress(portalAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", OptimismPortalAddressFlagName)
+	}
+	cfg.OptimismPortalAddress = common.HexToAddress(portalAddress)
+
+	if cfg.EventBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", EventBlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     OptimismPortalAddressFlagName,
+			Usage:    "Address of the OptimismPortal contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "OPTIMISM_PORTAL"),
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:    StartBlockHeightFlagName,
+			Usage:   "Starting height to scan for proven withdrawals, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    EventBlockRangeFlagName,
+			Usage:   "Max block range when scanning for events",
+			Value:   1000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EVENT_BLOCK_RANGE"),
+		},
+		&cli.DurationFlag{
+			Name:    ProofExpiryFlagName,
+			Usage:   "Age after which the proof of a withdrawal not finalized is considered expired (e.g. before an upgrade of the portal invalidating the proofs), disabled when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "PROOF_EXPIRY"),
+		},
+		&cli.DurationFlag{
+			Name:    ExpiryWarningFlagName,
+			Usage:   "Time before the expiry of a proof from which the withdrawal is reported as nearing expiry",
+			Value:   24 * time.Hour,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EXPIRY_WARNING"),
+		},
+	}
+}
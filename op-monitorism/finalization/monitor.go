@@ -0,0 +1,377 @@
+package finalization
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "finalization"
+	MetricsNamespace = "finalization_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the withdrawal hash.
+	EarlyFinalizationRule = "withdrawal finalized early"
+	InvalidatedProofRule  = "proof invalidated"
+	NearingExpiryRule     = "proof nearing expiry"
+)
+
+// provenWithdrawal is a withdrawal proven on L1 and not yet finalized.
+type provenWithdrawal struct {
+	provenAt      uint64
+	l2OutputIndex uint64
+	l1TxHash      common.Hash
+
+	// invalidated is true when the output proposal of the proof was deleted, the withdrawal must be proven again.
+	invalidated bool
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+
+	portalAddress common.Address
+	portal        *bindings.OptimismPortalCaller
+	portalEvents  *bindings.OptimismPortalFilterer
+	portalABI     *abi.ABI
+	oracleAddress common.Address
+	oracleEvents  *bindings.L2OutputOracleFilterer
+	oracleABI     *abi.ABI
+
+	finalizationPeriod uint64
+	proofExpiry        uint64
+	expiryWarning      uint64
+
+	nextL1Height    uint64
+	eventBlockRange uint64
+
+	withdrawals map[common.Hash]*provenWithdrawal
+
+	// metrics
+	highestBlockNumber   *prometheus.GaugeVec
+	provenWithdrawals    prometheus.Counter
+	finalizedWithdrawals *prometheus.CounterVec
+	finalizationDelay    prometheus.Histogram
+	pendingWithdrawals   *prometheus.GaugeVec
+	nearingExpiry        prometheus.Gauge
+	oldestFinalizableAge prometheus.Gauge
+	unexpectedRpcErrors  *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating finalization monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+
+	portal, err := bindings.NewOptimismPortalCaller(cfg.OptimismPortalAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the OptimismPortal: %w", err)
+	}
+	portalEvents, err := bindings.NewOptimismPortalFilterer(cfg.OptimismPortalAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the OptimismPortal: %w", err)
+	}
+	oracleAddress, err := portal.L2Oracle(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the L2OutputOracle address: %w", err)
+	}
+	oracle, err := bindings.NewL2OutputOracleCaller(oracleAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L2OutputOracle: %w", err)
+	}
+	oracleEvents, err := bindings.NewL2OutputOracleFilterer(oracleAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L2OutputOracle: %w", err)
+	}
+	finalizationPeriod, err := oracle.FINALIZATIONPERIODSECONDS(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the finalization period: %w", err)
+	}
+
+	portalABI, err := bindings.OptimismPortalMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the OptimismPortal ABI: %w", err)
+	}
+	oracleABI, err := bindings.L2OutputOracleMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the L2OutputOracle ABI: %w", err)
+	}
+
+	startingL1Height := cfg.StartBlockHeight
+	if startingL1Height == 0 {
+		if startingL1Height, err = l1Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+	log.Info("configured starting height", "height", startingL1Height, "l2_output_oracle", oracleAddress, "finalization_period", finalizationPeriod, "proof_expiry", cfg.ProofExpiry)
+
+	return &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+
+		portalAddress: cfg.OptimismPortalAddress,
+		portal:        portal,
+		portalEvents:  portalEvents,
+		portalABI:     portalABI,
+		oracleAddress: oracleAddress,
+		oracleEvents:  oracleEvents,
+		oracleABI:     oracleABI,
+
+		finalizationPeriod: finalizationPeriod.Uint64(),
+		proofExpiry:        uint64(cfg.ProofExpiry.Seconds()),
+		expiryWarning:      uint64(cfg.ExpiryWarning.Seconds()),
+
+		nextL1Height:    startingL1Height,
+		eventBlockRange: cfg.EventBlockRange,
+
+		withdrawals: make(map[common.Hash]*provenWithdrawal),
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed l1 heights (checked and known)",
+		}, []string{"type"}),
+		provenWithdrawals: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "provenWithdrawals",
+			Help:      "number of withdrawals proven, proven again included",
+		}),
+		finalizedWithdrawals: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "finalizedWithdrawals",
+			Help:      "number of withdrawals finalized by timing (early, onTime) and success",
+		}, []string{"timing", "success"}),
+		finalizationDelay: m.NewHistogram(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Name:      "finalizationDelay",
+			Help:      "seconds between the proof and the finalization of the withdrawals",
+			Buckets:   prometheus.ExponentialBuckets(3600, 2, 10),
+		}),
+		pendingWithdrawals: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "pendingWithdrawals",
+			Help:      "number of proven withdrawals not finalized by state (waiting, finalizable, invalidated)",
+		}, []string{"state"}),
+		nearingExpiry: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "nearingExpiry",
+			Help:      "number of proven withdrawals not finalized whose proof is nearing or past its expiry",
+		}),
+		oldestFinalizableAge: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "oldestFinalizableAge",
+			Help:      "seconds since the oldest withdrawal not finalized became finalizable",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latest, err := m.l1Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		m.log.Error("failed to query latest block", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "headerByNumber").Inc()
+		return
+	}
+	latestL1Height := latest.Number.Uint64()
+	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestL1Height))
+
+	if m.nextL1Height <= latestL1Height {
+		toBlock := min(latestL1Height, m.nextL1Height+m.eventBlockRange-1)
+		if err := m.processEvents(ctx, m.nextL1Height, toBlock); err != nil {
+			m.log.Error("failed to process the events", "from", m.nextL1Height, "to", toBlock, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l1", "processEvents").Inc()
+			return
+		}
+		m.highestBlockNumber.WithLabelValues("checked").Set(float64(toBlock))
+		m.nextL1Height = toBlock + 1
+	}
+
+	m.reportWithdrawals(ctx, latest.Time)
+}
+
+// processEvents tracks the withdrawals proven and finalized between the two L1 blocks (inclusive), and the proofs invalidated by the deletion of their output proposal.
+func (m *Monitor) processEvents(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	logs, err := m.l1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{m.portalAddress, m.oracleAddress},
+		Topics: [][]common.Hash{{
+			m.portalABI.Events["WithdrawalProven"].ID,
+			m.portalABI.Events["WithdrawalFinalized"].ID,
+			m.oracleABI.Events["OutputsDeleted"].ID,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the events: %w", err)
+	}
+
+	timestamps := make(map[common.Hash]uint64)
+	for _, vLog := range logs {
+		switch {
+		case vLog.Address == m.portalAddress && vLog.Topics[0] == m.portalABI.Events["WithdrawalProven"].ID:
+			proven, err := m.portalEvents.ParseWithdrawalProven(vLog)
+			if err != nil {
+				return fmt.Errorf("failed to parse the proven withdrawal %s: %w", vLog.TxHash, err)
+			}
+			proof, err := m.portal.ProvenWithdrawals(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(vLog.BlockNumber)}, proven.WithdrawalHash)
+			if err != nil {
+				return fmt.Errorf("failed to query the proof of %s: %w", common.Hash(proven.WithdrawalHash), err)
+			}
+
+			m.withdrawals[proven.WithdrawalHash] = &provenWithdrawal{provenAt: proof.Timestamp.Uint64(), l2OutputIndex: proof.L2OutputIndex.Uint64(), l1TxHash: vLog.TxHash}
+			m.provenWithdrawals.Inc()
+			m.log.Info("withdrawal proven", "withdrawal_hash", common.Hash(proven.WithdrawalHash), "l1_tx", vLog.TxHash, "l2_output_index", proof.L2OutputIndex, "from", proven.From, "to", proven.To)
+
+		case vLog.Address == m.portalAddress && vLog.Topics[0] == m.portalABI.Events["WithdrawalFinalized"].ID:
+			finalized, err := m.portalEvents.ParseWithdrawalFinalized(vLog)
+			if err != nil {
+				return fmt.Errorf("failed to parse the finalized withdrawal %s: %w", vLog.TxHash, err)
+			}
+
+			// The proof is kept after the finalization, the withdrawals proven before the starting height are checked as well.
+			proof, err := m.portal.ProvenWithdrawals(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(vLog.BlockNumber)}, finalized.WithdrawalHash)
+			if err != nil {
+				return fmt.Errorf("failed to query the proof of %s: %w", common.Hash(finalized.WithdrawalHash), err)
+			}
+			finalizedAt, ok := timestamps[vLog.BlockHash]
+			if !ok {
+				header, err := m.l1Client.HeaderByHash(ctx, vLog.BlockHash)
+				if err != nil {
+					return fmt.Errorf("failed to query the header of %s: %w", vLog.BlockHash, err)
+				}
+				finalizedAt, timestamps[vLog.BlockHash] = header.Time, header.Time
+			}
+			m.checkFinalization(ctx, finalized.WithdrawalHash, finalized.Success, proof.Timestamp.Uint64(), finalizedAt, vLog.TxHash)
+			m.Update(ctx, alert(NearingExpiryRule, "P2", finalized.WithdrawalHash, vLog.TxHash, fmt.Sprintf("withdrawal %s finalized", common.Hash(finalized.WithdrawalHash))), false)
+			delete(m.withdrawals, finalized.WithdrawalHash)
+
+		case vLog.Address == m.oracleAddress && vLog.Topics[0] == m.oracleABI.Events["OutputsDeleted"].ID:
+			deleted, err := m.oracleEvents.ParseOutputsDeleted(vLog)
+			if err != nil {
+				return fmt.Errorf("failed to parse the deleted outputs %s: %w", vLog.TxHash, err)
+			}
+			m.invalidateProofs(ctx, deleted.NewNextOutputIndex.Uint64())
+		}
+	}
+	return nil
+}
+
+// checkFinalization reports the timing of a finalization, a withdrawal finalized before the end of its finalization period should not be possible.
+func (m *Monitor) checkFinalization(ctx context.Context, withdrawalHash common.Hash, success bool, provenAt uint64, finalizedAt uint64, l1TxHash common.Hash) {
+	delay := float64(finalizedAt) - float64(provenAt)
+	m.finalizationDelay.Observe(delay)
+
+	timing := "onTime"
+	if isEarly(provenAt, finalizedAt, m.finalizationPeriod) {
+		timing = "early"
+		m.log.Error("withdrawal finalized before the end of its finalization period", "withdrawal_hash", withdrawalHash, "l1_tx", l1TxHash, "proven_at", provenAt, "finalized_at", finalizedAt, "finalization_period", m.finalizationPeriod)
+		m.Emit(ctx, alert(EarlyFinalizationRule, "P0", withdrawalHash, l1TxHash, fmt.Sprintf("withdrawal %s proven at %d finalized at %d, before the end of its finalization period of %ds", withdrawalHash, provenAt, finalizedAt, m.finalizationPeriod)))
+	} else {
+		m.log.Info("withdrawal finalized", "withdrawal_hash", withdrawalHash, "l1_tx", l1TxHash, "success", success, "delay", delay)
+	}
+	m.finalizedWithdrawals.WithLabelValues(timing, fmt.Sprint(success)).Inc()
+}
+
+// invalidateProofs marks the proofs of the deleted output proposals as invalidated.
+func (m *Monitor) invalidateProofs(ctx context.Context, newNextOutputIndex uint64) {
+	for withdrawalHash, w := range m.withdrawals {
+		if w.l2OutputIndex >= newNextOutputIndex && !w.invalidated {
+			w.invalidated = true
+			m.log.Warn("proof invalidated by the deletion of its output proposal", "withdrawal_hash", withdrawalHash, "l1_tx", w.l1TxHash, "l2_output_index", w.l2OutputIndex)
+			m.Emit(ctx, alert(InvalidatedProofRule, "P2", withdrawalHash, w.l1TxHash, fmt.Sprintf("proof of the withdrawal %s invalidated by the deletion of the output proposal %d", withdrawalHash, w.l2OutputIndex)))
+		}
+	}
+}
+
+// reportWithdrawals reports the proven withdrawals not finalized by state at the L1 timestamp `now`.
+func (m *Monitor) reportWithdrawals(ctx context.Context, now uint64) {
+	counts := map[string]int{"waiting": 0, "finalizable": 0, "invalidated": 0}
+	nearing := 0
+	var oldestFinalizable uint64
+	for withdrawalHash, w := range m.withdrawals {
+		state := withdrawalState(w, now, m.finalizationPeriod)
+		counts[state]++
+		if state == "finalizable" {
+			oldestFinalizable = max(oldestFinalizable, now-(w.provenAt+m.finalizationPeriod))
+		}
+		expiring := isNearingExpiry(w.provenAt, now, m.proofExpiry, m.expiryWarning)
+		if expiring {
+			nearing++
+		}
+		m.Update(ctx, alert(NearingExpiryRule, "P2", withdrawalHash, w.l1TxHash, fmt.Sprintf("proof of the withdrawal %s proven at %d nearing its expiry", withdrawalHash, w.provenAt)), expiring)
+	}
+
+	for state, count := range counts {
+		m.pendingWithdrawals.WithLabelValues(state).Set(float64(count))
+	}
+	m.nearingExpiry.Set(float64(nearing))
+	m.oldestFinalizableAge.Set(float64(oldestFinalizable))
+}
+
+// alert returns the alert of the rule about the withdrawal.
+func alert(rule string, priority string, withdrawalHash common.Hash, l1TxHash common.Hash, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   withdrawalHash.String(),
+		Summary:  summary,
+		Labels:   map[string]string{"l1_tx": l1TxHash.String()},
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	return nil
+}
+
+// withdrawalState returns the state of a proven withdrawal not finalized: waiting for its finalization period, finalizable or invalidated.
+func withdrawalState(w *provenWithdrawal, now uint64, finalizationPeriod uint64) string {
+	switch {
+	case w.invalidated:
+		return "invalidated"
+	case now > w.provenAt+finalizationPeriod:
+		return "finalizable"
+	default:
+		return "waiting"
+	}
+}
+
+// isEarly returns true when the withdrawal is finalized before the end of its finalization period, the portal requiring a timestamp strictly after it.
+func isEarly(provenAt uint64, finalizedAt uint64, finalizationPeriod uint64) bool {
+	return finalizedAt <= provenAt+finalizationPeriod
+}
+
+// isNearingExpiry returns true when the proof expires within the warning, or is already expired.
+func isNearingExpiry(provenAt uint64, now uint64, proofExpiry uint64, expiryWarning uint64) bool {
+	if proofExpiry == 0 {
+		return false
+	}
+	return now+expiryWarning >= provenAt+proofExpiry
+}
@@ -0,0 +1,237 @@
+package finalization
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const week = 7 * 24 * 60 * 60
+
+var (
+	portal = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+	oracle = common.HexToAddress("0xdfe97868233d1aa22e815a266982f2cf17685a27")
+
+	portalABI = util.MustParseABI(bindings.OptimismPortalMetaData)
+	oracleABI = util.MustParseABI(bindings.L2OutputOracleMetaData)
+)
+
+// proof is a proof of a withdrawal served by the portal.
+type proof struct {
+	timestamp     uint64
+	l2OutputIndex uint64
+}
+
+// portalNode is an L1 node with the events of the portal and the oracle, and the proofs of the withdrawals.
+type portalNode struct {
+	*fake.Node
+
+	proofs map[common.Hash]proof
+}
+
+// newPortalNode returns the node of a portal whose oracle has a finalization period of 100 seconds.
+func newPortalNode(t *testing.T) *portalNode {
+	n := &portalNode{Node: fake.NewNode(t), proofs: make(map[common.Hash]proof)}
+	n.AddBlock(&types.Header{})
+	n.Contract(oracle, oracleABI).Returns("FINALIZATION_PERIOD_SECONDS", big.NewInt(100))
+	n.Contract(portal, portalABI).
+		Returns("l2Oracle", oracle).
+		Handle("provenWithdrawals", func(args []interface{}) ([]interface{}, error) {
+			p := n.proofs[args[0].([32]byte)]
+			return []interface{}{[32]byte{}, new(big.Int).SetUint64(p.timestamp), new(big.Int).SetUint64(p.l2OutputIndex)}, nil
+		})
+	return n
+}
+
+// addBlock appends a block at the time, returning its header.
+func (n *portalNode) addBlock(timestamp uint64) *types.Header {
+	return n.AddBlock(&types.Header{Time: timestamp})
+}
+
+// addEvent adds the event of the contract to the block, the indexed arguments being the topics.
+func (n *portalNode) addEvent(t *testing.T, header *types.Header, address common.Address, event abi.Event, topics []common.Hash, args ...interface{}) {
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	n.AddLogs(types.Log{Address: address, Topics: append([]common.Hash{event.ID}, topics...), Data: data, BlockNumber: header.Number.Uint64(), BlockHash: header.Hash(), TxHash: common.HexToHash("0x0f")})
+}
+
+// prove proves the withdrawal in the block against the output proposal.
+func (n *portalNode) prove(t *testing.T, header *types.Header, withdrawalHash common.Hash, l2OutputIndex uint64) {
+	n.proofs[withdrawalHash] = proof{timestamp: header.Time, l2OutputIndex: l2OutputIndex}
+	n.addEvent(t, header, portal, portalABI.Events["WithdrawalProven"], []common.Hash{withdrawalHash, {}, {}})
+}
+
+// newTestMonitor returns the monitor of the portal from the block 1 with proofs expiring after 1000 seconds, and the
+// notifier of its alerts.
+func newTestMonitor(t *testing.T, node *portalNode) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{L1NodeURL: node.URL, OptimismPortalAddress: portal, StartBlockHeight: 1, EventBlockRange: 100, ProofExpiry: 1000 * time.Second, ExpiryWarning: 100 * time.Second}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestWithdrawalState(t *testing.T) {
+	tests := []struct {
+		name       string
+		withdrawal provenWithdrawal
+		now        uint64
+		expected   string
+	}{
+		{name: "just proven", withdrawal: provenWithdrawal{provenAt: 1000}, now: 1000, expected: "waiting"},
+		{name: "end of the period", withdrawal: provenWithdrawal{provenAt: 1000}, now: 1000 + week, expected: "waiting"},
+		{name: "after the period", withdrawal: provenWithdrawal{provenAt: 1000}, now: 1001 + week, expected: "finalizable"},
+		{name: "invalidated", withdrawal: provenWithdrawal{provenAt: 1000, invalidated: true}, now: 1001 + week, expected: "invalidated"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := withdrawalState(&test.withdrawal, test.now, week); got != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsEarly(t *testing.T) {
+	tests := []struct {
+		name        string
+		provenAt    uint64
+		finalizedAt uint64
+		expected    bool
+	}{
+		{name: "same block", provenAt: 1000, finalizedAt: 1000, expected: true},
+		{name: "end of the period", provenAt: 1000, finalizedAt: 1000 + week, expected: true},
+		{name: "after the period", provenAt: 1000, finalizedAt: 1001 + week, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isEarly(test.provenAt, test.finalizedAt, week); got != test.expected {
+				t.Errorf("Failed %s: expected %v but got %v", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsNearingExpiry(t *testing.T) {
+	day := uint64(24 * 60 * 60)
+	tests := []struct {
+		name     string
+		now      uint64
+		expiry   uint64
+		expected bool
+	}{
+		{name: "disabled", now: 1000 + 10*week, expiry: 0, expected: false},
+		{name: "far from expiry", now: 1000 + week, expiry: 2 * week, expected: false},
+		{name: "within the warning", now: 1000 + 2*week - day/2, expiry: 2 * week, expected: true},
+		{name: "expired", now: 1000 + 3*week, expiry: 2 * week, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isNearingExpiry(1000, test.now, test.expiry, day); got != test.expected {
+				t.Errorf("Failed %s: expected %v but got %v", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	node := newPortalNode(t)
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// the proven withdrawals wait for their finalization period.
+	first, second := common.HexToHash("0x01"), common.HexToHash("0x02")
+	header := node.addBlock(1000)
+	node.prove(t, header, first, 3)
+	node.prove(t, header, second, 3)
+	m.Run(ctx)
+	if proven, waiting := testutil.ToFloat64(m.provenWithdrawals), testutil.ToFloat64(m.pendingWithdrawals.WithLabelValues("waiting")); proven != 2 || waiting != 2 {
+		t.Errorf("expected 2 withdrawals proven and waiting but got %v and %v", proven, waiting)
+	}
+
+	// past their finalization period, the withdrawals are finalizable until finalized.
+	header = node.addBlock(1150)
+	node.addEvent(t, header, portal, portalABI.Events["WithdrawalFinalized"], []common.Hash{first}, true)
+	m.Run(ctx)
+	if onTime, finalizable, oldest := testutil.ToFloat64(m.finalizedWithdrawals.WithLabelValues("onTime", "true")), testutil.ToFloat64(m.pendingWithdrawals.WithLabelValues("finalizable")), testutil.ToFloat64(m.oldestFinalizableAge); onTime != 1 || finalizable != 1 || oldest != 50 {
+		t.Errorf("expected 1 withdrawal finalized on time and 1 finalizable for 50s but got %v, %v and %v", onTime, finalizable, oldest)
+	}
+
+	// the proof nearing its expiry is counted, and its withdrawal invalidated by the deletion of its output proposal.
+	node.addBlock(1950)
+	m.Run(ctx)
+	if nearing := testutil.ToFloat64(m.nearingExpiry); nearing != 1 {
+		t.Errorf("expected 1 proof nearing its expiry but got %v", nearing)
+	}
+	header = node.addBlock(1960)
+	node.addEvent(t, header, oracle, oracleABI.Events["OutputsDeleted"], []common.Hash{common.BigToHash(big.NewInt(4)), common.BigToHash(big.NewInt(3))})
+	m.Run(ctx)
+	if invalidated, finalizable := testutil.ToFloat64(m.pendingWithdrawals.WithLabelValues("invalidated")), testutil.ToFloat64(m.pendingWithdrawals.WithLabelValues("finalizable")); invalidated != 1 || finalizable != 0 {
+		t.Errorf("expected the withdrawal invalidated but got %v invalidated and %v finalizable", invalidated, finalizable)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newPortalNode(t)
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	invalidated, early := common.HexToHash("0x01"), common.HexToHash("0x02")
+	header := node.addBlock(1000)
+	node.prove(t, header, invalidated, 5)
+	node.prove(t, header, early, 3)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// the deletion of its output proposal invalidates a proof once, and a finalization before the end of its period fires.
+	header = node.addBlock(1050)
+	node.addEvent(t, header, oracle, oracleABI.Events["OutputsDeleted"], []common.Hash{common.BigToHash(big.NewInt(6)), common.BigToHash(big.NewInt(4))})
+	node.addEvent(t, header, portal, portalABI.Events["WithdrawalFinalized"], []common.Hash{early}, true)
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(InvalidatedProofRule); len(alerts) != 1 || alerts[0].Entity != invalidated.String() || alerts[0].Priority != "P2" {
+		t.Fatalf("expected the invalidated proof alert once but got %v", alerts)
+	}
+	if alerts := notifier.Alerts(EarlyFinalizationRule); len(alerts) != 1 || alerts[0].Entity != early.String() || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the early finalization alert once but got %v", alerts)
+	}
+
+	// a proof nearing its expiry fires once, and resolves once the withdrawal is finalized.
+	node.addBlock(1950)
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(NearingExpiryRule); len(alerts) != 1 || alerts[0].Entity != invalidated.String() {
+		t.Fatalf("expected the nearing expiry alert once but got %v", alerts)
+	}
+	header = node.addBlock(1960)
+	node.addEvent(t, header, portal, portalABI.Events["WithdrawalFinalized"], []common.Hash{invalidated}, true)
+	m.Run(ctx)
+	if firing := notifier.Firing(NearingExpiryRule); len(firing) != 0 || len(m.withdrawals) != 0 {
+		t.Fatalf("expected the nearing expiry alert resolved but got %v", firing)
+	}
+}
@@ -0,0 +1,118 @@
+package game_registry
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+
+	DisputeGameFactoryAddressFlagName = "disputegamefactory.address"
+	GameTypesFlagName                 = "game.types"
+	ProposerAddressFlagName           = "proposer.address"
+	ChallengerAddressFlagName         = "challenger.address"
+	StartBlockHeightFlagName          = "start.block.height"
+	EventBlockRangeFlagName           = "event.block.range"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+
+	DisputeGameFactoryAddress common.Address
+
+	// GameTypes are the game types monitored in addition to the ones registered after the starting height.
+	GameTypes []uint32
+
+	// ProposerAddress and ChallengerAddress are the expected roles of the permissioned games, not checked when nil.
+	ProposerAddress   *common.Address
+	ChallengerAddress *common.Address
+
+	StartBlockHeight uint64
+	EventBlockRange  uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:        ctx.String(L1NodeURLFlagName),
+		StartBlockHeight: ctx.Uint64(StartBlockHeightFlagName),
+		EventBlockRange:  ctx.Uint64(EventBlockRangeFlagName),
+	}
+
+	factoryAddress := ctx.String(DisputeGameFactoryAddressFlagName)
+	if !common.IsHexAddress(factoryAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", DisputeGameFactoryAddressFlagName)
+	}
+	cfg.DisputeGameFactoryAddress = common.HexToAddress(factoryAddress)
+
+	for _, gameType := range ctx.UintSlice(GameTypesFlagName) {
+		cfg.GameTypes = append(cfg.GameTypes, uint32(gameType))
+	}
+
+	for flagName, address := range map[string]**common.Address{ProposerAddressFlagName: &cfg.ProposerAddress, ChallengerAddressFlagName: &cfg.ChallengerAddress} {
+		if !ctx.IsSet(flagName) {
+			continue
+		}
+		value := ctx.String(flagName)
+		if !common.IsHexAddress(value) {
+			return cfg, fmt.Errorf("--%s is not a hex-encoded address", flagName)
+		}
+		parsed := common.HexToAddress(value)
+		*address = &parsed
+	}
+
+	if cfg.EventBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", EventBlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     DisputeGameFactoryAddressFlagName,
+			Usage:    "Address of the DisputeGameFactory contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "DISPUTE_GAME_FACTORY"),
+			Required: true,
+		},
+		&cli.UintSliceFlag{
+			Name:    GameTypesFlagName,
+			Usage:   "Game types monitored in addition to the ones registered after the starting height",
+			Value:   cli.NewUintSlice(0, 1),
+			EnvVars: opservice.PrefixEnvVar(envVar, "GAME_TYPES"),
+		},
+		&cli.StringFlag{
+			Name:    ProposerAddressFlagName,
+			Usage:   "Expected proposer of the permissioned games, not checked when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "PROPOSER"),
+		},
+		&cli.StringFlag{
+			Name:    ChallengerAddressFlagName,
+			Usage:   "Expected challenger of the permissioned games, not checked when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "CHALLENGER"),
+		},
+		&cli.Uint64Flag{
+			Name:    StartBlockHeightFlagName,
+			Usage:   "Starting height to scan for implementation registrations, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    EventBlockRangeFlagName,
+			Usage:   "Max block range when scanning for implementation registrations",
+			Value:   1000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EVENT_BLOCK_RANGE"),
+		},
+	}
+}
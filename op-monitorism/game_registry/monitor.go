@@ -0,0 +1,364 @@
+package game_registry
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "game_registry"
+	MetricsNamespace = "game_registry_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the game type or the role of the game type.
+	ImplementationSetRule = "game implementation set"
+	RoleChangedRule       = "role changed"
+	RoleMismatchRule      = "unexpected role"
+
+	// PermissionedDisputeGameABI is the subset of the PermissionedDisputeGame used by the monitor, the roles being immutables of the implementation.
+	PermissionedDisputeGameABI = `[{"inputs":[],"name":"proposer","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"challenger","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}]`
+)
+
+var (
+	permissionedDisputeGameABI = util.MustParseJSONABI(PermissionedDisputeGameABI)
+
+	// roles are the roles of the permissioned games.
+	roles = []string{"proposer", "challenger"}
+)
+
+// gameType is the state of a game type observed at the previous iteration.
+type gameType struct {
+	implementation common.Address
+	roles          map[string]common.Address
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+
+	factoryAddress common.Address
+	factory        *bindings.DisputeGameFactoryCaller
+	factoryEvents  *bindings.DisputeGameFactoryFilterer
+	factoryABI     *abi.ABI
+
+	expectedRoles map[string]*common.Address
+
+	nextL1Height    uint64
+	eventBlockRange uint64
+
+	gameTypes map[uint32]*gameType
+
+	// metrics
+	highestBlockNumber  *prometheus.GaugeVec
+	implementation      *prometheus.GaugeVec
+	implementationSets  *prometheus.CounterVec
+	initBond            *prometheus.GaugeVec
+	role                *prometheus.GaugeVec
+	roleChanges         *prometheus.CounterVec
+	roleMismatch        *prometheus.GaugeVec
+	registeredGameTypes prometheus.Gauge
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating game registry monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+
+	factory, err := bindings.NewDisputeGameFactoryCaller(cfg.DisputeGameFactoryAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the DisputeGameFactory: %w", err)
+	}
+	factoryEvents, err := bindings.NewDisputeGameFactoryFilterer(cfg.DisputeGameFactoryAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the DisputeGameFactory: %w", err)
+	}
+	factoryABI, err := bindings.DisputeGameFactoryMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the DisputeGameFactory ABI: %w", err)
+	}
+
+	startingL1Height := cfg.StartBlockHeight
+	if startingL1Height == 0 {
+		if startingL1Height, err = l1Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+	log.Info("configured starting height", "height", startingL1Height, "game_types", cfg.GameTypes, "proposer", cfg.ProposerAddress, "challenger", cfg.ChallengerAddress)
+
+	gameTypes := make(map[uint32]*gameType, len(cfg.GameTypes))
+	for _, t := range cfg.GameTypes {
+		gameTypes[t] = &gameType{roles: make(map[string]common.Address)}
+	}
+
+	return &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+
+		factoryAddress: cfg.DisputeGameFactoryAddress,
+		factory:        factory,
+		factoryEvents:  factoryEvents,
+		factoryABI:     factoryABI,
+
+		expectedRoles: map[string]*common.Address{"proposer": cfg.ProposerAddress, "challenger": cfg.ChallengerAddress},
+
+		nextL1Height:    startingL1Height,
+		eventBlockRange: cfg.EventBlockRange,
+
+		gameTypes: gameTypes,
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed l1 heights (checked and known)",
+		}, []string{"type"}),
+		implementation: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "implementation",
+			Help:      "implementation registered for the game type",
+		}, []string{"gameType", "implementation"}),
+		implementationSets: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "implementationSets",
+			Help:      "number of implementations set for the game type by kind (registered, changed, removed)",
+		}, []string{"gameType", "kind"}),
+		initBond: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "initBond",
+			Help:      "bond required to create a game of the game type, in ETH",
+		}, []string{"gameType"}),
+		role: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "role",
+			Help:      "address of the role (proposer, challenger) of the permissioned game type",
+		}, []string{"gameType", "role", "address"}),
+		roleChanges: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "roleChanges",
+			Help:      "number of changes of the role of the permissioned game type",
+		}, []string{"gameType", "role"}),
+		roleMismatch: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "roleMismatch",
+			Help:      "1 if the role of the permissioned game type is not the expected one, 0 otherwise",
+		}, []string{"gameType", "role"}),
+		registeredGameTypes: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "registeredGameTypes",
+			Help:      "number of monitored game types with an implementation",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestL1Height))
+
+	if m.nextL1Height <= latestL1Height {
+		toBlock := min(latestL1Height, m.nextL1Height+m.eventBlockRange-1)
+		if err := m.processImplementationSets(ctx, m.nextL1Height, toBlock); err != nil {
+			m.log.Error("failed to process the implementation registrations", "from", m.nextL1Height, "to", toBlock, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("DisputeGameFactory", "ImplementationSet").Inc()
+			return
+		}
+		m.highestBlockNumber.WithLabelValues("checked").Set(float64(toBlock))
+		m.nextL1Height = toBlock + 1
+	}
+
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(latestL1Height)}
+	registered := 0
+	for _, t := range m.sortedGameTypes() {
+		if err := m.checkGameType(ctx, callOpts, t); err != nil {
+			m.log.Error("failed to check the game type", "game_type", t, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("DisputeGameFactory", "gameImpls").Inc()
+			continue
+		}
+		if m.gameTypes[t].implementation != (common.Address{}) {
+			registered++
+		}
+	}
+	m.registeredGameTypes.Set(float64(registered))
+}
+
+// processImplementationSets reports the implementations set on the factory between the two L1 blocks (inclusive).
+func (m *Monitor) processImplementationSets(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	logs, err := m.l1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{m.factoryAddress},
+		Topics:    [][]common.Hash{{m.factoryABI.Events["ImplementationSet"].ID}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the ImplementationSet events: %w", err)
+	}
+
+	for _, vLog := range logs {
+		set, err := m.factoryEvents.ParseImplementationSet(vLog)
+		if err != nil {
+			return fmt.Errorf("failed to parse the implementation set %s: %w", vLog.TxHash, err)
+		}
+		previous, err := m.factory.GameImpls(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(vLog.BlockNumber - 1)}, set.GameType)
+		if err != nil {
+			return fmt.Errorf("failed to query the previous implementation of the game type %d: %w", set.GameType, err)
+		}
+
+		kind := registrationKind(previous, set.Impl)
+		m.implementationSets.WithLabelValues(gameTypeLabel(set.GameType), kind).Inc()
+		m.log.Warn("game implementation set", "game_type", set.GameType, "kind", kind, "previous", previous, "implementation", set.Impl, "l1_tx", vLog.TxHash, "l1_block", vLog.BlockNumber)
+		m.Emit(ctx, alert(ImplementationSetRule, "P1", gameTypeLabel(set.GameType), "", fmt.Sprintf("implementation of the game type %d %s from %s to %s in the transaction %s", set.GameType, kind, previous, set.Impl, vLog.TxHash)))
+
+		if _, ok := m.gameTypes[set.GameType]; !ok {
+			m.gameTypes[set.GameType] = &gameType{roles: make(map[string]common.Address)}
+		}
+	}
+	return nil
+}
+
+// checkGameType reports the implementation of the game type, its init bond and its roles when permissioned.
+func (m *Monitor) checkGameType(ctx context.Context, callOpts *bind.CallOpts, t uint32) error {
+	state, label := m.gameTypes[t], gameTypeLabel(t)
+
+	implementation, err := m.factory.GameImpls(callOpts, t)
+	if err != nil {
+		return fmt.Errorf("failed to query the implementation: %w", err)
+	}
+	bond, err := m.factory.InitBonds(callOpts, t)
+	if err != nil {
+		return fmt.Errorf("failed to query the init bond: %w", err)
+	}
+	m.initBond.WithLabelValues(label).Set(util.WeiToEther(bond))
+
+	if implementation != state.implementation {
+		m.implementation.DeleteLabelValues(label, state.implementation.String())
+		state.implementation = implementation
+	}
+	m.implementation.WithLabelValues(label, implementation.String()).Set(1)
+	if implementation == (common.Address{}) {
+		return nil
+	}
+
+	for _, r := range roles {
+		address, ok := m.readRole(ctx, callOpts, implementation, r)
+		if !ok { // not a permissioned game.
+			continue
+		}
+
+		previous, known := state.roles[r]
+		if known && previous != address {
+			m.log.Warn("role of the permissioned game changed", "game_type", t, "role", r, "previous", previous, "address", address, "implementation", implementation)
+			m.roleChanges.WithLabelValues(label, r).Inc()
+			m.Emit(ctx, alert(RoleChangedRule, "P1", label, r, fmt.Sprintf("%s of the game type %d changed from %s to %s", r, t, previous, address)))
+			m.role.DeleteLabelValues(label, r, previous.String())
+		}
+		state.roles[r] = address
+		m.role.WithLabelValues(label, r, address.String()).Set(1)
+
+		if expected := m.expectedRoles[r]; expected != nil {
+			mismatch := *expected != address
+			if mismatch {
+				m.log.Error("unexpected role of the permissioned game", "game_type", t, "role", r, "expected", *expected, "address", address)
+			}
+			m.roleMismatch.WithLabelValues(label, r).Set(util.BoolToFloat(mismatch))
+			m.Update(ctx, alert(RoleMismatchRule, "P0", label, r, fmt.Sprintf("%s of the game type %d is %s, expected %s", r, t, address, *expected)), mismatch)
+		}
+	}
+	return nil
+}
+
+// alert returns the alert of the rule about the game type, or about its role when given.
+func alert(rule string, priority string, gameType string, role string, summary string) alerts.Alert {
+	entity, labels := gameType, map[string]string{"game_type": gameType}
+	if len(role) > 0 {
+		entity, labels["role"] = gameType+"/"+role, role
+	}
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   entity,
+		Summary:  summary,
+		Labels:   labels,
+	}
+}
+
+// readRole returns the address of the role of a permissioned game implementation, false when the implementation doesn't have this role.
+func (m *Monitor) readRole(ctx context.Context, callOpts *bind.CallOpts, implementation common.Address, role string) (common.Address, bool) {
+	data, err := permissionedDisputeGameABI.Pack(role)
+	if err != nil {
+		return common.Address{}, false
+	}
+	out, err := m.l1Client.CallContract(ctx, ethereum.CallMsg{To: &implementation, Data: data}, callOpts.BlockNumber)
+	if err != nil {
+		return common.Address{}, false
+	}
+	unpacked, err := permissionedDisputeGameABI.Unpack(role, out)
+	if err != nil || len(unpacked) != 1 {
+		return common.Address{}, false
+	}
+	return unpacked[0].(common.Address), true
+}
+
+// sortedGameTypes returns the monitored game types in ascending order.
+func (m *Monitor) sortedGameTypes() []uint32 {
+	sorted := make([]uint32, 0, len(m.gameTypes))
+	for t := range m.gameTypes {
+		sorted = append(sorted, t)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	return nil
+}
+
+// registrationKind returns the kind of an implementation set over the previous one: registered, changed or removed.
+func registrationKind(previous common.Address, implementation common.Address) string {
+	switch {
+	case previous == (common.Address{}):
+		return "registered"
+	case implementation == (common.Address{}):
+		return "removed"
+	default:
+		return "changed"
+	}
+}
+
+func gameTypeLabel(t uint32) string {
+	return strconv.FormatUint(uint64(t), 10)
+}
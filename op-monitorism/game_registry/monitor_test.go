@@ -0,0 +1,146 @@
+package game_registry
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var (
+	factory        = common.HexToAddress("0xe5965Ab5962eDc7477C8520243A95517CD252fA9")
+	implementation = common.HexToAddress("0x050ed6F6273c7D836a111E42153BC00D0380b87d")
+	proposer       = common.HexToAddress("0x473300df21D047806A082244b417f96b32f13A33")
+	challenger     = common.HexToAddress("0x9BA6e03D8B90dE867373Db8cF1A58d2F7F006b3A")
+)
+
+// newTestMonitor returns the monitor of the permissioned game type 1 of the node from the block 1, expecting the roles,
+// and the notifier of its alerts. The roles of the implementation are served by the returned contract.
+func newTestMonitor(t *testing.T, node *fake.Node) (*Monitor, *fake.Contract, *fake.Notifier) {
+	node.Contract(factory, util.MustParseABI(bindings.DisputeGameFactoryMetaData)).
+		Returns("gameImpls", implementation).
+		Returns("initBonds", big.NewInt(8e16))
+	game := node.Contract(implementation, permissionedDisputeGameABI).
+		Returns("proposer", proposer).
+		Returns("challenger", challenger)
+
+	expectedProposer, expectedChallenger := proposer, challenger
+	cfg := CLIConfig{L1NodeURL: node.URL, DisputeGameFactoryAddress: factory, GameTypes: []uint32{1}, ProposerAddress: &expectedProposer, ChallengerAddress: &expectedChallenger, StartBlockHeight: 1, EventBlockRange: 100}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, game, notifier
+}
+
+func TestRegistrationKind(t *testing.T) {
+	implementation := common.HexToAddress("0x1")
+	upgraded := common.HexToAddress("0x2")
+
+	tests := []struct {
+		name           string
+		previous       common.Address
+		implementation common.Address
+		expected       string
+	}{
+		{name: "new game type", previous: common.Address{}, implementation: implementation, expected: "registered"},
+		{name: "upgraded implementation", previous: implementation, implementation: upgraded, expected: "changed"},
+		{name: "same implementation", previous: implementation, implementation: implementation, expected: "changed"},
+		{name: "removed implementation", previous: implementation, implementation: common.Address{}, expected: "removed"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := registrationKind(test.previous, test.implementation); got != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{})
+	m, game, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// the implementation, init bond and roles of the registered game type are reported.
+	m.Run(ctx)
+	if registered, bond := testutil.ToFloat64(m.registeredGameTypes), testutil.ToFloat64(m.initBond.WithLabelValues("1")); registered != 1 || bond != 0.08 {
+		t.Errorf("expected 1 game type registered with an init bond of 0.08 ETH but got %v and %v", registered, bond)
+	}
+	if impl, role := testutil.ToFloat64(m.implementation.WithLabelValues("1", implementation.String())), testutil.ToFloat64(m.role.WithLabelValues("1", "proposer", proposer.String())); impl != 1 || role != 1 {
+		t.Errorf("expected the implementation and the proposer reported but got %v and %v", impl, role)
+	}
+
+	// a changed role replaces its series, and is counted along with the mismatch.
+	game.Returns("proposer", common.HexToAddress("0x01"))
+	m.Run(ctx)
+	if count := testutil.CollectAndCount(m.role); count != 2 {
+		t.Errorf("expected the series of the proposer and the challenger only but got %d", count)
+	}
+	if changes, mismatch := testutil.ToFloat64(m.roleChanges.WithLabelValues("1", "proposer")), testutil.ToFloat64(m.roleMismatch.WithLabelValues("1", "proposer")); changes != 1 || mismatch != 1 {
+		t.Errorf("expected the proposer changed and mismatched but got %v and %v", changes, mismatch)
+	}
+
+	// an implementation set over the previous one is counted as changed.
+	event := m.factoryABI.Events["ImplementationSet"]
+	header := node.AddBlock(&types.Header{})
+	node.AddLogs(types.Log{Address: factory, Topics: []common.Hash{event.ID, common.BytesToHash(implementation.Bytes()), common.BigToHash(big.NewInt(1))}, BlockNumber: header.Number.Uint64(), BlockHash: header.Hash()})
+	m.Run(ctx)
+	if changed := testutil.ToFloat64(m.implementationSets.WithLabelValues("1", "changed")); changed != 1 {
+		t.Errorf("expected the implementation changed but got %v", changed)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{})
+	m, game, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a changed role fires the change once and the mismatch until the expected role is back.
+	game.Returns("proposer", common.HexToAddress("0x01"))
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(RoleChangedRule); len(alerts) != 1 || alerts[0].Entity != "1/proposer" || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the role changed alert once but got %v", alerts)
+	}
+	if alerts := notifier.Alerts(RoleMismatchRule); len(alerts) != 1 || alerts[0].Entity != "1/proposer" || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the role mismatch alert once but got %v", alerts)
+	}
+	game.Returns("proposer", proposer)
+	m.Run(ctx)
+	if firing := notifier.Firing(RoleMismatchRule); len(firing) != 0 {
+		t.Fatalf("expected the role mismatch alert resolved but got %v", firing)
+	}
+
+	// an implementation set on the factory fires once.
+	event := m.factoryABI.Events["ImplementationSet"]
+	header := node.AddBlock(&types.Header{})
+	node.AddLogs(types.Log{Address: factory, Topics: []common.Hash{event.ID, common.BytesToHash(implementation.Bytes()), common.BigToHash(big.NewInt(1))}, BlockNumber: header.Number.Uint64(), BlockHash: header.Hash()})
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(ImplementationSetRule); len(alerts) != 1 || alerts[0].Entity != "1" {
+		t.Fatalf("expected the implementation set alert once but got %v", alerts)
+	}
+}
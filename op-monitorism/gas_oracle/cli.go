@@ -0,0 +1,99 @@
+package gas_oracle
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L2NodeURLFlagName = "l2.node.url"
+
+	BoundsFlagName = "bounds"
+)
+
+// Bound is the range allowed for a parameter, a nil side is unbounded.
+type Bound struct {
+	Parameter string
+	Min       *big.Int
+	Max       *big.Int
+}
+
+type CLIConfig struct {
+	L2NodeURL string
+
+	Bounds []Bound
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L2NodeURL: ctx.String(L2NodeURLFlagName),
+	}
+
+	for _, bound := range ctx.StringSlice(BoundsFlagName) {
+		parsed, err := parseBound(bound)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Bounds = append(cfg.Bounds, parsed)
+	}
+
+	return cfg, nil
+}
+
+// parseBound parses a bound formatted via `parameter=min:max`, where either side can be omitted.
+func parseBound(bound string) (Bound, error) {
+	parameter, bounds, err := util.ParseNamedValue(bound, "parameter=min:max")
+	if err != nil {
+		return Bound{}, err
+	}
+	if !isParameter(parameter) {
+		return Bound{}, fmt.Errorf("unknown parameter %s, expected one of %s", parameter, strings.Join(Parameters, ", "))
+	}
+
+	limits := strings.Split(bounds, ":")
+	if len(limits) != 2 || (len(limits[0]) == 0 && len(limits[1]) == 0) {
+		return Bound{}, fmt.Errorf("failed to parse `parameter=min:max`: %s", bound)
+	}
+
+	parsed := Bound{Parameter: parameter}
+	for i, limit := range limits {
+		if len(limit) == 0 {
+			continue
+		}
+		value, ok := new(big.Int).SetString(limit, 10)
+		if !ok {
+			return Bound{}, fmt.Errorf("failed to parse the limit %s of %s", limit, bound)
+		}
+		if i == 0 {
+			parsed.Min = value
+		} else {
+			parsed.Max = value
+		}
+	}
+	if parsed.Min != nil && parsed.Max != nil && parsed.Min.Cmp(parsed.Max) > 0 {
+		return Bound{}, fmt.Errorf("the min is greater than the max: %s", bound)
+	}
+	return parsed, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L2NodeURLFlagName,
+			Usage:   "Node URL of L2 peer",
+			Value:   "127.0.0.1:9545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL"),
+		},
+		&cli.StringSliceFlag{
+			Name:    BoundsFlagName,
+			Usage:   "One or more ranges allowed for the parameters formatted via `parameter=min:max`, either side can be omitted",
+			EnvVars: opservice.PrefixEnvVar(envVar, "BOUNDS"),
+		},
+	}
+}
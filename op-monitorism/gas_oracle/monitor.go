@@ -0,0 +1,252 @@
+package gas_oracle
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "gas_oracle"
+	MetricsNamespace = "gas_oracle_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the fee parameter.
+	ParameterChangedRule = "fee parameter changed"
+	OutOfBoundsRule      = "fee parameter out of bounds"
+
+	BaseFeeScalar     = "baseFeeScalar"
+	BlobBaseFeeScalar = "blobBaseFeeScalar"
+	L1FeeOverhead     = "l1FeeOverhead"
+	L1FeeScalar       = "l1FeeScalar"
+	L1BaseFee         = "l1BaseFee"
+	BlobBaseFee       = "blobBaseFee"
+)
+
+var (
+	// Parameters are the fee parameters of the L1Block, the scalars and the overhead are set through the SystemConfig,
+	// while the base fees follow the L1 origin of the L2 block.
+	Parameters = []string{BaseFeeScalar, BlobBaseFeeScalar, L1FeeOverhead, L1FeeScalar, L1BaseFee, BlobBaseFee}
+
+	// configParameters are the parameters only updated by a change of the SystemConfig.
+	configParameters = map[string]bool{BaseFeeScalar: true, BlobBaseFeeScalar: true, L1FeeOverhead: true, L1FeeScalar: true}
+)
+
+func isParameter(parameter string) bool {
+	for _, p := range Parameters {
+		if p == parameter {
+			return true
+		}
+	}
+	return false
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l2Client       *ethclient.Client
+	gasPriceOracle *bindings.GasPriceOracleCaller
+	l1Block        *bindings.L1BlockCaller
+
+	bounds   map[string]Bound
+	previous map[string]*big.Int
+
+	// metrics
+	parameter           *prometheus.GaugeVec
+	outOfBounds         *prometheus.GaugeVec
+	parameterChanges    *prometheus.CounterVec
+	isEcotone           prometheus.Gauge
+	l1OriginNumber      prometheus.Gauge
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating gas oracle monitor...")
+
+	l2Client, err := ethclient.Dial(cfg.L2NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l2: %w", err)
+	}
+	gasPriceOracle, err := bindings.NewGasPriceOracleCaller(predeploys.GasPriceOracleAddr, l2Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the GasPriceOracle: %w", err)
+	}
+	l1Block, err := bindings.NewL1BlockCaller(predeploys.L1BlockAddr, l2Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L1Block: %w", err)
+	}
+
+	bounds := make(map[string]Bound, len(cfg.Bounds))
+	for _, bound := range cfg.Bounds {
+		bounds[bound.Parameter] = bound
+		log.Info("configured bound", "parameter", bound.Parameter, "min", bound.Min, "max", bound.Max)
+	}
+
+	return &Monitor{
+		log: log,
+
+		l2Client:       l2Client,
+		gasPriceOracle: gasPriceOracle,
+		l1Block:        l1Block,
+
+		bounds:   bounds,
+		previous: make(map[string]*big.Int),
+
+		parameter: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "parameter",
+			Help:      "value of the fee parameter in the L1Block",
+		}, []string{"parameter"}),
+		outOfBounds: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "outOfBounds",
+			Help:      "1 if the fee parameter is outside of its configured bounds, 0 otherwise",
+		}, []string{"parameter"}),
+		parameterChanges: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "parameterChanges",
+			Help:      "number of changes of the fee parameters set through the SystemConfig",
+		}, []string{"parameter"}),
+		isEcotone: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "isEcotone",
+			Help:      "1 if the GasPriceOracle uses the Ecotone fee formula, 0 otherwise",
+		}),
+		l1OriginNumber: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "l1OriginNumber",
+			Help:      "number of the L1 origin reported by the L1Block",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestL2Height, err := m.l2Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "blockNumber").Inc()
+		return
+	}
+
+	// pin every call to the same block so the parameters are consistent.
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(latestL2Height)}
+
+	isEcotone, err := m.gasPriceOracle.IsEcotone(callOpts)
+	if err != nil {
+		m.log.Error("failed to query isEcotone", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("GasPriceOracle", "isEcotone").Inc()
+		return
+	}
+	m.isEcotone.Set(util.BoolToFloat(isEcotone))
+
+	l1OriginNumber, err := m.l1Block.Number(callOpts)
+	if err != nil {
+		m.log.Error("failed to query the L1 origin number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("L1Block", "number").Inc()
+		return
+	}
+	m.l1OriginNumber.Set(float64(l1OriginNumber))
+
+	values, err := m.readParameters(callOpts)
+	if err != nil {
+		m.log.Error("failed to query the fee parameters", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("L1Block", "parameters").Inc()
+		return
+	}
+
+	for _, parameter := range Parameters {
+		value := values[parameter]
+		valueFloat, _ := new(big.Float).SetInt(value).Float64()
+		m.parameter.WithLabelValues(parameter).Set(valueFloat)
+
+		if previous, ok := m.previous[parameter]; ok && configParameters[parameter] && previous.Cmp(value) != 0 {
+			m.log.Warn("fee parameter changed", "parameter", parameter, "previous", previous, "current", value, "l2_block", latestL2Height)
+			m.parameterChanges.WithLabelValues(parameter).Inc()
+			m.Emit(ctx, alert(ParameterChangedRule, "P2", parameter, fmt.Sprintf("%s changed from %s to %s at l2 block %d", parameter, previous, value, latestL2Height)))
+		}
+		m.previous[parameter] = value
+
+		if bound, ok := m.bounds[parameter]; ok {
+			out := isOutOfBounds(value, bound)
+			if out {
+				m.log.Warn("fee parameter out of bounds", "parameter", parameter, "value", value, "min", bound.Min, "max", bound.Max)
+			}
+			m.outOfBounds.WithLabelValues(parameter).Set(util.BoolToFloat(out))
+			m.Update(ctx, alert(OutOfBoundsRule, "P1", parameter, fmt.Sprintf("%s is %s, out of its bounds", parameter, value)), out)
+		}
+	}
+
+	m.log.Info("checked fee parameters", "l2_block", latestL2Height, "l1_origin", l1OriginNumber, "ecotone", isEcotone)
+}
+
+// alert returns the alert of the rule about the fee parameter.
+func alert(rule string, priority string, parameter string, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   parameter,
+		Summary:  summary,
+	}
+}
+
+// readParameters reads the fee parameters of the L1Block.
+func (m *Monitor) readParameters(callOpts *bind.CallOpts) (map[string]*big.Int, error) {
+	values := make(map[string]*big.Int, len(Parameters))
+
+	baseFeeScalar, err := m.l1Block.BaseFeeScalar(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the baseFeeScalar: %w", err)
+	}
+	values[BaseFeeScalar] = new(big.Int).SetUint64(uint64(baseFeeScalar))
+
+	blobBaseFeeScalar, err := m.l1Block.BlobBaseFeeScalar(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the blobBaseFeeScalar: %w", err)
+	}
+	values[BlobBaseFeeScalar] = new(big.Int).SetUint64(uint64(blobBaseFeeScalar))
+
+	if values[L1FeeOverhead], err = m.l1Block.L1FeeOverhead(callOpts); err != nil {
+		return nil, fmt.Errorf("failed to query the l1FeeOverhead: %w", err)
+	}
+	if values[L1FeeScalar], err = m.l1Block.L1FeeScalar(callOpts); err != nil {
+		return nil, fmt.Errorf("failed to query the l1FeeScalar: %w", err)
+	}
+	if values[L1BaseFee], err = m.l1Block.Basefee(callOpts); err != nil {
+		return nil, fmt.Errorf("failed to query the basefee: %w", err)
+	}
+	if values[BlobBaseFee], err = m.l1Block.BlobBaseFee(callOpts); err != nil {
+		return nil, fmt.Errorf("failed to query the blobBaseFee: %w", err)
+	}
+
+	return values, nil
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l2Client.Close()
+	return nil
+}
+
+// isOutOfBounds returns true when the value is below the min or above the max of the bound.
+func isOutOfBounds(value *big.Int, bound Bound) bool {
+	return (bound.Min != nil && value.Cmp(bound.Min) < 0) || (bound.Max != nil && value.Cmp(bound.Max) > 0)
+}
@@ -0,0 +1,168 @@
+package gas_oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestParseBound(t *testing.T) {
+	tests := []struct {
+		name        string
+		bound       string
+		expectedMin *big.Int
+		expectedMax *big.Int
+		expectedErr bool
+	}{
+		{name: "Min and max", bound: "baseFeeScalar=1000:2000", expectedMin: big.NewInt(1000), expectedMax: big.NewInt(2000)},
+		{name: "Min only", bound: "blobBaseFee=1:", expectedMin: big.NewInt(1)},
+		{name: "Max only", bound: "l1BaseFee=:100000000000", expectedMax: big.NewInt(100000000000)},
+		{name: "Unknown parameter", bound: "gasPrice=1:2", expectedErr: true},
+		{name: "No limit", bound: "baseFeeScalar=:", expectedErr: true},
+		{name: "Min greater than max", bound: "baseFeeScalar=2:1", expectedErr: true},
+		{name: "Invalid limit", bound: "baseFeeScalar=a:1", expectedErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := parseBound(test.bound)
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("Failed %s: expected error %t but got %v", test.name, test.expectedErr, err)
+			}
+			if test.expectedErr {
+				return
+			}
+			if !equal(output.Min, test.expectedMin) || !equal(output.Max, test.expectedMax) {
+				t.Errorf("Failed %s: expected %v:%v but got %v:%v", test.name, test.expectedMin, test.expectedMax, output.Min, output.Max)
+			}
+		})
+	}
+}
+
+func TestIsOutOfBounds(t *testing.T) {
+	bound := Bound{Parameter: BaseFeeScalar, Min: big.NewInt(1000), Max: big.NewInt(2000)}
+	tests := []struct {
+		name     string
+		value    *big.Int
+		bound    Bound
+		expected bool
+	}{
+		{name: "Within bounds", value: big.NewInt(1500), bound: bound, expected: false},
+		{name: "On the min", value: big.NewInt(1000), bound: bound, expected: false},
+		{name: "Below the min", value: big.NewInt(999), bound: bound, expected: true},
+		{name: "Above the max", value: big.NewInt(2001), bound: bound, expected: true},
+		{name: "No max", value: big.NewInt(1 << 40), bound: Bound{Min: big.NewInt(1)}, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isOutOfBounds(test.value, test.bound)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func equal(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
+
+// newTestMonitor returns the monitor of the fee parameters of the node with the bounds, the L1Block serving the
+// parameters, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, bounds ...Bound) (*Monitor, *fake.Contract, *fake.Notifier) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{})
+	node.Contract(predeploys.GasPriceOracleAddr, util.MustParseABI(bindings.GasPriceOracleMetaData)).
+		Returns("isEcotone", true)
+	l1Block := node.Contract(predeploys.L1BlockAddr, util.MustParseABI(bindings.L1BlockMetaData)).
+		Returns("number", uint64(100)).
+		Returns("baseFeeScalar", uint32(1368)).
+		Returns("blobBaseFeeScalar", uint32(810949)).
+		Returns("l1FeeOverhead", big.NewInt(188)).
+		Returns("l1FeeScalar", big.NewInt(684000)).
+		Returns("basefee", big.NewInt(10e9)).
+		Returns("blobBaseFee", big.NewInt(1))
+
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), CLIConfig{L2NodeURL: node.URL, Bounds: bounds})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, l1Block, notifier
+}
+
+func TestRun(t *testing.T) {
+	m, l1Block, _ := newTestMonitor(t, Bound{Parameter: L1BaseFee, Max: big.NewInt(100e9)})
+	ctx := context.Background()
+
+	// the parameters are reported along with the L1 origin, within their bounds.
+	m.Run(ctx)
+	if ecotone, origin := testutil.ToFloat64(m.isEcotone), testutil.ToFloat64(m.l1OriginNumber); ecotone != 1 || origin != 100 {
+		t.Errorf("expected ecotone at the L1 origin 100 but got %v and %v", ecotone, origin)
+	}
+	if scalar, baseFee := testutil.ToFloat64(m.parameter.WithLabelValues(BaseFeeScalar)), testutil.ToFloat64(m.parameter.WithLabelValues(L1BaseFee)); scalar != 1368 || baseFee != 10e9 {
+		t.Errorf("expected the baseFeeScalar 1368 and the basefee 10 gwei but got %v and %v", scalar, baseFee)
+	}
+	if out := testutil.ToFloat64(m.outOfBounds.WithLabelValues(L1BaseFee)); out != 0 {
+		t.Errorf("expected the basefee within its bounds but got %v", out)
+	}
+
+	// only the changes of the config parameters are counted, and the bounds checked.
+	l1Block.Returns("baseFeeScalar", uint32(2000)).Returns("basefee", big.NewInt(200e9))
+	m.Run(ctx)
+	if scalar, baseFee := testutil.ToFloat64(m.parameterChanges.WithLabelValues(BaseFeeScalar)), testutil.ToFloat64(m.parameterChanges.WithLabelValues(L1BaseFee)); scalar != 1 || baseFee != 0 {
+		t.Errorf("expected the baseFeeScalar change only but got %v and %v", scalar, baseFee)
+	}
+	if out := testutil.ToFloat64(m.outOfBounds.WithLabelValues(L1BaseFee)); out != 1 {
+		t.Errorf("expected the basefee out of its bounds but got %v", out)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	m, l1Block, notifier := newTestMonitor(t, Bound{Parameter: L1BaseFee, Max: big.NewInt(100e9)})
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a changed scalar fires once, while a changed base fee follows the L1 origin.
+	l1Block.Returns("baseFeeScalar", uint32(2000)).Returns("basefee", big.NewInt(20e9))
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(ParameterChangedRule); len(alerts) != 1 || alerts[0].Entity != BaseFeeScalar || alerts[0].Priority != "P2" {
+		t.Fatalf("expected the changed baseFeeScalar alert once but got %v", alerts)
+	}
+
+	// a base fee above its bound fires until back within it.
+	l1Block.Returns("basefee", big.NewInt(200e9))
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(OutOfBoundsRule); len(alerts) != 1 || alerts[0].Entity != L1BaseFee || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the out of bounds alert once but got %v", alerts)
+	}
+	l1Block.Returns("basefee", big.NewInt(50e9))
+	m.Run(ctx)
+	if firing := notifier.Firing(OutOfBoundsRule); len(firing) != 0 {
+		t.Fatalf("expected the out of bounds alert resolved but got %v", firing)
+	}
+}
@@ -2,14 +2,18 @@ package global_events
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
@@ -18,6 +22,7 @@ import (
 
 const (
 	MetricsNamespace = "global_events_mon"
+	MonitorName      = "global_events"
 )
 
 var counter int = 0
@@ -34,6 +39,8 @@ type Monitor struct {
 
 	LiveAddress *common.Address
 
+	notifier alerts.Notifier
+
 	//filename   string //filename of the yaml rules
 	//yamlconfig Configuration
 
@@ -88,6 +95,7 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 		log:          log,
 		l1Client:     l1Client,
 		globalconfig: globalConfig,
+		notifier:     alerts.NopNotifier{},
 
 		nickname: cfg.Nickname,
 		eventEmitted: m.NewCounterVec(prometheus.CounterOpts{
@@ -145,6 +153,11 @@ func FormatAndHash(signature string) common.Hash {
 
 }
 
+// SetNotifier sets the notifier receiving an alert for every event detected.
+func (m *Monitor) SetNotifier(notifier alerts.Notifier) {
+	m.notifier = notifier
+}
+
 // Run the monitor functions declared as a monitor method.
 func (m *Monitor) Run(ctx context.Context) {
 	m.checkEvents(ctx)
@@ -213,12 +226,32 @@ func (m *Monitor) checkEvents(ctx context.Context) { //TODO: Ensure the logs cri
 				// m.eventEmitted.WithLabelValues(m.nickname, config.Name, config.Priority, event_config.Signature, event_config.Keccak256_Signature.Hex(), vLog.Address.String(), latestBlockNumber.String(), vLog.TxHash.String()).Set(float64(1)) //inc
 
 				m.eventEmitted.WithLabelValues(m.nickname, config.Name, config.Priority, event_config.Signature, event_config.Keccak256_Signature.Hex()).Inc()
+				if err := m.notifier.Notify(ctx, eventAlert(m.nickname, config, event_config, vLog)); err != nil && !errors.Is(err, alerts.ErrSilenced) {
+					m.log.Warn("Failed to notify the event", "RuleName", config.Name, "TxHash", vLog.TxHash.String(), "error", err.Error())
+				}
 			}
 		}
 	}
 	m.log.Info("Checking events..", "CurrentBlock", latestBlockNumber)
 }
 
+// eventAlert returns the alert of an event matching a rule, the entity being the address emitting the event.
+func eventAlert(nickname string, config Configuration, event Event, vLog types.Log) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     config.Name,
+		Priority: config.Priority,
+		Entity:   vLog.Address.Hex(),
+		Summary:  fmt.Sprintf("%s emitted by %s on %s", event.Signature, vLog.Address.Hex(), nickname),
+		Labels: map[string]string{
+			"nickname":    nickname,
+			"signature":   event.Signature,
+			"txHash":      vLog.TxHash.Hex(),
+			"blockNumber": fmt.Sprint(vLog.BlockNumber),
+		},
+	}
+}
+
 // ReturnConfigFromConfigsAndAddress allows to return the config from the configs and the address.
 func ReturnConfigFromConfigsAndAddress(address common.Address, configs []Configuration) Configuration {
 	configDefault := Configuration{}
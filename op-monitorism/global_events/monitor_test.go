@@ -1,9 +1,17 @@
 package global_events
 
 import (
+	"context"
 	"testing"
 
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v3"
 )
 
 func TestFormatSignature(t *testing.T) {
@@ -76,3 +84,70 @@ func TestFormatAndHash(t *testing.T) {
 		})
 	}
 }
+
+// newTestMonitor returns the monitor of the rules of `data` on the node, and the notifier of its alerts. The monitor is
+// built without `NewMonitor` which pauses to display the rules.
+func newTestMonitor(t *testing.T, node *fake.Node) (*Monitor, *fake.Notifier) {
+	var config GlobalConfiguration
+	if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	for _, rule := range config.Configuration {
+		for i := range rule.Events {
+			rule.Events[i].Keccak256_Signature = FormatAndHash(rule.Events[i].Signature)
+		}
+	}
+	l1Client, err := ethclient.Dial(node.URL)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(l1Client.Close)
+
+	notifier := &fake.Notifier{}
+	m := &Monitor{
+		log:                 log.New(),
+		l1Client:            l1Client,
+		globalconfig:        config,
+		nickname:            "mainnet",
+		notifier:            notifier,
+		eventEmitted:        prometheus.NewCounterVec(prometheus.CounterOpts{Name: "eventEmitted"}, []string{"nickname", "rulename", "priority", "functionName", "topics"}),
+		unexpectedRpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "unexpectedRpcErrors"}, []string{"section", "name"}),
+		CurrentBlock:        prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "CurrentBlock"}, []string{"nickname"}),
+	}
+	return m, notifier
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := fake.NewNode(t)
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// the events of the latest block are alerted with the rule of their emitter, the rule without addresses matching any emitter.
+	safe, other := common.HexToAddress("0x95222290DD7278Aa3Ddd389Cc1E1d165CC4BAfe5"), common.HexToAddress("0x01")
+	header := node.AddBlock(&types.Header{})
+	for i, address := range []common.Address{safe, other} {
+		node.AddLogs(types.Log{
+			Address:     address,
+			Topics:      []common.Hash{FormatAndHash("ExecutionFailure(bytes32,uint256)")},
+			BlockNumber: header.Number.Uint64(),
+			BlockHash:   header.Hash(),
+			TxHash:      common.BigToHash(common.Big1),
+			Index:       uint(i),
+		})
+	}
+	m.Run(ctx)
+	alerts := notifier.Alerts("")
+	if len(alerts) != 2 || alerts[0].Rule != "BuildLand" || alerts[0].Priority != "P0" || alerts[0].Entity != safe.Hex() || alerts[0].Labels["nickname"] != "mainnet" {
+		t.Fatalf("expected the alert of the BuildLand rule but got %v", alerts)
+	}
+	if alerts[1].Rule != "NightLand" || alerts[1].Priority != "P2" || alerts[1].Entity != other.Hex() {
+		t.Fatalf("expected the alert of the NightLand rule but got %v", alerts[1])
+	}
+
+	// the events are alerted once, the next block being scanned alone.
+	node.AddBlock(&types.Header{})
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 2 {
+		t.Fatalf("expected no new alert but got %v", alerts)
+	}
+}
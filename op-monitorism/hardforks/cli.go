@@ -0,0 +1,140 @@
+package hardforks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodesFlagName        = "nodes"
+	RollupConfigFlagName = "rollup.config"
+	ForksFlagName        = "forks"
+	MinVersionFlagName   = "min.version"
+)
+
+// Node is an op-node queried through its RPC.
+type Node struct {
+	Name string
+	URL  string
+}
+
+type CLIConfig struct {
+	Nodes []Node
+
+	// Forks are the expected activation timestamps by fork name.
+	Forks map[string]uint64
+
+	// MinVersion is the min version of op-node supporting the forks, not checked when empty.
+	MinVersion string
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{Forks: make(map[string]uint64), MinVersion: ctx.String(MinVersionFlagName)}
+
+	names := make(map[string]bool)
+	for _, node := range ctx.StringSlice(NodesFlagName) {
+		name, url, err := util.ParseNamedValue(node, "name=url")
+		if err != nil {
+			return cfg, err
+		}
+		if names[name] {
+			return cfg, fmt.Errorf("duplicated node %s", name)
+		}
+		names[name] = true
+		cfg.Nodes = append(cfg.Nodes, Node{Name: name, URL: url})
+	}
+
+	if path := ctx.String(RollupConfigFlagName); len(path) > 0 {
+		rollupConfig, err := readRollupConfig(path)
+		if err != nil {
+			return cfg, err
+		}
+		for fork, activation := range forkTimes(rollupConfig) {
+			if activation != nil {
+				cfg.Forks[fork] = *activation
+			}
+		}
+	}
+
+	for _, fork := range ctx.StringSlice(ForksFlagName) {
+		name, activation, err := parseFork(fork)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Forks[name] = activation
+	}
+	if len(cfg.Forks) == 0 {
+		return cfg, fmt.Errorf("no fork configured, --%s or --%s must be set", RollupConfigFlagName, ForksFlagName)
+	}
+
+	if len(cfg.MinVersion) > 0 {
+		if _, err := parseVersion(cfg.MinVersion); err != nil {
+			return cfg, fmt.Errorf("--%s: %w", MinVersionFlagName, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// readRollupConfig reads the reference rollup config, e.g. the one of the superchain registry.
+func readRollupConfig(path string) (*rollup.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the rollup config %s: %w", path, err)
+	}
+	var rollupConfig rollup.Config
+	if err := json.Unmarshal(data, &rollupConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the rollup config %s: %w", path, err)
+	}
+	return &rollupConfig, nil
+}
+
+// parseFork parses a fork formatted via `name=timestamp`.
+func parseFork(fork string) (string, uint64, error) {
+	name, timestamp, err := util.ParseNamedValue(fork, "name=timestamp")
+	if err != nil {
+		return "", 0, err
+	}
+	if _, ok := forkTimes(&rollup.Config{})[name]; !ok {
+		return "", 0, fmt.Errorf("unknown fork %s", name)
+	}
+	activation, err := strconv.ParseUint(timestamp, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse the timestamp of the fork %s: %w", name, err)
+	}
+	return name, activation, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     NodesFlagName,
+			Usage:    "One or more op-node RPC URLs formatted via `name=url`",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "NODES"),
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:    RollupConfigFlagName,
+			Usage:   "Path to the reference rollup config (rollup.json) scheduling the forks",
+			EnvVars: opservice.PrefixEnvVar(envVar, "ROLLUP_CONFIG"),
+		},
+		&cli.StringSliceFlag{
+			Name:    ForksFlagName,
+			Usage:   "One or more fork activations formatted via `name=timestamp` (e.g. fjord=1720627201), overriding the ones of the rollup config",
+			EnvVars: opservice.PrefixEnvVar(envVar, "FORKS"),
+		},
+		&cli.StringFlag{
+			Name:    MinVersionFlagName,
+			Usage:   "Min version of op-node supporting the forks (e.g. v1.7.7), not checked when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "MIN_VERSION"),
+		},
+	}
+}
@@ -0,0 +1,73 @@
+package hardforks
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// forkTimes returns the activation timestamps of the forks of the rollup config by name, `nil` when not scheduled.
+func forkTimes(rollupConfig *rollup.Config) map[string]*uint64 {
+	return map[string]*uint64{
+		"regolith": rollupConfig.RegolithTime,
+		"canyon":   rollupConfig.CanyonTime,
+		"delta":    rollupConfig.DeltaTime,
+		"ecotone":  rollupConfig.EcotoneTime,
+		"fjord":    rollupConfig.FjordTime,
+		"interop":  rollupConfig.InteropTime,
+	}
+}
+
+// forkConfigHash returns the hash of the activation timestamps of the given forks, so two nodes scheduling these forks identically have the same hash.
+func forkConfigHash(times map[string]*uint64, forks []string) common.Hash {
+	sorted := append([]string(nil), forks...)
+	sort.Strings(sorted)
+
+	var b strings.Builder
+	for _, fork := range sorted {
+		fmt.Fprintf(&b, "%s=%s;", fork, formatActivation(times[fork]))
+	}
+	return crypto.Keccak256Hash([]byte(b.String()))
+}
+
+// formatActivation returns the activation timestamp as a string, `unset` when not scheduled.
+func formatActivation(activation *uint64) string {
+	if activation == nil {
+		return "unset"
+	}
+	return strconv.FormatUint(*activation, 10)
+}
+
+// parseVersion parses the major, minor and patch numbers of a version (e.g. `v1.7.3-4c2c0ab7-1714399532`).
+func parseVersion(version string) ([3]uint64, error) {
+	var parsed [3]uint64
+	core, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), "-")
+	split := strings.Split(core, ".")
+	if len(split) != 3 {
+		return parsed, fmt.Errorf("failed to parse the version %s", version)
+	}
+	for i, s := range split {
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return parsed, fmt.Errorf("failed to parse the version %s: %w", version, err)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
+
+// isVersionAtLeast returns true when the version is at least the min version.
+func isVersionAtLeast(version [3]uint64, minVersion [3]uint64) bool {
+	for i := range version {
+		if version[i] != minVersion[i] {
+			return version[i] > minVersion[i]
+		}
+	}
+	return true
+}
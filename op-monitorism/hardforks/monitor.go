@@ -0,0 +1,208 @@
+package hardforks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "hardforks"
+	MetricsNamespace = "hardforks_mon"
+
+	// NotReadyRule is the rule of the alert emitted while a node is not ready for a fork, the entity being the node and the fork.
+	NotReadyRule = "node not ready for the fork"
+)
+
+type node struct {
+	Node
+	rpc client.RPC
+
+	// labels of the `nodeInfo` of the previous iteration.
+	version string
+	hash    string
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	nodes      []*node
+	forks      map[string]uint64
+	forkNames  []string
+	minVersion *[3]uint64
+
+	// metrics
+	activationTime         *prometheus.GaugeVec
+	secondsUntilActivation *prometheus.GaugeVec
+	nodeReady              *prometheus.GaugeVec
+	notReadyNodes          *prometheus.GaugeVec
+	nodeInfo               *prometheus.GaugeVec
+	unexpectedRpcErrors    *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating hardforks monitor...")
+
+	nodes := make([]*node, len(cfg.Nodes))
+	for i, n := range cfg.Nodes {
+		rpc, err := client.NewRPC(ctx, log, n.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial the node %s: %w", n.Name, err)
+		}
+		nodes[i] = &node{Node: n, rpc: rpc}
+	}
+
+	forkNames := make([]string, 0, len(cfg.Forks))
+	for fork, activation := range cfg.Forks {
+		forkNames = append(forkNames, fork)
+		log.Info("configured fork", "fork", fork, "activation", activation)
+	}
+	sort.Strings(forkNames)
+
+	var minVersion *[3]uint64
+	if len(cfg.MinVersion) > 0 {
+		parsed, err := parseVersion(cfg.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+		minVersion = &parsed
+	}
+
+	return &Monitor{
+		log: log,
+
+		nodes:      nodes,
+		forks:      cfg.Forks,
+		forkNames:  forkNames,
+		minVersion: minVersion,
+
+		activationTime: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "activationTime",
+			Help:      "expected activation timestamp of the fork",
+		}, []string{"fork"}),
+		secondsUntilActivation: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "secondsUntilActivation",
+			Help:      "seconds until the activation of the fork, negative once activated",
+		}, []string{"fork"}),
+		nodeReady: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "nodeReady",
+			Help:      "1 if the node schedules the fork at the expected timestamp with a supported version, 0 otherwise",
+		}, []string{"node", "fork"}),
+		notReadyNodes: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "notReadyNodes",
+			Help:      "number of nodes not ready for the fork, unreachable nodes included",
+		}, []string{"fork"}),
+		nodeInfo: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "nodeInfo",
+			Help:      "version and hash of the fork config of the node",
+		}, []string{"node", "version", "forkConfigHash"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	now := uint64(time.Now().Unix())
+	for _, fork := range m.forkNames {
+		m.activationTime.WithLabelValues(fork).Set(float64(m.forks[fork]))
+		m.secondsUntilActivation.WithLabelValues(fork).Set(float64(m.forks[fork]) - float64(now))
+	}
+
+	notReady := make(map[string]int, len(m.forkNames))
+	for _, n := range m.nodes {
+		ready, err := m.checkNode(ctx, n)
+		if err != nil {
+			m.log.Error("failed to probe the node", "node", n.Name, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("node", n.Name).Inc()
+		}
+		for _, fork := range m.forkNames {
+			if !ready[fork] {
+				notReady[fork]++
+			}
+			m.nodeReady.WithLabelValues(n.Name, fork).Set(util.BoolToFloat(ready[fork]))
+			m.Update(ctx, alerts.Alert{
+				Monitor:  MonitorName,
+				Rule:     NotReadyRule,
+				Priority: "P1",
+				Entity:   n.Name + "/" + fork,
+				Summary:  fmt.Sprintf("node %s not ready for %s activating at %d", n.Name, fork, m.forks[fork]),
+				Labels:   map[string]string{"node": n.Name, "fork": fork},
+			}, !ready[fork])
+		}
+	}
+
+	for _, fork := range m.forkNames {
+		m.notReadyNodes.WithLabelValues(fork).Set(float64(notReady[fork]))
+		if notReady[fork] > 0 {
+			m.log.Warn("nodes not ready for the fork", "fork", fork, "activation", m.forks[fork], "not_ready", notReady[fork])
+		}
+	}
+}
+
+// checkNode returns the forks the node is ready for, none when the node can't be probed.
+func (m *Monitor) checkNode(ctx context.Context, n *node) (map[string]bool, error) {
+	var version string
+	if err := n.rpc.CallContext(ctx, &version, "optimism_version"); err != nil {
+		return nil, fmt.Errorf("failed to query the version: %w", err)
+	}
+	var rollupConfig rollup.Config
+	if err := n.rpc.CallContext(ctx, &rollupConfig, "optimism_rollupConfig"); err != nil {
+		return nil, fmt.Errorf("failed to query the rollup config: %w", err)
+	}
+
+	times := forkTimes(&rollupConfig)
+	hash := forkConfigHash(times, m.forkNames).String()
+	if n.version != version || n.hash != hash {
+		m.nodeInfo.DeleteLabelValues(n.Name, n.version, n.hash)
+		n.version, n.hash = version, hash
+	}
+	m.nodeInfo.WithLabelValues(n.Name, version, hash).Set(1)
+
+	supported := true
+	if m.minVersion != nil {
+		parsed, err := parseVersion(version)
+		supported = err == nil && isVersionAtLeast(parsed, *m.minVersion)
+		if !supported {
+			m.log.Warn("node version not supporting the forks", "node", n.Name, "version", version)
+		}
+	}
+
+	ready := make(map[string]bool, len(m.forkNames))
+	for _, fork := range m.forkNames {
+		scheduled := times[fork] != nil && *times[fork] == m.forks[fork]
+		if !scheduled {
+			m.log.Warn("node not scheduling the fork at the expected timestamp", "node", n.Name, "fork", fork, "expected", m.forks[fork], "scheduled", formatActivation(times[fork]))
+		}
+		ready[fork] = scheduled && supported
+	}
+	return ready, nil
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	for _, n := range m.nodes {
+		n.rpc.Close()
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package hardforks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		expected [3]uint64
+		err      bool
+	}{
+		{name: "release", version: "v1.7.3", expected: [3]uint64{1, 7, 3}},
+		{name: "build metadata", version: "v1.7.3-4c2c0ab7-1714399532", expected: [3]uint64{1, 7, 3}},
+		{name: "no prefix", version: "1.10.0", expected: [3]uint64{1, 10, 0}},
+		{name: "dev build", version: "v0.0.0-unstable", expected: [3]uint64{0, 0, 0}},
+		{name: "invalid", version: "unknown", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := parseVersion(test.version)
+			if (err != nil) != test.err {
+				t.Fatalf("Failed %s: unexpected error %v", test.name, err)
+			}
+			if parsed != test.expected {
+				t.Errorf("Failed %s: expected %v but got %v", test.name, test.expected, parsed)
+			}
+		})
+	}
+}
+
+func TestIsVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    [3]uint64
+		minVersion [3]uint64
+		expected   bool
+	}{
+		{name: "equal", version: [3]uint64{1, 7, 3}, minVersion: [3]uint64{1, 7, 3}, expected: true},
+		{name: "newer patch", version: [3]uint64{1, 7, 4}, minVersion: [3]uint64{1, 7, 3}, expected: true},
+		{name: "newer minor", version: [3]uint64{1, 10, 0}, minVersion: [3]uint64{1, 7, 3}, expected: true},
+		{name: "older minor", version: [3]uint64{1, 6, 9}, minVersion: [3]uint64{1, 7, 3}, expected: false},
+		{name: "older major", version: [3]uint64{0, 9, 9}, minVersion: [3]uint64{1, 0, 0}, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isVersionAtLeast(test.version, test.minVersion); got != test.expected {
+				t.Errorf("Failed %s: expected %v but got %v", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestForkConfigHash(t *testing.T) {
+	ecotone, fjord, otherFjord := uint64(1710374401), uint64(1720627201), uint64(1720627202)
+	reference := forkTimes(&rollup.Config{EcotoneTime: &ecotone, FjordTime: &fjord})
+	forks := []string{"fjord", "ecotone"}
+
+	same := forkTimes(&rollup.Config{EcotoneTime: &ecotone, FjordTime: &fjord, DeltaTime: &ecotone})
+	if forkConfigHash(reference, forks) != forkConfigHash(same, []string{"ecotone", "fjord"}) {
+		t.Errorf("expected the same hash for the same schedule of the forks")
+	}
+
+	different := forkTimes(&rollup.Config{EcotoneTime: &ecotone, FjordTime: &otherFjord})
+	if forkConfigHash(reference, forks) == forkConfigHash(different, forks) {
+		t.Errorf("expected a different hash for a different activation")
+	}
+
+	unset := forkTimes(&rollup.Config{EcotoneTime: &ecotone})
+	if forkConfigHash(reference, forks) == forkConfigHash(unset, forks) {
+		t.Errorf("expected a different hash for an unscheduled fork")
+	}
+}
+
+func TestParseFork(t *testing.T) {
+	name, activation, err := parseFork("fjord=1720627201")
+	if err != nil || name != "fjord" || activation != 1720627201 {
+		t.Errorf("unexpected fork %s=%d: %v", name, activation, err)
+	}
+
+	for _, invalid := range []string{"fjord", "unknown=1", "fjord=soon"} {
+		if _, _, err := parseFork(invalid); err == nil {
+			t.Errorf("expected an error for %s", invalid)
+		}
+	}
+}
+
+// opNode is an op-node serving its version and the forks of its rollup config.
+type opNode struct {
+	*fake.Node
+
+	version     string
+	ecotoneTime *uint64
+}
+
+func newOpNode(t *testing.T, version string, ecotoneTime uint64) *opNode {
+	n := &opNode{Node: fake.NewNode(t), version: version, ecotoneTime: &ecotoneTime}
+	n.Handle("optimism_version", func(_ []json.RawMessage) (interface{}, error) {
+		return n.version, nil
+	})
+	n.Handle("optimism_rollupConfig", func(_ []json.RawMessage) (interface{}, error) {
+		return &rollup.Config{EcotoneTime: n.ecotoneTime}, nil
+	})
+	return n
+}
+
+// newTestMonitor returns the monitor of the ecotone fork of the nodes named after their index, and the notifier of its
+// alerts.
+func newTestMonitor(t *testing.T, ecotoneTime uint64, nodes ...*opNode) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{Forks: map[string]uint64{"ecotone": ecotoneTime}, MinVersion: "v1.4.0"}
+	for i, n := range nodes {
+		cfg.Nodes = append(cfg.Nodes, Node{Name: fmt.Sprintf("node-%d", i), URL: n.URL})
+	}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	ready, late := newOpNode(t, "v1.7.0", 1710374401), newOpNode(t, "v1.7.0", 1710374402)
+	m, _ := newTestMonitor(t, 1710374401, ready, late)
+	ctx := context.Background()
+
+	// the activation of the fork is reported along with the readiness of each node.
+	m.Run(ctx)
+	if activation, until := testutil.ToFloat64(m.activationTime.WithLabelValues("ecotone")), testutil.ToFloat64(m.secondsUntilActivation.WithLabelValues("ecotone")); activation != 1710374401 || until >= 0 {
+		t.Errorf("expected ecotone activated at 1710374401 but got %v, %v seconds until activation", activation, until)
+	}
+	if readyNode, lateNode, notReady := testutil.ToFloat64(m.nodeReady.WithLabelValues("node-0", "ecotone")), testutil.ToFloat64(m.nodeReady.WithLabelValues("node-1", "ecotone")), testutil.ToFloat64(m.notReadyNodes.WithLabelValues("ecotone")); readyNode != 1 || lateNode != 0 || notReady != 1 {
+		t.Errorf("expected only node-1 not ready but got %v, %v and %v not ready", readyNode, lateNode, notReady)
+	}
+
+	// an upgraded node replaces its info series.
+	ready.version = "v1.7.1"
+	m.Run(ctx)
+	if count := testutil.CollectAndCount(m.nodeInfo); count != 2 {
+		t.Errorf("expected the info series of the 2 nodes but got %d", count)
+	}
+
+	// a node down is counted as an rpc error and not ready.
+	late.Fail("optimism_version", errors.New("connection refused"))
+	*late.ecotoneTime = 1710374401
+	m.Run(ctx)
+	if errs, notReady := testutil.ToFloat64(m.unexpectedRpcErrors.WithLabelValues("node", "node-1")), testutil.ToFloat64(m.notReadyNodes.WithLabelValues("ecotone")); errs != 1 || notReady != 1 {
+		t.Errorf("expected the rpc error of node-1 not ready but got %v errors and %v not ready", errs, notReady)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	ready, late := newOpNode(t, "v1.7.0", 1710374401), newOpNode(t, "v1.7.0", 1710374402)
+	m, notifier := newTestMonitor(t, 1710374401, ready, late)
+	ctx := context.Background()
+
+	// a node scheduling the fork at another timestamp fires once, resolved once rescheduled.
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(NotReadyRule); len(alerts) != 1 || alerts[0].Entity != "node-1/ecotone" || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the not ready alert of node-1 once but got %v", alerts)
+	}
+	*late.ecotoneTime = 1710374401
+	m.Run(ctx)
+	if firing := notifier.Firing(NotReadyRule); len(firing) != 0 {
+		t.Fatalf("expected the not ready alert resolved but got %v", firing)
+	}
+
+	// a node below the min version or down isn't ready.
+	ready.version = "v1.3.9"
+	late.Fail("optimism_version", errors.New("connection refused"))
+	m.Run(ctx)
+	if firing := notifier.Firing(NotReadyRule); len(firing) != 2 {
+		t.Fatalf("expected the not ready alerts of both nodes but got %v", firing)
+	}
+}
@@ -0,0 +1,84 @@
+package heartbeat
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"strings"
+	"time"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodeURLFlagName = "node.url"
+
+	PrivateKeyFlagName         = "private.key"
+	HeartbeatIntervalFlagName  = "heartbeat.interval"
+	InclusionThresholdFlagName = "inclusion.threshold"
+)
+
+type CLIConfig struct {
+	NodeURL string
+
+	PrivateKey         *ecdsa.PrivateKey
+	HeartbeatInterval  time.Duration
+	InclusionThreshold time.Duration
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		NodeURL:            ctx.String(NodeURLFlagName),
+		HeartbeatInterval:  ctx.Duration(HeartbeatIntervalFlagName),
+		InclusionThreshold: ctx.Duration(InclusionThresholdFlagName),
+	}
+
+	privateKey, err := parsePrivateKey(ctx.String(PrivateKeyFlagName))
+	if err != nil {
+		return cfg, fmt.Errorf("--%s: %w", PrivateKeyFlagName, err)
+	}
+	cfg.PrivateKey = privateKey
+
+	return cfg, nil
+}
+
+// parsePrivateKey parses a hex-encoded private key, with or without the `0x` prefix.
+func parsePrivateKey(privateKey string) (*ecdsa.PrivateKey, error) {
+	parsed, err := crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return parsed, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    NodeURLFlagName,
+			Usage:   "Node URL of the sequencer or of a node forwarding the transactions to it",
+			Value:   "127.0.0.1:9545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     PrivateKeyFlagName,
+			Usage:    "Private key of the account sending the heartbeats, a dedicated account funded for the fees",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "PRIVATE_KEY"),
+			Required: true,
+		},
+		&cli.DurationFlag{
+			Name:    HeartbeatIntervalFlagName,
+			Usage:   "Interval between two heartbeats",
+			Value:   time.Minute,
+			EnvVars: opservice.PrefixEnvVar(envVar, "HEARTBEAT_INTERVAL"),
+		},
+		&cli.DurationFlag{
+			Name:    InclusionThresholdFlagName,
+			Usage:   "Max time before the heartbeat is included",
+			Value:   10 * time.Second,
+			EnvVars: opservice.PrefixEnvVar(envVar, "INCLUSION_THRESHOLD"),
+		},
+	}
+}
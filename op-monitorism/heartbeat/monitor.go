@@ -0,0 +1,409 @@
+package heartbeat
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "heartbeat"
+	MetricsNamespace = "heartbeat_mon"
+
+	// SlowInclusionRule is the rule of the alert emitted while the inclusion of the heartbeats exceeds the threshold,
+	// the entity being the address sending the heartbeats.
+	SlowInclusionRule = "heartbeat not included within the threshold"
+	// NonceUsedRule is the rule of the one-off alert emitted when the nonce of the pending heartbeat is used by another
+	// transaction, e.g. sent with the same key, the heartbeat being dropped.
+	NonceUsedRule = "heartbeat nonce used by another transaction"
+
+	// TransferGas is the gas of the self-transfer sent as heartbeat.
+	TransferGas = 21000
+)
+
+// heartbeat is a self-transfer sent and not yet included, along with the replacements sent with bumped fees.
+type heartbeat struct {
+	txHashes  []common.Hash
+	nonce     uint64
+	sentAt    time.Time
+	resentAt  time.Time
+	gasTipCap *big.Int
+	gasFeeCap *big.Int
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	client  *ethclient.Client
+	chainID *big.Int
+
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+
+	heartbeatInterval  time.Duration
+	inclusionThreshold time.Duration
+
+	pending  *heartbeat
+	lastSent time.Time
+
+	// metrics
+	heartbeatsSent       prometheus.Counter
+	heartbeatsIncluded   prometheus.Counter
+	heartbeatsDropped    prometheus.Counter
+	inclusionLatency     prometheus.Histogram
+	lastInclusionLatency prometheus.Gauge
+	pendingAge           prometheus.Gauge
+	thresholdExceeded    prometheus.Gauge
+	effectiveGasPrice    prometheus.Gauge
+	balance              prometheus.Gauge
+	unexpectedRpcErrors  *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating heartbeat monitor...")
+
+	client, err := ethclient.Dial(cfg.NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial the node: %w", err)
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the chain id: %w", err)
+	}
+
+	address := crypto.PubkeyToAddress(cfg.PrivateKey.PublicKey)
+	log.Info("configured heartbeat", "address", address, "chain_id", chainID, "interval", cfg.HeartbeatInterval, "inclusion_threshold", cfg.InclusionThreshold)
+
+	return &Monitor{
+		log: log,
+
+		client:  client,
+		chainID: chainID,
+
+		privateKey: cfg.PrivateKey,
+		address:    address,
+
+		heartbeatInterval:  cfg.HeartbeatInterval,
+		inclusionThreshold: cfg.InclusionThreshold,
+
+		heartbeatsSent: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "heartbeatsSent",
+			Help:      "number of heartbeats sent",
+		}),
+		heartbeatsIncluded: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "heartbeatsIncluded",
+			Help:      "number of heartbeats included",
+		}),
+		heartbeatsDropped: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "heartbeatsDropped",
+			Help:      "number of heartbeats dropped, their nonce being used by another transaction",
+		}),
+		inclusionLatency: m.NewHistogram(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Name:      "inclusionLatency",
+			Help:      "seconds between the submission of the heartbeat and the block including it",
+			Buckets:   []float64{1, 2, 4, 6, 10, 20, 30, 60, 120, 300},
+		}),
+		lastInclusionLatency: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "lastInclusionLatency",
+			Help:      "seconds between the submission of the last heartbeat included and its block",
+		}),
+		pendingAge: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "pendingAge",
+			Help:      "seconds since the submission of the heartbeat not yet included, 0 when none is pending",
+		}),
+		thresholdExceeded: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "thresholdExceeded",
+			Help:      "1 if the pending or the last heartbeat exceeded the inclusion threshold, 0 otherwise",
+		}),
+		effectiveGasPrice: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "effectiveGasPrice",
+			Help:      "effective gas price in gwei of the last heartbeat included",
+		}),
+		balance: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "balance",
+			Help:      "balance in ETH of the account sending the heartbeats",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	balance, err := m.client.BalanceAt(ctx, m.address, nil)
+	if err != nil {
+		m.log.Error("failed to query the balance", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("node", "balanceAt").Inc()
+	} else {
+		m.balance.Set(util.WeiToEther(balance))
+	}
+
+	now := time.Now()
+	if m.pending != nil {
+		m.checkPending(ctx, now)
+		return
+	}
+
+	if now.Sub(m.lastSent) < m.heartbeatInterval {
+		return
+	}
+	if err := m.sendHeartbeat(ctx, now); err != nil {
+		m.log.Error("failed to send the heartbeat", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("node", "sendHeartbeat").Inc()
+	}
+}
+
+// checkPending looks for the receipt of the pending heartbeat and reports its inclusion latency.
+// The heartbeats are sent one at a time, the next one is only sent once the pending one is included.
+// A heartbeat pending for longer than the heartbeat interval is replaced with bumped fees, and dropped once its nonce
+// is used by another transaction, none of its transactions being includable anymore.
+func (m *Monitor) checkPending(ctx context.Context, now time.Time) {
+	// the nonce is read before the receipts, so that a heartbeat included meanwhile is found rather than dropped.
+	nonce, err := m.client.NonceAt(ctx, m.address, nil)
+	if err != nil {
+		m.log.Error("failed to query the nonce", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("node", "nonceAt").Inc()
+		return
+	}
+
+	var receipt *types.Receipt
+	for _, txHash := range m.pending.txHashes {
+		r, err := m.client.TransactionReceipt(ctx, txHash)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			m.log.Error("failed to query the receipt of the heartbeat", "tx", txHash, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("node", "transactionReceipt").Inc()
+			return
+		}
+		receipt = r
+		break
+	}
+
+	if receipt == nil {
+		if nonce > m.pending.nonce {
+			m.dropPending(ctx, nonce)
+			return
+		}
+
+		age := now.Sub(m.pending.sentAt)
+		exceeded := isThresholdExceeded(age, m.inclusionThreshold)
+		if exceeded {
+			m.log.Warn("heartbeat not included", "nonce", m.pending.nonce, "txs", len(m.pending.txHashes), "age", age)
+		}
+		m.pendingAge.Set(age.Seconds())
+		m.thresholdExceeded.Set(util.BoolToFloat(exceeded))
+		m.Update(ctx, m.slowInclusionAlert(fmt.Sprintf("heartbeat with the nonce %d not included after %s", m.pending.nonce, age.Truncate(time.Second))), exceeded)
+
+		if now.Sub(m.pending.resentAt) >= m.heartbeatInterval {
+			if err := m.replaceHeartbeat(ctx, now); isNonceTooLow(err) {
+				m.dropPending(ctx, nonce)
+			} else if err != nil {
+				m.log.Error("failed to replace the heartbeat", "nonce", m.pending.nonce, "err", err)
+				m.unexpectedRpcErrors.WithLabelValues("node", "replaceHeartbeat").Inc()
+			}
+		}
+		return
+	}
+
+	header, err := m.client.HeaderByHash(ctx, receipt.BlockHash)
+	if err != nil {
+		m.log.Error("failed to query the block of the heartbeat", "tx", receipt.TxHash, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("node", "headerByHash").Inc()
+		return
+	}
+
+	latency := inclusionLatency(m.pending.sentAt, header.Time)
+	exceeded := isThresholdExceeded(latency, m.inclusionThreshold)
+	if exceeded {
+		m.log.Warn("heartbeat included after the threshold", "tx", receipt.TxHash, "block", receipt.BlockNumber, "latency", latency)
+	}
+
+	m.heartbeatsIncluded.Inc()
+	m.inclusionLatency.Observe(latency.Seconds())
+	m.lastInclusionLatency.Set(latency.Seconds())
+	m.effectiveGasPrice.Set(util.WeiToGwei(receipt.EffectiveGasPrice))
+	m.pendingAge.Set(0)
+	m.thresholdExceeded.Set(util.BoolToFloat(exceeded))
+	m.Update(ctx, m.slowInclusionAlert(fmt.Sprintf("heartbeat %s included after %s", receipt.TxHash, latency)), exceeded)
+	m.log.Info("heartbeat included", "tx", receipt.TxHash, "block", receipt.BlockNumber, "latency", latency, "effective_gas_price", receipt.EffectiveGasPrice)
+	m.pending = nil
+}
+
+// dropPending drops the pending heartbeat whose nonce is used by another transaction, the next heartbeat being sent
+// with the next nonce.
+func (m *Monitor) dropPending(ctx context.Context, nonce uint64) {
+	m.log.Warn("heartbeat nonce used by another transaction, dropping the heartbeat", "nonce", m.pending.nonce, "txs", len(m.pending.txHashes), "account_nonce", nonce)
+	m.heartbeatsDropped.Inc()
+	m.pendingAge.Set(0)
+	m.Emit(ctx, alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     NonceUsedRule,
+		Priority: "P2",
+		Entity:   m.address.String(),
+		Summary:  fmt.Sprintf("the nonce %d of the heartbeat was used by another transaction, the heartbeat being dropped", m.pending.nonce),
+	})
+	m.pending = nil
+}
+
+// isNonceTooLow returns true when the transaction was rejected for a nonce already used.
+func isNonceTooLow(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "nonce too low")
+}
+
+// slowInclusionAlert returns the alert of the heartbeats not included within the threshold.
+func (m *Monitor) slowInclusionAlert(summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     SlowInclusionRule,
+		Priority: "P1",
+		Entity:   m.address.String(),
+		Summary:  summary,
+	}
+}
+
+// sendHeartbeat signs and sends a self-transfer of 0 ETH.
+func (m *Monitor) sendHeartbeat(ctx context.Context, now time.Time) error {
+	nonce, err := m.client.PendingNonceAt(ctx, m.address)
+	if err != nil {
+		return fmt.Errorf("failed to query the nonce: %w", err)
+	}
+	tip, err := m.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query the gas tip cap: %w", err)
+	}
+	header, err := m.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to query latest header: %w", err)
+	}
+
+	pending := &heartbeat{nonce: nonce, sentAt: now, gasTipCap: tip, gasFeeCap: gasFeeCap(header.BaseFee, tip)}
+	txHash, err := m.signAndSend(ctx, pending)
+	if err != nil {
+		return err
+	}
+
+	pending.txHashes, pending.resentAt = []common.Hash{txHash}, now
+	m.pending = pending
+	m.lastSent = now
+	m.heartbeatsSent.Inc()
+	m.log.Info("heartbeat sent", "tx", txHash, "nonce", nonce)
+	return nil
+}
+
+// replaceHeartbeat sends the pending heartbeat again with the fees bumped enough to replace it in the mempool.
+func (m *Monitor) replaceHeartbeat(ctx context.Context, now time.Time) error {
+	tip, err := m.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query the gas tip cap: %w", err)
+	}
+	header, err := m.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to query latest header: %w", err)
+	}
+
+	replacement := *m.pending
+	replacement.gasTipCap = maxBig(tip, bumpFee(m.pending.gasTipCap))
+	replacement.gasFeeCap = maxBig(gasFeeCap(header.BaseFee, replacement.gasTipCap), bumpFee(m.pending.gasFeeCap))
+	txHash, err := m.signAndSend(ctx, &replacement)
+	if err != nil {
+		return err
+	}
+
+	m.pending.txHashes = append(m.pending.txHashes, txHash)
+	m.pending.gasTipCap, m.pending.gasFeeCap, m.pending.resentAt = replacement.gasTipCap, replacement.gasFeeCap, now
+	m.log.Warn("heartbeat replaced", "tx", txHash, "nonce", m.pending.nonce, "gas_tip_cap", replacement.gasTipCap, "gas_fee_cap", replacement.gasFeeCap)
+	return nil
+}
+
+// signAndSend signs and sends the self-transfer of the heartbeat.
+func (m *Monitor) signAndSend(ctx context.Context, h *heartbeat) (common.Hash, error) {
+	tx, err := types.SignNewTx(m.privateKey, types.LatestSignerForChainID(m.chainID), &types.DynamicFeeTx{
+		ChainID:   m.chainID,
+		Nonce:     h.nonce,
+		GasTipCap: h.gasTipCap,
+		GasFeeCap: h.gasFeeCap,
+		Gas:       TransferGas,
+		To:        &m.address,
+		Value:     new(big.Int),
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to sign the heartbeat: %w", err)
+	}
+	if err := m.client.SendTransaction(ctx, tx); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to send the heartbeat: %w", err)
+	}
+	return tx.Hash(), nil
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.client.Close()
+	return nil
+}
+
+// gasFeeCap returns the fee cap of the heartbeat, leaving room for the base fee to double.
+func gasFeeCap(baseFee *big.Int, tip *big.Int) *big.Int {
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(2))
+	return feeCap.Add(feeCap, tip)
+}
+
+// bumpFee returns the fee increased by the 10% required by the mempool to replace a transaction.
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(110))
+	bumped.Div(bumped, big.NewInt(100))
+	return bumped.Add(bumped, common.Big1)
+}
+
+func maxBig(a *big.Int, b *big.Int) *big.Int {
+	if a.Cmp(b) > 0 {
+		return a
+	}
+	return b
+}
+
+// inclusionLatency returns the time between the submission and the timestamp of the including block, the block
+// timestamps being in seconds the latency is truncated to 0 when the heartbeat is included within the same second.
+func inclusionLatency(sentAt time.Time, blockTime uint64) time.Duration {
+	latency := time.Unix(int64(blockTime), 0).Sub(sentAt)
+	if latency < 0 {
+		return 0
+	}
+	return latency
+}
+
+// isThresholdExceeded returns true when the latency is above the inclusion threshold.
+func isThresholdExceeded(latency time.Duration, threshold time.Duration) bool {
+	return latency > threshold
+}
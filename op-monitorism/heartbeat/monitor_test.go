@@ -0,0 +1,213 @@
+package heartbeat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestParsePrivateKey(t *testing.T) {
+	expected := common.HexToAddress("0x1Be31A94361a391bBaFB2a4CCd704F57dc04d4bb")
+	for _, key := range []string{"0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef", "1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"} {
+		privateKey, err := parsePrivateKey(key)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		if address := crypto.PubkeyToAddress(privateKey.PublicKey); address != expected {
+			t.Errorf("expected %s but got %s", expected, address)
+		}
+	}
+
+	if _, err := parsePrivateKey("0x1234"); err == nil {
+		t.Errorf("expected an error for a short key")
+	}
+}
+
+func TestGasFeeCap(t *testing.T) {
+	if feeCap := gasFeeCap(big.NewInt(100), big.NewInt(5)); feeCap.Cmp(big.NewInt(205)) != 0 {
+		t.Errorf("expected 205 but got %d", feeCap)
+	}
+}
+
+func TestBumpFee(t *testing.T) {
+	if fee := bumpFee(big.NewInt(1000)); fee.Cmp(big.NewInt(1101)) != 0 {
+		t.Errorf("expected 1101 but got %d", fee)
+	}
+	if fee := bumpFee(big.NewInt(0)); fee.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("expected 1 but got %d", fee)
+	}
+}
+
+func TestInclusionLatency(t *testing.T) {
+	sentAt := time.Unix(1000, 500_000_000)
+	tests := []struct {
+		name      string
+		blockTime uint64
+		expected  time.Duration
+	}{
+		{name: "Included later", blockTime: 1004, expected: 3500 * time.Millisecond},
+		{name: "Included within the same second", blockTime: 1000, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := inclusionLatency(sentAt, test.blockTime)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestIsThresholdExceeded(t *testing.T) {
+	tests := []struct {
+		name     string
+		latency  time.Duration
+		expected bool
+	}{
+		{name: "Below the threshold", latency: 2 * time.Second, expected: false},
+		{name: "On the threshold", latency: 10 * time.Second, expected: false},
+		{name: "Above the threshold", latency: 11 * time.Second, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isThresholdExceeded(test.latency, 10*time.Second)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+// heartbeatNode is a node accepting the heartbeats, the nonce of the account being set by the test.
+type heartbeatNode struct {
+	*fake.Node
+	nonce   uint64
+	sendErr error
+	sent    []*types.Transaction
+}
+
+func newHeartbeatNode(t *testing.T) *heartbeatNode {
+	n := &heartbeatNode{Node: fake.NewNode(t)}
+	n.AddBlock(&types.Header{Number: big.NewInt(1), Time: uint64(time.Now().Unix())})
+	n.Result("eth_getBalance", (*hexutil.Big)(big.NewInt(2e18)))
+	n.Result("eth_maxPriorityFeePerGas", (*hexutil.Big)(big.NewInt(1e9)))
+	n.Handle("eth_getTransactionCount", func(params []json.RawMessage) (interface{}, error) {
+		var tag string
+		if err := fake.Param(params, 1, &tag); err != nil {
+			return nil, err
+		}
+		nonce := n.nonce
+		if tag == "pending" {
+			nonce += uint64(len(n.sent))
+		}
+		return hexutil.Uint64(nonce), nil
+	})
+	n.Handle("eth_sendRawTransaction", func(params []json.RawMessage) (interface{}, error) {
+		if n.sendErr != nil {
+			return nil, n.sendErr
+		}
+		var raw hexutil.Bytes
+		if err := fake.Param(params, 0, &raw); err != nil {
+			return nil, err
+		}
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			return nil, err
+		}
+		n.sent = append(n.sent, tx)
+		return tx.Hash(), nil
+	})
+	return n
+}
+
+// include includes the last heartbeat sent in a new block.
+func (n *heartbeatNode) include(t *testing.T) {
+	tx := n.sent[len(n.sent)-1]
+	header := n.AddBlock(&types.Header{Number: big.NewInt(int64(n.nonce) + 2), Time: uint64(time.Now().Unix())}, tx)
+	n.AddReceipt(&types.Receipt{TxHash: tx.Hash(), Status: types.ReceiptStatusSuccessful, BlockHash: header.Hash(), BlockNumber: header.Number, EffectiveGasPrice: big.NewInt(2e9), Logs: []*types.Log{}})
+	n.nonce, n.sent = n.nonce+1, nil
+}
+
+func TestRun(t *testing.T) {
+	node := newHeartbeatNode(t)
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), CLIConfig{NodeURL: node.URL, PrivateKey: privateKey, HeartbeatInterval: time.Hour, InclusionThreshold: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	ctx := context.Background()
+
+	// the first heartbeat is sent and included.
+	m.Run(ctx)
+	if len(node.sent) != 1 || testutil.ToFloat64(m.heartbeatsSent) != 1 || testutil.ToFloat64(m.balance) != 2 {
+		t.Fatalf("expected a heartbeat sent but got %d", len(node.sent))
+	}
+	node.include(t)
+	m.Run(ctx)
+	if m.pending != nil || testutil.ToFloat64(m.heartbeatsIncluded) != 1 || testutil.ToFloat64(m.effectiveGasPrice) != 2 {
+		t.Fatalf("expected the heartbeat included but got %+v", m.pending)
+	}
+
+	// a heartbeat pending after the threshold fires the alert, resolved once included.
+	m.lastSent = time.Time{}
+	m.Run(ctx)
+	m.pending.sentAt = time.Now().Add(-time.Minute)
+	m.Run(ctx)
+	if firing := notifier.Firing(SlowInclusionRule); len(firing) != 1 || testutil.ToFloat64(m.thresholdExceeded) != 1 {
+		t.Fatalf("expected the slow inclusion alert but got %v", firing)
+	}
+	node.include(t)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(SlowInclusionRule); len(alerts) != 1 || alerts[0].Resolved {
+		t.Fatalf("expected the heartbeat included after the threshold to keep the alert fired once but got %v", alerts)
+	}
+	m.lastSent = time.Time{}
+	m.Run(ctx)
+	node.include(t)
+	m.Run(ctx)
+	if firing := notifier.Firing(SlowInclusionRule); len(firing) != 0 {
+		t.Fatalf("expected the alert resolved once a heartbeat is included within the threshold but got %v", firing)
+	}
+
+	// a heartbeat whose nonce is used by another transaction is dropped, the next one using the next nonce.
+	m.lastSent = time.Time{}
+	m.Run(ctx)
+	node.nonce, node.sent = node.nonce+1, nil
+	m.Run(ctx)
+	if m.pending != nil || testutil.ToFloat64(m.heartbeatsDropped) != 1 || len(notifier.Alerts(NonceUsedRule)) != 1 {
+		t.Fatalf("expected the heartbeat dropped but got %+v", m.pending)
+	}
+	m.lastSent = time.Time{}
+	m.Run(ctx)
+	if len(node.sent) != 1 || node.sent[0].Nonce() != node.nonce {
+		t.Fatalf("expected a heartbeat sent with the nonce %d", node.nonce)
+	}
+
+	// a replacement rejected for its nonce drops the heartbeat too.
+	node.sendErr = errors.New("nonce too low")
+	m.pending.resentAt = time.Time{}
+	m.Run(ctx)
+	if m.pending != nil || testutil.ToFloat64(m.heartbeatsDropped) != 2 {
+		t.Fatalf("expected the heartbeat dropped but got %+v", m.pending)
+	}
+}
@@ -0,0 +1,114 @@
+// Package beacon fetches the blob sidecars from the beacon node API.
+package beacon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+const (
+	genesisMethod      = "eth/v1/beacon/genesis"
+	specMethod         = "eth/v1/config/spec"
+	blobSidecarsMethod = "eth/v1/beacon/blob_sidecars/"
+)
+
+var (
+	errNotFound = errors.New("not found")
+)
+
+// Client fetches the blob sidecars from the beacon node API, the slot of an L1 block being derived from its timestamp.
+type Client struct {
+	url        string
+	httpClient *http.Client
+
+	genesisTime    uint64
+	secondsPerSlot uint64
+}
+
+// NewClient returns the client of the beacon node, `Init` reading the genesis and the spec before the slots are derived.
+func NewClient(url string) *Client {
+	return &Client{url: strings.TrimSuffix(url, "/"), httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (c *Client) get(ctx context.Context, method string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/"+method, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d for %s", resp.StatusCode, method)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Init reads the genesis time and the seconds per slot of the beacon chain.
+func (c *Client) Init(ctx context.Context) error {
+	var genesis eth.APIGenesisResponse
+	if err := c.get(ctx, genesisMethod, &genesis); err != nil {
+		return fmt.Errorf("failed to query the genesis: %w", err)
+	}
+	var spec eth.APIConfigResponse
+	if err := c.get(ctx, specMethod, &spec); err != nil {
+		return fmt.Errorf("failed to query the spec: %w", err)
+	}
+	if spec.Data.SecondsPerSlot == 0 {
+		return fmt.Errorf("invalid seconds per slot")
+	}
+	c.genesisTime, c.secondsPerSlot = uint64(genesis.Data.GenesisTime), uint64(spec.Data.SecondsPerSlot)
+	return nil
+}
+
+// Slot returns the slot of the given timestamp.
+func (c *Client) Slot(timestamp uint64) uint64 {
+	if timestamp < c.genesisTime {
+		return 0
+	}
+	return (timestamp - c.genesisTime) / c.secondsPerSlot
+}
+
+// Sidecars returns the blob sidecars of the slot indexed by versioned hash, none when the beacon node does not have them.
+func (c *Client) Sidecars(ctx context.Context, slot uint64) (map[common.Hash]*eth.APIBlobSidecar, error) {
+	var resp eth.APIGetBlobSidecarsResponse
+	err := c.get(ctx, fmt.Sprintf("%s%d", blobSidecarsMethod, slot), &resp)
+	if errors.Is(err, errNotFound) {
+		return map[common.Hash]*eth.APIBlobSidecar{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the blob sidecars of the slot %d: %w", slot, err)
+	}
+
+	sidecars := make(map[common.Hash]*eth.APIBlobSidecar, len(resp.Data))
+	for _, sidecar := range resp.Data {
+		sidecars[VersionedHash(sidecar.KZGCommitment)] = sidecar
+	}
+	return sidecars, nil
+}
+
+// VersionedHash returns the versioned hash of the blob with the given KZG commitment.
+func VersionedHash(commitment eth.Bytes48) common.Hash {
+	return kzg4844.CalcBlobHashV1(sha256.New(), (*kzg4844.Commitment)(&commitment))
+}
+
+// VerifySidecar checks the KZG proof of the blob against its commitment.
+func VerifySidecar(sidecar *eth.APIBlobSidecar) error {
+	return kzg4844.VerifyBlobProof(kzg4844.Blob(sidecar.Blob), kzg4844.Commitment(sidecar.KZGCommitment), kzg4844.Proof(sidecar.KZGProof))
+}
@@ -0,0 +1,67 @@
+package beacon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/beacon/genesis":
+			_, _ = w.Write([]byte(`{"data":{"genesis_time":"1000"}}`))
+		case "/eth/v1/config/spec":
+			_, _ = w.Write([]byte(`{"data":{"SECONDS_PER_SLOT":"12"}}`))
+		case "/eth/v1/beacon/blob_sidecars/10":
+			_, _ = w.Write([]byte(`{"data":[{"index":"0","blob":"0x` + zeroBlob + `","kzg_commitment":"0x` + zeroes(48) + `","kzg_proof":"0x` + zeroes(48) + `"}]}`))
+		case "/eth/v1/beacon/blob_sidecars/11":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL + "/")
+	if err := c.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slot := c.Slot(1000 + 10*12 + 5); slot != 10 {
+		t.Errorf("expected the slot 10 but got %d", slot)
+	}
+	if slot := c.Slot(999); slot != 0 {
+		t.Errorf("expected the slot 0 before the genesis but got %d", slot)
+	}
+
+	sidecars, err := c.Sidecars(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := sidecars[VersionedHash([48]byte{})]; len(sidecars) != 1 || !ok {
+		t.Errorf("expected the sidecar indexed by its versioned hash but got %v", sidecars)
+	}
+	if sidecars, err := c.Sidecars(context.Background(), 11); err != nil || len(sidecars) != 0 {
+		t.Errorf("expected no sidecar for a slot not found but got %v (%v)", sidecars, err)
+	}
+	if _, err := c.Sidecars(context.Background(), 12); err == nil {
+		t.Errorf("expected the error of the beacon node")
+	}
+}
+
+func TestVersionedHash(t *testing.T) {
+	hash := VersionedHash([48]byte{})
+	if hash[0] != 0x01 {
+		t.Errorf("expected the version 0x01 but got %#x", hash[0])
+	}
+}
+
+// zeroBlob is the hex encoding of an empty blob.
+var zeroBlob = zeroes(131072)
+
+// zeroes returns the hex encoding of n zero bytes.
+func zeroes(n int) string {
+	return strings.Repeat("0", 2*n)
+}
@@ -0,0 +1,612 @@
+// Package fake serves a fake JSON-RPC node and records the alerts, for the tests running the monitors end to end.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Handler returns the result of a JSON-RPC method from its raw parameters, the result being encoded in JSON.
+type Handler func(params []json.RawMessage) (interface{}, error)
+
+// CallHandler returns the output of an `eth_call` to a contract from its input.
+type CallHandler func(input []byte) ([]byte, error)
+
+// Error is a JSON-RPC error, e.g. a revert with its data.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Revert returns the error of a reverted call with the revert data.
+func Revert(data []byte) error {
+	return &Error{Code: 3, Message: "execution reverted", Data: hexutil.Encode(data)}
+}
+
+// Node is a JSON-RPC node serving the blocks, the transactions, the receipts, the logs and the contracts given by the tests, the other
+// methods being served by their handler and failing without one.
+type Node struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+	calls    map[common.Address]CallHandler
+	blocks   []*block
+	receipts map[common.Hash]*types.Receipt
+	logs     []types.Log
+	requests map[string]int
+}
+
+// block is a header with the transactions of the block.
+type block struct {
+	header *types.Header
+	txs    []*types.Transaction
+}
+
+// NewNode starts a node closed at the end of the test.
+func NewNode(t testing.TB) *Node {
+	n := &Node{
+		handlers: map[string]Handler{},
+		calls:    map[common.Address]CallHandler{},
+		receipts: map[common.Hash]*types.Receipt{},
+		requests: map[string]int{},
+	}
+	n.Server = httptest.NewServer(http.HandlerFunc(n.serve))
+	t.Cleanup(n.Close)
+
+	n.Handle("eth_chainId", func(_ []json.RawMessage) (interface{}, error) { return hexutil.Uint64(1), nil })
+	n.Handle("eth_blockNumber", n.blockNumber)
+	n.Handle("eth_getBlockByNumber", n.blockByNumber)
+	n.Handle("eth_getBlockByHash", n.blockByHash)
+	n.Handle("eth_getTransactionByHash", n.transactionByHash)
+	n.Handle("eth_getTransactionReceipt", n.receipt)
+	n.Handle("eth_getLogs", n.filterLogs)
+	n.Handle("eth_call", n.call)
+	return n
+}
+
+// Handle serves the method with the handler, replacing the previous one.
+func (n *Node) Handle(method string, handler Handler) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.handlers[method] = handler
+}
+
+// Result serves the method with a constant result.
+func (n *Node) Result(method string, result interface{}) {
+	n.Handle(method, func(_ []json.RawMessage) (interface{}, error) { return result, nil })
+}
+
+// Fail fails the method with the error.
+func (n *Node) Fail(method string, err error) {
+	n.Handle(method, func(_ []json.RawMessage) (interface{}, error) { return nil, err })
+}
+
+// Requests returns the number of requests of the method.
+func (n *Node) Requests(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.requests[method]
+}
+
+// AddBlock appends a block with the transactions, the hash of the parent and the roots being filled in. The header is
+// returned with its final hash.
+func (n *Node) AddBlock(header *types.Header, txs ...*types.Transaction) *types.Header {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// the copy of a header without number has the number 0.
+	numbered := header.Number != nil
+	header = types.CopyHeader(header)
+	if !numbered {
+		header.Number = big.NewInt(int64(len(n.blocks)))
+	}
+	if header.Difficulty == nil {
+		header.Difficulty = new(big.Int)
+	}
+	if header.BaseFee == nil {
+		header.BaseFee = big.NewInt(params.GWei)
+	}
+	header.UncleHash = types.EmptyUncleHash
+	header.TxHash = types.EmptyTxsHash
+	if len(txs) > 0 {
+		// only the emptiness of the root is checked by the clients.
+		hashes := make([][]byte, len(txs))
+		for i, tx := range txs {
+			hashes[i] = tx.Hash().Bytes()
+		}
+		header.TxHash = crypto.Keccak256Hash(hashes...)
+	}
+	header.ReceiptHash = types.EmptyReceiptsHash
+	if parent := n.byNumber(new(big.Int).Sub(header.Number, common.Big1)); parent != nil && header.ParentHash == (common.Hash{}) {
+		header.ParentHash = parent.header.Hash()
+	}
+
+	// a block replacing another at the same height reorgs the chain from its height.
+	for i, b := range n.blocks {
+		if b.header.Number.Cmp(header.Number) >= 0 {
+			n.blocks = n.blocks[:i]
+			break
+		}
+	}
+	n.blocks = append(n.blocks, &block{header: header, txs: txs})
+	return types.CopyHeader(header)
+}
+
+// AddReceipt serves the receipt of its transaction.
+func (n *Node) AddReceipt(receipt *types.Receipt) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.receipts[receipt.TxHash] = receipt
+}
+
+// AddLogs serves the logs to the filters matching them.
+func (n *Node) AddLogs(logs ...types.Log) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.logs = append(n.logs, logs...)
+}
+
+// HandleCall serves the calls to the address with the handler.
+func (n *Node) HandleCall(address common.Address, handler CallHandler) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.calls[address] = handler
+}
+
+// Contract serves the calls to the methods of the contract at the address through its ABI.
+func (n *Node) Contract(address common.Address, parsed *abi.ABI) *Contract {
+	c := &Contract{abi: parsed, methods: map[string]func(args []interface{}) ([]interface{}, error){}}
+	n.HandleCall(address, c.call)
+	return c
+}
+
+// Contract serves the methods of a contract, the methods without result reverting.
+type Contract struct {
+	mu      sync.Mutex
+	abi     *abi.ABI
+	methods map[string]func(args []interface{}) ([]interface{}, error)
+}
+
+// Returns sets the constant outputs of the method.
+func (c *Contract) Returns(method string, outputs ...interface{}) *Contract {
+	return c.Handle(method, func(_ []interface{}) ([]interface{}, error) { return outputs, nil })
+}
+
+// Handle serves the method with the function of its arguments.
+func (c *Contract) Handle(method string, fn func(args []interface{}) ([]interface{}, error)) *Contract {
+	if _, ok := c.abi.Methods[method]; !ok {
+		panic(fmt.Sprintf("unknown method %s", method))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.methods[method] = fn
+	return c
+}
+
+func (c *Contract) call(input []byte) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, Revert(nil)
+	}
+	method, err := c.abi.MethodById(input[:4])
+	if err != nil {
+		return nil, Revert(nil)
+	}
+	c.mu.Lock()
+	fn, ok := c.methods[method.Name]
+	c.mu.Unlock()
+	if !ok {
+		return nil, Revert(nil)
+	}
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the arguments of %s: %w", method.Name, err)
+	}
+	outputs, err := fn(args)
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.Pack(outputs...)
+}
+
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+func (n *Node) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+		var reqs []request
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resps := make([]response, len(reqs))
+		for i, req := range reqs {
+			resps[i] = n.handle(req)
+		}
+		_ = json.NewEncoder(w).Encode(resps)
+		return
+	}
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(n.handle(req))
+}
+
+func (n *Node) handle(req request) response {
+	n.mu.Lock()
+	n.requests[req.Method]++
+	handler, ok := n.handlers[req.Method]
+	n.mu.Unlock()
+
+	resp := response{JSONRPC: "2.0", ID: req.ID}
+	if !ok {
+		resp.Error = &Error{Code: -32601, Message: fmt.Sprintf("the method %s does not exist", req.Method)}
+		return resp
+	}
+	result, err := handler(req.Params)
+	if err != nil {
+		rpcErr, ok := err.(*Error)
+		if !ok {
+			rpcErr = &Error{Code: -32000, Message: err.Error()}
+		}
+		resp.Error = rpcErr
+		return resp
+	}
+	resp.Result = result
+	return resp
+}
+
+// Param decodes the parameter of index i into v.
+func Param(params []json.RawMessage, i int, v interface{}) error {
+	if i >= len(params) {
+		return fmt.Errorf("missing parameter %d", i)
+	}
+	return json.Unmarshal(params[i], v)
+}
+
+func (n *Node) blockNumber(_ []json.RawMessage) (interface{}, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.blocks) == 0 {
+		return hexutil.Uint64(0), nil
+	}
+	return (*hexutil.Big)(n.blocks[len(n.blocks)-1].header.Number), nil
+}
+
+// byNumber returns the block of the number, the latest one when nil.
+func (n *Node) byNumber(number *big.Int) *block {
+	if len(n.blocks) == 0 {
+		return nil
+	}
+	if number == nil {
+		return n.blocks[len(n.blocks)-1]
+	}
+	for _, b := range n.blocks {
+		if b.header.Number.Cmp(number) == 0 {
+			return b
+		}
+	}
+	return nil
+}
+
+func (n *Node) blockByNumber(params []json.RawMessage) (interface{}, error) {
+	var tag string
+	if err := Param(params, 0, &tag); err != nil {
+		return nil, err
+	}
+	var full bool
+	_ = Param(params, 1, &full)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var number *big.Int
+	switch tag {
+	case "latest", "pending", "safe", "finalized":
+	case "earliest":
+		number = new(big.Int)
+	default:
+		parsed, err := hexutil.DecodeBig(tag)
+		if err != nil {
+			return nil, err
+		}
+		number = parsed
+	}
+	b := n.byNumber(number)
+	if b == nil {
+		return nil, nil
+	}
+	return b.marshal(full)
+}
+
+func (n *Node) blockByHash(params []json.RawMessage) (interface{}, error) {
+	var hash common.Hash
+	if err := Param(params, 0, &hash); err != nil {
+		return nil, err
+	}
+	var full bool
+	_ = Param(params, 1, &full)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, b := range n.blocks {
+		if b.header.Hash() == hash {
+			return b.marshal(full)
+		}
+	}
+	return nil, nil
+}
+
+// marshal encodes the block as returned by `eth_getBlockBy*`.
+func (b *block) marshal(full bool) (interface{}, error) {
+	encoded, err := json.Marshal(b.header)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	txs := make([]interface{}, len(b.txs))
+	for i, tx := range b.txs {
+		if !full {
+			txs[i] = tx.Hash()
+			continue
+		}
+		if txs[i], err = b.marshalTx(i); err != nil {
+			return nil, err
+		}
+	}
+	fields["transactions"], fields["uncles"] = txs, []common.Hash{}
+	return fields, nil
+}
+
+// marshalTx encodes the i-th transaction of the block as returned by `eth_getTransactionByHash`.
+func (b *block) marshalTx(i int) (interface{}, error) {
+	tx := b.txs[i]
+	encoded, err := json.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	fields["blockHash"], fields["blockNumber"], fields["transactionIndex"] = b.header.Hash(), (*hexutil.Big)(b.header.Number), hexutil.Uint64(i)
+	if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+		fields["from"] = from
+	}
+	return fields, nil
+}
+
+func (n *Node) transactionByHash(params []json.RawMessage) (interface{}, error) {
+	var hash common.Hash
+	if err := Param(params, 0, &hash); err != nil {
+		return nil, err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, b := range n.blocks {
+		for i, tx := range b.txs {
+			if tx.Hash() == hash {
+				return b.marshalTx(i)
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (n *Node) receipt(params []json.RawMessage) (interface{}, error) {
+	var hash common.Hash
+	if err := Param(params, 0, &hash); err != nil {
+		return nil, err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	receipt, ok := n.receipts[hash]
+	if !ok {
+		return nil, nil
+	}
+	return receipt, nil
+}
+
+// filterQuery is the filter of `eth_getLogs`.
+type filterQuery struct {
+	BlockHash *common.Hash    `json:"blockHash"`
+	FromBlock string          `json:"fromBlock"`
+	ToBlock   string          `json:"toBlock"`
+	Addresses json.RawMessage `json:"address"`
+	Topics    []interface{}   `json:"topics"`
+}
+
+func (n *Node) filterLogs(params []json.RawMessage) (interface{}, error) {
+	var query filterQuery
+	if err := Param(params, 0, &query); err != nil {
+		return nil, err
+	}
+	var addresses []common.Address
+	if len(query.Addresses) > 0 {
+		var address common.Address
+		if err := json.Unmarshal(query.Addresses, &address); err == nil {
+			addresses = []common.Address{address}
+		} else if err := json.Unmarshal(query.Addresses, &addresses); err != nil {
+			return nil, fmt.Errorf("invalid addresses: %w", err)
+		}
+	}
+	topics, err := decodeTopics(query.Topics)
+	if err != nil {
+		return nil, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	from, to := uint64(0), uint64(1<<63)
+	if len(query.FromBlock) > 0 && query.FromBlock != "earliest" {
+		if from, err = n.blockTag(query.FromBlock); err != nil {
+			return nil, err
+		}
+	}
+	if len(query.ToBlock) > 0 {
+		if to, err = n.blockTag(query.ToBlock); err != nil {
+			return nil, err
+		}
+	}
+
+	logs := []types.Log{}
+	for _, log := range n.logs {
+		if query.BlockHash != nil {
+			if log.BlockHash != *query.BlockHash {
+				continue
+			}
+		} else if log.BlockNumber < from || log.BlockNumber > to {
+			continue
+		}
+		if len(addresses) > 0 && !containsAddress(addresses, log.Address) {
+			continue
+		}
+		if !matchTopics(topics, log.Topics) {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].BlockNumber < logs[j].BlockNumber })
+	return logs, nil
+}
+
+// blockTag returns the number of the block tag, the latest block for the named tags.
+func (n *Node) blockTag(tag string) (uint64, error) {
+	switch tag {
+	case "latest", "pending", "safe", "finalized":
+		if len(n.blocks) == 0 {
+			return 0, nil
+		}
+		return n.blocks[len(n.blocks)-1].header.Number.Uint64(), nil
+	}
+	return hexutil.DecodeUint64(tag)
+}
+
+// decodeTopics decodes the topics of a filter, each position being null, a topic or a list of topics.
+func decodeTopics(raw []interface{}) ([][]common.Hash, error) {
+	topics := make([][]common.Hash, len(raw))
+	for i, position := range raw {
+		switch value := position.(type) {
+		case nil:
+		case string:
+			topics[i] = []common.Hash{common.HexToHash(value)}
+		case []interface{}:
+			for _, topic := range value {
+				s, ok := topic.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid topic %v", topic)
+				}
+				topics[i] = append(topics[i], common.HexToHash(s))
+			}
+		default:
+			return nil, fmt.Errorf("invalid topic %v", value)
+		}
+	}
+	return topics, nil
+}
+
+func matchTopics(filter [][]common.Hash, topics []common.Hash) bool {
+	if len(filter) > len(topics) {
+		return false
+	}
+	for i, candidates := range filter {
+		if len(candidates) == 0 {
+			continue
+		}
+		match := false
+		for _, candidate := range candidates {
+			if candidate == topics[i] {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+// callArgs are the arguments of `eth_call`.
+type callArgs struct {
+	To    *common.Address `json:"to"`
+	Input hexutil.Bytes   `json:"input"`
+	Data  hexutil.Bytes   `json:"data"`
+}
+
+func (n *Node) call(params []json.RawMessage) (interface{}, error) {
+	var args callArgs
+	if err := Param(params, 0, &args); err != nil {
+		return nil, err
+	}
+	if args.To == nil {
+		return nil, fmt.Errorf("missing to")
+	}
+	input := args.Input
+	if len(input) == 0 {
+		input = args.Data
+	}
+
+	n.mu.Lock()
+	handler, ok := n.calls[*args.To]
+	n.mu.Unlock()
+	if !ok {
+		// a call to an account without code succeeds with no output.
+		return hexutil.Bytes{}, nil
+	}
+	output, err := handler(input)
+	if err != nil {
+		return nil, err
+	}
+	return hexutil.Bytes(output), nil
+}
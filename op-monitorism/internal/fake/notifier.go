@@ -0,0 +1,64 @@
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+)
+
+// Notifier records the alerts of a monitor.
+type Notifier struct {
+	mu     sync.Mutex
+	alerts []alerts.Alert
+}
+
+func (n *Notifier) Name() string {
+	return "fake"
+}
+
+func (n *Notifier) Notify(_ context.Context, alert alerts.Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+// Alerts returns the alerts of the rule, every alert when empty.
+func (n *Notifier) Alerts(rule string) []alerts.Alert {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var matching []alerts.Alert
+	for _, alert := range n.alerts {
+		if len(rule) == 0 || alert.Rule == rule {
+			matching = append(matching, alert)
+		}
+	}
+	return matching
+}
+
+// Firing returns the entities whose last alert of the rule is firing, in the order of their first alert.
+func (n *Notifier) Firing(rule string) []string {
+	var entities []string
+	firing := map[string]bool{}
+	for _, alert := range n.Alerts(rule) {
+		if _, ok := firing[alert.Entity]; !ok {
+			entities = append(entities, alert.Entity)
+		}
+		firing[alert.Entity] = !alert.Resolved
+	}
+	var result []string
+	for _, entity := range entities {
+		if firing[entity] {
+			result = append(result, entity)
+		}
+	}
+	return result
+}
+
+// Reset forgets the alerts recorded.
+func (n *Notifier) Reset() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = nil
+}
@@ -0,0 +1,101 @@
+// Package util holds the helpers shared by the monitors.
+package util
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BoolToFloat returns 1 for true and 0 for false, the value of a boolean gauge.
+func BoolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// WeiToEther converts an amount of wei to ether.
+func WeiToEther(wei *big.Int) float64 {
+	num := new(big.Rat).SetInt(wei)
+	denom := big.NewRat(params.Ether, 1)
+	num = num.Quo(num, denom)
+	f, _ := num.Float64()
+	return f
+}
+
+// WeiToGwei converts an amount of wei to gwei, e.g. a gas price.
+func WeiToGwei(wei *big.Int) float64 {
+	num := new(big.Rat).SetInt(wei)
+	denom := big.NewRat(params.GWei, 1)
+	num = num.Quo(num, denom)
+	f, _ := num.Float64()
+	return f
+}
+
+// ToUnits converts an ERC-20 amount to token units using its decimals.
+func ToUnits(amount *big.Int, decimals uint8) float64 {
+	num := new(big.Rat).SetInt(amount)
+	denom := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	f, _ := num.Quo(num, denom).Float64()
+	return f
+}
+
+// MustParseJSONABI parses a hand-written ABI, the ABIs are constants so this is not supposed to fail.
+func MustParseJSONABI(definition string) *abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse the ABI: %v", err))
+	}
+	return &parsed
+}
+
+// MustParseABI parses the ABI of a binding, the ABIs are generated so this is not supposed to fail.
+func MustParseABI(metadata *bind.MetaData) *abi.ABI {
+	parsed, err := metadata.GetAbi()
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse the ABI: %v", err))
+	}
+	return parsed
+}
+
+// ParseNamedValue parses a value formatted via `name=value`, the format being the one of the error, e.g. `name=url`.
+func ParseNamedValue(namedValue string, format string) (string, string, error) {
+	split := strings.SplitN(namedValue, "=", 2)
+	if len(split) != 2 || len(split[0]) == 0 || len(split[1]) == 0 {
+		return "", "", fmt.Errorf("failed to parse `%s`: %s", format, namedValue)
+	}
+	return split[0], split[1], nil
+}
+
+// ParseBridgedToken parses a bridged token formatted via `symbol:l1Address:l2Address`.
+func ParseBridgedToken(token string) (string, common.Address, common.Address, error) {
+	split := strings.Split(token, ":")
+	if len(split) != 3 {
+		return "", common.Address{}, common.Address{}, fmt.Errorf("failed to parse `symbol:l1Address:l2Address`: %s", token)
+	}
+
+	symbol, l1Token, l2Token := split[0], split[1], split[2]
+	if len(symbol) == 0 {
+		return "", common.Address{}, common.Address{}, fmt.Errorf("symbol for %s not set", token)
+	}
+	for _, addr := range []string{l1Token, l2Token} {
+		if !common.IsHexAddress(addr) {
+			return "", common.Address{}, common.Address{}, fmt.Errorf("address is not a hex-encoded address: %s", addr)
+		}
+	}
+	return symbol, common.HexToAddress(l1Token), common.HexToAddress(l2Token), nil
+}
+
+// IsRevert returns whether the error of the call is a revert rather than a failure of the node.
+func IsRevert(err error) bool {
+	var dataErr rpc.DataError
+	return errors.As(err, &dataErr) || strings.HasPrefix(err.Error(), "execution reverted")
+}
@@ -0,0 +1,125 @@
+package util
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// dataError is a JSON-RPC error carrying the revert data.
+type dataError struct{}
+
+func (dataError) Error() string          { return "reverted" }
+func (dataError) ErrorData() interface{} { return "0x" }
+
+func TestWeiToEther(t *testing.T) {
+	tests := []struct {
+		wei      *big.Int
+		expected float64
+	}{
+		{big.NewInt(0), 0},
+		{big.NewInt(1e18), 1},
+		{big.NewInt(15e17), 1.5},
+		{new(big.Int).Mul(big.NewInt(1234), big.NewInt(1e18)), 1234},
+	}
+
+	for _, test := range tests {
+		if ether := WeiToEther(test.wei); ether != test.expected {
+			t.Errorf("expected %v ether for %s wei but got %v", test.expected, test.wei, ether)
+		}
+	}
+}
+
+func TestWeiToGwei(t *testing.T) {
+	tests := []struct {
+		wei      *big.Int
+		expected float64
+	}{
+		{big.NewInt(0), 0},
+		{big.NewInt(1e9), 1},
+		{big.NewInt(25e8), 2.5},
+	}
+
+	for _, test := range tests {
+		if gwei := WeiToGwei(test.wei); gwei != test.expected {
+			t.Errorf("expected %v gwei for %s wei but got %v", test.expected, test.wei, gwei)
+		}
+	}
+}
+
+func TestToUnits(t *testing.T) {
+	tests := []struct {
+		amount   *big.Int
+		decimals uint8
+		expected float64
+	}{
+		{big.NewInt(0), 18, 0},
+		{big.NewInt(15e5), 6, 1.5},
+		{big.NewInt(1234), 0, 1234},
+		{new(big.Int).Mul(big.NewInt(2), big.NewInt(1e18)), 18, 2},
+	}
+
+	for _, test := range tests {
+		if units := ToUnits(test.amount, test.decimals); units != test.expected {
+			t.Errorf("expected %v units for %s with %d decimals but got %v", test.expected, test.amount, test.decimals, units)
+		}
+	}
+}
+
+func TestParseNamedValue(t *testing.T) {
+	tests := []struct {
+		value string
+		name  string
+		url   string
+		ok    bool
+	}{
+		{"mainnet=http://localhost:8545", "mainnet", "http://localhost:8545", true},
+		{"mainnet=http://localhost:8545/?key=value", "mainnet", "http://localhost:8545/?key=value", true},
+		{"http://localhost:8545", "", "", false},
+		{"=http://localhost:8545", "", "", false},
+		{"mainnet=", "", "", false},
+	}
+
+	for _, test := range tests {
+		name, url, err := ParseNamedValue(test.value, "name=url")
+		if (err == nil) != test.ok || name != test.name || url != test.url {
+			t.Errorf("Failed %s: expected %s, %s and ok=%v but got %s, %s and %v", test.value, test.name, test.url, test.ok, name, url, err)
+		}
+	}
+}
+
+func TestParseBridgedToken(t *testing.T) {
+	symbol, l1Token, l2Token, err := ParseBridgedToken("USDC:0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48:0x7F5c764cBc14f9669B88837ca1490cCa17c31607")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if symbol != "USDC" || l1Token.Hex() != "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48" || l2Token.Hex() != "0x7F5c764cBc14f9669B88837ca1490cCa17c31607" {
+		t.Errorf("unexpected token %s, %s and %s", symbol, l1Token, l2Token)
+	}
+
+	for _, invalid := range []string{"USDC", ":0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48:0x7F5c764cBc14f9669B88837ca1490cCa17c31607", "USDC:0x01:0x7F5c764cBc14f9669B88837ca1490cCa17c31607"} {
+		if _, _, _, err := ParseBridgedToken(invalid); err == nil {
+			t.Errorf("expected an error for %s", invalid)
+		}
+	}
+}
+
+func TestIsRevert(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		revert bool
+	}{
+		{"revert data", dataError{}, true},
+		{"revert message", errors.New("execution reverted: paused"), true},
+		{"node failure", errors.New("connection reset by peer"), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if revert := IsRevert(test.err); revert != test.revert {
+				t.Errorf("Failed %s: expected %v but got %v", test.name, test.revert, revert)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package interop
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L2NodeURLFlagName = "l2.node.url"
+
+	L1BlockAddressFlagName      = "l1block.address"
+	CrossL2InboxAddressFlagName = "crossl2inbox.address"
+	DependencySetFlagName       = "dependency.set"
+	ExpiryWindowFlagName        = "expiry.window"
+	StartBlockHeightFlagName    = "start.block.height"
+	EventBlockRangeFlagName     = "event.block.range"
+)
+
+// CrossL2InboxAddr is the address of the CrossL2Inbox predeploy of the interop chains.
+var CrossL2InboxAddr = common.HexToAddress("0x4200000000000000000000000000000000000022")
+
+type CLIConfig struct {
+	L2NodeURL string
+
+	L1BlockAddress      common.Address
+	CrossL2InboxAddress common.Address
+
+	// DependencySet is the expected dependency set of the chain, not checked when empty.
+	DependencySet []uint64
+
+	// ExpiryWindow is the duration after which an initiating message can no longer be executed.
+	ExpiryWindow time.Duration
+
+	StartBlockHeight uint64
+	EventBlockRange  uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L2NodeURL:        ctx.String(L2NodeURLFlagName),
+		DependencySet:    ctx.Uint64Slice(DependencySetFlagName),
+		ExpiryWindow:     ctx.Duration(ExpiryWindowFlagName),
+		StartBlockHeight: ctx.Uint64(StartBlockHeightFlagName),
+		EventBlockRange:  ctx.Uint64(EventBlockRangeFlagName),
+	}
+
+	for flagName, address := range map[string]*common.Address{L1BlockAddressFlagName: &cfg.L1BlockAddress, CrossL2InboxAddressFlagName: &cfg.CrossL2InboxAddress} {
+		value := ctx.String(flagName)
+		if !common.IsHexAddress(value) {
+			return cfg, fmt.Errorf("--%s is not a hex-encoded address", flagName)
+		}
+		*address = common.HexToAddress(value)
+	}
+
+	if cfg.ExpiryWindow <= 0 {
+		return cfg, fmt.Errorf("--%s must be positive", ExpiryWindowFlagName)
+	}
+	if cfg.EventBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", EventBlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L2NodeURLFlagName,
+			Usage:   "Node URL of the L2 peer of the interop chain",
+			Value:   "127.0.0.1:9545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    L1BlockAddressFlagName,
+			Usage:   "Address of the L1Block predeploy holding the dependency set",
+			Value:   predeploys.L1BlockAddr.Hex(),
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_BLOCK"),
+		},
+		&cli.StringFlag{
+			Name:    CrossL2InboxAddressFlagName,
+			Usage:   "Address of the CrossL2Inbox predeploy executing the cross-chain messages",
+			Value:   CrossL2InboxAddr.Hex(),
+			EnvVars: opservice.PrefixEnvVar(envVar, "CROSS_L2_INBOX"),
+		},
+		&cli.Uint64SliceFlag{
+			Name:    DependencySetFlagName,
+			Usage:   "Chain IDs of the expected dependency set, not checked when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "DEPENDENCY_SET"),
+		},
+		&cli.DurationFlag{
+			Name:    ExpiryWindowFlagName,
+			Usage:   "Duration after which an initiating message can no longer be executed",
+			Value:   7 * 24 * time.Hour,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EXPIRY_WINDOW"),
+		},
+		&cli.Uint64Flag{
+			Name:    StartBlockHeightFlagName,
+			Usage:   "Starting height to scan for the dependency changes and the executing messages, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    EventBlockRangeFlagName,
+			Usage:   "Max block range when scanning for events",
+			Value:   1000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EVENT_BLOCK_RANGE"),
+		},
+	}
+}
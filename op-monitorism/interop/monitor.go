@@ -0,0 +1,400 @@
+package interop
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "interop"
+	MetricsNamespace = "interop_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the chain id or the hash of the executing message.
+	DependencyChangedRule  = "dependency set changed"
+	DependencyMismatchRule = "unexpected membership in the dependency set"
+	InvalidMessageRule     = "invalid executing message"
+
+	// L1BlockInteropABI is the subset of the interop L1Block used by the monitor, the dependency set being updated by the deposits of the SystemConfig.
+	L1BlockInteropABI = `[{"inputs":[],"name":"dependencySetSize","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"_chainId","type":"uint256"}],"name":"isInDependencySet","outputs":[{"name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"name":"chainId","type":"uint256"}],"name":"DependencyAdded","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"chainId","type":"uint256"}],"name":"DependencyRemoved","type":"event"}]`
+
+	// CrossL2InboxABI is the subset of the CrossL2Inbox used by the monitor.
+	CrossL2InboxABI = `[{"anonymous":false,"inputs":[{"indexed":true,"name":"msgHash","type":"bytes32"},{"components":[{"name":"origin","type":"address"},{"name":"blockNumber","type":"uint256"},{"name":"logIndex","type":"uint256"},{"name":"timestamp","type":"uint256"},{"name":"chainId","type":"uint256"}],"indexed":false,"name":"id","type":"tuple"}],"name":"ExecutingMessage","type":"event"}]`
+)
+
+var (
+	l1BlockInteropABI = util.MustParseJSONABI(L1BlockInteropABI)
+	crossL2InboxABI   = util.MustParseJSONABI(CrossL2InboxABI)
+)
+
+// Identifier is the identifier of the initiating message referenced by an executing message.
+type Identifier struct {
+	Origin      common.Address
+	BlockNumber *big.Int
+	LogIndex    *big.Int
+	Timestamp   *big.Int
+	ChainId     *big.Int
+}
+
+// membershipKey caches the membership of a chain in the dependency set at a block.
+type membershipKey struct {
+	chainID string
+	block   uint64
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l2Client *ethclient.Client
+
+	l1BlockAddress      common.Address
+	crossL2InboxAddress common.Address
+
+	expectedDependencies map[string]bool
+	expiryWindow         time.Duration
+
+	nextL2Height    uint64
+	eventBlockRange uint64
+
+	// dependencies are the chains observed in the dependency set, seeded with the expected ones.
+	dependencies map[string]*big.Int
+
+	// metrics
+	highestBlockNumber     *prometheus.GaugeVec
+	dependencySetSize      prometheus.Gauge
+	dependency             *prometheus.GaugeVec
+	dependencyChanges      *prometheus.CounterVec
+	dependencyMismatch     *prometheus.GaugeVec
+	unexpectedDependencies prometheus.Gauge
+	executingMessages      *prometheus.CounterVec
+	unexpectedRpcErrors    *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating interop monitor...")
+
+	l2Client, err := ethclient.Dial(cfg.L2NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l2: %w", err)
+	}
+
+	startingL2Height := cfg.StartBlockHeight
+	if startingL2Height == 0 {
+		if startingL2Height, err = l2Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+	log.Info("configured starting height", "height", startingL2Height, "dependency_set", cfg.DependencySet, "expiry_window", cfg.ExpiryWindow)
+
+	expectedDependencies := make(map[string]bool, len(cfg.DependencySet))
+	dependencies := make(map[string]*big.Int, len(cfg.DependencySet))
+	for _, chainID := range cfg.DependencySet {
+		id := new(big.Int).SetUint64(chainID)
+		expectedDependencies[id.String()] = true
+		dependencies[id.String()] = id
+	}
+
+	return &Monitor{
+		log: log,
+
+		l2Client: l2Client,
+
+		l1BlockAddress:      cfg.L1BlockAddress,
+		crossL2InboxAddress: cfg.CrossL2InboxAddress,
+
+		expectedDependencies: expectedDependencies,
+		expiryWindow:         cfg.ExpiryWindow,
+
+		nextL2Height:    startingL2Height,
+		eventBlockRange: cfg.EventBlockRange,
+
+		dependencies: dependencies,
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed l2 heights (checked and known)",
+		}, []string{"type"}),
+		dependencySetSize: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "dependencySetSize",
+			Help:      "number of chains in the dependency set",
+		}),
+		dependency: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "dependency",
+			Help:      "1 if the observed chain is in the dependency set, 0 otherwise",
+		}, []string{"chainId"}),
+		dependencyChanges: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "dependencyChanges",
+			Help:      "number of changes of the dependency set by kind (added, removed)",
+		}, []string{"chainId", "kind"}),
+		dependencyMismatch: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "dependencyMismatch",
+			Help:      "1 if the membership of the chain in the dependency set is not the expected one, 0 otherwise",
+		}, []string{"chainId"}),
+		unexpectedDependencies: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedDependencies",
+			Help:      "number of chains in the dependency set which are not expected",
+		}),
+		executingMessages: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "executingMessages",
+			Help:      "number of executing messages by source chain and check (valid, unknownChain, future, expired)",
+		}, []string{"chainId", "check"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestL2Height, err := m.l2Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestL2Height))
+
+	if m.nextL2Height <= latestL2Height {
+		toBlock := min(latestL2Height, m.nextL2Height+m.eventBlockRange-1)
+		if err := m.processLogs(ctx, m.nextL2Height, toBlock); err != nil {
+			m.log.Error("failed to process the interop events", "from", m.nextL2Height, "to", toBlock, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l2", "filterLogs").Inc()
+			return
+		}
+		m.highestBlockNumber.WithLabelValues("checked").Set(float64(toBlock))
+		m.nextL2Height = toBlock + 1
+	}
+
+	if err := m.checkDependencySet(ctx, new(big.Int).SetUint64(latestL2Height)); err != nil {
+		m.log.Error("failed to check the dependency set", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("L1Block", "isInDependencySet").Inc()
+	}
+}
+
+// processLogs reports the dependency set changes and the executing messages between the two L2 blocks (inclusive).
+func (m *Monitor) processLogs(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	dependencyAdded := l1BlockInteropABI.Events["DependencyAdded"].ID
+	dependencyRemoved := l1BlockInteropABI.Events["DependencyRemoved"].ID
+	executingMessage := crossL2InboxABI.Events["ExecutingMessage"].ID
+
+	logs, err := m.l2Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{m.l1BlockAddress, m.crossL2InboxAddress},
+		Topics:    [][]common.Hash{{dependencyAdded, dependencyRemoved, executingMessage}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the interop events: %w", err)
+	}
+
+	timestamps := make(map[uint64]uint64)
+	memberships := make(map[membershipKey]bool)
+	for _, vLog := range logs {
+		switch {
+		case vLog.Address == m.l1BlockAddress && (vLog.Topics[0] == dependencyAdded || vLog.Topics[0] == dependencyRemoved):
+			if len(vLog.Topics) != 2 {
+				return fmt.Errorf("unexpected dependency change %s", vLog.TxHash)
+			}
+			chainID := new(big.Int).SetBytes(vLog.Topics[1].Bytes())
+			kind := "added"
+			if vLog.Topics[0] == dependencyRemoved {
+				kind = "removed"
+			}
+			m.dependencies[chainID.String()] = chainID
+			m.dependencyChanges.WithLabelValues(chainID.String(), kind).Inc()
+			m.log.Warn("dependency set changed", "chain_id", chainID, "kind", kind, "expected", m.expectedDependencies[chainID.String()], "l2_tx", vLog.TxHash, "l2_block", vLog.BlockNumber)
+			m.Emit(ctx, alert(DependencyChangedRule, "P1", chainID.String(), chainID.String(), fmt.Sprintf("chain %s %s to the dependency set in the transaction %s", chainID, kind, vLog.TxHash)))
+
+		case vLog.Address == m.crossL2InboxAddress && vLog.Topics[0] == executingMessage:
+			if err := m.processExecutingMessage(ctx, vLog, timestamps, memberships); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// processExecutingMessage checks the source chain and the validity window of the initiating message referenced by the executing message.
+func (m *Monitor) processExecutingMessage(ctx context.Context, vLog types.Log, timestamps map[uint64]uint64, memberships map[membershipKey]bool) error {
+	var event struct {
+		Id Identifier
+	}
+	if len(vLog.Topics) != 2 {
+		return fmt.Errorf("unexpected executing message %s", vLog.TxHash)
+	}
+	if err := crossL2InboxABI.UnpackIntoInterface(&event, "ExecutingMessage", vLog.Data); err != nil {
+		return fmt.Errorf("failed to parse the executing message %s: %w", vLog.TxHash, err)
+	}
+	msgHash := vLog.Topics[1]
+	chainID := event.Id.ChainId.String()
+
+	timestamp, ok := timestamps[vLog.BlockNumber]
+	if !ok {
+		header, err := m.l2Client.HeaderByNumber(ctx, new(big.Int).SetUint64(vLog.BlockNumber))
+		if err != nil {
+			return fmt.Errorf("failed to query the header of the block %d: %w", vLog.BlockNumber, err)
+		}
+		timestamp = header.Time
+		timestamps[vLog.BlockNumber] = timestamp
+	}
+
+	key := membershipKey{chainID: chainID, block: vLog.BlockNumber}
+	inDependencySet, ok := memberships[key]
+	if !ok {
+		var err error
+		if inDependencySet, err = m.isInDependencySet(ctx, event.Id.ChainId, new(big.Int).SetUint64(vLog.BlockNumber)); err != nil {
+			return fmt.Errorf("failed to query the membership of the chain %s: %w", chainID, err)
+		}
+		memberships[key] = inDependencySet
+	}
+
+	check := messageCheck(inDependencySet, event.Id.Timestamp, timestamp, m.expiryWindow)
+	m.executingMessages.WithLabelValues(chainID, check).Inc()
+	if check != "valid" {
+		m.log.Error("invalid executing message", "check", check, "chain_id", chainID, "origin", event.Id.Origin, "block_number", event.Id.BlockNumber, "log_index", event.Id.LogIndex, "timestamp", event.Id.Timestamp, "msg_hash", msgHash, "l2_tx", vLog.TxHash, "l2_block", vLog.BlockNumber)
+		m.Emit(ctx, alert(InvalidMessageRule, "P0", msgHash.String(), chainID, fmt.Sprintf("executing message %s of the transaction %s invalid (%s)", msgHash, vLog.TxHash, check)))
+	}
+	return nil
+}
+
+// checkDependencySet reports the size of the dependency set and the membership of the observed chains at the block.
+func (m *Monitor) checkDependencySet(ctx context.Context, blockNumber *big.Int) error {
+	size, err := m.readDependencySetSize(ctx, blockNumber)
+	if err != nil {
+		return fmt.Errorf("failed to query the dependency set size: %w", err)
+	}
+	m.dependencySetSize.Set(float64(size))
+
+	expectedMembers := 0
+	for _, chainID := range m.sortedDependencies() {
+		label := chainID.String()
+		inDependencySet, err := m.isInDependencySet(ctx, chainID, blockNumber)
+		if err != nil {
+			return fmt.Errorf("failed to query the membership of the chain %s: %w", label, err)
+		}
+		m.dependency.WithLabelValues(label).Set(util.BoolToFloat(inDependencySet))
+		if len(m.expectedDependencies) == 0 {
+			continue
+		}
+
+		expected := m.expectedDependencies[label]
+		if inDependencySet && expected {
+			expectedMembers++
+		}
+		mismatch := inDependencySet != expected
+		if mismatch {
+			m.log.Error("unexpected membership in the dependency set", "chain_id", label, "in_dependency_set", inDependencySet, "expected", expected)
+		}
+		m.dependencyMismatch.WithLabelValues(label).Set(util.BoolToFloat(mismatch))
+		m.Update(ctx, alert(DependencyMismatchRule, "P1", label, label, fmt.Sprintf("chain %s in the dependency set: %t, expected: %t", label, inDependencySet, expected)), mismatch)
+	}
+
+	// the set can't be enumerated, the chains added before the starting height are only accounted from its size.
+	if len(m.expectedDependencies) > 0 {
+		m.unexpectedDependencies.Set(float64(max(int(size)-expectedMembers, 0)))
+	}
+	return nil
+}
+
+// alert returns the alert of the rule about the entity, labeled by the chain id.
+func alert(rule string, priority string, entity string, chainID string, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   entity,
+		Summary:  summary,
+		Labels:   map[string]string{"chain_id": chainID},
+	}
+}
+
+func (m *Monitor) readDependencySetSize(ctx context.Context, blockNumber *big.Int) (uint8, error) {
+	out, err := m.call(ctx, blockNumber, "dependencySetSize")
+	if err != nil {
+		return 0, err
+	}
+	return out.(uint8), nil
+}
+
+func (m *Monitor) isInDependencySet(ctx context.Context, chainID *big.Int, blockNumber *big.Int) (bool, error) {
+	out, err := m.call(ctx, blockNumber, "isInDependencySet", chainID)
+	if err != nil {
+		return false, err
+	}
+	return out.(bool), nil
+}
+
+// call calls a single-output method of the L1Block at the block.
+func (m *Monitor) call(ctx context.Context, blockNumber *big.Int, method string, args ...interface{}) (interface{}, error) {
+	data, err := l1BlockInteropABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	out, err := m.l2Client.CallContract(ctx, ethereum.CallMsg{To: &m.l1BlockAddress, Data: data}, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	unpacked, err := l1BlockInteropABI.Unpack(method, out)
+	if err != nil {
+		return nil, err
+	}
+	if len(unpacked) != 1 {
+		return nil, fmt.Errorf("unexpected output of %s", method)
+	}
+	return unpacked[0], nil
+}
+
+// sortedDependencies returns the observed chains in ascending order.
+func (m *Monitor) sortedDependencies() []*big.Int {
+	sorted := make([]*big.Int, 0, len(m.dependencies))
+	for _, chainID := range m.dependencies {
+		sorted = append(sorted, chainID)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	return sorted
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l2Client.Close()
+	return nil
+}
+
+// messageCheck returns the check of an executing message: valid, unknownChain when the source chain is not in the dependency set,
+// future when the initiating message is after the executing block and expired when it is older than the expiry window.
+func messageCheck(inDependencySet bool, initTimestamp *big.Int, execTimestamp uint64, expiryWindow time.Duration) string {
+	switch {
+	case !inDependencySet:
+		return "unknownChain"
+	case !initTimestamp.IsUint64() || initTimestamp.Uint64() > execTimestamp:
+		return "future"
+	case initTimestamp.Uint64()+uint64(expiryWindow.Seconds()) < execTimestamp:
+		return "expired"
+	default:
+		return "valid"
+	}
+}
@@ -0,0 +1,176 @@
+package interop
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMessageCheck(t *testing.T) {
+	expiryWindow := 7 * 24 * time.Hour
+	execTimestamp := uint64(1_000_000)
+
+	tests := []struct {
+		name            string
+		inDependencySet bool
+		initTimestamp   *big.Int
+		expected        string
+	}{
+		{name: "valid message", inDependencySet: true, initTimestamp: big.NewInt(999_000), expected: "valid"},
+		{name: "same block message", inDependencySet: true, initTimestamp: big.NewInt(1_000_000), expected: "valid"},
+		{name: "end of the expiry window", inDependencySet: true, initTimestamp: big.NewInt(1_000_000 - 604_800), expected: "valid"},
+		{name: "expired message", inDependencySet: true, initTimestamp: big.NewInt(1_000_000 - 604_801), expected: "expired"},
+		{name: "future message", inDependencySet: true, initTimestamp: big.NewInt(1_000_001), expected: "future"},
+		{name: "overflowing timestamp", inDependencySet: true, initTimestamp: new(big.Int).Lsh(big.NewInt(1), 64), expected: "future"},
+		{name: "unknown chain", inDependencySet: false, initTimestamp: big.NewInt(999_000), expected: "unknownChain"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := messageCheck(test.inDependencySet, test.initTimestamp, execTimestamp, expiryWindow); got != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+var (
+	l1Block      = common.HexToAddress("0x4200000000000000000000000000000000000015")
+	crossL2Inbox = common.HexToAddress("0x4200000000000000000000000000000000000022")
+)
+
+// interopNode is an L2 node whose L1Block serves the members of the dependency set.
+type interopNode struct {
+	*fake.Node
+
+	dependencySet map[uint64]bool
+}
+
+func newInteropNode(t *testing.T, dependencySet ...uint64) *interopNode {
+	n := &interopNode{Node: fake.NewNode(t), dependencySet: make(map[uint64]bool)}
+	for _, chainID := range dependencySet {
+		n.dependencySet[chainID] = true
+	}
+	n.AddBlock(&types.Header{Time: 1000})
+	n.Contract(l1Block, l1BlockInteropABI).
+		Handle("dependencySetSize", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{uint8(len(n.dependencySet))}, nil
+		}).
+		Handle("isInDependencySet", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{n.dependencySet[args[0].(*big.Int).Uint64()]}, nil
+		})
+	return n
+}
+
+// addDependencyChange adds a block changing the membership of the chain in the dependency set.
+func (n *interopNode) addDependencyChange(chainID uint64, added bool) {
+	event := l1BlockInteropABI.Events["DependencyRemoved"]
+	if added {
+		event = l1BlockInteropABI.Events["DependencyAdded"]
+	}
+	n.dependencySet[chainID] = added
+	header := n.AddBlock(&types.Header{Time: 1000})
+	n.AddLogs(types.Log{Address: l1Block, Topics: []common.Hash{event.ID, common.BigToHash(new(big.Int).SetUint64(chainID))}, BlockNumber: header.Number.Uint64(), TxHash: common.HexToHash("0x01")})
+}
+
+// addExecutingMessage adds a block at the timestamp executing a message of the chain initiated at the initiating timestamp.
+func (n *interopNode) addExecutingMessage(t *testing.T, msgHash common.Hash, chainID uint64, initTimestamp uint64, timestamp uint64) {
+	event := crossL2InboxABI.Events["ExecutingMessage"]
+	data, err := event.Inputs.NonIndexed().Pack(Identifier{Origin: common.HexToAddress("0x02"), BlockNumber: big.NewInt(1), LogIndex: big.NewInt(0), Timestamp: new(big.Int).SetUint64(initTimestamp), ChainId: new(big.Int).SetUint64(chainID)})
+	if err != nil {
+		t.Fatalf("failed to pack the executing message: %v", err)
+	}
+	header := n.AddBlock(&types.Header{Time: timestamp})
+	n.AddLogs(types.Log{Address: crossL2Inbox, Topics: []common.Hash{event.ID, msgHash}, Data: data, BlockNumber: header.Number.Uint64(), TxHash: common.HexToHash("0x03")})
+}
+
+// newTestMonitor returns the monitor of the node from the block 1 expecting the dependency set, with an expiry window of
+// 100s, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *interopNode, dependencySet ...uint64) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{L2NodeURL: node.URL, L1BlockAddress: l1Block, CrossL2InboxAddress: crossL2Inbox, DependencySet: dependencySet, ExpiryWindow: 100 * time.Second, StartBlockHeight: 1, EventBlockRange: 100}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := newInteropNode(t, 10)
+	m, _ := newTestMonitor(t, node, 10)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if size, member, unexpected := testutil.ToFloat64(m.dependencySetSize), testutil.ToFloat64(m.dependency.WithLabelValues("10")), testutil.ToFloat64(m.unexpectedDependencies); size != 1 || member != 1 || unexpected != 0 {
+		t.Errorf("expected the chain 10 as the only dependency but got %v, %v and %v", size, member, unexpected)
+	}
+
+	// an unexpected chain added is counted and reported as a mismatch.
+	node.addDependencyChange(8453, true)
+	m.Run(ctx)
+	if changes, mismatch, unexpected := testutil.ToFloat64(m.dependencyChanges.WithLabelValues("8453", "added")), testutil.ToFloat64(m.dependencyMismatch.WithLabelValues("8453")), testutil.ToFloat64(m.unexpectedDependencies); changes != 1 || mismatch != 1 || unexpected != 1 {
+		t.Errorf("expected 1 unexpected chain added but got %v, %v and %v", changes, mismatch, unexpected)
+	}
+	if checked := testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("checked")); checked != 1 {
+		t.Errorf("expected the block 1 checked but got %v", checked)
+	}
+
+	// the executing messages are counted by chain and check.
+	node.addExecutingMessage(t, common.HexToHash("0x10"), 10, 1900, 2000)
+	node.addExecutingMessage(t, common.HexToHash("0x11"), 10, 1800, 2000)
+	m.Run(ctx)
+	if valid, expired := testutil.ToFloat64(m.executingMessages.WithLabelValues("10", "valid")), testutil.ToFloat64(m.executingMessages.WithLabelValues("10", "expired")); valid != 1 || expired != 1 {
+		t.Errorf("expected 1 valid and 1 expired message but got %v and %v", valid, expired)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newInteropNode(t, 10)
+	m, notifier := newTestMonitor(t, node, 10)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// an unexpected chain added fires the change once and the mismatch until removed.
+	node.addDependencyChange(8453, true)
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(DependencyChangedRule); len(alerts) != 1 || alerts[0].Entity != "8453" || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the dependency change alert once but got %v", alerts)
+	}
+	if alerts := notifier.Alerts(DependencyMismatchRule); len(alerts) != 1 || alerts[0].Labels["chain_id"] != "8453" {
+		t.Fatalf("expected the mismatch alert once but got %v", alerts)
+	}
+	node.addDependencyChange(8453, false)
+	m.Run(ctx)
+	if firing := notifier.Firing(DependencyMismatchRule); len(firing) != 0 {
+		t.Fatalf("expected the mismatch alert resolved but got %v", firing)
+	}
+
+	// the messages of an unknown chain or out of the expiry window are invalid.
+	node.addExecutingMessage(t, common.HexToHash("0x10"), 10, 1900, 2000)
+	node.addExecutingMessage(t, common.HexToHash("0x11"), 10, 1800, 2000)
+	node.addExecutingMessage(t, common.HexToHash("0x12"), 8453, 1950, 2000)
+	m.Run(ctx)
+	alerts := notifier.Alerts(InvalidMessageRule)
+	if len(alerts) != 2 || alerts[0].Entity != common.HexToHash("0x11").String() || alerts[1].Labels["chain_id"] != "8453" || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the invalid message alerts of the expired and the unknown chain messages but got %v", alerts)
+	}
+}
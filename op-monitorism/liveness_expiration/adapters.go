@@ -0,0 +1,113 @@
+package liveness_expiration
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// LivenessModule2ABI is the subset of the LivenessModule v2 used by the monitor.
+	// The v2 is a singleton shared by the safes, the fallback owner challenges a safe which has to respond within the response period.
+	LivenessModule2ABI = `[{"inputs":[{"name":"","type":"address"}],"name":"livenessSafeConfiguration","outputs":[{"name":"livenessResponsePeriod","type":"uint256"},{"name":"fallbackOwner","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"","type":"address"}],"name":"challengeStartTime","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"version","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"}]`
+)
+
+var (
+	livenessModule2ABI = util.MustParseJSONABI(LivenessModule2ABI)
+)
+
+// livenessModuleAdapter reads the state of a version of the LivenessModule through the multicall.
+type livenessModuleAdapter interface {
+	// version is the version reported by the module.
+	version() string
+	// requests returns the calls to add to the multicall of the safe.
+	requests(safe *safeTarget) []multicallRequest
+	// decode fills the snapshot with the outputs of the calls returned by `requests`.
+	decode(outputs [][]interface{}, snapshot *safeSnapshot)
+}
+
+// livenessModuleV1 is the LivenessModule v1, installed on a single safe and removing the owners that are not live anymore.
+type livenessModuleV1 struct {
+	moduleVersion string
+}
+
+func (a *livenessModuleV1) version() string { return a.moduleVersion }
+
+func (a *livenessModuleV1) requests(safe *safeTarget) []multicallRequest {
+	return []multicallRequest{
+		{target: safe.LivenessModuleAddress, abi: livenessModuleABI, method: "livenessInterval"},
+		{target: safe.LivenessModuleAddress, abi: livenessModuleABI, method: "minOwners"},
+		{target: safe.LivenessModuleAddress, abi: livenessModuleABI, method: "fallbackOwner"},
+		{target: safe.LivenessModuleAddress, abi: livenessModuleABI, method: "ownershipTransferredToFallback"},
+	}
+}
+
+func (a *livenessModuleV1) decode(outputs [][]interface{}, snapshot *safeSnapshot) {
+	snapshot.interval = *abi.ConvertType(outputs[0][0], new(*big.Int)).(**big.Int)
+	snapshot.minOwners = *abi.ConvertType(outputs[1][0], new(*big.Int)).(**big.Int)
+	snapshot.fallback = *abi.ConvertType(outputs[2][0], new(common.Address)).(*common.Address)
+	snapshot.transferredToFallback = *abi.ConvertType(outputs[3][0], new(bool)).(*bool)
+}
+
+// livenessModuleV2 is the LivenessModule v2, shared by the safes and based on challenges started by the fallback owner.
+type livenessModuleV2 struct {
+	moduleVersion string
+}
+
+func (a *livenessModuleV2) version() string { return a.moduleVersion }
+
+func (a *livenessModuleV2) requests(safe *safeTarget) []multicallRequest {
+	return []multicallRequest{
+		{target: safe.LivenessModuleAddress, abi: livenessModule2ABI, method: "livenessSafeConfiguration", args: []interface{}{safe.GnosisSafeAddress}},
+		{target: safe.LivenessModuleAddress, abi: livenessModule2ABI, method: "challengeStartTime", args: []interface{}{safe.GnosisSafeAddress}},
+	}
+}
+
+func (a *livenessModuleV2) decode(outputs [][]interface{}, snapshot *safeSnapshot) {
+	snapshot.interval = *abi.ConvertType(outputs[0][0], new(*big.Int)).(**big.Int)
+	snapshot.fallback = *abi.ConvertType(outputs[0][1], new(common.Address)).(*common.Address)
+	snapshot.challengeStartTime = *abi.ConvertType(outputs[1][0], new(*big.Int)).(**big.Int)
+}
+
+// detectLivenessModuleAdapter returns the adapter matching the major version reported by `version()`.
+// The modules without `version()`, reverting or returning no data, are considered as v1. Any other failure of the call
+// is returned, a module failing to answer not being bound to the v1 adapter for good. The version is read at the block
+// number, `nil` for the latest block, so the historical mode decodes the module installed at the pinned block.
+func detectLivenessModuleAdapter(ctx context.Context, l1Client bind.ContractCaller, module common.Address, blockNumber *big.Int) (livenessModuleAdapter, error) {
+	data, err := livenessModule2ABI.Pack("version")
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack version: %w", err)
+	}
+	output, err := l1Client.CallContract(ctx, ethereum.CallMsg{To: &module, Data: data}, blockNumber)
+	if err != nil {
+		if util.IsRevert(err) {
+			return &livenessModuleV1{moduleVersion: "unknown"}, nil
+		}
+		return nil, fmt.Errorf("failed to call version: %w", err)
+	}
+	if len(output) == 0 {
+		return &livenessModuleV1{moduleVersion: "unknown"}, nil
+	}
+	out, err := livenessModule2ABI.Unpack("version", output)
+	if err != nil || len(out) != 1 {
+		return nil, fmt.Errorf("failed to unpack version: %w", err)
+	}
+
+	version := *abi.ConvertType(out[0], new(string)).(*string)
+	switch {
+	case strings.HasPrefix(version, "1."):
+		return &livenessModuleV1{moduleVersion: version}, nil
+	case strings.HasPrefix(version, "2."):
+		return &livenessModuleV2{moduleVersion: version}, nil
+	default:
+		return nil, fmt.Errorf("unsupported LivenessModule version %q", version)
+	}
+}
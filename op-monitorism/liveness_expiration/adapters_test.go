@@ -0,0 +1,144 @@
+package liveness_expiration
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// versionCaller answers the calls with the output or the error, recording the block number of the last call.
+type versionCaller struct {
+	output []byte
+	err    error
+
+	blockNumber *big.Int
+}
+
+func (c *versionCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x01}, nil
+}
+
+func (c *versionCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.blockNumber = blockNumber
+	return c.output, c.err
+}
+
+func TestDetectLivenessModuleAdapter(t *testing.T) {
+	encodeVersion := func(version string) []byte {
+		output, err := livenessModule2ABI.Methods["version"].Outputs.Pack(version)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		return output
+	}
+
+	tests := []struct {
+		name     string
+		caller   *versionCaller
+		expected string // version of the adapter, empty when an error is expected.
+	}{
+		{name: "v1", caller: &versionCaller{output: encodeVersion("1.2.0")}, expected: "1.2.0"},
+		{name: "v2", caller: &versionCaller{output: encodeVersion("2.0.0")}, expected: "2.0.0"},
+		{name: "No version reverting", caller: &versionCaller{err: errors.New("execution reverted")}, expected: "unknown"},
+		{name: "No version returning no data", caller: &versionCaller{}, expected: "unknown"},
+		{name: "RPC failure", caller: &versionCaller{err: errors.New("503 Service Unavailable")}},
+		{name: "Timeout", caller: &versionCaller{err: context.DeadlineExceeded}},
+		{name: "Unsupported version", caller: &versionCaller{output: encodeVersion("3.0.0")}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			adapter, err := detectLivenessModuleAdapter(context.Background(), test.caller, common.HexToAddress("0x01"), nil)
+			if len(test.expected) == 0 {
+				if err == nil {
+					t.Errorf("Failed %s: expected an error but got the adapter %s", test.name, adapter.version())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed %s: unexpected error %v", test.name, err)
+			}
+			if adapter.version() != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, adapter.version())
+			}
+		})
+	}
+}
+
+func TestDetectLivenessModuleAdapterAtBlock(t *testing.T) {
+	output, err := livenessModule2ABI.Methods["version"].Outputs.Pack("2.0.0")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	caller := &versionCaller{output: output}
+
+	// the version is read at the pinned block.
+	if _, err := detectLivenessModuleAdapter(context.Background(), caller, common.HexToAddress("0x01"), big.NewInt(100)); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if caller.blockNumber == nil || caller.blockNumber.Uint64() != 100 {
+		t.Errorf("expected the version read at the block 100 but got %v", caller.blockNumber)
+	}
+
+	// the version is read at the latest block otherwise.
+	if _, err := detectLivenessModuleAdapter(context.Background(), caller, common.HexToAddress("0x01"), nil); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if caller.blockNumber != nil {
+		t.Errorf("expected the version read at the latest block but got %v", caller.blockNumber)
+	}
+}
+
+func TestRunLivenessModuleV2(t *testing.T) {
+	day := uint64(86400)
+	now := 100 * day
+	tests := []struct {
+		name               string
+		challengeStartTime uint64
+		expectedActive     float64
+		expectedEnd        float64
+		expectedStatus     Status
+	}{
+		{name: "Safe not challenged", challengeStartTime: 0, expectedStatus: StatusOK},
+		{name: "Challenge within the response period", challengeStartTime: now - day, expectedActive: 1, expectedEnd: float64(now + 6*day), expectedStatus: StatusAtRisk},
+		{name: "Challenge past the response period", challengeStartTime: now - 8*day, expectedActive: 1, expectedEnd: float64(now - day), expectedStatus: StatusExpired},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := newSafeNode(t, now)
+			// the module is a LivenessModule v2 with a response period of 7 days.
+			node.Contract(testModule, livenessModule2ABI).
+				Returns("version", "2.0.0").
+				Returns("livenessSafeConfiguration", new(big.Int).SetUint64(7*day), common.HexToAddress("0x0F")).
+				Returns("challengeStartTime", new(big.Int).SetUint64(test.challengeStartTime))
+			m, notifier := newTestMonitor(t, node)
+			chain := m.chains[0]
+
+			status, err := m.RunOnce(context.Background())
+			if err != nil {
+				t.Fatalf("Failed %s: unexpected error %v", test.name, err)
+			}
+			if version := chain.safes[0].adapter.version(); version != "2.0.0" {
+				t.Errorf("Failed %s: expected the version 2.0.0 but got %s", test.name, version)
+			}
+			if status != test.expectedStatus {
+				t.Errorf("Failed %s: expected the status %d but got %d", test.name, test.expectedStatus, status)
+			}
+			if active := testutil.ToFloat64(chain.challengeActive.WithLabelValues(testSafe.String())); active != test.expectedActive {
+				t.Errorf("Failed %s: expected the challenge active %v but got %v", test.name, test.expectedActive, active)
+			}
+			if end := testutil.ToFloat64(chain.challengePeriodEnd.WithLabelValues(testSafe.String())); end != test.expectedEnd {
+				t.Errorf("Failed %s: expected the challenge period end %v but got %v", test.name, test.expectedEnd, end)
+			}
+			if firing := notifier.Firing(ChallengeActiveRule); float64(len(firing)) != test.expectedActive {
+				t.Errorf("Failed %s: expected %v challenge alerts firing but got %v", test.name, test.expectedActive, firing)
+			}
+		})
+	}
+}
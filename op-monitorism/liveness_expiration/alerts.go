@@ -0,0 +1,59 @@
+package liveness_expiration
+
+import (
+	"strings"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	MonitorName = "liveness_expiration"
+
+	// Rules of the alerts emitted by the monitor, resolved once their condition clears.
+	OwnerAtRiskRule          = "owner at risk"
+	ThresholdUnreachableRule = "threshold unreachable"
+	ShutdownImminentRule     = "shutdown imminent"
+	FallbackOwnershipRule    = "ownership transferred to the fallback owner"
+	ChallengeActiveRule      = "challenge active"
+	ContractNotInstalledRule = "contract not installed"
+	CodeHashMismatchRule     = "codehash mismatch"
+	UnexpectedModuleRule     = "unexpected module"
+	UnexpectedOwnerRule      = "owner not in the roster"
+	MissingOwnerRule         = "roster member not an owner"
+	StuckApprovalRule        = "approved hash not executed"
+	StuckFullySignedRule     = "fully signed transaction not executed"
+
+	// ExpiringRule is the rule of the notifications sent when the remaining time of an owner reaches an escalation
+	// level, with the severity of the level as priority.
+	ExpiringRule = "liveness expiring"
+
+	// Rules of the one-off alerts emitted on the changes of the safe.
+	OwnerAddedRule           = "owner added"
+	OwnerRemovedRule         = "owner removed"
+	ThresholdChangedRule     = "threshold changed"
+	IntervalChangedRule      = "liveness interval changed"
+	FallbackOwnerChangedRule = "fallback owner changed"
+)
+
+// alert returns the alert of the rule about the safe, or about the addresses of the safe (e.g. an owner) when given.
+// The entity is prefixed by the safe so that the alerts of an owner of several safes are distinct, and by the chain
+// when it isn't the default one, the safes being often deployed at the same address on several chains.
+func (m *chainMonitor) alert(rule string, priority string, safe *safeTarget, summary string, addresses ...common.Address) alerts.Alert {
+	entity := make([]string, 0, len(addresses)+2)
+	if m.name != DefaultChain {
+		entity = append(entity, m.name)
+	}
+	entity = append(entity, safe.GnosisSafeAddress.String())
+	for _, address := range addresses {
+		entity = append(entity, address.String())
+	}
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   strings.Join(entity, "/"),
+		Summary:  summary,
+		Labels:   map[string]string{"chain": m.name, "safe": safe.label},
+	}
+}
@@ -0,0 +1,103 @@
+package liveness_expiration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultChain is the name of the chain configured with `--l1.node.url`, used by the safes without `chain`.
+	DefaultChain = "l1"
+)
+
+// ChainConfig is a chain where safes are monitored.
+type ChainConfig struct {
+	Name    string `yaml:"name"`
+	NodeURL string `yaml:"rpc"`
+
+	// SafeTxServiceURL is the url of the Safe Transaction Service of the chain, empty to disable the pending transactions.
+	SafeTxServiceURL string `yaml:"safeTxServiceUrl"`
+}
+
+// chainFactory adds a constant `chain` label to every metric created.
+// The monitors of the chains share the same registry, the metrics are distinguished by this label.
+type chainFactory struct {
+	metrics.Factory
+	chain string
+}
+
+func (f *chainFactory) NewCounter(opts prometheus.CounterOpts) prometheus.Counter {
+	opts.ConstLabels = prometheus.Labels{"chain": f.chain}
+	return f.Factory.NewCounter(opts)
+}
+
+func (f *chainFactory) NewGauge(opts prometheus.GaugeOpts) prometheus.Gauge {
+	opts.ConstLabels = prometheus.Labels{"chain": f.chain}
+	return f.Factory.NewGauge(opts)
+}
+
+func (f *chainFactory) NewCounterVec(opts prometheus.CounterOpts, labelNames []string) *prometheus.CounterVec {
+	opts.ConstLabels = prometheus.Labels{"chain": f.chain}
+	return f.Factory.NewCounterVec(opts, labelNames)
+}
+
+func (f *chainFactory) NewGaugeVec(opts prometheus.GaugeOpts, labelNames []string) *prometheus.GaugeVec {
+	opts.ConstLabels = prometheus.Labels{"chain": f.chain}
+	return f.Factory.NewGaugeVec(opts, labelNames)
+}
+
+// Monitor monitors the liveness of the safes of every chain configured.
+type Monitor struct {
+	// Tracker emits the alerts of every chain.
+	alerts.Tracker
+
+	log    log.Logger
+	chains []*chainMonitor
+}
+
+// NewMonitor creates a new monitor.
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("Starting the liveness expiration monitoring...")
+
+	chains := append([]ChainConfig{{Name: DefaultChain, NodeURL: cfg.L1NodeURL, SafeTxServiceURL: cfg.SafeTxServiceURL}}, cfg.Chains...)
+	if len(chains) > 1 && cfg.Block != 0 {
+		return nil, errors.New("the historical mode can't be used with several chains, the block numbers are specific to a chain")
+	}
+
+	monitor := &Monitor{log: log}
+	for _, chain := range chains {
+		if !cfg.hasSafesOn(chain.Name) {
+			continue
+		}
+		chainMonitor, err := newChainMonitor(ctx, log.New("chain", chain.Name), &chainFactory{Factory: m, chain: chain.Name}, cfg, chain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the monitor of the chain %s: %w", chain.Name, err)
+		}
+		chainMonitor.tracker = &monitor.Tracker
+		monitor.chains = append(monitor.chains, chainMonitor)
+	}
+	return monitor, nil
+}
+
+// Run evaluates the safes of every chain.
+func (m *Monitor) Run(ctx context.Context) {
+	for _, chain := range m.chains {
+		chain.Run(ctx)
+	}
+}
+
+// Close closes the clients of every chain.
+func (m *Monitor) Close(ctx context.Context) error {
+	for _, chain := range m.chains {
+		if err := chain.Close(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,8 +1,14 @@
 package liveness_expiration
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 
 	"github.com/urfave/cli/v2"
@@ -13,9 +19,24 @@ const (
 	EventBlockRangeFlagName       = "event.block.range"
 	StartingL1BlockHeightFlagName = "start.block.height"
 
-	SafeAddressFlagName           = "safe.address"
-	LivenessModuleAddressFlagName = "livenessmodule.address"
-	LivenessGuardAddressFlagName  = "livenessguard.address"
+	SafeAddressFlagName            = "safe.address"
+	LivenessModuleAddressFlagName  = "livenessmodule.address"
+	LivenessGuardAddressFlagName   = "livenessguard.address"
+	SafesFlagName                  = "safes"
+	SafesConfigFlagName            = "safes.config"
+	ChainsFlagName                 = "chains"
+	NestedDepthFlagName            = "nested.depth"
+	Multicall3AddressFlagName      = "multicall3.address"
+	BlockFlagName                  = "block"
+	OneShotFlagName                = "one-shot"
+	AddressBookFlagName            = "address.book"
+	ENSFlagName                    = "ens"
+	SafeTxServiceURLFlagName       = "safe.tx.service.url"
+	StuckExecutionDurationFlagName = "stuck.execution.duration"
+	NotifyWebhookURLFlagName       = "notify.webhook.url"
+	NotifySlackURLFlagName         = "notify.slack.url"
+	NotifyThresholdsFlagName       = "notify.thresholds"
+	BufferFlagName                 = "buffer"
 )
 
 type CLIConfig struct {
@@ -23,24 +44,149 @@ type CLIConfig struct {
 	EventBlockRange       uint64
 	StartingL1BlockHeight uint64
 
-	LivenessModuleAddress common.Address
-	LivenessGuardAddress  common.Address
-	SafeAddress           common.Address
+	// Safes is the list of (safe, guard, module) monitored by the same instance.
+	Safes []SafeConfig
+
+	// Multicall3Address is the Multicall3 used to read the state of the safes in a single call.
+	Multicall3Address common.Address
+
+	// Chains are the chains in addition to the default one (`L1NodeURL`), the safes select their chain by name.
+	Chains []ChainConfig
+
+	// NestedDepth is the depth of the owners that are safes resolved, 0 disables the nested safes.
+	NestedDepth uint64
+
+	// Block pins every query to a historical block to reconstruct the liveness state at that time, 0 follows the latest block.
+	Block uint64
+
+	// AddressBook is the path to a YAML file with the names of the owners, formatted as `address: name`.
+	AddressBook string
+	// ENS resolves the names of the owners that are not in the address book through their ENS reverse record.
+	ENS bool
+
+	// SafeTxServiceURL is the url of the Safe Transaction Service used to export the pending transactions, empty to disable.
+	SafeTxServiceURL string
+
+	// StuckExecutionDuration is the duration after which an approved or fully signed transaction not executed is stuck.
+	StuckExecutionDuration time.Duration
+
+	// NotifyWebhookURL and NotifySlackURL are notified when the remaining time of an owner reaches a threshold, empty to disable.
+	NotifyWebhookURL string
+	NotifySlackURL   string
+	NotifyThresholds []NotifyThreshold
+
+	// OneShot performs a single evaluation and exits with the resulting `Status`.
+	OneShot bool
+
+	// Buffer is the warning window before an owner liveness expires.
+	Buffer time.Duration
 }
 
 func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
 	cfg := CLIConfig{
-		L1NodeURL:             ctx.String(L1NodeURLFlagName),
-		EventBlockRange:       ctx.Uint64(EventBlockRangeFlagName),
-		StartingL1BlockHeight: ctx.Uint64(StartingL1BlockHeightFlagName),
-		SafeAddress:           common.HexToAddress(ctx.String(SafeAddressFlagName)),
-		LivenessModuleAddress: common.HexToAddress(ctx.String(LivenessModuleAddressFlagName)),
-		LivenessGuardAddress:  common.HexToAddress(ctx.String(LivenessGuardAddressFlagName)),
+		L1NodeURL:              ctx.String(L1NodeURLFlagName),
+		EventBlockRange:        ctx.Uint64(EventBlockRangeFlagName),
+		StartingL1BlockHeight:  ctx.Uint64(StartingL1BlockHeightFlagName),
+		NestedDepth:            ctx.Uint64(NestedDepthFlagName),
+		Block:                  ctx.Uint64(BlockFlagName),
+		OneShot:                ctx.Bool(OneShotFlagName),
+		AddressBook:            ctx.String(AddressBookFlagName),
+		ENS:                    ctx.Bool(ENSFlagName),
+		SafeTxServiceURL:       ctx.String(SafeTxServiceURLFlagName),
+		StuckExecutionDuration: ctx.Duration(StuckExecutionDurationFlagName),
+		NotifyWebhookURL:       ctx.String(NotifyWebhookURLFlagName),
+		NotifySlackURL:         ctx.String(NotifySlackURLFlagName),
+		Buffer:                 ctx.Duration(BufferFlagName),
+	}
+
+	thresholds, err := ParseNotifyThresholds(ctx.StringSlice(NotifyThresholdsFlagName))
+	if err != nil {
+		return cfg, fmt.Errorf("failed to parse --%s: %w", NotifyThresholdsFlagName, err)
+	}
+	cfg.NotifyThresholds = thresholds
+
+	multicall3Address := ctx.String(Multicall3AddressFlagName)
+	if !common.IsHexAddress(multicall3Address) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", Multicall3AddressFlagName)
+	}
+	cfg.Multicall3Address = common.HexToAddress(multicall3Address)
+
+	// The single safe flags are kept so the existing deployments continue to work.
+	if ctx.IsSet(SafeAddressFlagName) || ctx.IsSet(LivenessGuardAddressFlagName) || ctx.IsSet(LivenessModuleAddressFlagName) {
+		safe, err := parseSafeConfig(ctx.String(SafeAddressFlagName), ctx.String(LivenessGuardAddressFlagName), ctx.String(LivenessModuleAddressFlagName))
+		if err != nil {
+			return cfg, fmt.Errorf("failed to parse --%s, --%s and --%s: %w", SafeAddressFlagName, LivenessGuardAddressFlagName, LivenessModuleAddressFlagName, err)
+		}
+		cfg.Safes = append(cfg.Safes, safe)
+	}
+
+	for _, triplet := range ctx.StringSlice(SafesFlagName) {
+		split := strings.Split(triplet, ":")
+		if len(split) != 3 {
+			return cfg, fmt.Errorf("failed to parse `safe:guard:module`: %s", triplet)
+		}
+
+		safe, err := parseSafeConfig(split[0], split[1], split[2])
+		if err != nil {
+			return cfg, fmt.Errorf("failed to parse `safe:guard:module` %s: %w", triplet, err)
+		}
+		cfg.Safes = append(cfg.Safes, safe)
+	}
+
+	for _, chain := range ctx.StringSlice(ChainsFlagName) {
+		name, url, err := util.ParseNamedValue(chain, "name=url")
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Chains = append(cfg.Chains, ChainConfig{Name: name, NodeURL: url})
+	}
+
+	if path := ctx.String(SafesConfigFlagName); len(path) > 0 {
+		config, err := ReadConfigFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Chains = append(cfg.Chains, config.Chains...)
+		cfg.Safes = append(cfg.Safes, config.Safes...)
+	}
+
+	if len(cfg.Safes) == 0 {
+		return cfg, fmt.Errorf("at least one safe must be configured with --%s, --%s or --%s", SafeAddressFlagName, SafesFlagName, SafesConfigFlagName)
+	}
+	if err := cfg.checkChains(); err != nil {
+		return cfg, err
 	}
 
 	return cfg, nil
 }
 
+// checkChains ensures the names of the chains are unique and every safe is on a configured chain.
+func (c CLIConfig) checkChains() error {
+	names := map[string]bool{DefaultChain: true}
+	for _, chain := range c.Chains {
+		if names[chain.Name] {
+			return fmt.Errorf("the chain %s is configured several times", chain.Name)
+		}
+		names[chain.Name] = true
+	}
+	for _, safe := range c.Safes {
+		if !names[safe.chainName()] {
+			return fmt.Errorf("the chain %s of the safe %s is not configured", safe.chainName(), safe.SafeAddress)
+		}
+	}
+	return nil
+}
+
+// hasSafesOn returns true when at least one safe is configured on the chain.
+func (c CLIConfig) hasSafesOn(chain string) bool {
+	for _, safe := range c.Safes {
+		if safe.chainName() == chain {
+			return true
+		}
+	}
+	return false
+}
+
 func CLIFlags(envVar string) []cli.Flag {
 	return []cli.Flag{
 		&cli.StringFlag{
@@ -49,29 +195,115 @@ func CLIFlags(envVar string) []cli.Flag {
 			Value:   "127.0.0.1:8545",
 			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
 		},
+		&cli.Uint64Flag{
+			Name:    EventBlockRangeFlagName,
+			Usage:   "Max block range when scanning for events",
+			Value:   1000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EVENT_BLOCK_RANGE"),
+		},
 		&cli.Uint64Flag{
 			Name:     StartingL1BlockHeightFlagName,
-			Usage:    "Starting height to scan for events (still not implemented for now.. The monitoring will start at the last block number)",
+			Usage:    "Starting height to scan for events (AddedOwner, RemovedOwner). When not set, the monitoring will start at the last block number",
 			EnvVars:  opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
 			Required: false,
 		},
 		&cli.StringFlag{
-			Name:     LivenessModuleAddressFlagName,
-			Usage:    "Address of the LivenessModuleAddress contract",
-			EnvVars:  opservice.PrefixEnvVar(envVar, "LIVENESS_MODULE_ADDRESS"),
-			Required: true,
+			Name:    LivenessModuleAddressFlagName,
+			Usage:   "Address of the LivenessModuleAddress contract",
+			EnvVars: opservice.PrefixEnvVar(envVar, "LIVENESS_MODULE_ADDRESS"),
 		},
 		&cli.StringFlag{
-			Name:     LivenessGuardAddressFlagName,
-			Usage:    "Address of the LivenessGuardAddress contract",
-			EnvVars:  opservice.PrefixEnvVar(envVar, "LIVENESS_GUARD_ADDRESS"),
-			Required: true,
+			Name:    LivenessGuardAddressFlagName,
+			Usage:   "Address of the LivenessGuardAddress contract",
+			EnvVars: opservice.PrefixEnvVar(envVar, "LIVENESS_GUARD_ADDRESS"),
 		},
 		&cli.StringFlag{
-			Name:     SafeAddressFlagName,
-			Usage:    "Address of the safe contract",
-			EnvVars:  opservice.PrefixEnvVar(envVar, "SAFE_ADDRESS"),
-			Required: true,
+			Name:    SafeAddressFlagName,
+			Usage:   "Address of the safe contract",
+			EnvVars: opservice.PrefixEnvVar(envVar, "SAFE_ADDRESS"),
+		},
+		&cli.StringSliceFlag{
+			Name:    SafesFlagName,
+			Usage:   "One or more safes formatted via `safe:guard:module`",
+			EnvVars: opservice.PrefixEnvVar(envVar, "SAFES"),
+		},
+		&cli.StringFlag{
+			Name:    SafesConfigFlagName,
+			Usage:   "Path to a YAML file containing the list of safes to monitor",
+			EnvVars: opservice.PrefixEnvVar(envVar, "SAFES_CONFIG"),
+		},
+		&cli.StringSliceFlag{
+			Name:    ChainsFlagName,
+			Usage:   "Additional chains formatted via name=url, the safes of the YAML file select their chain by name (the chain of --l1.node.url is named l1)",
+			EnvVars: opservice.PrefixEnvVar(envVar, "CHAINS"),
+		},
+		&cli.StringFlag{
+			Name:    Multicall3AddressFlagName,
+			Usage:   "Address of the Multicall3 contract used to batch the calls",
+			Value:   predeploys.MultiCall3,
+			EnvVars: opservice.PrefixEnvVar(envVar, "MULTICALL3_ADDRESS"),
+		},
+		&cli.Uint64Flag{
+			Name:    NestedDepthFlagName,
+			Usage:   "Depth of the owners that are safes to resolve and monitor (e.g. 1 for a 2-of-2 structure), 0 to disable",
+			Value:   0,
+			EnvVars: opservice.PrefixEnvVar(envVar, "NESTED_DEPTH"),
+		},
+		&cli.Uint64Flag{
+			Name:    BlockFlagName,
+			Usage:   "Historical block number every query is pinned to (e.g. to reconstruct the state at the time of an incident), 0 to follow the latest block",
+			Value:   0,
+			EnvVars: opservice.PrefixEnvVar(envVar, "BLOCK"),
+		},
+		&cli.StringFlag{
+			Name:    AddressBookFlagName,
+			Usage:   "Path to a YAML file with the names of the owners formatted as address: name, added as the name label of the owner metrics",
+			EnvVars: opservice.PrefixEnvVar(envVar, "ADDRESS_BOOK"),
+		},
+		&cli.BoolFlag{
+			Name:    ENSFlagName,
+			Usage:   "Resolve the names of the owners that are not in the address book through their ENS reverse record (Ethereum mainnet only)",
+			Value:   false,
+			EnvVars: opservice.PrefixEnvVar(envVar, "ENS"),
+		},
+		&cli.StringFlag{
+			Name:    SafeTxServiceURLFlagName,
+			Usage:   "URL of the Safe Transaction Service used to export the pending transactions of the safes (e.g. https://safe-transaction-mainnet.safe.global), disabled when empty",
+			EnvVars: opservice.PrefixEnvVar(envVar, "SAFE_TX_SERVICE_URL"),
+		},
+		&cli.DurationFlag{
+			Name:    StuckExecutionDurationFlagName,
+			Usage:   "Duration after which a transaction approved through ApproveHash, or fully signed in the Safe Transaction Service, and not executed is considered stuck",
+			Value:   24 * time.Hour,
+			EnvVars: opservice.PrefixEnvVar(envVar, "STUCK_EXECUTION_DURATION"),
+		},
+		&cli.StringFlag{
+			Name:    NotifyWebhookURLFlagName,
+			Usage:   "Webhook receiving the alert encoded in JSON when the remaining time of an owner reaches a threshold, disabled when empty",
+			EnvVars: opservice.PrefixEnvVar(envVar, "NOTIFY_WEBHOOK_URL"),
+		},
+		&cli.StringFlag{
+			Name:    NotifySlackURLFlagName,
+			Usage:   "Slack incoming webhook notified when the remaining time of an owner reaches a threshold, disabled when empty",
+			EnvVars: opservice.PrefixEnvVar(envVar, "NOTIFY_SLACK_URL"),
+		},
+		&cli.StringSliceFlag{
+			Name:    NotifyThresholdsFlagName,
+			Usage:   "Escalation thresholds of the notifications formatted via severity=duration",
+			Value:   cli.NewStringSlice("medium=336h", "high=168h", "critical=48h"),
+			EnvVars: opservice.PrefixEnvVar(envVar, "NOTIFY_THRESHOLDS"),
+		},
+		&cli.BoolFlag{
+			Name:    OneShotFlagName,
+			Usage:   "Perform a single evaluation and exit with 0 (ok), 1 (an owner is within the buffer), 2 (an owner is expired) or 3 (failed to read the safes)",
+			Value:   false,
+			EnvVars: opservice.PrefixEnvVar(envVar, "ONE_SHOT"),
+		},
+		&cli.DurationFlag{
+			Name:    BufferFlagName,
+			Usage:   "Warning buffer before the liveness expiration, an owner is at risk when block.timestamp + buffer > lastLive(owner) + livenessInterval",
+			Value:   14 * 24 * time.Hour,
+			EnvVars: opservice.PrefixEnvVar(envVar, "BUFFER"),
 		},
 	}
 }
@@ -0,0 +1,44 @@
+package liveness_expiration
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// checkCodeHashes ensures the bytecode of the LivenessGuard and the LivenessModule didn't change (redeploy or metamorphic contract).
+// The codehashes are compared against the ones from the configuration, or against the first ones observed when not configured.
+func (m *chainMonitor) checkCodeHashes(ctx context.Context, safe *safeTarget, latestL1Height uint64) {
+	blockNumber := new(big.Int).SetUint64(latestL1Height)
+	m.checkCodeHash(ctx, safe, "guard", safe.LivenessGuardAddress, &safe.guardCodeHash, blockNumber)
+	m.checkCodeHash(ctx, safe, "module", safe.LivenessModuleAddress, &safe.moduleCodeHash, blockNumber)
+}
+
+// checkCodeHash compares the codehash of a single contract with the expected one, the expected one is set on the first call when empty.
+func (m *chainMonitor) checkCodeHash(ctx context.Context, safe *safeTarget, contract string, address common.Address, expected *common.Hash, blockNumber *big.Int) {
+	code, err := m.l1Client.CodeAt(ctx, address, blockNumber)
+	if err != nil {
+		m.log.Error("failed to query the code of the contract", "err", err, "safe", safe.label, "contract", contract, "address", address)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "CodeAt").Inc()
+		return
+	}
+
+	codeHash := crypto.Keccak256Hash(code)
+	if *expected == (common.Hash{}) {
+		m.log.Info("no codehash configured, the current one is used as reference", "safe", safe.label, "contract", contract, "address", address, "codehash", codeHash)
+		*expected = codeHash
+	}
+
+	alert := m.alert(CodeHashMismatchRule, "P0", safe, fmt.Sprintf("the bytecode of the %s %s of the safe %s changed", contract, address, safe.label), address)
+	alert.Labels["expectedCodeHash"], alert.Labels["codeHash"] = expected.String(), codeHash.String()
+	m.tracker.Update(ctx, alert, codeHash != *expected)
+	if codeHash != *expected {
+		m.log.Error("the bytecode of the contract changed!", "safe", safe.label, "contract", contract, "address", address, "expected_codehash", *expected, "codehash", codeHash)
+		m.codeHashMismatch.WithLabelValues(safe.label, contract).Set(1)
+	} else {
+		m.codeHashMismatch.WithLabelValues(safe.label, contract).Set(0)
+	}
+}
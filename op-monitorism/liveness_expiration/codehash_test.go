@@ -0,0 +1,51 @@
+package liveness_expiration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCheckCodeHashes(t *testing.T) {
+	tests := []struct {
+		name           string
+		guardCodeHash  common.Hash // configured codehashes, empty to use the first ones observed.
+		moduleCodeHash common.Hash
+		moduleCode     []byte // bytecode of the module after the first iteration.
+		expectedGuard  float64
+		expectedModule float64
+	}{
+		{name: "Codehashes observed and unchanged", moduleCode: []byte{0x60, 0x01}},
+		{name: "Module redeployed after the first observation", moduleCode: []byte{0x60, 0x03}, expectedModule: 1},
+		{name: "Configured codehashes matching", guardCodeHash: crypto.Keccak256Hash([]byte{0x60, 0x02}), moduleCodeHash: crypto.Keccak256Hash([]byte{0x60, 0x01}), moduleCode: []byte{0x60, 0x01}},
+		{name: "Configured guard codehash mismatching", guardCodeHash: common.HexToHash("0x01"), moduleCode: []byte{0x60, 0x01}, expectedGuard: 1},
+		{name: "Configured module codehash mismatching", moduleCodeHash: common.HexToHash("0x01"), moduleCode: []byte{0x60, 0x01}, expectedModule: 1},
+		{name: "Module redeployed to the configured codehash", moduleCodeHash: crypto.Keccak256Hash([]byte{0x60, 0x03}), moduleCode: []byte{0x60, 0x03}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := newSafeNode(t, 100*86400)
+			m, notifier := newTestMonitor(t, node, func(cfg *CLIConfig) {
+				cfg.Safes[0].GuardCodeHash, cfg.Safes[0].ModuleCodeHash = test.guardCodeHash, test.moduleCodeHash
+			})
+			chain := m.chains[0]
+			m.Run(context.Background())
+			node.moduleCode = test.moduleCode
+			m.Run(context.Background())
+
+			if mismatch := testutil.ToFloat64(chain.codeHashMismatch.WithLabelValues(testSafe.String(), "guard")); mismatch != test.expectedGuard {
+				t.Errorf("Failed %s: expected the guard mismatch %v but got %v", test.name, test.expectedGuard, mismatch)
+			}
+			if mismatch := testutil.ToFloat64(chain.codeHashMismatch.WithLabelValues(testSafe.String(), "module")); mismatch != test.expectedModule {
+				t.Errorf("Failed %s: expected the module mismatch %v but got %v", test.name, test.expectedModule, mismatch)
+			}
+			if firing := notifier.Firing(CodeHashMismatchRule); float64(len(firing)) != test.expectedGuard+test.expectedModule {
+				t.Errorf("Failed %s: expected %v codehash mismatch alerts firing but got %v", test.name, test.expectedGuard+test.expectedModule, firing)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package liveness_expiration
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// SafeConfig is a Safe monitored along with its LivenessGuard and LivenessModule.
+type SafeConfig struct {
+	// Chain is the name of the chain of the safe, the default chain (`--l1.node.url`) when empty.
+	Chain string `yaml:"chain"`
+
+	SafeAddress           common.Address `yaml:"safe"`
+	LivenessGuardAddress  common.Address `yaml:"guard"`
+	LivenessModuleAddress common.Address `yaml:"module"`
+
+	// GuardCodeHash and ModuleCodeHash are the expected codehashes of the contracts, the first ones observed are used when not set.
+	GuardCodeHash  common.Hash `yaml:"guardCodeHash"`
+	ModuleCodeHash common.Hash `yaml:"moduleCodeHash"`
+
+	// AllowedModules are the modules allowed on the safe in addition to the LivenessModule.
+	AllowedModules []common.Address `yaml:"allowedModules"`
+
+	// Owners is the expected roster of the safe, only available from the YAML file.
+	Owners []RosterMember `yaml:"owners"`
+}
+
+// chainName returns the name of the chain of the safe.
+func (c SafeConfig) chainName() string {
+	if len(c.Chain) == 0 {
+		return DefaultChain
+	}
+	return c.Chain
+}
+
+// RosterMember is an expected owner of a safe along with a human readable name (e.g. "Member A").
+type RosterMember struct {
+	Address common.Address `yaml:"address"`
+	Name    string         `yaml:"name"`
+}
+
+// SafesConfiguration is the content of the YAML file given with `--safes.config`.
+//
+//	chains:
+//	  - name: optimism
+//	    rpc: https://mainnet.optimism.io
+//	safes:
+//	  - safe: 0xc2819DC788505Aac350142A7A707BF9D03E3Bd03
+//	    guard: 0x24424336F04440b1c28685a38303aC33C9D14a25
+//	    module: 0x0454092516c9A4d636d3CAfA1e82161376C8a748
+//	    owners:
+//	      - address: 0x42d27eEA1AD6e22Af6284F609847CB3Cd56B9c64
+//	        name: Member A
+type SafesConfiguration struct {
+	// Chains are the chains in addition to the default one (`--l1.node.url`).
+	Chains []ChainConfig `yaml:"chains"`
+	Safes  []SafeConfig  `yaml:"safes"`
+}
+
+// parseSafeConfig ensures the three addresses are hex-encoded and returns the associated `SafeConfig`.
+func parseSafeConfig(safe, guard, module string) (SafeConfig, error) {
+	for _, addr := range []string{safe, guard, module} {
+		if !common.IsHexAddress(addr) {
+			return SafeConfig{}, fmt.Errorf("address is not a hex-encoded address: %q", addr)
+		}
+	}
+
+	return SafeConfig{
+		SafeAddress:           common.HexToAddress(safe),
+		LivenessGuardAddress:  common.HexToAddress(guard),
+		LivenessModuleAddress: common.HexToAddress(module),
+	}, nil
+}
+
+// ReadSafesConfigFile reads the list of safes to monitor from a YAML file.
+func ReadSafesConfigFile(path string) ([]SafeConfig, error) {
+	config, err := ReadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return config.Safes, nil
+}
+
+// ReadConfigFile reads the chains and the safes to monitor from a YAML file.
+func ReadConfigFile(path string) (SafesConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SafesConfiguration{}, fmt.Errorf("failed to read the safes config file %s: %w", path, err)
+	}
+
+	var config SafesConfiguration
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return SafesConfiguration{}, fmt.Errorf("failed to parse the safes config file %s: %w", path, err)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,89 @@
+package liveness_expiration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const safesConfigData = `
+chains:
+  - name: optimism
+    rpc: https://mainnet.optimism.io
+safes:
+  - safe: 0xc2819DC788505Aac350142A7A707BF9D03E3Bd03
+    guard: 0x24424336F04440b1c28685a38303aC33C9D14a25
+    module: 0x0454092516c9A4d636d3CAfA1e82161376C8a748
+    owners:
+      - address: 0x42d27eEA1AD6e22Af6284F609847CB3Cd56B9c64
+        name: Member A
+  - chain: optimism
+    safe: 0x847B5c174615B1B7fDF770882256e2D3E95b9D92
+    guard: 0x0000000000000000000000000000000000000002
+    module: 0x0000000000000000000000000000000000000003
+`
+
+func TestReadSafesConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "safes.yaml")
+	if err := os.WriteFile(path, []byte(safesConfigData), 0644); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	safes, err := ReadSafesConfigFile(path)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(safes) != 2 {
+		t.Fatalf("expected 2 safes but got %d", len(safes))
+	}
+	if safes[0].SafeAddress != common.HexToAddress("0xc2819DC788505Aac350142A7A707BF9D03E3Bd03") {
+		t.Errorf("unexpected safe address %s", safes[0].SafeAddress)
+	}
+	if len(safes[0].Owners) != 1 || safes[0].Owners[0].Name != "Member A" || safes[0].Owners[0].Address != common.HexToAddress("0x42d27eEA1AD6e22Af6284F609847CB3Cd56B9c64") {
+		t.Errorf("unexpected roster %v", safes[0].Owners)
+	}
+	if len(safes[1].Owners) != 0 {
+		t.Errorf("expected no roster but got %v", safes[1].Owners)
+	}
+	if safes[1].LivenessModuleAddress != common.HexToAddress("0x0000000000000000000000000000000000000003") {
+		t.Errorf("unexpected module address %s", safes[1].LivenessModuleAddress)
+	}
+}
+
+func TestReadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "safes.yaml")
+	if err := os.WriteFile(path, []byte(safesConfigData), 0644); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	config, err := ReadConfigFile(path)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(config.Chains) != 1 || config.Chains[0].Name != "optimism" || config.Chains[0].NodeURL != "https://mainnet.optimism.io" {
+		t.Errorf("unexpected chains %v", config.Chains)
+	}
+	if config.Safes[0].chainName() != DefaultChain || config.Safes[1].chainName() != "optimism" {
+		t.Errorf("unexpected chains of the safes %s and %s", config.Safes[0].chainName(), config.Safes[1].chainName())
+	}
+
+	cfg := CLIConfig{Chains: config.Chains, Safes: config.Safes}
+	if err := cfg.checkChains(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	cfg.Safes = append(cfg.Safes, SafeConfig{Chain: "sepolia"})
+	if err := cfg.checkChains(); err == nil {
+		t.Errorf("expected an error for a safe on a chain not configured")
+	}
+}
+
+func TestParseSafeConfig(t *testing.T) {
+	if _, err := parseSafeConfig("0xc2819DC788505Aac350142A7A707BF9D03E3Bd03", "0x24424336F04440b1c28685a38303aC33C9D14a25", "0x0454092516c9A4d636d3CAfA1e82161376C8a748"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := parseSafeConfig("0xc2819DC788505Aac350142A7A707BF9D03E3Bd03", "notAnAddress", "0x0454092516c9A4d636d3CAfA1e82161376C8a748"); err == nil {
+		t.Errorf("expected an error for an invalid guard address")
+	}
+}
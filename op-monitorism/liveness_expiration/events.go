@@ -0,0 +1,119 @@
+package liveness_expiration
+
+import (
+	"context"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/liveness_expiration/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// checkOwnerEvents scans the `AddedOwner` and `RemovedOwner` events emitted by the safe and the `OwnerRecorded` events
+// emitted by the LivenessGuard since the last iteration.
+// The `ApproveHash` events are remembered until the execution of the transaction (`ExecutionSuccess` or `ExecutionFailure`) to detect the stuck executions.
+// The block range is bounded by `maxBlockRange`, the remaining blocks are scanned during the next iterations.
+// The range only advances once every query succeeded, a failed query rescanning the whole range during the next iteration.
+func (m *chainMonitor) checkOwnerEvents(ctx context.Context, safe *safeTarget, latestL1Height uint64, now uint64) {
+	safeLabel := safe.label
+	fromBlockNumber := safe.nextL1Height
+	if fromBlockNumber > latestL1Height {
+		m.log.Info("no new blocks", "safe", safeLabel, "next_height", fromBlockNumber, "latest_height", latestL1Height)
+		return
+	}
+
+	toBlockNumber := latestL1Height
+	if toBlockNumber-fromBlockNumber > m.maxBlockRange {
+		toBlockNumber = fromBlockNumber + m.maxBlockRange
+	}
+	filterOpts := &bind.FilterOpts{Context: ctx, Start: fromBlockNumber, End: &toBlockNumber}
+
+	addedOwners, err := safe.GnosisSafe.FilterAddedOwner(filterOpts)
+	if err != nil {
+		m.log.Error("failed to query the `AddedOwner` events", "err", err, "safe", safeLabel, "from_height", fromBlockNumber, "to_height", toBlockNumber)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "FilterAddedOwner").Inc()
+		return
+	}
+	defer addedOwners.Close()
+
+	removedOwners, err := safe.GnosisSafe.FilterRemovedOwner(filterOpts)
+	if err != nil {
+		m.log.Error("failed to query the `RemovedOwner` events", "err", err, "safe", safeLabel, "from_height", fromBlockNumber, "to_height", toBlockNumber)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "FilterRemovedOwner").Inc()
+		return
+	}
+	defer removedOwners.Close()
+
+	approvedHashes, err := safe.GnosisSafe.FilterApproveHash(filterOpts, nil, nil)
+	if err != nil {
+		m.log.Error("failed to query the `ApproveHash` events", "err", err, "safe", safeLabel, "from_height", fromBlockNumber, "to_height", toBlockNumber)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "FilterApproveHash").Inc()
+		return
+	}
+	defer approvedHashes.Close()
+
+	executionSuccesses, err := safe.GnosisSafe.FilterExecutionSuccess(filterOpts)
+	if err != nil {
+		m.log.Error("failed to query the `ExecutionSuccess` events", "err", err, "safe", safeLabel, "from_height", fromBlockNumber, "to_height", toBlockNumber)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "FilterExecutionSuccess").Inc()
+		return
+	}
+	defer executionSuccesses.Close()
+
+	executionFailures, err := safe.GnosisSafe.FilterExecutionFailure(filterOpts)
+	if err != nil {
+		m.log.Error("failed to query the `ExecutionFailure` events", "err", err, "safe", safeLabel, "from_height", fromBlockNumber, "to_height", toBlockNumber)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "FilterExecutionFailure").Inc()
+		return
+	}
+	defer executionFailures.Close()
+
+	var ownersRecorded *bindings.LivenessGuardOwnerRecordedIterator
+	if safe.LivenessGuard != nil {
+		ownersRecorded, err = safe.LivenessGuard.FilterOwnerRecorded(filterOpts)
+		if err != nil {
+			m.log.Error("failed to query the `OwnerRecorded` events", "err", err, "safe", safeLabel, "from_height", fromBlockNumber, "to_height", toBlockNumber)
+			m.unexpectedRpcErrors.WithLabelValues("l1", "FilterOwnerRecorded").Inc()
+			return
+		}
+		defer ownersRecorded.Close()
+	}
+
+	// every query succeeded, the events are only processed once so that a failure doesn't count them twice when the
+	// range is scanned again during the next iteration.
+	m.ownerEvents.WithLabelValues(safeLabel, "AddedOwner").Add(0)
+	m.ownerEvents.WithLabelValues(safeLabel, "RemovedOwner").Add(0)
+	for addedOwners.Next() {
+		event := addedOwners.Event
+		m.log.Warn("`AddedOwner` event detected", "safe", safeLabel, "owner", event.Owner, "block_height", event.Raw.BlockNumber, "tx_hash", event.Raw.TxHash.String())
+		m.ownerEvents.WithLabelValues(safeLabel, "AddedOwner").Inc()
+	}
+	for removedOwners.Next() {
+		event := removedOwners.Event
+		m.log.Warn("`RemovedOwner` event detected", "safe", safeLabel, "owner", event.Owner, "block_height", event.Raw.BlockNumber, "tx_hash", event.Raw.TxHash.String())
+		m.ownerEvents.WithLabelValues(safeLabel, "RemovedOwner").Inc()
+	}
+	for approvedHashes.Next() {
+		event := approvedHashes.Event
+		m.log.Info("`ApproveHash` event detected", "safe", safeLabel, "owner", event.Owner, "approved_hash", common.Hash(event.ApprovedHash), "block_height", event.Raw.BlockNumber, "tx_hash", event.Raw.TxHash.String())
+		if _, ok := safe.approvedHashes[event.ApprovedHash]; !ok {
+			safe.approvedHashes[event.ApprovedHash] = now
+		}
+	}
+	for executionSuccesses.Next() {
+		delete(safe.approvedHashes, common.Hash(executionSuccesses.Event.TxHash))
+	}
+	for executionFailures.Next() {
+		delete(safe.approvedHashes, common.Hash(executionFailures.Event.TxHash))
+	}
+
+	if ownersRecorded != nil {
+		for ownersRecorded.Next() {
+			event := ownersRecorded.Event
+			m.log.Info("`OwnerRecorded` event detected, the owner proved its liveness", "safe", safeLabel, "owner", event.Owner, "block_height", event.Raw.BlockNumber, "tx_hash", event.Raw.TxHash.String())
+			m.ownerRecordedEvents.WithLabelValues(m.ownerLabels(safe, event.Owner)...).Inc()
+			safe.lastLiveTxHash[event.Owner] = event.Raw.TxHash
+		}
+	}
+
+	safe.nextL1Height = toBlockNumber + 1
+}
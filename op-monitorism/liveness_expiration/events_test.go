@@ -0,0 +1,141 @@
+package liveness_expiration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/liveness_expiration/bindings"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// logsBackend serves the logs of the events by topic, failing the queries of the events in `failing`.
+type logsBackend struct {
+	bind.ContractBackend
+
+	logs    map[common.Hash][]types.Log
+	failing map[common.Hash]bool
+}
+
+func (b *logsBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	topic := query.Topics[0][0]
+	if b.failing[topic] {
+		return nil, errors.New("connection reset by peer")
+	}
+	return b.logs[topic], nil
+}
+
+func TestCheckOwnerEventsFailure(t *testing.T) {
+	safeABI, err := bindings.GnosisSafeMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	addedOwner := safeABI.Events["AddedOwner"].ID
+	approveHash := safeABI.Events["ApproveHash"].ID
+
+	backend := &logsBackend{
+		logs: map[common.Hash][]types.Log{
+			addedOwner: {{Topics: []common.Hash{addedOwner}, Data: common.LeftPadBytes(common.HexToAddress("0x01").Bytes(), 32), BlockNumber: 10}},
+		},
+		failing: map[common.Hash]bool{approveHash: true},
+	}
+	gnosisSafe, err := bindings.NewGnosisSafe(common.HexToAddress("0x02"), backend)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	m := &chainMonitor{
+		log:                 log.New(),
+		maxBlockRange:       1000,
+		unexpectedRpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "unexpectedRpcErrors"}, []string{"section", "name"}),
+		ownerEvents:         prometheus.NewCounterVec(prometheus.CounterOpts{Name: "ownerEvents"}, []string{"safe", "event"}),
+	}
+	safe := &safeTarget{label: "safe", GnosisSafe: gnosisSafe, nextL1Height: 1, approvedHashes: map[common.Hash]uint64{}}
+
+	// the `ApproveHash` query failing twice, the range is scanned three times but the `AddedOwner` event counted once.
+	for i := 0; i < 2; i++ {
+		m.checkOwnerEvents(context.Background(), safe, 100, 0)
+		if safe.nextL1Height != 1 {
+			t.Fatalf("expected the range not to advance but got the next height %d", safe.nextL1Height)
+		}
+	}
+	if count := testutil.ToFloat64(m.ownerEvents.WithLabelValues("safe", "AddedOwner")); count != 0 {
+		t.Errorf("expected no `AddedOwner` event counted before the queries succeed but got %v", count)
+	}
+
+	backend.failing = nil
+	m.checkOwnerEvents(context.Background(), safe, 100, 0)
+	if safe.nextL1Height != 101 {
+		t.Errorf("expected the next height 101 but got %d", safe.nextL1Height)
+	}
+	if count := testutil.ToFloat64(m.ownerEvents.WithLabelValues("safe", "AddedOwner")); count != 1 {
+		t.Errorf("expected 1 `AddedOwner` event but got %v", count)
+	}
+}
+
+func TestCheckOwnerRecordedEvents(t *testing.T) {
+	ownerRecorded := livenessGuardABI.Events["OwnerRecorded"].ID
+	recorded := func(owner common.Address, txHash common.Hash) types.Log {
+		return types.Log{Topics: []common.Hash{ownerRecorded}, Data: common.LeftPadBytes(owner.Bytes(), 32), BlockNumber: 10, TxHash: txHash}
+	}
+	tx1, tx2 := common.HexToHash("0x01"), common.HexToHash("0x02")
+
+	tests := []struct {
+		name           string
+		logs           []types.Log
+		expectedCounts map[common.Address]float64
+		expectedTxs    map[common.Address]common.Hash
+	}{
+		{name: "No event", expectedCounts: map[common.Address]float64{}, expectedTxs: map[common.Address]common.Hash{}},
+		{name: "Single event", logs: []types.Log{recorded(ownerA, tx1)}, expectedCounts: map[common.Address]float64{ownerA: 1}, expectedTxs: map[common.Address]common.Hash{ownerA: tx1}},
+		{name: "Several events of an owner", logs: []types.Log{recorded(ownerA, tx1), recorded(ownerA, tx2)}, expectedCounts: map[common.Address]float64{ownerA: 2}, expectedTxs: map[common.Address]common.Hash{ownerA: tx2}},
+		{name: "Several owners", logs: []types.Log{recorded(ownerA, tx1), recorded(ownerB, tx2)}, expectedCounts: map[common.Address]float64{ownerA: 1, ownerB: 1}, expectedTxs: map[common.Address]common.Hash{ownerA: tx1, ownerB: tx2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend := &logsBackend{logs: map[common.Hash][]types.Log{ownerRecorded: test.logs}}
+			gnosisSafe, err := bindings.NewGnosisSafe(testSafe, backend)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			livenessGuard, err := bindings.NewLivenessGuard(testGuard, backend)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+
+			m := &chainMonitor{
+				log:                 log.New(),
+				maxBlockRange:       1000,
+				unexpectedRpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "unexpectedRpcErrors"}, []string{"section", "name"}),
+				ownerEvents:         prometheus.NewCounterVec(prometheus.CounterOpts{Name: "ownerEvents"}, []string{"safe", "event"}),
+				ownerRecordedEvents: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "ownerRecordedEvents"}, []string{"safe", "owner", "member", "name"}),
+			}
+			safe := &safeTarget{label: "safe", GnosisSafe: gnosisSafe, LivenessGuard: livenessGuard, nextL1Height: 1, lastLiveTxHash: map[common.Address]common.Hash{}, approvedHashes: map[common.Hash]uint64{}}
+
+			m.checkOwnerEvents(context.Background(), safe, 100, 0)
+			if count := testutil.CollectAndCount(m.ownerRecordedEvents); count != len(test.expectedCounts) {
+				t.Errorf("Failed %s: expected the events of %d owners but got %d", test.name, len(test.expectedCounts), count)
+			}
+			for owner, expected := range test.expectedCounts {
+				if count := testutil.ToFloat64(m.ownerRecordedEvents.WithLabelValues("safe", owner.String(), "", "")); count != expected {
+					t.Errorf("Failed %s: expected %v events of %s but got %v", test.name, expected, owner, count)
+				}
+			}
+			if len(safe.lastLiveTxHash) != len(test.expectedTxs) {
+				t.Errorf("Failed %s: expected the transactions %v but got %v", test.name, test.expectedTxs, safe.lastLiveTxHash)
+			}
+			for owner, expected := range test.expectedTxs {
+				if txHash := safe.lastLiveTxHash[owner]; txHash != expected {
+					t.Errorf("Failed %s: expected the transaction %s of %s but got %s", test.name, expected, owner, txHash)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package liveness_expiration
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// listModules returns every module enabled on the safe by walking `getModulesPaginated`.
+// Before Safe v1.4.1, `next` is the first module of the next page and not the last one of the current page, it is added explicitly.
+func (m *chainMonitor) listModules(ctx context.Context, safe *safeTarget, latestL1Height uint64) ([]common.Address, error) {
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(latestL1Height)}
+
+	var modules []common.Address
+	seen := make(map[common.Address]bool)
+	start := SentinelModules
+	for {
+		page, err := safe.GnosisSafe.GetModulesPaginated(callOpts, start, big.NewInt(MaxModulesPageSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the modules of the safe: %w", err)
+		}
+		if page.Next != SentinelModules && page.Next != (common.Address{}) {
+			page.Array = append(page.Array, page.Next)
+		}
+		for _, module := range page.Array {
+			if !seen[module] {
+				seen[module] = true
+				modules = append(modules, module)
+			}
+		}
+		if page.Next == SentinelModules || page.Next == (common.Address{}) || page.Next == start {
+			return modules, nil
+		}
+		start = page.Next
+	}
+}
+
+// unexpectedModules returns the modules that are neither the LivenessModule nor in the allowlist.
+func unexpectedModules(modules []common.Address, livenessModule common.Address, allowed []common.Address) []common.Address {
+	allowlist := map[common.Address]bool{livenessModule: true}
+	for _, module := range allowed {
+		allowlist[module] = true
+	}
+
+	var unexpected []common.Address
+	for _, module := range modules {
+		if !allowlist[module] {
+			unexpected = append(unexpected, module)
+		}
+	}
+	return unexpected
+}
+
+// checkModules compares the modules enabled on the safe against the LivenessModule and the allowlist from the configuration.
+// An unknown module on a Security Council safe can execute any transaction without the signatures of the owners.
+func (m *chainMonitor) checkModules(ctx context.Context, safe *safeTarget, latestL1Height uint64) {
+	modules, err := m.listModules(ctx, safe, latestL1Height)
+	if err != nil {
+		m.log.Error("failed to list the modules of the safe", "err", err, "blockNumber", latestL1Height, "safe", safe.label)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "GetModulesPaginated").Inc()
+		return
+	}
+
+	unexpected := unexpectedModules(modules, safe.LivenessModuleAddress, safe.allowedModules)
+	flagged := make(map[common.Address]bool, len(unexpected))
+	for _, module := range unexpected {
+		m.log.Error("an unexpected module is enabled on the safe!", "safe", safe.label, "module", module)
+		flagged[module] = true
+	}
+	// the modules flagged during the previous iteration are resolved once disabled or allowed.
+	for _, module := range append(unexpected, safe.unexpectedModules...) {
+		alert := m.alert(UnexpectedModuleRule, "P0", safe, fmt.Sprintf("the unexpected module %s is enabled on the safe %s", module, safe.label), module)
+		m.tracker.Update(ctx, alert, flagged[module])
+	}
+	safe.unexpectedModules = unexpected
+	m.enabledModules.WithLabelValues(safe.label).Set(float64(len(modules)))
+	m.unexpectedModules.WithLabelValues(safe.label).Set(float64(len(unexpected)))
+}
@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"math/big"
 	"math/bits"
+	"net/http"
 	"time"
 
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/liveness_expiration/bindings"
+	opbindings "github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
@@ -19,35 +23,129 @@ const (
 	MetricsNamespace = "liveness_expiration_mon"
 )
 
-type Monitor struct {
-	log      log.Logger
-	l1Client *ethclient.Client
+var (
+	// GuardStorageSlot is the storage slot of the guard in a Safe, `keccak256("guard_manager.guard.address")`.
+	GuardStorageSlot = common.HexToHash("0x4a204f620c8c5ccdca3fd54d003badd85ba500436a431f0cbda4f558c93c34c8")
+)
+
+// safeTarget is a Safe monitored with its LivenessGuard and LivenessModule.
+// For a nested safe, the LivenessGuard and LivenessModule are `nil` when they are not installed.
+type safeTarget struct {
+	label string // value of the `safe` label, the path `parent/child` for the nested safes.
 
-	/** Contracts **/
 	GnosisSafe            *bindings.GnosisSafe
 	GnosisSafeAddress     common.Address
 	LivenessGuard         *bindings.LivenessGuard
 	LivenessGuardAddress  common.Address
 	LivenessModule        *bindings.LivenessModule
 	LivenessModuleAddress common.Address
+	adapter               livenessModuleAdapter // reads the state of the version of the LivenessModule.
+
+	owners        []common.Address // owners observed during the previous iteration, `nil` before the first one.
+	threshold     *big.Int         // threshold observed during the previous iteration, `nil` before the first one.
+	fallbackOwner *common.Address  // fallback owner observed during the previous iteration, `nil` before the first one.
+	interval      *big.Int         // liveness interval observed during the previous iteration, `nil` before the first one.
+	nextL1Height  uint64           // next block to scan for the safe events.
+
+	nested map[common.Address]*safeTarget // owners that are safes, `nil` values are owners that are not safes.
+
+	lastLiveTxHash map[common.Address]common.Hash // transaction of the last `OwnerRecorded` event of each owner.
+
+	consecutiveFailures uint64 // number of consecutive iterations where the safe could not be read.
+
+	nonce          uint64                 // nonce of the safe.
+	nonceUpdatedAt uint64                 // block timestamp of the iteration where the nonce advanced for the last time.
+	approvedHashes map[common.Hash]uint64 // hashes approved through `ApproveHash` not executed yet, with the block timestamp of the iteration they were observed.
+
+	guardCodeHash  common.Hash // expected codehash of the LivenessGuard.
+	moduleCodeHash common.Hash // expected codehash of the LivenessModule.
+
+	allowedModules    []common.Address // modules allowed on the safe in addition to the LivenessModule.
+	unexpectedModules []common.Address // unexpected modules enabled on the safe during the previous iteration.
+
+	notifiedLevel map[common.Address]map[string]int // escalation level already notified for each owner, by notifier.
+
+	roster map[common.Address]string // expected owners of the safe with their name, empty when no roster is configured.
+
+	ownerSeries map[common.Address][]string // label values of the series set for each owner.
+}
+
+// chainMonitor monitors the safes of a single chain, its metrics have a constant `chain` label.
+type chainMonitor struct {
+	name      string // name of the chain.
+	log       log.Logger
+	tracker   *alerts.Tracker // emits the alerts of the safes, shared by the chains.
+	l1Client  *ethclient.Client
+	multicall *opbindings.MultiCall3CallerRaw
+
+	/** Contracts **/
+	safes []*safeTarget
+
+	buffer        uint64 // warning buffer in seconds used to evaluate the invariant.
+	maxBlockRange uint64
+	nestedDepth   uint64 // depth of the nested safes resolved, 0 to disable.
+	block         uint64 // block every query is pinned to in the historical mode, 0 to follow the latest block.
+
+	status    Status // worst status observed since the last `RunOnce`.
+	evaluated bool   // true when the last `Run` went through every safe.
+
+	addressBook map[common.Address]string // names of the addresses from the address book file.
+	ens         bool                      // resolve the names of the owners through the ENS reverse records.
+	names       map[common.Address]string // cache of the resolved names, empty when the owner has no name.
+
+	safeTxServiceURL string // url of the Safe Transaction Service, empty to disable the pending transactions.
+	httpClient       *http.Client
+
+	stuckDuration uint64 // duration in seconds after which an approved or fully signed transaction not executed is stuck.
+
+	notifiers        []alerts.Notifier // notified on the escalation levels, empty to disable.
+	notifyThresholds []NotifyThreshold
 	/** Metrics **/
-	highestBlockNumber      *prometheus.GaugeVec
-	unexpectedRpcErrors     *prometheus.CounterVec
-	intervalLiveness        *prometheus.GaugeVec
-	lastLiveOfAOwner        *prometheus.GaugeVec
-	blockTimestamp          *prometheus.GaugeVec
-	ownerStalePeriod        *prometheus.GaugeVec
-	ownerDaysBeforeDeadline *prometheus.GaugeVec
-}
-
-// NewMonitor creates a new monitor.
-func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
-	log.Info("Starting the liveness expiration monitoring...")
-	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to dial l1: %w", err)
-	}
+	highestBlockNumber              *prometheus.GaugeVec
+	unexpectedRpcErrors             *prometheus.CounterVec
+	intervalLiveness                *prometheus.GaugeVec
+	intervalChanges                 *prometheus.CounterVec
+	lastLiveOfAOwner                *prometheus.GaugeVec
+	blockTimestamp                  prometheus.Gauge
+	ownerStalePeriod                *prometheus.GaugeVec
+	ownerDaysBeforeDeadline         *prometheus.GaugeVec
+	ownerSecondsBeforeDeadline      *prometheus.GaugeVec
+	ownerAtRisk                     *prometheus.GaugeVec
+	ownerInfo                       *prometheus.GaugeVec
+	ownerSetChanges                 *prometheus.CounterVec
+	ownerEvents                     *prometheus.CounterVec
+	removedOwners                   *prometheus.CounterVec
+	ownerRecordedEvents             *prometheus.CounterVec
+	threshold                       *prometheus.GaugeVec
+	thresholdChanges                *prometheus.CounterVec
+	thresholdUnreachable            *prometheus.GaugeVec
+	unexpectedOwners                *prometheus.GaugeVec
+	missingOwners                   *prometheus.GaugeVec
+	ownersCount                     *prometheus.GaugeVec
+	minOwners                       *prometheus.GaugeVec
+	fallbackOwner                   *prometheus.GaugeVec
+	shutdownImminent                *prometheus.GaugeVec
+	ownershipTransferredToFallback  *prometheus.GaugeVec
+	challengeActive                 *prometheus.GaugeVec
+	challengePeriodEnd              *prometheus.GaugeVec
+	contractNotInstalled            *prometheus.GaugeVec
+	enabledModules                  *prometheus.GaugeVec
+	unexpectedModules               *prometheus.GaugeVec
+	codeHashMismatch                *prometheus.GaugeVec
+	pendingTransactions             *prometheus.GaugeVec
+	pendingTransactionConfirmations *prometheus.GaugeVec
+	nonceGap                        *prometheus.GaugeVec
+	safeNonce                       *prometheus.GaugeVec
+	pendingApprovedHashes           *prometheus.GaugeVec
+	stuckExecution                  *prometheus.GaugeVec
+	consecutiveFailures             *prometheus.GaugeVec
+	notifications                   *prometheus.CounterVec
+	notifyFailures                  prometheus.Counter
+	lastSuccessfulScrape            *prometheus.GaugeVec
+}
 
+// newSafeTarget binds the contracts of a single safe.
+func newSafeTarget(l1Client *ethclient.Client, cfg SafeConfig, startingL1Height uint64) (*safeTarget, error) {
 	if cfg.SafeAddress.Cmp(common.Address{}) == 0 {
 		return nil, fmt.Errorf("The `SafeAddress` specified is set to -> %s", cfg.SafeAddress)
 	}
@@ -73,24 +171,145 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 		return nil, fmt.Errorf("failed to bind to the LivenessModule: %w", err)
 	}
 
-	log.Info("----------------------- Liveness Expiration Monitoring (Infos) -----------------------------")
-	log.Info("", "Safe Address", cfg.SafeAddress)
-	log.Info("", "LivenessModuleAddress", cfg.LivenessModuleAddress)
-	log.Info("", "LivenessGuardAddress", cfg.LivenessGuardAddress)
-	log.Info("", "L1RpcUrl", cfg.L1NodeURL)
-	log.Info("--------------------------- End of Infos -------------------------------------------------------")
-
-	return &Monitor{
-		log: log,
-
-		l1Client: l1Client,
+	roster := make(map[common.Address]string, len(cfg.Owners))
+	for _, member := range cfg.Owners {
+		roster[member.Address] = member.Name
+	}
 
+	return &safeTarget{
+		label:                 cfg.SafeAddress.String(),
 		GnosisSafe:            GnosisSafe,
 		GnosisSafeAddress:     cfg.SafeAddress,
 		LivenessGuard:         LivenessGuard,
 		LivenessGuardAddress:  cfg.LivenessGuardAddress,
 		LivenessModule:        LivenessModule,
 		LivenessModuleAddress: cfg.LivenessModuleAddress,
+
+		nextL1Height: startingL1Height,
+		nested:       make(map[common.Address]*safeTarget),
+
+		lastLiveTxHash: make(map[common.Address]common.Hash),
+		approvedHashes: make(map[common.Hash]uint64),
+		notifiedLevel:  make(map[common.Address]map[string]int),
+		roster:         roster,
+
+		guardCodeHash:  cfg.GuardCodeHash,
+		moduleCodeHash: cfg.ModuleCodeHash,
+		allowedModules: cfg.AllowedModules,
+	}, nil
+}
+
+// newChainMonitor creates the monitor of the safes configured on a chain.
+func newChainMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig, chain ChainConfig) (*chainMonitor, error) {
+	l1Client, err := ethclient.Dial(chain.NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", chain.Name, err)
+	}
+
+	multicall, err := opbindings.NewMultiCall3Caller(cfg.Multicall3Address, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the Multicall3: %w", err)
+	}
+
+	startingL1Height := uint64(0)
+	if chain.Name == DefaultChain { // the starting height is a block number of the default chain.
+		startingL1Height = cfg.StartingL1BlockHeight
+	}
+	if startingL1Height == 0 && cfg.Block != 0 {
+		startingL1Height = cfg.Block
+	} else if startingL1Height == 0 {
+		latestL1Height, err := l1Client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+		startingL1Height = latestL1Height
+	}
+
+	addressBook := make(map[common.Address]string)
+	if len(cfg.AddressBook) > 0 {
+		addressBook, err = ReadAddressBookFile(cfg.AddressBook)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The Safe Transaction Service and the ENS resolution only serve the current state, skipped in the historical mode
+	// so the output only reflects the pinned block.
+	ens := cfg.ENS && chain.Name == DefaultChain // the ENS registry is on Ethereum.
+	safeTxServiceURL := chain.SafeTxServiceURL
+	if cfg.Block != 0 {
+		ens, safeTxServiceURL = false, ""
+	}
+
+	var blockNumber *big.Int // `nil` for the latest block.
+	if cfg.Block != 0 {
+		blockNumber = new(big.Int).SetUint64(cfg.Block)
+	}
+
+	log.Info("----------------------- Liveness Expiration Monitoring (Infos) -----------------------------")
+	safes := make([]*safeTarget, 0, len(cfg.Safes))
+	for _, safeConfig := range cfg.Safes {
+		if safeConfig.chainName() != chain.Name {
+			continue
+		}
+		safe, err := newSafeTarget(l1Client, safeConfig, startingL1Height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure the safe %s: %w", safeConfig.SafeAddress, err)
+		}
+		safe.adapter, err = detectLivenessModuleAdapter(ctx, l1Client, safeConfig.LivenessModuleAddress, blockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect the LivenessModule version of the safe %s: %w", safeConfig.SafeAddress, err)
+		}
+		safes = append(safes, safe)
+
+		log.Info("", "Safe Address", safeConfig.SafeAddress)
+		log.Info("", "LivenessModuleAddress", safeConfig.LivenessModuleAddress)
+		log.Info("", "LivenessModuleVersion", safe.adapter.version())
+		log.Info("", "LivenessGuardAddress", safeConfig.LivenessGuardAddress)
+	}
+	log.Info("", "Chain", chain.Name)
+	log.Info("", "RpcUrl", chain.NodeURL)
+	log.Info("", "Multicall3Address", cfg.Multicall3Address)
+	log.Info("", "Buffer", cfg.Buffer)
+	log.Info("", "StartingL1BlockHeight", startingL1Height)
+	log.Info("", "NestedDepth", cfg.NestedDepth)
+	log.Info("", "AddressBook", cfg.AddressBook)
+	log.Info("", "ENS", ens)
+	log.Info("", "SafeTxServiceURL", safeTxServiceURL)
+	if cfg.Block != 0 {
+		log.Info("", "Block", cfg.Block)
+		log.Warn("historical mode, every query is pinned to the block", "block", cfg.Block)
+		if cfg.ENS || len(chain.SafeTxServiceURL) > 0 {
+			log.Warn("historical mode, ignoring the ENS names and the Safe Transaction Service which only serve the current state")
+		}
+	}
+	log.Info("--------------------------- End of Infos -------------------------------------------------------")
+
+	return &chainMonitor{
+		name: chain.Name,
+		log:  log,
+
+		l1Client:  l1Client,
+		multicall: &opbindings.MultiCall3CallerRaw{Contract: multicall},
+
+		safes: safes,
+
+		buffer:        uint64(cfg.Buffer.Seconds()),
+		maxBlockRange: cfg.EventBlockRange,
+		nestedDepth:   cfg.NestedDepth,
+		block:         cfg.Block,
+
+		addressBook: addressBook,
+		ens:         ens,
+		names:       make(map[common.Address]string),
+
+		safeTxServiceURL: safeTxServiceURL,
+		httpClient:       &http.Client{Timeout: SafeTxServiceTimeout},
+
+		stuckDuration: uint64(cfg.StuckExecutionDuration.Seconds()),
+
+		notifiers:        newNotifiers(cfg),
+		notifyThresholds: cfg.NotifyThresholds,
 		/** Metrics **/
 		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: MetricsNamespace,
@@ -106,32 +325,202 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 			Namespace: MetricsNamespace,
 			Name:      "intervalLiveness",
 			Help:      "Interval in (second) of the liveness from the liveness module",
-		}, []string{"interval"}),
+		}, []string{"safe"}),
+		intervalChanges: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "intervalChanges",
+			Help:      "Number of times the liveness interval of the liveness module changed, a change modifies the safety margin of the owners.",
+		}, []string{"safe"}),
 		lastLiveOfAOwner: m.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: MetricsNamespace,
 			Name:      "lastLiveOfAOwner",
 			Help:      "Last Live of an owner from the liveness guard, means the last time an owner make an action.",
-		}, []string{"address"}),
+		}, []string{"safe", "address", "member", "name"}),
 		ownerDaysBeforeDeadline: m.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: MetricsNamespace,
 			Name:      "ownerDaysBeforeDeadline",
 			Help:      "Number of days before the deadline is reached for a specific owner.",
-		}, []string{"safeOwnerAddress"}),
+		}, []string{"safe", "safeOwnerAddress", "member", "name"}),
+		ownerSecondsBeforeDeadline: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "ownerSecondsBeforeDeadline",
+			Help:      "Number of seconds before the deadline of an owner (lastLive(owner) + livenessInterval - block.timestamp), negative when expired.",
+		}, []string{"safe", "safeOwnerAddress", "member", "name"}),
 		ownerStalePeriod: m.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: MetricsNamespace,
 			Name:      "ownerStalePeriod",
 			Help:      "Safe Owner Stale Period, the time that a safe owner address is not active anymore, should always be 0. The values can be 0 (normal), 1 (1 day - HIGH 1 day left), 7 (7 days - MEDIUM 7 days left), 14 (14 days - LOW 14 days left).",
-		}, []string{"safeOwnerAddress"}),
-		blockTimestamp: m.NewGaugeVec(prometheus.GaugeOpts{
+		}, []string{"safe", "safeOwnerAddress", "member", "name"}),
+		ownerAtRisk: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "ownerAtRisk",
+			Help:      "1 if the owner breaks the invariant `block.timestamp + buffer > lastLive(owner) + livenessInterval`, 0 otherwise.",
+		}, []string{"safe", "owner", "member", "name"}),
+		ownerInfo: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "ownerInfo",
+			Help:      "1 for the name of an owner resolved through the address book or ENS.",
+		}, []string{"owner", "name"}),
+		ownerSetChanges: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "ownerSetChanges",
+			Help:      "Number of owners added or removed from the safe, observed by comparing the owners between two iterations.",
+		}, []string{"safe", "change"}),
+		ownerEvents: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "ownerEvents",
+			Help:      "Number of `AddedOwner` and `RemovedOwner` events emitted by the safe.",
+		}, []string{"safe", "event"}),
+		removedOwners: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "removedOwners",
+			Help:      "Number of owners removed from the safe whose metric series have been deleted.",
+		}, []string{"safe"}),
+		ownerRecordedEvents: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "ownerRecordedEvents",
+			Help:      "Number of `OwnerRecorded` events emitted by the liveness guard, each event is a proof of liveness of the owner.",
+		}, []string{"safe", "owner", "member", "name"}),
+		threshold: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "threshold",
+			Help:      "Signing threshold of the safe.",
+		}, []string{"safe"}),
+		thresholdChanges: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "thresholdChanges",
+			Help:      "Number of times the signing threshold of the safe changed.",
+		}, []string{"safe"}),
+		thresholdUnreachable: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "thresholdUnreachable",
+			Help:      "1 if the threshold is greater than the number of owners not at risk, meaning the safe could be unable to act before the liveness expiration. 0 otherwise.",
+		}, []string{"safe"}),
+		unexpectedOwners: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedOwners",
+			Help:      "Number of owners of the safe that are not in the expected roster.",
+		}, []string{"safe"}),
+		missingOwners: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "missingOwners",
+			Help:      "Number of members of the expected roster that are not owners of the safe.",
+		}, []string{"safe"}),
+		ownersCount: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "ownersCount",
+			Help:      "Number of owners of the safe.",
+		}, []string{"safe"}),
+		minOwners: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "minOwners",
+			Help:      "Minimum number of owners from the liveness module, below this number the ownership is transferred to the fallback owner.",
+		}, []string{"safe"}),
+		fallbackOwner: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "fallbackOwner",
+			Help:      "Fallback owner from the liveness module (label `fallbackOwner`), always 1.",
+		}, []string{"safe", "fallbackOwner"}),
+		shutdownImminent: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "shutdownImminent",
+			Help:      "1 if removing the owners at risk would push the safe below `minOwners`, meaning the ownership would be transferred to the fallback owner. 0 otherwise.",
+		}, []string{"safe"}),
+		ownershipTransferredToFallback: m.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: MetricsNamespace,
-			Name:      "BlockTimestamp",
-			Help:      "Block Timestamp of the last block.",
-		}, []string{"blocktimestamp"}),
+			Name:      "ownershipTransferredToFallback",
+			Help:      "CRITICAL: 1 if the LivenessModule (v1) transferred the ownership of the safe to the fallback owner, 0 otherwise.",
+		}, []string{"safe"}),
+		challengeActive: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "challengeActive",
+			Help:      "1 if the safe is challenged by the fallback owner of the LivenessModule (v2), 0 otherwise.",
+		}, []string{"safe"}),
+		challengePeriodEnd: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "challengePeriodEnd",
+			Help:      "Timestamp of the end of the challenge of the LivenessModule (v2), 0 when the safe is not challenged.",
+		}, []string{"safe"}),
+		contractNotInstalled: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "contractNotInstalled",
+			Help:      "CRITICAL: 1 if the LivenessGuard is not the guard of the safe anymore or if the LivenessModule is disabled, 0 otherwise.",
+		}, []string{"safe", "contract"}),
+		enabledModules: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "enabledModules",
+			Help:      "Number of modules enabled on the safe.",
+		}, []string{"safe"}),
+		unexpectedModules: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedModules",
+			Help:      "CRITICAL: number of modules enabled on the safe that are neither the LivenessModule nor in the allowlist.",
+		}, []string{"safe"}),
+		codeHashMismatch: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "codeHashMismatch",
+			Help:      "CRITICAL: 1 if the bytecode of the LivenessGuard or the LivenessModule doesn't match the expected codehash, 0 otherwise.",
+		}, []string{"safe", "contract"}),
+		pendingTransactions: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "pendingTransactions",
+			Help:      "Number of transactions queued for the safe in the Safe Transaction Service.",
+		}, []string{"safe"}),
+		pendingTransactionConfirmations: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "pendingTransactionConfirmations",
+			Help:      "Number of confirmations of a transaction queued for the safe in the Safe Transaction Service.",
+		}, []string{"safe", "nonce", "safeTxHash"}),
+		nonceGap: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "nonceGap",
+			Help:      "Number of nonces between the nonce of the safe and the lowest transaction queued, the queued transactions can't be executed when greater than 0.",
+		}, []string{"safe"}),
+		safeNonce: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "safeNonce",
+			Help:      "Nonce of the safe.",
+		}, []string{"safe"}),
+		pendingApprovedHashes: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "pendingApprovedHashes",
+			Help:      "Number of hashes approved through `ApproveHash` not executed yet.",
+		}, []string{"safe"}),
+		stuckExecution: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "stuckExecution",
+			Help:      "1 if an approved hash (reason `approveHash`) or a fully signed transaction (reason `fullySigned`) is not executed within the stuck execution duration, 0 otherwise.",
+		}, []string{"safe", "reason"}),
+		notifications: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "notifications",
+			Help:      "Number of notifications sent, by severity and notifier.",
+		}, []string{"safe", "severity", "notifier"}),
+		notifyFailures: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "notifyFailures",
+			Help:      "Number of notifications that failed to be sent, they are retried during the next iteration.",
+		}),
+		consecutiveFailures: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "consecutiveFailures",
+			Help:      "Number of consecutive iterations where the safe could not be read, the other metrics of the safe are not updated in this case.",
+		}, []string{"safe"}),
+		lastSuccessfulScrape: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "lastSuccessfulScrape",
+			Help:      "Unix timestamp of the last successful read of the safe, used to detect stale metrics.",
+		}, []string{"safe"}),
+		blockTimestamp: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "blockTimestamp",
+			Help:      "Timestamp of the block used to evaluate the invariant.",
+		}),
 	}, nil
 }
 
 // Run is the main loop of the monitor.
-// This loop will update the metrics `blockTimestamp`, `highestBlockNumber`, `lastLiveOfAOwner`, `intervalLiveness`.
+// This loop will update the metrics `blockTimestamp`, `highestBlockNumber`, `lastLiveOfAOwner`, `intervalLiveness` for every safe configured.
 // Thanks to these metrics we can monitor the liveness expiration through  (block.timestamp + BUFFER > lastLive(owner) + livenessInterval).
 // NOTE: 	// Liveness module mainnet  -> https://etherscan.io/address/0x0454092516c9A4d636d3CAfA1e82161376C8a748
 // Liveness guard mainnet  ->  https://etherscan.io/address/0x24424336F04440b1c28685a38303aC33C9D14a25
@@ -139,51 +528,138 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 // 2. livenessGuard.lastLive(owner)
 // 3. save the livenessInterval()
 // 4. Ensure that the invariant is not broken -> (block.timestamp + BUFFER > lastLive(owner) + livenessInterval) == true
-func (m *Monitor) Run(ctx context.Context) {
-	day := uint64(86400) // 1 day in seconds
-	blocknumber := new(big.Int)
-
-	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+// The calls 1. 2. 3. are batched into a single Multicall3 `aggregate3` pinned to the latest block, so the values are a consistent snapshot.
+func (m *chainMonitor) Run(ctx context.Context) {
+	// The header is fetched once, its number and timestamp are used for every safe of the iteration.
+	var blockNumber *big.Int // `nil` for the latest block.
+	if m.block != 0 {
+		blockNumber = new(big.Int).SetUint64(m.block)
+	}
+	header, err := m.l1Client.HeaderByNumber(ctx, blockNumber)
 	if err != nil {
-		m.log.Error("failed to query latest block number", "err", err)
-		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		m.log.Error("failed to query the method `HeaderByNumber`", "err", err, "blockNumber", blockNumber)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "HeaderByNumber").Inc()
 		return
 	}
+	latestL1Height := header.Number.Uint64()
+	now := header.Time
+	m.blockTimestamp.Set(float64(now))
+
+	for _, safe := range m.safes {
+		m.checkInstalledContracts(ctx, safe, latestL1Height)
+		m.checkCodeHashes(ctx, safe, latestL1Height)
+		m.checkModules(ctx, safe, latestL1Height)
+		m.checkSafe(ctx, safe, latestL1Height, now, 0)
+		m.checkOwnerEvents(ctx, safe, latestL1Height, now)
+		m.checkStuckApprovals(ctx, safe, now)
+		if len(m.safeTxServiceURL) > 0 {
+			m.checkPendingTransactions(ctx, safe, now)
+		}
+	}
 
-	blocknumber.SetUint64(uint64(latestL1Height))
-	blockTimestamp, err := m.l1Client.BlockByNumber(ctx, blocknumber)
+	m.highestBlockNumber.WithLabelValues("blockNumber").Set(float64(latestL1Height))
+	m.evaluated = true
+}
+
+// checkInstalledContracts ensures the LivenessGuard is still the guard of the safe and the LivenessModule is still enabled.
+// Otherwise, the values reported by the monitor are stale and the liveness of the owners is not enforced anymore.
+func (m *chainMonitor) checkInstalledContracts(ctx context.Context, safe *safeTarget, latestL1Height uint64) {
+	safeLabel := safe.label
+
+	blockNumber := new(big.Int).SetUint64(latestL1Height)
+
+	guardSlot, err := m.l1Client.StorageAt(ctx, safe.GnosisSafeAddress, GuardStorageSlot, blockNumber)
 	if err != nil {
-		m.log.Error("failed to query the method `BlockByNumber`", "err", err, "blockNumber", latestL1Height)
-		m.unexpectedRpcErrors.WithLabelValues("l1", "BlockByNumber").Inc()
-		return
+		m.log.Error("failed to query the guard storage slot", "err", err, "blockNumber", latestL1Height, "safe", safeLabel)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "StorageAt").Inc()
+	} else {
+		guard := common.BytesToAddress(guardSlot)
+		if guard != safe.LivenessGuardAddress {
+			m.log.Error("the LivenessGuard is not the guard of the safe anymore!", "safe", safeLabel, "expected_guard", safe.LivenessGuardAddress, "guard", guard)
+			m.contractNotInstalled.WithLabelValues(safeLabel, "guard").Set(1)
+		} else {
+			m.contractNotInstalled.WithLabelValues(safeLabel, "guard").Set(0)
+		}
+		alert := m.alert(ContractNotInstalledRule, "P0", safe, fmt.Sprintf("the LivenessGuard %s is not the guard of the safe %s anymore", safe.LivenessGuardAddress, safeLabel), safe.LivenessGuardAddress)
+		alert.Labels["guard"] = guard.String()
+		m.tracker.Update(ctx, alert, guard != safe.LivenessGuardAddress)
 	}
-	now := blockTimestamp.Time()
 
-	listOwners, err := m.GnosisSafe.GetOwners(nil) // 1. Get the list of owner from the safe.
+	enabled, err := safe.GnosisSafe.IsModuleEnabled(&bind.CallOpts{Context: ctx, BlockNumber: blockNumber}, safe.LivenessModuleAddress)
 	if err != nil {
-		m.log.Error("failed to query the method `GetOwners`", "err", err, "blockNumber", latestL1Height)
-		m.unexpectedRpcErrors.WithLabelValues("l1", "GetOwners").Inc()
-		return
+		m.log.Error("failed to query the method `IsModuleEnabled`", "err", err, "blockNumber", latestL1Height, "safe", safeLabel)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "IsModuleEnabled").Inc()
+	} else {
+		if !enabled {
+			m.log.Error("the LivenessModule is not enabled on the safe anymore!", "safe", safeLabel, "module", safe.LivenessModuleAddress)
+			m.contractNotInstalled.WithLabelValues(safeLabel, "module").Set(1)
+		} else {
+			m.contractNotInstalled.WithLabelValues(safeLabel, "module").Set(0)
+		}
+		m.tracker.Update(ctx, m.alert(ContractNotInstalledRule, "P0", safe, fmt.Sprintf("the LivenessModule %s is not enabled on the safe %s anymore", safe.LivenessModuleAddress, safeLabel), safe.LivenessModuleAddress), !enabled)
 	}
+}
+
+// checkSafe evaluates the liveness of every owner of a single safe.
+// When the nested safes are enabled, the owners that are safes are checked recursively up to `nestedDepth`.
+func (m *chainMonitor) checkSafe(ctx context.Context, safe *safeTarget, latestL1Height uint64, now uint64, depth uint64) {
+	day := uint64(86400) // 1 day in seconds
+	safeLabel := safe.label
 
-	interval, err := m.LivenessModule.LivenessInterval(nil) // 2. Get the interval from the liveness module.
+	// 1. 2. 3. Get the owners, the threshold, the interval and the last live of each owner through a single multicall.
+	snapshot, err := m.readSafeSnapshot(ctx, safe, new(big.Int).SetUint64(latestL1Height))
 	if err != nil {
-		m.log.Error("failed to query the method `LivenessInterval`", "err", err, "blockNumber", latestL1Height)
-		m.unexpectedRpcErrors.WithLabelValues("l1", "LivenessInterval").Inc()
+		// The metrics of the safe are left untouched, setting them with zero values would make the owners look expired.
+		safe.consecutiveFailures++
+		m.log.Error("failed to read the safe through Multicall3", "err", err, "blockNumber", latestL1Height, "safe", safeLabel, "consecutive_failures", safe.consecutiveFailures)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "Multicall3").Inc()
+		m.consecutiveFailures.WithLabelValues(safeLabel).Set(float64(safe.consecutiveFailures))
 		return
 	}
-	m.intervalLiveness.WithLabelValues("interval").Set(float64(interval.Uint64()))
+	safe.consecutiveFailures = 0
+	m.consecutiveFailures.WithLabelValues(safeLabel).Set(0)
+	m.lastSuccessfulScrape.WithLabelValues(safeLabel).Set(float64(time.Now().Unix()))
+	listOwners, threshold, interval := snapshot.owners, snapshot.threshold, snapshot.interval
 
+	m.checkOwnerSetChanges(ctx, safe, listOwners)
+	m.checkRoster(ctx, safe, listOwners)
+	if depth < m.nestedDepth {
+		m.checkNestedSafes(ctx, safe, listOwners, latestL1Height, now, depth)
+	}
+	m.checkThresholdChanges(ctx, safe, threshold)
+	m.checkNonce(safe, snapshot.nonce.Uint64(), now)
+	m.ownersCount.WithLabelValues(safeLabel).Set(float64(len(listOwners)))
+
+	if safe.LivenessGuard == nil || safe.LivenessModule == nil {
+		m.log.Info("no liveness contracts installed on the nested safe, only the owners and the threshold are monitored", "safe", safeLabel, "threshold", threshold, "Owners", listOwners)
+		return
+	}
+	m.checkIntervalChanges(ctx, safe, interval)
+	m.checkFallbackOwner(ctx, safe, snapshot.fallback)
+	if snapshot.minOwners != nil {
+		m.minOwners.WithLabelValues(safeLabel).Set(float64(snapshot.minOwners.Uint64()))
+	}
+	if snapshot.transferredToFallback {
+		m.raiseStatus(StatusExpired)
+		m.log.Error("the ownership of the safe has been transferred to the fallback owner!", "safe", safeLabel, "fallback_owner", snapshot.fallback)
+		m.ownershipTransferredToFallback.WithLabelValues(safeLabel).Set(1)
+	} else {
+		m.ownershipTransferredToFallback.WithLabelValues(safeLabel).Set(0)
+	}
+	alert := m.alert(FallbackOwnershipRule, "P0", safe, fmt.Sprintf("the ownership of the safe %s has been transferred to the fallback owner %s", safeLabel, snapshot.fallback))
+	alert.Labels["fallbackOwner"] = snapshot.fallback.String()
+	m.tracker.Update(ctx, alert, snapshot.transferredToFallback)
+	if snapshot.challengeStartTime != nil {
+		m.checkChallenge(ctx, safe, snapshot.challengeStartTime.Uint64(), interval.Uint64(), now)
+	}
+
+	ownersAtRisk := uint64(0)
 	for _, owner := range listOwners {
-		lastLive, err := m.LivenessGuard.LastLive(nil, owner) // 3. Get the last live from the liveness guard for each owner
+		name := m.resolveName(ctx, owner)
+		lastLive := snapshot.lastLive[owner]
 		big_deadline := big.NewInt(0)
-		if err != nil {
-			m.log.Error("failed to query the method `LastLive`", "err", err, "blockNumber", latestL1Height)
-			m.unexpectedRpcErrors.WithLabelValues("l1", "LastLive").Inc()
-			return
-		}
 
-		m.lastLiveOfAOwner.WithLabelValues(owner.String()).Set(float64(lastLive.Uint64()))
+		m.lastLiveOfAOwner.WithLabelValues(m.ownerLabels(safe, owner)...).Set(float64(lastLive.Uint64()))
 
 		big_deadline.Add(lastLive, interval)
 		deadline := big_deadline.Uint64()
@@ -193,37 +669,234 @@ func (m *Monitor) Run(ctx context.Context) {
 		// 4. Ensure that the invariant is not broken -> (block.timestamp + BUFFER > lastLive(owner) + livenessInterval) == true
 		remainingTime, borrow := bits.Sub64(deadline, now, 0)
 		if borrow != 0 {
-			m.log.Warn("`deadline - now` is negative means that the `owner` is not active anymore at all and should be removed fast! This is not suppose to happen because we will be intervening before ensure that is not happening", "deadline", deadline, "now", now, "owner", owner)
+			m.raiseStatus(StatusExpired)
+			m.log.Warn("`deadline - now` is negative means that the `owner` is not active anymore at all and should be removed fast! This is not suppose to happen because we will be intervening before ensure that is not happening", "deadline", deadline, "now", now, "owner", owner, "safe", safeLabel)
+		}
+
+		atRisk := isOwnerAtRisk(now, m.buffer, deadline)
+		alert := m.alert(OwnerAtRiskRule, "P1", safe, fmt.Sprintf("the liveness deadline of the owner %s of the safe %s is on %s", owner, safeLabel, formattedDate), owner)
+		alert.Labels["owner"], alert.Labels["member"], alert.Labels["name"] = owner.String(), safe.roster[owner], name
+		alert.Labels["deadline"] = deadline_date.UTC().Format(time.RFC3339)
+		m.tracker.Update(ctx, alert, atRisk)
+		if atRisk {
+			ownersAtRisk++
+			m.raiseStatus(StatusAtRisk)
+			m.log.Warn("owner is at risk, the liveness deadline is within the buffer", "safe", safeLabel, "owner", owner, "member", safe.roster[owner], "name", name, "now", now, "buffer", m.buffer, "deadline", deadline, "deadline_date", formattedDate)
+			m.ownerAtRisk.WithLabelValues(m.ownerLabels(safe, owner)...).Set(1)
+		} else {
+			m.ownerAtRisk.WithLabelValues(m.ownerLabels(safe, owner)...).Set(0)
 		}
 
 		days_left_before_deadline := remainingTime / day
 
-		m.log.Info("", "owner", owner, "now", now, "deadline", deadline, "lastlive", lastLive, "interval", interval, "deadline_date", formattedDate, "days_left_before_deadline", days_left_before_deadline)
-		m.ownerDaysBeforeDeadline.WithLabelValues(owner.String()).Set(float64(days_left_before_deadline))
+		m.log.Info("", "safe", safeLabel, "owner", owner, "member", safe.roster[owner], "name", name, "now", now, "deadline", deadline, "lastlive", lastLive, "lastLiveTxHash", safe.lastLiveTxHash[owner], "interval", interval, "deadline_date", formattedDate, "days_left_before_deadline", days_left_before_deadline)
+		m.ownerDaysBeforeDeadline.WithLabelValues(m.ownerLabels(safe, owner)...).Set(float64(days_left_before_deadline))
+		m.ownerSecondsBeforeDeadline.WithLabelValues(m.ownerLabels(safe, owner)...).Set(float64(deadline) - float64(now))
+		m.notifyExpiration(ctx, safe, owner, deadline, int64(deadline)-int64(now))
 
 		if remainingTime <= 1*day {
-			m.log.Info("deadline is less than 1 day we need to ensure that the owner is doing something in the last 24h otherwise we need to remove it!", "lastLive", lastLive, "owner", owner)
-			m.ownerStalePeriod.WithLabelValues(owner.String()).Set(float64(1))
+			m.log.Info("deadline is less than 1 day we need to ensure that the owner is doing something in the last 24h otherwise we need to remove it!", "lastLive", lastLive, "owner", owner, "safe", safeLabel)
+			m.ownerStalePeriod.WithLabelValues(m.ownerLabels(safe, owner)...).Set(float64(1))
 		} else if remainingTime <= 7*day {
-			m.log.Info("deadline is less than 7 days we need to ensure that the owner is doing something in the last 7 days otherwise we need to remove it!", "lastLive", lastLive, "owner", owner)
-			m.ownerStalePeriod.WithLabelValues(owner.String()).Set(float64(7))
+			m.log.Info("deadline is less than 7 days we need to ensure that the owner is doing something in the last 7 days otherwise we need to remove it!", "lastLive", lastLive, "owner", owner, "safe", safeLabel)
+			m.ownerStalePeriod.WithLabelValues(m.ownerLabels(safe, owner)...).Set(float64(7))
 
 		} else if remainingTime <= 14*day {
-			m.log.Info("deadline is less than 14 days we need to ensure that the owner is doing something in the last 14 days otherwise we need to remove it!", "lastLive", lastLive, "owner", owner)
-			m.ownerStalePeriod.WithLabelValues(owner.String()).Set(float64(14))
+			m.log.Info("deadline is less than 14 days we need to ensure that the owner is doing something in the last 14 days otherwise we need to remove it!", "lastLive", lastLive, "owner", owner, "safe", safeLabel)
+			m.ownerStalePeriod.WithLabelValues(m.ownerLabels(safe, owner)...).Set(float64(14))
 
 		} else { //If Owner is not stalling (most of the time) we set the metric to 0 for the owner because he is not stalling.
-			m.ownerStalePeriod.WithLabelValues(owner.String()).Set(float64(0))
+			m.ownerStalePeriod.WithLabelValues(m.ownerLabels(safe, owner)...).Set(float64(0))
 		}
 	}
 
-	m.log.Info("", "interval", interval, "Owners", listOwners, "SafeAddress", m.GnosisSafeAddress, "highestBlockNumber", latestL1Height)
+	unreachable := isThresholdUnreachable(threshold.Uint64(), uint64(len(listOwners)), ownersAtRisk)
+	alert = m.alert(ThresholdUnreachableRule, "P0", safe, fmt.Sprintf("the threshold %s of the safe %s is greater than the number of owners not at risk (%d owners, %d at risk)", threshold, safeLabel, len(listOwners), ownersAtRisk))
+	m.tracker.Update(ctx, alert, unreachable)
+	if unreachable {
+		m.log.Warn("the threshold is greater than the number of owners not at risk, the safe could be unable to act before the liveness expiration", "safe", safeLabel, "threshold", threshold, "owners", len(listOwners), "owners_at_risk", ownersAtRisk)
+		m.thresholdUnreachable.WithLabelValues(safeLabel).Set(1)
+	} else {
+		m.thresholdUnreachable.WithLabelValues(safeLabel).Set(0)
+	}
 
-	m.highestBlockNumber.WithLabelValues("blockNumber").Set(float64(latestL1Height))
+	// LivenessModule v2 when `minOwners` is not set, the owners are not removed one by one.
+	imminent := snapshot.minOwners != nil && isShutdownImminent(uint64(len(listOwners)), ownersAtRisk, snapshot.minOwners.Uint64())
+	alert = m.alert(ShutdownImminentRule, "P0", safe, fmt.Sprintf("removing the %d owners at risk would push the safe %s below its min owners, the ownership being transferred to the fallback owner", ownersAtRisk, safeLabel))
+	m.tracker.Update(ctx, alert, imminent)
+	if snapshot.minOwners == nil {
+		m.shutdownImminent.WithLabelValues(safeLabel).Set(0)
+	} else if imminent {
+		m.log.Warn("removing the owners at risk would push the safe below the min owners, the ownership would be transferred to the fallback owner", "safe", safeLabel, "owners", len(listOwners), "owners_at_risk", ownersAtRisk, "min_owners", snapshot.minOwners, "fallback_owner", snapshot.fallback)
+		m.shutdownImminent.WithLabelValues(safeLabel).Set(1)
+	} else {
+		m.shutdownImminent.WithLabelValues(safeLabel).Set(0)
+	}
+
+	m.log.Info("", "interval", interval, "threshold", threshold, "Owners", listOwners, "Safe", safeLabel, "highestBlockNumber", latestL1Height)
+}
+
+// checkChallenge exports the challenge of the LivenessModule v2, the safe has to respond before the end of the response period
+// otherwise the ownership is transferred to the fallback owner.
+func (m *chainMonitor) checkChallenge(ctx context.Context, safe *safeTarget, challengeStartTime uint64, responsePeriod uint64, now uint64) {
+	safeLabel := safe.label
+	end := challengeStartTime + responsePeriod
+	alert := m.alert(ChallengeActiveRule, "P1", safe, fmt.Sprintf("the safe %s is challenged by the fallback owner and has to respond before %s", safeLabel, time.Unix(int64(end), 0).UTC().Format(time.RFC3339)))
+	m.tracker.Update(ctx, alert, challengeStartTime != 0)
+	if challengeStartTime == 0 {
+		m.challengeActive.WithLabelValues(safeLabel).Set(0)
+		m.challengePeriodEnd.WithLabelValues(safeLabel).Set(0)
+		return
+	}
+
+	m.log.Warn("the safe is challenged by the fallback owner, a response is required before the end of the challenge period", "safe", safeLabel, "challenge_start_time", challengeStartTime, "challenge_period_end", end, "now", now)
+	m.challengeActive.WithLabelValues(safeLabel).Set(1)
+	m.challengePeriodEnd.WithLabelValues(safeLabel).Set(float64(end))
+	if now >= end {
+		m.raiseStatus(StatusExpired)
+	} else {
+		m.raiseStatus(StatusAtRisk)
+	}
+}
+
+// checkFallbackOwner exports the fallback owner of the LivenessModule, the previous label is removed when it changes.
+func (m *chainMonitor) checkFallbackOwner(ctx context.Context, safe *safeTarget, fallbackOwner common.Address) {
+	if safe.fallbackOwner != nil && *safe.fallbackOwner != fallbackOwner {
+		m.log.Warn("the fallback owner of the liveness module changed", "safe", safe.label, "previous_fallback_owner", safe.fallbackOwner, "fallback_owner", fallbackOwner)
+		alert := m.alert(FallbackOwnerChangedRule, "P1", safe, fmt.Sprintf("the fallback owner of the safe %s changed from %s to %s", safe.label, safe.fallbackOwner, fallbackOwner))
+		alert.Labels["previousFallbackOwner"], alert.Labels["fallbackOwner"] = safe.fallbackOwner.String(), fallbackOwner.String()
+		m.tracker.Emit(ctx, alert)
+		m.fallbackOwner.DeleteLabelValues(safe.label, safe.fallbackOwner.String())
+	}
+	m.fallbackOwner.WithLabelValues(safe.label, fallbackOwner.String()).Set(1)
+	safe.fallbackOwner = &fallbackOwner
+}
+
+// isShutdownImminent returns true when removing the owners at risk would leave less than `minOwners` owners.
+// The LivenessModule then transfers the ownership of the safe to the fallback owner.
+func isShutdownImminent(owners uint64, ownersAtRisk uint64, minOwners uint64) bool {
+	if ownersAtRisk == 0 {
+		return false
+	}
+	if ownersAtRisk > owners {
+		return true
+	}
+	return owners-ownersAtRisk < minOwners
+}
+
+// checkThresholdChanges compares the threshold of the safe with the one observed during the previous iteration.
+func (m *chainMonitor) checkThresholdChanges(ctx context.Context, safe *safeTarget, threshold *big.Int) {
+	safeLabel := safe.label
+	m.threshold.WithLabelValues(safeLabel).Set(float64(threshold.Uint64()))
+	m.thresholdChanges.WithLabelValues(safeLabel).Add(0)
+	if safe.threshold != nil && safe.threshold.Cmp(threshold) != 0 {
+		m.log.Warn("the threshold of the safe changed", "safe", safeLabel, "previous_threshold", safe.threshold, "threshold", threshold)
+		m.tracker.Emit(ctx, m.alert(ThresholdChangedRule, "P1", safe, fmt.Sprintf("the threshold of the safe %s changed from %s to %s", safeLabel, safe.threshold, threshold)))
+		m.thresholdChanges.WithLabelValues(safeLabel).Inc()
+	}
+	safe.threshold = threshold
+}
+
+// checkIntervalChanges compares the liveness interval with the one observed during the previous iteration.
+// The interval is the safety margin of the owners, a change is expected to be a reviewed governance action.
+func (m *chainMonitor) checkIntervalChanges(ctx context.Context, safe *safeTarget, interval *big.Int) {
+	safeLabel := safe.label
+	m.intervalLiveness.WithLabelValues(safeLabel).Set(float64(interval.Uint64()))
+	m.intervalChanges.WithLabelValues(safeLabel).Add(0)
+	if safe.interval != nil && safe.interval.Cmp(interval) != 0 {
+		m.log.Warn("the liveness interval of the liveness module changed", "safe", safeLabel, "previous_interval", safe.interval, "interval", interval)
+		m.tracker.Emit(ctx, m.alert(IntervalChangedRule, "P1", safe, fmt.Sprintf("the liveness interval of the safe %s changed from %ss to %ss", safeLabel, safe.interval, interval)))
+		m.intervalChanges.WithLabelValues(safeLabel).Inc()
+	}
+	safe.interval = interval
+}
+
+// isThresholdUnreachable returns true when the owners not at risk are not enough to reach the threshold.
+func isThresholdUnreachable(threshold uint64, owners uint64, ownersAtRisk uint64) bool {
+	if ownersAtRisk > owners {
+		return true
+	}
+	return threshold > owners-ownersAtRisk
+}
+
+// checkOwnerSetChanges compares the owners of the safe with the ones observed during the previous iteration.
+// An unexpected owner churn on a Security Council safe is a critical signal.
+func (m *chainMonitor) checkOwnerSetChanges(ctx context.Context, safe *safeTarget, owners []common.Address) {
+	safeLabel := safe.label
+	if safe.owners != nil {
+		added, removed := diffOwners(safe.owners, owners)
+		for _, owner := range added {
+			m.log.Warn("a new owner has been added to the safe", "safe", safeLabel, "owner", owner)
+			m.tracker.Emit(ctx, m.alert(OwnerAddedRule, "P1", safe, fmt.Sprintf("the owner %s has been added to the safe %s", owner, safeLabel), owner))
+			m.ownerSetChanges.WithLabelValues(safeLabel, "added").Inc()
+		}
+		for _, owner := range removed {
+			m.log.Warn("an owner has been removed from the safe", "safe", safeLabel, "owner", owner)
+			m.tracker.Emit(ctx, m.alert(OwnerRemovedRule, "P1", safe, fmt.Sprintf("the owner %s has been removed from the safe %s", owner, safeLabel), owner))
+			m.ownerSetChanges.WithLabelValues(safeLabel, "removed").Inc()
+			m.deleteOwnerMetrics(safe, owner)
+			// the alerts of the owner are resolved, its conditions not being checked anymore.
+			m.tracker.Update(ctx, m.alert(OwnerAtRiskRule, "P1", safe, fmt.Sprintf("the owner %s has been removed from the safe %s", owner, safeLabel), owner), false)
+			m.tracker.Update(ctx, m.alert(UnexpectedOwnerRule, "P1", safe, fmt.Sprintf("the owner %s has been removed from the safe %s", owner, safeLabel), owner), false)
+		}
+	} else { // first iteration, we emit the metrics with the values set to `0`.
+		m.ownerSetChanges.WithLabelValues(safeLabel, "added").Add(0)
+		m.ownerSetChanges.WithLabelValues(safeLabel, "removed").Add(0)
+		m.removedOwners.WithLabelValues(safeLabel).Add(0)
+	}
+	safe.owners = owners
+}
+
+// deleteOwnerMetrics deletes the series of an owner removed from the safe, so the dashboards and the alerts don't keep reporting it.
+func (m *chainMonitor) deleteOwnerMetrics(safe *safeTarget, owner common.Address) {
+	if labels, ok := safe.ownerSeries[owner]; ok {
+		m.deleteOwnerSeries(labels)
+		delete(safe.ownerSeries, owner)
+	}
+	delete(safe.lastLiveTxHash, owner)
+	delete(safe.notifiedLevel, owner)
+	m.removedOwners.WithLabelValues(safe.label).Inc()
+}
+
+// deleteOwnerSeries deletes the series of an owner with the label values they were set with.
+func (m *chainMonitor) deleteOwnerSeries(labels []string) {
+	m.lastLiveOfAOwner.DeleteLabelValues(labels...)
+	m.ownerDaysBeforeDeadline.DeleteLabelValues(labels...)
+	m.ownerSecondsBeforeDeadline.DeleteLabelValues(labels...)
+	m.ownerStalePeriod.DeleteLabelValues(labels...)
+	m.ownerAtRisk.DeleteLabelValues(labels...)
+	m.ownerRecordedEvents.DeleteLabelValues(labels...)
+}
+
+// diffOwners returns the owners present in `current` but not in `previous` (added) and the ones present in `previous` but not in `current` (removed).
+func diffOwners(previous, current []common.Address) (added, removed []common.Address) {
+	previousSet := make(map[common.Address]bool, len(previous))
+	for _, owner := range previous {
+		previousSet[owner] = true
+	}
+	currentSet := make(map[common.Address]bool, len(current))
+	for _, owner := range current {
+		currentSet[owner] = true
+		if !previousSet[owner] {
+			added = append(added, owner)
+		}
+	}
+	for _, owner := range previous {
+		if !currentSet[owner] {
+			removed = append(removed, owner)
+		}
+	}
+	return added, removed
+}
+
+// isOwnerAtRisk evaluates the invariant `block.timestamp + buffer > lastLive(owner) + livenessInterval`.
+// The deadline is expected to be `lastLive(owner) + livenessInterval`.
+func isOwnerAtRisk(now uint64, buffer uint64, deadline uint64) bool {
+	return now+buffer > deadline
 }
 
 // Close closes the monitor.
-func (m *Monitor) Close(_ context.Context) error {
+func (m *chainMonitor) Close(_ context.Context) error {
 	m.l1Client.Close()
 	return nil
 }
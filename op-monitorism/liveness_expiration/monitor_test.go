@@ -0,0 +1,670 @@
+package liveness_expiration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opbindings "github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var (
+	testSafe      = common.HexToAddress("0xc2819DC788505Aac350142A7A707BF9D03E3Bd03")
+	testGuard     = common.HexToAddress("0x24424336F04440b1c28685a38303aC33C9D14a25")
+	testModule    = common.HexToAddress("0x0454092516c9A4d636d3CAfA1e82161376C8a748")
+	testMulticall = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+	ownerA = common.HexToAddress("0x0A")
+	ownerB = common.HexToAddress("0x0B")
+	ownerC = common.HexToAddress("0x0C")
+	ownerD = common.HexToAddress("0x0D")
+)
+
+func TestIsOwnerAtRisk(t *testing.T) {
+	day := uint64(86400)
+	tests := []struct {
+		name     string
+		now      uint64
+		buffer   uint64
+		deadline uint64
+		expected bool
+	}{
+		{
+			name:     "Deadline far away",
+			now:      1_000_000,
+			buffer:   14 * day,
+			deadline: 1_000_000 + 30*day,
+			expected: false,
+		},
+		{
+			name:     "Deadline within the buffer",
+			now:      1_000_000,
+			buffer:   14 * day,
+			deadline: 1_000_000 + 7*day,
+			expected: true,
+		},
+		{
+			name:     "Deadline exactly at the end of the buffer",
+			now:      1_000_000,
+			buffer:   14 * day,
+			deadline: 1_000_000 + 14*day,
+			expected: false,
+		},
+		{
+			name:     "Deadline already expired",
+			now:      1_000_000,
+			buffer:   0,
+			deadline: 1_000_000 - day,
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isOwnerAtRisk(test.now, test.buffer, test.deadline)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %v but got %v", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestDiffOwners(t *testing.T) {
+	a := common.HexToAddress("0x01")
+	b := common.HexToAddress("0x02")
+	c := common.HexToAddress("0x03")
+
+	added, removed := diffOwners([]common.Address{a, b}, []common.Address{b, c})
+	if len(added) != 1 || added[0] != c {
+		t.Errorf("expected %s to be added but got %v", c, added)
+	}
+	if len(removed) != 1 || removed[0] != a {
+		t.Errorf("expected %s to be removed but got %v", a, removed)
+	}
+
+	added, removed = diffOwners([]common.Address{a, b}, []common.Address{b, a})
+	if len(added) != 0 || len(removed) != 0 {
+		t.Errorf("expected no changes but got added %v and removed %v", added, removed)
+	}
+}
+
+func TestDeleteOwnerMetrics(t *testing.T) {
+	ownerVec := func(name string) *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name}, []string{"safe", "owner", "member", "name"})
+	}
+	m := &chainMonitor{
+		addressBook:                map[common.Address]string{ownerA: "Old name"},
+		lastLiveOfAOwner:           ownerVec("lastLiveOfAOwner"),
+		ownerStalePeriod:           ownerVec("ownerStalePeriod"),
+		ownerDaysBeforeDeadline:    ownerVec("ownerDaysBeforeDeadline"),
+		ownerSecondsBeforeDeadline: ownerVec("ownerSecondsBeforeDeadline"),
+		ownerAtRisk:                ownerVec("ownerAtRisk"),
+		ownerRecordedEvents:        prometheus.NewCounterVec(prometheus.CounterOpts{Name: "ownerRecordedEvents"}, []string{"safe", "owner", "member", "name"}),
+		removedOwners:              prometheus.NewCounterVec(prometheus.CounterOpts{Name: "removedOwners"}, []string{"safe"}),
+	}
+	safe := &safeTarget{label: "safe"}
+
+	// a new name replaces the series set with the previous one.
+	m.ownerAtRisk.WithLabelValues(m.ownerLabels(safe, ownerA)...).Set(1)
+	m.addressBook[ownerA] = "New name"
+	m.ownerAtRisk.WithLabelValues(m.ownerLabels(safe, ownerA)...).Set(1)
+	if count, value := testutil.CollectAndCount(m.ownerAtRisk), testutil.ToFloat64(m.ownerAtRisk.WithLabelValues("safe", ownerA.String(), "", "New name")); count != 1 || value != 1 {
+		t.Errorf("expected the series of the new name only but got %d series and %v", count, value)
+	}
+
+	// the series of a removed owner are deleted with the values they were set with.
+	m.addressBook[ownerA] = "Another name"
+	m.deleteOwnerMetrics(safe, ownerA)
+	if count, removed := testutil.CollectAndCount(m.ownerAtRisk), testutil.ToFloat64(m.removedOwners.WithLabelValues("safe")); count != 0 || removed != 1 {
+		t.Errorf("expected the series of the removed owner deleted but got %d series and %v removed owners", count, removed)
+	}
+}
+
+func TestIsThresholdUnreachable(t *testing.T) {
+	tests := []struct {
+		name         string
+		threshold    uint64
+		owners       uint64
+		ownersAtRisk uint64
+		expected     bool
+	}{
+		{name: "No owner at risk", threshold: 10, owners: 13, ownersAtRisk: 0, expected: false},
+		{name: "Enough owners not at risk", threshold: 10, owners: 13, ownersAtRisk: 3, expected: false},
+		{name: "Not enough owners not at risk", threshold: 10, owners: 13, ownersAtRisk: 4, expected: true},
+		{name: "Every owner at risk", threshold: 1, owners: 2, ownersAtRisk: 2, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isThresholdUnreachable(test.threshold, test.owners, test.ownersAtRisk)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %v but got %v", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestIsShutdownImminent(t *testing.T) {
+	tests := []struct {
+		name         string
+		owners       uint64
+		ownersAtRisk uint64
+		minOwners    uint64
+		expected     bool
+	}{
+		{name: "No owner at risk", owners: 8, ownersAtRisk: 0, minOwners: 8, expected: false},
+		{name: "Enough owners remaining", owners: 13, ownersAtRisk: 5, minOwners: 8, expected: false},
+		{name: "Below the min owners", owners: 13, ownersAtRisk: 6, minOwners: 8, expected: true},
+		{name: "Every owner at risk", owners: 2, ownersAtRisk: 2, minOwners: 1, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isShutdownImminent(test.owners, test.ownersAtRisk, test.minOwners)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %v but got %v", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestNonceGap(t *testing.T) {
+	tests := []struct {
+		name     string
+		nonce    uint64
+		pending  []uint64
+		expected uint64
+	}{
+		{name: "No pending transaction", nonce: 10, pending: nil, expected: 0},
+		{name: "Next nonce pending", nonce: 10, pending: []uint64{11, 10}, expected: 0},
+		{name: "Missing nonces", nonce: 10, pending: []uint64{13, 12}, expected: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pending := make([]pendingTransaction, len(test.pending))
+			for i, nonce := range test.pending {
+				pending[i].Nonce = nonce
+			}
+			output := nonceGap(test.nonce, pending)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %d but got %d", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestIsStuck(t *testing.T) {
+	tests := []struct {
+		name     string
+		since    uint64
+		now      uint64
+		duration uint64
+		expected bool
+	}{
+		{name: "Within the duration", since: 1_000, now: 1_500, duration: 1_000, expected: false},
+		{name: "Exactly the duration", since: 1_000, now: 2_000, duration: 1_000, expected: false},
+		{name: "After the duration", since: 1_000, now: 2_001, duration: 1_000, expected: true},
+		{name: "In the future", since: 3_000, now: 2_000, duration: 0, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isStuck(test.since, test.now, test.duration)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %v but got %v", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestUnexpectedModules(t *testing.T) {
+	livenessModule := common.HexToAddress("0x01")
+	allowed := common.HexToAddress("0x02")
+	unknown := common.HexToAddress("0x03")
+
+	unexpected := unexpectedModules([]common.Address{livenessModule, allowed}, livenessModule, []common.Address{allowed})
+	if len(unexpected) != 0 {
+		t.Errorf("expected no unexpected module but got %v", unexpected)
+	}
+
+	unexpected = unexpectedModules([]common.Address{livenessModule, unknown}, livenessModule, []common.Address{allowed})
+	if len(unexpected) != 1 || unexpected[0] != unknown {
+		t.Errorf("expected %s to be unexpected but got %v", unknown, unexpected)
+	}
+}
+
+func TestEscalationLevel(t *testing.T) {
+	thresholds, err := ParseNotifyThresholds([]string{"critical=48h", "medium=336h", "high=168h"})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if thresholds[0].Severity != "medium" || thresholds[2].Severity != "critical" {
+		t.Fatalf("expected the thresholds to be sorted from the least to the most severe but got %v", thresholds)
+	}
+
+	day := int64(86400)
+	tests := []struct {
+		name      string
+		remaining int64
+		expected  int
+	}{
+		{name: "Far from the deadline", remaining: 30 * day, expected: -1},
+		{name: "Within 14 days", remaining: 10 * day, expected: 0},
+		{name: "Within 7 days", remaining: 7 * day, expected: 1},
+		{name: "Within 48 hours", remaining: day, expected: 2},
+		{name: "Expired", remaining: -day, expected: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := escalationLevel(thresholds, test.remaining)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %d but got %d", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+// safeNode serves a safe with its LivenessGuard, its LivenessModule v1 (30 days interval, 2 min owners) and the Multicall3
+// at the block of timestamp `now`.
+type safeNode struct {
+	*fake.Node
+
+	owners     []common.Address
+	threshold  int64
+	interval   int64 // the liveness interval of the module, in seconds.
+	lastLive   map[common.Address]uint64
+	guard      common.Address
+	modules    []common.Address
+	moduleCode []byte
+	failing    bool // the Multicall3 reverting.
+}
+
+func newSafeNode(t *testing.T, now uint64) *safeNode {
+	n := &safeNode{Node: fake.NewNode(t), owners: []common.Address{ownerA, ownerB, ownerC}, threshold: 2, interval: 30 * 86400, guard: testGuard, modules: []common.Address{testModule}, moduleCode: []byte{0x60, 0x01}}
+	n.lastLive = map[common.Address]uint64{ownerA: now, ownerB: now, ownerC: now, ownerD: now}
+	n.AddBlock(&types.Header{Time: now})
+	client, err := ethclient.Dial(n.URL)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	n.Contract(testSafe, gnosisSafeABI).
+		Handle("getOwners", func(_ []interface{}) ([]interface{}, error) { return []interface{}{n.owners}, nil }).
+		Handle("getThreshold", func(_ []interface{}) ([]interface{}, error) { return []interface{}{big.NewInt(n.threshold)}, nil }).
+		Returns("nonce", big.NewInt(1)).
+		Handle("isModuleEnabled", func(args []interface{}) ([]interface{}, error) {
+			for _, module := range n.modules {
+				if module == args[0].(common.Address) {
+					return []interface{}{true}, nil
+				}
+			}
+			return []interface{}{false}, nil
+		}).
+		Handle("getModulesPaginated", func(_ []interface{}) ([]interface{}, error) { return []interface{}{n.modules, SentinelModules}, nil })
+	n.Contract(testGuard, livenessGuardABI).Handle("lastLive", func(args []interface{}) ([]interface{}, error) {
+		return []interface{}{new(big.Int).SetUint64(n.lastLive[args[0].(common.Address)])}, nil
+	})
+	n.Contract(testModule, livenessModuleABI).
+		Handle("livenessInterval", func(_ []interface{}) ([]interface{}, error) { return []interface{}{big.NewInt(n.interval)}, nil }).
+		Returns("minOwners", big.NewInt(2)).
+		Returns("fallbackOwner", common.HexToAddress("0x0F")).
+		Returns("ownershipTransferredToFallback", false)
+	// the calls of the multicall are forwarded to the contracts of the node.
+	n.Contract(testMulticall, util.MustParseABI(opbindings.MultiCall3MetaData)).Handle("aggregate3", func(args []interface{}) ([]interface{}, error) {
+		if n.failing {
+			return nil, errors.New("execution reverted")
+		}
+		calls := *abi.ConvertType(args[0], new([]opbindings.Multicall3Call3)).(*[]opbindings.Multicall3Call3)
+		results := make([]opbindings.Multicall3Result, len(calls))
+		for i, call := range calls {
+			output, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &call.Target, Data: call.CallData}, nil)
+			results[i] = opbindings.Multicall3Result{Success: err == nil, ReturnData: output}
+		}
+		return []interface{}{results}, nil
+	})
+
+	n.Handle("eth_getStorageAt", func(params []json.RawMessage) (interface{}, error) {
+		var address common.Address
+		if err := fake.Param(params, 0, &address); err != nil {
+			return nil, err
+		}
+		if address != testSafe { // the other safes have no guard.
+			return common.Hash{}, nil
+		}
+		return common.BytesToHash(n.guard.Bytes()), nil
+	})
+	n.Handle("eth_getCode", func(params []json.RawMessage) (interface{}, error) {
+		var address common.Address
+		if err := fake.Param(params, 0, &address); err != nil {
+			return nil, err
+		}
+		if address == testModule {
+			return hexutil.Bytes(n.moduleCode), nil
+		}
+		return hexutil.Bytes{0x60, 0x02}, nil
+	})
+	return n
+}
+
+// newTestMonitor returns the monitor of the safe of the node with a buffer of 7 days and the roster of the owners A, B and
+// C, the options being applied to its configuration, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *safeNode, options ...func(*CLIConfig)) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{
+		L1NodeURL:              node.URL,
+		Safes:                  []SafeConfig{{SafeAddress: testSafe, LivenessGuardAddress: testGuard, LivenessModuleAddress: testModule, Owners: []RosterMember{{Address: ownerA, Name: "Member A"}, {Address: ownerB, Name: "Member B"}, {Address: ownerC, Name: "Member C"}}}},
+		Multicall3Address:      testMulticall,
+		EventBlockRange:        100,
+		StuckExecutionDuration: time.Hour,
+		Buffer:                 7 * 24 * time.Hour,
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRunAlerts(t *testing.T) {
+	day := uint64(86400)
+	now := 100 * day
+	node := newSafeNode(t, now)
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// an owner within the buffer of its deadline fires once.
+	node.lastLive[ownerA] = now - 25*day
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(OwnerAtRiskRule); len(alerts) != 1 || alerts[0].Entity != testSafe.String()+"/"+ownerA.String() || alerts[0].Priority != "P1" || alerts[0].Labels["member"] != "Member A" {
+		t.Fatalf("expected the owner at risk alert once but got %v", alerts)
+	}
+	if alerts := append(notifier.Alerts(ThresholdUnreachableRule), notifier.Alerts(ShutdownImminentRule)...); len(alerts) != 0 {
+		t.Fatalf("expected the threshold to be reachable but got %v", alerts)
+	}
+
+	// a second owner at risk leaves the threshold unreachable and the shutdown imminent, resolved once both are live.
+	node.lastLive[ownerB] = now - 25*day
+	m.Run(ctx)
+	if len(notifier.Firing(ThresholdUnreachableRule)) != 1 || len(notifier.Firing(ShutdownImminentRule)) != 1 {
+		t.Fatalf("expected the threshold unreachable and the shutdown imminent alerts but got %v", notifier.Alerts(""))
+	}
+	node.lastLive[ownerA], node.lastLive[ownerB] = now, now
+	m.Run(ctx)
+	for _, rule := range []string{OwnerAtRiskRule, ThresholdUnreachableRule, ShutdownImminentRule} {
+		if firing := notifier.Firing(rule); len(firing) != 0 {
+			t.Fatalf("expected the %s alerts resolved but got %v", rule, firing)
+		}
+	}
+
+	// a replaced guard, a disabled LivenessModule and a new module bytecode fire until restored.
+	node.guard, node.modules, node.moduleCode = common.HexToAddress("0x01"), nil, []byte{0x60, 0x03}
+	m.Run(ctx)
+	if firing := notifier.Firing(ContractNotInstalledRule); len(firing) != 2 {
+		t.Fatalf("expected the guard and the module not installed but got %v", firing)
+	}
+	if firing := notifier.Firing(CodeHashMismatchRule); len(firing) != 1 || firing[0] != testSafe.String()+"/"+testModule.String() {
+		t.Fatalf("expected the codehash mismatch of the module but got %v", firing)
+	}
+	node.guard, node.modules, node.moduleCode = testGuard, []common.Address{testModule}, []byte{0x60, 0x01}
+	m.Run(ctx)
+	if firing := append(notifier.Firing(ContractNotInstalledRule), notifier.Firing(CodeHashMismatchRule)...); len(firing) != 0 {
+		t.Fatalf("expected the contract alerts resolved but got %v", firing)
+	}
+
+	// an unknown module fires until disabled.
+	node.modules = []common.Address{testModule, ownerD}
+	m.Run(ctx)
+	if alerts := notifier.Alerts(UnexpectedModuleRule); len(alerts) != 1 || alerts[0].Entity != testSafe.String()+"/"+ownerD.String() || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the unexpected module alert but got %v", alerts)
+	}
+	node.modules = []common.Address{testModule}
+	m.Run(ctx)
+	if firing := notifier.Firing(UnexpectedModuleRule); len(firing) != 0 {
+		t.Fatalf("expected the unexpected module alert resolved but got %v", firing)
+	}
+
+	// an owner swapped out of the roster along with a new threshold emits the changes once and fires the roster drift.
+	node.owners, node.threshold = []common.Address{ownerA, ownerB, ownerD}, 3
+	m.Run(ctx)
+	m.Run(ctx)
+	for _, rule := range []string{OwnerAddedRule, OwnerRemovedRule, ThresholdChangedRule} {
+		if alerts := notifier.Alerts(rule); len(alerts) != 1 {
+			t.Fatalf("expected the %s alert once but got %v", rule, alerts)
+		}
+	}
+	if firing := notifier.Firing(UnexpectedOwnerRule); len(firing) != 1 || firing[0] != testSafe.String()+"/"+ownerD.String() {
+		t.Fatalf("expected the owner not in the roster alert but got %v", firing)
+	}
+	if firing := notifier.Firing(MissingOwnerRule); len(firing) != 1 || firing[0] != testSafe.String()+"/"+ownerC.String() {
+		t.Fatalf("expected the roster member not an owner alert but got %v", firing)
+	}
+}
+
+func TestCheckInstalledContracts(t *testing.T) {
+	tests := []struct {
+		name           string
+		guard          common.Address
+		modules        []common.Address
+		expectedGuard  float64
+		expectedModule float64
+	}{
+		{name: "Installed", guard: testGuard, modules: []common.Address{testModule}},
+		{name: "Guard replaced", guard: common.HexToAddress("0x01"), modules: []common.Address{testModule}, expectedGuard: 1},
+		{name: "Guard removed", modules: []common.Address{testModule}, expectedGuard: 1},
+		{name: "Module disabled", guard: testGuard, expectedModule: 1},
+		{name: "Module replaced", guard: testGuard, modules: []common.Address{common.HexToAddress("0x02")}, expectedModule: 1},
+		{name: "Both uninstalled", expectedGuard: 1, expectedModule: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := newSafeNode(t, 100*86400)
+			m, notifier := newTestMonitor(t, node)
+			node.guard, node.modules = test.guard, test.modules
+			m.Run(context.Background())
+
+			chain := m.chains[0]
+			guard, module := testutil.ToFloat64(chain.contractNotInstalled.WithLabelValues(testSafe.String(), "guard")), testutil.ToFloat64(chain.contractNotInstalled.WithLabelValues(testSafe.String(), "module"))
+			if guard != test.expectedGuard || module != test.expectedModule {
+				t.Errorf("Failed %s: expected the guard %v and the module %v not installed but got %v and %v", test.name, test.expectedGuard, test.expectedModule, guard, module)
+			}
+			if firing := notifier.Firing(ContractNotInstalledRule); len(firing) != int(test.expectedGuard+test.expectedModule) {
+				t.Errorf("Failed %s: expected %v alerts but got %v", test.name, test.expectedGuard+test.expectedModule, firing)
+			}
+		})
+	}
+}
+
+func TestRunFailures(t *testing.T) {
+	now := uint64(100 * 86400)
+	tests := []struct {
+		name             string
+		runs             []bool // failing runs after a successful one.
+		expectedFailures float64
+	}{
+		{name: "No failure", runs: []bool{false}, expectedFailures: 0},
+		{name: "Single failure", runs: []bool{true}, expectedFailures: 1},
+		{name: "Consecutive failures", runs: []bool{true, true, true}, expectedFailures: 3},
+		{name: "Recovered", runs: []bool{true, true, false}, expectedFailures: 0},
+		{name: "Failure after a recovery", runs: []bool{true, false, true}, expectedFailures: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := newSafeNode(t, now)
+			node.lastLive[ownerA] = now - 86400
+			m, _ := newTestMonitor(t, node)
+			chain := m.chains[0]
+			m.Run(context.Background())
+
+			// the owner looks live again, its metrics only change on a successful read.
+			node.lastLive[ownerA] = now
+			for _, failing := range test.runs {
+				node.failing = failing
+				m.Run(context.Background())
+			}
+
+			if failures := testutil.ToFloat64(chain.consecutiveFailures.WithLabelValues(testSafe.String())); failures != test.expectedFailures {
+				t.Errorf("Failed %s: expected %v consecutive failures but got %v", test.name, test.expectedFailures, failures)
+			}
+			expected := now - 86400
+			for _, failing := range test.runs {
+				if !failing {
+					expected = now
+				}
+			}
+			if lastLive := testutil.ToFloat64(chain.lastLiveOfAOwner.WithLabelValues(testSafe.String(), ownerA.String(), "Member A", "")); lastLive != float64(expected) {
+				t.Errorf("Failed %s: expected the last live %d but got %v", test.name, expected, lastLive)
+			}
+			if scrape := testutil.ToFloat64(chain.lastSuccessfulScrape.WithLabelValues(testSafe.String())); scrape == 0 {
+				t.Errorf("Failed %s: expected the last successful scrape to be kept but got %v", test.name, scrape)
+			}
+		})
+	}
+}
+
+func TestRunRemovedOwners(t *testing.T) {
+	tests := []struct {
+		name            string
+		owners          []common.Address // owners after the first iteration.
+		expectedRemoved float64
+	}{
+		{name: "No change", owners: []common.Address{ownerA, ownerB, ownerC}},
+		{name: "Owner removed", owners: []common.Address{ownerA, ownerB}, expectedRemoved: 1},
+		{name: "Owners removed", owners: []common.Address{ownerA}, expectedRemoved: 2},
+		{name: "Owner replaced", owners: []common.Address{ownerA, ownerB, ownerD}, expectedRemoved: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := newSafeNode(t, 100*86400)
+			m, _ := newTestMonitor(t, node)
+			chain := m.chains[0]
+			m.Run(context.Background())
+			node.owners = test.owners
+			m.Run(context.Background())
+
+			if removed := testutil.ToFloat64(chain.removedOwners.WithLabelValues(testSafe.String())); removed != test.expectedRemoved {
+				t.Errorf("Failed %s: expected %v removed owners but got %v", test.name, test.expectedRemoved, removed)
+			}
+			for _, vec := range []*prometheus.GaugeVec{chain.lastLiveOfAOwner, chain.ownerAtRisk, chain.ownerDaysBeforeDeadline, chain.ownerSecondsBeforeDeadline, chain.ownerStalePeriod} {
+				if count := testutil.CollectAndCount(vec); count != len(test.owners) {
+					t.Errorf("Failed %s: expected the series of %d owners but got %d", test.name, len(test.owners), count)
+				}
+			}
+			// the series left are the ones of the current owners.
+			for _, owner := range test.owners {
+				if count := chain.ownerAtRisk.DeletePartialMatch(prometheus.Labels{"owner": owner.String()}); count != 1 {
+					t.Errorf("Failed %s: expected the series of the owner %s but got %d", test.name, owner, count)
+				}
+			}
+		})
+	}
+}
+
+func TestRunIntervalChanges(t *testing.T) {
+	day := int64(86400)
+	tests := []struct {
+		name            string
+		intervals       []int64 // intervals of the successive iterations.
+		expectedChanges float64
+	}{
+		{name: "Interval unchanged", intervals: []int64{30 * day, 30 * day}, expectedChanges: 0},
+		{name: "Interval shortened", intervals: []int64{30 * day, 20 * day}, expectedChanges: 1},
+		{name: "Interval lengthened", intervals: []int64{30 * day, 40 * day}, expectedChanges: 1},
+		{name: "Interval changed back", intervals: []int64{30 * day, 20 * day, 30 * day}, expectedChanges: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := newSafeNode(t, 100*86400)
+			m, notifier := newTestMonitor(t, node)
+			chain := m.chains[0]
+			for _, interval := range test.intervals {
+				node.interval = interval
+				m.Run(context.Background())
+			}
+
+			if changes := testutil.ToFloat64(chain.intervalChanges.WithLabelValues(testSafe.String())); changes != test.expectedChanges {
+				t.Errorf("Failed %s: expected %v interval changes but got %v", test.name, test.expectedChanges, changes)
+			}
+			last := test.intervals[len(test.intervals)-1]
+			if interval := testutil.ToFloat64(chain.intervalLiveness.WithLabelValues(testSafe.String())); interval != float64(last) {
+				t.Errorf("Failed %s: expected the interval %d but got %v", test.name, last, interval)
+			}
+			alerts := notifier.Alerts(IntervalChangedRule)
+			if float64(len(alerts)) != test.expectedChanges {
+				t.Errorf("Failed %s: expected %v interval changed alerts but got %v", test.name, test.expectedChanges, alerts)
+			}
+			for _, alert := range alerts {
+				if alert.Entity != testSafe.String() || alert.Priority != "P1" {
+					t.Errorf("Failed %s: expected a P1 alert of the safe but got %v", test.name, alert)
+				}
+			}
+		})
+	}
+}
+
+func TestRunBlockTimestamp(t *testing.T) {
+	day := uint64(86400)
+	now := 100 * day
+	tests := []struct {
+		name              string
+		block             uint64 // the block pinned by the configuration, 0 to follow the latest block.
+		expectedTimestamp uint64
+	}{
+		{name: "Latest block", block: 0, expectedTimestamp: now + 2*day},
+		{name: "Pinned block", block: 1, expectedTimestamp: now + day},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := newSafeNode(t, now)
+			node.AddBlock(&types.Header{Time: now + day})
+			node.AddBlock(&types.Header{Time: now + 2*day})
+			m, _ := newTestMonitor(t, node, func(cfg *CLIConfig) { cfg.Block = test.block })
+			chain := m.chains[0]
+
+			// the header is requested once per iteration, its timestamp being shared by the safes.
+			for i := 1; i <= 2; i++ {
+				requests := node.Requests("eth_getBlockByNumber")
+				m.Run(context.Background())
+				if count := node.Requests("eth_getBlockByNumber") - requests; count != 1 {
+					t.Errorf("Failed %s: expected the header requested once by the iteration %d but got %d", test.name, i, count)
+				}
+			}
+			if timestamp := testutil.ToFloat64(chain.blockTimestamp); timestamp != float64(test.expectedTimestamp) {
+				t.Errorf("Failed %s: expected the timestamp %d but got %v", test.name, test.expectedTimestamp, timestamp)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package liveness_expiration
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/liveness_expiration/bindings"
+	opbindings "github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	gnosisSafeABI     = util.MustParseABI(bindings.GnosisSafeMetaData)
+	livenessGuardABI  = util.MustParseABI(bindings.LivenessGuardMetaData)
+	livenessModuleABI = util.MustParseABI(bindings.LivenessModuleMetaData)
+)
+
+// multicallRequest is a single call of a Multicall3 `aggregate3`.
+type multicallRequest struct {
+	target common.Address
+	abi    *abi.ABI
+	method string
+	args   []interface{}
+}
+
+// safeSnapshot is the state of a safe read at a single block.
+type safeSnapshot struct {
+	owners    []common.Address
+	threshold *big.Int
+	nonce     *big.Int
+	interval  *big.Int                    // `nil` when the safe has no LivenessModule.
+	minOwners *big.Int                    // `nil` when the safe has no LivenessModule or for the LivenessModule v2.
+	fallback  common.Address              // zero when the safe has no LivenessModule.
+	lastLive  map[common.Address]*big.Int // `nil` when the safe has no LivenessGuard.
+
+	transferredToFallback bool     // LivenessModule v1 only.
+	challengeStartTime    *big.Int // LivenessModule v2 only, 0 when the safe is not challenged.
+}
+
+// aggregate3 executes the requests through a single Multicall3 `aggregate3` pinned to `blockNumber` and returns the unpacked outputs of each request.
+func (m *chainMonitor) aggregate3(ctx context.Context, blockNumber *big.Int, requests []multicallRequest) ([][]interface{}, error) {
+	calls := make([]opbindings.Multicall3Call3, len(requests))
+	for i, request := range requests {
+		callData, err := request.abi.Pack(request.method, request.args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack `%s`: %w", request.method, err)
+		}
+		calls[i] = opbindings.Multicall3Call3{Target: request.target, AllowFailure: true, CallData: callData}
+	}
+
+	var out []interface{}
+	if err := m.multicall.Call(&bind.CallOpts{Context: ctx, BlockNumber: blockNumber}, &out, "aggregate3", calls); err != nil {
+		return nil, fmt.Errorf("failed to call `aggregate3`: %w", err)
+	}
+	results := *abi.ConvertType(out[0], new([]opbindings.Multicall3Result)).(*[]opbindings.Multicall3Result)
+	if len(results) != len(requests) {
+		return nil, fmt.Errorf("unexpected number of results from `aggregate3`: %d (expected %d)", len(results), len(requests))
+	}
+
+	outputs := make([][]interface{}, len(requests))
+	for i, result := range results {
+		if !result.Success {
+			return nil, fmt.Errorf("the call `%s` to %s failed", requests[i].method, requests[i].target)
+		}
+		output, err := requests[i].abi.Unpack(requests[i].method, result.ReturnData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack `%s`: %w", requests[i].method, err)
+		}
+		outputs[i] = output
+	}
+	return outputs, nil
+}
+
+// readSafeSnapshot reads the owners, the threshold, the nonce, the state of the LivenessModule (through the adapter of its version) and the last live of every owner of the safe at `blockNumber`.
+// The `lastLive` of the owners known from the previous iteration are queried in the same `aggregate3` as the owners,
+// so a single call is required unless the owners changed. In this case, a second call pinned to the same block is made for the new owners.
+func (m *chainMonitor) readSafeSnapshot(ctx context.Context, safe *safeTarget, blockNumber *big.Int) (*safeSnapshot, error) {
+	requests := []multicallRequest{
+		{target: safe.GnosisSafeAddress, abi: gnosisSafeABI, method: "getOwners"},
+		{target: safe.GnosisSafeAddress, abi: gnosisSafeABI, method: "getThreshold"},
+		{target: safe.GnosisSafeAddress, abi: gnosisSafeABI, method: "nonce"},
+	}
+	hasLiveness := safe.LivenessGuard != nil && safe.LivenessModule != nil
+	var moduleRequests []multicallRequest
+	if hasLiveness {
+		moduleRequests = safe.adapter.requests(safe)
+		requests = append(requests, moduleRequests...)
+		for _, owner := range safe.owners {
+			requests = append(requests, m.lastLiveRequest(safe, owner))
+		}
+	}
+
+	outputs, err := m.aggregate3(ctx, blockNumber, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &safeSnapshot{
+		owners:    *abi.ConvertType(outputs[0][0], new([]common.Address)).(*[]common.Address),
+		threshold: *abi.ConvertType(outputs[1][0], new(*big.Int)).(**big.Int),
+		nonce:     *abi.ConvertType(outputs[2][0], new(*big.Int)).(**big.Int),
+	}
+	if !hasLiveness {
+		return snapshot, nil
+	}
+
+	safe.adapter.decode(outputs[3:3+len(moduleRequests)], snapshot)
+	snapshot.lastLive = make(map[common.Address]*big.Int, len(snapshot.owners))
+	for i, owner := range safe.owners {
+		snapshot.lastLive[owner] = *abi.ConvertType(outputs[3+len(moduleRequests)+i][0], new(*big.Int)).(**big.Int)
+	}
+
+	// The owners changed since the previous iteration, query the missing ones at the same block.
+	var missing []common.Address
+	for _, owner := range snapshot.owners {
+		if _, ok := snapshot.lastLive[owner]; !ok {
+			missing = append(missing, owner)
+		}
+	}
+	if len(missing) == 0 {
+		return snapshot, nil
+	}
+
+	requests = make([]multicallRequest, len(missing))
+	for i, owner := range missing {
+		requests[i] = m.lastLiveRequest(safe, owner)
+	}
+	outputs, err = m.aggregate3(ctx, blockNumber, requests)
+	if err != nil {
+		return nil, err
+	}
+	for i, owner := range missing {
+		snapshot.lastLive[owner] = *abi.ConvertType(outputs[i][0], new(*big.Int)).(**big.Int)
+	}
+	return snapshot, nil
+}
+
+// lastLiveRequest returns the request of `lastLive(owner)` on the LivenessGuard of the safe.
+func (m *chainMonitor) lastLiveRequest(safe *safeTarget, owner common.Address) multicallRequest {
+	return multicallRequest{target: safe.LivenessGuardAddress, abi: livenessGuardABI, method: "lastLive", args: []interface{}{owner}}
+}
@@ -0,0 +1,64 @@
+package liveness_expiration
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestReadSafeSnapshot(t *testing.T) {
+	now := uint64(100 * 86400)
+	tests := []struct {
+		name          string
+		known         []common.Address // owners known from the previous iteration.
+		failing       bool             // `lastLive` failing on the LivenessGuard.
+		expectedCalls int              // `eth_call` of the `aggregate3` and of the calls forwarded by the Multicall3.
+	}{
+		{name: "Owners unknown", expectedCalls: (1 + 7) + (1 + 3)},
+		{name: "Owners known", known: []common.Address{ownerA, ownerB, ownerC}, expectedCalls: 1 + 10},
+		{name: "Owner added", known: []common.Address{ownerA, ownerB}, expectedCalls: (1 + 9) + (1 + 1)},
+		{name: "Owner removed", known: []common.Address{ownerA, ownerB, ownerC, ownerD}, expectedCalls: 1 + 11},
+		{name: "Call failing", known: []common.Address{ownerA, ownerB, ownerC}, failing: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := newSafeNode(t, now)
+			node.lastLive = map[common.Address]uint64{ownerA: now - 1, ownerB: now - 2, ownerC: now - 3, ownerD: now - 4}
+			if test.failing {
+				node.Contract(testGuard, livenessGuardABI).Handle("lastLive", func(_ []interface{}) ([]interface{}, error) {
+					return nil, errors.New("execution reverted")
+				})
+			}
+			m, _ := newTestMonitor(t, node)
+			chain, safe := m.chains[0], m.chains[0].safes[0]
+			safe.owners = test.known
+
+			calls := node.Requests("eth_call")
+			snapshot, err := chain.readSafeSnapshot(context.Background(), safe, big.NewInt(0))
+			if test.failing {
+				if err == nil {
+					t.Errorf("Failed %s: expected an error but got %v", test.name, snapshot)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed %s: unexpected error %v", test.name, err)
+			}
+			if calls := node.Requests("eth_call") - calls; calls != test.expectedCalls {
+				t.Errorf("Failed %s: expected %d calls but got %d", test.name, test.expectedCalls, calls)
+			}
+			if len(snapshot.owners) != 3 || snapshot.threshold.Int64() != 2 || snapshot.nonce.Int64() != 1 || snapshot.interval.Int64() != 30*86400 || snapshot.minOwners.Int64() != 2 {
+				t.Errorf("Failed %s: unexpected state of the safe %v", test.name, snapshot)
+			}
+			for _, owner := range snapshot.owners {
+				if lastLive := snapshot.lastLive[owner]; lastLive == nil || lastLive.Uint64() != node.lastLive[owner] {
+					t.Errorf("Failed %s: expected the last live %d of %s but got %v", test.name, node.lastLive[owner], owner, lastLive)
+				}
+			}
+		})
+	}
+}
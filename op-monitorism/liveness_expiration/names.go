@@ -0,0 +1,148 @@
+package liveness_expiration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ENSRegistryABI is the subset of the ENS registry used to find the resolver of a node.
+	ENSRegistryABI = `[{"inputs":[{"name":"node","type":"bytes32"}],"name":"resolver","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}]`
+	// ENSResolverABI is the subset of an ENS resolver used for the reverse and the forward resolution.
+	ENSResolverABI = `[{"inputs":[{"name":"node","type":"bytes32"}],"name":"name","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"node","type":"bytes32"}],"name":"addr","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}]`
+)
+
+var (
+	// ENSRegistryAddress is the address of the ENS registry on Ethereum mainnet.
+	ENSRegistryAddress = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+	ensRegistryABI = util.MustParseJSONABI(ENSRegistryABI)
+	ensResolverABI = util.MustParseJSONABI(ENSResolverABI)
+)
+
+// ReadAddressBookFile reads the names of the addresses from a YAML file formatted as `address: name`.
+func ReadAddressBookFile(path string) (map[common.Address]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the address book %s: %w", path, err)
+	}
+
+	var book map[common.Address]string
+	if err := yaml.Unmarshal(data, &book); err != nil {
+		return nil, fmt.Errorf("failed to parse the address book %s: %w", path, err)
+	}
+	return book, nil
+}
+
+// namehash computes the ENS namehash of a name (EIP-137).
+func namehash(name string) common.Hash {
+	node := common.Hash{}
+	if name == "" {
+		return node
+	}
+
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = crypto.Keccak256Hash(node.Bytes(), crypto.Keccak256([]byte(labels[i])))
+	}
+	return node
+}
+
+// resolveName returns the name of an owner, from the address book first and then from the ENS reverse record when enabled.
+// The names are cached, an empty name is returned when the owner has no name.
+func (m *chainMonitor) resolveName(ctx context.Context, owner common.Address) string {
+	if name, ok := m.names[owner]; ok {
+		return name
+	}
+	if name, ok := m.addressBook[owner]; ok {
+		m.setName(owner, name)
+		return name
+	}
+	if !m.ens {
+		m.names[owner] = ""
+		return ""
+	}
+
+	name, err := m.lookupENS(ctx, owner)
+	if err != nil { // not cached, the resolution is retried during the next iteration.
+		m.log.Error("failed to resolve the ENS name of the owner", "err", err, "owner", owner)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "lookupENS").Inc()
+		return ""
+	}
+	m.setName(owner, name)
+	return name
+}
+
+// setName caches the name of the owner and reports it as `ownerInfo`, replacing the series of its previous name.
+func (m *chainMonitor) setName(owner common.Address, name string) {
+	if previous, ok := m.names[owner]; ok && previous != name {
+		m.ownerInfo.DeleteLabelValues(owner.String(), previous)
+	}
+	m.names[owner] = name
+	if len(name) > 0 {
+		m.ownerInfo.WithLabelValues(owner.String(), name).Set(1)
+	}
+}
+
+// lookupENS resolves the ENS reverse record of an address, the name is only returned when its forward resolution matches the address.
+func (m *chainMonitor) lookupENS(ctx context.Context, owner common.Address) (string, error) {
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	registry := bind.NewBoundContract(ENSRegistryAddress, *ensRegistryABI, m.l1Client, nil, nil)
+
+	reverseNode := namehash(strings.ToLower(owner.Hex()[2:]) + ".addr.reverse")
+	reverseResolver, err := callAddress(registry, callOpts, "resolver", reverseNode)
+	if err != nil {
+		return "", err
+	}
+	if reverseResolver == (common.Address{}) { // no reverse record.
+		return "", nil
+	}
+
+	var out []interface{}
+	if err := bind.NewBoundContract(reverseResolver, *ensResolverABI, m.l1Client, nil, nil).Call(callOpts, &out, "name", reverseNode); err != nil {
+		return "", fmt.Errorf("failed to call `name`: %w", err)
+	}
+	name := *abi.ConvertType(out[0], new(string)).(*string)
+	if name == "" {
+		return "", nil
+	}
+
+	// The reverse record is set by the owner itself, the forward resolution ensures the name belongs to it.
+	forwardNode := namehash(name)
+	forwardResolver, err := callAddress(registry, callOpts, "resolver", forwardNode)
+	if err != nil {
+		return "", err
+	}
+	if forwardResolver == (common.Address{}) {
+		return "", nil
+	}
+	addr, err := callAddress(bind.NewBoundContract(forwardResolver, *ensResolverABI, m.l1Client, nil, nil), callOpts, "addr", forwardNode)
+	if err != nil {
+		return "", err
+	}
+	if addr != owner {
+		m.log.Warn("the ENS reverse record of the owner does not resolve to it, ignoring the name", "owner", owner, "name", name, "resolved", addr)
+		return "", nil
+	}
+	return name, nil
+}
+
+// callAddress calls a method returning a single address.
+func callAddress(contract *bind.BoundContract, callOpts *bind.CallOpts, method string, node common.Hash) (common.Address, error) {
+	var out []interface{}
+	if err := contract.Call(callOpts, &out, method, node); err != nil {
+		return common.Address{}, fmt.Errorf("failed to call `%s`: %w", method, err)
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
@@ -0,0 +1,78 @@
+package liveness_expiration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNamehash(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected common.Hash
+	}{
+		{name: "", expected: common.Hash{}},
+		{name: "eth", expected: common.HexToHash("0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae")},
+		{name: "foo.eth", expected: common.HexToHash("0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f")},
+		{name: "addr.reverse", expected: common.HexToHash("0x91d1777781884d03a6757a803996e38de2a42967fb37eeaca72729271025a9e2")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := namehash(test.name)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestReadAddressBookFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "addressbook.yaml")
+	if err := os.WriteFile(path, []byte("0x42d27eEA1AD6e22Af6284F609847CB3Cd56B9c64: Member A\n"), 0644); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	book, err := ReadAddressBookFile(path)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if name := book[common.HexToAddress("0x42d27eEA1AD6e22Af6284F609847CB3Cd56B9c64")]; name != "Member A" {
+		t.Errorf("expected `Member A` but got %q", name)
+	}
+}
+
+func TestOwnerNames(t *testing.T) {
+	member, other := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	m := &chainMonitor{
+		addressBook: map[common.Address]string{member: "Member A"},
+		names:       make(map[common.Address]string),
+		ownerInfo:   prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "ownerInfo"}, []string{"owner", "name"}),
+	}
+	safe := &safeTarget{label: "safe"}
+
+	if name := m.resolveName(context.Background(), member); name != "Member A" {
+		t.Errorf("expected `Member A` but got %q", name)
+	}
+	if value := testutil.ToFloat64(m.ownerInfo.WithLabelValues(member.String(), "Member A")); value != 1 {
+		t.Errorf("expected the name of the member reported but got %v", value)
+	}
+
+	// the names resolved through ENS are reported by `ownerInfo` only, replacing the previous name.
+	m.setName(other, "old.eth")
+	m.setName(other, "new.eth")
+	if count := testutil.CollectAndCount(m.ownerInfo); count != 2 {
+		t.Errorf("expected the series of the member and of the new name only but got %d", count)
+	}
+	if labels := m.ownerLabels(safe, other); labels[3] != "" {
+		t.Errorf("expected no name label for an owner out of the address book but got %q", labels[3])
+	}
+	if labels := m.ownerLabels(safe, member); labels[3] != "Member A" {
+		t.Errorf("expected the name of the address book but got %q", labels[3])
+	}
+}
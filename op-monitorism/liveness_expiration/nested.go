@@ -0,0 +1,125 @@
+package liveness_expiration
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/liveness_expiration/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// MaxModulesPageSize is the page size used to list the modules enabled on a safe.
+	MaxModulesPageSize = 32
+)
+
+var (
+	// SentinelModules is the start of the linked list of the modules in a Safe.
+	SentinelModules = common.HexToAddress("0x0000000000000000000000000000000000000001")
+)
+
+// checkNestedSafes checks the owners of the safe that are safes themselves (e.g. the 2-of-2 structure).
+// The owners are resolved once and cached, the metrics of the nested safes are labeled with the path `parent/child`.
+func (m *chainMonitor) checkNestedSafes(ctx context.Context, parent *safeTarget, owners []common.Address, latestL1Height uint64, now uint64, depth uint64) {
+	current := make(map[common.Address]bool, len(owners))
+	for _, owner := range owners {
+		current[owner] = true
+
+		nested, known := parent.nested[owner]
+		if !known {
+			var err error
+			nested, err = m.resolveNestedSafe(ctx, parent, owner, latestL1Height)
+			if err != nil {
+				m.log.Error("failed to resolve the owner as a nested safe", "err", err, "safe", parent.label, "owner", owner)
+				m.unexpectedRpcErrors.WithLabelValues("l1", "resolveNestedSafe").Inc()
+				continue
+			}
+			parent.nested[owner] = nested
+		}
+
+		if nested != nil {
+			m.checkSafe(ctx, nested, latestL1Height, now, depth+1)
+		}
+	}
+
+	// Forget the owners removed from the parent safe.
+	for owner := range parent.nested {
+		if !current[owner] {
+			delete(parent.nested, owner)
+		}
+	}
+}
+
+// resolveNestedSafe returns the `safeTarget` of an owner when this one is a safe, `nil` otherwise.
+// The LivenessGuard is read from the guard storage slot, the LivenessModule is the enabled module using this guard (v1 only).
+func (m *chainMonitor) resolveNestedSafe(ctx context.Context, parent *safeTarget, owner common.Address, latestL1Height uint64) (*safeTarget, error) {
+	blockNumber := new(big.Int).SetUint64(latestL1Height)
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: blockNumber}
+
+	code, err := m.l1Client.CodeAt(ctx, owner, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the code of the owner: %w", err)
+	}
+	if len(code) == 0 { // EOA
+		return nil, nil
+	}
+
+	GnosisSafe, err := bindings.NewGnosisSafe(owner, m.l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the GnosisSafe: %w", err)
+	}
+	if _, err := GnosisSafe.GetThreshold(callOpts); err != nil { // contract that is not a safe.
+		m.log.Info("the owner is a contract but not a safe", "safe", parent.label, "owner", owner)
+		return nil, nil
+	}
+
+	nested := &safeTarget{
+		label:             parent.label + "/" + owner.String(),
+		GnosisSafe:        GnosisSafe,
+		GnosisSafeAddress: owner,
+		nested:            make(map[common.Address]*safeTarget),
+
+		lastLiveTxHash: make(map[common.Address]common.Hash),
+		approvedHashes: make(map[common.Hash]uint64),
+		notifiedLevel:  make(map[common.Address]map[string]int),
+	}
+
+	guardSlot, err := m.l1Client.StorageAt(ctx, owner, GuardStorageSlot, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the guard storage slot: %w", err)
+	}
+	guard := common.BytesToAddress(guardSlot)
+	if guard == (common.Address{}) {
+		m.log.Info("resolved a nested safe without guard", "safe", nested.label)
+		return nested, nil
+	}
+
+	modules, err := GnosisSafe.GetModulesPaginated(callOpts, SentinelModules, big.NewInt(MaxModulesPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the modules of the nested safe: %w", err)
+	}
+	for _, module := range modules.Array {
+		LivenessModule, err := bindings.NewLivenessModule(module, m.l1Client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bind to the LivenessModule: %w", err)
+		}
+		moduleGuard, err := LivenessModule.LivenessGuard(callOpts)
+		if err != nil || moduleGuard != guard { // not a LivenessModule or not the one using this guard.
+			continue
+		}
+
+		LivenessGuard, err := bindings.NewLivenessGuard(guard, m.l1Client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bind to the LivenessGuard: %w", err)
+		}
+		nested.LivenessGuard, nested.LivenessGuardAddress = LivenessGuard, guard
+		nested.LivenessModule, nested.LivenessModuleAddress = LivenessModule, module
+		nested.adapter = &livenessModuleV1{moduleVersion: "unknown"}
+		break
+	}
+
+	m.log.Info("resolved a nested safe", "safe", nested.label, "guard", nested.LivenessGuardAddress, "module", nested.LivenessModuleAddress)
+	return nested, nil
+}
@@ -0,0 +1,71 @@
+package liveness_expiration
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCheckNestedSafes(t *testing.T) {
+	nestedSafe, innerSafe := ownerD, common.HexToAddress("0x0E")
+	tests := []struct {
+		name          string
+		depth         uint64
+		safes         map[common.Address][]common.Address // owners of the safes owning the monitored safe.
+		expectedSafes map[string]float64                  // owners count by safe label.
+	}{
+		{
+			name:          "Nested safe",
+			depth:         1,
+			safes:         map[common.Address][]common.Address{nestedSafe: {ownerA, ownerB}},
+			expectedSafes: map[string]float64{testSafe.String(): 4, testSafe.String() + "/" + nestedSafe.String(): 2},
+		},
+		{
+			name:          "Nested safes disabled",
+			safes:         map[common.Address][]common.Address{nestedSafe: {ownerA, ownerB}},
+			expectedSafes: map[string]float64{testSafe.String(): 4},
+		},
+		{
+			name:          "No nested safe",
+			depth:         1,
+			expectedSafes: map[string]float64{testSafe.String(): 4},
+		},
+		{
+			name:          "Nested safe beyond the depth",
+			depth:         1,
+			safes:         map[common.Address][]common.Address{nestedSafe: {ownerA, innerSafe}, innerSafe: {ownerB}},
+			expectedSafes: map[string]float64{testSafe.String(): 4, testSafe.String() + "/" + nestedSafe.String(): 2},
+		},
+		{
+			name:          "Nested safes within the depth",
+			depth:         2,
+			safes:         map[common.Address][]common.Address{nestedSafe: {ownerA, innerSafe}, innerSafe: {ownerB}},
+			expectedSafes: map[string]float64{testSafe.String(): 4, testSafe.String() + "/" + nestedSafe.String(): 2, testSafe.String() + "/" + nestedSafe.String() + "/" + innerSafe.String(): 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := newSafeNode(t, 100*86400)
+			node.owners = []common.Address{ownerA, ownerB, ownerC, nestedSafe}
+			for safe, owners := range test.safes {
+				node.Contract(safe, gnosisSafeABI).Returns("getOwners", owners).Returns("getThreshold", big.NewInt(1)).Returns("nonce", big.NewInt(0))
+			}
+			m, _ := newTestMonitor(t, node, func(cfg *CLIConfig) { cfg.NestedDepth = test.depth })
+			m.Run(context.Background())
+
+			chain := m.chains[0]
+			if count := testutil.CollectAndCount(chain.ownersCount); count != len(test.expectedSafes) {
+				t.Errorf("Failed %s: expected %d safes but got %d", test.name, len(test.expectedSafes), count)
+			}
+			for label, expected := range test.expectedSafes {
+				if owners := testutil.ToFloat64(chain.ownersCount.WithLabelValues(label)); owners != expected {
+					t.Errorf("Failed %s: expected %v owners of %s but got %v", test.name, expected, label, owners)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package liveness_expiration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// NotifyTimeout is the timeout of a notification.
+	NotifyTimeout = 10 * time.Second
+)
+
+// NotifyThreshold is an escalation level, a notification with `Severity` is sent when the remaining time of an owner falls below `Remaining`.
+type NotifyThreshold struct {
+	Severity  string
+	Remaining time.Duration
+}
+
+// ParseNotifyThresholds parses the thresholds formatted via `severity=duration` and sorts them from the least to the most severe.
+func ParseNotifyThresholds(values []string) ([]NotifyThreshold, error) {
+	thresholds := make([]NotifyThreshold, 0, len(values))
+	for _, value := range values {
+		severity, duration, err := util.ParseNamedValue(value, "severity=duration")
+		if err != nil {
+			return nil, err
+		}
+		remaining, err := time.ParseDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the duration of %s: %w", value, err)
+		}
+		thresholds = append(thresholds, NotifyThreshold{Severity: severity, Remaining: remaining})
+	}
+
+	sort.Slice(thresholds, func(i, j int) bool { return thresholds[i].Remaining > thresholds[j].Remaining })
+	return thresholds, nil
+}
+
+// escalationLevel returns the index of the most severe threshold reached by the remaining time, -1 when none is reached.
+func escalationLevel(thresholds []NotifyThreshold, remaining int64) int {
+	level := -1
+	for i, threshold := range thresholds {
+		if remaining <= int64(threshold.Remaining.Seconds()) {
+			level = i
+		}
+	}
+	return level
+}
+
+// newNotifiers returns the notifiers of the escalation levels, the generic webhook receiving the alert encoded in JSON.
+func newNotifiers(cfg CLIConfig) []alerts.Notifier {
+	var notifiers []alerts.Notifier
+	if len(cfg.NotifyWebhookURL) > 0 {
+		notifiers = append(notifiers, alerts.NewWebhookNotifier(cfg.NotifyWebhookURL, nil, nil, "", 1, NotifyTimeout))
+	}
+	if len(cfg.NotifySlackURL) > 0 {
+		notifiers = append(notifiers, alerts.NewSlackNotifier(cfg.NotifySlackURL, NotifyTimeout))
+	}
+	return notifiers
+}
+
+// notifyExpiration notifies the notifiers when the remaining time of an owner reaches a new escalation level.
+// A level is notified once per notifier, a failed notifier being retried during the next iteration without notifying
+// the others again. The owner is notified again from the first level after proving its liveness.
+func (m *chainMonitor) notifyExpiration(ctx context.Context, safe *safeTarget, owner common.Address, deadline uint64, remaining int64) {
+	if len(m.notifiers) == 0 {
+		return
+	}
+
+	level := escalationLevel(m.notifyThresholds, remaining)
+	if level < 0 {
+		delete(safe.notifiedLevel, owner)
+		return
+	}
+	notified, ok := safe.notifiedLevel[owner]
+	if !ok {
+		notified = make(map[string]int)
+		safe.notifiedLevel[owner] = notified
+	}
+
+	alert := m.expirationAlert(safe, owner, m.notifyThresholds[level].Severity, deadline, remaining)
+	for _, notifier := range m.notifiers {
+		if previous, ok := notified[notifier.Name()]; ok && previous >= level {
+			continue
+		}
+		if err := notifier.Notify(ctx, alert); err != nil {
+			m.log.Error("failed to send the notification", "notifier", notifier.Name(), "err", err, "safe", safe.label, "owner", owner, "severity", alert.Priority)
+			m.notifyFailures.Inc()
+			continue // retried during the next iteration.
+		}
+		m.log.Info("notification sent", "notifier", notifier.Name(), "safe", safe.label, "owner", owner, "severity", alert.Priority)
+		m.notifications.WithLabelValues(safe.label, alert.Priority, notifier.Name()).Inc()
+		notified[notifier.Name()] = level
+	}
+}
+
+// expirationAlert returns the notification of the escalation level of the owner, the severity of the level being the
+// priority of the alert.
+func (m *chainMonitor) expirationAlert(safe *safeTarget, owner common.Address, severity string, deadline uint64, remaining int64) alerts.Alert {
+	member, name := safe.roster[owner], m.names[owner]
+	summary := fmt.Sprintf("the liveness of the owner %s of the safe %s (%s) expires on %s", ownerDisplayName(owner, member, name), safe.label, m.name, time.Unix(int64(deadline), 0).UTC().Format(time.RFC1123))
+	alert := m.alert(ExpiringRule, severity, safe, summary, owner)
+	alert.Labels["owner"], alert.Labels["member"], alert.Labels["name"] = owner.String(), member, name
+	alert.Labels["deadline"], alert.Labels["remainingSeconds"] = fmt.Sprint(deadline), fmt.Sprint(remaining)
+	alert.Time = time.Now()
+	return alert
+}
+
+// ownerDisplayName returns the most readable name of the owner.
+func ownerDisplayName(owner common.Address, member string, name string) string {
+	switch {
+	case len(member) > 0:
+		return fmt.Sprintf("%s (%s)", member, owner)
+	case len(name) > 0:
+		return fmt.Sprintf("%s (%s)", name, owner)
+	default:
+		return owner.String()
+	}
+}
@@ -0,0 +1,105 @@
+package liveness_expiration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// notifyServer records the alerts posted to it, failing with `status` when set.
+type notifyServer struct {
+	*httptest.Server
+	status int
+	alerts []alerts.Alert
+}
+
+func newNotifyServer(t *testing.T) *notifyServer {
+	s := &notifyServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert alerts.Alert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("failed to decode the notification: %v", err)
+		}
+		if s.status != 0 {
+			w.WriteHeader(s.status)
+			return
+		}
+		s.alerts = append(s.alerts, alert)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestNotifyExpiration(t *testing.T) {
+	webhook, slack := newNotifyServer(t), newNotifyServer(t)
+	thresholds, err := ParseNotifyThresholds([]string{"medium=336h", "critical=48h"})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	m := &chainMonitor{
+		name:             DefaultChain,
+		log:              log.New(),
+		names:            map[common.Address]string{},
+		notifiers:        newNotifiers(CLIConfig{NotifyWebhookURL: webhook.URL + "/secret", NotifySlackURL: slack.URL}),
+		notifyThresholds: thresholds,
+		notifications:    prometheus.NewCounterVec(prometheus.CounterOpts{Name: "notifications"}, []string{"safe", "severity", "notifier"}),
+		notifyFailures:   prometheus.NewCounter(prometheus.CounterOpts{Name: "notifyFailures"}),
+	}
+	owner := common.HexToAddress("0x42d27eEA1AD6e22Af6284F609847CB3Cd56B9c64")
+	safe := &safeTarget{
+		GnosisSafeAddress: common.HexToAddress("0xc2819DC788505Aac350142A7A707BF9D03E3Bd03"),
+		label:             "0xc2819DC788505Aac350142A7A707BF9D03E3Bd03",
+		roster:            map[common.Address]string{owner: "Member A"},
+		notifiedLevel:     make(map[common.Address]map[string]int),
+	}
+	day := int64(86400)
+
+	steps := []struct {
+		name      string
+		remaining int64
+		failing   bool // whether the webhook fails.
+		webhook   int  // number of notifications received by the webhook after the step.
+		slack     int  // number of notifications received by Slack after the step.
+	}{
+		{name: "Far from the deadline", remaining: 30 * day},
+		{name: "Within 14 days, the webhook failing", remaining: 10 * day, failing: true, slack: 1},
+		{name: "Webhook retried only", remaining: 10 * day, webhook: 1, slack: 1},
+		{name: "Level already notified", remaining: 9 * day, webhook: 1, slack: 1},
+		{name: "Within 48 hours", remaining: day, webhook: 2, slack: 2},
+		{name: "Liveness proven", remaining: 60 * day, webhook: 2, slack: 2},
+		{name: "Within 14 days again", remaining: 10 * day, webhook: 3, slack: 3},
+	}
+	for _, step := range steps {
+		webhook.status = 0
+		if step.failing {
+			webhook.status = http.StatusServiceUnavailable
+		}
+		m.notifyExpiration(context.Background(), safe, owner, 1717171717, step.remaining)
+		if len(webhook.alerts) != step.webhook || len(slack.alerts) != step.slack {
+			t.Fatalf("Failed %s: expected %d webhook and %d slack notifications but got %d and %d", step.name, step.webhook, step.slack, len(webhook.alerts), len(slack.alerts))
+		}
+	}
+
+	alert := webhook.alerts[1]
+	if alert.Rule != ExpiringRule || alert.Priority != "critical" || alert.Labels["member"] != "Member A" || alert.Labels["remainingSeconds"] != "86400" {
+		t.Errorf("unexpected notification %+v", alert)
+	}
+	if !strings.Contains(alert.Summary, "Member A (0x42d27eEA1AD6e22Af6284F609847CB3Cd56B9c64)") {
+		t.Errorf("expected the summary to name the member but got %s", alert.Summary)
+	}
+	if failures := testutil.ToFloat64(m.notifyFailures); failures != 1 {
+		t.Errorf("expected 1 failure but got %v", failures)
+	}
+	if sent := testutil.ToFloat64(m.notifications.WithLabelValues(safe.label, "medium", "slack")); sent != 2 {
+		t.Errorf("expected 2 medium notifications sent to slack but got %v", sent)
+	}
+}
@@ -0,0 +1,60 @@
+package liveness_expiration
+
+import (
+	"context"
+	"fmt"
+)
+
+// Status is the result of a single evaluation, used as the exit code of the one-shot mode.
+type Status int
+
+const (
+	// StatusOK means every owner is outside of the warning buffer.
+	StatusOK Status = 0
+	// StatusAtRisk means at least one owner is within the warning buffer.
+	StatusAtRisk Status = 1
+	// StatusExpired means the liveness of at least one owner is already expired.
+	StatusExpired Status = 2
+	// StatusFailure means the safes could not be evaluated.
+	StatusFailure Status = 3
+)
+
+// raiseStatus keeps the worst status observed during the evaluation.
+func (m *chainMonitor) raiseStatus(status Status) {
+	if status > m.status {
+		m.status = status
+	}
+}
+
+// RunOnce performs a single evaluation of every safe of every chain and returns the worst status observed.
+// An error is returned when a safe could not be read, the status would be incomplete otherwise.
+func (m *Monitor) RunOnce(ctx context.Context) (Status, error) {
+	worst := StatusOK
+	for _, chain := range m.chains {
+		status, err := chain.runOnce(ctx)
+		if err != nil {
+			return status, fmt.Errorf("%s: %w", chain.name, err)
+		}
+		if status > worst {
+			worst = status
+		}
+	}
+	return worst, nil
+}
+
+// runOnce performs a single evaluation of every safe of the chain and returns the worst status observed.
+func (m *chainMonitor) runOnce(ctx context.Context) (Status, error) {
+	m.status = StatusOK
+	m.evaluated = false
+
+	m.Run(ctx)
+	if !m.evaluated {
+		return StatusFailure, fmt.Errorf("failed to query the latest block")
+	}
+	for _, safe := range m.safes {
+		if safe.consecutiveFailures > 0 {
+			return StatusFailure, fmt.Errorf("failed to read the safe %s", safe.label)
+		}
+	}
+	return m.status, nil
+}
@@ -0,0 +1,46 @@
+package liveness_expiration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRunOnce(t *testing.T) {
+	day := uint64(86400)
+	now := 100 * day
+	tests := []struct {
+		name        string
+		lastLive    map[common.Address]uint64 // offsets of the last live of the owners before now.
+		failing     bool
+		expected    Status
+		expectedErr bool
+	}{
+		{name: "Owners live", expected: StatusOK},
+		{name: "Owner outside of the buffer", lastLive: map[common.Address]uint64{ownerA: 20 * day}, expected: StatusOK},
+		{name: "Owner within the buffer", lastLive: map[common.Address]uint64{ownerA: 25 * day}, expected: StatusAtRisk},
+		{name: "Owner expired", lastLive: map[common.Address]uint64{ownerA: 31 * day}, expected: StatusExpired},
+		{name: "Worst owner kept", lastLive: map[common.Address]uint64{ownerA: 31 * day, ownerB: 25 * day}, expected: StatusExpired},
+		{name: "Safe not read", failing: true, expected: StatusFailure, expectedErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := newSafeNode(t, now)
+			for owner, offset := range test.lastLive {
+				node.lastLive[owner] = now - offset
+			}
+			node.failing = test.failing
+			m, _ := newTestMonitor(t, node)
+
+			status, err := m.RunOnce(context.Background())
+			if (err != nil) != test.expectedErr {
+				t.Errorf("Failed %s: expected an error %v but got %v", test.name, test.expectedErr, err)
+			}
+			if status != test.expected {
+				t.Errorf("Failed %s: expected the status %d but got %d", test.name, test.expected, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package liveness_expiration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ownerLabels returns the values of the labels `safe`, `owner`, `member` and `name` of the metrics related to an owner.
+// The `name` is the one of the address book only, the names resolved through ENS being reported by `ownerInfo`. The
+// values are recorded for the owner, the series of previous values being deleted when they change.
+func (m *chainMonitor) ownerLabels(safe *safeTarget, owner common.Address) []string {
+	labels := []string{safe.label, owner.String(), safe.roster[owner], m.addressBook[owner]}
+	if previous, ok := safe.ownerSeries[owner]; ok && !slices.Equal(previous, labels) {
+		m.deleteOwnerSeries(previous)
+	}
+	if safe.ownerSeries == nil {
+		safe.ownerSeries = make(map[common.Address][]string)
+	}
+	safe.ownerSeries[owner] = labels
+	return labels
+}
+
+// checkRoster compares the owners of the safe with the expected roster from the configuration.
+// Nothing is checked when no roster is configured for the safe.
+func (m *chainMonitor) checkRoster(ctx context.Context, safe *safeTarget, owners []common.Address) {
+	if len(safe.roster) == 0 {
+		return
+	}
+
+	expected := make([]common.Address, 0, len(safe.roster))
+	for owner := range safe.roster {
+		expected = append(expected, owner)
+	}
+	unexpected, missing := diffOwners(expected, owners)
+
+	for _, owner := range owners {
+		_, expected := safe.roster[owner]
+		m.tracker.Update(ctx, m.alert(UnexpectedOwnerRule, "P1", safe, fmt.Sprintf("the owner %s of the safe %s is not in the expected roster", owner, safe.label), owner), !expected)
+	}
+	isOwner := make(map[common.Address]bool, len(owners))
+	for _, owner := range owners {
+		isOwner[owner] = true
+	}
+	for member, name := range safe.roster {
+		alert := m.alert(MissingOwnerRule, "P2", safe, fmt.Sprintf("the member %s (%s) of the expected roster is not an owner of the safe %s", member, name, safe.label), member)
+		alert.Labels["member"] = name
+		m.tracker.Update(ctx, alert, !isOwner[member])
+	}
+
+	for _, owner := range unexpected {
+		m.log.Error("the owner of the safe is not in the expected roster", "safe", safe.label, "owner", owner)
+	}
+	for _, owner := range missing {
+		m.log.Error("a member of the expected roster is not an owner of the safe", "safe", safe.label, "owner", owner, "member", safe.roster[owner])
+	}
+	m.unexpectedOwners.WithLabelValues(safe.label).Set(float64(len(unexpected)))
+	m.missingOwners.WithLabelValues(safe.label).Set(float64(len(missing)))
+}
@@ -0,0 +1,63 @@
+package liveness_expiration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// checkNonce exports the nonce of the safe and remembers when it advanced for the last time.
+func (m *chainMonitor) checkNonce(safe *safeTarget, nonce uint64, now uint64) {
+	if safe.nonceUpdatedAt == 0 || nonce != safe.nonce {
+		safe.nonce, safe.nonceUpdatedAt = nonce, now
+	}
+	m.safeNonce.WithLabelValues(safe.label).Set(float64(nonce))
+}
+
+// checkStuckApprovals raises an alert when an `ApproveHash` event was not followed by the execution of the transaction within `stuckDuration`.
+func (m *chainMonitor) checkStuckApprovals(ctx context.Context, safe *safeTarget, now uint64) {
+	stuck := 0
+	for hash, approvedAt := range safe.approvedHashes {
+		if isStuck(approvedAt, now, m.stuckDuration) {
+			stuck++
+			m.log.Warn("the approved hash was not executed", "safe", safe.label, "approved_hash", hash, "approved_at", approvedAt, "now", now)
+		}
+	}
+	m.pendingApprovedHashes.WithLabelValues(safe.label).Set(float64(len(safe.approvedHashes)))
+	m.setStuckExecution(safe, "approveHash", stuck > 0)
+	alert := m.alert(StuckApprovalRule, "P2", safe, fmt.Sprintf("%d hashes approved on the safe %s were not executed within %s", stuck, safe.label, time.Duration(m.stuckDuration)*time.Second))
+	m.tracker.Update(ctx, alert, stuck > 0)
+}
+
+// checkStuckFullySigned raises an alert when a fully signed transaction with the current nonce of the safe is not executed within `stuckDuration`.
+func (m *chainMonitor) checkStuckFullySigned(ctx context.Context, safe *safeTarget, now uint64, pending []pendingTransaction) {
+	fullySigned := common.Hash{}
+	for _, tx := range pending {
+		if tx.Nonce == safe.nonce && uint64(len(tx.Confirmations)) >= tx.ConfirmationsRequired {
+			fullySigned = tx.SafeTxHash
+		}
+	}
+
+	stuck := fullySigned != (common.Hash{}) && isStuck(safe.nonceUpdatedAt, now, m.stuckDuration)
+	if stuck {
+		m.log.Warn("the nonce of the safe didn't advance despite a fully signed transaction", "safe", safe.label, "nonce", safe.nonce, "safe_tx_hash", fullySigned, "nonce_updated_at", safe.nonceUpdatedAt, "now", now)
+	}
+	m.setStuckExecution(safe, "fullySigned", stuck)
+	alert := m.alert(StuckFullySignedRule, "P2", safe, fmt.Sprintf("the fully signed transaction %s with the nonce %d of the safe %s was not executed within %s", fullySigned, safe.nonce, safe.label, time.Duration(m.stuckDuration)*time.Second))
+	m.tracker.Update(ctx, alert, stuck)
+}
+
+func (m *chainMonitor) setStuckExecution(safe *safeTarget, reason string, stuck bool) {
+	if stuck {
+		m.stuckExecution.WithLabelValues(safe.label, reason).Set(1)
+	} else {
+		m.stuckExecution.WithLabelValues(safe.label, reason).Set(0)
+	}
+}
+
+// isStuck returns true when more than `duration` seconds elapsed since `since`.
+func isStuck(since uint64, now uint64, duration uint64) bool {
+	return now > since && now-since > duration
+}
@@ -0,0 +1,125 @@
+package liveness_expiration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// SafeTxServiceTimeout is the timeout of a request to the Safe Transaction Service.
+	SafeTxServiceTimeout = 10 * time.Second
+
+	// SafeTxServicePageSize is the number of transactions requested per page of the Safe Transaction Service.
+	SafeTxServicePageSize = 100
+	// SafeTxServiceMaxPages bounds the pages followed for a safe, in case the service keeps returning a next page.
+	SafeTxServiceMaxPages = 20
+)
+
+// pendingTransaction is a multisig transaction queued in the Safe Transaction Service.
+type pendingTransaction struct {
+	SafeTxHash            common.Hash `json:"safeTxHash"`
+	Nonce                 uint64      `json:"nonce"`
+	ConfirmationsRequired uint64      `json:"confirmationsRequired"`
+	Confirmations         []struct {
+		Owner common.Address `json:"owner"`
+	} `json:"confirmations"`
+}
+
+// pendingTransactionsResponse is a page of the response of `/api/v1/safes/{address}/multisig-transactions/`, `Next`
+// being the URL of the next page, empty on the last one.
+type pendingTransactionsResponse struct {
+	Count   uint64               `json:"count"`
+	Next    string               `json:"next"`
+	Results []pendingTransaction `json:"results"`
+}
+
+// fetchPendingTransactions returns the transactions of the safe not executed yet with a nonce greater or equal to `nonce`,
+// following the pages of the response.
+func (m *chainMonitor) fetchPendingTransactions(ctx context.Context, safe common.Address, nonce uint64) ([]pendingTransaction, error) {
+	url := fmt.Sprintf("%s/api/v1/safes/%s/multisig-transactions/?executed=false&nonce__gte=%d&ordering=nonce&limit=%d", strings.TrimSuffix(m.safeTxServiceURL, "/"), safe.Hex(), nonce, SafeTxServicePageSize)
+
+	var pending []pendingTransaction
+	for page := 0; len(url) > 0; page++ {
+		if page == SafeTxServiceMaxPages {
+			return nil, fmt.Errorf("more than %d pages of pending transactions", SafeTxServiceMaxPages)
+		}
+		body, err := m.fetchPendingTransactionsPage(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, body.Results...)
+		url = body.Next
+	}
+	return pending, nil
+}
+
+// fetchPendingTransactionsPage returns a page of the pending transactions.
+func (m *chainMonitor) fetchPendingTransactionsPage(ctx context.Context, url string) (*pendingTransactionsResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the request: %w", err)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the Safe Transaction Service: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status from the Safe Transaction Service: %s", resp.Status)
+	}
+
+	var body pendingTransactionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode the response of the Safe Transaction Service: %w", err)
+	}
+	return &body, nil
+}
+
+// nonceGap returns the number of nonces between the nonce of the safe and the lowest pending transaction.
+// A gap means the pending transactions can't be executed before the missing nonces.
+func nonceGap(nonce uint64, pending []pendingTransaction) uint64 {
+	if len(pending) == 0 {
+		return 0
+	}
+	lowest := pending[0].Nonce
+	for _, tx := range pending[1:] {
+		if tx.Nonce < lowest {
+			lowest = tx.Nonce
+		}
+	}
+	if lowest <= nonce {
+		return 0
+	}
+	return lowest - nonce
+}
+
+// checkPendingTransactions exports the transactions queued for the safe in the Safe Transaction Service,
+// so the operators can see whether a transaction proving the liveness of an owner is already staged.
+// The nonce of the safe is the one read by `checkSafe` during the same iteration.
+func (m *chainMonitor) checkPendingTransactions(ctx context.Context, safe *safeTarget, now uint64) {
+	safeLabel := safe.label
+	nonce := safe.nonce
+
+	pending, err := m.fetchPendingTransactions(ctx, safe.GnosisSafeAddress, nonce)
+	if err != nil {
+		m.log.Error("failed to fetch the pending transactions", "err", err, "safe", safeLabel)
+		m.unexpectedRpcErrors.WithLabelValues("safe-tx-service", "multisig-transactions").Inc()
+		return
+	}
+
+	m.pendingTransactionConfirmations.DeletePartialMatch(map[string]string{"safe": safeLabel})
+	for _, tx := range pending {
+		m.log.Info("pending transaction", "safe", safeLabel, "nonce", tx.Nonce, "safe_tx_hash", tx.SafeTxHash, "confirmations", len(tx.Confirmations), "confirmations_required", tx.ConfirmationsRequired)
+		m.pendingTransactionConfirmations.WithLabelValues(safeLabel, fmt.Sprint(tx.Nonce), tx.SafeTxHash.Hex()).Set(float64(len(tx.Confirmations)))
+	}
+	m.pendingTransactions.WithLabelValues(safeLabel).Set(float64(len(pending)))
+	m.nonceGap.WithLabelValues(safeLabel).Set(float64(nonceGap(nonce, pending)))
+	m.checkStuckFullySigned(ctx, safe, now, pending)
+}
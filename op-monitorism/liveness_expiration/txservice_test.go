@@ -0,0 +1,33 @@
+package liveness_expiration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestFetchPendingTransactionsPages(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first page links to the second one, the last page having no next page.
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprintf(w, `{"count":3,"next":"%s%s?offset=2","results":[{"nonce":5},{"nonce":6}]}`, server.URL, r.URL.Path)
+			return
+		}
+		fmt.Fprint(w, `{"count":3,"next":null,"results":[{"nonce":7}]}`)
+	}))
+	defer server.Close()
+
+	m := &chainMonitor{safeTxServiceURL: server.URL, httpClient: server.Client()}
+	pending, err := m.fetchPendingTransactions(context.Background(), common.HexToAddress("0x01"), 5)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(pending) != 3 || pending[2].Nonce != 7 {
+		t.Errorf("expected the 3 pending transactions of both pages but got %v", pending)
+	}
+}
@@ -0,0 +1,86 @@
+package messages
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+	L2NodeURLFlagName = "l2.node.url"
+
+	L1CrossDomainMessengerAddressFlagName = "l1crossdomainmessenger.address"
+	StartBlockHeightFlagName              = "start.block.height"
+	EventBlockRangeFlagName               = "event.block.range"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+	L2NodeURL string
+
+	L1CrossDomainMessengerAddress common.Address
+
+	// StartBlockHeight is the starting L2 height, the L1 height being derived from its L1 origin.
+	StartBlockHeight uint64
+	EventBlockRange  uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:        ctx.String(L1NodeURLFlagName),
+		L2NodeURL:        ctx.String(L2NodeURLFlagName),
+		StartBlockHeight: ctx.Uint64(StartBlockHeightFlagName),
+		EventBlockRange:  ctx.Uint64(EventBlockRangeFlagName),
+	}
+
+	messengerAddress := ctx.String(L1CrossDomainMessengerAddressFlagName)
+	if !common.IsHexAddress(messengerAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", L1CrossDomainMessengerAddressFlagName)
+	}
+	cfg.L1CrossDomainMessengerAddress = common.HexToAddress(messengerAddress)
+
+	if cfg.EventBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", EventBlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    L2NodeURLFlagName,
+			Usage:   "Node URL of L2 peer",
+			Value:   "127.0.0.1:9545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     L1CrossDomainMessengerAddressFlagName,
+			Usage:    "Address of the L1CrossDomainMessenger contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "L1_CROSS_DOMAIN_MESSENGER"),
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:    StartBlockHeightFlagName,
+			Usage:   "Starting L2 height to scan for relayed messages, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    EventBlockRangeFlagName,
+			Usage:   "Max block range when scanning for events, on L1 and L2",
+			Value:   1000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EVENT_BLOCK_RANGE"),
+		},
+	}
+}
@@ -0,0 +1,429 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-chain-ops/crossdomain"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "messages"
+	MetricsNamespace = "messages_mon"
+
+	// FailedRelayRule is the rule of the alert emitted while the relay of a message failed and it isn't relayed yet,
+	// the entity being the message hash.
+	FailedRelayRule = "message relay failed"
+)
+
+// ageBuckets are the upper bounds (in seconds) of the age buckets of the unrelayed messages.
+var ageBuckets = []struct {
+	label string
+	max   uint64
+}{
+	{label: "5m", max: 5 * 60},
+	{label: "30m", max: 30 * 60},
+	{label: "1h", max: 60 * 60},
+	{label: "6h", max: 6 * 60 * 60},
+	{label: "1d", max: 24 * 60 * 60},
+	{label: "older", max: math.MaxUint64},
+}
+
+// pendingMessage is a message sent on L1 not yet relayed on L2.
+type pendingMessage struct {
+	l1BlockNumber uint64
+	l1Timestamp   uint64
+	l1TxHash      common.Hash
+
+	// failedRelays is the number of relays of the message that failed on L2, it can still be replayed.
+	failedRelays int
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+	l2Client *ethclient.Client
+	l1Block  *bindings.L1BlockCaller
+
+	l1MessengerAddress common.Address
+	l1Messenger        *bindings.L1CrossDomainMessengerFilterer
+	l1MessengerABI     *abi.ABI
+	l2Messenger        *bindings.L2CrossDomainMessengerFilterer
+	l2MessengerABI     *abi.ABI
+
+	nextL1Height    uint64
+	nextL2Height    uint64
+	eventBlockRange uint64
+
+	pending map[common.Hash]*pendingMessage // indexed by the hash of the message.
+
+	// metrics
+	highestBlockNumber  *prometheus.GaugeVec
+	sentMessages        prometheus.Counter
+	relayedMessages     prometheus.Counter
+	failedRelays        prometheus.Counter
+	relayLatency        prometheus.Histogram
+	unrelayedMessages   *prometheus.GaugeVec
+	oldestUnrelayedAge  prometheus.Gauge
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating messages monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	l2Client, err := ethclient.Dial(cfg.L2NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l2: %w", err)
+	}
+
+	l1Block, err := bindings.NewL1BlockCaller(predeploys.L1BlockAddr, l2Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L1Block: %w", err)
+	}
+	l1Messenger, err := bindings.NewL1CrossDomainMessengerFilterer(cfg.L1CrossDomainMessengerAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L1CrossDomainMessenger: %w", err)
+	}
+	l2Messenger, err := bindings.NewL2CrossDomainMessengerFilterer(predeploys.L2CrossDomainMessengerAddr, l2Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L2CrossDomainMessenger: %w", err)
+	}
+
+	l1MessengerABI, err := bindings.L1CrossDomainMessengerMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the L1CrossDomainMessenger ABI: %w", err)
+	}
+	l2MessengerABI, err := bindings.L2CrossDomainMessengerMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the L2CrossDomainMessenger ABI: %w", err)
+	}
+
+	startingL2Height := cfg.StartBlockHeight
+	if startingL2Height == 0 {
+		if startingL2Height, err = l2Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+
+	// The messages of the epoch of the starting L2 block may be relayed before it, the L1 scan starts at the next epoch.
+	origin, err := l1Block.Number(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(startingL2Height)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the L1 origin of the starting height: %w", err)
+	}
+	log.Info("configured starting heights", "l2_height", startingL2Height, "l1_height", origin+1)
+
+	return &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+		l2Client: l2Client,
+		l1Block:  l1Block,
+
+		l1MessengerAddress: cfg.L1CrossDomainMessengerAddress,
+		l1Messenger:        l1Messenger,
+		l1MessengerABI:     l1MessengerABI,
+		l2Messenger:        l2Messenger,
+		l2MessengerABI:     l2MessengerABI,
+
+		nextL1Height:    origin + 1,
+		nextL2Height:    startingL2Height,
+		eventBlockRange: cfg.EventBlockRange,
+
+		pending: make(map[common.Hash]*pendingMessage),
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed heights (checked and known) by layer",
+		}, []string{"layer", "type"}),
+		sentMessages: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "sentMessages",
+			Help:      "number of messages sent through the L1CrossDomainMessenger",
+		}),
+		relayedMessages: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "relayedMessages",
+			Help:      "number of messages successfully relayed on L2",
+		}),
+		failedRelays: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "failedRelays",
+			Help:      "number of failed relays of the messages on L2",
+		}),
+		relayLatency: m.NewHistogram(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Name:      "relayLatency",
+			Help:      "seconds between the L1 block sending the message and the L2 block relaying it",
+			Buckets:   []float64{60, 120, 180, 300, 600, 1800, 3600, 21600, 86400},
+		}),
+		unrelayedMessages: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unrelayedMessages",
+			Help:      "number of messages sent on L1 not yet relayed on L2 by age (5m, 30m, 1h, 6h, 1d, older)",
+		}, []string{"age"}),
+		oldestUnrelayedAge: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "oldestUnrelayedAge",
+			Help:      "seconds since the L1 block of the oldest message not yet relayed on L2",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest l1 block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("l1", "known").Set(float64(latestL1Height))
+
+	if m.nextL1Height <= latestL1Height {
+		toBlock := min(latestL1Height, m.nextL1Height+m.eventBlockRange-1)
+		if err := m.processSentMessages(ctx, m.nextL1Height, toBlock); err != nil {
+			m.log.Error("failed to process the sent messages", "from", m.nextL1Height, "to", toBlock, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l1", "SentMessage").Inc()
+			return
+		}
+		m.highestBlockNumber.WithLabelValues("l1", "checked").Set(float64(toBlock))
+		m.nextL1Height = toBlock + 1
+	}
+
+	m.processL2(ctx)
+	m.reportBacklog(uint64(time.Now().Unix()))
+}
+
+// processL2 matches the relays of the next L2 blocks whose L1 origin is processed.
+func (m *Monitor) processL2(ctx context.Context) {
+	latestL2Height, err := m.l2Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest l2 block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("l2", "known").Set(float64(latestL2Height))
+
+	if m.nextL2Height > latestL2Height {
+		return
+	}
+	toBlock := min(latestL2Height, m.nextL2Height+m.eventBlockRange-1)
+
+	// The relays of the L2 blocks are matched once the messages of their L1 origin are processed.
+	origin, err := m.l1Block.Number(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(toBlock)})
+	if err != nil {
+		m.log.Error("failed to query the l1 origin", "block", toBlock, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("L1Block", "number").Inc()
+		return
+	}
+	if origin >= m.nextL1Height {
+		m.log.Info("waiting for the messages of the l1 origin", "l2_block", toBlock, "l1_origin", origin, "l1_checked", m.nextL1Height-1)
+		return
+	}
+
+	if err := m.processRelays(ctx, m.nextL2Height, toBlock); err != nil {
+		m.log.Error("failed to process the relays", "from", m.nextL2Height, "to", toBlock, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "RelayedMessage").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("l2", "checked").Set(float64(toBlock))
+	m.nextL2Height = toBlock + 1
+}
+
+// processSentMessages tracks the messages sent through the L1CrossDomainMessenger between the two L1 blocks (inclusive).
+func (m *Monitor) processSentMessages(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	logs, err := m.l1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{m.l1MessengerAddress},
+		Topics:    [][]common.Hash{{m.l1MessengerABI.Events["SentMessage"].ID, m.l1MessengerABI.Events["SentMessageExtension1"].ID}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the SentMessage events: %w", err)
+	}
+
+	// `SentMessage` is always followed by `SentMessageExtension1` carrying the value of the message.
+	var sent *bindings.L1CrossDomainMessengerSentMessage
+	timestamps := make(map[common.Hash]uint64)
+	for _, vLog := range logs {
+		if vLog.Topics[0] == m.l1MessengerABI.Events["SentMessage"].ID {
+			if sent, err = m.l1Messenger.ParseSentMessage(vLog); err != nil {
+				return fmt.Errorf("failed to parse the message %s: %w", vLog.TxHash, err)
+			}
+			continue
+		}
+
+		extension, err := m.l1Messenger.ParseSentMessageExtension1(vLog)
+		if err != nil {
+			return fmt.Errorf("failed to parse the message extension %s: %w", vLog.TxHash, err)
+		}
+		if sent == nil || sent.Raw.TxHash != vLog.TxHash {
+			m.log.Error("message extension without a message", "l1_tx", vLog.TxHash, "log_index", vLog.Index)
+			continue
+		}
+		msgHash, err := messageHash(sent, extension.Value)
+		if err != nil {
+			m.log.Error("failed to hash the message", "l1_tx", vLog.TxHash, "nonce", sent.MessageNonce, "err", err)
+			sent = nil
+			continue
+		}
+		sent = nil
+
+		timestamp, ok := timestamps[vLog.BlockHash]
+		if !ok {
+			header, err := m.l1Client.HeaderByHash(ctx, vLog.BlockHash)
+			if err != nil {
+				return fmt.Errorf("failed to query the header of %s: %w", vLog.BlockHash, err)
+			}
+			timestamp, timestamps[vLog.BlockHash] = header.Time, header.Time
+		}
+
+		m.pending[msgHash] = &pendingMessage{l1BlockNumber: vLog.BlockNumber, l1Timestamp: timestamp, l1TxHash: vLog.TxHash}
+		m.sentMessages.Inc()
+		m.log.Info("message sent", "l1_tx", vLog.TxHash, "l1_block", vLog.BlockNumber, "msg_hash", msgHash, "value", extension.Value)
+	}
+	return nil
+}
+
+// processRelays matches the relays on L2 between the two blocks (inclusive) with the pending messages.
+func (m *Monitor) processRelays(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	logs, err := m.l2Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{predeploys.L2CrossDomainMessengerAddr},
+		Topics:    [][]common.Hash{{m.l2MessengerABI.Events["RelayedMessage"].ID, m.l2MessengerABI.Events["FailedRelayedMessage"].ID}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the RelayedMessage events: %w", err)
+	}
+
+	timestamps := make(map[common.Hash]uint64)
+	for _, vLog := range logs {
+		if vLog.Topics[0] == m.l2MessengerABI.Events["FailedRelayedMessage"].ID {
+			failed, err := m.l2Messenger.ParseFailedRelayedMessage(vLog)
+			if err != nil {
+				return fmt.Errorf("failed to parse the failed relay %s: %w", vLog.TxHash, err)
+			}
+			if message, ok := m.pending[failed.MsgHash]; ok {
+				message.failedRelays++
+				m.failedRelays.Inc()
+				m.log.Warn("message relay failed", "msg_hash", common.Hash(failed.MsgHash), "l1_tx", message.l1TxHash, "l2_tx", vLog.TxHash, "failed_relays", message.failedRelays)
+				m.Update(ctx, failedRelayAlert(failed.MsgHash, message, fmt.Sprintf("relay of the message %s sent in the L1 transaction %s failed in the L2 transaction %s", common.Hash(failed.MsgHash), message.l1TxHash, vLog.TxHash)), true)
+			}
+			continue
+		}
+
+		relayed, err := m.l2Messenger.ParseRelayedMessage(vLog)
+		if err != nil {
+			return fmt.Errorf("failed to parse the relay %s: %w", vLog.TxHash, err)
+		}
+		message, ok := m.pending[relayed.MsgHash]
+		if !ok { // sent before the starting height.
+			continue
+		}
+
+		timestamp, ok := timestamps[vLog.BlockHash]
+		if !ok {
+			header, err := m.l2Client.HeaderByHash(ctx, vLog.BlockHash)
+			if err != nil {
+				return fmt.Errorf("failed to query the header of %s: %w", vLog.BlockHash, err)
+			}
+			timestamp, timestamps[vLog.BlockHash] = header.Time, header.Time
+		}
+
+		latency := float64(timestamp) - float64(message.l1Timestamp)
+		m.relayLatency.Observe(latency)
+		m.relayedMessages.Inc()
+		m.log.Info("message relayed", "msg_hash", common.Hash(relayed.MsgHash), "l1_tx", message.l1TxHash, "l2_tx", vLog.TxHash, "latency", latency, "failed_relays", message.failedRelays)
+		if message.failedRelays > 0 {
+			m.Update(ctx, failedRelayAlert(relayed.MsgHash, message, fmt.Sprintf("message %s relayed after %d failed relays", common.Hash(relayed.MsgHash), message.failedRelays)), false)
+		}
+		delete(m.pending, relayed.MsgHash)
+	}
+	return nil
+}
+
+// failedRelayAlert returns the alert of the message whose relay failed.
+func failedRelayAlert(msgHash common.Hash, message *pendingMessage, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     FailedRelayRule,
+		Priority: "P2",
+		Entity:   msgHash.String(),
+		Summary:  summary,
+		Labels:   map[string]string{"l1_tx": message.l1TxHash.String()},
+	}
+}
+
+// reportBacklog reports the messages not yet relayed by age.
+func (m *Monitor) reportBacklog(now uint64) {
+	counts := make(map[string]int, len(ageBuckets))
+	var oldest uint64
+	for _, message := range m.pending {
+		age := uint64(0)
+		if now > message.l1Timestamp {
+			age = now - message.l1Timestamp
+		}
+		counts[ageBucket(age)]++
+		oldest = max(oldest, age)
+	}
+
+	for _, bucket := range ageBuckets {
+		m.unrelayedMessages.WithLabelValues(bucket.label).Set(float64(counts[bucket.label]))
+	}
+	m.oldestUnrelayedAge.Set(float64(oldest))
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	m.l2Client.Close()
+	return nil
+}
+
+// messageHash returns the hash of the message as computed by the L2CrossDomainMessenger when relaying it.
+func messageHash(sent *bindings.L1CrossDomainMessengerSentMessage, value *big.Int) (common.Hash, error) {
+	_, version := crossdomain.DecodeVersionedNonce(sent.MessageNonce)
+	if version.Uint64() != 1 {
+		return common.Hash{}, fmt.Errorf("unsupported message version %d", version)
+	}
+	return crossdomain.HashCrossDomainMessageV1(sent.MessageNonce, sent.Sender, sent.Target, value, sent.GasLimit, sent.Message)
+}
+
+// ageBucket returns the label of the age bucket of a message.
+func ageBucket(age uint64) string {
+	for _, bucket := range ageBuckets {
+		if age <= bucket.max {
+			return bucket.label
+		}
+	}
+	return ageBuckets[len(ageBuckets)-1].label
+}
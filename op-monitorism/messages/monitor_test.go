@@ -0,0 +1,196 @@
+package messages
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-chain-ops/crossdomain"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMessageHash(t *testing.T) {
+	sent := &bindings.L1CrossDomainMessengerSentMessage{
+		Target:       common.HexToAddress("0x4200000000000000000000000000000000000010"),
+		Sender:       common.HexToAddress("0x99C9fc46f92E8a1c0deC1b1747d010903E884bE1"),
+		Message:      []byte{0xde, 0xad, 0xbe, 0xef},
+		MessageNonce: crossdomain.EncodeVersionedNonce(big.NewInt(42), big.NewInt(1)),
+		GasLimit:     big.NewInt(200_000),
+	}
+	value := big.NewInt(1_000)
+
+	// the hash of the calldata of `relayMessage` on the L2CrossDomainMessenger.
+	messengerABI, err := bindings.L2CrossDomainMessengerMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	calldata, err := messengerABI.Pack("relayMessage", sent.MessageNonce, sent.Sender, sent.Target, value, sent.GasLimit, sent.Message)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	hash, err := messageHash(sent, value)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if expected := crypto.Keccak256Hash(calldata); hash != expected {
+		t.Errorf("expected %s but got %s", expected, hash)
+	}
+
+	sent.MessageNonce = big.NewInt(42) // version 0.
+	if _, err := messageHash(sent, value); err == nil {
+		t.Errorf("expected an error for a legacy message")
+	}
+}
+
+func TestAgeBucket(t *testing.T) {
+	tests := []struct {
+		name     string
+		age      uint64
+		expected string
+	}{
+		{name: "just sent", age: 0, expected: "5m"},
+		{name: "upper bound", age: 300, expected: "5m"},
+		{name: "above the first bound", age: 301, expected: "30m"},
+		{name: "hours", age: 2 * 60 * 60, expected: "6h"},
+		{name: "a day", age: 24 * 60 * 60, expected: "1d"},
+		{name: "a week", age: 7 * 24 * 60 * 60, expected: "older"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ageBucket(test.age); got != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+var (
+	l1Messenger    = common.HexToAddress("0x25ace71c97B33Cc4729CF772ae268934F7ab5fA1")
+	l1MessengerABI = util.MustParseABI(bindings.L1CrossDomainMessengerMetaData)
+	l2MessengerABI = util.MustParseABI(bindings.L2CrossDomainMessengerMetaData)
+)
+
+// addEvent adds the event of the address to the block, in a transaction identified by the block number.
+func addEvent(t *testing.T, node *fake.Node, header *types.Header, address common.Address, event abi.Event, topics []common.Hash, args ...interface{}) {
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		t.Fatalf("failed to pack %s: %v", event.Name, err)
+	}
+	node.AddLogs(types.Log{
+		Address:     address,
+		Topics:      append([]common.Hash{event.ID}, topics...),
+		Data:        data,
+		BlockNumber: header.Number.Uint64(),
+		BlockHash:   header.Hash(),
+		TxHash:      common.BigToHash(header.Number),
+	})
+}
+
+// sendMessage adds an L1 block sending a message through the messenger, returning the hash of the message.
+func sendMessage(t *testing.T, l1 *fake.Node) common.Hash {
+	sent := &bindings.L1CrossDomainMessengerSentMessage{
+		Target:       common.HexToAddress("0x4200000000000000000000000000000000000010"),
+		Sender:       common.HexToAddress("0x99C9fc46f92E8a1c0deC1b1747d010903E884bE1"),
+		Message:      []byte{0xde, 0xad, 0xbe, 0xef},
+		MessageNonce: crossdomain.EncodeVersionedNonce(big.NewInt(42), big.NewInt(1)),
+		GasLimit:     big.NewInt(200_000),
+	}
+	header := l1.AddBlock(&types.Header{Time: uint64(time.Now().Unix())})
+	addEvent(t, l1, header, l1Messenger, l1MessengerABI.Events["SentMessage"], []common.Hash{common.BytesToHash(sent.Target.Bytes())}, sent.Sender, sent.Message, sent.MessageNonce, sent.GasLimit)
+	addEvent(t, l1, header, l1Messenger, l1MessengerABI.Events["SentMessageExtension1"], []common.Hash{common.BytesToHash(sent.Sender.Bytes())}, big.NewInt(1_000))
+	msgHash, err := messageHash(sent, big.NewInt(1_000))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	return msgHash
+}
+
+// newTestMonitor returns the monitor of the messages from the L2 block 1, whose L1 origin is served by the returned
+// L1Block, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, l1 *fake.Node, l2 *fake.Node) (*Monitor, *fake.Contract, *fake.Notifier) {
+	l1Block := l2.Contract(predeploys.L1BlockAddr, util.MustParseABI(bindings.L1BlockMetaData)).Returns("number", uint64(0))
+	cfg := CLIConfig{L1NodeURL: l1.URL, L2NodeURL: l2.URL, L1CrossDomainMessengerAddress: l1Messenger, StartBlockHeight: 1, EventBlockRange: 100}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, l1Block, notifier
+}
+
+func TestRun(t *testing.T) {
+	l1, l2 := fake.NewNode(t), fake.NewNode(t)
+	l1.AddBlock(&types.Header{})
+	l2.AddBlock(&types.Header{})
+	m, l1Block, _ := newTestMonitor(t, l1, l2)
+	ctx := context.Background()
+
+	// a sent message is unrelayed until relayed on L2.
+	msgHash := sendMessage(t, l1)
+	m.Run(ctx)
+	if sent, unrelayed := testutil.ToFloat64(m.sentMessages), testutil.ToFloat64(m.unrelayedMessages.WithLabelValues(ageBucket(0))); sent != 1 || unrelayed != 1 {
+		t.Errorf("expected 1 message sent and unrelayed but got %v and %v", sent, unrelayed)
+	}
+
+	// a failed relay is counted, the message staying unrelayed.
+	l1Block.Returns("number", uint64(1))
+	addEvent(t, l2, l2.AddBlock(&types.Header{}), predeploys.L2CrossDomainMessengerAddr, l2MessengerABI.Events["FailedRelayedMessage"], []common.Hash{msgHash})
+	m.Run(ctx)
+	if failed, unrelayed := testutil.ToFloat64(m.failedRelays), testutil.ToFloat64(m.unrelayedMessages.WithLabelValues(ageBucket(0))); failed != 1 || unrelayed != 1 {
+		t.Errorf("expected 1 failed relay of the unrelayed message but got %v and %v", failed, unrelayed)
+	}
+
+	// the relayed message is counted and no longer in the backlog.
+	addEvent(t, l2, l2.AddBlock(&types.Header{}), predeploys.L2CrossDomainMessengerAddr, l2MessengerABI.Events["RelayedMessage"], []common.Hash{msgHash})
+	m.Run(ctx)
+	if relayed, unrelayed, oldest := testutil.ToFloat64(m.relayedMessages), testutil.ToFloat64(m.unrelayedMessages.WithLabelValues(ageBucket(0))), testutil.ToFloat64(m.oldestUnrelayedAge); relayed != 1 || unrelayed != 0 || oldest != 0 {
+		t.Errorf("expected the message relayed but got %v relayed, %v unrelayed and the oldest %vs old", relayed, unrelayed, oldest)
+	}
+	if checked := testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("l2", "checked")); checked != 2 {
+		t.Errorf("expected the L2 block 2 checked but got %v", checked)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	l1, l2 := fake.NewNode(t), fake.NewNode(t)
+	l1.AddBlock(&types.Header{})
+	l2.AddBlock(&types.Header{})
+	m, l1Block, notifier := newTestMonitor(t, l1, l2)
+	ctx := context.Background()
+
+	// a failed relay fires once, even when replayed and failing again.
+	msgHash := sendMessage(t, l1)
+	l1Block.Returns("number", uint64(1))
+	addEvent(t, l2, l2.AddBlock(&types.Header{}), predeploys.L2CrossDomainMessengerAddr, l2MessengerABI.Events["FailedRelayedMessage"], []common.Hash{msgHash})
+	m.Run(ctx)
+	addEvent(t, l2, l2.AddBlock(&types.Header{}), predeploys.L2CrossDomainMessengerAddr, l2MessengerABI.Events["FailedRelayedMessage"], []common.Hash{msgHash})
+	m.Run(ctx)
+	if alerts := notifier.Alerts(FailedRelayRule); len(alerts) != 1 || alerts[0].Entity != msgHash.String() || alerts[0].Priority != "P2" || alerts[0].Labels["l1_tx"] != common.BigToHash(big.NewInt(1)).String() {
+		t.Fatalf("expected the failed relay alert once but got %v", alerts)
+	}
+
+	// the message relayed resolves the alert.
+	addEvent(t, l2, l2.AddBlock(&types.Header{}), predeploys.L2CrossDomainMessengerAddr, l2MessengerABI.Events["RelayedMessage"], []common.Hash{msgHash})
+	m.Run(ctx)
+	if firing := notifier.Firing(FailedRelayRule); len(firing) != 0 || len(m.pending) != 0 {
+		t.Fatalf("expected the failed relay alert resolved but got %v", firing)
+	}
+}
@@ -0,0 +1,512 @@
+package mint_burn
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "mint_burn"
+	MetricsNamespace = "mint_burn_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the L2 transaction.
+	UnmatchedMintRule = "mint without a deposit"
+	UnmatchedBurnRule = "burn without a withdrawal"
+)
+
+// bridgeKey identifies a transfer through the standard bridge, the same on both sides.
+type bridgeKey struct {
+	l1Token common.Address
+	l2Token common.Address
+	from    common.Address
+	to      common.Address
+	amount  string
+}
+
+// pendingDeposit is a deposit initiated on L1 not yet minted on L2.
+type pendingDeposit struct {
+	l1BlockNumber uint64
+	l1TxHash      common.Hash
+}
+
+// l2Transaction is the events of interest emitted by a single L2 transaction.
+type l2Transaction struct {
+	mints       []*bindings.OptimismMintableERC20Mint
+	burns       []*bindings.OptimismMintableERC20Burn
+	finalized   []*bindings.L2StandardBridgeDepositFinalized
+	initiated   []*bindings.L2StandardBridgeWithdrawalInitiated
+	blockNumber uint64
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+	l2Client *ethclient.Client
+	l1Block  *bindings.L1BlockCaller
+
+	l1BridgeAddress common.Address
+	l1Bridge        *bindings.L1StandardBridgeFilterer
+	l1BridgeABI     *abi.ABI
+	l2Bridge        *bindings.L2StandardBridgeFilterer
+	l2BridgeABI     *abi.ABI
+	token           *bindings.OptimismMintableERC20Filterer
+	tokenABI        *abi.ABI
+
+	tokens   map[common.Address]Token // indexed by the L2 token.
+	l1Tokens []common.Address
+
+	startingL1Height uint64
+	nextL1Height     uint64
+	nextL2Height     uint64
+	eventBlockRange  uint64
+
+	pending map[bridgeKey][]pendingDeposit
+
+	// metrics
+	highestBlockNumber  *prometheus.GaugeVec
+	deposits            *prometheus.CounterVec
+	mints               *prometheus.CounterVec
+	burns               *prometheus.CounterVec
+	unmatchedMints      *prometheus.CounterVec
+	unmatchedBurns      *prometheus.CounterVec
+	pendingDeposits     *prometheus.GaugeVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating mint/burn monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	l2Client, err := ethclient.Dial(cfg.L2NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l2: %w", err)
+	}
+
+	l1Block, err := bindings.NewL1BlockCaller(predeploys.L1BlockAddr, l2Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L1Block: %w", err)
+	}
+	l1Bridge, err := bindings.NewL1StandardBridgeFilterer(cfg.L1StandardBridgeAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L1StandardBridge: %w", err)
+	}
+	l2Bridge, err := bindings.NewL2StandardBridgeFilterer(predeploys.L2StandardBridgeAddr, l2Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L2StandardBridge: %w", err)
+	}
+	token, err := bindings.NewOptimismMintableERC20Filterer(common.Address{}, l2Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the OptimismMintableERC20: %w", err)
+	}
+
+	l1BridgeABI, err := bindings.L1StandardBridgeMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the L1StandardBridge ABI: %w", err)
+	}
+	l2BridgeABI, err := bindings.L2StandardBridgeMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the L2StandardBridge ABI: %w", err)
+	}
+	tokenABI, err := bindings.OptimismMintableERC20MetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the OptimismMintableERC20 ABI: %w", err)
+	}
+
+	tokens := make(map[common.Address]Token, len(cfg.Tokens))
+	l1Tokens := make([]common.Address, len(cfg.Tokens))
+	for i, t := range cfg.Tokens {
+		tokens[t.L2Token], l1Tokens[i] = t, t.L1Token
+		log.Info("configured token", "symbol", t.Symbol, "l1_token", t.L1Token, "l2_token", t.L2Token)
+	}
+
+	startingL2Height := cfg.StartBlockHeight
+	if startingL2Height == 0 {
+		if startingL2Height, err = l2Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+
+	// The deposits of the epoch of the starting L2 block may be minted before it, the L1 scan starts at the next epoch.
+	origin, err := l1Block.Number(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(startingL2Height)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the L1 origin of the starting height: %w", err)
+	}
+	startingL1Height := origin + 1
+	log.Info("configured starting heights", "l2_height", startingL2Height, "l1_height", startingL1Height)
+
+	return &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+		l2Client: l2Client,
+		l1Block:  l1Block,
+
+		l1BridgeAddress: cfg.L1StandardBridgeAddress,
+		l1Bridge:        l1Bridge,
+		l1BridgeABI:     l1BridgeABI,
+		l2Bridge:        l2Bridge,
+		l2BridgeABI:     l2BridgeABI,
+		token:           token,
+		tokenABI:        tokenABI,
+
+		tokens:   tokens,
+		l1Tokens: l1Tokens,
+
+		startingL1Height: startingL1Height,
+		nextL1Height:     startingL1Height,
+		nextL2Height:     startingL2Height,
+		eventBlockRange:  cfg.EventBlockRange,
+
+		pending: make(map[bridgeKey][]pendingDeposit),
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed heights (checked and known) by layer",
+		}, []string{"layer", "type"}),
+		deposits: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "deposits",
+			Help:      "number of deposits of the token initiated on L1",
+		}, []string{"token"}),
+		mints: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "mints",
+			Help:      "number of mints of the token on L2",
+		}, []string{"token"}),
+		burns: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "burns",
+			Help:      "number of burns of the token on L2",
+		}, []string{"token"}),
+		unmatchedMints: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unmatchedMints",
+			Help:      "number of mints without a matching deposit by reason (noBridgeEvent, noL1Deposit)",
+		}, []string{"token", "reason"}),
+		unmatchedBurns: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unmatchedBurns",
+			Help:      "number of burns without a matching withdrawal initiated through the L2StandardBridge",
+		}, []string{"token"}),
+		pendingDeposits: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "pendingDeposits",
+			Help:      "number of deposits initiated on L1 not yet minted on L2",
+		}, []string{"token"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest l1 block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("l1", "known").Set(float64(latestL1Height))
+
+	if m.nextL1Height <= latestL1Height {
+		toBlock := min(latestL1Height, m.nextL1Height+m.eventBlockRange-1)
+		if err := m.processDeposits(ctx, m.nextL1Height, toBlock); err != nil {
+			m.log.Error("failed to process the deposits", "from", m.nextL1Height, "to", toBlock, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l1", "ERC20DepositInitiated").Inc()
+			return
+		}
+		m.highestBlockNumber.WithLabelValues("l1", "checked").Set(float64(toBlock))
+		m.nextL1Height = toBlock + 1
+	}
+
+	m.processL2(ctx)
+
+	pending := make(map[string]int, len(m.tokens))
+	for key, deposits := range m.pending {
+		pending[m.tokens[key.l2Token].Symbol] += len(deposits)
+	}
+	for _, t := range m.tokens {
+		m.pendingDeposits.WithLabelValues(t.Symbol).Set(float64(pending[t.Symbol]))
+	}
+}
+
+// processL2 matches the mints and burns of the next L2 blocks whose L1 origin is processed.
+func (m *Monitor) processL2(ctx context.Context) {
+	latestL2Height, err := m.l2Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest l2 block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("l2", "known").Set(float64(latestL2Height))
+
+	if m.nextL2Height <= latestL2Height {
+		toBlock := min(latestL2Height, m.nextL2Height+m.eventBlockRange-1)
+
+		// The mints of the L2 blocks are matched once the deposits of their L1 origin are processed.
+		origin, err := m.l1Block.Number(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(toBlock)})
+		if err != nil {
+			m.log.Error("failed to query the l1 origin", "block", toBlock, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("L1Block", "number").Inc()
+			return
+		}
+		if origin >= m.nextL1Height {
+			m.log.Info("waiting for the deposits of the l1 origin", "l2_block", toBlock, "l1_origin", origin, "l1_checked", m.nextL1Height-1)
+			return
+		}
+
+		if err := m.processMintsAndBurns(ctx, m.nextL2Height, toBlock); err != nil {
+			m.log.Error("failed to process the mints and burns", "from", m.nextL2Height, "to", toBlock, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l2", "MintBurn").Inc()
+			return
+		}
+		m.highestBlockNumber.WithLabelValues("l2", "checked").Set(float64(toBlock))
+		m.nextL2Height = toBlock + 1
+	}
+}
+
+// processDeposits tracks the deposits of the tokens initiated on L1 between the two blocks (inclusive).
+func (m *Monitor) processDeposits(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	l1TokenTopics := make([]common.Hash, len(m.l1Tokens))
+	for i, l1Token := range m.l1Tokens {
+		l1TokenTopics[i] = common.BytesToHash(l1Token.Bytes())
+	}
+	logs, err := m.l1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{m.l1BridgeAddress},
+		Topics:    [][]common.Hash{{m.l1BridgeABI.Events["ERC20DepositInitiated"].ID}, l1TokenTopics},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the ERC20DepositInitiated events: %w", err)
+	}
+
+	for _, vLog := range logs {
+		deposit, err := m.l1Bridge.ParseERC20DepositInitiated(vLog)
+		if err != nil {
+			return fmt.Errorf("failed to parse the deposit %s: %w", vLog.TxHash, err)
+		}
+		t, ok := m.tokens[deposit.L2Token]
+		if !ok || t.L1Token != deposit.L1Token { // bridged to another L2 token.
+			continue
+		}
+
+		key := bridgeKey{l1Token: deposit.L1Token, l2Token: deposit.L2Token, from: deposit.From, to: deposit.To, amount: deposit.Amount.String()}
+		m.pending[key] = append(m.pending[key], pendingDeposit{l1BlockNumber: vLog.BlockNumber, l1TxHash: vLog.TxHash})
+		m.deposits.WithLabelValues(t.Symbol).Inc()
+		m.log.Info("deposit", "token", t.Symbol, "l1_tx", vLog.TxHash, "l1_block", vLog.BlockNumber, "from", deposit.From, "to", deposit.To, "amount", deposit.Amount)
+	}
+	return nil
+}
+
+// processMintsAndBurns matches the mints and burns of the tokens between the two L2 blocks (inclusive) with the bridge events.
+func (m *Monitor) processMintsAndBurns(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	addresses := []common.Address{predeploys.L2StandardBridgeAddr}
+	for l2Token := range m.tokens {
+		addresses = append(addresses, l2Token)
+	}
+	logs, err := m.l2Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: addresses,
+		Topics: [][]common.Hash{{
+			m.tokenABI.Events["Mint"].ID,
+			m.tokenABI.Events["Burn"].ID,
+			m.l2BridgeABI.Events["DepositFinalized"].ID,
+			m.l2BridgeABI.Events["WithdrawalInitiated"].ID,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the events: %w", err)
+	}
+
+	txs, err := m.groupByTransaction(logs)
+	if err != nil {
+		return err
+	}
+
+	origins := make(map[uint64]uint64)
+	for _, txHash := range txs.order {
+		tx := txs.byHash[txHash]
+		if len(tx.mints) > 0 {
+			origin, ok := origins[tx.blockNumber]
+			if !ok {
+				if origin, err = m.l1Block.Number(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(tx.blockNumber)}); err != nil {
+					return fmt.Errorf("failed to query the l1 origin of the block %d: %w", tx.blockNumber, err)
+				}
+				origins[tx.blockNumber] = origin
+			}
+			m.checkMints(ctx, txHash, tx, origin)
+		}
+		m.checkBurns(ctx, txHash, tx)
+	}
+	return nil
+}
+
+// transactions is the L2 transactions in the order of their events.
+type transactions struct {
+	order  []common.Hash
+	byHash map[common.Hash]*l2Transaction
+}
+
+// groupByTransaction groups the events by L2 transaction, ignoring the bridge events of other tokens.
+func (m *Monitor) groupByTransaction(logs []types.Log) (*transactions, error) {
+	txs := &transactions{byHash: make(map[common.Hash]*l2Transaction)}
+	for _, vLog := range logs {
+		tx, ok := txs.byHash[vLog.TxHash]
+		if !ok {
+			tx = &l2Transaction{blockNumber: vLog.BlockNumber}
+			txs.byHash[vLog.TxHash] = tx
+			txs.order = append(txs.order, vLog.TxHash)
+		}
+
+		var err error
+		switch {
+		case vLog.Address == predeploys.L2StandardBridgeAddr && vLog.Topics[0] == m.l2BridgeABI.Events["DepositFinalized"].ID:
+			var finalized *bindings.L2StandardBridgeDepositFinalized
+			if finalized, err = m.l2Bridge.ParseDepositFinalized(vLog); err == nil {
+				tx.finalized = append(tx.finalized, finalized)
+			}
+		case vLog.Address == predeploys.L2StandardBridgeAddr && vLog.Topics[0] == m.l2BridgeABI.Events["WithdrawalInitiated"].ID:
+			var initiated *bindings.L2StandardBridgeWithdrawalInitiated
+			if initiated, err = m.l2Bridge.ParseWithdrawalInitiated(vLog); err == nil {
+				tx.initiated = append(tx.initiated, initiated)
+			}
+		case vLog.Address != predeploys.L2StandardBridgeAddr && vLog.Topics[0] == m.tokenABI.Events["Mint"].ID:
+			var mint *bindings.OptimismMintableERC20Mint
+			if mint, err = m.token.ParseMint(vLog); err == nil {
+				tx.mints = append(tx.mints, mint)
+			}
+		case vLog.Address != predeploys.L2StandardBridgeAddr && vLog.Topics[0] == m.tokenABI.Events["Burn"].ID:
+			var burn *bindings.OptimismMintableERC20Burn
+			if burn, err = m.token.ParseBurn(vLog); err == nil {
+				tx.burns = append(tx.burns, burn)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the event %s of %s: %w", vLog.Topics[0], vLog.TxHash, err)
+		}
+	}
+	return txs, nil
+}
+
+// checkMints matches the mints of the transaction with the deposits finalized by the L2StandardBridge, and these deposits with the ones initiated on L1.
+func (m *Monitor) checkMints(ctx context.Context, txHash common.Hash, tx *l2Transaction, origin uint64) {
+	used := make([]bool, len(tx.finalized))
+	for _, mint := range tx.mints {
+		t := m.tokens[mint.Raw.Address]
+		m.mints.WithLabelValues(t.Symbol).Inc()
+
+		i := matchDepositFinalized(mint.Raw.Address, mint.Account, mint.Amount, tx.finalized, used)
+		if i < 0 {
+			m.log.Error("mint without a deposit finalized by the L2StandardBridge", "token", t.Symbol, "l2_tx", txHash, "l2_block", tx.blockNumber, "account", mint.Account, "amount", mint.Amount)
+			m.unmatchedMints.WithLabelValues(t.Symbol, "noBridgeEvent").Inc()
+			m.Emit(ctx, alert(UnmatchedMintRule, "P0", txHash, t.Symbol, fmt.Sprintf("%s %s minted to %s without a deposit finalized by the L2StandardBridge", mint.Amount, t.Symbol, mint.Account)))
+			continue
+		}
+		used[i] = true
+
+		finalized := tx.finalized[i]
+		key := bridgeKey{l1Token: finalized.L1Token, l2Token: finalized.L2Token, from: finalized.From, to: finalized.To, amount: finalized.Amount.String()}
+		deposits := m.pending[key]
+		if len(deposits) == 0 {
+			if origin < m.startingL1Height { // deposit initiated before the starting height.
+				m.log.Info("mint of a deposit initiated before the starting height", "token", t.Symbol, "l2_tx", txHash, "l1_origin", origin)
+				continue
+			}
+			m.log.Error("mint without a matching deposit initiated on L1", "token", t.Symbol, "l2_tx", txHash, "l2_block", tx.blockNumber, "from", finalized.From, "to", finalized.To, "amount", finalized.Amount)
+			m.unmatchedMints.WithLabelValues(t.Symbol, "noL1Deposit").Inc()
+			m.Emit(ctx, alert(UnmatchedMintRule, "P0", txHash, t.Symbol, fmt.Sprintf("%s %s minted to %s without a matching deposit initiated on L1", finalized.Amount, t.Symbol, finalized.To)))
+			continue
+		}
+
+		m.log.Info("mint matched", "token", t.Symbol, "l2_tx", txHash, "l1_tx", deposits[0].l1TxHash, "l1_block", deposits[0].l1BlockNumber, "amount", finalized.Amount)
+		if len(deposits) == 1 {
+			delete(m.pending, key)
+		} else {
+			m.pending[key] = deposits[1:]
+		}
+	}
+}
+
+// checkBurns matches the burns of the transaction with the withdrawals initiated through the L2StandardBridge.
+func (m *Monitor) checkBurns(ctx context.Context, txHash common.Hash, tx *l2Transaction) {
+	used := make([]bool, len(tx.initiated))
+	for _, burn := range tx.burns {
+		t := m.tokens[burn.Raw.Address]
+		m.burns.WithLabelValues(t.Symbol).Inc()
+
+		i := matchWithdrawalInitiated(burn.Raw.Address, burn.Account, burn.Amount, tx.initiated, used)
+		if i < 0 {
+			m.log.Error("burn without a withdrawal initiated through the L2StandardBridge", "token", t.Symbol, "l2_tx", txHash, "l2_block", tx.blockNumber, "account", burn.Account, "amount", burn.Amount)
+			m.unmatchedBurns.WithLabelValues(t.Symbol).Inc()
+			m.Emit(ctx, alert(UnmatchedBurnRule, "P1", txHash, t.Symbol, fmt.Sprintf("%s %s burnt from %s without a withdrawal initiated through the L2StandardBridge", burn.Amount, t.Symbol, burn.Account)))
+			continue
+		}
+		used[i] = true
+	}
+}
+
+// alert returns the alert of the rule about the L2 transaction.
+func alert(rule string, priority string, txHash common.Hash, symbol string, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   txHash.String(),
+		Summary:  summary,
+		Labels:   map[string]string{"token": symbol},
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	m.l2Client.Close()
+	return nil
+}
+
+// matchDepositFinalized returns the index of the first unused deposit finalized for the mint, -1 if none.
+func matchDepositFinalized(l2Token common.Address, account common.Address, amount *big.Int, finalized []*bindings.L2StandardBridgeDepositFinalized, used []bool) int {
+	for i, f := range finalized {
+		if !used[i] && f.L2Token == l2Token && f.To == account && f.Amount.Cmp(amount) == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchWithdrawalInitiated returns the index of the first unused withdrawal initiated for the burn, -1 if none.
+func matchWithdrawalInitiated(l2Token common.Address, account common.Address, amount *big.Int, initiated []*bindings.L2StandardBridgeWithdrawalInitiated, used []bool) int {
+	for i, w := range initiated {
+		if !used[i] && w.L2Token == l2Token && w.From == account && w.Amount.Cmp(amount) == 0 {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,220 @@
+package mint_burn
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMatchDepositFinalized(t *testing.T) {
+	token, other := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	alice, bob := common.HexToAddress("0xa1"), common.HexToAddress("0xb0")
+	finalized := []*bindings.L2StandardBridgeDepositFinalized{
+		{L2Token: token, To: alice, Amount: big.NewInt(100)},
+		{L2Token: other, To: bob, Amount: big.NewInt(50)},
+		{L2Token: token, To: alice, Amount: big.NewInt(100)},
+	}
+
+	tests := []struct {
+		name     string
+		l2Token  common.Address
+		account  common.Address
+		amount   int64
+		used     []bool
+		expected int
+	}{
+		{name: "first match", l2Token: token, account: alice, amount: 100, used: []bool{false, false, false}, expected: 0},
+		{name: "first match used", l2Token: token, account: alice, amount: 100, used: []bool{true, false, false}, expected: 2},
+		{name: "all matches used", l2Token: token, account: alice, amount: 100, used: []bool{true, false, true}, expected: -1},
+		{name: "other token", l2Token: other, account: bob, amount: 50, used: []bool{false, false, false}, expected: 1},
+		{name: "different amount", l2Token: token, account: alice, amount: 101, used: []bool{false, false, false}, expected: -1},
+		{name: "different account", l2Token: token, account: bob, amount: 100, used: []bool{false, false, false}, expected: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := matchDepositFinalized(test.l2Token, test.account, big.NewInt(test.amount), finalized, test.used); got != test.expected {
+				t.Errorf("Failed %s: expected %d but got %d", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatchWithdrawalInitiated(t *testing.T) {
+	token := common.HexToAddress("0x01")
+	alice, bob := common.HexToAddress("0xa1"), common.HexToAddress("0xb0")
+	initiated := []*bindings.L2StandardBridgeWithdrawalInitiated{
+		{L2Token: token, From: alice, To: bob, Amount: big.NewInt(100)},
+	}
+
+	if got := matchWithdrawalInitiated(token, alice, big.NewInt(100), initiated, []bool{false}); got != 0 {
+		t.Errorf("expected the withdrawal to match but got %d", got)
+	}
+	if got := matchWithdrawalInitiated(token, bob, big.NewInt(100), initiated, []bool{false}); got != -1 {
+		t.Errorf("expected the recipient not to match the burnt account but got %d", got)
+	}
+	if got := matchWithdrawalInitiated(token, alice, big.NewInt(100), initiated, []bool{true}); got != -1 {
+		t.Errorf("expected a used withdrawal not to match but got %d", got)
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	token, err := parseToken("USDC:0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48:0x7F5c764cBc14f9669B88837ca1490cCa17c31607")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if token.Symbol != "USDC" || token.L2Token != common.HexToAddress("0x7F5c764cBc14f9669B88837ca1490cCa17c31607") {
+		t.Errorf("unexpected token %+v", token)
+	}
+
+	for _, invalid := range []string{"USDC:0x01", ":0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48:0x7F5c764cBc14f9669B88837ca1490cCa17c31607", "USDC:0x01:0x02"} {
+		if _, err := parseToken(invalid); err == nil {
+			t.Errorf("expected an error for %s", invalid)
+		}
+	}
+}
+
+var (
+	l1Bridge    = common.HexToAddress("0x99C9fc46f92E8a1c0deC1b1747d010903E884bE1")
+	usdc        = Token{Symbol: "USDC", L1Token: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), L2Token: common.HexToAddress("0x7F5c764cBc14f9669B88837ca1490cCa17c31607")}
+	account     = common.HexToAddress("0x0a")
+	l1BridgeABI = util.MustParseABI(bindings.L1StandardBridgeMetaData)
+	l2BridgeABI = util.MustParseABI(bindings.L2StandardBridgeMetaData)
+	tokenABI    = util.MustParseABI(bindings.OptimismMintableERC20MetaData)
+)
+
+// addEvent adds the event of the address to the block, in a transaction identified by the block number.
+func addEvent(t *testing.T, node *fake.Node, header *types.Header, address common.Address, event abi.Event, topics []common.Hash, args ...interface{}) {
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		t.Fatalf("failed to pack %s: %v", event.Name, err)
+	}
+	node.AddLogs(types.Log{
+		Address:     address,
+		Topics:      append([]common.Hash{event.ID}, topics...),
+		Data:        data,
+		BlockNumber: header.Number.Uint64(),
+		BlockHash:   header.Hash(),
+		TxHash:      common.BigToHash(header.Number),
+	})
+}
+
+// bridgeTopics are the indexed tokens and sender of the bridge events of the account.
+func bridgeTopics() []common.Hash {
+	return []common.Hash{common.BytesToHash(usdc.L1Token.Bytes()), common.BytesToHash(usdc.L2Token.Bytes()), common.BytesToHash(account.Bytes())}
+}
+
+// addMint adds an L2 transaction minting the amount to the account, with the deposit finalized by the bridge when set.
+func addMint(t *testing.T, l2 *fake.Node, amount int64, finalized bool) common.Hash {
+	header := l2.AddBlock(&types.Header{})
+	if finalized {
+		addEvent(t, l2, header, predeploys.L2StandardBridgeAddr, l2BridgeABI.Events["DepositFinalized"], bridgeTopics(), account, big.NewInt(amount), []byte{})
+	}
+	addEvent(t, l2, header, usdc.L2Token, tokenABI.Events["Mint"], []common.Hash{common.BytesToHash(account.Bytes())}, big.NewInt(amount))
+	return common.BigToHash(header.Number)
+}
+
+// newTestMonitor returns the monitor of the USDC from the L2 block 1, whose L1 origin is served by the returned L1Block,
+// and the notifier of its alerts.
+func newTestMonitor(t *testing.T, l1 *fake.Node, l2 *fake.Node) (*Monitor, *fake.Contract, *fake.Notifier) {
+	l1Block := l2.Contract(predeploys.L1BlockAddr, util.MustParseABI(bindings.L1BlockMetaData)).Returns("number", uint64(0))
+	cfg := CLIConfig{L1NodeURL: l1.URL, L2NodeURL: l2.URL, L1StandardBridgeAddress: l1Bridge, Tokens: []Token{usdc}, StartBlockHeight: 1, EventBlockRange: 100}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, l1Block, notifier
+}
+
+func TestRun(t *testing.T) {
+	l1, l2 := fake.NewNode(t), fake.NewNode(t)
+	l1.AddBlock(&types.Header{})
+	l2.AddBlock(&types.Header{})
+	m, l1Block, _ := newTestMonitor(t, l1, l2)
+	ctx := context.Background()
+
+	// a deposit initiated on L1 is pending until minted.
+	addEvent(t, l1, l1.AddBlock(&types.Header{}), l1Bridge, l1BridgeABI.Events["ERC20DepositInitiated"], bridgeTopics(), account, big.NewInt(100), []byte{})
+	m.Run(ctx)
+	if deposits, pending := testutil.ToFloat64(m.deposits.WithLabelValues("USDC")), testutil.ToFloat64(m.pendingDeposits.WithLabelValues("USDC")); deposits != 1 || pending != 1 {
+		t.Errorf("expected 1 deposit pending but got %v deposits and %v pending", deposits, pending)
+	}
+
+	// the L2 blocks wait for the deposits of their L1 origin.
+	l1Block.Returns("number", uint64(2))
+	addMint(t, l2, 100, true)
+	m.Run(ctx)
+	if checked := testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("l2", "checked")); checked != 0 {
+		t.Errorf("expected the L2 blocks waiting for their L1 origin but got %v checked", checked)
+	}
+
+	// the mint matching the deposit is counted, the deposit no longer pending.
+	l1Block.Returns("number", uint64(1))
+	m.Run(ctx)
+	if mints, pending, checked := testutil.ToFloat64(m.mints.WithLabelValues("USDC")), testutil.ToFloat64(m.pendingDeposits.WithLabelValues("USDC")), testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("l2", "checked")); mints != 1 || pending != 0 || checked != 1 {
+		t.Errorf("expected the mint of the block 1 matched but got %v mints, %v pending and %v checked", mints, pending, checked)
+	}
+
+	// the unmatched mints and burns are counted by reason.
+	addMint(t, l2, 50, false)
+	unmatched := l2.AddBlock(&types.Header{})
+	addEvent(t, l2, unmatched, usdc.L2Token, tokenABI.Events["Burn"], []common.Hash{common.BytesToHash(account.Bytes())}, big.NewInt(10))
+	m.Run(ctx)
+	if noBridgeEvent, burns, unmatchedBurns := testutil.ToFloat64(m.unmatchedMints.WithLabelValues("USDC", "noBridgeEvent")), testutil.ToFloat64(m.burns.WithLabelValues("USDC")), testutil.ToFloat64(m.unmatchedBurns.WithLabelValues("USDC")); noBridgeEvent != 1 || burns != 1 || unmatchedBurns != 1 {
+		t.Errorf("expected the unmatched mint and burn but got %v unmatched mints, %v burns and %v unmatched burns", noBridgeEvent, burns, unmatchedBurns)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	l1, l2 := fake.NewNode(t), fake.NewNode(t)
+	l1.AddBlock(&types.Header{})
+	l2.AddBlock(&types.Header{})
+	m, l1Block, notifier := newTestMonitor(t, l1, l2)
+	ctx := context.Background()
+
+	// a mint of a deposit initiated on L1 is matched.
+	addEvent(t, l1, l1.AddBlock(&types.Header{}), l1Bridge, l1BridgeABI.Events["ERC20DepositInitiated"], bridgeTopics(), account, big.NewInt(100), []byte{})
+	l1Block.Returns("number", uint64(1))
+	addMint(t, l2, 100, true)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 || len(m.pending) != 0 {
+		t.Fatalf("expected the mint matched but got %v", alerts)
+	}
+
+	// the mints without a deposit finalized by the bridge or initiated on L1 fire once.
+	noBridgeEvent, noL1Deposit := addMint(t, l2, 50, false), addMint(t, l2, 70, true)
+	m.Run(ctx)
+	m.Run(ctx)
+	alerts := notifier.Alerts(UnmatchedMintRule)
+	if len(alerts) != 2 || alerts[0].Entity != noBridgeEvent.String() || alerts[1].Entity != noL1Deposit.String() || alerts[0].Priority != "P0" || alerts[0].Labels["token"] != "USDC" {
+		t.Fatalf("expected the unmatched mint alerts once but got %v", alerts)
+	}
+
+	// a burn without a withdrawal initiated through the bridge fires, while a withdrawal doesn't.
+	header := l2.AddBlock(&types.Header{})
+	addEvent(t, l2, header, predeploys.L2StandardBridgeAddr, l2BridgeABI.Events["WithdrawalInitiated"], bridgeTopics(), account, big.NewInt(10), []byte{})
+	addEvent(t, l2, header, usdc.L2Token, tokenABI.Events["Burn"], []common.Hash{common.BytesToHash(account.Bytes())}, big.NewInt(10))
+	unmatched := l2.AddBlock(&types.Header{})
+	addEvent(t, l2, unmatched, usdc.L2Token, tokenABI.Events["Burn"], []common.Hash{common.BytesToHash(account.Bytes())}, big.NewInt(10))
+	m.Run(ctx)
+	if alerts := notifier.Alerts(UnmatchedBurnRule); len(alerts) != 1 || alerts[0].Entity != common.BigToHash(unmatched.Number).String() || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the unmatched burn alert once but got %v", alerts)
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+
 	"github.com/ethereum-optimism/optimism/op-service/cliapp"
 	"github.com/ethereum-optimism/optimism/op-service/clock"
 	"github.com/ethereum-optimism/optimism/op-service/httputil"
@@ -39,6 +41,15 @@ type cliApp struct {
 
 	monitor Monitor
 
+	// dispatcher delivers the alerts of the monitor, nil when it doesn't emit any, the escalations loop
+	// re-notifying and escalating its unresolved alerts.
+	dispatcher  *alerts.Dispatcher
+	escalations *clock.LoopFn
+	audit       *alerts.AuditLog
+	apiAddr     string
+	apiToken    string
+	apiSrv      *httputil.HTTPServer
+
 	registry   *prometheus.Registry
 	metricsCfg opmetrics.CLIConfig
 	metricsSrv *httputil.HTTPServer
@@ -50,10 +61,56 @@ func NewCliApp(ctx *cli.Context, log log.Logger, registry *prometheus.Registry,
 		return nil, errors.New("zero loop interval configured")
 	}
 
+	alertsCfg, err := alerts.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse alerts config from flags: %w", err)
+	}
+	notifiers, err := alertsCfg.Notifiers()
+	if err != nil {
+		return nil, err
+	}
+	var dispatcher *alerts.Dispatcher
+	var audit *alerts.AuditLog
+	if len(notifiers) > 0 || len(alertsCfg.AuditLogPath) > 0 {
+		emitter, ok := monitor.(alerts.Emitter)
+		if !ok {
+			return nil, errors.New("alert notifiers or audit log configured for a monitor not emitting alerts")
+		}
+		var routing *alerts.Routing
+		if len(alertsCfg.RoutingPath) > 0 {
+			if routing, err = alerts.LoadRouting(alertsCfg.RoutingPath, notifiers); err != nil {
+				return nil, err
+			}
+		}
+		var silences *alerts.Silences
+		if len(alertsCfg.SilencesPath) > 0 {
+			if silences, err = alerts.LoadSilences(alertsCfg.SilencesPath); err != nil {
+				return nil, err
+			}
+		}
+		if len(alertsCfg.AuditLogPath) > 0 {
+			if audit, err = alerts.OpenAuditLog(alertsCfg.AuditLogPath); err != nil {
+				return nil, err
+			}
+		}
+		dispatcher = alerts.NewDispatcher(log, opmetrics.With(registry), notifiers, alerts.DispatcherConfig{
+			Dedup:     alerts.NewDeduplicator(alertsCfg.Cooldown, alertsCfg.Cooldowns),
+			Silences:  silences,
+			Routing:   routing,
+			Templates: alertsCfg.MessageTemplates,
+			Audit:     audit,
+		})
+		emitter.SetNotifier(dispatcher)
+	}
+
 	return &cliApp{
 		log:            log,
 		loopIntervalMs: loopIntervalMs,
 		monitor:        monitor,
+		dispatcher:     dispatcher,
+		audit:          audit,
+		apiAddr:        alertsCfg.APIAddr,
+		apiToken:       alertsCfg.APIToken,
 		registry:       registry,
 		metricsCfg:     opmetrics.ReadCLIConfig(ctx),
 	}, nil
@@ -61,12 +118,13 @@ func NewCliApp(ctx *cli.Context, log log.Logger, registry *prometheus.Registry,
 
 func DefaultCLIFlags(envVarPrefix string) []cli.Flag {
 	defaultFlags := append(oplog.CLIFlags(envVarPrefix), opmetrics.CLIFlags(envVarPrefix)...)
-	return append(defaultFlags, &cli.Uint64Flag{
+	defaultFlags = append(defaultFlags, &cli.Uint64Flag{
 		Name:    LoopIntervalMsecFlagName,
 		Usage:   "Loop interval of the monitor in milliseconds",
 		Value:   60_000,
 		EnvVars: opservice.PrefixEnvVar(envVarPrefix, "LOOP_INTERVAL_MSEC"),
 	})
+	return append(defaultFlags, alerts.CLIFlags(envVarPrefix)...)
 }
 
 func (app *cliApp) Start(ctx context.Context) error {
@@ -79,6 +137,13 @@ func (app *cliApp) Start(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to start metrics server: %w", err)
 	}
+	if app.dispatcher != nil && len(app.apiAddr) > 0 {
+		app.log.Info("starting alerts api", "addr", app.apiAddr)
+		if app.apiSrv, err = httputil.StartHTTPServer(app.apiAddr, alerts.NewAPIHandler(app.log, app.dispatcher, app.apiToken)); err != nil {
+			_ = srv.Close()
+			return fmt.Errorf("failed to start alerts api: %w", err)
+		}
+	}
 
 	app.log.Info("starting monitor...", "loop_interval_ms", app.loopIntervalMs)
 
@@ -86,6 +151,9 @@ func (app *cliApp) Start(ctx context.Context) error {
 	app.monitor.Run(ctx)
 
 	app.worker = clock.NewLoopFn(clock.SystemClock, app.monitor.Run, nil, time.Millisecond*time.Duration(app.loopIntervalMs))
+	if app.dispatcher != nil {
+		app.escalations = clock.NewLoopFn(clock.SystemClock, app.dispatcher.Escalate, nil, alerts.EscalationInterval)
+	}
 	app.metricsSrv = srv
 	return nil
 }
@@ -99,9 +167,24 @@ func (app *cliApp) Stop(ctx context.Context) error {
 	if err := app.worker.Close(); err != nil {
 		app.log.Error("error stopping worker loop", "err", err)
 	}
+	if app.escalations != nil {
+		if err := app.escalations.Close(); err != nil {
+			app.log.Error("error stopping escalations loop", "err", err)
+		}
+	}
+	if app.apiSrv != nil {
+		if err := app.apiSrv.Close(); err != nil {
+			app.log.Error("error closing alerts api", "err", err)
+		}
+	}
 	if err := app.monitor.Close(ctx); err != nil {
 		app.log.Error("error closing monitor", "err", err)
 	}
+	if app.audit != nil {
+		if err := app.audit.Close(); err != nil {
+			app.log.Error("error closing audit log", "err", err)
+		}
+	}
 	if err := app.metricsSrv.Close(); err != nil {
 		app.log.Error("error closing metrics server", "err", err)
 	}
@@ -17,6 +17,7 @@ const (
 	OptimismPortalAddressFlagName = "optimismportal.address"
 	SafeAddressFlagName           = "safe.address"
 	OnePassVaultFlagName          = "op.vault"
+	PresignedDirFlagName          = "presigned.dir"
 )
 
 type CLIConfig struct {
@@ -27,6 +28,7 @@ type CLIConfig struct {
 	// Optional
 	SafeAddress  *common.Address
 	OnePassVault *string
+	PresignedDir *string
 }
 
 func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
@@ -55,6 +57,14 @@ func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
 		cfg.OnePassVault = &onePassVault
 	}
 
+	presignedDir := ctx.String(PresignedDirFlagName)
+	if len(presignedDir) > 0 {
+		cfg.PresignedDir = &presignedDir
+	}
+	if cfg.OnePassVault != nil && cfg.PresignedDir != nil {
+		return cfg, fmt.Errorf("--%s and --%s are mutually exclusive", OnePassVaultFlagName, PresignedDirFlagName)
+	}
+
 	return cfg, nil
 }
 
@@ -88,5 +98,10 @@ func CLIFlags(envVar string) []cli.Flag {
 			Usage:   "1Pass vault name storing presigned safe txs following a 'ready-<nonce>.json' item name format",
 			EnvVars: opservice.PrefixEnvVar(envVar, "1PASS_VAULT_NAME"),
 		},
+		&cli.StringFlag{
+			Name:    PresignedDirFlagName,
+			Usage:   "Directory storing presigned safe txs following a 'ready-<nonce>.json' file name format, instead of 1Pass",
+			EnvVars: opservice.PrefixEnvVar(envVar, "PRESIGNED_DIR"),
+		},
 	}
 }
@@ -10,9 +10,11 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -24,21 +26,36 @@ import (
 )
 
 const (
+	MonitorName      = "multisig"
 	MetricsNamespace = "multisig_mon"
 	SafeNonceABI     = "nonce()"
 
+	// Rules of the alerts emitted by the monitor.
+	PortalPausedRule     = "OptimismPortal paused"
+	NoPresignedPauseRule = "no presigned pause transaction"
+	HashApprovedRule     = "hash approved"
+
 	OPTokenEnvName = "OP_SERVICE_ACCOUNT_TOKEN"
 
 	// Item names follow a `ready-<nonce>.json` format
 	PresignedNonceTitlePrefix = "ready-"
 	PresignedNonceTitleSuffix = ".json"
+
+	// MaxApproveHashBlockRange is the maximum number of blocks scanned for `ApproveHash` events per iteration.
+	MaxApproveHashBlockRange = 1000
 )
 
 var (
 	SafeNonceSelector = crypto.Keccak256([]byte(SafeNonceABI))[:4]
+
+	// ApproveHashTopic is the topic of `ApproveHash(bytes32 indexed approvedHash, address indexed owner)`.
+	ApproveHashTopic = crypto.Keccak256Hash([]byte("ApproveHash(bytes32,address)"))
 )
 
 type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
 	log log.Logger
 
 	l1Client *ethclient.Client
@@ -49,11 +66,17 @@ type Monitor struct {
 
 	//onePassToken string
 	onePassVault *string
+	presignedDir *string
 	safeAddress  *common.Address
 
+	// next block scanned for `ApproveHash` events, 0 until the first iteration.
+	nextApproveHashBlock uint64
+
 	// metrics
 	safeNonce                 *prometheus.GaugeVec
 	latestPresignedPauseNonce *prometheus.GaugeVec
+	remainingPresignedPause   *prometheus.GaugeVec
+	approveHashEvents         *prometheus.CounterVec
 	pausedState               *prometheus.GaugeVec
 	unexpectedRpcErrors       *prometheus.CounterVec
 }
@@ -73,7 +96,7 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 		return nil, fmt.Errorf("%s ENV name must be set for 1Pass integration", OPTokenEnvName)
 	}
 
-	if cfg.OnePassVault == nil {
+	if cfg.OnePassVault == nil && cfg.PresignedDir == nil {
 		log.Warn("one pass integration is not configured")
 	}
 	if cfg.SafeAddress == nil {
@@ -90,6 +113,7 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 
 		safeAddress:  cfg.SafeAddress,
 		onePassVault: cfg.OnePassVault,
+		presignedDir: cfg.PresignedDir,
 
 		safeNonce: m.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: MetricsNamespace,
@@ -101,6 +125,16 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 			Name:      "latestPresignedPauseNonce",
 			Help:      "Latest pre-signed pause nonce",
 		}, []string{"address", "nickname"}),
+		remainingPresignedPause: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "remainingPresignedPause",
+			Help:      "Number of consecutive pre-signed pause transactions valid from the current safe nonce",
+		}, []string{"address", "nickname"}),
+		approveHashEvents: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "approveHashEvents",
+			Help:      "Number of ApproveHash events emitted by the safe",
+		}, []string{"address", "nickname", "owner"}),
 		pausedState: m.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: MetricsNamespace,
 			Name:      "pausedState",
@@ -116,8 +150,9 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 
 func (m *Monitor) Run(ctx context.Context) {
 	m.checkOptimismPortal(ctx)
-	m.checkSafeNonce(ctx)
-	m.checkPresignedNonce(ctx)
+	nonce, ok := m.checkSafeNonce(ctx)
+	m.checkApproveHashes(ctx)
+	m.checkPresignedNonce(ctx, nonce, ok)
 }
 
 func (m *Monitor) checkOptimismPortal(ctx context.Context) {
@@ -134,13 +169,15 @@ func (m *Monitor) checkOptimismPortal(ctx context.Context) {
 	}
 
 	m.pausedState.WithLabelValues(m.optimismPortalAddress.String(), m.nickname).Set(float64(pausedMetric))
+	m.Update(ctx, m.alert(PortalPausedRule, "P1", m.optimismPortalAddress.String(), fmt.Sprintf("the OptimismPortal of %s is paused", m.nickname)), paused)
 	m.log.Info("OptimismPortal status", "address", m.optimismPortalAddress.String(), "paused", paused)
 }
 
-func (m *Monitor) checkSafeNonce(ctx context.Context) {
+// checkSafeNonce reports the nonce of the safe and returns it, `false` is returned when it is unknown.
+func (m *Monitor) checkSafeNonce(ctx context.Context) (uint64, bool) {
 	if m.safeAddress == nil {
 		m.log.Warn("safe address is not configured, skipping...")
-		return
+		return 0, false
 	}
 
 	nonceBytes := hexutil.Bytes{}
@@ -148,59 +185,192 @@ func (m *Monitor) checkSafeNonce(ctx context.Context) {
 	if err := m.l1Client.Client().CallContext(ctx, &nonceBytes, "eth_call", nonceTx, "latest"); err != nil {
 		m.log.Error("failed to query safe nonce", "err", err)
 		m.unexpectedRpcErrors.WithLabelValues("safe", "nonce()").Inc()
-		return
+		return 0, false
 	}
 
 	nonce := new(big.Int).SetBytes(nonceBytes).Uint64()
 	m.safeNonce.WithLabelValues(m.safeAddress.String(), m.nickname).Set(float64(nonce))
 	m.log.Info("Safe Nonce", "address", m.safeAddress.String(), "nonce", nonce)
+	return nonce, true
 }
 
-func (m *Monitor) checkPresignedNonce(ctx context.Context) {
-	if m.onePassVault == nil {
+// checkApproveHashes counts the `ApproveHash` events emitted by the safe since the previous iteration.
+// The first iteration starts from the latest block, at most `MaxApproveHashBlockRange` blocks are scanned per iteration.
+func (m *Monitor) checkApproveHashes(ctx context.Context) {
+	if m.safeAddress == nil {
+		return
+	}
+
+	latest, err := m.l1Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		return
+	}
+	if m.nextApproveHashBlock == 0 {
+		m.nextApproveHashBlock = latest
+	}
+	if m.nextApproveHashBlock > latest {
+		return
+	}
+	toBlock := min(latest, m.nextApproveHashBlock+MaxApproveHashBlockRange-1)
+
+	logs, err := m.l1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(m.nextApproveHashBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{*m.safeAddress},
+		Topics:    [][]common.Hash{{ApproveHashTopic}},
+	})
+	if err != nil {
+		m.log.Error("failed to query ApproveHash events", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("safe", "ApproveHash").Inc()
+		return
+	}
+
+	for _, vLog := range logs {
+		if len(vLog.Topics) != 3 {
+			continue
+		}
+		owner := common.BytesToAddress(vLog.Topics[2].Bytes())
+		m.approveHashEvents.WithLabelValues(m.safeAddress.String(), m.nickname, owner.String()).Inc()
+		m.log.Info("ApproveHash", "address", m.safeAddress.String(), "owner", owner, "hash", vLog.Topics[1], "block", vLog.BlockNumber, "tx", vLog.TxHash)
+		alert := m.alert(HashApprovedRule, "P3", vLog.Topics[1].String(), fmt.Sprintf("the owner %s of the safe %s approved the hash %s", owner, m.safeAddress, vLog.Topics[1]))
+		alert.Labels["safe"], alert.Labels["owner"], alert.Labels["txHash"] = m.safeAddress.String(), owner.String(), vLog.TxHash.String()
+		m.Emit(ctx, alert)
+	}
+	m.nextApproveHashBlock = toBlock + 1
+}
+
+// checkPresignedNonce reports the latest presigned nonce stored in 1Pass or in the presigned directory,
+// and the number of presigned pause transactions remaining from the safe nonce when it is known.
+func (m *Monitor) checkPresignedNonce(ctx context.Context, safeNonce uint64, safeNonceKnown bool) {
+	var titles []string
+	var err error
+	switch {
+	case m.onePassVault != nil:
+		titles, err = m.onePassTitles(ctx)
+	case m.presignedDir != nil:
+		titles, err = m.presignedDirTitles()
+	default:
 		m.log.Warn("one pass integration is not configured, skipping...")
 		return
 	}
+	if err != nil {
+		return
+	}
+
+	nonces, err := presignedNonces(titles)
+	if err != nil {
+		m.log.Error("failed to parse nonce from item title", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("presigned", "title").Inc()
+		return
+	}
+
+	latestPresignedNonce := int64(-1)
+	for nonce := range nonces {
+		if int64(nonce) > latestPresignedNonce {
+			latestPresignedNonce = int64(nonce)
+		}
+	}
+
+	if safeNonceKnown {
+		remaining := remainingPresignedNonces(nonces, safeNonce)
+		m.remainingPresignedPause.WithLabelValues(m.safeAddress.String(), m.nickname).Set(float64(remaining))
+		m.log.Info("Remaining Presigned Nonces", "safe_nonce", safeNonce, "remaining", remaining)
+		// the runbook pausing the chain breaks without a presigned pause transaction valid at the nonce of the safe.
+		alert := m.alert(NoPresignedPauseRule, "P1", m.safeAddress.String(), fmt.Sprintf("no presigned pause transaction is valid at the nonce %d of the safe of %s", safeNonce, m.nickname))
+		alert.Labels["safeNonce"] = fmt.Sprint(safeNonce)
+		m.Update(ctx, alert, remaining == 0)
+	}
+
+	m.latestPresignedPauseNonce.WithLabelValues(m.safeAddress.String(), m.nickname).Set(float64(latestPresignedNonce))
+	if latestPresignedNonce == -1 {
+		m.log.Error("no presigned nonce found")
+		return
+	}
 
+	m.log.Info("Latest Presigned Nonce", "nonce", latestPresignedNonce)
+}
+
+// alert returns the alert of the rule about the entity, labeled with the nickname of the chain.
+func (m *Monitor) alert(rule string, priority string, entity string, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   entity,
+		Summary:  summary,
+		Labels:   map[string]string{"nickname": m.nickname},
+	}
+}
+
+// onePassTitles returns the titles of the items of the 1Pass vault.
+func (m *Monitor) onePassTitles(ctx context.Context) ([]string, error) {
 	cmd := exec.CommandContext(ctx, "op", "item", "list", "--format=json", fmt.Sprintf("--vault=%s", *m.onePassVault))
 
 	output, err := cmd.Output()
 	if err != nil {
 		m.log.Error("failed to run op cli")
 		m.unexpectedRpcErrors.WithLabelValues("1pass", "exec").Inc()
-		return
+		return nil, err
 	}
 
 	vaultItems := []struct{ Title string }{}
 	if err := json.Unmarshal(output, &vaultItems); err != nil {
 		m.log.Error("failed to unmarshal op cli stdout", "err", err)
 		m.unexpectedRpcErrors.WithLabelValues("1pass", "stdout").Inc()
-		return
+		return nil, err
 	}
 
-	latestPresignedNonce := int64(-1)
-	for _, item := range vaultItems {
-		if strings.HasPrefix(item.Title, PresignedNonceTitlePrefix) && strings.HasSuffix(item.Title, PresignedNonceTitleSuffix) {
-			nonceStr := item.Title[len(PresignedNonceTitlePrefix) : len(item.Title)-len(PresignedNonceTitleSuffix)]
-			nonce, err := strconv.ParseInt(nonceStr, 10, 64)
+	titles := make([]string, len(vaultItems))
+	for i, item := range vaultItems {
+		titles[i] = item.Title
+	}
+	return titles, nil
+}
+
+// presignedDirTitles returns the names of the files of the presigned directory.
+func (m *Monitor) presignedDirTitles() ([]string, error) {
+	entries, err := os.ReadDir(*m.presignedDir)
+	if err != nil {
+		m.log.Error("failed to read the presigned directory", "dir", *m.presignedDir, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("presigned", "readDir").Inc()
+		return nil, err
+	}
+
+	var titles []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			titles = append(titles, entry.Name())
+		}
+	}
+	return titles, nil
+}
+
+// presignedNonces returns the nonces of the titles following the `ready-<nonce>.json` format, the other titles are ignored.
+func presignedNonces(titles []string) (map[uint64]bool, error) {
+	nonces := make(map[uint64]bool)
+	for _, title := range titles {
+		if strings.HasPrefix(title, PresignedNonceTitlePrefix) && strings.HasSuffix(title, PresignedNonceTitleSuffix) {
+			nonceStr := title[len(PresignedNonceTitlePrefix) : len(title)-len(PresignedNonceTitleSuffix)]
+			nonce, err := strconv.ParseUint(nonceStr, 10, 64)
 			if err != nil {
-				m.log.Error("failed to parse nonce from item title", "title", item.Title)
-				m.unexpectedRpcErrors.WithLabelValues("1pass", "title").Inc()
-				return
-			}
-			if nonce > latestPresignedNonce {
-				latestPresignedNonce = nonce
+				return nil, fmt.Errorf("invalid nonce in title %s: %w", title, err)
 			}
+			nonces[nonce] = true
 		}
 	}
+	return nonces, nil
+}
 
-	m.latestPresignedPauseNonce.WithLabelValues(m.safeAddress.String(), m.nickname).Set(float64(latestPresignedNonce))
-	if latestPresignedNonce == -1 {
-		m.log.Error("no presigned nonce found")
-		return
+// remainingPresignedNonces returns the number of consecutive presigned nonces starting from the safe nonce.
+// A presigned transaction can only be executed at its nonce, so the runbook breaks at the first missing one.
+func remainingPresignedNonces(nonces map[uint64]bool, safeNonce uint64) uint64 {
+	remaining := uint64(0)
+	for nonces[safeNonce+remaining] {
+		remaining++
 	}
-
-	m.log.Info("Latest Presigned Nonce", "nonce", latestPresignedNonce)
+	return remaining
 }
 
 func (m *Monitor) Close(_ context.Context) error {
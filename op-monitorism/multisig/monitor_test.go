@@ -0,0 +1,159 @@
+package multisig
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	portal = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+	safe   = common.HexToAddress("0x9BA6e03D8B90dE867373Db8cF1A58d2F7F006b3A")
+	signer = common.HexToAddress("0x42d27eEA1AD6e22Af6284F609847CB3Cd56B9c64")
+)
+
+func TestPresignedNonces(t *testing.T) {
+	nonces, err := presignedNonces([]string{"ready-3.json", "ready-5.json", "ready-4.json", "notes.txt", "draft-6.json"})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(nonces) != 3 || !nonces[3] || !nonces[4] || !nonces[5] {
+		t.Errorf("expected the nonces 3, 4 and 5 but got %v", nonces)
+	}
+
+	if _, err := presignedNonces([]string{"ready-abc.json"}); err == nil {
+		t.Errorf("expected an error for an invalid nonce")
+	}
+}
+
+func TestRemainingPresignedNonces(t *testing.T) {
+	nonces := map[uint64]bool{3: true, 4: true, 5: true, 7: true}
+	tests := []struct {
+		name      string
+		safeNonce uint64
+		expected  uint64
+	}{
+		{name: "Consecutive nonces from the safe nonce", safeNonce: 3, expected: 3},
+		{name: "Gap after the safe nonce", safeNonce: 5, expected: 1},
+		{name: "Safe nonce not presigned", safeNonce: 6, expected: 0},
+		{name: "Safe nonce beyond the presigned nonces", safeNonce: 8, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := remainingPresignedNonces(nonces, test.safeNonce)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %d but got %d", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+// safeNode serves the OptimismPortal and the nonce of the safe.
+type safeNode struct {
+	*fake.Node
+
+	portal *fake.Contract
+	nonce  int64
+}
+
+func newSafeNode(t *testing.T) *safeNode {
+	portalABI, err := bindings.OptimismPortalMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	n := &safeNode{Node: fake.NewNode(t), nonce: 3}
+	n.AddBlock(&types.Header{})
+	n.portal = n.Contract(portal, portalABI).Returns("paused", false)
+	n.HandleCall(safe, func(_ []byte) ([]byte, error) {
+		return common.BigToHash(big.NewInt(n.nonce)).Bytes(), nil
+	})
+	return n
+}
+
+// newTestMonitor returns the monitor of the portal and of the safe of the node, the presigned pause transactions of the
+// nonces 3 and 4 being in a presigned directory, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *safeNode) (*Monitor, *fake.Notifier) {
+	dir := t.TempDir()
+	for _, name := range []string{"ready-3.json", "ready-4.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatalf("error: %v", err)
+		}
+	}
+	cfg := CLIConfig{L1NodeURL: node.URL, Nickname: "mainnet", OptimismPortalAddress: portal, SafeAddress: &safe, PresignedDir: &dir}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newSafeNode(t)
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a paused portal fires once, resolved once unpaused.
+	node.portal.Returns("paused", true)
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(PortalPausedRule); len(alerts) != 1 || alerts[0].Entity != portal.String() || alerts[0].Priority != "P1" || alerts[0].Labels["nickname"] != "mainnet" {
+		t.Fatalf("expected the paused alert once but got %v", alerts)
+	}
+	node.portal.Returns("paused", false)
+	m.Run(ctx)
+	if firing := notifier.Firing(PortalPausedRule); len(firing) != 0 {
+		t.Fatalf("expected the paused alert resolved but got %v", firing)
+	}
+
+	// a safe nonce past the presigned nonces fires once, resolved once presigned again.
+	node.nonce = 5
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(NoPresignedPauseRule); len(alerts) != 1 || alerts[0].Entity != safe.String() || alerts[0].Labels["safeNonce"] != "5" {
+		t.Fatalf("expected the no presigned pause alert once but got %v", alerts)
+	}
+	if err := os.WriteFile(filepath.Join(*m.presignedDir, "ready-5.json"), []byte("{}"), 0644); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	m.Run(ctx)
+	if firing := notifier.Firing(NoPresignedPauseRule); len(firing) != 0 {
+		t.Fatalf("expected the no presigned pause alert resolved but got %v", firing)
+	}
+
+	// an approved hash is emitted once.
+	approved := common.HexToHash("0x01")
+	header := node.AddBlock(&types.Header{})
+	node.AddLogs(types.Log{
+		Address:     safe,
+		Topics:      []common.Hash{ApproveHashTopic, approved, common.BytesToHash(signer.Bytes())},
+		BlockNumber: header.Number.Uint64(),
+		BlockHash:   header.Hash(),
+		TxHash:      common.HexToHash("0x02"),
+	})
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(HashApprovedRule); len(alerts) != 1 || alerts[0].Entity != approved.String() || alerts[0].Labels["owner"] != signer.String() {
+		t.Fatalf("expected the approved hash alert once but got %v", alerts)
+	}
+}
@@ -0,0 +1,108 @@
+package nft_bridge
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+	L2NodeURLFlagName = "l2.node.url"
+
+	L1ERC721BridgeAddressFlagName = "l1erc721bridge.address"
+	L2ERC721BridgeAddressFlagName = "l2erc721bridge.address"
+	L1StartBlockHeightFlagName    = "l1.start.block.height"
+	L2StartBlockHeightFlagName    = "l2.start.block.height"
+	EventBlockRangeFlagName       = "event.block.range"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+	L2NodeURL string
+
+	L1ERC721BridgeAddress common.Address
+	L2ERC721BridgeAddress common.Address
+
+	L1StartBlockHeight uint64
+	L2StartBlockHeight uint64
+	EventBlockRange    uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:          ctx.String(L1NodeURLFlagName),
+		L2NodeURL:          ctx.String(L2NodeURLFlagName),
+		L1StartBlockHeight: ctx.Uint64(L1StartBlockHeightFlagName),
+		L2StartBlockHeight: ctx.Uint64(L2StartBlockHeightFlagName),
+		EventBlockRange:    ctx.Uint64(EventBlockRangeFlagName),
+	}
+
+	l1BridgeAddress := ctx.String(L1ERC721BridgeAddressFlagName)
+	if !common.IsHexAddress(l1BridgeAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", L1ERC721BridgeAddressFlagName)
+	}
+	cfg.L1ERC721BridgeAddress = common.HexToAddress(l1BridgeAddress)
+
+	l2BridgeAddress := ctx.String(L2ERC721BridgeAddressFlagName)
+	if !common.IsHexAddress(l2BridgeAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", L2ERC721BridgeAddressFlagName)
+	}
+	cfg.L2ERC721BridgeAddress = common.HexToAddress(l2BridgeAddress)
+
+	if cfg.EventBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", EventBlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    L2NodeURLFlagName,
+			Usage:   "Node URL of L2 peer",
+			Value:   "127.0.0.1:9545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     L1ERC721BridgeAddressFlagName,
+			Usage:    "Address of the L1ERC721Bridge contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "L1_ERC721_BRIDGE"),
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:    L2ERC721BridgeAddressFlagName,
+			Usage:   "Address of the L2ERC721Bridge contract",
+			Value:   predeploys.L2ERC721Bridge,
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_ERC721_BRIDGE"),
+		},
+		&cli.Uint64Flag{
+			Name:    L1StartBlockHeightFlagName,
+			Usage:   "Starting L1 height to scan for the deposits, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    L2StartBlockHeightFlagName,
+			Usage:   "Starting L2 height to scan for the mints, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    EventBlockRangeFlagName,
+			Usage:   "Max block range when scanning for the bridge events",
+			Value:   1000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EVENT_BLOCK_RANGE"),
+		},
+	}
+}
@@ -0,0 +1,308 @@
+package nft_bridge
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "nft_bridge"
+	MetricsNamespace = "nft_bridge_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the L2 token and the token id.
+	UnbackedRule   = "nft live on l2 without l1 escrow"
+	DoubleLiveRule = "nft live on both layers"
+
+	// ERC721ABI is the subset of the ERC-721 used by the monitor.
+	ERC721ABI = `[{"inputs":[{"name":"tokenId","type":"uint256"}],"name":"ownerOf","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}]`
+)
+
+var (
+	erc721ABI = util.MustParseJSONABI(ERC721ABI)
+)
+
+// layer is the scanning state of the bridge events of a layer.
+type layer struct {
+	name       string
+	client     *ethclient.Client
+	bridge     common.Address
+	event      string
+	topic      common.Hash
+	nextHeight uint64
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1 *layer
+	l2 *layer
+
+	l1Bridge        *bindings.L1ERC721Bridge
+	l2Bridge        *bindings.L2ERC721BridgeFilterer
+	eventBlockRange uint64
+
+	tokens map[tokenKey]*bridgedToken
+
+	// metrics
+	highestBlockNumber  *prometheus.GaugeVec
+	bridgeEvents        *prometheus.CounterVec
+	trackedTokens       prometheus.Gauge
+	unbackedTokens      prometheus.Gauge
+	doubleLiveTokens    prometheus.Gauge
+	violations          *prometheus.CounterVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating nft bridge monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	l2Client, err := ethclient.Dial(cfg.L2NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l2: %w", err)
+	}
+
+	l1Bridge, err := bindings.NewL1ERC721Bridge(cfg.L1ERC721BridgeAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L1ERC721Bridge: %w", err)
+	}
+	l2Bridge, err := bindings.NewL2ERC721BridgeFilterer(cfg.L2ERC721BridgeAddress, l2Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L2ERC721Bridge: %w", err)
+	}
+	bridgeABI, err := bindings.L1ERC721BridgeMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the L1ERC721Bridge ABI: %w", err)
+	}
+
+	// the deposits are initiated on L1, and minted when finalized on L2.
+	l1 := &layer{name: "l1", client: l1Client, bridge: cfg.L1ERC721BridgeAddress, event: "ERC721BridgeInitiated", nextHeight: cfg.L1StartBlockHeight}
+	l2 := &layer{name: "l2", client: l2Client, bridge: cfg.L2ERC721BridgeAddress, event: "ERC721BridgeFinalized", nextHeight: cfg.L2StartBlockHeight}
+	for _, l := range []*layer{l1, l2} {
+		l.topic = bridgeABI.Events[l.event].ID
+		if l.nextHeight == 0 {
+			if l.nextHeight, err = l.client.BlockNumber(ctx); err != nil {
+				return nil, fmt.Errorf("failed to query latest %s block number: %w", l.name, err)
+			}
+		}
+		log.Info("configured bridge", "layer", l.name, "address", l.bridge, "event", l.event, "start_block", l.nextHeight)
+	}
+
+	return &Monitor{
+		log: log,
+
+		l1: l1,
+		l2: l2,
+
+		l1Bridge:        l1Bridge,
+		l2Bridge:        l2Bridge,
+		eventBlockRange: cfg.EventBlockRange,
+
+		tokens: make(map[tokenKey]*bridgedToken),
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed heights by layer (checked and known)",
+		}, []string{"layer", "type"}),
+		bridgeEvents: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "bridgeEvents",
+			Help:      "number of bridge events, the deposits on L1 and the mints on L2",
+		}, []string{"layer", "event"}),
+		trackedTokens: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "trackedTokens",
+			Help:      "number of bridged NFTs tracked",
+		}),
+		unbackedTokens: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unbackedTokens",
+			Help:      "number of NFTs live on L2 without the L1 token escrowed by the L1 bridge",
+		}),
+		doubleLiveTokens: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "doubleLiveTokens",
+			Help:      "number of NFTs live on both layers",
+		}),
+		violations: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "violations",
+			Help:      "number of inconsistent NFTs found by kind (unbacked, doubleLive)",
+		}, []string{"kind", "l1Token", "l2Token"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	for _, l := range []*layer{m.l1, m.l2} {
+		latestHeight, err := l.client.BlockNumber(ctx)
+		if err != nil {
+			m.log.Error("failed to query latest block number", "layer", l.name, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues(l.name, "blockNumber").Inc()
+			return
+		}
+		m.highestBlockNumber.WithLabelValues(l.name, "known").Set(float64(latestHeight))
+
+		if l.nextHeight <= latestHeight {
+			toBlock := min(latestHeight, l.nextHeight+m.eventBlockRange-1)
+			if err := m.processEvents(ctx, l, l.nextHeight, toBlock); err != nil {
+				m.log.Error("failed to process the bridge events", "layer", l.name, "from", l.nextHeight, "to", toBlock, "err", err)
+				m.unexpectedRpcErrors.WithLabelValues(l.name, l.event).Inc()
+				return
+			}
+			m.highestBlockNumber.WithLabelValues(l.name, "checked").Set(float64(toBlock))
+			l.nextHeight = toBlock + 1
+		}
+	}
+
+	unbacked, doubleLive := 0, 0
+	for key, token := range m.tokens {
+		state, err := m.readState(ctx, token)
+		if err != nil {
+			m.log.Error("failed to read the state of the nft", "l1_token", token.l1Token, "l2_token", token.l2Token, "token_id", token.tokenID, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("token", "readState").Inc()
+			continue
+		}
+
+		check := state.check(m.l1.bridge)
+		if check.unbacked {
+			unbacked++
+			m.log.Error("nft live on l2 without l1 escrow!!!", "l1_token", token.l1Token, "l2_token", token.l2Token, "token_id", token.tokenID, "escrowed", state.escrowed, "l1_owner", state.l1Owner)
+			m.violations.WithLabelValues("unbacked", token.l1Token.String(), token.l2Token.String()).Inc()
+		}
+		if check.doubleLive {
+			doubleLive++
+			m.log.Error("nft live on both layers!!!", "l1_token", token.l1Token, "l2_token", token.l2Token, "token_id", token.tokenID, "l1_owner", state.l1Owner)
+			m.violations.WithLabelValues("doubleLive", token.l1Token.String(), token.l2Token.String()).Inc()
+		}
+		m.Update(ctx, alert(UnbackedRule, token, fmt.Sprintf("nft %s of %s live on l2 without its l1 token %s escrowed by the bridge", token.tokenID, token.l2Token, token.l1Token)), check.unbacked)
+		m.Update(ctx, alert(DoubleLiveRule, token, fmt.Sprintf("nft %s of %s live on l2 and on l1, owned by %s", token.tokenID, token.l2Token, state.l1Owner)), check.doubleLive)
+		if check.settled {
+			m.log.Info("nft settled on l1", "l1_token", token.l1Token, "l2_token", token.l2Token, "token_id", token.tokenID)
+			delete(m.tokens, key)
+		}
+	}
+
+	m.trackedTokens.Set(float64(len(m.tokens)))
+	m.unbackedTokens.Set(float64(unbacked))
+	m.doubleLiveTokens.Set(float64(doubleLive))
+}
+
+// alert returns the P0 alert of the rule about the bridged NFT.
+func alert(rule string, token *bridgedToken, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: "P0",
+		Entity:   fmt.Sprintf("%s/%s", token.l2Token, token.tokenID),
+		Summary:  summary,
+		Labels:   map[string]string{"l1_token": token.l1Token.String()},
+	}
+}
+
+// processEvents tracks the NFTs deposited on L1 or minted on L2 between the two blocks (inclusive).
+func (m *Monitor) processEvents(ctx context.Context, l *layer, fromBlock uint64, toBlock uint64) error {
+	logs, err := l.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{l.bridge},
+		Topics:    [][]common.Hash{{l.topic}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the %s events: %w", l.event, err)
+	}
+
+	var tokens []*bridgedToken
+	for _, vLog := range logs {
+		// the local token is the token of the layer, the remote token the one of the other layer.
+		if l == m.l1 {
+			deposit, err := m.l1Bridge.ParseERC721BridgeInitiated(vLog)
+			if err != nil {
+				return fmt.Errorf("failed to parse the ERC721BridgeInitiated event: %w", err)
+			}
+			tokens = append(tokens, &bridgedToken{l1Token: deposit.LocalToken, l2Token: deposit.RemoteToken, tokenID: deposit.TokenId})
+		} else {
+			mint, err := m.l2Bridge.ParseERC721BridgeFinalized(vLog)
+			if err != nil {
+				return fmt.Errorf("failed to parse the ERC721BridgeFinalized event: %w", err)
+			}
+			tokens = append(tokens, &bridgedToken{l1Token: mint.RemoteToken, l2Token: mint.LocalToken, tokenID: mint.TokenId})
+		}
+	}
+
+	// the tokens are tracked once all of the range is read, the range being read again on failure.
+	for _, token := range tokens {
+		m.tokens[token.key()] = token
+		m.bridgeEvents.WithLabelValues(l.name, l.event).Inc()
+	}
+	return nil
+}
+
+// readState reads the state of the NFT on both layers at their latest block.
+func (m *Monitor) readState(ctx context.Context, token *bridgedToken) (tokenState, error) {
+	state := tokenState{}
+
+	var err error
+	if state.l1Owner, state.l1Live, err = ownerOf(ctx, m.l1.client, token.l1Token, token.tokenID); err != nil {
+		return state, fmt.Errorf("failed to query the l1 owner: %w", err)
+	}
+	if _, state.l2Live, err = ownerOf(ctx, m.l2.client, token.l2Token, token.tokenID); err != nil {
+		return state, fmt.Errorf("failed to query the l2 owner: %w", err)
+	}
+	if state.escrowed, err = m.l1Bridge.Deposits(&bind.CallOpts{Context: ctx}, token.l1Token, token.l2Token, token.tokenID); err != nil {
+		return state, fmt.Errorf("failed to query the l1 deposit: %w", err)
+	}
+	return state, nil
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1.client.Close()
+	m.l2.client.Close()
+	return nil
+}
+
+// ownerOf returns the owner of the NFT, `false` when it doesn't exist, `ownerOf` reverting or returning the zero address.
+func ownerOf(ctx context.Context, client *ethclient.Client, token common.Address, tokenID *big.Int) (common.Address, bool, error) {
+	data, err := erc721ABI.Pack("ownerOf", tokenID)
+	if err != nil {
+		return common.Address{}, false, err
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		if util.IsRevert(err) {
+			return common.Address{}, false, nil
+		}
+		return common.Address{}, false, err
+	}
+	unpacked, err := erc721ABI.Unpack("ownerOf", out)
+	if err != nil || len(unpacked) != 1 {
+		return common.Address{}, false, fmt.Errorf("failed to unpack ownerOf: %w", err)
+	}
+	owner := unpacked[0].(common.Address)
+	return owner, owner != (common.Address{}), nil
+}
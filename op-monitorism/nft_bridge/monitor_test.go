@@ -0,0 +1,120 @@
+package nft_bridge
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var (
+	l1Bridge = common.HexToAddress("0x5a7749f83b81B301cAb5f48EB8516B986DAef23D")
+	l2Bridge = common.HexToAddress("0x4200000000000000000000000000000000000014")
+	l1Token  = common.HexToAddress("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D")
+	l2Token  = common.HexToAddress("0x0a")
+	owner    = common.HexToAddress("0x0b")
+)
+
+// newTestMonitor returns the monitor of the bridges from the block 1 of both layers, the L1 bridge and token, and the
+// notifier of its alerts. The NFT 7 is minted on L2 to the owner in the block 1, and escrowed by the L1 bridge.
+func newTestMonitor(t *testing.T) (*Monitor, *fake.Contract, *fake.Contract, *fake.Notifier) {
+	l1, l2 := fake.NewNode(t), fake.NewNode(t)
+	l1.AddBlock(&types.Header{})
+	l2.AddBlock(&types.Header{})
+	bridge := l1.Contract(l1Bridge, util.MustParseABI(bindings.L1ERC721BridgeMetaData)).Returns("deposits", true)
+	token := l1.Contract(l1Token, erc721ABI).Returns("ownerOf", l1Bridge)
+	l2.Contract(l2Token, erc721ABI).Returns("ownerOf", owner)
+
+	event := util.MustParseABI(bindings.L2ERC721BridgeMetaData).Events["ERC721BridgeFinalized"]
+	data, err := event.Inputs.NonIndexed().Pack(owner, big.NewInt(7), []byte{})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	header := l2.AddBlock(&types.Header{})
+	l2.AddLogs(types.Log{
+		Address:     l2Bridge,
+		Topics:      []common.Hash{event.ID, common.BytesToHash(l2Token.Bytes()), common.BytesToHash(l1Token.Bytes()), common.BytesToHash(owner.Bytes())},
+		Data:        data,
+		BlockNumber: header.Number.Uint64(),
+	})
+
+	cfg := CLIConfig{L1NodeURL: l1.URL, L2NodeURL: l2.URL, L1ERC721BridgeAddress: l1Bridge, L2ERC721BridgeAddress: l2Bridge, L1StartBlockHeight: 1, L2StartBlockHeight: 1, EventBlockRange: 100}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, bridge, token, notifier
+}
+
+func TestRun(t *testing.T) {
+	m, bridge, token, _ := newTestMonitor(t)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if events, tracked, checked := testutil.ToFloat64(m.bridgeEvents.WithLabelValues("l2", "ERC721BridgeFinalized")), testutil.ToFloat64(m.trackedTokens), testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("l2", "checked")); events != 1 || tracked != 1 || checked != 1 {
+		t.Errorf("expected the nft of the l2 block 1 tracked but got %v, %v and %v", events, tracked, checked)
+	}
+	if unbacked, doubleLive := testutil.ToFloat64(m.unbackedTokens), testutil.ToFloat64(m.doubleLiveTokens); unbacked != 0 || doubleLive != 0 {
+		t.Errorf("expected the nft backed but got %v and %v", unbacked, doubleLive)
+	}
+
+	// the nft no longer escrowed is counted as unbacked on every run.
+	bridge.Returns("deposits", false)
+	m.Run(ctx)
+	m.Run(ctx)
+	if unbacked, violations := testutil.ToFloat64(m.unbackedTokens), testutil.ToFloat64(m.violations.WithLabelValues("unbacked", l1Token.String(), l2Token.String())); unbacked != 1 || violations != 2 {
+		t.Errorf("expected 1 unbacked nft over 2 runs but got %v and %v", unbacked, violations)
+	}
+
+	// the l1 token leaving the bridge while live on l2 is double live.
+	bridge.Returns("deposits", true)
+	token.Returns("ownerOf", owner)
+	m.Run(ctx)
+	if doubleLive, violations := testutil.ToFloat64(m.doubleLiveTokens), testutil.ToFloat64(m.violations.WithLabelValues("doubleLive", l1Token.String(), l2Token.String())); doubleLive != 1 || violations != 1 {
+		t.Errorf("expected 1 double live nft but got %v and %v", doubleLive, violations)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	m, bridge, token, notifier := newTestMonitor(t)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 || len(m.tokens) != 1 {
+		t.Fatalf("expected the nft tracked without alert but got %v", alerts)
+	}
+
+	// the nft no longer escrowed fires the unbacked alert once, resolved once escrowed again.
+	bridge.Returns("deposits", false)
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(UnbackedRule); len(alerts) != 1 || alerts[0].Entity != l2Token.String()+"/7" || alerts[0].Priority != "P0" || alerts[0].Labels["l1_token"] != l1Token.String() {
+		t.Fatalf("expected the unbacked alert once but got %v", alerts)
+	}
+	bridge.Returns("deposits", true)
+	m.Run(ctx)
+	if firing := notifier.Firing(UnbackedRule); len(firing) != 0 {
+		t.Fatalf("expected the unbacked alert resolved but got %v", firing)
+	}
+
+	// the l1 token leaving the bridge while live on l2 fires both alerts.
+	token.Returns("ownerOf", owner)
+	m.Run(ctx)
+	if len(notifier.Firing(UnbackedRule)) != 1 || len(notifier.Firing(DoubleLiveRule)) != 1 {
+		t.Fatalf("expected the unbacked and double live alerts but got %v", notifier.Alerts(""))
+	}
+}
@@ -0,0 +1,58 @@
+package nft_bridge
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// tokenKey identifies a bridged NFT, the L1 token and its L2 representation along with the token id.
+type tokenKey struct {
+	l1Token common.Address
+	l2Token common.Address
+	tokenID string
+}
+
+type bridgedToken struct {
+	l1Token common.Address
+	l2Token common.Address
+	tokenID *big.Int
+}
+
+func (t *bridgedToken) key() tokenKey {
+	return tokenKey{l1Token: t.l1Token, l2Token: t.l2Token, tokenID: t.tokenID.String()}
+}
+
+// tokenState is the state of a bridged NFT on both layers.
+type tokenState struct {
+	// l1Owner is the owner of the L1 token, `l1Live` being false when the token doesn't exist on L1.
+	l1Live  bool
+	l1Owner common.Address
+
+	// l2Live is true when the L2 representation exists, i.e. minted by the L2 bridge and not burned by a withdrawal.
+	l2Live bool
+
+	// escrowed is the `deposits` flag of the L1 bridge for the token.
+	escrowed bool
+}
+
+// tokenCheck is the consistency of a bridged NFT between the layers.
+type tokenCheck struct {
+	// unbacked is true when the L2 representation exists without the L1 token being escrowed by the L1 bridge.
+	unbacked bool
+	// doubleLive is true when the token is live on both layers, owned by an account other than the L1 bridge on L1.
+	doubleLive bool
+	// settled is true when the token is back on L1 and no longer bridged, so it doesn't need to be tracked anymore.
+	settled bool
+}
+
+// check returns the consistency of the token. A deposit in flight is escrowed on L1 without being minted on L2 yet, and a withdrawal in
+// flight is burned on L2 while still escrowed on L1, both being consistent.
+func (s tokenState) check(l1Bridge common.Address) tokenCheck {
+	heldByBridge := s.l1Live && s.l1Owner == l1Bridge
+	return tokenCheck{
+		unbacked:   s.l2Live && !(s.escrowed && heldByBridge),
+		doubleLive: s.l2Live && s.l1Live && !heldByBridge,
+		settled:    !s.l2Live && !s.escrowed && !heldByBridge,
+	}
+}
@@ -0,0 +1,34 @@
+package nft_bridge
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCheck(t *testing.T) {
+	bridge := common.HexToAddress("0x01")
+	user := common.HexToAddress("0x02")
+
+	tests := []struct {
+		name     string
+		state    tokenState
+		expected tokenCheck
+	}{
+		{name: "Bridged", state: tokenState{l1Live: true, l1Owner: bridge, l2Live: true, escrowed: true}, expected: tokenCheck{}},
+		{name: "Deposit or withdrawal in flight", state: tokenState{l1Live: true, l1Owner: bridge, escrowed: true}, expected: tokenCheck{}},
+		{name: "Settled on L1", state: tokenState{l1Live: true, l1Owner: user}, expected: tokenCheck{settled: true}},
+		{name: "Mint without escrow", state: tokenState{l1Live: true, l1Owner: bridge, l2Live: true}, expected: tokenCheck{unbacked: true}},
+		{name: "Live on both layers", state: tokenState{l1Live: true, l1Owner: user, l2Live: true, escrowed: true}, expected: tokenCheck{unbacked: true, doubleLive: true}},
+		{name: "Burned on L1", state: tokenState{l2Live: true, escrowed: true}, expected: tokenCheck{unbacked: true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := test.state.check(bridge)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %+v but got %+v", test.name, test.expected, output)
+			}
+		})
+	}
+}
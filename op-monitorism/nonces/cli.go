@@ -0,0 +1,100 @@
+package nonces
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodeURLFlagName       = "node.url"
+	AccountsFlagName      = "accounts"
+	StuckDurationFlagName = "stuck.duration"
+	TxPoolFlagName        = "txpool"
+)
+
+// Account is an operational hot wallet, e.g. the batcher, the proposer or the challenger.
+type Account struct {
+	Address  common.Address
+	Nickname string
+}
+
+type CLIConfig struct {
+	NodeURL  string
+	Accounts []Account
+
+	// StuckDuration is the duration after which the pending transactions of an account whose nonce doesn't advance are stuck.
+	StuckDuration time.Duration
+
+	// TxPool inspects the transactions of the accounts in the mempool (`txpool_contentFrom`), to report the underpriced ones.
+	TxPool bool
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		NodeURL:       ctx.String(NodeURLFlagName),
+		StuckDuration: ctx.Duration(StuckDurationFlagName),
+		TxPool:        ctx.Bool(TxPoolFlagName),
+	}
+
+	for _, account := range ctx.StringSlice(AccountsFlagName) {
+		split := strings.Split(account, ":")
+		if len(split) != 2 {
+			return cfg, fmt.Errorf("failed to parse `address:nickname`: %s", account)
+		}
+
+		addr, nickname := split[0], split[1]
+		if !common.IsHexAddress(addr) {
+			return cfg, fmt.Errorf("address is not a hex-encoded address: %s", addr)
+		}
+		if len(nickname) == 0 {
+			return cfg, fmt.Errorf("nickname for %s not set", addr)
+		}
+
+		cfg.Accounts = append(cfg.Accounts, Account{Address: common.HexToAddress(addr), Nickname: nickname})
+	}
+	if len(cfg.Accounts) == 0 {
+		return cfg, fmt.Errorf("--%s must have at least one account", AccountsFlagName)
+	}
+
+	if cfg.StuckDuration <= 0 {
+		return cfg, fmt.Errorf("--%s must be positive", StuckDurationFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    NodeURLFlagName,
+			Usage:   "Node URL of a peer, its mempool is inspected",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "NODE_URL"),
+		},
+		&cli.StringSliceFlag{
+			Name:     AccountsFlagName,
+			Usage:    "One or more accounts formatted via `address:nickname`",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "ACCOUNTS"),
+			Required: true,
+		},
+		&cli.DurationFlag{
+			Name:    StuckDurationFlagName,
+			Usage:   "Duration after which the pending transactions of an account whose nonce doesn't advance are stuck",
+			Value:   5 * time.Minute,
+			EnvVars: opservice.PrefixEnvVar(envVar, "STUCK_DURATION"),
+		},
+		&cli.BoolFlag{
+			Name:    TxPoolFlagName,
+			Usage:   "Inspect the transactions of the accounts in the mempool of the node (txpool namespace) to report the underpriced ones",
+			Value:   true,
+			EnvVars: opservice.PrefixEnvVar(envVar, "TXPOOL"),
+		},
+	}
+}
@@ -0,0 +1,213 @@
+package nonces
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "nonces"
+	MetricsNamespace = "nonces_mon"
+
+	// StuckRule is the rule of the alert emitted while the pending transactions of an account are stuck, the entity being the account.
+	StuckRule = "pending transactions stuck"
+)
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	client *ethclient.Client
+
+	accounts      []Account
+	stuckDuration time.Duration
+	txPool        bool
+
+	pendingStates map[int]*pendingState
+
+	// metrics
+	nonce               *prometheus.GaugeVec
+	nonceGap            *prometheus.GaugeVec
+	pendingDuration     *prometheus.GaugeVec
+	stuck               *prometheus.GaugeVec
+	mempoolTransactions *prometheus.GaugeVec
+	underpriced         *prometheus.GaugeVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating nonces monitor...")
+
+	client, err := ethclient.Dial(cfg.NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial node: %w", err)
+	}
+
+	pendingStates := make(map[int]*pendingState, len(cfg.Accounts))
+	for i, account := range cfg.Accounts {
+		log.Info("configured account", "nickname", account.Nickname, "address", account.Address)
+		pendingStates[i] = &pendingState{}
+	}
+
+	return &Monitor{
+		log: log,
+
+		client: client,
+
+		accounts:      cfg.Accounts,
+		stuckDuration: cfg.StuckDuration,
+		txPool:        cfg.TxPool,
+
+		pendingStates: pendingStates,
+
+		nonce: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "nonce",
+			Help:      "nonce of the account (latest and pending)",
+		}, []string{"nickname", "address", "type"}),
+		nonceGap: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "nonceGap",
+			Help:      "number of pending transactions of the account, the gap between the pending and the latest nonces",
+		}, []string{"nickname", "address"}),
+		pendingDuration: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "pendingDuration",
+			Help:      "seconds since the latest nonce of the account with pending transactions didn't advance",
+		}, []string{"nickname", "address"}),
+		stuck: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "stuck",
+			Help:      "1 if the pending transactions of the account are stuck, 0 otherwise",
+		}, []string{"nickname", "address"}),
+		mempoolTransactions: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "mempoolTransactions",
+			Help:      "number of transactions of the account in the mempool by pool (pending, queued)",
+		}, []string{"nickname", "address", "pool"}),
+		underpriced: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "underpriced",
+			Help:      "1 if the next transaction of the account in the mempool doesn't pay the current fees, 0 otherwise",
+		}, []string{"nickname", "address"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	var currentFees fees
+	if m.txPool {
+		var err error
+		if currentFees, err = m.fees(ctx); err != nil {
+			m.log.Error("failed to query the current fees", "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("node", "fees").Inc()
+			return
+		}
+	}
+
+	now := time.Now()
+	for i, account := range m.accounts {
+		address := account.Address.String()
+
+		latest, err := m.client.NonceAt(ctx, account.Address, nil)
+		if err != nil {
+			m.log.Error("failed to query the latest nonce", "nickname", account.Nickname, "address", account.Address, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("node", "NonceAt").Inc()
+			continue
+		}
+		pending, err := m.client.PendingNonceAt(ctx, account.Address)
+		if err != nil {
+			m.log.Error("failed to query the pending nonce", "nickname", account.Nickname, "address", account.Address, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("node", "PendingNonceAt").Inc()
+			continue
+		}
+		gap := uint64(0)
+		if pending > latest {
+			gap = pending - latest
+		}
+		m.nonce.WithLabelValues(account.Nickname, address, "latest").Set(float64(latest))
+		m.nonce.WithLabelValues(account.Nickname, address, "pending").Set(float64(pending))
+		m.nonceGap.WithLabelValues(account.Nickname, address).Set(float64(gap))
+
+		duration := m.pendingStates[i].update(latest, pending, now)
+		stuck := duration >= m.stuckDuration
+		m.pendingDuration.WithLabelValues(account.Nickname, address).Set(duration.Seconds())
+		m.stuck.WithLabelValues(account.Nickname, address).Set(util.BoolToFloat(stuck))
+		m.Update(ctx, alerts.Alert{
+			Monitor:  MonitorName,
+			Rule:     StuckRule,
+			Priority: "P1",
+			Entity:   address,
+			Summary:  fmt.Sprintf("%d pending transactions of %s stuck at the nonce %d for %s", gap, account.Nickname, latest, duration.Truncate(time.Second)),
+			Labels:   map[string]string{"nickname": account.Nickname},
+		}, stuck)
+
+		if !m.txPool {
+			if stuck {
+				m.log.Warn("pending transactions stuck", "nickname", account.Nickname, "address", account.Address, "nonce", latest, "gap", gap, "duration", duration)
+			}
+			continue
+		}
+
+		var content txPoolContent
+		if err := m.client.Client().CallContext(ctx, &content, "txpool_contentFrom", account.Address); err != nil {
+			m.log.Error("failed to query the mempool", "nickname", account.Nickname, "address", account.Address, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("node", "txpool_contentFrom").Inc()
+			continue
+		}
+		m.mempoolTransactions.WithLabelValues(account.Nickname, address, "pending").Set(float64(len(content["pending"])))
+		m.mempoolTransactions.WithLabelValues(account.Nickname, address, "queued").Set(float64(len(content["queued"])))
+
+		// The next transaction blocks the following ones, only its fees matter.
+		next := content.tx("pending", latest)
+		underpriced := next != nil && isUnderpriced(next, currentFees)
+		m.underpriced.WithLabelValues(account.Nickname, address).Set(util.BoolToFloat(underpriced))
+		if stuck {
+			if next == nil {
+				m.log.Warn("pending transactions stuck, the next transaction is not in the mempool", "nickname", account.Nickname, "address", account.Address, "nonce", latest, "gap", gap, "duration", duration, "queued", len(content["queued"]))
+			} else {
+				m.log.Warn("pending transactions stuck", "nickname", account.Nickname, "address", account.Address, "nonce", latest, "gap", gap, "duration", duration, "tx", next.Hash, "underpriced", underpriced)
+			}
+		}
+	}
+}
+
+// fees returns the fees a transaction must pay to be included, from the latest block and the suggested tip.
+func (m *Monitor) fees(ctx context.Context) (fees, error) {
+	header, err := m.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fees{}, fmt.Errorf("failed to query the latest header: %w", err)
+	}
+	tipCap, err := m.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return fees{}, fmt.Errorf("failed to query the suggested tip: %w", err)
+	}
+
+	current := fees{baseFee: header.BaseFee, tipCap: tipCap}
+	if header.ExcessBlobGas != nil {
+		current.blobBaseFee = eip4844.CalcBlobFee(*header.ExcessBlobGas)
+	}
+	return current, nil
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.client.Close()
+	return nil
+}
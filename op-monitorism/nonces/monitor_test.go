@@ -0,0 +1,116 @@
+package nonces
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var batcher = common.HexToAddress("0x6887246668a3b87F54DeB3b94Ba47a6f63F32985")
+
+// nonceNode is a node serving the latest and the pending nonces of the accounts.
+type nonceNode struct {
+	*fake.Node
+
+	latest  uint64
+	pending uint64
+}
+
+func newNonceNode(t *testing.T) *nonceNode {
+	n := &nonceNode{Node: fake.NewNode(t)}
+	n.AddBlock(&types.Header{})
+	n.Handle("eth_getTransactionCount", func(params []json.RawMessage) (interface{}, error) {
+		var tag string
+		if err := fake.Param(params, 1, &tag); err != nil {
+			return nil, err
+		}
+		if tag == "pending" {
+			return hexutil.Uint64(n.pending), nil
+		}
+		return hexutil.Uint64(n.latest), nil
+	})
+	return n
+}
+
+// newTestMonitor returns the monitor of the batcher stuck after a minute, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *nonceNode) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{NodeURL: node.URL, Accounts: []Account{{Address: batcher, Nickname: "batcher"}}, StuckDuration: time.Minute}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := newNonceNode(t)
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+	address := batcher.String()
+
+	// pending transactions are reported as a gap, not stuck before the duration.
+	node.latest, node.pending = 5, 8
+	m.Run(ctx)
+	if latest, pending, gap := testutil.ToFloat64(m.nonce.WithLabelValues("batcher", address, "latest")), testutil.ToFloat64(m.nonce.WithLabelValues("batcher", address, "pending")), testutil.ToFloat64(m.nonceGap.WithLabelValues("batcher", address)); latest != 5 || pending != 8 || gap != 3 {
+		t.Errorf("expected the nonces 5 and 8 with a gap of 3 but got %v, %v and %v", latest, pending, gap)
+	}
+	if stuck := testutil.ToFloat64(m.stuck.WithLabelValues("batcher", address)); stuck != 0 {
+		t.Errorf("expected the batcher not stuck but got %v", stuck)
+	}
+
+	// the nonce not advancing for the duration reports the batcher stuck.
+	m.pendingStates[0].since = time.Now().Add(-time.Hour)
+	m.Run(ctx)
+	if duration, stuck := testutil.ToFloat64(m.pendingDuration.WithLabelValues("batcher", address)), testutil.ToFloat64(m.stuck.WithLabelValues("batcher", address)); duration < 3600 || stuck != 1 {
+		t.Errorf("expected the batcher stuck for an hour but got %v and %v", duration, stuck)
+	}
+
+	// an rpc error is counted.
+	node.Fail("eth_getTransactionCount", errors.New("unavailable"))
+	m.Run(ctx)
+	if errs := testutil.ToFloat64(m.unexpectedRpcErrors.WithLabelValues("node", "NonceAt")); errs != 1 {
+		t.Errorf("expected 1 rpc error but got %v", errs)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newNonceNode(t)
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// pending transactions whose nonce doesn't advance fire once stuck for the duration.
+	node.latest, node.pending = 5, 8
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+	m.pendingStates[0].since = time.Now().Add(-time.Hour)
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(StuckRule); len(alerts) != 1 || alerts[0].Entity != batcher.String() || alerts[0].Priority != "P1" || alerts[0].Labels["nickname"] != "batcher" {
+		t.Fatalf("expected the stuck alert once but got %v", alerts)
+	}
+
+	// the nonce advancing resolves the alert.
+	node.latest = 6
+	m.Run(ctx)
+	if firing := notifier.Firing(StuckRule); len(firing) != 0 {
+		t.Fatalf("expected the stuck alert resolved but got %v", firing)
+	}
+}
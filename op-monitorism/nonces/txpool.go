@@ -0,0 +1,77 @@
+package nonces
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// poolTx is the subset of a transaction of the mempool used by the monitor.
+type poolTx struct {
+	Hash                 common.Hash    `json:"hash"`
+	Nonce                hexutil.Uint64 `json:"nonce"`
+	GasPrice             *hexutil.Big   `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	MaxFeePerBlobGas     *hexutil.Big   `json:"maxFeePerBlobGas"`
+}
+
+// txPoolContent is the result of `txpool_contentFrom`, the transactions of an account by pool (pending, queued) and nonce.
+type txPoolContent map[string]map[string]*poolTx
+
+// tx returns the transaction of the pool with the nonce, nil when not in the pool.
+func (c txPoolContent) tx(pool string, nonce uint64) *poolTx {
+	return c[pool][strconv.FormatUint(nonce, 10)]
+}
+
+// fees are the fees a transaction must pay to be included in the next block.
+type fees struct {
+	baseFee     *big.Int
+	tipCap      *big.Int
+	blobBaseFee *big.Int
+}
+
+// isUnderpriced returns true when the transaction doesn't pay the fees, a legacy transaction pays its gas price as fee cap and tip.
+// The blob fee is only checked for the blob transactions, when the blob base fee is known.
+func isUnderpriced(tx *poolTx, fees fees) bool {
+	feeCap, tip := tx.MaxFeePerGas, tx.MaxPriorityFeePerGas
+	if feeCap == nil {
+		feeCap, tip = tx.GasPrice, tx.GasPrice
+	}
+	if feeCap == nil || tip == nil {
+		return false
+	}
+
+	if fees.baseFee != nil && feeCap.ToInt().Cmp(fees.baseFee) < 0 {
+		return true
+	}
+	if fees.tipCap != nil && tip.ToInt().Cmp(fees.tipCap) < 0 {
+		return true
+	}
+	if tx.MaxFeePerBlobGas != nil && fees.blobBaseFee != nil && tx.MaxFeePerBlobGas.ToInt().Cmp(fees.blobBaseFee) < 0 {
+		return true
+	}
+	return false
+}
+
+// pendingState tracks since when the nonce of an account with pending transactions doesn't advance.
+type pendingState struct {
+	nonce uint64
+	since time.Time
+}
+
+// update returns for how long the latest nonce didn't advance while transactions are pending, 0 without pending transactions.
+func (s *pendingState) update(latest uint64, pending uint64, now time.Time) time.Duration {
+	if pending <= latest {
+		s.since = time.Time{}
+		return 0
+	}
+	if s.since.IsZero() || s.nonce != latest {
+		s.nonce, s.since = latest, now
+		return 0
+	}
+	return now.Sub(s.since)
+}
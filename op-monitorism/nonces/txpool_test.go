@@ -0,0 +1,81 @@
+package nonces
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestIsUnderpriced(t *testing.T) {
+	gwei := func(n int64) *hexutil.Big { return (*hexutil.Big)(big.NewInt(n * 1e9)) }
+	current := fees{baseFee: big.NewInt(10e9), tipCap: big.NewInt(1e9), blobBaseFee: big.NewInt(5e9)}
+
+	tests := []struct {
+		name     string
+		tx       poolTx
+		expected bool
+	}{
+		{name: "dynamic fee", tx: poolTx{MaxFeePerGas: gwei(20), MaxPriorityFeePerGas: gwei(2)}, expected: false},
+		{name: "fee cap below the base fee", tx: poolTx{MaxFeePerGas: gwei(9), MaxPriorityFeePerGas: gwei(2)}, expected: true},
+		{name: "tip below the suggested tip", tx: poolTx{MaxFeePerGas: gwei(20), MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(1))}, expected: true},
+		{name: "legacy", tx: poolTx{GasPrice: gwei(11)}, expected: false},
+		{name: "legacy below the base fee", tx: poolTx{GasPrice: gwei(5)}, expected: true},
+		{name: "blob", tx: poolTx{MaxFeePerGas: gwei(20), MaxPriorityFeePerGas: gwei(2), MaxFeePerBlobGas: gwei(5)}, expected: false},
+		{name: "blob fee below the blob base fee", tx: poolTx{MaxFeePerGas: gwei(20), MaxPriorityFeePerGas: gwei(2), MaxFeePerBlobGas: gwei(4)}, expected: true},
+		{name: "no fee", tx: poolTx{}, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isUnderpriced(&test.tx, current); got != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestTxPoolContent(t *testing.T) {
+	raw := `{"pending":{"7":{"hash":"0x0000000000000000000000000000000000000000000000000000000000000001","nonce":"0x7","maxFeePerGas":"0x3b9aca00","maxPriorityFeePerGas":"0x1"}},"queued":{}}`
+
+	var content txPoolContent
+	if err := json.Unmarshal([]byte(raw), &content); err != nil {
+		t.Fatalf("failed to parse the mempool content: %v", err)
+	}
+	tx := content.tx("pending", 7)
+	if tx == nil || uint64(tx.Nonce) != 7 || tx.MaxFeePerGas.ToInt().Int64() != 1e9 {
+		t.Errorf("Failed pending: expected the transaction with the nonce 7 but got %v", tx)
+	}
+	if content.tx("pending", 8) != nil || content.tx("queued", 7) != nil {
+		t.Errorf("Failed missing: expected no transaction")
+	}
+}
+
+func TestPendingState(t *testing.T) {
+	start := time.Unix(1000, 0)
+	state := &pendingState{}
+
+	steps := []struct {
+		name     string
+		latest   uint64
+		pending  uint64
+		now      time.Time
+		expected time.Duration
+	}{
+		{name: "no pending transaction", latest: 5, pending: 5, now: start, expected: 0},
+		{name: "first pending transaction", latest: 5, pending: 6, now: start, expected: 0},
+		{name: "nonce not advancing", latest: 5, pending: 7, now: start.Add(time.Minute), expected: time.Minute},
+		{name: "nonce advancing", latest: 6, pending: 7, now: start.Add(2 * time.Minute), expected: 0},
+		{name: "nonce not advancing again", latest: 6, pending: 7, now: start.Add(5 * time.Minute), expected: 3 * time.Minute},
+		{name: "all included", latest: 7, pending: 7, now: start.Add(6 * time.Minute), expected: 0},
+		{name: "new pending transaction", latest: 7, pending: 8, now: start.Add(7 * time.Minute), expected: 0},
+	}
+
+	for _, step := range steps {
+		if got := state.update(step.latest, step.pending, step.now); got != step.expected {
+			t.Errorf("Failed %s: expected %s but got %s", step.name, step.expected, got)
+		}
+	}
+}
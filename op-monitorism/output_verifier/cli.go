@@ -0,0 +1,118 @@
+package output_verifier
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+	L2NodeURLFlagName = "l2.node.url"
+
+	L2OutputOracleAddressFlagName     = "l2outputoracle.address"
+	DisputeGameFactoryAddressFlagName = "disputegamefactory.address"
+	GameTypeFlagName                  = "game.type"
+	StartBlockHeightFlagName          = "start.block.height"
+	EventBlockRangeFlagName           = "event.block.range"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+
+	// L2NodeURL is the trusted L2 archive node the output roots are recomputed from.
+	L2NodeURL string
+
+	// Exactly one of the L2OutputOracle or the DisputeGameFactory is set.
+	L2OutputOracleAddress     *common.Address
+	DisputeGameFactoryAddress *common.Address
+	GameType                  uint32
+
+	StartBlockHeight uint64
+	EventBlockRange  uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:        ctx.String(L1NodeURLFlagName),
+		L2NodeURL:        ctx.String(L2NodeURLFlagName),
+		GameType:         uint32(ctx.Uint(GameTypeFlagName)),
+		StartBlockHeight: ctx.Uint64(StartBlockHeightFlagName),
+		EventBlockRange:  ctx.Uint64(EventBlockRangeFlagName),
+	}
+
+	l2ooAddress := ctx.String(L2OutputOracleAddressFlagName)
+	if len(l2ooAddress) > 0 {
+		if !common.IsHexAddress(l2ooAddress) {
+			return cfg, fmt.Errorf("--%s is not a hex-encoded address", L2OutputOracleAddressFlagName)
+		}
+		addr := common.HexToAddress(l2ooAddress)
+		cfg.L2OutputOracleAddress = &addr
+	}
+
+	factoryAddress := ctx.String(DisputeGameFactoryAddressFlagName)
+	if len(factoryAddress) > 0 {
+		if !common.IsHexAddress(factoryAddress) {
+			return cfg, fmt.Errorf("--%s is not a hex-encoded address", DisputeGameFactoryAddressFlagName)
+		}
+		addr := common.HexToAddress(factoryAddress)
+		cfg.DisputeGameFactoryAddress = &addr
+	}
+
+	if (cfg.L2OutputOracleAddress == nil) == (cfg.DisputeGameFactoryAddress == nil) {
+		return cfg, fmt.Errorf("exactly one of --%s or --%s must be set", L2OutputOracleAddressFlagName, DisputeGameFactoryAddressFlagName)
+	}
+	if cfg.EventBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", EventBlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    L2NodeURLFlagName,
+			Usage:   "Node URL of a trusted L2 archive node the output roots are recomputed from",
+			Value:   "127.0.0.1:9545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    L2OutputOracleAddressFlagName,
+			Usage:   "Address of the L2OutputOracle contract, for the chains proposing outputs to the L2OutputOracle",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_OUTPUT_ORACLE"),
+		},
+		&cli.StringFlag{
+			Name:    DisputeGameFactoryAddressFlagName,
+			Usage:   "Address of the DisputeGameFactory contract, for the chains proposing outputs through dispute games",
+			EnvVars: opservice.PrefixEnvVar(envVar, "DISPUTE_GAME_FACTORY"),
+		},
+		&cli.UintFlag{
+			Name:    GameTypeFlagName,
+			Usage:   "Type of the dispute games whose root claims are verified",
+			Value:   0,
+			EnvVars: opservice.PrefixEnvVar(envVar, "GAME_TYPE"),
+		},
+		&cli.Uint64Flag{
+			Name:    StartBlockHeightFlagName,
+			Usage:   "Starting L1 height to scan for proposals, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    EventBlockRangeFlagName,
+			Usage:   "Max block range when scanning for the proposal events",
+			Value:   1000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EVENT_BLOCK_RANGE"),
+		},
+	}
+}
@@ -0,0 +1,352 @@
+package output_verifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "output_verifier"
+	MetricsNamespace = "output_verifier_mon"
+
+	// MismatchRule is the rule of the alert emitted while the last verified proposal doesn't match the recomputed output root,
+	// the entity being the L2OutputOracle or the DisputeGameFactory.
+	MismatchRule = "output root mismatch"
+)
+
+// proposal is an output root proposed on L1, waiting for the L2 node to reach its block.
+type proposal struct {
+	outputRoot    eth.Bytes32
+	l2BlockNumber uint64
+
+	// source identifies the proposal in the logs, the output index or the dispute game.
+	source string
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+	l2Client *ethclient.Client
+
+	// filter of the proposal events, `OutputProposed` on the L2OutputOracle or `DisputeGameCreated` on the DisputeGameFactory.
+	source    common.Address
+	topics    [][]common.Hash
+	eventName string
+	l2oo      *bindings.L2OutputOracleFilterer
+	factory   *bindings.DisputeGameFactoryFilterer
+
+	nextBlock       uint64
+	eventBlockRange uint64
+
+	// pending proposals, in the order of proposal.
+	pending []proposal
+
+	// metrics
+	highestBlockNumber      *prometheus.GaugeVec
+	pendingProposals        prometheus.Gauge
+	proposals               *prometheus.CounterVec
+	mismatched              prometheus.Gauge
+	lastVerifiedBlockNumber prometheus.Gauge
+	inconsistentResponses   *prometheus.CounterVec
+	unexpectedRpcErrors     *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating output verifier monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	l2Client, err := ethclient.Dial(cfg.L2NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l2: %w", err)
+	}
+
+	monitor := &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+		l2Client: l2Client,
+
+		eventBlockRange: cfg.EventBlockRange,
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed l1 heights (checked and known)",
+		}, []string{"type"}),
+		pendingProposals: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "pendingProposals",
+			Help:      "number of proposals waiting for the L2 node to reach their block",
+		}),
+		proposals: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "proposals",
+			Help:      "number of proposals verified by result (valid, mismatched)",
+		}, []string{"result"}),
+		mismatched: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "mismatched",
+			Help:      "1 if the last verified proposal doesn't match the recomputed output root",
+		}),
+		lastVerifiedBlockNumber: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "lastVerifiedBlockNumber",
+			Help:      "L2 block number of the last verified proposal",
+		}),
+		inconsistentResponses: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "inconsistentResponses",
+			Help:      "number of responses of the L2 node failing verification (header hash, proof)",
+		}, []string{"kind"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}
+
+	if cfg.L2OutputOracleAddress != nil {
+		if monitor.l2oo, err = bindings.NewL2OutputOracleFilterer(*cfg.L2OutputOracleAddress, l1Client); err != nil {
+			return nil, fmt.Errorf("failed to bind to the L2OutputOracle: %w", err)
+		}
+		l2ooABI, err := bindings.L2OutputOracleMetaData.GetAbi()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the L2OutputOracle ABI: %w", err)
+		}
+		monitor.source, monitor.eventName = *cfg.L2OutputOracleAddress, "OutputProposed"
+		monitor.topics = [][]common.Hash{{l2ooABI.Events[monitor.eventName].ID}}
+	} else {
+		if monitor.factory, err = bindings.NewDisputeGameFactoryFilterer(*cfg.DisputeGameFactoryAddress, l1Client); err != nil {
+			return nil, fmt.Errorf("failed to bind to the DisputeGameFactory: %w", err)
+		}
+		factoryABI, err := bindings.DisputeGameFactoryMetaData.GetAbi()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the DisputeGameFactory ABI: %w", err)
+		}
+		monitor.source, monitor.eventName = *cfg.DisputeGameFactoryAddress, "DisputeGameCreated"
+		monitor.topics = [][]common.Hash{{factoryABI.Events[monitor.eventName].ID}, nil, {common.BigToHash(new(big.Int).SetUint64(uint64(cfg.GameType)))}}
+	}
+
+	monitor.nextBlock = cfg.StartBlockHeight
+	if monitor.nextBlock == 0 {
+		if monitor.nextBlock, err = l1Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+
+	log.Info("configured proposals", "source", monitor.source, "event", monitor.eventName, "start_block", monitor.nextBlock)
+	return monitor, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestL1Height))
+
+	if m.nextBlock <= latestL1Height {
+		toBlock := min(latestL1Height, m.nextBlock+m.eventBlockRange-1)
+		if err := m.processProposals(ctx, m.nextBlock, toBlock); err != nil {
+			m.log.Error("failed to process the proposals", "from", m.nextBlock, "to", toBlock, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l1", m.eventName).Inc()
+			return
+		}
+		m.highestBlockNumber.WithLabelValues("checked").Set(float64(toBlock))
+		m.nextBlock = toBlock + 1
+	}
+
+	l2Height, err := m.l2Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest l2 height", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "blockNumber").Inc()
+		return
+	}
+
+	for len(m.pending) > 0 {
+		p := m.pending[0]
+		if p.l2BlockNumber > l2Height {
+			m.log.Info("l2 node is behind the proposal, waiting for sync...", "proposal", p.source, "l2_block", p.l2BlockNumber, "l2_height", l2Height)
+			break
+		}
+
+		outputRoot, ok := m.recomputeOutputRoot(ctx, p.l2BlockNumber)
+		if !ok {
+			break
+		}
+		if outputRoot != p.outputRoot {
+			m.log.Error("output root mismatch!!!", "proposal", p.source, "l2_block", p.l2BlockNumber, "proposed_output_root", p.outputRoot, "recomputed_output_root", outputRoot)
+			m.proposals.WithLabelValues("mismatched").Inc()
+			m.mismatched.Set(1)
+			m.Update(ctx, m.mismatchAlert(fmt.Sprintf("output root %s proposed by %s at l2 block %d doesn't match the recomputed output root %s", p.outputRoot, p.source, p.l2BlockNumber, outputRoot)), true)
+		} else {
+			m.log.Info("verified output", "proposal", p.source, "l2_block", p.l2BlockNumber, "output_root", outputRoot)
+			m.proposals.WithLabelValues("valid").Inc()
+			m.mismatched.Set(0)
+			m.Update(ctx, m.mismatchAlert(fmt.Sprintf("output root proposed by %s at l2 block %d verified", p.source, p.l2BlockNumber)), false)
+		}
+		m.lastVerifiedBlockNumber.Set(float64(p.l2BlockNumber))
+		m.pending = m.pending[1:]
+	}
+	m.pendingProposals.Set(float64(len(m.pending)))
+}
+
+// mismatchAlert returns the alert of a mismatched output root.
+func (m *Monitor) mismatchAlert(summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     MismatchRule,
+		Priority: "P0",
+		Entity:   m.source.String(),
+		Summary:  summary,
+	}
+}
+
+// processProposals queues the proposals made between the two blocks (inclusive).
+func (m *Monitor) processProposals(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	logs, err := m.l1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{m.source},
+		Topics:    m.topics,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the %s events: %w", m.eventName, err)
+	}
+
+	var proposals []proposal
+	for _, vLog := range logs {
+		if m.l2oo != nil {
+			output, err := m.l2oo.ParseOutputProposed(vLog)
+			if err != nil {
+				return fmt.Errorf("failed to parse the OutputProposed event: %w", err)
+			}
+			proposals = append(proposals, proposal{
+				outputRoot:    eth.Bytes32(output.OutputRoot),
+				l2BlockNumber: output.L2BlockNumber.Uint64(),
+				source:        fmt.Sprintf("output %s", output.L2OutputIndex),
+			})
+			continue
+		}
+
+		created, err := m.factory.ParseDisputeGameCreated(vLog)
+		if err != nil {
+			return fmt.Errorf("failed to parse the DisputeGameCreated event: %w", err)
+		}
+		game, err := bindings.NewFaultDisputeGameCaller(created.DisputeProxy, m.l1Client)
+		if err != nil {
+			return fmt.Errorf("failed to bind to the dispute game %s: %w", created.DisputeProxy, err)
+		}
+		l2BlockNumber, err := game.L2BlockNumber(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return fmt.Errorf("failed to query the l2 block number of the dispute game %s: %w", created.DisputeProxy, err)
+		}
+		proposals = append(proposals, proposal{
+			outputRoot:    eth.Bytes32(created.RootClaim),
+			l2BlockNumber: l2BlockNumber.Uint64(),
+			source:        fmt.Sprintf("game %s", created.DisputeProxy),
+		})
+	}
+
+	// the proposals are queued once all of the range is read, the range being read again on failure.
+	m.pending = append(m.pending, proposals...)
+	return nil
+}
+
+// recomputeOutputRoot recomputes the output root of the L2 block from its header and the storage root of the L2ToL1MessagePasser,
+// verifying the header against its hash and the account proof of the message passer against the state root rather than trusting the
+// L2 node. The failures are logged and counted, `false` is returned to retry on the next tick.
+func (m *Monitor) recomputeOutputRoot(ctx context.Context, l2BlockNumber uint64) (eth.Bytes32, bool) {
+	var raw json.RawMessage
+	if err := m.l2Client.Client().CallContext(ctx, &raw, "eth_getBlockByNumber", hexutil.EncodeUint64(l2BlockNumber), false); err != nil {
+		m.log.Error("failed to query l2 block", "height", l2BlockNumber, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "getBlockByNumber").Inc()
+		return eth.Bytes32{}, false
+	}
+	header, err := decodeHeader(raw)
+	if err != nil {
+		m.log.Error("inconsistent l2 header", "height", l2BlockNumber, "err", err)
+		m.inconsistentResponses.WithLabelValues("header").Inc()
+		return eth.Bytes32{}, false
+	}
+	if header.Number.Uint64() != l2BlockNumber {
+		m.log.Error("l2 header at another height", "height", l2BlockNumber, "header_height", header.Number)
+		m.inconsistentResponses.WithLabelValues("header").Inc()
+		return eth.Bytes32{}, false
+	}
+
+	var proof eth.AccountResult
+	if err := m.l2Client.Client().CallContext(ctx, &proof, "eth_getProof",
+		predeploys.L2ToL1MessagePasserAddr, []common.Hash{}, hexutil.EncodeUint64(l2BlockNumber)); err != nil {
+		m.log.Error("failed to query for proof response of l2ToL1MP contract", "height", l2BlockNumber, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "getProof").Inc()
+		return eth.Bytes32{}, false
+	}
+	if proof.Address != predeploys.L2ToL1MessagePasserAddr {
+		m.log.Error("proof of another account", "height", l2BlockNumber, "proof_address", proof.Address)
+		m.inconsistentResponses.WithLabelValues("proof").Inc()
+		return eth.Bytes32{}, false
+	}
+	if err := proof.Verify(header.Root); err != nil {
+		m.log.Error("invalid proof of the l2ToL1MP contract", "height", l2BlockNumber, "state_root", header.Root, "err", err)
+		m.inconsistentResponses.WithLabelValues("proof").Inc()
+		return eth.Bytes32{}, false
+	}
+
+	return eth.OutputRoot(&eth.OutputV0{StateRoot: eth.Bytes32(header.Root), MessagePasserStorageRoot: eth.Bytes32(proof.StorageHash), BlockHash: header.Hash()}), true
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	m.l2Client.Close()
+	return nil
+}
+
+// decodeHeader decodes the header of the JSON-RPC block and checks its fields against the block hash returned by the node, the
+// block hash of the output root being the hash of the header.
+func decodeHeader(raw json.RawMessage) (*types.Header, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, fmt.Errorf("block not found")
+	}
+	var header types.Header
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("failed to decode the header: %w", err)
+	}
+	var block struct {
+		Hash common.Hash `json:"hash"`
+	}
+	if err := json.Unmarshal(raw, &block); err != nil {
+		return nil, fmt.Errorf("failed to decode the block hash: %w", err)
+	}
+	if header.Hash() != block.Hash {
+		return nil, fmt.Errorf("header hashes to %s but the block hash is %s", header.Hash(), block.Hash)
+	}
+	return &header, nil
+}
@@ -0,0 +1,198 @@
+package output_verifier
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDecodeHeader(t *testing.T) {
+	header := &types.Header{
+		ParentHash: common.HexToHash("0x01"),
+		Root:       common.HexToHash("0x02"),
+		Number:     big.NewInt(100),
+		GasLimit:   30_000_000,
+		Time:       1000,
+		Difficulty: common.Big0,
+		BaseFee:    big.NewInt(7),
+	}
+	valid, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	tampered := *header
+	tampered.Root = common.HexToHash("0x03")
+	tamperedRaw, err := json.Marshal(&tampered)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(tamperedRaw, &fields); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	fields["hash"] = header.Hash() // hash of the untampered header.
+	tamperedRaw, _ = json.Marshal(fields)
+
+	tests := []struct {
+		name  string
+		raw   json.RawMessage
+		fails bool
+	}{
+		{name: "Consistent header", raw: valid},
+		{name: "Tampered state root", raw: tamperedRaw, fails: true},
+		{name: "Block not found", raw: json.RawMessage("null"), fails: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := decodeHeader(test.raw)
+			if (err != nil) != test.fails {
+				t.Fatalf("Failed %s: expected failure %t but got %v", test.name, test.fails, err)
+			}
+			if err == nil && output.Hash() != header.Hash() {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, header.Hash(), output.Hash())
+			}
+		})
+	}
+}
+
+var l2oo = common.HexToAddress("0xdfe97868233d1aa22e815a266982f2cf17685a27")
+
+// newL2Node returns an L2 node whose block 1 commits to the storage root of the L2ToL1MessagePasser, and the output root
+// of the block.
+func newL2Node(t *testing.T) (*fake.Node, eth.Bytes32) {
+	storageHash := common.HexToHash("0x5f")
+	account, err := rlp.EncodeToBytes([]interface{}{uint64(1), []byte{}, storageHash, types.EmptyCodeHash})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	state := trie.NewEmpty(trie.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+	key := crypto.Keccak256(predeploys.L2ToL1MessagePasserAddr.Bytes())
+	state.MustUpdate(key, account)
+	var proof trienode.ProofList
+	if err := state.Prove(key, &proof); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{})
+	header := node.AddBlock(&types.Header{Root: state.Hash()})
+	result := &eth.AccountResult{Address: predeploys.L2ToL1MessagePasserAddr, Balance: (*hexutil.Big)(big.NewInt(0)), CodeHash: types.EmptyCodeHash, Nonce: 1, StorageHash: storageHash, StorageProof: []eth.StorageProofEntry{}}
+	for _, encoded := range proof {
+		result.AccountProof = append(result.AccountProof, hexutil.Bytes(encoded))
+	}
+	node.Result("eth_getProof", result)
+	return node, eth.OutputRoot(&eth.OutputV0{StateRoot: eth.Bytes32(header.Root), MessagePasserStorageRoot: eth.Bytes32(storageHash), BlockHash: header.Hash()})
+}
+
+// propose adds an L1 block proposing the output root of the L2 block 1 at the index.
+func propose(t *testing.T, l1 *fake.Node, outputRoot eth.Bytes32, index int64) {
+	event := util.MustParseABI(bindings.L2OutputOracleMetaData).Events["OutputProposed"]
+	data, err := event.Inputs.NonIndexed().Pack(big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	header := l1.AddBlock(&types.Header{})
+	l1.AddLogs(types.Log{
+		Address:     l2oo,
+		Topics:      []common.Hash{event.ID, common.Hash(outputRoot), common.BigToHash(big.NewInt(index)), common.BigToHash(big.NewInt(1))},
+		Data:        data,
+		BlockNumber: header.Number.Uint64(),
+	})
+}
+
+// newTestMonitor returns the monitor of the outputs proposed to the L2OutputOracle from the block 1, and the notifier of
+// its alerts.
+func newTestMonitor(t *testing.T, l1 *fake.Node, l2 *fake.Node) (*Monitor, *fake.Notifier) {
+	oracle := l2oo
+	cfg := CLIConfig{L1NodeURL: l1.URL, L2NodeURL: l2.URL, L2OutputOracleAddress: &oracle, StartBlockHeight: 1, EventBlockRange: 100}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	l1 := fake.NewNode(t)
+	l1.AddBlock(&types.Header{})
+	l2, outputRoot := newL2Node(t)
+	m, _ := newTestMonitor(t, l1, l2)
+	ctx := context.Background()
+
+	propose(t, l1, outputRoot, 0)
+	m.Run(ctx)
+	if valid, verified, pending := testutil.ToFloat64(m.proposals.WithLabelValues("valid")), testutil.ToFloat64(m.lastVerifiedBlockNumber), testutil.ToFloat64(m.pendingProposals); valid != 1 || verified != 1 || pending != 0 {
+		t.Errorf("expected 1 valid proposal of the l2 block 1 but got %v, %v and %v", valid, verified, pending)
+	}
+	if checked := testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("checked")); checked != 1 {
+		t.Errorf("expected the block 1 checked but got %v", checked)
+	}
+
+	// a mismatched output root is counted and reported.
+	propose(t, l1, eth.Bytes32{0x01}, 1)
+	m.Run(ctx)
+	if mismatched, current := testutil.ToFloat64(m.proposals.WithLabelValues("mismatched")), testutil.ToFloat64(m.mismatched); mismatched != 1 || current != 1 {
+		t.Errorf("expected 1 mismatched proposal but got %v and %v", mismatched, current)
+	}
+
+	// a proof of another account is inconsistent, the proposal being kept pending.
+	l2.Result("eth_getProof", &eth.AccountResult{Address: common.HexToAddress("0x01"), Balance: (*hexutil.Big)(big.NewInt(0)), StorageProof: []eth.StorageProofEntry{}})
+	propose(t, l1, outputRoot, 2)
+	m.Run(ctx)
+	if inconsistent, pending := testutil.ToFloat64(m.inconsistentResponses.WithLabelValues("proof")), testutil.ToFloat64(m.pendingProposals); inconsistent != 1 || pending != 1 {
+		t.Errorf("expected 1 inconsistent proof keeping the proposal pending but got %v and %v", inconsistent, pending)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	l1 := fake.NewNode(t)
+	l1.AddBlock(&types.Header{})
+	l2, outputRoot := newL2Node(t)
+	m, notifier := newTestMonitor(t, l1, l2)
+	ctx := context.Background()
+
+	propose(t, l1, outputRoot, 0)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 || len(m.pending) != 0 {
+		t.Fatalf("expected the output verified without alert but got %v", alerts)
+	}
+
+	// a mismatched output root fires once, resolved by the next verified output.
+	propose(t, l1, eth.Bytes32{0x01}, 1)
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(MismatchRule); len(alerts) != 1 || alerts[0].Entity != l2oo.String() || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the mismatch alert once but got %v", alerts)
+	}
+	propose(t, l1, outputRoot, 2)
+	m.Run(ctx)
+	if firing := notifier.Firing(MismatchRule); len(firing) != 0 {
+		t.Fatalf("expected the mismatch alert resolved but got %v", firing)
+	}
+}
@@ -0,0 +1,105 @@
+package p2p
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodesFlagName            = "nodes"
+	MetricsURLsFlagName      = "metrics.urls"
+	PeersFloorFlagName       = "peers.floor"
+	GossipPeersFloorFlagName = "gossip.peers.floor"
+	ScoreFloorFlagName       = "score.floor"
+)
+
+// Node is an op-node queried through its RPC, along with its metrics endpoint when configured.
+type Node struct {
+	Name       string
+	URL        string
+	MetricsURL string
+}
+
+type CLIConfig struct {
+	Nodes []Node
+
+	// PeersFloor and GossipPeersFloor are the min numbers of connected peers and of peers in the blocks topic.
+	PeersFloor       uint64
+	GossipPeersFloor uint64
+
+	// ScoreFloor is the gossip score below which a peer is reported as low scored.
+	ScoreFloor float64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		PeersFloor:       ctx.Uint64(PeersFloorFlagName),
+		GossipPeersFloor: ctx.Uint64(GossipPeersFloorFlagName),
+		ScoreFloor:       ctx.Float64(ScoreFloorFlagName),
+	}
+
+	names := make(map[string]int)
+	for _, node := range ctx.StringSlice(NodesFlagName) {
+		name, url, err := util.ParseNamedValue(node, "name=url")
+		if err != nil {
+			return cfg, err
+		}
+		if _, ok := names[name]; ok {
+			return cfg, fmt.Errorf("duplicated node %s", name)
+		}
+		names[name] = len(cfg.Nodes)
+		cfg.Nodes = append(cfg.Nodes, Node{Name: name, URL: url})
+	}
+
+	for _, metricsURL := range ctx.StringSlice(MetricsURLsFlagName) {
+		name, url, err := util.ParseNamedValue(metricsURL, "name=url")
+		if err != nil {
+			return cfg, err
+		}
+		i, ok := names[name]
+		if !ok {
+			return cfg, fmt.Errorf("--%s: unknown node %s", MetricsURLsFlagName, name)
+		}
+		cfg.Nodes[i].MetricsURL = url
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     NodesFlagName,
+			Usage:    "One or more op-node RPC URLs formatted via `name=url`",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "NODES"),
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:    MetricsURLsFlagName,
+			Usage:   "One or more op-node metrics URLs formatted via `name=url` to report the gossip bandwidth, the name being the one of the node",
+			EnvVars: opservice.PrefixEnvVar(envVar, "METRICS_URLS"),
+		},
+		&cli.Uint64Flag{
+			Name:    PeersFloorFlagName,
+			Usage:   "Min number of connected peers",
+			Value:   10,
+			EnvVars: opservice.PrefixEnvVar(envVar, "PEERS_FLOOR"),
+		},
+		&cli.Uint64Flag{
+			Name:    GossipPeersFloorFlagName,
+			Usage:   "Min number of peers in the blocks topic, gossiping the unsafe blocks",
+			Value:   5,
+			EnvVars: opservice.PrefixEnvVar(envVar, "GOSSIP_PEERS_FLOOR"),
+		},
+		&cli.Float64Flag{
+			Name:    ScoreFloorFlagName,
+			Usage:   "Gossip score below which a peer is reported as low scored",
+			Value:   0,
+			EnvVars: opservice.PrefixEnvVar(envVar, "SCORE_FLOOR"),
+		},
+	}
+}
@@ -0,0 +1,339 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	MonitorName      = "p2p"
+	MetricsNamespace = "p2p_mon"
+
+	// BelowFloorRule is the rule of the alert emitted while the peers of a node are below their floor, the entity being
+	// the node and the kind of peers (connected or gossip).
+	BelowFloorRule = "peers below floor"
+
+	// BandwidthMetricSuffix is the suffix of the op-node bandwidth metric, prefixed with the namespace of the process.
+	BandwidthMetricSuffix = "_p2p_bandwidth_bytes_total"
+)
+
+// peerStats is the result of `opp2p_peerStats`.
+type peerStats struct {
+	Connected     uint `json:"connected"`
+	Table         uint `json:"table"`
+	BlocksTopic   uint `json:"blocksTopic"`
+	BlocksTopicV2 uint `json:"blocksTopicV2"`
+	BlocksTopicV3 uint `json:"blocksTopicV3"`
+	Banned        uint `json:"banned"`
+	Known         uint `json:"known"`
+}
+
+// gossipPeers returns the number of peers of the most populated blocks topic, the topic in use depending on the active hardfork.
+func (s *peerStats) gossipPeers() uint {
+	return max(s.BlocksTopic, s.BlocksTopicV2, s.BlocksTopicV3)
+}
+
+// peerDump is the subset of the result of `opp2p_peers` used by the monitor.
+type peerDump struct {
+	TotalConnected uint                  `json:"totalConnected"`
+	Peers          map[string]*peerScore `json:"peers"`
+}
+
+type peerScore struct {
+	Scores struct {
+		Gossip struct {
+			Total float64 `json:"total"`
+		} `json:"gossip"`
+	} `json:"scores"`
+}
+
+type node struct {
+	Node
+	rpc client.RPC
+
+	// bandwidth of the previous iteration, to compute the rate.
+	bandwidth   map[string]float64
+	bandwidthAt time.Time
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	nodes      []*node
+	httpClient *http.Client
+
+	peersFloor       uint64
+	gossipPeersFloor uint64
+	scoreFloor       float64
+
+	// metrics
+	peers               *prometheus.GaugeVec
+	peerScores          *prometheus.GaugeVec
+	lowScorePeers       *prometheus.GaugeVec
+	belowFloor          *prometheus.GaugeVec
+	unsafeHead          *prometheus.GaugeVec
+	unsafeHeadAge       *prometheus.GaugeVec
+	bandwidth           *prometheus.GaugeVec
+	bandwidthRate       *prometheus.GaugeVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating p2p monitor...")
+
+	nodes := make([]*node, len(cfg.Nodes))
+	for i, n := range cfg.Nodes {
+		rpc, err := client.NewRPC(ctx, log, n.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial the node %s: %w", n.Name, err)
+		}
+		nodes[i] = &node{Node: n, rpc: rpc}
+		log.Info("configured node", "name", n.Name, "metrics", len(n.MetricsURL) > 0)
+	}
+
+	return &Monitor{
+		log: log,
+
+		nodes:      nodes,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+
+		peersFloor:       cfg.PeersFloor,
+		gossipPeersFloor: cfg.GossipPeersFloor,
+		scoreFloor:       cfg.ScoreFloor,
+
+		peers: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "peers",
+			Help:      "number of peers of the node by type (connected, table, blocksTopic, blocksTopicV2, blocksTopicV3, banned, known)",
+		}, []string{"node", "type"}),
+		peerScores: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "peerScores",
+			Help:      "gossip scores of the connected peers (min, median, max)",
+		}, []string{"node", "quantile"}),
+		lowScorePeers: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "lowScorePeers",
+			Help:      "number of connected peers with a gossip score below the floor",
+		}, []string{"node"}),
+		belowFloor: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "belowFloor",
+			Help:      "1 if the number of peers of the type (connected, gossip) is below its floor, 0 otherwise",
+		}, []string{"node", "type"}),
+		unsafeHead: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unsafeHead",
+			Help:      "number of the unsafe head of the node",
+		}, []string{"node"}),
+		unsafeHeadAge: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unsafeHeadAge",
+			Help:      "seconds since the timestamp of the unsafe head of the node",
+		}, []string{"node"}),
+		bandwidth: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "bandwidth",
+			Help:      "total p2p bytes of the node by direction, reported by its metrics",
+		}, []string{"node", "direction"}),
+		bandwidthRate: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "bandwidthRate",
+			Help:      "p2p bytes per second of the node by direction since the previous iteration",
+		}, []string{"node", "direction"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	for _, n := range m.nodes {
+		m.checkPeers(ctx, n)
+		m.checkUnsafeHead(ctx, n)
+		if len(n.MetricsURL) > 0 {
+			m.checkBandwidth(ctx, n)
+		}
+	}
+}
+
+// checkPeers reports the peer counts and the gossip scores of the node, and whether they are below their floors.
+func (m *Monitor) checkPeers(ctx context.Context, n *node) {
+	var stats peerStats
+	if err := n.rpc.CallContext(ctx, &stats, "opp2p_peerStats"); err != nil {
+		m.log.Error("failed to query the peer stats", "node", n.Name, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues(n.Name, "peerStats").Inc()
+		return
+	}
+
+	for peerType, count := range map[string]uint{
+		"connected":     stats.Connected,
+		"table":         stats.Table,
+		"blocksTopic":   stats.BlocksTopic,
+		"blocksTopicV2": stats.BlocksTopicV2,
+		"blocksTopicV3": stats.BlocksTopicV3,
+		"banned":        stats.Banned,
+		"known":         stats.Known,
+	} {
+		m.peers.WithLabelValues(n.Name, peerType).Set(float64(count))
+	}
+
+	connectedBelow := isBelowFloor(stats.Connected, m.peersFloor)
+	gossipBelow := isBelowFloor(stats.gossipPeers(), m.gossipPeersFloor)
+	if connectedBelow || gossipBelow {
+		m.log.Warn("peers below floor", "node", n.Name, "connected", stats.Connected, "gossip", stats.gossipPeers())
+	}
+	m.belowFloor.WithLabelValues(n.Name, "connected").Set(util.BoolToFloat(connectedBelow))
+	m.belowFloor.WithLabelValues(n.Name, "gossip").Set(util.BoolToFloat(gossipBelow))
+	m.Update(ctx, belowFloorAlert(n, "connected", fmt.Sprintf("%d peers connected to %s, below the floor of %d", stats.Connected, n.Name, m.peersFloor)), connectedBelow)
+	m.Update(ctx, belowFloorAlert(n, "gossip", fmt.Sprintf("%d gossip peers of %s, below the floor of %d", stats.gossipPeers(), n.Name, m.gossipPeersFloor)), gossipBelow)
+
+	var dump peerDump
+	if err := n.rpc.CallContext(ctx, &dump, "opp2p_peers", true); err != nil {
+		m.log.Error("failed to query the peers", "node", n.Name, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues(n.Name, "peers").Inc()
+		return
+	}
+
+	scores := make([]float64, 0, len(dump.Peers))
+	for _, peer := range dump.Peers {
+		scores = append(scores, peer.Scores.Gossip.Total)
+	}
+	low := 0
+	for _, score := range scores {
+		if score < m.scoreFloor {
+			low++
+		}
+	}
+	m.lowScorePeers.WithLabelValues(n.Name).Set(float64(low))
+
+	if len(scores) == 0 {
+		return
+	}
+	sort.Float64s(scores)
+	m.peerScores.WithLabelValues(n.Name, "min").Set(scores[0])
+	m.peerScores.WithLabelValues(n.Name, "median").Set(median(scores))
+	m.peerScores.WithLabelValues(n.Name, "max").Set(scores[len(scores)-1])
+}
+
+// belowFloorAlert returns the alert of the kind of peers of the node below its floor.
+func belowFloorAlert(n *node, kind string, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     BelowFloorRule,
+		Priority: "P2",
+		Entity:   n.Name + "/" + kind,
+		Summary:  summary,
+		Labels:   map[string]string{"node": n.Name, "kind": kind},
+	}
+}
+
+// checkUnsafeHead reports the unsafe head of the node, which stalls when the unsafe blocks are not gossiped anymore.
+func (m *Monitor) checkUnsafeHead(ctx context.Context, n *node) {
+	var status eth.SyncStatus
+	if err := n.rpc.CallContext(ctx, &status, "optimism_syncStatus"); err != nil {
+		m.log.Error("failed to query the sync status", "node", n.Name, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues(n.Name, "syncStatus").Inc()
+		return
+	}
+
+	m.unsafeHead.WithLabelValues(n.Name).Set(float64(status.UnsafeL2.Number))
+	m.unsafeHeadAge.WithLabelValues(n.Name).Set(float64(time.Now().Unix()) - float64(status.UnsafeL2.Time))
+}
+
+// checkBandwidth reads the p2p bandwidth from the metrics of the node and reports its rate since the previous iteration.
+func (m *Monitor) checkBandwidth(ctx context.Context, n *node) {
+	now := time.Now()
+	bandwidth, err := m.readBandwidth(ctx, n.MetricsURL)
+	if err != nil {
+		m.log.Error("failed to read the bandwidth", "node", n.Name, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues(n.Name, "metrics").Inc()
+		return
+	}
+
+	for direction, bytes := range bandwidth {
+		m.bandwidth.WithLabelValues(n.Name, direction).Set(bytes)
+		if previous, ok := n.bandwidth[direction]; ok && bytes >= previous {
+			m.bandwidthRate.WithLabelValues(n.Name, direction).Set((bytes - previous) / now.Sub(n.bandwidthAt).Seconds())
+		}
+	}
+	n.bandwidth, n.bandwidthAt = bandwidth, now
+}
+
+// readBandwidth scrapes the metrics endpoint of an op-node and returns the total bytes by direction.
+func (m *Monitor) readBandwidth(ctx context.Context, metricsURL string) (map[string]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metricsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the metrics: %w", err)
+	}
+	for name, family := range families {
+		if !strings.HasSuffix(name, BandwidthMetricSuffix) {
+			continue
+		}
+		bandwidth := make(map[string]float64)
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "direction" {
+					bandwidth[label.GetValue()] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+		return bandwidth, nil
+	}
+	return nil, fmt.Errorf("metric *%s not found", BandwidthMetricSuffix)
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	for _, n := range m.nodes {
+		n.rpc.Close()
+	}
+	return nil
+}
+
+// isBelowFloor returns true when the number of peers is below the floor.
+func isBelowFloor(peers uint, floor uint64) bool {
+	return uint64(peers) < floor
+}
+
+// median returns the median of sorted values.
+func median(sorted []float64) float64 {
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -0,0 +1,159 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestGossipPeers(t *testing.T) {
+	stats := peerStats{Connected: 40, BlocksTopic: 3, BlocksTopicV2: 12, BlocksTopicV3: 30}
+	if peers := stats.gossipPeers(); peers != 30 {
+		t.Errorf("expected 30 but got %d", peers)
+	}
+}
+
+func TestIsBelowFloor(t *testing.T) {
+	tests := []struct {
+		name     string
+		peers    uint
+		expected bool
+	}{
+		{name: "Above the floor", peers: 20, expected: false},
+		{name: "On the floor", peers: 10, expected: false},
+		{name: "Below the floor", peers: 9, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isBelowFloor(test.peers, 10)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestMedian(t *testing.T) {
+	if m := median([]float64{-5, 1, 3}); m != 1 {
+		t.Errorf("expected 1 but got %f", m)
+	}
+	if m := median([]float64{-5, 1, 3, 7}); m != 2 {
+		t.Errorf("expected 2 but got %f", m)
+	}
+}
+
+// newOpNode returns an op-node serving the peer stats, without peers nor sync status.
+func newOpNode(t *testing.T, stats *peerStats) *fake.Node {
+	node := fake.NewNode(t)
+	node.Handle("opp2p_peerStats", func(_ []json.RawMessage) (interface{}, error) {
+		return stats, nil
+	})
+	node.Result("opp2p_peers", peerDump{Peers: map[string]*peerScore{}})
+	node.Result("optimism_syncStatus", eth.SyncStatus{})
+	return node
+}
+
+// newTestMonitor returns the monitor of the node with the floors of 10 connected and 5 gossip peers, and the notifier
+// of its alerts.
+func newTestMonitor(t *testing.T, node *fake.Node) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{Nodes: []Node{{Name: "op-node-0", URL: node.URL}}, PeersFloor: 10, GossipPeersFloor: 5}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := newOpNode(t, &peerStats{Connected: 40, BlocksTopicV3: 3})
+	node.Result("opp2p_peers", json.RawMessage(`{"peers":{"a":{"scores":{"gossip":{"total":-5}}},"b":{"scores":{"gossip":{"total":1}}},"c":{"scores":{"gossip":{"total":2}}}}}`))
+	node.Result("optimism_syncStatus", eth.SyncStatus{UnsafeL2: eth.L2BlockRef{Number: 100}})
+	bandwidth := 1000
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, "# TYPE op_node_default_p2p_bandwidth_bytes_total gauge\nop_node_default_p2p_bandwidth_bytes_total{direction=\"in\"} %d\n", bandwidth)
+	}))
+	t.Cleanup(server.Close)
+	cfg := CLIConfig{Nodes: []Node{{Name: "op-node-0", URL: node.URL, MetricsURL: server.URL}}, PeersFloor: 10, GossipPeersFloor: 5}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	m.SetNotifier(&fake.Notifier{})
+	ctx := context.Background()
+
+	// the peer counts are reported, the gossip peers below their floor.
+	m.Run(ctx)
+	if connected, gossip := testutil.ToFloat64(m.peers.WithLabelValues("op-node-0", "connected")), testutil.ToFloat64(m.peers.WithLabelValues("op-node-0", "blocksTopicV3")); connected != 40 || gossip != 3 {
+		t.Errorf("expected 40 connected and 3 gossip peers but got %v and %v", connected, gossip)
+	}
+	if connected, gossip := testutil.ToFloat64(m.belowFloor.WithLabelValues("op-node-0", "connected")), testutil.ToFloat64(m.belowFloor.WithLabelValues("op-node-0", "gossip")); connected != 0 || gossip != 1 {
+		t.Errorf("expected only the gossip peers below their floor but got %v and %v", connected, gossip)
+	}
+
+	// the gossip scores are summarized, the negative one being low.
+	low, lowest, median, highest := testutil.ToFloat64(m.lowScorePeers.WithLabelValues("op-node-0")), testutil.ToFloat64(m.peerScores.WithLabelValues("op-node-0", "min")), testutil.ToFloat64(m.peerScores.WithLabelValues("op-node-0", "median")), testutil.ToFloat64(m.peerScores.WithLabelValues("op-node-0", "max"))
+	if low != 1 || lowest != -5 || median != 1 || highest != 2 {
+		t.Errorf("expected 1 low score peer with the scores -5, 1 and 2 but got %v, %v, %v and %v", low, lowest, median, highest)
+	}
+	if head := testutil.ToFloat64(m.unsafeHead.WithLabelValues("op-node-0")); head != 100 {
+		t.Errorf("expected the unsafe head 100 but got %v", head)
+	}
+
+	// the bandwidth is read from the metrics of the node, its rate following from the previous iteration.
+	if in := testutil.ToFloat64(m.bandwidth.WithLabelValues("op-node-0", "in")); in != 1000 {
+		t.Errorf("expected 1000 bytes in but got %v", in)
+	}
+	bandwidth = 2000
+	m.Run(ctx)
+	if rate := testutil.ToFloat64(m.bandwidthRate.WithLabelValues("op-node-0", "in")); rate <= 0 {
+		t.Errorf("expected a positive rate of bytes in but got %v", rate)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	stats := &peerStats{Connected: 40, BlocksTopicV3: 30}
+	m, notifier := newTestMonitor(t, newOpNode(t, stats))
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// the gossip peers below their floor fire once, resolved once back above.
+	stats.BlocksTopicV3 = 2
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(BelowFloorRule); len(alerts) != 1 || alerts[0].Entity != "op-node-0/gossip" || alerts[0].Priority != "P2" || alerts[0].Labels["kind"] != "gossip" {
+		t.Fatalf("expected the gossip below floor alert once but got %v", alerts)
+	}
+	stats.BlocksTopicV3 = 30
+	m.Run(ctx)
+	if firing := notifier.Firing(BelowFloorRule); len(firing) != 0 {
+		t.Fatalf("expected the below floor alert resolved but got %v", firing)
+	}
+
+	// losing the peers fires for both kinds.
+	stats.Connected, stats.BlocksTopicV3 = 3, 0
+	m.Run(ctx)
+	if firing := notifier.Firing(BelowFloorRule); len(firing) != 2 {
+		t.Fatalf("expected the connected and gossip below floor alerts but got %v", firing)
+	}
+}
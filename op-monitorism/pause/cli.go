@@ -0,0 +1,72 @@
+package pause
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+
+	OptimismPortalAddressFlagName         = "optimismportal.address"
+	L1CrossDomainMessengerAddressFlagName = "l1crossdomainmessenger.address"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+
+	OptimismPortalAddress common.Address
+
+	// L1CrossDomainMessengerAddress is nil when the relay of the messages is not simulated.
+	L1CrossDomainMessengerAddress *common.Address
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL: ctx.String(L1NodeURLFlagName),
+	}
+
+	portalAddress := ctx.String(OptimismPortalAddressFlagName)
+	if !common.IsHexAddress(portalAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", OptimismPortalAddressFlagName)
+	}
+	cfg.OptimismPortalAddress = common.HexToAddress(portalAddress)
+
+	messengerAddress := ctx.String(L1CrossDomainMessengerAddressFlagName)
+	if len(messengerAddress) > 0 {
+		if !common.IsHexAddress(messengerAddress) {
+			return cfg, fmt.Errorf("--%s is not a hex-encoded address", L1CrossDomainMessengerAddressFlagName)
+		}
+		addr := common.HexToAddress(messengerAddress)
+		cfg.L1CrossDomainMessengerAddress = &addr
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     OptimismPortalAddressFlagName,
+			Usage:    "Address of the OptimismPortal contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "OPTIMISM_PORTAL"),
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:    L1CrossDomainMessengerAddressFlagName,
+			Usage:   "Address of the L1CrossDomainMessenger contract, the relay of the messages is not simulated when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_CROSS_DOMAIN_MESSENGER"),
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package pause
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	outcomeSucceeded    = "succeeded"
+	outcomePausedRevert = "pausedRevert"
+	outcomeOtherRevert  = "otherRevert"
+)
+
+// action is a simulated call whose effect depends on the pause.
+type action struct {
+	name string
+	to   string // name of the target contract, for the logs.
+
+	// guarded is true when the action must revert with `pausedReason` while paused. An unguarded action, such as a deposit, must
+	// succeed whether paused or not.
+	guarded      bool
+	pausedReason string
+}
+
+// outcome classifies the result of the simulation of the action, an error not being a revert being returned as is.
+func (a action) outcome(err error) (string, error) {
+	if err == nil {
+		return outcomeSucceeded, nil
+	}
+	reason, reverted := revertReason(err)
+	if !reverted {
+		return "", err
+	}
+	if reason == a.pausedReason {
+		return outcomePausedRevert, nil
+	}
+	return outcomeOtherRevert, nil
+}
+
+// holds returns whether the outcome of the simulation is the one expected for the pause state: a guarded action reverts because of
+// the pause if and only if paused, and an unguarded action succeeds.
+func (a action) holds(paused bool, outcome string) bool {
+	if !a.guarded {
+		return outcome == outcomeSucceeded
+	}
+	return (outcome == outcomePausedRevert) == paused
+}
+
+// revertReason returns the reason of a reverted call, from the revert data when returned by the node, from the error message
+// otherwise. `false` is returned when the error is not a revert.
+func revertReason(err error) (string, bool) {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if raw, decodeErr := hexutil.Decode(data); decodeErr == nil {
+				if reason, unpackErr := abi.UnpackRevert(raw); unpackErr == nil {
+					return reason, true
+				}
+				return "", true // custom error or no reason.
+			}
+		}
+	}
+	if msg := err.Error(); strings.HasPrefix(msg, "execution reverted") {
+		return strings.TrimPrefix(strings.TrimPrefix(msg, "execution reverted"), ": "), true
+	}
+	return "", false
+}
@@ -0,0 +1,87 @@
+package pause
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type revertError struct{ data string }
+
+func (e revertError) Error() string          { return "execution reverted" }
+func (e revertError) ErrorCode() int         { return 3 }
+func (e revertError) ErrorData() interface{} { return e.data }
+
+// revertData returns the revert data of the reason, encoded as `Error(string)`.
+func revertData(t *testing.T, reason string) []byte {
+	stringType, _ := abi.NewType("string", "", nil)
+	packed, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	return append([]byte{0x08, 0xc3, 0x79, 0xa0}, packed...)
+}
+
+// revertWith returns the error of a call reverted with the reason, as returned by geth.
+func revertWith(t *testing.T, reason string) error {
+	return revertError{data: hexutil.Encode(revertData(t, reason))}
+}
+
+func TestOutcome(t *testing.T) {
+	guarded := action{name: "finalizeWithdrawal", guarded: true, pausedReason: "OptimismPortal: paused"}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+		fails    bool
+	}{
+		{name: "Succeeded", err: nil, expected: outcomeSucceeded},
+		{name: "Paused revert", err: revertWith(t, "OptimismPortal: paused"), expected: outcomePausedRevert},
+		{name: "Other revert", err: revertWith(t, "OptimismPortal: withdrawal has not been proven yet"), expected: outcomeOtherRevert},
+		{name: "Revert in the message", err: fmt.Errorf("execution reverted: OptimismPortal: paused"), expected: outcomePausedRevert},
+		{name: "Node down", err: errors.New("connection refused"), fails: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := guarded.outcome(test.err)
+			if (err != nil) != test.fails || output != test.expected {
+				t.Errorf("Failed %s: expected %q (fails %t) but got %q (%v)", test.name, test.expected, test.fails, output, err)
+			}
+		})
+	}
+}
+
+func TestHolds(t *testing.T) {
+	guarded := action{guarded: true}
+	unguarded := action{}
+
+	tests := []struct {
+		name     string
+		action   action
+		paused   bool
+		outcome  string
+		expected bool
+	}{
+		{name: "Guarded reverts when paused", action: guarded, paused: true, outcome: outcomePausedRevert, expected: true},
+		{name: "Guarded goes through when paused", action: guarded, paused: true, outcome: outcomeOtherRevert, expected: false},
+		{name: "Guarded succeeds when paused", action: guarded, paused: true, outcome: outcomeSucceeded, expected: false},
+		{name: "Guarded when unpaused", action: guarded, paused: false, outcome: outcomeOtherRevert, expected: true},
+		{name: "Guarded reverts as paused when unpaused", action: guarded, paused: false, outcome: outcomePausedRevert, expected: false},
+		{name: "Deposit when paused", action: unguarded, paused: true, outcome: outcomeSucceeded, expected: true},
+		{name: "Deposit reverts when paused", action: unguarded, paused: true, outcome: outcomePausedRevert, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := test.action.holds(test.paused, test.outcome)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, output)
+			}
+		})
+	}
+}
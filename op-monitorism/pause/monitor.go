@@ -0,0 +1,230 @@
+package pause
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "pause"
+	MetricsNamespace = "pause_mon"
+
+	// UnexpectedEffectRule is the rule of the alert emitted while an action doesn't have the expected outcome given the pause,
+	// the entity being the action.
+	UnexpectedEffectRule = "unexpected effect of the pause"
+)
+
+var (
+	// simulationSender is the sender of the simulated calls, an account without code or privilege.
+	simulationSender = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+)
+
+// simulation is an action along with the call simulating it.
+type simulation struct {
+	action
+	msg ethereum.CallMsg
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+
+	optimismPortal *bindings.OptimismPortalCaller
+	simulations    []simulation
+
+	// metrics
+	paused              prometheus.Gauge
+	effectHolds         *prometheus.GaugeVec
+	unexpectedEffects   *prometheus.CounterVec
+	highestBlockNumber  prometheus.Gauge
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating pause monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	optimismPortal, err := bindings.NewOptimismPortalCaller(cfg.OptimismPortalAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the OptimismPortal: %w", err)
+	}
+
+	simulations, err := portalSimulations(cfg.OptimismPortalAddress)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.L1CrossDomainMessengerAddress != nil {
+		relay, err := messengerSimulation(*cfg.L1CrossDomainMessengerAddress)
+		if err != nil {
+			return nil, err
+		}
+		simulations = append(simulations, relay)
+	}
+	for _, s := range simulations {
+		log.Info("configured simulation", "action", s.name, "contract", s.to, "address", s.msg.To, "guarded", s.guarded)
+	}
+
+	return &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+
+		optimismPortal: optimismPortal,
+		simulations:    simulations,
+
+		paused: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "paused",
+			Help:      "1 if the system is paused",
+		}),
+		effectHolds: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "effectHolds",
+			Help:      "1 if the simulation of the action has the outcome expected for the pause state",
+		}, []string{"action"}),
+		unexpectedEffects: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedEffects",
+			Help:      "number of simulations of the action with an unexpected outcome (succeeded, pausedRevert, otherRevert)",
+		}, []string{"action", "paused", "outcome"}),
+		highestBlockNumber: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "l1 height of the last simulations",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+// portalSimulations returns the simulations of the withdrawals, guarded by the pause, and of a deposit, which must go through.
+func portalSimulations(portal common.Address) ([]simulation, error) {
+	portalABI, err := bindings.OptimismPortalMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the OptimismPortal ABI: %w", err)
+	}
+
+	// the pause is checked before the withdrawal, an unproven dummy withdrawal reverts because of the pause first.
+	withdrawal := bindings.TypesWithdrawalTransaction{Nonce: common.Big0, Sender: simulationSender, Target: simulationSender, Value: common.Big0, GasLimit: common.Big0, Data: []byte{}}
+	prove, err := portalABI.Pack("proveWithdrawalTransaction", withdrawal, common.Big0, bindings.TypesOutputRootProof{}, [][]byte{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack proveWithdrawalTransaction: %w", err)
+	}
+	finalize, err := portalABI.Pack("finalizeWithdrawalTransaction", withdrawal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack finalizeWithdrawalTransaction: %w", err)
+	}
+	deposit, err := portalABI.Pack("depositTransaction", simulationSender, common.Big0, uint64(100_000), false, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack depositTransaction: %w", err)
+	}
+
+	const pausedReason = "OptimismPortal: paused"
+	return []simulation{
+		{
+			action: action{name: "proveWithdrawal", to: "OptimismPortal", guarded: true, pausedReason: pausedReason},
+			msg:    ethereum.CallMsg{From: simulationSender, To: &portal, Data: prove},
+		},
+		{
+			action: action{name: "finalizeWithdrawal", to: "OptimismPortal", guarded: true, pausedReason: pausedReason},
+			msg:    ethereum.CallMsg{From: simulationSender, To: &portal, Data: finalize},
+		},
+		{
+			action: action{name: "deposit", to: "OptimismPortal", pausedReason: pausedReason},
+			msg:    ethereum.CallMsg{From: simulationSender, To: &portal, Data: deposit},
+		},
+	}, nil
+}
+
+// messengerSimulation returns the simulation of the relay of a message, guarded by the pause.
+func messengerSimulation(messenger common.Address) (simulation, error) {
+	messengerABI, err := bindings.L1CrossDomainMessengerMetaData.GetAbi()
+	if err != nil {
+		return simulation{}, fmt.Errorf("failed to parse the L1CrossDomainMessenger ABI: %w", err)
+	}
+	relay, err := messengerABI.Pack("relayMessage", common.Big0, simulationSender, simulationSender, common.Big0, common.Big0, []byte{})
+	if err != nil {
+		return simulation{}, fmt.Errorf("failed to pack relayMessage: %w", err)
+	}
+	return simulation{
+		action: action{name: "relayMessage", to: "L1CrossDomainMessenger", guarded: true, pausedReason: "CrossDomainMessenger: paused"},
+		msg:    ethereum.CallMsg{From: simulationSender, To: &messenger, Data: relay},
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		return
+	}
+	blockNumber := new(big.Int).SetUint64(latestL1Height)
+
+	// the pause and the simulations are read at the same height.
+	paused, err := m.optimismPortal.Paused(&bind.CallOpts{Context: ctx, BlockNumber: blockNumber})
+	if err != nil {
+		m.log.Error("failed to query the paused status", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "paused").Inc()
+		return
+	}
+	m.paused.Set(util.BoolToFloat(paused))
+
+	for _, s := range m.simulations {
+		_, callErr := m.l1Client.CallContract(ctx, s.msg, blockNumber)
+		outcome, err := s.outcome(callErr)
+		if err != nil {
+			m.log.Error("failed to simulate the action", "action", s.name, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l1", s.name).Inc()
+			continue
+		}
+
+		holds := s.holds(paused, outcome)
+		if !holds {
+			reason, _ := revertReason(callErr)
+			m.log.Error("unexpected effect of the pause!!!", "action", s.name, "contract", s.to, "paused", paused, "guarded", s.guarded, "outcome", outcome, "reason", reason, "block", latestL1Height)
+			m.unexpectedEffects.WithLabelValues(s.name, fmt.Sprint(paused), outcome).Inc()
+		}
+		m.effectHolds.WithLabelValues(s.name).Set(util.BoolToFloat(holds))
+		m.Update(ctx, alerts.Alert{
+			Monitor:  MonitorName,
+			Rule:     UnexpectedEffectRule,
+			Priority: "P0",
+			Entity:   s.name,
+			Summary:  fmt.Sprintf("unexpected outcome %s of %s on the %s, paused: %t", outcome, s.name, s.to, paused),
+			Labels:   map[string]string{"contract": s.to, "paused": fmt.Sprint(paused)},
+		}, !holds)
+	}
+
+	m.highestBlockNumber.Set(float64(latestL1Height))
+	m.log.Info("checked the pause effects", "paused", paused, "block", latestL1Height)
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	return nil
+}
@@ -0,0 +1,134 @@
+package pause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var portal = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+
+// portalNode serves an OptimismPortal whose withdrawals revert because of the pause while paused, unless unguarded.
+type portalNode struct {
+	*fake.Node
+
+	paused    bool
+	unguarded bool
+}
+
+func newPortalNode(t *testing.T) *portalNode {
+	n := &portalNode{Node: fake.NewNode(t)}
+	n.AddBlock(&types.Header{})
+	withdraw := func(_ []interface{}) ([]interface{}, error) {
+		if n.paused && !n.unguarded {
+			return nil, fake.Revert(revertData(t, "OptimismPortal: paused"))
+		}
+		return nil, fake.Revert(revertData(t, "OptimismPortal: withdrawal has not been proven yet"))
+	}
+	n.Contract(portal, util.MustParseABI(bindings.OptimismPortalMetaData)).
+		Handle("paused", func(_ []interface{}) ([]interface{}, error) { return []interface{}{n.paused}, nil }).
+		Handle("proveWithdrawalTransaction", withdraw).
+		Handle("finalizeWithdrawalTransaction", withdraw).
+		Returns("depositTransaction")
+	return n
+}
+
+// newTestMonitor returns the monitor of the pause of the portal of the node, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *portalNode) (*Monitor, *fake.Notifier) {
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), CLIConfig{L1NodeURL: node.URL, OptimismPortalAddress: portal})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestSimulations(t *testing.T) {
+	simulations, err := portalSimulations(common.HexToAddress("0x01"))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	relay, err := messengerSimulation(common.HexToAddress("0x02"))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	guarded := 0
+	for _, s := range append(simulations, relay) {
+		if len(s.msg.Data) < 4 || s.msg.To == nil {
+			t.Errorf("invalid call for %s", s.name)
+		}
+		if s.guarded {
+			guarded++
+		}
+	}
+	if len(simulations) != 3 || guarded != 3 {
+		t.Errorf("expected the portal simulations and 3 guarded actions but got %d simulations and %d guarded", len(simulations), guarded)
+	}
+}
+
+func TestRun(t *testing.T) {
+	node := newPortalNode(t)
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if paused, holds := testutil.ToFloat64(m.paused), testutil.ToFloat64(m.effectHolds.WithLabelValues("proveWithdrawal")); paused != 0 || holds != 1 {
+		t.Errorf("expected the portal unpaused with the expected effects but got %v and %v", paused, holds)
+	}
+
+	// the withdrawals not reverting because of the pause while paused are counted by outcome.
+	node.paused, node.unguarded = true, true
+	m.Run(ctx)
+	if paused, holds := testutil.ToFloat64(m.paused), testutil.ToFloat64(m.effectHolds.WithLabelValues("finalizeWithdrawal")); paused != 1 || holds != 0 {
+		t.Errorf("expected the portal paused without the expected effects but got %v and %v", paused, holds)
+	}
+	if prove, finalize := testutil.ToFloat64(m.unexpectedEffects.WithLabelValues("proveWithdrawal", "true", "otherRevert")), testutil.ToFloat64(m.unexpectedEffects.WithLabelValues("finalizeWithdrawal", "true", "otherRevert")); prove != 1 || finalize != 1 {
+		t.Errorf("expected 1 unexpected effect of each withdrawal but got %v and %v", prove, finalize)
+	}
+
+	// the deposits are not affected by the pause.
+	if holds, block := testutil.ToFloat64(m.effectHolds.WithLabelValues("deposit")), testutil.ToFloat64(m.highestBlockNumber); holds != 1 || block != 0 {
+		t.Errorf("expected the deposit effect held at the block 0 but got %v and %v", holds, block)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newPortalNode(t)
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	node.paused = true
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// the withdrawals not reverting because of the pause while paused fire once, resolved once guarded again.
+	node.unguarded = true
+	m.Run(ctx)
+	m.Run(ctx)
+	alerts := notifier.Alerts(UnexpectedEffectRule)
+	if len(alerts) != 2 || alerts[0].Entity != "proveWithdrawal" || alerts[1].Entity != "finalizeWithdrawal" || alerts[0].Priority != "P0" || alerts[0].Labels["paused"] != "true" {
+		t.Fatalf("expected the unexpected effect alerts of the withdrawals once but got %v", alerts)
+	}
+	node.unguarded = false
+	m.Run(ctx)
+	if firing := notifier.Firing(UnexpectedEffectRule); len(firing) != 0 {
+		t.Fatalf("expected the unexpected effect alerts resolved but got %v", firing)
+	}
+}
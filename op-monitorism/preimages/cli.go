@@ -0,0 +1,68 @@
+package preimages
+
+import (
+	"fmt"
+	"time"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName             = "l1.node.url"
+	PreimageOracleAddressFlagName = "preimageoracle.address"
+	IgnoreAfterFlagName           = "ignore.after"
+)
+
+type CLIConfig struct {
+	L1NodeURL             string
+	PreimageOracleAddress common.Address
+
+	// IgnoreAfter is the duration after the end of the upload of a large preimage from which the proposal is no longer verified.
+	IgnoreAfter time.Duration
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:   ctx.String(L1NodeURLFlagName),
+		IgnoreAfter: ctx.Duration(IgnoreAfterFlagName),
+	}
+
+	oracleAddress := ctx.String(PreimageOracleAddressFlagName)
+	if !common.IsHexAddress(oracleAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", PreimageOracleAddressFlagName)
+	}
+	cfg.PreimageOracleAddress = common.HexToAddress(oracleAddress)
+
+	if cfg.IgnoreAfter <= 0 {
+		return cfg, fmt.Errorf("--%s must be positive", IgnoreAfterFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     PreimageOracleAddressFlagName,
+			Usage:    "Address of the PreimageOracle contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "PREIMAGE_ORACLE"),
+			Required: true,
+		},
+		&cli.DurationFlag{
+			Name:    IgnoreAfterFlagName,
+			Usage:   "Duration after the end of the upload of a large preimage from which the proposal is no longer verified nor reported",
+			Value:   14 * 24 * time.Hour,
+			EnvVars: opservice.PrefixEnvVar(envVar, "IGNORE_AFTER"),
+		},
+	}
+}
@@ -0,0 +1,266 @@
+package preimages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/keccak"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/keccak/fetcher"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/keccak/matrix"
+	keccakTypes "github.com/ethereum-optimism/optimism/op-challenger/game/keccak/types"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "preimages"
+	MetricsNamespace = "preimages_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the proposal (its claimant and its uuid).
+	UnchallengedRule = "invalid large preimage proposal not challenged"
+	FinalizedRule    = "invalid large preimage proposal finalized"
+
+	// batchSize is the max number of calls batched when reading the proposals.
+	batchSize = 100
+)
+
+// states are the states of the large preimage proposals.
+var states = []string{"uploading", "challengeWindow", "windowEnded", "countered"}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+
+	oracle   *contracts.PreimageOracleContract
+	verifier *keccak.PreimageVerifier
+
+	challengePeriod uint64
+	ignoreAfter     time.Duration
+
+	// verified are the verification results of the completed uploads by proposal, true when the proposal is valid.
+	// The leaves of a completed upload can't change, the results are final.
+	verified map[string]bool
+
+	// metrics
+	proposals                    *prometheus.GaugeVec
+	invalidProposals             *prometheus.GaugeVec
+	unchallengedInvalidProposals prometheus.Gauge
+	verifications                *prometheus.CounterVec
+	challengePeriodSeconds       prometheus.Gauge
+	highestBlockNumber           prometheus.Gauge
+	unexpectedRpcErrors          *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating preimages monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+
+	oracle, err := contracts.NewPreimageOracleContract(cfg.PreimageOracleAddress, batching.NewMultiCaller(l1Client.Client(), batchSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the PreimageOracle: %w", err)
+	}
+	challengePeriod, err := oracle.ChallengePeriod(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the challenge period: %w", err)
+	}
+	log.Info("configured preimage oracle", "address", cfg.PreimageOracleAddress, "challenge_period", time.Duration(challengePeriod)*time.Second, "ignore_after", cfg.IgnoreAfter)
+
+	monitor := &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+
+		oracle:   oracle,
+		verifier: keccak.NewPreimageVerifier(log, fetcher.NewPreimageFetcher(log, l1Client)),
+
+		challengePeriod: challengePeriod,
+		ignoreAfter:     cfg.IgnoreAfter,
+
+		verified: make(map[string]bool),
+
+		proposals: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "proposals",
+			Help:      "number of large preimage proposals by state (uploading, challengeWindow, windowEnded, countered)",
+		}, []string{"state"}),
+		invalidProposals: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "invalidProposals",
+			Help:      "number of large preimage proposals whose recomputation disagrees with the claimed leaves by state",
+		}, []string{"state"}),
+		unchallengedInvalidProposals: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unchallengedInvalidProposals",
+			Help:      "number of invalid large preimage proposals whose challenge window ended without a challenge",
+		}),
+		verifications: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "verifications",
+			Help:      "number of large preimage proposals verified by result (valid, invalid)",
+		}, []string{"result"}),
+		challengePeriodSeconds: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "challengePeriod",
+			Help:      "challenge period of the large preimage proposals in seconds",
+		}),
+		highestBlockNumber: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "l1 height at which the proposals were last read",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}
+	monitor.challengePeriodSeconds.Set(float64(challengePeriod))
+	return monitor, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	header, err := m.l1Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		m.log.Error("failed to query latest header", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "HeaderByNumber").Inc()
+		return
+	}
+
+	proposals, err := m.oracle.GetActivePreimages(ctx, header.Hash())
+	if err != nil {
+		m.log.Error("failed to query the large preimage proposals", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("PreimageOracle", "proposals").Inc()
+		return
+	}
+
+	counts, invalidCounts, unchallenged := make(map[string]int), make(map[string]int), 0
+	for _, proposal := range proposals {
+		if isIgnored(proposal, header.Time, m.ignoreAfter) {
+			m.Update(ctx, alert(UnchallengedRule, "P1", proposal, "proposal ignored"), false)
+			m.Update(ctx, alert(FinalizedRule, "P0", proposal, "proposal ignored"), false)
+			continue
+		}
+		state := proposalState(proposal, header.Time, m.challengePeriod)
+		counts[state]++
+		// The leaves of an incomplete upload can still be added, a countered proposal can't be squeezed.
+		if proposal.Timestamp == 0 || proposal.Countered {
+			m.Update(ctx, alert(UnchallengedRule, "P1", proposal, "proposal countered"), false)
+			continue
+		}
+
+		valid, err := m.verify(ctx, proposal, header)
+		if err != nil {
+			m.log.Error("failed to verify the large preimage proposal", "claimant", proposal.Claimant, "uuid", proposal.UUID, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("PreimageOracle", "verify").Inc()
+			continue
+		}
+		m.Update(ctx, alert(UnchallengedRule, "P1", proposal, fmt.Sprintf("invalid large preimage proposal %s of %d bytes not challenged, the challenge window ending at %d", proposalKey(proposal.LargePreimageIdent), proposal.ClaimedSize, proposal.Timestamp+m.challengePeriod)), !valid && state == "challengeWindow")
+		m.Update(ctx, alert(FinalizedRule, "P0", proposal, fmt.Sprintf("invalid large preimage proposal %s of %d bytes finalized unchallenged", proposalKey(proposal.LargePreimageIdent), proposal.ClaimedSize)), !valid && state == "windowEnded")
+		if valid {
+			continue
+		}
+
+		invalidCounts[state]++
+		switch state {
+		case "challengeWindow":
+			remaining := time.Duration(proposal.Timestamp+m.challengePeriod-header.Time) * time.Second
+			m.log.Warn("invalid large preimage proposal not challenged yet", "claimant", proposal.Claimant, "uuid", proposal.UUID, "claimed_size", proposal.ClaimedSize, "remaining", remaining)
+		case "windowEnded":
+			unchallenged++
+			m.log.Error("invalid large preimage proposal finalized unchallenged!", "claimant", proposal.Claimant, "uuid", proposal.UUID, "claimed_size", proposal.ClaimedSize, "part_offset", proposal.PartOffset)
+		}
+	}
+
+	for _, state := range states {
+		m.proposals.WithLabelValues(state).Set(float64(counts[state]))
+		m.invalidProposals.WithLabelValues(state).Set(float64(invalidCounts[state]))
+	}
+	m.unchallengedInvalidProposals.Set(float64(unchallenged))
+	m.highestBlockNumber.Set(float64(header.Number.Uint64()))
+}
+
+// alert returns the alert of the rule about the proposal.
+func alert(rule string, priority string, proposal keccakTypes.LargePreimageMetaData, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   proposalKey(proposal.LargePreimageIdent),
+		Summary:  summary,
+		Labels:   map[string]string{"claimant": proposal.Claimant.String()},
+	}
+}
+
+// verify recomputes the keccak permutations of a completed upload from the leaves of its transactions,
+// and returns true when the state commitments of the proposal match.
+func (m *Monitor) verify(ctx context.Context, proposal keccakTypes.LargePreimageMetaData, header *types.Header) (bool, error) {
+	key := proposalKey(proposal.LargePreimageIdent)
+	if valid, ok := m.verified[key]; ok {
+		return valid, nil
+	}
+
+	_, err := m.verifier.CreateChallenge(ctx, header.Hash(), m.oracle, proposal)
+	if err != nil && !errors.Is(err, matrix.ErrValid) {
+		return false, err
+	}
+
+	valid := err != nil
+	m.verified[key] = valid
+	m.verifications.WithLabelValues(verificationResult(valid)).Inc()
+	return valid, nil
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	return nil
+}
+
+// proposalState returns the state of a large preimage proposal at the timestamp.
+// The challenge window starts at the end of the upload, a proposal can be squeezed once it ended.
+func proposalState(proposal keccakTypes.LargePreimageMetaData, now uint64, challengePeriod uint64) string {
+	switch {
+	case proposal.Countered:
+		return "countered"
+	case proposal.Timestamp == 0:
+		return "uploading"
+	case now <= proposal.Timestamp+challengePeriod:
+		return "challengeWindow"
+	default:
+		return "windowEnded"
+	}
+}
+
+// isIgnored returns true when the upload of the proposal ended more than `ignoreAfter` before the timestamp.
+func isIgnored(proposal keccakTypes.LargePreimageMetaData, now uint64, ignoreAfter time.Duration) bool {
+	return proposal.Timestamp > 0 && proposal.Timestamp+uint64(ignoreAfter.Seconds()) < now
+}
+
+// proposalKey identifies a large preimage proposal by its claimant and its uuid.
+func proposalKey(ident keccakTypes.LargePreimageIdent) string {
+	return fmt.Sprintf("%s-%s", ident.Claimant, ident.UUID)
+}
+
+func verificationResult(valid bool) string {
+	if valid {
+		return "valid"
+	}
+	return "invalid"
+}
@@ -0,0 +1,218 @@
+package preimages
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/keccak/matrix"
+	keccakTypes "github.com/ethereum-optimism/optimism/op-challenger/game/keccak/types"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const challengePeriod = 24 * 60 * 60
+
+func TestProposalState(t *testing.T) {
+	tests := []struct {
+		name     string
+		proposal keccakTypes.LargePreimageMetaData
+		now      uint64
+		expected string
+	}{
+		{name: "uploading", proposal: keccakTypes.LargePreimageMetaData{}, now: 1000, expected: "uploading"},
+		{name: "upload completed", proposal: keccakTypes.LargePreimageMetaData{Timestamp: 1000}, now: 1000, expected: "challengeWindow"},
+		{name: "end of the window", proposal: keccakTypes.LargePreimageMetaData{Timestamp: 1000}, now: 1000 + challengePeriod, expected: "challengeWindow"},
+		{name: "window ended", proposal: keccakTypes.LargePreimageMetaData{Timestamp: 1000}, now: 1001 + challengePeriod, expected: "windowEnded"},
+		{name: "countered", proposal: keccakTypes.LargePreimageMetaData{Timestamp: 1000, Countered: true}, now: 1000, expected: "countered"},
+		{name: "countered while uploading", proposal: keccakTypes.LargePreimageMetaData{Countered: true}, now: 1000, expected: "countered"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := proposalState(test.proposal, test.now, challengePeriod); got != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsIgnored(t *testing.T) {
+	ignoreAfter := 14 * 24 * time.Hour
+	tests := []struct {
+		name      string
+		timestamp uint64
+		now       uint64
+		expected  bool
+	}{
+		{name: "uploading", timestamp: 0, now: 1 << 40, expected: false},
+		{name: "recent", timestamp: 1000, now: 1000 + challengePeriod, expected: false},
+		{name: "limit", timestamp: 1000, now: 1000 + uint64(ignoreAfter.Seconds()), expected: false},
+		{name: "old", timestamp: 1000, now: 1001 + uint64(ignoreAfter.Seconds()), expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isIgnored(keccakTypes.LargePreimageMetaData{Timestamp: test.timestamp}, test.now, ignoreAfter); got != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+var (
+	oracle    = common.HexToAddress("0x9c065e11870B891D214Bc2Da7EF1f9DDFA1BE277")
+	oracleABI = util.MustParseABI(bindings.PreimageOracleMetaData)
+)
+
+// oracleNode serves a PreimageOracle whose large preimage proposals are uploaded by the claimant, each in its own block.
+type oracleNode struct {
+	*fake.Node
+
+	key       *ecdsa.PrivateKey
+	claimant  common.Address
+	proposals []*big.Int
+	blocks    map[string]uint64
+	metadata  map[string][32]byte
+}
+
+func newOracleNode(t *testing.T) *oracleNode {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	n := &oracleNode{Node: fake.NewNode(t), key: key, claimant: crypto.PubkeyToAddress(key.PublicKey), blocks: make(map[string]uint64), metadata: make(map[string][32]byte)}
+	n.AddBlock(&types.Header{Time: 1000})
+	n.Contract(oracle, oracleABI).
+		Returns("challengePeriod", big.NewInt(challengePeriod)).
+		Handle("proposalCount", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{big.NewInt(int64(len(n.proposals)))}, nil
+		}).
+		Handle("proposals", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{n.claimant, n.proposals[args[0].(*big.Int).Int64()]}, nil
+		}).
+		Handle("proposalMetadata", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{n.metadata[args[1].(*big.Int).String()]}, nil
+		}).
+		Handle("getTreeRootLPP", func(args []interface{}) ([]interface{}, error) {
+			// the verifier caches the valid proposals by root.
+			return []interface{}{common.BigToHash(args[1].(*big.Int))}, nil
+		}).
+		Returns("proposalBlocksLen", big.NewInt(1)).
+		Handle("proposalBlocks", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{n.blocks[args[1].(*big.Int).String()]}, nil
+		})
+	return n
+}
+
+// propose adds a block uploading the whole input of the proposal, completed at the timestamp 1000. The state commitments
+// are tampered with unless valid.
+func (n *oracleNode) propose(t *testing.T, uuid int64, valid bool) {
+	input, err := matrix.NewStateMatrix().AbsorbUpTo(bytes.NewReader(bytes.Repeat([]byte{0x01}, 200)), 10*keccakTypes.BlockSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		t.Fatalf("error: %v", err)
+	}
+	if !valid {
+		input.Commitments[0] = common.HexToHash("0xbad")
+	}
+	data, err := oracleABI.Pack("addLeavesLPP", big.NewInt(uuid), big.NewInt(0), input.Input, input.Commitments, true)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	tx := types.MustSignNewTx(n.key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{ChainID: big.NewInt(1), To: &oracle, Gas: 1_000_000, GasFeeCap: big.NewInt(1e9), Data: data})
+	header := n.AddBlock(&types.Header{Time: 1000}, tx)
+	n.AddReceipt(&types.Receipt{TxHash: tx.Hash(), Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{}, BlockHash: header.Hash(), BlockNumber: header.Number})
+
+	var metadata [32]byte
+	binary.BigEndian.PutUint64(metadata[0:8], 1000)
+	binary.BigEndian.PutUint32(metadata[12:16], uint32(len(input.Input)))
+	n.proposals = append(n.proposals, big.NewInt(uuid))
+	n.blocks[big.NewInt(uuid).String()] = header.Number.Uint64()
+	n.metadata[big.NewInt(uuid).String()] = metadata
+}
+
+// newTestMonitor returns the monitor of the proposals of the node, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *oracleNode) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{L1NodeURL: node.URL, PreimageOracleAddress: oracle, IgnoreAfter: 14 * 24 * time.Hour}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := newOracleNode(t)
+	node.propose(t, 1, true)
+	node.propose(t, 2, false)
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// both proposals are verified once, within their challenge window.
+	m.Run(ctx)
+	m.Run(ctx)
+	if valid, invalid := testutil.ToFloat64(m.verifications.WithLabelValues("valid")), testutil.ToFloat64(m.verifications.WithLabelValues("invalid")); valid != 1 || invalid != 1 {
+		t.Errorf("expected 1 valid and 1 invalid verification but got %v and %v", valid, invalid)
+	}
+	if proposals, invalid := testutil.ToFloat64(m.proposals.WithLabelValues("challengeWindow")), testutil.ToFloat64(m.invalidProposals.WithLabelValues("challengeWindow")); proposals != 2 || invalid != 1 {
+		t.Errorf("expected 2 proposals within the window, 1 invalid, but got %v and %v", proposals, invalid)
+	}
+	if period, block := testutil.ToFloat64(m.challengePeriodSeconds), testutil.ToFloat64(m.highestBlockNumber); period != challengePeriod || block != 2 {
+		t.Errorf("expected the challenge period at the block 2 but got %v and %v", period, block)
+	}
+
+	// the window ending moves the proposals and reports the invalid one unchallenged.
+	node.AddBlock(&types.Header{Time: 1001 + challengePeriod})
+	m.Run(ctx)
+	if window, ended := testutil.ToFloat64(m.proposals.WithLabelValues("challengeWindow")), testutil.ToFloat64(m.proposals.WithLabelValues("windowEnded")); window != 0 || ended != 2 {
+		t.Errorf("expected the 2 proposals with their window ended but got %v and %v", window, ended)
+	}
+	if unchallenged := testutil.ToFloat64(m.unchallengedInvalidProposals); unchallenged != 1 {
+		t.Errorf("expected 1 unchallenged invalid proposal but got %v", unchallenged)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newOracleNode(t)
+	node.propose(t, 1, true)
+	node.propose(t, 2, false)
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// the invalid proposal within its challenge window fires once.
+	m.Run(ctx)
+	m.Run(ctx)
+	invalid := proposalKey(keccakTypes.LargePreimageIdent{Claimant: node.claimant, UUID: big.NewInt(2)})
+	if alerts := notifier.Alerts(UnchallengedRule); len(alerts) != 1 || alerts[0].Entity != invalid || alerts[0].Priority != "P1" || alerts[0].Labels["claimant"] != node.claimant.String() {
+		t.Fatalf("expected the unchallenged alert of the invalid proposal once but got %v", alerts)
+	}
+
+	// the window ending unchallenged resolves the alert and fires the finalized one.
+	node.AddBlock(&types.Header{Time: 1001 + challengePeriod})
+	m.Run(ctx)
+	if firing := notifier.Firing(UnchallengedRule); len(firing) != 0 {
+		t.Fatalf("expected the unchallenged alert resolved but got %v", firing)
+	}
+	if alerts := notifier.Alerts(FinalizedRule); len(alerts) != 1 || alerts[0].Entity != invalid || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the finalized alert of the invalid proposal but got %v", alerts)
+	}
+}
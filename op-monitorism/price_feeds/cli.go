@@ -0,0 +1,66 @@
+package price_feeds
+
+import (
+	"fmt"
+	"time"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodeURLFlagName     = "node.url"
+	FeedsConfigFlagName = "feeds.config"
+	GracePeriodFlagName = "grace.period"
+)
+
+type CLIConfig struct {
+	NodeURL string
+
+	Feeds []FeedConfig
+
+	// GracePeriod is the duration after the heartbeat of a feed before it is reported as stale.
+	GracePeriod time.Duration
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		NodeURL:     ctx.String(NodeURLFlagName),
+		GracePeriod: ctx.Duration(GracePeriodFlagName),
+	}
+
+	config, err := ReadConfigFile(ctx.String(FeedsConfigFlagName))
+	if err != nil {
+		return cfg, err
+	}
+	if len(config.Feeds) == 0 {
+		return cfg, fmt.Errorf("--%s: no feed to monitor", FeedsConfigFlagName)
+	}
+	cfg.Feeds = config.Feeds
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    NodeURLFlagName,
+			Usage:   "Node URL of the chain of the feeds",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     FeedsConfigFlagName,
+			Usage:    "YAML file with the price feeds, their heartbeat and the band of their expected answers",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "FEEDS_CONFIG"),
+			Required: true,
+		},
+		&cli.DurationFlag{
+			Name:    GracePeriodFlagName,
+			Usage:   "Duration after the heartbeat of a feed before it is reported as stale",
+			Value:   5 * time.Minute,
+			EnvVars: opservice.PrefixEnvVar(envVar, "GRACE_PERIOD"),
+		},
+	}
+}
@@ -0,0 +1,73 @@
+package price_feeds
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// FeedConfig is a Chainlink-style price feed along with its heartbeat and the band of its expected answers.
+type FeedConfig struct {
+	Name    string         `yaml:"name"`
+	Address common.Address `yaml:"address"`
+
+	// Heartbeat is the max duration between two updates of the feed, as documented by the feed.
+	Heartbeat time.Duration `yaml:"heartbeat"`
+
+	// Min and Max are the inclusive bounds of the answer in the unit of the feed (e.g. USD), not checked when nil.
+	Min *float64 `yaml:"min"`
+	Max *float64 `yaml:"max"`
+}
+
+// FeedsConfiguration is the content of the YAML file given with `--feeds.config`.
+//
+//	feeds:
+//	  - name: ETH / USD
+//	    address: 0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419
+//	    heartbeat: 1h
+//	    min: 500
+//	    max: 20000
+type FeedsConfiguration struct {
+	Feeds []FeedConfig `yaml:"feeds"`
+}
+
+// ReadConfigFile reads the price feeds from a YAML file.
+func ReadConfigFile(path string) (FeedsConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return FeedsConfiguration{}, fmt.Errorf("failed to read the feeds config file %s: %w", path, err)
+	}
+
+	var config FeedsConfiguration
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return FeedsConfiguration{}, fmt.Errorf("failed to parse the feeds config file %s: %w", path, err)
+	}
+	if err := config.check(); err != nil {
+		return FeedsConfiguration{}, fmt.Errorf("invalid feeds config file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// check ensures the feeds are uniquely named, with a heartbeat and a non-empty band.
+func (c FeedsConfiguration) check() error {
+	names := make(map[string]bool)
+	for _, feed := range c.Feeds {
+		if len(feed.Name) == 0 {
+			return fmt.Errorf("the feed %s has no name", feed.Address)
+		}
+		if names[feed.Name] {
+			return fmt.Errorf("duplicated feed %s", feed.Name)
+		}
+		names[feed.Name] = true
+		if feed.Heartbeat <= 0 {
+			return fmt.Errorf("the feed %s has no heartbeat", feed.Name)
+		}
+		if feed.Min != nil && feed.Max != nil && *feed.Min > *feed.Max {
+			return fmt.Errorf("the min %f of the feed %s is above its max %f", *feed.Min, feed.Name, *feed.Max)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package price_feeds
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testConfig = `
+feeds:
+  - name: ETH / USD
+    address: 0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419
+    heartbeat: 1h
+    min: 500
+    max: 20000
+  - name: OP / USD
+    address: 0x0000000000000000000000000000000000000001
+    heartbeat: 24h
+`
+
+func TestFeedsConfiguration(t *testing.T) {
+	var config FeedsConfiguration
+	if err := yaml.Unmarshal([]byte(testConfig), &config); err != nil {
+		t.Fatalf("failed to parse the config: %v", err)
+	}
+	if err := config.check(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	if len(config.Feeds) != 2 {
+		t.Fatalf("expected 2 feeds but got %d", len(config.Feeds))
+	}
+
+	eth, op := config.Feeds[0], config.Feeds[1]
+	if eth.Heartbeat != time.Hour || eth.Min == nil || *eth.Min != 500 || eth.Max == nil || *eth.Max != 20000 {
+		t.Errorf("unexpected feed %+v", eth)
+	}
+	if op.Heartbeat != 24*time.Hour || op.Min != nil || op.Max != nil {
+		t.Errorf("unexpected feed %+v", op)
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	low, high := 1.0, 2.0
+
+	tests := []struct {
+		name  string
+		feeds []FeedConfig
+		valid bool
+	}{
+		{name: "valid feed", feeds: []FeedConfig{{Name: "a", Heartbeat: time.Hour, Min: &low, Max: &high}}, valid: true},
+		{name: "unnamed feed", feeds: []FeedConfig{{Heartbeat: time.Hour}}},
+		{name: "duplicated feed", feeds: []FeedConfig{{Name: "a", Heartbeat: time.Hour}, {Name: "a", Heartbeat: time.Hour}}},
+		{name: "no heartbeat", feeds: []FeedConfig{{Name: "a"}}},
+		{name: "inverted band", feeds: []FeedConfig{{Name: "a", Heartbeat: time.Hour, Min: &high, Max: &low}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := (FeedsConfiguration{Feeds: test.feeds}).check(); (err == nil) != test.valid {
+				t.Errorf("Failed %s: expected valid %t but got %v", test.name, test.valid, err)
+			}
+		})
+	}
+}
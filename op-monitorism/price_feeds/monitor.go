@@ -0,0 +1,258 @@
+package price_feeds
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "price_feeds"
+	MetricsNamespace = "price_feeds_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the feed.
+	StaleRule      = "price feed stale"
+	IncompleteRule = "price feed answered from a previous round"
+	InvalidRule    = "price feed answer not positive"
+	OutOfBandRule  = "price feed answer out of band"
+
+	// AggregatorV3ABI is the subset of the Chainlink AggregatorV3Interface used by the monitor.
+	AggregatorV3ABI = `[{"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"latestRoundData","outputs":[{"name":"roundId","type":"uint80"},{"name":"answer","type":"int256"},{"name":"startedAt","type":"uint256"},{"name":"updatedAt","type":"uint256"},{"name":"answeredInRound","type":"uint80"}],"stateMutability":"view","type":"function"}]`
+)
+
+var (
+	aggregatorV3ABI = util.MustParseJSONABI(AggregatorV3ABI)
+)
+
+// round is the latest round of a feed.
+type round struct {
+	RoundId         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}
+
+// roundCheck is the result of the checks of the latest round of a feed.
+type roundCheck struct {
+	answer float64
+	age    time.Duration
+
+	stale      bool
+	incomplete bool
+	invalid    bool
+	outOfBand  bool
+}
+
+// feed is a price feed along with its decimals and the round observed at the previous iteration.
+type feed struct {
+	FeedConfig
+	contract *bind.BoundContract
+
+	// decimals is `nil` until read, the decimals of a feed are immutable.
+	decimals  *uint8
+	lastRound *big.Int
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	client *ethclient.Client
+
+	feeds       []*feed
+	gracePeriod time.Duration
+
+	// metrics
+	answer              *prometheus.GaugeVec
+	age                 *prometheus.GaugeVec
+	stale               *prometheus.GaugeVec
+	incompleteRound     *prometheus.GaugeVec
+	invalidAnswer       *prometheus.GaugeVec
+	outOfBand           *prometheus.GaugeVec
+	staleFeeds          prometheus.Gauge
+	updates             *prometheus.CounterVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating price feeds monitor...")
+
+	client, err := ethclient.Dial(cfg.NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial node: %w", err)
+	}
+
+	feeds := make([]*feed, len(cfg.Feeds))
+	for i, f := range cfg.Feeds {
+		feeds[i] = &feed{FeedConfig: f, contract: bind.NewBoundContract(f.Address, *aggregatorV3ABI, client, nil, nil)}
+		log.Info("configured feed", "name", f.Name, "address", f.Address, "heartbeat", f.Heartbeat, "min", f.Min, "max", f.Max)
+	}
+
+	return &Monitor{
+		log: log,
+
+		client: client,
+
+		feeds:       feeds,
+		gracePeriod: cfg.GracePeriod,
+
+		answer: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "answer",
+			Help:      "latest answer of the feed, in the unit of the feed",
+		}, []string{"feed"}),
+		age: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "age",
+			Help:      "seconds since the latest update of the feed",
+		}, []string{"feed"}),
+		stale: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "stale",
+			Help:      "1 if the feed was not updated within its heartbeat and the grace period, 0 otherwise",
+		}, []string{"feed"}),
+		incompleteRound: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "incompleteRound",
+			Help:      "1 if the answer of the latest round was carried over from a previous round, 0 otherwise",
+		}, []string{"feed"}),
+		invalidAnswer: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "invalidAnswer",
+			Help:      "1 if the latest answer of the feed is not positive, 0 otherwise",
+		}, []string{"feed"}),
+		outOfBand: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "outOfBand",
+			Help:      "1 if the latest answer of the feed is out of its expected band, 0 otherwise",
+		}, []string{"feed"}),
+		staleFeeds: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "staleFeeds",
+			Help:      "number of stale feeds",
+		}),
+		updates: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "updates",
+			Help:      "number of new rounds of the feed observed",
+		}, []string{"feed"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	callOpts := &bind.CallOpts{Context: ctx}
+	now := time.Now()
+
+	stale := 0
+	for _, f := range m.feeds {
+		if f.decimals == nil {
+			var out []interface{}
+			if err := f.contract.Call(callOpts, &out, "decimals"); err != nil {
+				m.log.Error("failed to query the decimals of the feed", "feed", f.Name, "err", err)
+				m.unexpectedRpcErrors.WithLabelValues(f.Name, "decimals").Inc()
+				continue
+			}
+			decimals := *abi.ConvertType(out[0], new(uint8)).(*uint8)
+			f.decimals = &decimals
+		}
+
+		var out []interface{}
+		if err := f.contract.Call(callOpts, &out, "latestRoundData"); err != nil {
+			m.log.Error("failed to query the latest round of the feed", "feed", f.Name, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues(f.Name, "latestRoundData").Inc()
+			continue
+		}
+		var r round
+		if err := aggregatorV3ABI.Methods["latestRoundData"].Outputs.Copy(&r, out); err != nil {
+			m.log.Error("failed to decode the latest round of the feed", "feed", f.Name, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues(f.Name, "latestRoundData").Inc()
+			continue
+		}
+
+		if f.lastRound != nil && r.RoundId.Cmp(f.lastRound) != 0 {
+			m.updates.WithLabelValues(f.Name).Inc()
+		}
+		f.lastRound = r.RoundId
+
+		check := checkRound(r, *f.decimals, now, f.FeedConfig, m.gracePeriod)
+		if check.stale {
+			stale++
+			m.log.Error("price feed is stale", "feed", f.Name, "age", check.age, "heartbeat", f.Heartbeat, "round", r.RoundId)
+		}
+		if check.incomplete {
+			m.log.Error("price feed answered from a previous round", "feed", f.Name, "round", r.RoundId, "answered_in_round", r.AnsweredInRound)
+		}
+		if check.invalid {
+			m.log.Error("price feed answer is not positive", "feed", f.Name, "answer", r.Answer, "round", r.RoundId)
+		}
+		if check.outOfBand {
+			m.log.Error("price feed answer is out of band", "feed", f.Name, "answer", check.answer, "min", f.Min, "max", f.Max, "round", r.RoundId)
+		}
+
+		m.answer.WithLabelValues(f.Name).Set(check.answer)
+		m.age.WithLabelValues(f.Name).Set(check.age.Seconds())
+		m.stale.WithLabelValues(f.Name).Set(util.BoolToFloat(check.stale))
+		m.incompleteRound.WithLabelValues(f.Name).Set(util.BoolToFloat(check.incomplete))
+		m.invalidAnswer.WithLabelValues(f.Name).Set(util.BoolToFloat(check.invalid))
+		m.outOfBand.WithLabelValues(f.Name).Set(util.BoolToFloat(check.outOfBand))
+		m.Update(ctx, alert(StaleRule, "P1", f, fmt.Sprintf("%s not updated for %s, its heartbeat being %s", f.Name, check.age.Truncate(time.Second), f.Heartbeat)), check.stale)
+		m.Update(ctx, alert(IncompleteRule, "P1", f, fmt.Sprintf("%s round %s answered in the previous round %s", f.Name, r.RoundId, r.AnsweredInRound)), check.incomplete)
+		m.Update(ctx, alert(InvalidRule, "P0", f, fmt.Sprintf("%s answer %s is not positive", f.Name, r.Answer)), check.invalid)
+		m.Update(ctx, alert(OutOfBandRule, "P1", f, fmt.Sprintf("%s answer %g is out of band", f.Name, check.answer)), check.outOfBand)
+	}
+	m.staleFeeds.Set(float64(stale))
+}
+
+// alert returns the alert of the rule about the feed.
+func alert(rule string, priority string, f *feed, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   f.Address.String(),
+		Summary:  summary,
+		Labels:   map[string]string{"feed": f.Name},
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.client.Close()
+	return nil
+}
+
+// checkRound checks the latest round of the feed: stale when not updated within its heartbeat and the grace period, incomplete when
+// the answer was carried over from a previous round, invalid when not positive and out of band when outside of the expected band.
+func checkRound(r round, decimals uint8, now time.Time, cfg FeedConfig, gracePeriod time.Duration) roundCheck {
+	answer, _ := new(big.Rat).SetFrac(r.Answer, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)).Float64()
+	age := now.Sub(time.Unix(r.UpdatedAt.Int64(), 0))
+
+	return roundCheck{
+		answer: answer,
+		age:    age,
+
+		stale:      r.UpdatedAt.Sign() == 0 || age > cfg.Heartbeat+gracePeriod,
+		incomplete: r.AnsweredInRound.Cmp(r.RoundId) < 0,
+		invalid:    r.Answer.Sign() <= 0,
+		outOfBand:  (cfg.Min != nil && answer < *cfg.Min) || (cfg.Max != nil && answer > *cfg.Max),
+	}
+}
@@ -0,0 +1,144 @@
+package price_feeds
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var ethUsd = common.HexToAddress("0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419")
+
+func TestCheckRound(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	low, high := 500.0, 20000.0
+	cfg := FeedConfig{Heartbeat: time.Hour, Min: &low, Max: &high}
+	gracePeriod := 5 * time.Minute
+
+	newRound := func(answer int64, updatedAgo time.Duration, roundId int64, answeredInRound int64) round {
+		return round{
+			RoundId:         big.NewInt(roundId),
+			Answer:          new(big.Int).Mul(big.NewInt(answer), big.NewInt(100_000_000)),
+			StartedAt:       big.NewInt(now.Add(-updatedAgo).Unix()),
+			UpdatedAt:       big.NewInt(now.Add(-updatedAgo).Unix()),
+			AnsweredInRound: big.NewInt(answeredInRound),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		round    round
+		expected roundCheck
+	}{
+		{name: "fresh answer", round: newRound(2000, time.Minute, 2, 2), expected: roundCheck{answer: 2000, age: time.Minute}},
+		{name: "within the grace period", round: newRound(2000, time.Hour+time.Minute, 2, 2), expected: roundCheck{answer: 2000, age: time.Hour + time.Minute}},
+		{name: "stale answer", round: newRound(2000, 2*time.Hour, 2, 2), expected: roundCheck{answer: 2000, age: 2 * time.Hour, stale: true}},
+		{name: "carried over answer", round: newRound(2000, time.Minute, 2, 1), expected: roundCheck{answer: 2000, age: time.Minute, incomplete: true}},
+		{name: "above the band", round: newRound(30000, time.Minute, 2, 2), expected: roundCheck{answer: 30000, age: time.Minute, outOfBand: true}},
+		{name: "negative answer", round: newRound(-1, time.Minute, 2, 2), expected: roundCheck{answer: -1, age: time.Minute, invalid: true, outOfBand: true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := checkRound(test.round, 8, now, cfg, gracePeriod); got != test.expected {
+				t.Errorf("Failed %s: expected %+v but got %+v", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+// newTestMonitor returns the monitor of an ETH / USD feed with a band of 500 to 20000, the feed contract and the notifier
+// of its alerts.
+func newTestMonitor(t *testing.T) (*Monitor, *fake.Contract, *fake.Notifier) {
+	node := fake.NewNode(t)
+	feed := node.Contract(ethUsd, aggregatorV3ABI).Returns("decimals", uint8(8))
+
+	low, high := 500.0, 20000.0
+	cfg := CLIConfig{NodeURL: node.URL, Feeds: []FeedConfig{{Name: "ETH / USD", Address: ethUsd, Heartbeat: time.Hour, Min: &low, Max: &high}}}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, feed, notifier
+}
+
+// latestRound serves the round of the feed, answered in the round and updated ago.
+func latestRound(feed *fake.Contract, roundId int64, answer int64, updatedAgo time.Duration) {
+	updatedAt := big.NewInt(time.Now().Add(-updatedAgo).Unix())
+	feed.Returns("latestRoundData", big.NewInt(roundId), new(big.Int).Mul(big.NewInt(answer), big.NewInt(100_000_000)), updatedAt, updatedAt, big.NewInt(roundId))
+}
+
+func TestRun(t *testing.T) {
+	m, feed, _ := newTestMonitor(t)
+	ctx := context.Background()
+	name := "ETH / USD"
+
+	latestRound(feed, 1, 3000, time.Minute)
+	m.Run(ctx)
+	if answer, age, updates := testutil.ToFloat64(m.answer.WithLabelValues(name)), testutil.ToFloat64(m.age.WithLabelValues(name)), testutil.ToFloat64(m.updates.WithLabelValues(name)); answer != 3000 || age < 60 || updates != 0 {
+		t.Errorf("expected the answer 3000 a minute old without update but got %v, %v and %v", answer, age, updates)
+	}
+
+	// a feed not updated within its heartbeat is reported stale.
+	latestRound(feed, 1, 3000, 2*time.Hour)
+	m.Run(ctx)
+	if stale, staleFeeds := testutil.ToFloat64(m.stale.WithLabelValues(name)), testutil.ToFloat64(m.staleFeeds); stale != 1 || staleFeeds != 1 {
+		t.Errorf("expected the feed stale but got %v and %v", stale, staleFeeds)
+	}
+
+	// a new round is counted as an update, its negative answer being invalid and out of band.
+	latestRound(feed, 2, -1, time.Minute)
+	m.Run(ctx)
+	if updates, staleFeeds := testutil.ToFloat64(m.updates.WithLabelValues(name)), testutil.ToFloat64(m.staleFeeds); updates != 1 || staleFeeds != 0 {
+		t.Errorf("expected 1 update of the feed no longer stale but got %v and %v", updates, staleFeeds)
+	}
+	if invalid, outOfBand, incomplete := testutil.ToFloat64(m.invalidAnswer.WithLabelValues(name)), testutil.ToFloat64(m.outOfBand.WithLabelValues(name)), testutil.ToFloat64(m.incompleteRound.WithLabelValues(name)); invalid != 1 || outOfBand != 1 || incomplete != 0 {
+		t.Errorf("expected the answer invalid and out of band but got %v, %v and %v", invalid, outOfBand, incomplete)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	m, feed, notifier := newTestMonitor(t)
+	ctx := context.Background()
+
+	latestRound(feed, 1, 3000, time.Minute)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a feed not updated within its heartbeat fires once, resolved by a new round.
+	latestRound(feed, 1, 3000, 2*time.Hour)
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(StaleRule); len(alerts) != 1 || alerts[0].Entity != ethUsd.String() || alerts[0].Priority != "P1" || alerts[0].Labels["feed"] != "ETH / USD" {
+		t.Fatalf("expected the stale alert once but got %v", alerts)
+	}
+	latestRound(feed, 2, 3000, time.Minute)
+	m.Run(ctx)
+	if firing := notifier.Firing(StaleRule); len(firing) != 0 {
+		t.Fatalf("expected the stale alert resolved but got %v", firing)
+	}
+
+	// a negative answer is both invalid and out of band.
+	latestRound(feed, 3, -1, time.Minute)
+	m.Run(ctx)
+	if len(notifier.Firing(InvalidRule)) != 1 || len(notifier.Firing(OutOfBandRule)) != 1 || len(notifier.Firing(IncompleteRule)) != 0 {
+		t.Fatalf("expected the invalid and out of band alerts but got %v", notifier.Alerts(""))
+	}
+	if alerts := notifier.Alerts(InvalidRule); alerts[0].Priority != "P0" {
+		t.Fatalf("expected the invalid alert with P0 but got %v", alerts)
+	}
+}
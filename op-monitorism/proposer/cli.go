@@ -0,0 +1,127 @@
+package proposer
+
+import (
+	"fmt"
+	"time"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+
+	ProposerAddressFlagName           = "proposer.address"
+	L2OutputOracleAddressFlagName     = "l2outputoracle.address"
+	DisputeGameFactoryAddressFlagName = "disputegamefactory.address"
+	GameTypeFlagName                  = "game.type"
+	ProposalIntervalFlagName          = "proposal.interval"
+	EventBlockRangeFlagName           = "event.block.range"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+
+	ProposerAddress common.Address
+
+	// Exactly one of the L2OutputOracle or the DisputeGameFactory is set.
+	L2OutputOracleAddress     *common.Address
+	DisputeGameFactoryAddress *common.Address
+	GameType                  uint32
+
+	ProposalInterval time.Duration
+	EventBlockRange  uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:        ctx.String(L1NodeURLFlagName),
+		GameType:         uint32(ctx.Uint(GameTypeFlagName)),
+		ProposalInterval: ctx.Duration(ProposalIntervalFlagName),
+		EventBlockRange:  ctx.Uint64(EventBlockRangeFlagName),
+	}
+
+	proposerAddress := ctx.String(ProposerAddressFlagName)
+	if !common.IsHexAddress(proposerAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", ProposerAddressFlagName)
+	}
+	cfg.ProposerAddress = common.HexToAddress(proposerAddress)
+
+	l2ooAddress := ctx.String(L2OutputOracleAddressFlagName)
+	if len(l2ooAddress) > 0 {
+		if !common.IsHexAddress(l2ooAddress) {
+			return cfg, fmt.Errorf("--%s is not a hex-encoded address", L2OutputOracleAddressFlagName)
+		}
+		addr := common.HexToAddress(l2ooAddress)
+		cfg.L2OutputOracleAddress = &addr
+	}
+
+	factoryAddress := ctx.String(DisputeGameFactoryAddressFlagName)
+	if len(factoryAddress) > 0 {
+		if !common.IsHexAddress(factoryAddress) {
+			return cfg, fmt.Errorf("--%s is not a hex-encoded address", DisputeGameFactoryAddressFlagName)
+		}
+		addr := common.HexToAddress(factoryAddress)
+		cfg.DisputeGameFactoryAddress = &addr
+	}
+
+	if (cfg.L2OutputOracleAddress == nil) == (cfg.DisputeGameFactoryAddress == nil) {
+		return cfg, fmt.Errorf("exactly one of --%s or --%s must be set", L2OutputOracleAddressFlagName, DisputeGameFactoryAddressFlagName)
+	}
+	if cfg.ProposalInterval <= 0 {
+		return cfg, fmt.Errorf("--%s must be positive", ProposalIntervalFlagName)
+	}
+	if cfg.EventBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", EventBlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     ProposerAddressFlagName,
+			Usage:    "Address of the proposer",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "PROPOSER"),
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:    L2OutputOracleAddressFlagName,
+			Usage:   "Address of the L2OutputOracle contract, for the chains proposing outputs to the L2OutputOracle",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_OUTPUT_ORACLE"),
+		},
+		&cli.StringFlag{
+			Name:    DisputeGameFactoryAddressFlagName,
+			Usage:   "Address of the DisputeGameFactory contract, for the chains proposing outputs through dispute games",
+			EnvVars: opservice.PrefixEnvVar(envVar, "DISPUTE_GAME_FACTORY"),
+		},
+		&cli.UintFlag{
+			Name:    GameTypeFlagName,
+			Usage:   "Type of the dispute games created by the proposer",
+			Value:   0,
+			EnvVars: opservice.PrefixEnvVar(envVar, "GAME_TYPE"),
+		},
+		&cli.DurationFlag{
+			Name:    ProposalIntervalFlagName,
+			Usage:   "Maximum expected duration between two proposals",
+			Value:   time.Hour,
+			EnvVars: opservice.PrefixEnvVar(envVar, "PROPOSAL_INTERVAL"),
+		},
+		&cli.Uint64Flag{
+			Name:    EventBlockRangeFlagName,
+			Usage:   "Max block range when scanning for the proposal events",
+			Value:   1000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EVENT_BLOCK_RANGE"),
+		},
+	}
+}
@@ -0,0 +1,308 @@
+package proposer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "proposer"
+	MetricsNamespace = "proposer_mon"
+
+	// ExceededRule is the rule of the alert fired when the proposal interval is exceeded, the entity being the proposer.
+	ExceededRule = "proposal interval exceeded"
+)
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+
+	proposer         common.Address
+	proposalInterval uint64
+	eventBlockRange  uint64
+
+	// filter of the proposal events, `OutputProposed` on the L2OutputOracle or `DisputeGameCreated` on the DisputeGameFactory.
+	source    common.Address
+	topics    [][]common.Hash
+	eventName string
+
+	nextBlock    uint64
+	lastProposal uint64 // timestamp of the last proposal, 0 when unknown.
+
+	// metrics
+	lastProposalTimestamp    prometheus.Gauge
+	secondsSinceLastProposal prometheus.Gauge
+	proposalIntervalExceeded prometheus.Gauge
+	proposals                prometheus.Counter
+	lastProposalGasCost      prometheus.Gauge
+	proposalsGasCost         prometheus.Counter
+	balance                  prometheus.Gauge
+	unexpectedRpcErrors      *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating proposer monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+
+	monitor := &Monitor{
+		log:      log,
+		l1Client: l1Client,
+
+		proposer:         cfg.ProposerAddress,
+		proposalInterval: uint64(cfg.ProposalInterval.Seconds()),
+		eventBlockRange:  cfg.EventBlockRange,
+
+		lastProposalTimestamp: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "lastProposalTimestamp",
+			Help:      "Timestamp of the last proposal",
+		}),
+		secondsSinceLastProposal: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "secondsSinceLastProposal",
+			Help:      "Seconds elapsed since the last proposal at the latest L1 block",
+		}),
+		proposalIntervalExceeded: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "proposalIntervalExceeded",
+			Help:      "1 if the time since the last proposal exceeds the proposal interval, 0 otherwise",
+		}),
+		proposals: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "proposals",
+			Help:      "Number of proposals made by the proposer since the start of the monitor",
+		}),
+		lastProposalGasCost: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "lastProposalGasCost",
+			Help:      "Cost in ETH of the transaction of the last proposal",
+		}),
+		proposalsGasCost: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "proposalsGasCost",
+			Help:      "Cost in ETH of the transactions of the proposals since the start of the monitor",
+		}),
+		balance: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "balance",
+			Help:      "ETH balance of the proposer",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}
+
+	callOpts := &bind.CallOpts{Context: ctx}
+	if cfg.L2OutputOracleAddress != nil {
+		if err := monitor.configureL2OutputOracle(callOpts, *cfg.L2OutputOracleAddress); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := monitor.configureDisputeGameFactory(callOpts, *cfg.DisputeGameFactoryAddress, cfg.GameType); err != nil {
+			return nil, err
+		}
+	}
+
+	latestL1Height, err := l1Client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest block number: %w", err)
+	}
+	monitor.nextBlock = latestL1Height
+
+	log.Info("configured proposer", "address", cfg.ProposerAddress, "source", monitor.source, "event", monitor.eventName, "last_proposal", monitor.lastProposal, "start_block", latestL1Height)
+	return monitor, nil
+}
+
+// configureL2OutputOracle watches the `OutputProposed` events, the last proposal is the latest output.
+func (m *Monitor) configureL2OutputOracle(callOpts *bind.CallOpts, address common.Address) error {
+	l2ooABI, err := bindings.L2OutputOracleMetaData.GetAbi()
+	if err != nil {
+		return fmt.Errorf("failed to parse the L2OutputOracle ABI: %w", err)
+	}
+	l2oo, err := bindings.NewL2OutputOracleCaller(address, m.l1Client)
+	if err != nil {
+		return fmt.Errorf("failed to bind to the L2OutputOracle: %w", err)
+	}
+
+	m.source, m.eventName = address, "OutputProposed"
+	m.topics = [][]common.Hash{{l2ooABI.Events[m.eventName].ID}}
+
+	nextOutputIndex, err := l2oo.NextOutputIndex(callOpts)
+	if err != nil {
+		return fmt.Errorf("failed to query the next output index: %w", err)
+	}
+	if nextOutputIndex.Sign() == 0 { // no output proposed yet.
+		return nil
+	}
+	output, err := l2oo.GetL2Output(callOpts, new(big.Int).Sub(nextOutputIndex, common.Big1))
+	if err != nil {
+		return fmt.Errorf("failed to query the latest output: %w", err)
+	}
+	m.lastProposal = output.Timestamp.Uint64()
+	return nil
+}
+
+// configureDisputeGameFactory watches the `DisputeGameCreated` events of the game type, the last proposal is the latest game of this type.
+func (m *Monitor) configureDisputeGameFactory(callOpts *bind.CallOpts, address common.Address, gameType uint32) error {
+	factoryABI, err := bindings.DisputeGameFactoryMetaData.GetAbi()
+	if err != nil {
+		return fmt.Errorf("failed to parse the DisputeGameFactory ABI: %w", err)
+	}
+	factory, err := bindings.NewDisputeGameFactoryCaller(address, m.l1Client)
+	if err != nil {
+		return fmt.Errorf("failed to bind to the DisputeGameFactory: %w", err)
+	}
+
+	m.source, m.eventName = address, "DisputeGameCreated"
+	m.topics = [][]common.Hash{{factoryABI.Events[m.eventName].ID}, nil, {common.BigToHash(new(big.Int).SetUint64(uint64(gameType)))}}
+
+	gameCount, err := factory.GameCount(callOpts)
+	if err != nil {
+		return fmt.Errorf("failed to query the game count: %w", err)
+	}
+	if gameCount.Sign() == 0 {
+		return nil
+	}
+	games, err := factory.FindLatestGames(callOpts, gameType, new(big.Int).Sub(gameCount, common.Big1), common.Big1)
+	if err != nil {
+		return fmt.Errorf("failed to find the latest game: %w", err)
+	}
+	if len(games) > 0 {
+		m.lastProposal = games[0].Timestamp
+	}
+	return nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	header, err := m.l1Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		m.log.Error("failed to query latest header", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "headerByNumber").Inc()
+		return
+	}
+	latestL1Height := header.Number.Uint64()
+
+	if m.nextBlock <= latestL1Height {
+		toBlock := min(latestL1Height, m.nextBlock+m.eventBlockRange-1)
+		if err := m.processProposals(ctx, m.nextBlock, toBlock); err != nil {
+			m.log.Error("failed to process the proposals", "from", m.nextBlock, "to", toBlock, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l1", m.eventName).Inc()
+			return
+		}
+		m.nextBlock = toBlock + 1
+	}
+
+	if m.lastProposal > 0 {
+		m.lastProposalTimestamp.Set(float64(m.lastProposal))
+		m.secondsSinceLastProposal.Set(float64(header.Time) - float64(m.lastProposal))
+	}
+	exceeded := isIntervalExceeded(m.lastProposal, header.Time, m.proposalInterval)
+	if exceeded {
+		m.log.Warn("proposal interval exceeded", "last_proposal", m.lastProposal, "now", header.Time, "interval", m.proposalInterval)
+	}
+	m.proposalIntervalExceeded.Set(util.BoolToFloat(exceeded))
+	m.Update(ctx, alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     ExceededRule,
+		Priority: "P1",
+		Entity:   m.proposer.String(),
+		Summary:  fmt.Sprintf("no proposal since %d, interval of %ds exceeded", m.lastProposal, m.proposalInterval),
+	}, exceeded)
+
+	balance, err := m.l1Client.BalanceAt(ctx, m.proposer, header.Number)
+	if err != nil {
+		m.log.Error("failed to query the balance of the proposer", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "balanceAt").Inc()
+		return
+	}
+	m.balance.Set(util.WeiToEther(balance))
+}
+
+// processProposals processes the proposal events made by the proposer between the two blocks (inclusive).
+func (m *Monitor) processProposals(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	logs, err := m.l1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{m.source},
+		Topics:    m.topics,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the %s events: %w", m.eventName, err)
+	}
+
+	for _, vLog := range logs {
+		tx, _, err := m.l1Client.TransactionByHash(ctx, vLog.TxHash)
+		if err != nil {
+			return fmt.Errorf("failed to query the transaction %s: %w", vLog.TxHash, err)
+		}
+		sender, err := m.l1Client.TransactionSender(ctx, tx, vLog.BlockHash, vLog.TxIndex)
+		if err != nil {
+			return fmt.Errorf("failed to query the sender of %s: %w", vLog.TxHash, err)
+		}
+		if sender != m.proposer {
+			m.log.Info("proposal made by another account", "tx", vLog.TxHash, "sender", sender)
+			continue
+		}
+
+		receipt, err := m.l1Client.TransactionReceipt(ctx, vLog.TxHash)
+		if err != nil {
+			return fmt.Errorf("failed to query the receipt of %s: %w", vLog.TxHash, err)
+		}
+		header, err := m.l1Client.HeaderByHash(ctx, vLog.BlockHash)
+		if err != nil {
+			return fmt.Errorf("failed to query the header of %s: %w", vLog.BlockHash, err)
+		}
+
+		cost := util.WeiToEther(gasCost(receipt))
+		m.lastProposal = header.Time
+		m.proposals.Inc()
+		m.lastProposalGasCost.Set(cost)
+		m.proposalsGasCost.Add(cost)
+		m.log.Info("proposal", "tx", vLog.TxHash, "block", vLog.BlockNumber, "timestamp", header.Time, "gas_used", receipt.GasUsed, "cost", cost)
+	}
+	return nil
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	return nil
+}
+
+// isIntervalExceeded returns true when the last proposal is known and older than the interval.
+func isIntervalExceeded(lastProposal uint64, now uint64, interval uint64) bool {
+	return lastProposal > 0 && now > lastProposal+interval
+}
+
+// gasCost returns the cost in wei paid for the execution and the blobs of the transaction.
+func gasCost(receipt *types.Receipt) *big.Int {
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	if receipt.BlobGasPrice != nil {
+		cost.Add(cost, new(big.Int).Mul(new(big.Int).SetUint64(receipt.BlobGasUsed), receipt.BlobGasPrice))
+	}
+	return cost
+}
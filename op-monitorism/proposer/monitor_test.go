@@ -0,0 +1,165 @@
+package proposer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var l2OutputOracle = common.HexToAddress("0xdfe97868233d1aa22e815a266982f2cf17685a27")
+
+func TestIsIntervalExceeded(t *testing.T) {
+	tests := []struct {
+		name         string
+		lastProposal uint64
+		now          uint64
+		expected     bool
+	}{
+		{name: "Unknown last proposal", lastProposal: 0, now: 10000, expected: false},
+		{name: "Within the interval", lastProposal: 1000, now: 4000, expected: false},
+		{name: "At the end of the interval", lastProposal: 1000, now: 4600, expected: false},
+		{name: "Interval exceeded", lastProposal: 1000, now: 4601, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isIntervalExceeded(test.lastProposal, test.now, 3600)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestGasCost(t *testing.T) {
+	receipt := &types.Receipt{GasUsed: 100, EffectiveGasPrice: big.NewInt(3)}
+	if cost := gasCost(receipt); cost.Cmp(big.NewInt(300)) != 0 {
+		t.Errorf("expected 300 but got %s", cost)
+	}
+
+	receipt.BlobGasUsed, receipt.BlobGasPrice = 10, big.NewInt(2)
+	if cost := gasCost(receipt); cost.Cmp(big.NewInt(320)) != 0 {
+		t.Errorf("expected 320 but got %s", cost)
+	}
+}
+
+// proposerNode is an L1 node with the L2OutputOracle whose latest output is at the timestamp 1000.
+type proposerNode struct {
+	*fake.Node
+
+	key *ecdsa.PrivateKey
+}
+
+func newProposerNode(t *testing.T) *proposerNode {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	n := &proposerNode{Node: fake.NewNode(t), key: key}
+	n.AddBlock(&types.Header{Time: 2000})
+	n.Result("eth_getBalance", (*hexutil.Big)(big.NewInt(1e18)))
+	n.Contract(l2OutputOracle, util.MustParseABI(bindings.L2OutputOracleMetaData)).
+		Returns("nextOutputIndex", big.NewInt(1)).
+		Returns("getL2Output", bindings.TypesOutputProposal{Timestamp: big.NewInt(1000), L2BlockNumber: big.NewInt(1800)})
+	return n
+}
+
+// propose includes an output proposed by the proposer in a new block at the timestamp.
+func (n *proposerNode) propose(t *testing.T, timestamp uint64) {
+	l2ooABI := util.MustParseABI(bindings.L2OutputOracleMetaData)
+	event := l2ooABI.Events["OutputProposed"]
+	data, err := event.Inputs.NonIndexed().Pack(new(big.Int).SetUint64(timestamp))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	tx := types.MustSignNewTx(n.key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{ChainID: big.NewInt(1), To: &l2OutputOracle, Gas: 100_000, GasFeeCap: big.NewInt(1e9)})
+	header := n.AddBlock(&types.Header{Time: timestamp}, tx)
+	n.AddReceipt(&types.Receipt{TxHash: tx.Hash(), Status: types.ReceiptStatusSuccessful, BlockHash: header.Hash(), BlockNumber: header.Number, GasUsed: 80_000, EffectiveGasPrice: big.NewInt(1e9), Logs: []*types.Log{}})
+	n.AddLogs(types.Log{
+		Address:     l2OutputOracle,
+		Topics:      []common.Hash{event.ID, {}, common.BigToHash(common.Big1), common.BigToHash(big.NewInt(3600))},
+		Data:        data,
+		BlockNumber: header.Number.Uint64(),
+		BlockHash:   header.Hash(),
+		TxHash:      tx.Hash(),
+	})
+}
+
+// newTestMonitor returns the monitor of the proposals to the L2OutputOracle every hour, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *proposerNode) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{L1NodeURL: node.URL, ProposerAddress: crypto.PubkeyToAddress(node.key.PublicKey), L2OutputOracleAddress: &l2OutputOracle, ProposalInterval: time.Hour, EventBlockRange: 100}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := newProposerNode(t)
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// the latest output is the last proposal, within the interval.
+	m.Run(ctx)
+	if last, since := testutil.ToFloat64(m.lastProposalTimestamp), testutil.ToFloat64(m.secondsSinceLastProposal); last != 1000 || since != 1000 {
+		t.Errorf("expected the last proposal at 1000, 1000s ago, but got %v and %v", last, since)
+	}
+	if exceeded, balance := testutil.ToFloat64(m.proposalIntervalExceeded), testutil.ToFloat64(m.balance); exceeded != 0 || balance != 1 {
+		t.Errorf("expected the interval not exceeded with a balance of 1 ETH but got %v and %v", exceeded, balance)
+	}
+
+	// a proposal of the proposer is counted along with its gas cost.
+	node.propose(t, 2500)
+	m.Run(ctx)
+	cost := util.WeiToEther(big.NewInt(80_000 * 1e9))
+	if proposals, last := testutil.ToFloat64(m.proposals), testutil.ToFloat64(m.lastProposalGasCost); proposals != 1 || last != cost {
+		t.Errorf("expected 1 proposal costing %v ETH but got %v proposals costing %v", cost, proposals, last)
+	}
+	if total, since := testutil.ToFloat64(m.proposalsGasCost), testutil.ToFloat64(m.secondsSinceLastProposal); total != cost || since != 0 {
+		t.Errorf("expected a total cost of %v ETH and a proposal 0s ago but got %v and %v", cost, total, since)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newProposerNode(t)
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// no proposal within the interval fires once, resolved by the next proposal.
+	node.AddBlock(&types.Header{Time: 5000})
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(ExceededRule); len(alerts) != 1 || alerts[0].Entity != m.proposer.String() || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the exceeded alert once but got %v", alerts)
+	}
+	node.propose(t, 5100)
+	m.Run(ctx)
+	if firing := notifier.Firing(ExceededRule); len(firing) != 0 || m.lastProposal != 5100 {
+		t.Fatalf("expected the exceeded alert resolved but got %v", firing)
+	}
+}
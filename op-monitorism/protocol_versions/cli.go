@@ -0,0 +1,96 @@
+package protocol_versions
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+
+	ProtocolVersionsAddressFlagName = "protocolversions.address"
+	NodesFlagName                   = "nodes"
+	JWTSecretFlagName               = "jwt.secret"
+)
+
+// Node is an execution engine of a managed node, queried through its authenticated engine API.
+type Node struct {
+	Name string
+	URL  string
+}
+
+type CLIConfig struct {
+	L1NodeURL string
+
+	ProtocolVersionsAddress common.Address
+	Nodes                   []Node
+	JWTSecretPath           string
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:     ctx.String(L1NodeURLFlagName),
+		JWTSecretPath: ctx.String(JWTSecretFlagName),
+	}
+
+	protocolVersionsAddress := ctx.String(ProtocolVersionsAddressFlagName)
+	if !common.IsHexAddress(protocolVersionsAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", ProtocolVersionsAddressFlagName)
+	}
+	cfg.ProtocolVersionsAddress = common.HexToAddress(protocolVersionsAddress)
+
+	for _, node := range ctx.StringSlice(NodesFlagName) {
+		parsed, err := parseNode(node)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Nodes = append(cfg.Nodes, parsed)
+	}
+	if len(cfg.Nodes) > 0 && len(cfg.JWTSecretPath) == 0 {
+		return cfg, fmt.Errorf("--%s must be set to query the nodes", JWTSecretFlagName)
+	}
+
+	return cfg, nil
+}
+
+// parseNode parses a node formatted via `name=url`.
+func parseNode(node string) (Node, error) {
+	name, url, err := util.ParseNamedValue(node, "name=url")
+	if err != nil {
+		return Node{}, err
+	}
+	return Node{Name: name, URL: url}, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     ProtocolVersionsAddressFlagName,
+			Usage:    "Address of the ProtocolVersions contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "PROTOCOL_VERSIONS"),
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:    NodesFlagName,
+			Usage:   "One or more authenticated engine API URLs of the managed nodes formatted via `name=url`",
+			EnvVars: opservice.PrefixEnvVar(envVar, "NODES"),
+		},
+		&cli.StringFlag{
+			Name:    JWTSecretFlagName,
+			Usage:   "Path to the JWT secret shared with the engine API of the nodes",
+			EnvVars: opservice.PrefixEnvVar(envVar, "JWT_SECRET"),
+		},
+	}
+}
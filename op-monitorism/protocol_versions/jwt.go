@@ -0,0 +1,57 @@
+package protocol_versions
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ReadJWTSecret reads a hex-encoded 32 bytes JWT secret, as used by the engine API.
+func ReadJWTSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the JWT secret %s: %w", path, err)
+	}
+	secret := common.FromHex(strings.TrimSpace(string(data)))
+	if len(secret) != 32 {
+		return nil, fmt.Errorf("invalid JWT secret %s: expected 32 bytes but got %d", path, len(secret))
+	}
+	return secret, nil
+}
+
+// jwtToken returns a HS256 token with the `iat` claim expected by the engine API.
+func jwtToken(secret []byte, issuedAt time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]int64{"iat": issuedAt.Unix()})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// jwtAuth returns the `rpc.WithHTTPAuth` provider setting a fresh token on every request.
+func jwtAuth(secret []byte) func(h http.Header) error {
+	return func(h http.Header) error {
+		token, err := jwtToken(secret, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to create the JWT token: %w", err)
+		}
+		h.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
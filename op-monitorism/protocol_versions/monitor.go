@@ -0,0 +1,232 @@
+package protocol_versions
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "protocol_versions"
+	MetricsNamespace = "protocol_versions_mon"
+
+	// Rules of the alerts emitted by the monitor.
+	ChangedRule = "protocol version changed"
+	BehindRule  = "node behind the protocol version"
+
+	RequiredVersion    = "required"
+	RecommendedVersion = "recommended"
+)
+
+// superchainSignal is the parameter of `engine_signalSuperchainV1`.
+type superchainSignal struct {
+	Recommended params.ProtocolVersion `json:"recommended"`
+	Required    params.ProtocolVersion `json:"required"`
+}
+
+type node struct {
+	Node
+	client  *rpc.Client
+	version string // last version reported by the node.
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client         *ethclient.Client
+	protocolVersions *bindings.ProtocolVersionsCaller
+	nodes            []*node
+
+	versions map[string]string // last versions read from the contract by type.
+
+	// metrics
+	protocolVersion     *prometheus.GaugeVec
+	nodeProtocolVersion *prometheus.GaugeVec
+	nodeComparison      *prometheus.GaugeVec
+	nodeBehind          *prometheus.GaugeVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating protocol versions monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	protocolVersions, err := bindings.NewProtocolVersionsCaller(cfg.ProtocolVersionsAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the ProtocolVersions: %w", err)
+	}
+
+	nodes := make([]*node, len(cfg.Nodes))
+	if len(cfg.Nodes) > 0 {
+		secret, err := ReadJWTSecret(cfg.JWTSecretPath)
+		if err != nil {
+			return nil, err
+		}
+		for i, n := range cfg.Nodes {
+			client, err := rpc.DialOptions(ctx, n.URL, rpc.WithHTTPAuth(jwtAuth(secret)))
+			if err != nil {
+				return nil, fmt.Errorf("failed to dial the node %s: %w", n.Name, err)
+			}
+			nodes[i] = &node{Node: n, client: client}
+			log.Info("configured node", "name", n.Name)
+		}
+	}
+
+	return &Monitor{
+		log: log,
+
+		l1Client:         l1Client,
+		protocolVersions: protocolVersions,
+		nodes:            nodes,
+
+		versions: make(map[string]string),
+
+		protocolVersion: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "protocolVersion",
+			Help:      "1 for the current required and recommended protocol versions of the ProtocolVersions contract",
+		}, []string{"type", "version"}),
+		nodeProtocolVersion: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "nodeProtocolVersion",
+			Help:      "1 for the protocol version supported by the node",
+		}, []string{"node", "version"}),
+		nodeComparison: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "nodeComparison",
+			Help:      "comparison of the version of the node against the protocol version, negative when the node is outdated (-4 major, -3 minor, -2 patch, -1 prerelease), 0 when matching, positive when ahead, 100+ when not comparable",
+		}, []string{"node", "type"}),
+		nodeBehind: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "nodeBehind",
+			Help:      "1 if the node is behind the protocol version, 0 otherwise",
+		}, []string{"node", "type"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	required, err := m.protocolVersions.Required(callOpts)
+	if err != nil {
+		m.log.Error("failed to query the required protocol version", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "required").Inc()
+		return
+	}
+	recommended, err := m.protocolVersions.Recommended(callOpts)
+	if err != nil {
+		m.log.Error("failed to query the recommended protocol version", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "recommended").Inc()
+		return
+	}
+
+	requiredVersion, recommendedVersion := toProtocolVersion(required), toProtocolVersion(recommended)
+	m.setVersion(ctx, RequiredVersion, requiredVersion)
+	m.setVersion(ctx, RecommendedVersion, recommendedVersion)
+
+	for _, n := range m.nodes {
+		m.checkNode(ctx, n, requiredVersion, recommendedVersion)
+	}
+}
+
+// setVersion reports the protocol version of the type, replacing the previous one.
+func (m *Monitor) setVersion(ctx context.Context, versionType string, version params.ProtocolVersion) {
+	current := version.String()
+	if previous, ok := m.versions[versionType]; ok && previous != current {
+		m.log.Warn("protocol version changed", "type", versionType, "previous", previous, "current", current)
+		m.Emit(ctx, alerts.Alert{
+			Monitor:  MonitorName,
+			Rule:     ChangedRule,
+			Priority: "P2",
+			Entity:   versionType,
+			Summary:  fmt.Sprintf("%s protocol version changed from %s to %s", versionType, previous, current),
+		})
+		m.protocolVersion.DeleteLabelValues(versionType, previous)
+	}
+	m.versions[versionType] = current
+	m.protocolVersion.WithLabelValues(versionType, current).Set(1)
+}
+
+// checkNode signals the protocol versions to the node and compares the version it supports against them.
+func (m *Monitor) checkNode(ctx context.Context, n *node, required params.ProtocolVersion, recommended params.ProtocolVersion) {
+	var version params.ProtocolVersion
+	if err := n.client.CallContext(ctx, &version, "engine_signalSuperchainV1", &superchainSignal{Recommended: recommended, Required: required}); err != nil {
+		m.log.Error("failed to query the protocol version of the node", "node", n.Name, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues(n.Name, "signalSuperchainV1").Inc()
+		return
+	}
+
+	current := version.String()
+	if len(n.version) > 0 && n.version != current {
+		m.nodeProtocolVersion.DeleteLabelValues(n.Name, n.version)
+	}
+	n.version = current
+	m.nodeProtocolVersion.WithLabelValues(n.Name, current).Set(1)
+
+	for versionType, protocolVersion := range map[string]params.ProtocolVersion{RequiredVersion: required, RecommendedVersion: recommended} {
+		comparison := version.Compare(protocolVersion)
+		behind := isBehind(comparison)
+		if behind {
+			m.log.Warn("node behind the protocol version", "node", n.Name, "type", versionType, "node_version", current, "protocol_version", protocolVersion.String())
+		}
+		m.nodeComparison.WithLabelValues(n.Name, versionType).Set(float64(comparison))
+		m.nodeBehind.WithLabelValues(n.Name, versionType).Set(util.BoolToFloat(behind))
+
+		// falling behind the required version halts the node, the recommended one only warrants an upgrade.
+		priority := "P2"
+		if versionType == RequiredVersion {
+			priority = "P1"
+		}
+		m.Update(ctx, alerts.Alert{
+			Monitor:  MonitorName,
+			Rule:     BehindRule,
+			Priority: priority,
+			Entity:   n.Name + "/" + versionType,
+			Summary:  fmt.Sprintf("node %s supports %s, behind the %s protocol version %s", n.Name, current, versionType, protocolVersion.String()),
+			Labels:   map[string]string{"node": n.Name, "type": versionType},
+		}, behind)
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	for _, n := range m.nodes {
+		n.client.Close()
+	}
+	return nil
+}
+
+// toProtocolVersion converts the uint256 of the contract to a protocol version.
+func toProtocolVersion(value *big.Int) (version params.ProtocolVersion) {
+	value.FillBytes(version[:])
+	return version
+}
+
+// isBehind returns true when the comparison of the node version against the protocol version means it is outdated.
+func isBehind(comparison params.ProtocolVersionComparison) bool {
+	return comparison < 0
+}
@@ -0,0 +1,186 @@
+package protocol_versions
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var protocolVersions = common.HexToAddress("0x8062AbC286f5e7D9428a0Ccb9AbD71e50d93b935")
+
+func TestParseNode(t *testing.T) {
+	n, err := parseNode("sequencer=http://127.0.0.1:8551")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if n.Name != "sequencer" || n.URL != "http://127.0.0.1:8551" {
+		t.Errorf("unexpected node %v", n)
+	}
+
+	for _, invalid := range []string{"sequencer", "=http://127.0.0.1:8551", "sequencer="} {
+		if _, err := parseNode(invalid); err == nil {
+			t.Errorf("expected an error for %s", invalid)
+		}
+	}
+}
+
+func TestToProtocolVersion(t *testing.T) {
+	expected := params.ProtocolVersionV0{Major: 6, Minor: 1, Patch: 2}.Encode()
+	version := toProtocolVersion(new(big.Int).SetBytes(expected[:]))
+	if version != expected {
+		t.Errorf("expected %s but got %s", expected, version)
+	}
+}
+
+func TestIsBehind(t *testing.T) {
+	required := params.ProtocolVersionV0{Major: 6}.Encode()
+	tests := []struct {
+		name     string
+		version  params.ProtocolVersion
+		expected bool
+	}{
+		{name: "Matching", version: params.ProtocolVersionV0{Major: 6}.Encode(), expected: false},
+		{name: "Ahead", version: params.ProtocolVersionV0{Major: 7}.Encode(), expected: false},
+		{name: "Outdated major", version: params.ProtocolVersionV0{Major: 5, Minor: 9}.Encode(), expected: true},
+		{name: "Prerelease of the required version", version: params.ProtocolVersionV0{Major: 6, PreRelease: 1}.Encode(), expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := isBehind(test.version.Compare(required))
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+func TestJWTToken(t *testing.T) {
+	secret := make([]byte, 32)
+	token, err := jwtToken(secret, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 parts but got %d", len(parts))
+	}
+	claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || string(claims) != `{"iat":1700000000}` {
+		t.Errorf("unexpected claims %s (%v)", claims, err)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if parts[2] != base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("invalid signature %s", parts[2])
+	}
+}
+
+// version returns the protocol version of the major version, as a uint256 of the ProtocolVersions.
+func version(major uint32) *big.Int {
+	v := params.ProtocolVersionV0{Major: major}.Encode()
+	return new(big.Int).SetBytes(v[:])
+}
+
+// newTestMonitor returns the monitor of the protocol versions of the L1 node signaled to the engine node, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, l1 *fake.Node, engine *fake.Node) (*Monitor, *fake.Notifier) {
+	secretPath := filepath.Join(t.TempDir(), "jwt.hex")
+	if err := os.WriteFile(secretPath, []byte(hexutil.Encode(make([]byte, 32))), 0o600); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	cfg := CLIConfig{L1NodeURL: l1.URL, ProtocolVersionsAddress: protocolVersions, Nodes: []Node{{Name: "engine", URL: engine.URL}}, JWTSecretPath: secretPath}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	l1, engine := fake.NewNode(t), fake.NewNode(t)
+	contract := l1.Contract(protocolVersions, util.MustParseABI(bindings.ProtocolVersionsMetaData)).
+		Returns("required", version(6)).
+		Returns("recommended", version(6))
+	engine.Result("engine_signalSuperchainV1", params.ProtocolVersionV0{Major: 6}.Encode())
+	m, _ := newTestMonitor(t, l1, engine)
+	ctx := context.Background()
+
+	// the protocol versions and the one of the node are reported, the node being up to date.
+	v6, v7 := toProtocolVersion(version(6)).String(), toProtocolVersion(version(7)).String()
+	m.Run(ctx)
+	if required, node := testutil.ToFloat64(m.protocolVersion.WithLabelValues(RequiredVersion, v6)), testutil.ToFloat64(m.nodeProtocolVersion.WithLabelValues("engine", v6)); required != 1 || node != 1 {
+		t.Errorf("expected the required and node versions %s but got %v and %v", v6, required, node)
+	}
+	if comparison, behind := testutil.ToFloat64(m.nodeComparison.WithLabelValues("engine", RecommendedVersion)), testutil.ToFloat64(m.nodeBehind.WithLabelValues("engine", RecommendedVersion)); comparison != 0 || behind != 0 {
+		t.Errorf("expected the node at the recommended version but got the comparison %v and behind %v", comparison, behind)
+	}
+
+	// a new recommended version replaces the previous series and puts the node behind it.
+	contract.Returns("recommended", version(7))
+	m.Run(ctx)
+	if count := testutil.CollectAndCount(m.protocolVersion); count != 2 {
+		t.Errorf("expected the required and recommended series only but got %d", count)
+	}
+	if recommended := testutil.ToFloat64(m.protocolVersion.WithLabelValues(RecommendedVersion, v7)); recommended != 1 {
+		t.Errorf("expected the recommended version %s but got %v", v7, recommended)
+	}
+	if behind := testutil.ToFloat64(m.nodeBehind.WithLabelValues("engine", RecommendedVersion)); behind != 1 {
+		t.Errorf("expected the node behind the recommended version but got %v", behind)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	l1, engine := fake.NewNode(t), fake.NewNode(t)
+	contract := l1.Contract(protocolVersions, util.MustParseABI(bindings.ProtocolVersionsMetaData)).
+		Returns("required", version(6)).
+		Returns("recommended", version(6))
+	engine.Result("engine_signalSuperchainV1", params.ProtocolVersionV0{Major: 6}.Encode())
+	m, notifier := newTestMonitor(t, l1, engine)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a new recommended version fires the change once, and the node behind it until upgraded.
+	contract.Returns("recommended", version(7))
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(ChangedRule); len(alerts) != 1 || alerts[0].Entity != RecommendedVersion || alerts[0].Priority != "P2" {
+		t.Fatalf("expected the changed alert once but got %v", alerts)
+	}
+	if alerts := notifier.Alerts(BehindRule); len(alerts) != 1 || alerts[0].Entity != "engine/recommended" || alerts[0].Priority != "P2" || alerts[0].Labels["type"] != RecommendedVersion {
+		t.Fatalf("expected the behind alert once but got %v", alerts)
+	}
+	engine.Result("engine_signalSuperchainV1", params.ProtocolVersionV0{Major: 7}.Encode())
+	m.Run(ctx)
+	if firing := notifier.Firing(BehindRule); len(firing) != 0 {
+		t.Fatalf("expected the behind alert resolved but got %v", firing)
+	}
+}
@@ -0,0 +1,112 @@
+package proxy_admin
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName = "l1.node.url"
+
+	ProxyAdminAddressFlagName  = "proxyadmin.address"
+	ProxiesFlagName            = "proxies"
+	MaintenanceWindowsFlagName = "maintenance.windows"
+	StartBlockHeightFlagName   = "start.block.height"
+	EventBlockRangeFlagName    = "event.block.range"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+
+	ProxyAdminAddress common.Address
+
+	// Proxies are the names of the watched proxies by address. Every proxy administered by the ProxyAdmin is watched when empty.
+	Proxies map[common.Address]string
+
+	// MaintenanceWindows are the announced windows, the changes outside of them are reported as unannounced.
+	MaintenanceWindows []MaintenanceWindow
+
+	StartBlockHeight uint64
+	EventBlockRange  uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL:        ctx.String(L1NodeURLFlagName),
+		Proxies:          make(map[common.Address]string),
+		StartBlockHeight: ctx.Uint64(StartBlockHeightFlagName),
+		EventBlockRange:  ctx.Uint64(EventBlockRangeFlagName),
+	}
+
+	proxyAdminAddress := ctx.String(ProxyAdminAddressFlagName)
+	if !common.IsHexAddress(proxyAdminAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", ProxyAdminAddressFlagName)
+	}
+	cfg.ProxyAdminAddress = common.HexToAddress(proxyAdminAddress)
+
+	for _, proxy := range ctx.StringSlice(ProxiesFlagName) {
+		name, address, err := util.ParseNamedValue(proxy, "name=address")
+		if err != nil || !common.IsHexAddress(address) {
+			return cfg, fmt.Errorf("failed to parse `name=address`: %s", proxy)
+		}
+		cfg.Proxies[common.HexToAddress(address)] = name
+	}
+
+	for _, window := range ctx.StringSlice(MaintenanceWindowsFlagName) {
+		parsed, err := parseMaintenanceWindow(window)
+		if err != nil {
+			return cfg, fmt.Errorf("--%s: %w", MaintenanceWindowsFlagName, err)
+		}
+		cfg.MaintenanceWindows = append(cfg.MaintenanceWindows, parsed)
+	}
+
+	if cfg.EventBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", EventBlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     ProxyAdminAddressFlagName,
+			Usage:    "Address of the ProxyAdmin contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "PROXY_ADMIN"),
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:    ProxiesFlagName,
+			Usage:   "Proxies watched formatted via `name=address`, every proxy administered by the ProxyAdmin when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "PROXIES"),
+		},
+		&cli.StringSliceFlag{
+			Name:    MaintenanceWindowsFlagName,
+			Usage:   "Announced maintenance windows formatted via `start/end` (RFC 3339), the changes outside of them are unannounced",
+			EnvVars: opservice.PrefixEnvVar(envVar, "MAINTENANCE_WINDOWS"),
+		},
+		&cli.Uint64Flag{
+			Name:    StartBlockHeightFlagName,
+			Usage:   "Starting height to scan for upgrades, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    EventBlockRangeFlagName,
+			Usage:   "Max block range when scanning for upgrades",
+			Value:   1000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EVENT_BLOCK_RANGE"),
+		},
+	}
+}
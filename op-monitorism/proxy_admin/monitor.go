@@ -0,0 +1,418 @@
+package proxy_admin
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "proxy_admin"
+	MetricsNamespace = "proxy_admin_mon"
+
+	// Rules of the alerts emitted by the monitor, paged when the change is outside of the maintenance windows.
+	OwnershipTransferredRule = "ownership of the ProxyAdmin transferred"
+	UpgradedRule             = "proxy upgraded"
+	AdminChangedRule         = "admin of the proxy changed"
+)
+
+var (
+	// ImplementationSlot and AdminSlot are the EIP-1967 storage slots of the implementation and the admin of a proxy.
+	ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	AdminSlot          = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+)
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	l1Client *ethclient.Client
+
+	proxyAdminAddress common.Address
+	proxyAdmin        *bindings.ProxyAdminCaller
+	proxyAdminEvents  *bindings.ProxyAdminFilterer
+	proxyAdminABI     *abi.ABI
+	proxyEvents       *bindings.ProxyFilterer
+	proxyABI          *abi.ABI
+
+	proxies            map[common.Address]string
+	maintenanceWindows []MaintenanceWindow
+
+	nextL1Height    uint64
+	eventBlockRange uint64
+
+	owner common.Address
+
+	// metrics
+	upgrades            *prometheus.CounterVec
+	adminChanges        *prometheus.CounterVec
+	ownershipTransfers  *prometheus.CounterVec
+	implementation      *prometheus.GaugeVec
+	ownerInfo           *prometheus.GaugeVec
+	inMaintenanceWindow prometheus.Gauge
+	highestBlockNumber  *prometheus.GaugeVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating proxy admin monitor...")
+
+	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+
+	proxyAdmin, err := bindings.NewProxyAdminCaller(cfg.ProxyAdminAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the ProxyAdmin: %w", err)
+	}
+	proxyAdminEvents, err := bindings.NewProxyAdminFilterer(cfg.ProxyAdminAddress, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the ProxyAdmin: %w", err)
+	}
+	proxyAdminABI, err := bindings.ProxyAdminMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the ProxyAdmin ABI: %w", err)
+	}
+	// The proxies are not known in advance, the filterer only parses their logs.
+	proxyEvents, err := bindings.NewProxyFilterer(common.Address{}, l1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the Proxy: %w", err)
+	}
+	proxyABI, err := bindings.ProxyMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the Proxy ABI: %w", err)
+	}
+
+	startingL1Height := cfg.StartBlockHeight
+	if startingL1Height == 0 {
+		if startingL1Height, err = l1Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+	log.Info("configured starting height", "height", startingL1Height, "proxies", len(cfg.Proxies), "maintenance_windows", len(cfg.MaintenanceWindows))
+
+	return &Monitor{
+		log: log,
+
+		l1Client: l1Client,
+
+		proxyAdminAddress: cfg.ProxyAdminAddress,
+		proxyAdmin:        proxyAdmin,
+		proxyAdminEvents:  proxyAdminEvents,
+		proxyAdminABI:     proxyAdminABI,
+		proxyEvents:       proxyEvents,
+		proxyABI:          proxyABI,
+
+		proxies:            cfg.Proxies,
+		maintenanceWindows: cfg.MaintenanceWindows,
+
+		nextL1Height:    startingL1Height,
+		eventBlockRange: cfg.EventBlockRange,
+
+		upgrades: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "upgrades",
+			Help:      "number of upgrades of the proxies, announced or not",
+		}, []string{"proxy", "announced"}),
+		adminChanges: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "adminChanges",
+			Help:      "number of changes of the admin of the proxies, announced or not",
+		}, []string{"proxy", "announced"}),
+		ownershipTransfers: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "ownershipTransfers",
+			Help:      "number of ownership transfers of the ProxyAdmin, announced or not",
+		}, []string{"announced"}),
+		implementation: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "implementation",
+			Help:      "implementation the proxy was last upgraded to",
+		}, []string{"proxy", "implementation"}),
+		ownerInfo: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "owner",
+			Help:      "owner of the ProxyAdmin",
+		}, []string{"address"}),
+		inMaintenanceWindow: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "inMaintenanceWindow",
+			Help:      "1 if the current time is within an announced maintenance window, 0 otherwise",
+		}),
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed l1 heights (checked and known)",
+		}, []string{"type"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	m.inMaintenanceWindow.Set(util.BoolToFloat(isAnnounced(m.maintenanceWindows, time.Now())))
+
+	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l1", "blockNumber").Inc()
+		return
+	}
+	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestL1Height))
+
+	owner, err := m.proxyAdmin.Owner(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(latestL1Height)})
+	if err != nil {
+		m.log.Error("failed to query the owner of the ProxyAdmin", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("ProxyAdmin", "owner").Inc()
+	} else {
+		if owner != m.owner {
+			m.ownerInfo.DeleteLabelValues(m.owner.String())
+			m.owner = owner
+		}
+		m.ownerInfo.WithLabelValues(owner.String()).Set(1)
+	}
+
+	if m.nextL1Height > latestL1Height {
+		m.log.Info("no new blocks", "next_height", m.nextL1Height, "latest_height", latestL1Height)
+		return
+	}
+
+	toBlock := min(latestL1Height, m.nextL1Height+m.eventBlockRange-1)
+	blockTimes := make(map[uint64]time.Time)
+	if err := m.processOwnershipTransfers(ctx, m.nextL1Height, toBlock, blockTimes); err != nil {
+		m.log.Error("failed to process the ownership transfers", "from", m.nextL1Height, "to", toBlock, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("ProxyAdmin", "OwnershipTransferred").Inc()
+		return
+	}
+	if err := m.processProxyChanges(ctx, m.nextL1Height, toBlock, blockTimes); err != nil {
+		m.log.Error("failed to process the proxy upgrades", "from", m.nextL1Height, "to", toBlock, "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("Proxy", "Upgraded").Inc()
+		return
+	}
+
+	m.log.Info("checked blocks", "from", m.nextL1Height, "to", toBlock)
+	m.highestBlockNumber.WithLabelValues("checked").Set(float64(toBlock))
+	m.nextL1Height = toBlock + 1
+}
+
+// processOwnershipTransfers reports the ownership transfers of the ProxyAdmin between the two L1 blocks (inclusive).
+func (m *Monitor) processOwnershipTransfers(ctx context.Context, fromBlock uint64, toBlock uint64, blockTimes map[uint64]time.Time) error {
+	logs, err := m.l1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{m.proxyAdminAddress},
+		Topics:    [][]common.Hash{{m.proxyAdminABI.Events["OwnershipTransferred"].ID}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the OwnershipTransferred events: %w", err)
+	}
+
+	for _, vLog := range logs {
+		transfer, err := m.proxyAdminEvents.ParseOwnershipTransferred(vLog)
+		if err != nil {
+			return fmt.Errorf("failed to parse the ownership transfer %s: %w", vLog.TxHash, err)
+		}
+		announced, err := m.isAnnounced(ctx, vLog.BlockNumber, blockTimes)
+		if err != nil {
+			return err
+		}
+
+		m.ownershipTransfers.WithLabelValues(strconv.FormatBool(announced)).Inc()
+		m.logChange("ownership of the ProxyAdmin transferred", announced, "previous", transfer.PreviousOwner, "owner", transfer.NewOwner, "l1_tx", vLog.TxHash, "l1_block", vLog.BlockNumber)
+		m.emitChange(ctx, OwnershipTransferredRule, announced, m.proxyAdminAddress.String(), vLog,
+			fmt.Sprintf("ownership of the ProxyAdmin transferred from %s to %s", transfer.PreviousOwner, transfer.NewOwner))
+	}
+	return nil
+}
+
+// processProxyChanges reports the upgrades and the admin changes of the proxies between the two L1 blocks (inclusive).
+// Without configured proxies, the changes of every proxy administered by the ProxyAdmin are reported.
+func (m *Monitor) processProxyChanges(ctx context.Context, fromBlock uint64, toBlock uint64, blockTimes map[uint64]time.Time) error {
+	var addresses []common.Address
+	for address := range m.proxies {
+		addresses = append(addresses, address)
+	}
+
+	upgradedID, adminChangedID := m.proxyABI.Events["Upgraded"].ID, m.proxyABI.Events["AdminChanged"].ID
+	logs, err := m.l1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: addresses,
+		Topics:    [][]common.Hash{{upgradedID, adminChangedID}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the Upgraded and AdminChanged events: %w", err)
+	}
+
+	for _, vLog := range logs {
+		if len(vLog.Topics) == 0 {
+			continue
+		}
+
+		switch vLog.Topics[0] {
+		case upgradedID:
+			err = m.processUpgraded(ctx, vLog, blockTimes)
+		case adminChangedID:
+			err = m.processAdminChanged(ctx, vLog, blockTimes)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Monitor) processUpgraded(ctx context.Context, vLog types.Log, blockTimes map[uint64]time.Time) error {
+	upgraded, err := m.proxyEvents.ParseUpgraded(vLog)
+	if err != nil {
+		// e.g. an Upgraded event of another standard with a non-indexed implementation.
+		m.log.Debug("ignoring an unexpected Upgraded event", "proxy", vLog.Address, "l1_tx", vLog.TxHash, "err", err)
+		return nil
+	}
+
+	managed, err := m.isManaged(ctx, vLog.Address, vLog.BlockNumber)
+	if err != nil || !managed {
+		return err
+	}
+	previous, err := m.readSlot(ctx, vLog.Address, ImplementationSlot, vLog.BlockNumber-1)
+	if err != nil {
+		return err
+	}
+	announced, err := m.isAnnounced(ctx, vLog.BlockNumber, blockTimes)
+	if err != nil {
+		return err
+	}
+
+	proxy := m.proxyName(vLog.Address)
+	m.upgrades.WithLabelValues(proxy, strconv.FormatBool(announced)).Inc()
+	m.implementation.DeleteLabelValues(proxy, previous.String())
+	m.implementation.WithLabelValues(proxy, upgraded.Implementation.String()).Set(1)
+	m.logChange("proxy upgraded", announced, "proxy", proxy, "address", vLog.Address, "previous", previous, "implementation", upgraded.Implementation, "l1_tx", vLog.TxHash, "l1_block", vLog.BlockNumber)
+	m.emitChange(ctx, UpgradedRule, announced, proxy, vLog,
+		fmt.Sprintf("proxy %s upgraded from %s to %s", proxy, previous, upgraded.Implementation))
+	return nil
+}
+
+func (m *Monitor) processAdminChanged(ctx context.Context, vLog types.Log, blockTimes map[uint64]time.Time) error {
+	changed, err := m.proxyEvents.ParseAdminChanged(vLog)
+	if err != nil {
+		m.log.Debug("ignoring an unexpected AdminChanged event", "proxy", vLog.Address, "l1_tx", vLog.TxHash, "err", err)
+		return nil
+	}
+
+	// The proxies leaving or joining the ProxyAdmin are reported as well.
+	_, configured := m.proxies[vLog.Address]
+	if !configured && changed.PreviousAdmin != m.proxyAdminAddress && changed.NewAdmin != m.proxyAdminAddress {
+		return nil
+	}
+	announced, err := m.isAnnounced(ctx, vLog.BlockNumber, blockTimes)
+	if err != nil {
+		return err
+	}
+
+	proxy := m.proxyName(vLog.Address)
+	m.adminChanges.WithLabelValues(proxy, strconv.FormatBool(announced)).Inc()
+	m.logChange("admin of the proxy changed", announced, "proxy", proxy, "address", vLog.Address, "previous", changed.PreviousAdmin, "admin", changed.NewAdmin, "l1_tx", vLog.TxHash, "l1_block", vLog.BlockNumber)
+	m.emitChange(ctx, AdminChangedRule, announced, proxy, vLog,
+		fmt.Sprintf("admin of the proxy %s changed from %s to %s", proxy, changed.PreviousAdmin, changed.NewAdmin))
+	return nil
+}
+
+// isManaged returns true when the proxy is configured, or administered by the ProxyAdmin at the block without configured proxies.
+func (m *Monitor) isManaged(ctx context.Context, proxy common.Address, blockNumber uint64) (bool, error) {
+	if len(m.proxies) > 0 {
+		_, ok := m.proxies[proxy]
+		return ok, nil
+	}
+	admin, err := m.readSlot(ctx, proxy, AdminSlot, blockNumber)
+	if err != nil {
+		return false, err
+	}
+	return admin == m.proxyAdminAddress, nil
+}
+
+// readSlot reads an EIP-1967 slot of the proxy holding an address.
+func (m *Monitor) readSlot(ctx context.Context, proxy common.Address, slot common.Hash, blockNumber uint64) (common.Address, error) {
+	value, err := m.l1Client.StorageAt(ctx, proxy, slot, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to read the slot %s of the proxy %s: %w", slot, proxy, err)
+	}
+	return common.BytesToAddress(value), nil
+}
+
+// isAnnounced returns true when the block is within an announced maintenance window, the block times are cached for the iteration.
+func (m *Monitor) isAnnounced(ctx context.Context, blockNumber uint64, blockTimes map[uint64]time.Time) (bool, error) {
+	blockTime, ok := blockTimes[blockNumber]
+	if !ok {
+		header, err := m.l1Client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+		if err != nil {
+			return false, fmt.Errorf("failed to query the header %d: %w", blockNumber, err)
+		}
+		blockTime = time.Unix(int64(header.Time), 0)
+		blockTimes[blockNumber] = blockTime
+	}
+	return isAnnounced(m.maintenanceWindows, blockTime), nil
+}
+
+// logChange logs an announced change as a warning and an unannounced one as an error.
+func (m *Monitor) logChange(msg string, announced bool, ctx ...interface{}) {
+	if announced {
+		m.log.Warn(msg, ctx...)
+	} else {
+		m.log.Error("unannounced "+msg, ctx...)
+	}
+}
+
+// emitChange emits the alert of a change, paging when it is unannounced.
+func (m *Monitor) emitChange(ctx context.Context, rule string, announced bool, entity string, vLog types.Log, summary string) {
+	priority := "P0"
+	if announced {
+		priority = "P2"
+	} else {
+		summary = "unannounced " + summary
+	}
+	m.Emit(ctx, alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   entity,
+		Summary:  summary,
+		Labels:   map[string]string{"announced": strconv.FormatBool(announced), "l1_tx": vLog.TxHash.String()},
+	})
+}
+
+// proxyName returns the configured name of the proxy, its address when not configured.
+func (m *Monitor) proxyName(proxy common.Address) string {
+	if name, ok := m.proxies[proxy]; ok {
+		return name
+	}
+	return proxy.String()
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.l1Client.Close()
+	return nil
+}
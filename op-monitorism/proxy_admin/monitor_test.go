@@ -0,0 +1,144 @@
+package proxy_admin
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var (
+	proxyAdmin = common.HexToAddress("0x543bA4AADBAb8f9025686Bd03993043599c6fB04")
+	portal     = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+	owner      = common.HexToAddress("0x5a0Aae59D09fccBdDb6C6CcEB07B7279367C3d2A")
+
+	proxyAdminABI = util.MustParseABI(bindings.ProxyAdminMetaData)
+	proxyABI      = util.MustParseABI(bindings.ProxyMetaData)
+)
+
+// addEvent adds the event of the address to a new block at the timestamp, in a transaction identified by the block number.
+func addEvent(t *testing.T, node *fake.Node, timestamp uint64, address common.Address, event abi.Event, topics []common.Hash, args ...interface{}) {
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		t.Fatalf("failed to pack %s: %v", event.Name, err)
+	}
+	header := node.AddBlock(&types.Header{Time: timestamp})
+	node.AddLogs(types.Log{
+		Address:     address,
+		Topics:      append([]common.Hash{event.ID}, topics...),
+		Data:        data,
+		BlockNumber: header.Number.Uint64(),
+		BlockHash:   header.Hash(),
+		TxHash:      common.BigToHash(header.Number),
+	})
+}
+
+// newTestMonitor returns the monitor of the portal proxy administered by the ProxyAdmin, announcing the changes between
+// the timestamps 5000 and 6000, and the notifier of its alerts.
+func newTestMonitor(t *testing.T) (*Monitor, *fake.Node, *fake.Notifier) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{Time: 1000})
+	node.Contract(proxyAdmin, proxyAdminABI).Returns("owner", owner)
+	node.Handle("eth_getStorageAt", func(_ []json.RawMessage) (interface{}, error) {
+		return common.BytesToHash(common.HexToAddress("0x01").Bytes()), nil
+	})
+
+	cfg := CLIConfig{
+		L1NodeURL:          node.URL,
+		ProxyAdminAddress:  proxyAdmin,
+		Proxies:            map[common.Address]string{portal: "OptimismPortal"},
+		MaintenanceWindows: []MaintenanceWindow{{Start: time.Unix(5000, 0), End: time.Unix(6000, 0)}},
+		EventBlockRange:    100,
+	}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, node, notifier
+}
+
+func TestRun(t *testing.T) {
+	m, node, _ := newTestMonitor(t)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if info, checked := testutil.ToFloat64(m.ownerInfo.WithLabelValues(owner.String())), testutil.ToFloat64(m.highestBlockNumber.WithLabelValues("checked")); info != 1 || checked != 0 {
+		t.Errorf("expected the owner reported at the block 0 but got %v and %v", info, checked)
+	}
+
+	// an unannounced upgrade is counted and reports the new implementation.
+	implementation := common.HexToAddress("0x02")
+	addEvent(t, node, 2000, portal, proxyABI.Events["Upgraded"], []common.Hash{common.BytesToHash(implementation.Bytes())})
+	m.Run(ctx)
+	if upgrades, impl := testutil.ToFloat64(m.upgrades.WithLabelValues("OptimismPortal", "false")), testutil.ToFloat64(m.implementation.WithLabelValues("OptimismPortal", implementation.String())); upgrades != 1 || impl != 1 {
+		t.Errorf("expected 1 unannounced upgrade to %s but got %v and %v", implementation, upgrades, impl)
+	}
+
+	// an announced admin change is counted as announced.
+	addEvent(t, node, 5500, portal, proxyABI.Events["AdminChanged"], nil, proxyAdmin, common.HexToAddress("0x03"))
+	m.Run(ctx)
+	if changes := testutil.ToFloat64(m.adminChanges.WithLabelValues("OptimismPortal", "true")); changes != 1 {
+		t.Errorf("expected 1 announced admin change but got %v", changes)
+	}
+
+	// a new owner is counted as a transfer and replaces the owner series.
+	newOwner := common.HexToAddress("0x04")
+	addEvent(t, node, 7000, proxyAdmin, proxyAdminABI.Events["OwnershipTransferred"], []common.Hash{common.BytesToHash(owner.Bytes()), common.BytesToHash(newOwner.Bytes())})
+	node.Contract(proxyAdmin, proxyAdminABI).Returns("owner", newOwner)
+	m.Run(ctx)
+	if transfers, info := testutil.ToFloat64(m.ownershipTransfers.WithLabelValues("false")), testutil.ToFloat64(m.ownerInfo.WithLabelValues(newOwner.String())); transfers != 1 || info != 1 {
+		t.Errorf("expected 1 unannounced transfer to %s but got %v and %v", newOwner, transfers, info)
+	}
+	if count := testutil.CollectAndCount(m.ownerInfo); count != 1 {
+		t.Errorf("expected the series of the new owner only but got %d", count)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	m, node, notifier := newTestMonitor(t)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// an upgrade outside of the maintenance windows pages once.
+	addEvent(t, node, 2000, portal, proxyABI.Events["Upgraded"], []common.Hash{common.BytesToHash(common.HexToAddress("0x02").Bytes())})
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(UpgradedRule); len(alerts) != 1 || alerts[0].Entity != "OptimismPortal" || alerts[0].Priority != "P0" || alerts[0].Labels["announced"] != "false" {
+		t.Fatalf("expected the unannounced upgrade alert once but got %v", alerts)
+	}
+
+	// an admin change within a maintenance window is only reported.
+	addEvent(t, node, 5500, portal, proxyABI.Events["AdminChanged"], nil, proxyAdmin, common.HexToAddress("0x03"))
+	m.Run(ctx)
+	if alerts := notifier.Alerts(AdminChangedRule); len(alerts) != 1 || alerts[0].Priority != "P2" || alerts[0].Labels["announced"] != "true" {
+		t.Fatalf("expected the announced admin change alert but got %v", alerts)
+	}
+
+	// an ownership transfer of the ProxyAdmin pages as well.
+	newOwner := common.HexToAddress("0x04")
+	addEvent(t, node, 7000, proxyAdmin, proxyAdminABI.Events["OwnershipTransferred"], []common.Hash{common.BytesToHash(owner.Bytes()), common.BytesToHash(newOwner.Bytes())})
+	m.Run(ctx)
+	if alerts := notifier.Alerts(OwnershipTransferredRule); len(alerts) != 1 || alerts[0].Entity != proxyAdmin.String() || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the ownership transfer alert but got %v", alerts)
+	}
+}
@@ -0,0 +1,43 @@
+package proxy_admin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow is an announced window during which the upgrades are expected, both bounds inclusive.
+type MaintenanceWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// parseMaintenanceWindow parses a window formatted via `start/end`, both RFC 3339 timestamps.
+func parseMaintenanceWindow(window string) (MaintenanceWindow, error) {
+	split := strings.SplitN(window, "/", 2)
+	if len(split) != 2 {
+		return MaintenanceWindow{}, fmt.Errorf("failed to parse `start/end`: %s", window)
+	}
+	start, err := time.Parse(time.RFC3339, split[0])
+	if err != nil {
+		return MaintenanceWindow{}, fmt.Errorf("failed to parse the start of the window %s: %w", window, err)
+	}
+	end, err := time.Parse(time.RFC3339, split[1])
+	if err != nil {
+		return MaintenanceWindow{}, fmt.Errorf("failed to parse the end of the window %s: %w", window, err)
+	}
+	if end.Before(start) {
+		return MaintenanceWindow{}, fmt.Errorf("the window %s ends before its start", window)
+	}
+	return MaintenanceWindow{Start: start, End: end}, nil
+}
+
+// isAnnounced returns true when the timestamp is within one of the maintenance windows.
+func isAnnounced(windows []MaintenanceWindow, timestamp time.Time) bool {
+	for _, window := range windows {
+		if !timestamp.Before(window.Start) && !timestamp.After(window.End) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package proxy_admin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMaintenanceWindow(t *testing.T) {
+	tests := []struct {
+		name   string
+		window string
+		valid  bool
+	}{
+		{name: "window", window: "2024-05-01T14:00:00Z/2024-05-01T16:00:00Z", valid: true},
+		{name: "offset", window: "2024-05-01T14:00:00+02:00/2024-05-01T16:00:00+02:00", valid: true},
+		{name: "single timestamp", window: "2024-05-01T14:00:00Z", valid: false},
+		{name: "not a timestamp", window: "2024-05-01/2024-05-02", valid: false},
+		{name: "reversed", window: "2024-05-01T16:00:00Z/2024-05-01T14:00:00Z", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := parseMaintenanceWindow(test.window); (err == nil) != test.valid {
+				t.Errorf("Failed %s: expected valid %t but got %v", test.name, test.valid, err)
+			}
+		})
+	}
+}
+
+func TestIsAnnounced(t *testing.T) {
+	window, err := parseMaintenanceWindow("2024-05-01T14:00:00Z/2024-05-01T16:00:00Z")
+	if err != nil {
+		t.Fatalf("failed to parse the window: %v", err)
+	}
+	windows := []MaintenanceWindow{window}
+
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		expected  bool
+	}{
+		{name: "before", timestamp: window.Start.Add(-time.Second), expected: false},
+		{name: "start", timestamp: window.Start, expected: true},
+		{name: "within", timestamp: window.Start.Add(time.Hour), expected: true},
+		{name: "end", timestamp: window.End, expected: true},
+		{name: "after", timestamp: window.End.Add(time.Second), expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isAnnounced(windows, test.timestamp); got != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, got)
+			}
+		})
+	}
+
+	if isAnnounced(nil, window.Start) {
+		t.Errorf("Failed no window: expected every change to be unannounced")
+	}
+}
@@ -0,0 +1,90 @@
+package replicas
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodesFlagName            = "nodes"
+	ConfirmationsFlagName    = "confirmations"
+	StartBlockHeightFlagName = "start.block.height"
+	BlockRangeFlagName       = "block.range"
+)
+
+// Node is an execution node of the chain, the first one being the reference the others are compared with.
+type Node struct {
+	Name string
+	URL  string
+}
+
+type CLIConfig struct {
+	Nodes []Node
+
+	// Confirmations is the number of blocks behind the lowest head of the nodes before a height is compared, to skip the unsafe reorgs.
+	Confirmations    uint64
+	StartBlockHeight uint64
+	BlockRange       uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		Confirmations:    ctx.Uint64(ConfirmationsFlagName),
+		StartBlockHeight: ctx.Uint64(StartBlockHeightFlagName),
+		BlockRange:       ctx.Uint64(BlockRangeFlagName),
+	}
+
+	names := make(map[string]bool)
+	for _, node := range ctx.StringSlice(NodesFlagName) {
+		name, url, err := util.ParseNamedValue(node, "name=url")
+		if err != nil {
+			return cfg, err
+		}
+		if names[name] {
+			return cfg, fmt.Errorf("duplicated node %s", name)
+		}
+		names[name] = true
+		cfg.Nodes = append(cfg.Nodes, Node{Name: name, URL: url})
+	}
+	if len(cfg.Nodes) < 2 {
+		return cfg, fmt.Errorf("--%s: at least two nodes are compared", NodesFlagName)
+	}
+
+	if cfg.BlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", BlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     NodesFlagName,
+			Usage:    "Two or more execution nodes formatted via `name=url`, the first one being the reference",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "NODES"),
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:    ConfirmationsFlagName,
+			Usage:   "Number of blocks behind the lowest head of the nodes before a height is compared",
+			Value:   10,
+			EnvVars: opservice.PrefixEnvVar(envVar, "CONFIRMATIONS"),
+		},
+		&cli.Uint64Flag{
+			Name:    StartBlockHeightFlagName,
+			Usage:   "Starting height to compare, the lowest confirmed height of the nodes when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    BlockRangeFlagName,
+			Usage:   "Max number of heights compared per iteration",
+			Value:   100,
+			EnvVars: opservice.PrefixEnvVar(envVar, "BLOCK_RANGE"),
+		},
+	}
+}
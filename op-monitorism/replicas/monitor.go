@@ -0,0 +1,251 @@
+package replicas
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "replicas"
+	MetricsNamespace = "replicas_mon"
+
+	// DivergedRule is the rule of the alert fired when a replica diverges from the reference, the entity being the replica.
+	DivergedRule = "replica diverged from the reference"
+)
+
+type node struct {
+	Node
+	client *ethclient.Client
+
+	// lastAgreed is the highest height where the node agreed with the reference, the genesis being assumed agreed.
+	lastAgreed uint64
+
+	// firstDivergent is the first height where the node diverged from the reference, 0 when it agrees.
+	firstDivergent uint64
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	// reference is the node the replicas are compared with.
+	reference *node
+	replicas  []*node
+
+	confirmations uint64
+	nextHeight    uint64
+	blockRange    uint64
+
+	// metrics
+	blockNumber         *prometheus.GaugeVec
+	diverged            *prometheus.GaugeVec
+	firstDivergentBlock *prometheus.GaugeVec
+	divergences         *prometheus.CounterVec
+	checkedBlockNumber  prometheus.Gauge
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating replicas monitor...")
+
+	nodes := make([]*node, len(cfg.Nodes))
+	for i, n := range cfg.Nodes {
+		client, err := ethclient.Dial(n.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial the node %s: %w", n.Name, err)
+		}
+		nodes[i] = &node{Node: n, client: client}
+	}
+	log.Info("configured nodes", "reference", cfg.Nodes[0].Name, "replicas", len(cfg.Nodes)-1, "start", cfg.StartBlockHeight, "confirmations", cfg.Confirmations)
+
+	return &Monitor{
+		log: log,
+
+		reference: nodes[0],
+		replicas:  nodes[1:],
+
+		confirmations: cfg.Confirmations,
+		nextHeight:    cfg.StartBlockHeight,
+		blockRange:    cfg.BlockRange,
+
+		blockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "blockNumber",
+			Help:      "latest block number of the node",
+		}, []string{"node"}),
+		diverged: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "diverged",
+			Help:      "1 if the replica diverged from the reference, 0 otherwise",
+		}, []string{"node"}),
+		firstDivergentBlock: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "firstDivergentBlock",
+			Help:      "first block number where the replica diverged from the reference, 0 when it agrees",
+		}, []string{"node"}),
+		divergences: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "divergences",
+			Help:      "number of divergences of the replica from the reference by field (hash, stateRoot, receiptsRoot, transactionsRoot)",
+		}, []string{"node", "field"}),
+		checkedBlockNumber: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "checkedBlockNumber",
+			Help:      "highest height compared between the nodes",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	// the heights are compared up to the lowest confirmed head, a replica behind delays the comparison of the others.
+	var lowest uint64
+	for i, n := range append([]*node{m.reference}, m.replicas...) {
+		latest, err := n.client.BlockNumber(ctx)
+		if err != nil {
+			m.log.Error("failed to query latest block number", "node", n.Name, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues(n.Name, "blockNumber").Inc()
+			return
+		}
+		m.blockNumber.WithLabelValues(n.Name).Set(float64(latest))
+		if i == 0 || latest < lowest {
+			lowest = latest
+		}
+	}
+	if lowest < m.confirmations {
+		return
+	}
+	confirmed := lowest - m.confirmations
+	if m.nextHeight == 0 {
+		m.nextHeight = confirmed
+	}
+
+	toHeight := min(confirmed, m.nextHeight+m.blockRange-1)
+	for height := m.nextHeight; height <= toHeight; height++ {
+		if err := m.compareHeight(ctx, height); err != nil {
+			m.log.Error("failed to compare the height", "height", height, "err", err)
+			return
+		}
+		m.checkedBlockNumber.Set(float64(height))
+		m.nextHeight = height + 1
+	}
+}
+
+// compareHeight compares the header of every replica at the height with the one of the reference.
+func (m *Monitor) compareHeight(ctx context.Context, height uint64) error {
+	expected, err := m.header(ctx, m.reference, height)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range m.replicas {
+		header, err := m.header(ctx, n, height)
+		if err != nil {
+			return err
+		}
+
+		fields := divergentFields(expected, header)
+		if len(fields) == 0 {
+			if n.firstDivergent != 0 {
+				m.log.Info("replica agrees again with the reference", "node", n.Name, "height", height, "first_divergent", n.firstDivergent)
+			}
+			n.lastAgreed, n.firstDivergent = height, 0
+		} else if n.firstDivergent == 0 {
+			first, err := m.firstDivergence(ctx, n, n.lastAgreed, height)
+			if err != nil {
+				return err
+			}
+			n.firstDivergent = first
+			for _, field := range fields {
+				m.divergences.WithLabelValues(n.Name, field).Inc()
+			}
+			m.log.Error("replica diverged from the reference", "node", n.Name, "reference", m.reference.Name, "first_divergent", first, "height", height, "fields", fields,
+				"state_root", header.Root, "expected_state_root", expected.Root, "receipts_root", header.ReceiptHash, "expected_receipts_root", expected.ReceiptHash)
+		}
+
+		m.diverged.WithLabelValues(n.Name).Set(util.BoolToFloat(n.firstDivergent != 0))
+		m.Update(ctx, alerts.Alert{
+			Monitor:  MonitorName,
+			Rule:     DivergedRule,
+			Priority: "P1",
+			Entity:   n.Name,
+			Summary:  fmt.Sprintf("replica %s diverged from %s at block %d", n.Name, m.reference.Name, n.firstDivergent),
+		}, n.firstDivergent != 0)
+		m.firstDivergentBlock.WithLabelValues(n.Name).Set(float64(n.firstDivergent))
+	}
+	return nil
+}
+
+// firstDivergence returns the first height between the agreed and the divergent heights where the replica diverges from the reference.
+// The block hashes commit to the parent hash, once diverged the chains don't agree anymore, so the search is a bisection.
+func (m *Monitor) firstDivergence(ctx context.Context, n *node, agreed uint64, divergent uint64) (uint64, error) {
+	for agreed+1 < divergent {
+		middle := agreed + (divergent-agreed)/2
+		expected, err := m.header(ctx, m.reference, middle)
+		if err != nil {
+			return 0, err
+		}
+		header, err := m.header(ctx, n, middle)
+		if err != nil {
+			return 0, err
+		}
+		if expected.Hash() == header.Hash() {
+			agreed = middle
+		} else {
+			divergent = middle
+		}
+	}
+	return divergent, nil
+}
+
+func (m *Monitor) header(ctx context.Context, n *node, height uint64) (*types.Header, error) {
+	header, err := n.client.HeaderByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		m.unexpectedRpcErrors.WithLabelValues(n.Name, "headerByNumber").Inc()
+		return nil, fmt.Errorf("failed to query the header of the node %s: %w", n.Name, err)
+	}
+	return header, nil
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.reference.client.Close()
+	for _, n := range m.replicas {
+		n.client.Close()
+	}
+	return nil
+}
+
+// divergentFields returns the fields of the header differing from the expected one, the hash covering the fields not compared individually.
+func divergentFields(expected *types.Header, header *types.Header) []string {
+	var fields []string
+	if expected.Hash() != header.Hash() {
+		fields = append(fields, "hash")
+	}
+	if expected.Root != header.Root {
+		fields = append(fields, "stateRoot")
+	}
+	if expected.ReceiptHash != header.ReceiptHash {
+		fields = append(fields, "receiptsRoot")
+	}
+	if expected.TxHash != header.TxHash {
+		fields = append(fields, "transactionsRoot")
+	}
+	return fields
+}
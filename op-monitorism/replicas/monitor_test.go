@@ -0,0 +1,133 @@
+package replicas
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDivergentFields(t *testing.T) {
+	expected := &types.Header{Number: big.NewInt(1), Root: common.HexToHash("0x1"), ReceiptHash: common.HexToHash("0x2"), TxHash: common.HexToHash("0x3")}
+
+	tests := []struct {
+		name     string
+		header   types.Header
+		expected []string
+	}{
+		{name: "same header", header: *expected},
+		{name: "different state root", header: types.Header{Number: big.NewInt(1), Root: common.HexToHash("0xa"), ReceiptHash: common.HexToHash("0x2"), TxHash: common.HexToHash("0x3")}, expected: []string{"hash", "stateRoot"}},
+		{name: "different receipts", header: types.Header{Number: big.NewInt(1), Root: common.HexToHash("0x1"), ReceiptHash: common.HexToHash("0xa"), TxHash: common.HexToHash("0x3")}, expected: []string{"hash", "receiptsRoot"}},
+		{name: "different transactions", header: types.Header{Number: big.NewInt(1), Root: common.HexToHash("0xa"), ReceiptHash: common.HexToHash("0xb"), TxHash: common.HexToHash("0xc")}, expected: []string{"hash", "stateRoot", "receiptsRoot", "transactionsRoot"}},
+		{name: "different extra data", header: types.Header{Number: big.NewInt(1), Root: common.HexToHash("0x1"), ReceiptHash: common.HexToHash("0x2"), TxHash: common.HexToHash("0x3"), Extra: []byte{1}}, expected: []string{"hash"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := divergentFields(expected, &test.header); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("Failed %s: expected %v but got %v", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+// newTestMonitor returns the monitor of the replica against the reference from the height 1, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, reference *fake.Node, replica *fake.Node) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{Nodes: []Node{{Name: "reference", URL: reference.URL}, {Name: "replica", URL: replica.URL}}, StartBlockHeight: 1, BlockRange: 100}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	reference, replica := fake.NewNode(t), fake.NewNode(t)
+	for _, n := range []*fake.Node{reference, replica} {
+		n.AddBlock(&types.Header{})
+		n.AddBlock(&types.Header{Root: common.HexToHash("0x1")})
+	}
+	m, _ := newTestMonitor(t, reference, replica)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if height, checked, diverged := testutil.ToFloat64(m.blockNumber.WithLabelValues("replica")), testutil.ToFloat64(m.checkedBlockNumber), testutil.ToFloat64(m.diverged.WithLabelValues("replica")); height != 1 || checked != 1 || diverged != 0 {
+		t.Errorf("expected the replica agreeing at the block 1 but got %v, %v and %v", height, checked, diverged)
+	}
+
+	// a replica with another state root is counted once by divergent field.
+	reference.AddBlock(&types.Header{Root: common.HexToHash("0x2")})
+	replica.AddBlock(&types.Header{Root: common.HexToHash("0xbad")})
+	m.Run(ctx)
+	reference.AddBlock(&types.Header{Root: common.HexToHash("0x3")})
+	replica.AddBlock(&types.Header{Root: common.HexToHash("0x3")})
+	m.Run(ctx)
+	if hash, stateRoot, receiptsRoot := testutil.ToFloat64(m.divergences.WithLabelValues("replica", "hash")), testutil.ToFloat64(m.divergences.WithLabelValues("replica", "stateRoot")), testutil.ToFloat64(m.divergences.WithLabelValues("replica", "receiptsRoot")); hash != 1 || stateRoot != 1 || receiptsRoot != 0 {
+		t.Errorf("expected 1 divergence of the hash and the state root but got %v, %v and %v", hash, stateRoot, receiptsRoot)
+	}
+	if diverged, first := testutil.ToFloat64(m.diverged.WithLabelValues("replica")), testutil.ToFloat64(m.firstDivergentBlock.WithLabelValues("replica")); diverged != 1 || first != 2 {
+		t.Errorf("expected the replica diverged at the block 2 but got %v and %v", diverged, first)
+	}
+
+	// the replica reorged to the chain of the reference agrees again.
+	replica.AddBlock(&types.Header{Number: big.NewInt(2), Root: common.HexToHash("0x2")})
+	replica.AddBlock(&types.Header{Root: common.HexToHash("0x3")})
+	for _, n := range []*fake.Node{reference, replica} {
+		n.AddBlock(&types.Header{Root: common.HexToHash("0x4")})
+	}
+	m.Run(ctx)
+	if diverged, first := testutil.ToFloat64(m.diverged.WithLabelValues("replica")), testutil.ToFloat64(m.firstDivergentBlock.WithLabelValues("replica")); diverged != 0 || first != 0 {
+		t.Errorf("expected the replica agreeing again but got %v and %v", diverged, first)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	reference, replica := fake.NewNode(t), fake.NewNode(t)
+	for _, n := range []*fake.Node{reference, replica} {
+		n.AddBlock(&types.Header{})
+		n.AddBlock(&types.Header{Root: common.HexToHash("0x1")})
+	}
+	m, notifier := newTestMonitor(t, reference, replica)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a replica with another state root diverges from it onwards, firing once.
+	reference.AddBlock(&types.Header{Root: common.HexToHash("0x2")})
+	replica.AddBlock(&types.Header{Root: common.HexToHash("0xbad")})
+	m.Run(ctx)
+	reference.AddBlock(&types.Header{Root: common.HexToHash("0x3")})
+	replica.AddBlock(&types.Header{Root: common.HexToHash("0x3")})
+	m.Run(ctx)
+	if alerts := notifier.Alerts(DivergedRule); len(alerts) != 1 || alerts[0].Entity != "replica" || alerts[0].Priority != "P1" || !strings.HasSuffix(alerts[0].Summary, "at block 2") {
+		t.Fatalf("expected the diverged alert once but got %v", alerts)
+	}
+
+	// the replica reorged to the chain of the reference agrees again.
+	replica.AddBlock(&types.Header{Number: big.NewInt(2), Root: common.HexToHash("0x2")})
+	replica.AddBlock(&types.Header{Root: common.HexToHash("0x3")})
+	for _, n := range []*fake.Node{reference, replica} {
+		n.AddBlock(&types.Header{Root: common.HexToHash("0x4")})
+	}
+	m.Run(ctx)
+	if firing := notifier.Firing(DivergedRule); len(firing) != 0 {
+		t.Fatalf("expected the diverged alert resolved but got %v", firing)
+	}
+}
@@ -0,0 +1,62 @@
+package roles
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodeURLFlagName     = "node.url"
+	RolesConfigFlagName = "roles.config"
+)
+
+type CLIConfig struct {
+	NodeURL string
+
+	// Roles are the roles of the contracts read at each iteration.
+	Roles []Role
+	// Names are the names of the address book, used to label the holders of the roles.
+	Names map[common.Address]string
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{NodeURL: ctx.String(NodeURLFlagName), Names: make(map[common.Address]string)}
+
+	config, err := ReadConfigFile(ctx.String(RolesConfigFlagName))
+	if err != nil {
+		return cfg, err
+	}
+	if cfg.Roles, err = config.roles(); err != nil {
+		return cfg, fmt.Errorf("--%s: %w", RolesConfigFlagName, err)
+	}
+	if len(cfg.Roles) == 0 {
+		return cfg, fmt.Errorf("--%s: no contract configured", RolesConfigFlagName)
+	}
+	for name, address := range config.Addresses {
+		cfg.Names[address] = name
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    NodeURLFlagName,
+			Usage:   "Node URL of the chain of the contracts",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     RolesConfigFlagName,
+			Usage:    "Path to a YAML file with the contracts, their roles and the expected holders",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "ROLES_CONFIG"),
+			Required: true,
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package roles
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// RolesConfiguration is the content of the YAML file given with `--roles.config`.
+// The expected holder of a role is either a hex-encoded address or a name of the address book.
+//
+//	addresses:
+//	  Security Council: 0xc2819DC788505Aac350142A7A707BF9D03E3Bd03
+//	contracts:
+//	  - name: OptimismPortal
+//	    address: 0xbEb5Fc579115071764c7423A4f12eDde41f106Ed
+//	    roles:
+//	      guardian: Security Council
+//	  - name: SystemConfig
+//	    address: 0x229047fed2591dbec1eF1118d64F7aF3dB9EB290
+//	    roles:
+//	      owner: 0x847B5c174615B1B7fDF770882256e2D3E95b9D92
+type RolesConfiguration struct {
+	// Addresses is the address book, the names of the addresses holding the roles.
+	Addresses map[string]common.Address `yaml:"addresses"`
+	Contracts []ContractConfig          `yaml:"contracts"`
+}
+
+// ContractConfig is a contract along with its roles, the name of a no-argument view method returning an address (e.g. `owner`, `GUARDIAN`).
+type ContractConfig struct {
+	Name    string            `yaml:"name"`
+	Address common.Address    `yaml:"address"`
+	Roles   map[string]string `yaml:"roles"`
+}
+
+// Role is a role of a contract along with its expected holder.
+type Role struct {
+	Contract string
+	Address  common.Address
+	Method   string
+
+	// Expected is the expected holder of the role, the first one observed is used when nil.
+	Expected *common.Address
+}
+
+// ReadConfigFile reads the contracts and the expected holders of their roles from a YAML file.
+func ReadConfigFile(path string) (RolesConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return RolesConfiguration{}, fmt.Errorf("failed to read the roles config file %s: %w", path, err)
+	}
+
+	var config RolesConfiguration
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return RolesConfiguration{}, fmt.Errorf("failed to parse the roles config file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// roles returns the roles of the contracts ordered by contract and method, resolving the expected holders through the address book.
+// An empty expected holder is accepted, the first holder observed is then expected.
+func (c RolesConfiguration) roles() ([]Role, error) {
+	names := make(map[string]bool)
+	var roles []Role
+	for _, contract := range c.Contracts {
+		if len(contract.Name) == 0 {
+			return nil, fmt.Errorf("the contract %s has no name", contract.Address)
+		}
+		if names[contract.Name] {
+			return nil, fmt.Errorf("duplicated contract %s", contract.Name)
+		}
+		names[contract.Name] = true
+		if len(contract.Roles) == 0 {
+			return nil, fmt.Errorf("the contract %s has no role", contract.Name)
+		}
+
+		methods := make([]string, 0, len(contract.Roles))
+		for method := range contract.Roles {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			role := Role{Contract: contract.Name, Address: contract.Address, Method: method}
+			expected, err := c.resolve(contract.Roles[method])
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve the role %s of the contract %s: %w", method, contract.Name, err)
+			}
+			role.Expected = expected
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+// resolve returns the address of a holder, either hex-encoded or a name of the address book, nil when empty.
+func (c RolesConfiguration) resolve(holder string) (*common.Address, error) {
+	if len(holder) == 0 {
+		return nil, nil
+	}
+	if address, ok := c.Addresses[holder]; ok {
+		return &address, nil
+	}
+	if !common.IsHexAddress(holder) {
+		return nil, fmt.Errorf("%s is neither a hex-encoded address nor a name of the address book", holder)
+	}
+	address := common.HexToAddress(holder)
+	return &address, nil
+}
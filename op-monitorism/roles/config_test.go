@@ -0,0 +1,83 @@
+package roles
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+const testConfig = `
+addresses:
+  Security Council: 0xc2819DC788505Aac350142A7A707BF9D03E3Bd03
+contracts:
+  - name: OptimismPortal
+    address: 0xbEb5Fc579115071764c7423A4f12eDde41f106Ed
+    roles:
+      guardian: Security Council
+      GUARDIAN: Security Council
+  - name: SystemConfig
+    address: 0x229047fed2591dbec1eF1118d64F7aF3dB9EB290
+    roles:
+      owner: 0x847B5c174615B1B7fDF770882256e2D3E95b9D92
+      admin: ""
+`
+
+func TestRoles(t *testing.T) {
+	var config RolesConfiguration
+	if err := yaml.Unmarshal([]byte(testConfig), &config); err != nil {
+		t.Fatalf("failed to parse the config: %v", err)
+	}
+	roles, err := config.roles()
+	if err != nil {
+		t.Fatalf("failed to resolve the roles: %v", err)
+	}
+
+	council := common.HexToAddress("0xc2819DC788505Aac350142A7A707BF9D03E3Bd03")
+	owner := common.HexToAddress("0x847B5c174615B1B7fDF770882256e2D3E95b9D92")
+	expected := []struct {
+		contract string
+		method   string
+		expected *common.Address
+	}{
+		{contract: "OptimismPortal", method: "GUARDIAN", expected: &council},
+		{contract: "OptimismPortal", method: "guardian", expected: &council},
+		{contract: "SystemConfig", method: "admin", expected: nil},
+		{contract: "SystemConfig", method: "owner", expected: &owner},
+	}
+	if len(roles) != len(expected) {
+		t.Fatalf("expected %d roles but got %d", len(expected), len(roles))
+	}
+	for i, test := range expected {
+		role := roles[i]
+		if role.Contract != test.contract || role.Method != test.method {
+			t.Errorf("Failed role %d: expected %s.%s but got %s.%s", i, test.contract, test.method, role.Contract, role.Method)
+		}
+		if (role.Expected == nil) != (test.expected == nil) || (role.Expected != nil && *role.Expected != *test.expected) {
+			t.Errorf("Failed %s.%s: expected %v but got %v", test.contract, test.method, test.expected, role.Expected)
+		}
+	}
+}
+
+func TestRolesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config RolesConfiguration
+	}{
+		{name: "unknown holder", config: RolesConfiguration{Contracts: []ContractConfig{{Name: "OptimismPortal", Roles: map[string]string{"guardian": "Unknown"}}}}},
+		{name: "no name", config: RolesConfiguration{Contracts: []ContractConfig{{Roles: map[string]string{"guardian": ""}}}}},
+		{name: "no role", config: RolesConfiguration{Contracts: []ContractConfig{{Name: "OptimismPortal"}}}},
+		{name: "duplicated contract", config: RolesConfiguration{Contracts: []ContractConfig{
+			{Name: "OptimismPortal", Roles: map[string]string{"guardian": ""}},
+			{Name: "OptimismPortal", Roles: map[string]string{"guardian": ""}},
+		}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.config.roles(); err == nil {
+				t.Errorf("Failed %s: expected an error", test.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package roles
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "roles"
+	MetricsNamespace = "roles_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the contract and the role.
+	ChangedRule  = "role changed"
+	MismatchRule = "unexpected holder of the role"
+)
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	client *ethclient.Client
+
+	roles []Role
+	names map[common.Address]string
+
+	// holders are the holders of the roles observed at the previous iteration, by role index.
+	holders map[int]common.Address
+
+	// metrics
+	holder              *prometheus.GaugeVec
+	roleMismatch        *prometheus.GaugeVec
+	roleChanges         *prometheus.CounterVec
+	checkedBlockNumber  prometheus.Gauge
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating roles monitor...")
+
+	client, err := ethclient.Dial(cfg.NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial node: %w", err)
+	}
+
+	for _, role := range cfg.Roles {
+		log.Info("configured role", "contract", role.Contract, "address", role.Address, "role", role.Method, "expected", role.Expected)
+	}
+
+	return &Monitor{
+		log: log,
+
+		client: client,
+
+		roles:   cfg.Roles,
+		names:   cfg.Names,
+		holders: make(map[int]common.Address),
+
+		holder: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "holder",
+			Help:      "holder of the role of the contract, named from the address book",
+		}, []string{"contract", "role", "address", "name"}),
+		roleMismatch: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "roleMismatch",
+			Help:      "1 if the holder of the role is not the expected one, 0 otherwise",
+		}, []string{"contract", "role"}),
+		roleChanges: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "roleChanges",
+			Help:      "number of changes of the holder of the role observed between two iterations",
+		}, []string{"contract", "role"}),
+		checkedBlockNumber: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "checkedBlockNumber",
+			Help:      "block height at which the roles were last read",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	// The roles are read at the same block so the holders are consistent with each other.
+	blockNumber, err := m.client.BlockNumber(ctx)
+	if err != nil {
+		m.log.Error("failed to query latest block number", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("node", "blockNumber").Inc()
+		return
+	}
+
+	for i, role := range m.roles {
+		holder, err := m.readRole(ctx, role, new(big.Int).SetUint64(blockNumber))
+		if err != nil {
+			m.log.Error("failed to read the role", "contract", role.Contract, "role", role.Method, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues(role.Contract, role.Method).Inc()
+			continue
+		}
+
+		previous, known := m.holders[i]
+		if known && previous != holder {
+			m.log.Warn("role changed", "contract", role.Contract, "role", role.Method, "previous", previous, "holder", holder, "name", m.names[holder], "block", blockNumber)
+			m.roleChanges.WithLabelValues(role.Contract, role.Method).Inc()
+			m.holder.DeleteLabelValues(role.Contract, role.Method, previous.String(), m.names[previous])
+			m.Emit(ctx, alert(ChangedRule, "P1", role, fmt.Sprintf("%s of %s changed from %s to %s", role.Method, role.Contract, previous, holder)))
+		}
+		m.holders[i] = holder
+		m.holder.WithLabelValues(role.Contract, role.Method, holder.String(), m.names[holder]).Set(1)
+
+		if role.Expected == nil { // the first holder observed is expected.
+			expected := holder
+			m.roles[i].Expected = &expected
+			m.log.Info("expecting the current holder of the role", "contract", role.Contract, "role", role.Method, "holder", holder, "name", m.names[holder])
+			role.Expected = &expected
+		}
+		mismatch := holder != *role.Expected
+		if mismatch {
+			m.log.Error("unexpected holder of the role", "contract", role.Contract, "role", role.Method, "expected", *role.Expected, "expected_name", m.names[*role.Expected], "holder", holder, "name", m.names[holder])
+		}
+		m.roleMismatch.WithLabelValues(role.Contract, role.Method).Set(util.BoolToFloat(mismatch))
+		m.Update(ctx, alert(MismatchRule, "P0", role, fmt.Sprintf("%s of %s held by %s instead of %s", role.Method, role.Contract, holder, *role.Expected)), mismatch)
+	}
+
+	m.checkedBlockNumber.Set(float64(blockNumber))
+}
+
+// alert returns the alert of the rule about the role.
+func alert(rule, priority string, role Role, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   role.Contract + "/" + role.Method,
+		Summary:  summary,
+		Labels:   map[string]string{"contract": role.Contract, "role": role.Method},
+	}
+}
+
+// readRole calls the no-argument method of the role, returning an address.
+// The call is sent from the zero address, allowing `admin()` on the proxies only callable by their admin or by `eth_call`.
+func (m *Monitor) readRole(ctx context.Context, role Role, blockNumber *big.Int) (common.Address, error) {
+	out, err := m.client.CallContract(ctx, ethereum.CallMsg{To: &role.Address, Data: roleSelector(role.Method)}, blockNumber)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to call `%s()`: %w", role.Method, err)
+	}
+	return decodeAddress(out)
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.client.Close()
+	return nil
+}
+
+// roleSelector returns the selector of the no-argument method of a role.
+func roleSelector(method string) []byte {
+	return crypto.Keccak256([]byte(method + "()"))[:4]
+}
+
+// decodeAddress decodes an ABI-encoded address, the output of a method not returning an address is rejected.
+func decodeAddress(out []byte) (common.Address, error) {
+	if len(out) != 32 {
+		return common.Address{}, fmt.Errorf("unexpected output length %d, expected an address", len(out))
+	}
+	if common.BytesToHash(out[:12]) != (common.Hash{}) {
+		return common.Address{}, fmt.Errorf("unexpected output %x, expected an address", out)
+	}
+	return common.BytesToAddress(out[12:]), nil
+}
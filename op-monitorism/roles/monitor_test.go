@@ -0,0 +1,131 @@
+package roles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var portal = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+
+func TestDecodeAddress(t *testing.T) {
+	address := common.HexToAddress("0xc2819DC788505Aac350142A7A707BF9D03E3Bd03")
+
+	tests := []struct {
+		name     string
+		out      []byte
+		expected common.Address
+		valid    bool
+	}{
+		{name: "address", out: common.LeftPadBytes(address.Bytes(), 32), expected: address, valid: true},
+		{name: "zero address", out: make([]byte, 32), expected: common.Address{}, valid: true},
+		{name: "no output", out: nil, valid: false},
+		{name: "not an address", out: common.MaxHash.Bytes(), valid: false},
+		{name: "several values", out: make([]byte, 64), valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := decodeAddress(test.out)
+			if (err == nil) != test.valid {
+				t.Errorf("Failed %s: expected valid %t but got %v", test.name, test.valid, err)
+			}
+			if test.valid && got != test.expected {
+				t.Errorf("Failed %s: expected %s but got %s", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+// newTestMonitor returns the monitor of the guardian of the portal, expecting the first holder observed, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *fake.Node) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{NodeURL: node.URL, Roles: []Role{{Contract: "OptimismPortal", Address: portal, Method: "guardian"}}, Names: map[common.Address]string{}}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{})
+	guardian, attacker := common.HexToAddress("0x09f7150D8c019BeF34450d6920f6B3608ceFdAf2"), common.HexToAddress("0x02")
+	holder := guardian
+	node.HandleCall(portal, func(_ []byte) ([]byte, error) {
+		return common.BytesToHash(holder.Bytes()).Bytes(), nil
+	})
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	// the holder of the role is reported at the checked block.
+	m.Run(ctx)
+	if held, checked := testutil.ToFloat64(m.holder.WithLabelValues("OptimismPortal", "guardian", guardian.String(), "")), testutil.ToFloat64(m.checkedBlockNumber); held != 1 || checked != 0 {
+		t.Errorf("expected the guardian held at the block 0 but got %v and %v", held, checked)
+	}
+
+	// a new holder replaces the series of the previous one, and is counted as a change and a mismatch.
+	holder = attacker
+	node.AddBlock(&types.Header{})
+	m.Run(ctx)
+	if count := testutil.CollectAndCount(m.holder); count != 1 {
+		t.Errorf("expected the series of the new holder only but got %d", count)
+	}
+	if changes, mismatch, checked := testutil.ToFloat64(m.roleChanges.WithLabelValues("OptimismPortal", "guardian")), testutil.ToFloat64(m.roleMismatch.WithLabelValues("OptimismPortal", "guardian")), testutil.ToFloat64(m.checkedBlockNumber); changes != 1 || mismatch != 1 || checked != 1 {
+		t.Errorf("expected the guardian changed and mismatched at the block 1 but got %v, %v and %v", changes, mismatch, checked)
+	}
+
+	// a failed read is counted as an rpc error.
+	node.HandleCall(portal, func(_ []byte) ([]byte, error) {
+		return nil, fake.Revert(nil)
+	})
+	m.Run(ctx)
+	if errs := testutil.ToFloat64(m.unexpectedRpcErrors.WithLabelValues("OptimismPortal", "guardian")); errs != 1 {
+		t.Errorf("expected 1 rpc error but got %v", errs)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{})
+	guardian, attacker := common.HexToAddress("0x09f7150D8c019BeF34450d6920f6B3608ceFdAf2"), common.HexToAddress("0x02")
+	holder := guardian
+	node.HandleCall(portal, func(_ []byte) ([]byte, error) {
+		return common.BytesToHash(holder.Bytes()).Bytes(), nil
+	})
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a new holder fires the change once, and the mismatch until the expected holder is back.
+	holder = attacker
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(ChangedRule); len(alerts) != 1 || alerts[0].Entity != "OptimismPortal/guardian" || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the changed alert once but got %v", alerts)
+	}
+	if alerts := notifier.Alerts(MismatchRule); len(alerts) != 1 || alerts[0].Priority != "P0" || alerts[0].Labels["role"] != "guardian" {
+		t.Fatalf("expected the mismatch alert once but got %v", alerts)
+	}
+	holder = guardian
+	m.Run(ctx)
+	if firing := notifier.Firing(MismatchRule); len(firing) != 0 || len(notifier.Alerts(ChangedRule)) != 2 {
+		t.Fatalf("expected the mismatch alert resolved but got %v", firing)
+	}
+}
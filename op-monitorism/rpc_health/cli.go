@@ -0,0 +1,116 @@
+package rpc_health
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	EndpointsFlagName      = "endpoints"
+	ProbeTimeoutFlagName   = "probe.timeout"
+	LogsBlockRangeFlagName = "logs.block.range"
+	CallAddressFlagName    = "call.address"
+	CallDataFlagName       = "call.data"
+)
+
+// Endpoint is an RPC endpoint probed by the monitor.
+type Endpoint struct {
+	Name string
+	URL  string
+}
+
+type CLIConfig struct {
+	Endpoints []Endpoint
+
+	ProbeTimeout   time.Duration
+	LogsBlockRange uint64
+
+	// CallAddress and CallData are the target and the calldata of the `eth_call` probe.
+	CallAddress common.Address
+	CallData    []byte
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		ProbeTimeout:   ctx.Duration(ProbeTimeoutFlagName),
+		LogsBlockRange: ctx.Uint64(LogsBlockRangeFlagName),
+	}
+
+	for _, endpoint := range ctx.StringSlice(EndpointsFlagName) {
+		parsed, err := parseEndpoint(endpoint)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Endpoints = append(cfg.Endpoints, parsed)
+	}
+
+	if cfg.LogsBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", LogsBlockRangeFlagName)
+	}
+
+	callAddress := ctx.String(CallAddressFlagName)
+	if !common.IsHexAddress(callAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", CallAddressFlagName)
+	}
+	cfg.CallAddress = common.HexToAddress(callAddress)
+
+	callData, err := hexutil.Decode(ctx.String(CallDataFlagName))
+	if err != nil {
+		return cfg, fmt.Errorf("--%s is not hex-encoded: %w", CallDataFlagName, err)
+	}
+	cfg.CallData = callData
+
+	return cfg, nil
+}
+
+// parseEndpoint parses an endpoint formatted via `name=url`.
+func parseEndpoint(endpoint string) (Endpoint, error) {
+	name, url, err := util.ParseNamedValue(endpoint, "name=url")
+	if err != nil {
+		return Endpoint{}, err
+	}
+	return Endpoint{Name: name, URL: url}, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     EndpointsFlagName,
+			Usage:    "One or more RPC endpoints formatted via `name=url`",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "ENDPOINTS"),
+			Required: true,
+		},
+		&cli.DurationFlag{
+			Name:    ProbeTimeoutFlagName,
+			Usage:   "Timeout of a probe",
+			Value:   5 * time.Second,
+			EnvVars: opservice.PrefixEnvVar(envVar, "PROBE_TIMEOUT"),
+		},
+		&cli.Uint64Flag{
+			Name:    LogsBlockRangeFlagName,
+			Usage:   "Block range of the eth_getLogs probe, ending at the latest block",
+			Value:   10,
+			EnvVars: opservice.PrefixEnvVar(envVar, "LOGS_BLOCK_RANGE"),
+		},
+		&cli.StringFlag{
+			Name:    CallAddressFlagName,
+			Usage:   "Target of the eth_call probe, also used to filter the logs of the eth_getLogs probe",
+			Value:   "0x4200000000000000000000000000000000000015",
+			EnvVars: opservice.PrefixEnvVar(envVar, "CALL_ADDRESS"),
+		},
+		&cli.StringFlag{
+			Name:    CallDataFlagName,
+			Usage:   "Hex-encoded calldata of the eth_call probe",
+			Value:   "0x54fd4d50",
+			EnvVars: opservice.PrefixEnvVar(envVar, "CALL_DATA"),
+		},
+	}
+}
@@ -0,0 +1,261 @@
+package rpc_health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "rpc_health"
+	MetricsNamespace = "rpc_health_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the endpoint and the method.
+	UnavailableRule = "endpoint unavailable"
+	IncapableRule   = "method unsupported by the endpoint"
+)
+
+// probe is a method called on every endpoint, `latest` is the block number reported by the endpoint (0 when unknown).
+type probe struct {
+	method string
+	call   func(ctx context.Context, client *rpc.Client, latest uint64) error
+}
+
+type endpoint struct {
+	Endpoint
+	client *rpc.Client
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	endpoints []*endpoint
+	probes    []probe
+
+	probeTimeout time.Duration
+
+	// metrics
+	available        *prometheus.GaugeVec
+	capable          *prometheus.GaugeVec
+	latency          *prometheus.GaugeVec
+	latencyHistogram *prometheus.HistogramVec
+	failures         *prometheus.CounterVec
+	blockNumber      *prometheus.GaugeVec
+	blocksBehind     *prometheus.GaugeVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating rpc health monitor...")
+
+	endpoints := make([]*endpoint, len(cfg.Endpoints))
+	for i, e := range cfg.Endpoints {
+		// the dial is lazy for http, an endpoint down at startup is reported by the probes.
+		client, err := rpc.DialContext(ctx, e.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial the endpoint %s: %w", e.Name, err)
+		}
+		endpoints[i] = &endpoint{Endpoint: e, client: client}
+		log.Info("configured endpoint", "name", e.Name)
+	}
+
+	return &Monitor{
+		log: log,
+
+		endpoints: endpoints,
+		probes:    newProbes(cfg.LogsBlockRange, cfg.CallAddress, cfg.CallData),
+
+		probeTimeout: cfg.ProbeTimeout,
+
+		available: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "available",
+			Help:      "1 if the endpoint answered the method, with a result or a JSON-RPC error, 0 otherwise",
+		}, []string{"endpoint", "method"}),
+		capable: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "capable",
+			Help:      "1 if the endpoint answered the method with a result, 0 otherwise",
+		}, []string{"endpoint", "method"}),
+		latency: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "latency",
+			Help:      "seconds taken by the last call of the method",
+		}, []string{"endpoint", "method"}),
+		latencyHistogram: m.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Name:      "latencyHistogram",
+			Help:      "seconds taken by the calls of the method",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"endpoint", "method"}),
+		failures: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "failures",
+			Help:      "number of failed calls of the method",
+		}, []string{"endpoint", "method"}),
+		blockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "blockNumber",
+			Help:      "latest block number reported by the endpoint",
+		}, []string{"endpoint"}),
+		blocksBehind: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "blocksBehind",
+			Help:      "number of blocks between the endpoint and the highest block reported by the endpoints",
+		}, []string{"endpoint"}),
+	}, nil
+}
+
+// newProbes returns the battery of methods called on every endpoint.
+func newProbes(logsBlockRange uint64, callAddress common.Address, callData []byte) []probe {
+	blockTag := func(latest uint64) string {
+		if latest == 0 {
+			return "latest"
+		}
+		return hexutil.EncodeUint64(latest)
+	}
+
+	return []probe{
+		{method: "eth_chainId", call: func(ctx context.Context, client *rpc.Client, _ uint64) error {
+			var chainID hexutil.Big
+			return client.CallContext(ctx, &chainID, "eth_chainId")
+		}},
+		{method: "eth_getBlockByNumber", call: func(ctx context.Context, client *rpc.Client, latest uint64) error {
+			var block map[string]interface{}
+			if err := client.CallContext(ctx, &block, "eth_getBlockByNumber", blockTag(latest), false); err != nil {
+				return err
+			}
+			if block == nil {
+				return errors.New("block not found")
+			}
+			return nil
+		}},
+		{method: "eth_getLogs", call: func(ctx context.Context, client *rpc.Client, latest uint64) error {
+			fromBlock := "latest"
+			if latest != 0 {
+				fromBlock = hexutil.EncodeUint64(logsFromBlock(latest, logsBlockRange))
+			}
+			var logs []interface{}
+			return client.CallContext(ctx, &logs, "eth_getLogs", map[string]interface{}{
+				"fromBlock": fromBlock,
+				"toBlock":   blockTag(latest),
+				"address":   callAddress,
+			})
+		}},
+		{method: "eth_call", call: func(ctx context.Context, client *rpc.Client, latest uint64) error {
+			var result hexutil.Bytes
+			return client.CallContext(ctx, &result, "eth_call", map[string]interface{}{
+				"to":   callAddress,
+				"data": hexutil.Bytes(callData),
+			}, blockTag(latest))
+		}},
+	}
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	latest := make(map[string]uint64, len(m.endpoints))
+	highest := uint64(0)
+	for _, e := range m.endpoints {
+		var blockNumber hexutil.Uint64
+		ok := m.runProbe(ctx, e, "eth_blockNumber", func(ctx context.Context) error {
+			return e.client.CallContext(ctx, &blockNumber, "eth_blockNumber")
+		})
+		if ok {
+			latest[e.Name] = uint64(blockNumber)
+			highest = max(highest, uint64(blockNumber))
+			m.blockNumber.WithLabelValues(e.Name).Set(float64(blockNumber))
+		}
+
+		for _, p := range m.probes {
+			m.runProbe(ctx, e, p.method, func(ctx context.Context) error {
+				return p.call(ctx, e.client, uint64(blockNumber))
+			})
+		}
+	}
+
+	for _, e := range m.endpoints {
+		if blockNumber, ok := latest[e.Name]; ok {
+			m.blocksBehind.WithLabelValues(e.Name).Set(float64(highest - blockNumber))
+		}
+	}
+}
+
+// runProbe calls the method on the endpoint within the probe timeout and reports its availability, capability and latency.
+func (m *Monitor) runProbe(ctx context.Context, e *endpoint, method string, call func(ctx context.Context) error) bool {
+	probeCtx, cancel := context.WithTimeout(ctx, m.probeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := call(probeCtx)
+	elapsed := time.Since(start)
+
+	available, capable := classify(err)
+	if !capable {
+		m.log.Warn("probe failed", "endpoint", e.Name, "method", method, "available", available, "err", err)
+		m.failures.WithLabelValues(e.Name, method).Inc()
+	}
+
+	m.available.WithLabelValues(e.Name, method).Set(util.BoolToFloat(available))
+	m.capable.WithLabelValues(e.Name, method).Set(util.BoolToFloat(capable))
+	m.Update(ctx, alert(UnavailableRule, "P1", e, method, fmt.Sprintf("%s not answering %s: %v", e.Name, method, err)), !available)
+	m.Update(ctx, alert(IncapableRule, "P2", e, method, fmt.Sprintf("%s not serving %s: %v", e.Name, method, err)), available && !capable)
+	if available {
+		m.latency.WithLabelValues(e.Name, method).Set(elapsed.Seconds())
+		m.latencyHistogram.WithLabelValues(e.Name, method).Observe(elapsed.Seconds())
+	}
+	return capable
+}
+
+// alert returns the alert of the rule about the method of the endpoint.
+func alert(rule, priority string, e *endpoint, method string, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   e.Name + "/" + method,
+		Summary:  summary,
+		Labels:   map[string]string{"endpoint": e.Name, "method": method},
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	for _, e := range m.endpoints {
+		e.client.Close()
+	}
+	return nil
+}
+
+// classify returns whether the endpoint answered (available), and whether it answered with a result (capable).
+// A JSON-RPC error means the endpoint is up but does not support the call, e.g. a disabled method or a range limit.
+func classify(err error) (available bool, capable bool) {
+	if err == nil {
+		return true, true
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return true, false
+	}
+	return false, false
+}
+
+// logsFromBlock returns the first block of the `eth_getLogs` probe.
+func logsFromBlock(latest uint64, blockRange uint64) uint64 {
+	if latest < blockRange {
+		return 0
+	}
+	return latest - blockRange + 1
+}
@@ -0,0 +1,165 @@
+package rpc_health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type jsonRpcError struct{}
+
+func (e *jsonRpcError) Error() string {
+	return "the method eth_getLogs does not exist/is not available"
+}
+func (e *jsonRpcError) ErrorCode() int { return -32601 }
+
+func TestParseEndpoint(t *testing.T) {
+	e, err := parseEndpoint("provider=https://rpc.example.com/v1?key=a=b")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if e.Name != "provider" || e.URL != "https://rpc.example.com/v1?key=a=b" {
+		t.Errorf("unexpected endpoint %v", e)
+	}
+
+	for _, invalid := range []string{"provider", "=https://rpc.example.com", "provider="} {
+		if _, err := parseEndpoint(invalid); err == nil {
+			t.Errorf("expected an error for %s", invalid)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name              string
+		err               error
+		expectedAvailable bool
+		expectedCapable   bool
+	}{
+		{name: "Result", err: nil, expectedAvailable: true, expectedCapable: true},
+		{name: "JSON-RPC error", err: &jsonRpcError{}, expectedAvailable: true, expectedCapable: false},
+		{name: "Timeout", err: context.DeadlineExceeded, expectedAvailable: false, expectedCapable: false},
+		{name: "Transport error", err: errors.New("connection refused"), expectedAvailable: false, expectedCapable: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			available, capable := classify(test.err)
+			if available != test.expectedAvailable || capable != test.expectedCapable {
+				t.Errorf("Failed %s: expected (%t, %t) but got (%t, %t)", test.name, test.expectedAvailable, test.expectedCapable, available, capable)
+			}
+		})
+	}
+}
+
+func TestLogsFromBlock(t *testing.T) {
+	if from := logsFromBlock(100, 10); from != 91 {
+		t.Errorf("expected 91 but got %d", from)
+	}
+	if from := logsFromBlock(5, 10); from != 0 {
+		t.Errorf("expected 0 but got %d", from)
+	}
+}
+
+// newTestMonitor returns the monitor of the node timing out after 100ms, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *fake.Node) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{Endpoints: []Endpoint{{Name: "node", URL: node.URL}}, ProbeTimeout: 100 * time.Millisecond, LogsBlockRange: 100}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	leader, lagging := fake.NewNode(t), fake.NewNode(t)
+	for i := 0; i < 3; i++ {
+		leader.AddBlock(&types.Header{})
+	}
+	lagging.AddBlock(&types.Header{})
+	lagging.Fail("eth_getLogs", &fake.Error{Code: -32005, Message: "block range too large"})
+	cfg := CLIConfig{Endpoints: []Endpoint{{Name: "leader", URL: leader.URL}, {Name: "lagging", URL: lagging.URL}}, ProbeTimeout: 100 * time.Millisecond, LogsBlockRange: 100}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	m.SetNotifier(&fake.Notifier{})
+
+	// the block numbers are reported, the lagging endpoint behind the highest one.
+	m.Run(context.Background())
+	if leaderHeight, laggingHeight := testutil.ToFloat64(m.blockNumber.WithLabelValues("leader")), testutil.ToFloat64(m.blockNumber.WithLabelValues("lagging")); leaderHeight != 2 || laggingHeight != 0 {
+		t.Errorf("expected the block numbers 2 and 0 but got %v and %v", leaderHeight, laggingHeight)
+	}
+	if leaderBehind, laggingBehind := testutil.ToFloat64(m.blocksBehind.WithLabelValues("leader")), testutil.ToFloat64(m.blocksBehind.WithLabelValues("lagging")); leaderBehind != 0 || laggingBehind != 2 {
+		t.Errorf("expected the lagging endpoint 2 blocks behind but got %v and %v", leaderBehind, laggingBehind)
+	}
+
+	// the failing method stays available but incapable, and is counted as a failure.
+	available, capable := testutil.ToFloat64(m.available.WithLabelValues("lagging", "eth_getLogs")), testutil.ToFloat64(m.capable.WithLabelValues("lagging", "eth_getLogs"))
+	if available != 1 || capable != 0 {
+		t.Errorf("expected eth_getLogs available but incapable but got %v and %v", available, capable)
+	}
+	if failures, series := testutil.ToFloat64(m.failures.WithLabelValues("lagging", "eth_getLogs")), testutil.CollectAndCount(m.failures); failures != 1 || series != 1 {
+		t.Errorf("expected the single failure of eth_getLogs but got %v out of %d series", failures, series)
+	}
+
+	// the latency of the 5 probes of both endpoints is observed.
+	if count := testutil.CollectAndCount(m.latencyHistogram); count != 10 {
+		t.Errorf("expected the latency of 10 probes but got %d", count)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{})
+	hanging := false
+	node.Handle("eth_chainId", func(_ []json.RawMessage) (interface{}, error) {
+		if hanging {
+			time.Sleep(500 * time.Millisecond)
+		}
+		return hexutil.Uint64(10), nil
+	})
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a method answered with an error fires the incapable alert once, resolved once served.
+	node.Fail("eth_getLogs", &fake.Error{Code: -32005, Message: "block range too large"})
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(IncapableRule); len(alerts) != 1 || alerts[0].Entity != "node/eth_getLogs" || alerts[0].Priority != "P2" || len(notifier.Alerts(UnavailableRule)) != 0 {
+		t.Fatalf("expected the incapable alert once but got %v", alerts)
+	}
+	node.Result("eth_getLogs", []types.Log{})
+	m.Run(ctx)
+	if firing := notifier.Firing(IncapableRule); len(firing) != 0 {
+		t.Fatalf("expected the incapable alert resolved but got %v", firing)
+	}
+
+	// a method not answered within the timeout fires the unavailable alert.
+	hanging = true
+	m.Run(ctx)
+	if alerts := notifier.Alerts(UnavailableRule); len(alerts) != 1 || alerts[0].Entity != "node/eth_chainId" || alerts[0].Priority != "P1" || alerts[0].Labels["method"] != "eth_chainId" {
+		t.Fatalf("expected the unavailable alert but got %v", alerts)
+	}
+}
@@ -0,0 +1,52 @@
+package runway
+
+import (
+	"math"
+)
+
+// sample is the balance of an account, in ETH, at the timestamp of a block.
+type sample struct {
+	timestamp uint64
+	balance   float64
+}
+
+// history is the trailing window of balance samples of an account, in ascending timestamp order.
+type history struct {
+	samples []sample
+}
+
+// add records the sample and drops the samples no longer needed for the window, keeping the last one before it so that the burn
+// over the whole window is known. A sample not newer than the last one is ignored.
+func (h *history) add(s sample, window uint64) {
+	if len(h.samples) > 0 && s.timestamp <= h.samples[len(h.samples)-1].timestamp {
+		return
+	}
+	h.samples = append(h.samples, s)
+	for len(h.samples) > 2 && h.samples[1].timestamp+window <= s.timestamp {
+		h.samples = h.samples[1:]
+	}
+}
+
+// burnRate returns the ETH spent per second over the samples, the refills being ignored, along with the covered duration in
+// seconds. The burn rate is unknown, 0 being returned, until two samples are recorded.
+func (h *history) burnRate() (float64, uint64) {
+	if len(h.samples) < 2 {
+		return 0, 0
+	}
+	burned := 0.0
+	for i := 1; i < len(h.samples); i++ {
+		if spent := h.samples[i-1].balance - h.samples[i].balance; spent > 0 {
+			burned += spent
+		}
+	}
+	span := h.samples[len(h.samples)-1].timestamp - h.samples[0].timestamp
+	return burned / float64(span), span
+}
+
+// runway returns the seconds left before the balance is depleted at the burn rate, +Inf when nothing is burned.
+func runway(balance float64, rate float64) float64 {
+	if rate <= 0 {
+		return math.Inf(1)
+	}
+	return max(balance, 0) / rate
+}
@@ -0,0 +1,54 @@
+package runway
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	h := &history{}
+	h.add(sample{timestamp: 0, balance: 10}, 100)
+	if rate, _ := h.burnRate(); rate != 0 {
+		t.Errorf("expected an unknown burn rate with one sample but got %f", rate)
+	}
+
+	h.add(sample{timestamp: 50, balance: 5}, 100)
+	h.add(sample{timestamp: 50, balance: 1}, 100)  // same block, ignored.
+	h.add(sample{timestamp: 60, balance: 20}, 100) // refill.
+	h.add(sample{timestamp: 100, balance: 16}, 100)
+
+	rate, span := h.burnRate()
+	if span != 100 || rate != 0.09 {
+		t.Errorf("expected 9 ETH burned over 100s but got %f over %ds", rate, span)
+	}
+
+	h.add(sample{timestamp: 170, balance: 14}, 100)
+	if len(h.samples) != 3 || h.samples[0].timestamp != 60 {
+		t.Errorf("expected the samples since the last one before the window but got %v", h.samples)
+	}
+	if rate, span := h.burnRate(); span != 110 || math.Abs(rate-6.0/110) > 1e-9 {
+		t.Errorf("expected 6 ETH burned over 110s but got %f over %ds", rate, span)
+	}
+}
+
+func TestRunway(t *testing.T) {
+	tests := []struct {
+		name     string
+		balance  float64
+		rate     float64
+		expected float64
+	}{
+		{name: "Burning", balance: 10, rate: 0.5, expected: 20},
+		{name: "Nothing burned", balance: 10, rate: 0, expected: math.Inf(1)},
+		{name: "Depleted", balance: 0, rate: 0.5, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := runway(test.balance, test.rate)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %f but got %f", test.name, test.expected, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package runway
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodeURLFlagName   = "node.url"
+	AccountsFlagName  = "accounts"
+	WindowFlagName    = "window"
+	RefillSLAFlagName = "refill.sla"
+)
+
+// Account is a faucet or an account funded by Drippie, whose balance is spent and refilled.
+type Account struct {
+	Name    string
+	Address common.Address
+}
+
+type CLIConfig struct {
+	NodeURL  string
+	Accounts []Account
+
+	// Window is the trailing window over which the burn rate is computed.
+	Window time.Duration
+
+	// RefillSLA is the time needed to refill an account, a depletion projected within it is reported.
+	RefillSLA time.Duration
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		NodeURL:   ctx.String(NodeURLFlagName),
+		Window:    ctx.Duration(WindowFlagName),
+		RefillSLA: ctx.Duration(RefillSLAFlagName),
+	}
+
+	names := make(map[string]bool)
+	for _, account := range ctx.StringSlice(AccountsFlagName) {
+		name, address, err := util.ParseNamedValue(account, "name=address")
+		if err != nil {
+			return cfg, err
+		}
+		if !common.IsHexAddress(address) {
+			return cfg, fmt.Errorf("address is not a hex-encoded address: %s", address)
+		}
+		if names[name] {
+			return cfg, fmt.Errorf("duplicated account %s", name)
+		}
+		names[name] = true
+		cfg.Accounts = append(cfg.Accounts, Account{Name: name, Address: common.HexToAddress(address)})
+	}
+
+	if cfg.Window <= 0 {
+		return cfg, fmt.Errorf("--%s must be positive", WindowFlagName)
+	}
+	if cfg.RefillSLA <= 0 {
+		return cfg, fmt.Errorf("--%s must be positive", RefillSLAFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    NodeURLFlagName,
+			Usage:   "Node URL of a peer",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "NODE_URL"),
+		},
+		&cli.StringSliceFlag{
+			Name:     AccountsFlagName,
+			Usage:    "One or more faucets or Drippie-funded accounts formatted via `name=address`",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "ACCOUNTS"),
+			Required: true,
+		},
+		&cli.DurationFlag{
+			Name:    WindowFlagName,
+			Usage:   "Trailing window over which the burn rate is computed",
+			Value:   24 * time.Hour,
+			EnvVars: opservice.PrefixEnvVar(envVar, "WINDOW"),
+		},
+		&cli.DurationFlag{
+			Name:    RefillSLAFlagName,
+			Usage:   "Time needed to refill an account, a depletion projected within it is reported",
+			Value:   72 * time.Hour,
+			EnvVars: opservice.PrefixEnvVar(envVar, "REFILL_SLA"),
+		},
+	}
+}
@@ -0,0 +1,157 @@
+package runway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "runway"
+	MetricsNamespace = "runway_mon"
+
+	// DepletionRule is the rule of the alert fired when the projected depletion of an account is within the refill SLA, the entity being the account.
+	DepletionRule = "projected depletion within the refill SLA"
+
+	secondsPerDay = 24 * 60 * 60
+)
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	client *ethclient.Client
+
+	accounts  []Account
+	histories map[string]*history
+	window    uint64
+	refillSLA float64
+
+	// metrics
+	balance             *prometheus.GaugeVec
+	burnRate            *prometheus.GaugeVec
+	runwayDays          *prometheus.GaugeVec
+	withinRefillSLA     *prometheus.GaugeVec
+	refills             *prometheus.CounterVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating runway monitor...")
+
+	client, err := ethclient.Dial(cfg.NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial node: %w", err)
+	}
+
+	histories := make(map[string]*history, len(cfg.Accounts))
+	for _, account := range cfg.Accounts {
+		histories[account.Name] = &history{}
+		log.Info("configured account", "name", account.Name, "address", account.Address)
+	}
+
+	return &Monitor{
+		log: log,
+
+		client: client,
+
+		accounts:  cfg.Accounts,
+		histories: histories,
+		window:    uint64(cfg.Window.Seconds()),
+		refillSLA: cfg.RefillSLA.Seconds(),
+
+		balance: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "balance",
+			Help:      "ETH balance of the account",
+		}, []string{"name", "address"}),
+		burnRate: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "burnRate",
+			Help:      "ETH spent per day by the account over the trailing window, the refills being ignored",
+		}, []string{"name", "address"}),
+		runwayDays: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "runwayDays",
+			Help:      "estimated days before the account is depleted at the burn rate, +Inf when nothing is burned",
+		}, []string{"name", "address"}),
+		withinRefillSLA: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "withinRefillSLA",
+			Help:      "1 if the projected depletion of the account falls within the refill SLA",
+		}, []string{"name", "address"}),
+		refills: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "refills",
+			Help:      "number of balance increases of the account",
+		}, []string{"name", "address"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	header, err := m.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		m.log.Error("failed to query latest header", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("node", "headerByNumber").Inc()
+		return
+	}
+
+	for _, account := range m.accounts {
+		address := account.Address.String()
+		balanceWei, err := m.client.BalanceAt(ctx, account.Address, header.Number)
+		if err != nil {
+			m.log.Error("failed to query the balance", "name", account.Name, "address", address, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues(account.Name, "balanceAt").Inc()
+			continue
+		}
+		balance := util.WeiToEther(balanceWei)
+
+		h := m.histories[account.Name]
+		if n := len(h.samples); n > 0 && h.samples[n-1].timestamp < header.Time && balance > h.samples[n-1].balance {
+			m.log.Info("account refilled", "name", account.Name, "previous_balance", h.samples[n-1].balance, "balance", balance)
+			m.refills.WithLabelValues(account.Name, address).Inc()
+		}
+		h.add(sample{timestamp: header.Time, balance: balance}, m.window)
+
+		rate, span := h.burnRate()
+		left := runway(balance, rate)
+		within := left <= m.refillSLA
+		if within {
+			m.log.Warn("projected depletion within the refill SLA", "name", account.Name, "address", address, "balance", balance, "burn_rate_per_day", rate*secondsPerDay, "runway_days", left/secondsPerDay)
+		}
+
+		m.balance.WithLabelValues(account.Name, address).Set(balance)
+		m.burnRate.WithLabelValues(account.Name, address).Set(rate * secondsPerDay)
+		m.runwayDays.WithLabelValues(account.Name, address).Set(left / secondsPerDay)
+		m.withinRefillSLA.WithLabelValues(account.Name, address).Set(util.BoolToFloat(within))
+		m.Update(ctx, alerts.Alert{
+			Monitor:  MonitorName,
+			Rule:     DepletionRule,
+			Priority: "P1",
+			Entity:   account.Name,
+			Summary:  fmt.Sprintf("%s (%s) projected to deplete its %.4f ETH in %.1f days", account.Name, address, balance, left/secondsPerDay),
+			Labels:   map[string]string{"address": address},
+		}, within)
+		m.log.Info("checked account", "name", account.Name, "balance", balance, "burn_rate_per_day", rate*secondsPerDay, "covered_seconds", span, "runway_days", left/secondsPerDay)
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.client.Close()
+	return nil
+}
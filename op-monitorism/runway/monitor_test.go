@@ -0,0 +1,92 @@
+package runway
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// newTestMonitor returns the monitor of the batcher over a window of an hour with a refill SLA of a day, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *fake.Node) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{NodeURL: node.URL, Accounts: []Account{{Name: "batcher", Address: common.HexToAddress("0x6887246668a3b87F54DeB3b94Ba47a6f63F32985")}}, Window: time.Hour, RefillSLA: 24 * time.Hour}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{Time: 1000})
+	node.Result("eth_getBalance", (*hexutil.Big)(big.NewInt(5e18)))
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+	address := "0x6887246668a3b87F54DeB3b94Ba47a6f63F32985"
+
+	m.Run(ctx)
+	if balance, rate := testutil.ToFloat64(m.balance.WithLabelValues("batcher", address)), testutil.ToFloat64(m.burnRate.WithLabelValues("batcher", address)); balance != 5 || rate != 0 {
+		t.Errorf("expected a balance of 5 ETH without burn but got %v and %v", balance, rate)
+	}
+
+	// burning 1 ETH per hour leaves 4 hours of runway, within the refill SLA.
+	node.AddBlock(&types.Header{Time: 4600})
+	node.Result("eth_getBalance", (*hexutil.Big)(big.NewInt(4e18)))
+	m.Run(ctx)
+	if rate, days, within := testutil.ToFloat64(m.burnRate.WithLabelValues("batcher", address)), testutil.ToFloat64(m.runwayDays.WithLabelValues("batcher", address)), testutil.ToFloat64(m.withinRefillSLA.WithLabelValues("batcher", address)); rate != 24 || days != 4.0/24 || within != 1 {
+		t.Errorf("expected a burn of 24 ETH per day leaving 4 hours within the SLA but got %v, %v and %v", rate, days, within)
+	}
+
+	// a higher balance is counted as a refill.
+	node.AddBlock(&types.Header{Time: 8200})
+	node.Result("eth_getBalance", (*hexutil.Big)(new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))))
+	m.Run(ctx)
+	if refills, within := testutil.ToFloat64(m.refills.WithLabelValues("batcher", address)), testutil.ToFloat64(m.withinRefillSLA.WithLabelValues("batcher", address)); refills != 1 || within != 0 {
+		t.Errorf("expected 1 refill out of the SLA but got %v and %v", refills, within)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{Time: 1000})
+	node.Result("eth_getBalance", (*hexutil.Big)(big.NewInt(5e18)))
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// burning 1 ETH per hour leaves 4 hours of runway, firing once.
+	node.AddBlock(&types.Header{Time: 4600})
+	node.Result("eth_getBalance", (*hexutil.Big)(big.NewInt(4e18)))
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(DepletionRule); len(alerts) != 1 || alerts[0].Entity != "batcher" || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the depletion alert once but got %v", alerts)
+	}
+
+	// a refill without burn over the window resolves the alert.
+	node.AddBlock(&types.Header{Time: 8200})
+	node.Result("eth_getBalance", (*hexutil.Big)(new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))))
+	m.Run(ctx)
+	if firing := notifier.Firing(DepletionRule); len(firing) != 0 {
+		t.Fatalf("expected the depletion alert resolved but got %v", firing)
+	}
+}
@@ -0,0 +1,56 @@
+package safes
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	L1NodeURLFlagName   = "l1.node.url"
+	SafesConfigFlagName = "safes.config"
+)
+
+type CLIConfig struct {
+	L1NodeURL string
+
+	// Chains are the chains in addition to the default one, from the expected state file.
+	Chains []ChainConfig
+	Safes  []SafeConfig
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		L1NodeURL: ctx.String(L1NodeURLFlagName),
+	}
+
+	config, err := ReadConfigFile(ctx.String(SafesConfigFlagName))
+	if err != nil {
+		return cfg, err
+	}
+	if len(config.Safes) == 0 {
+		return cfg, fmt.Errorf("--%s: no safe to monitor", SafesConfigFlagName)
+	}
+	cfg.Chains, cfg.Safes = config.Chains, config.Safes
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    L1NodeURLFlagName,
+			Usage:   "Node URL of L1 peer, the default chain of the safes",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     SafesConfigFlagName,
+			Usage:    "YAML file with the safes of the organization and their expected owners, threshold, modules, guard and fallback handler",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "SAFES_CONFIG"),
+			Required: true,
+		},
+	}
+}
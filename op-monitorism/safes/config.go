@@ -0,0 +1,119 @@
+package safes
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultChain is the name of the chain configured with `--l1.node.url`, used by the safes without `chain`.
+	DefaultChain = "l1"
+)
+
+// ChainConfig is a chain where safes are inventoried.
+type ChainConfig struct {
+	Name    string `yaml:"name"`
+	NodeURL string `yaml:"rpc"`
+}
+
+// SafeConfig is a Safe of the organization along with its expected state.
+type SafeConfig struct {
+	Name    string         `yaml:"name"`
+	Address common.Address `yaml:"address"`
+
+	// Chain is the name of the chain of the safe, the default chain (`--l1.node.url`) when empty.
+	Chain string `yaml:"chain"`
+
+	Owners    []common.Address `yaml:"owners"`
+	Threshold uint64           `yaml:"threshold"`
+	Modules   []common.Address `yaml:"modules"`
+
+	// Guard and FallbackHandler are the zero address when the safe has none.
+	Guard           common.Address `yaml:"guard"`
+	FallbackHandler common.Address `yaml:"fallbackHandler"`
+
+	// Version is the expected version of the singleton of the safe, not checked when empty.
+	Version string `yaml:"version"`
+}
+
+// chainName returns the name of the chain of the safe.
+func (c SafeConfig) chainName() string {
+	if len(c.Chain) == 0 {
+		return DefaultChain
+	}
+	return c.Chain
+}
+
+// SafesConfiguration is the content of the expected state file given with `--safes.config`, committed along with the changes of the safes.
+//
+//	chains:
+//	  - name: optimism
+//	    rpc: https://mainnet.optimism.io
+//	safes:
+//	  - name: Security Council
+//	    address: 0xc2819DC788505Aac350142A7A707BF9D03E3Bd03
+//	    owners:
+//	      - 0x42d27eEA1AD6e22Af6284F609847CB3Cd56B9c64
+//	    threshold: 1
+//	    modules:
+//	      - 0x0454092516c9A4d636d3CAfA1e82161376C8a748
+//	    guard: 0x24424336F04440b1c28685a38303aC33C9D14a25
+//	    fallbackHandler: 0xf48f2B2d2a534e402487b3ee7C18c33Aec0Fe5e4
+//	    version: 1.3.0
+type SafesConfiguration struct {
+	// Chains are the chains in addition to the default one (`--l1.node.url`).
+	Chains []ChainConfig `yaml:"chains"`
+	Safes  []SafeConfig  `yaml:"safes"`
+}
+
+// ReadConfigFile reads the chains and the expected state of the safes from a YAML file.
+func ReadConfigFile(path string) (SafesConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SafesConfiguration{}, fmt.Errorf("failed to read the safes config file %s: %w", path, err)
+	}
+
+	var config SafesConfiguration
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return SafesConfiguration{}, fmt.Errorf("failed to parse the safes config file %s: %w", path, err)
+	}
+	if err := config.check(); err != nil {
+		return SafesConfiguration{}, fmt.Errorf("invalid safes config file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// check ensures the safes are uniquely named, on a configured chain and with a reachable threshold.
+func (c SafesConfiguration) check() error {
+	chains := map[string]bool{DefaultChain: true}
+	for _, chain := range c.Chains {
+		if len(chain.Name) == 0 || len(chain.NodeURL) == 0 {
+			return fmt.Errorf("the chain %q has no name or rpc", chain.Name)
+		}
+		if chains[chain.Name] {
+			return fmt.Errorf("duplicated chain %s", chain.Name)
+		}
+		chains[chain.Name] = true
+	}
+
+	names := make(map[string]bool)
+	for _, safe := range c.Safes {
+		if len(safe.Name) == 0 {
+			return fmt.Errorf("the safe %s has no name", safe.Address)
+		}
+		if names[safe.Name] {
+			return fmt.Errorf("duplicated safe %s", safe.Name)
+		}
+		names[safe.Name] = true
+		if !chains[safe.chainName()] {
+			return fmt.Errorf("unknown chain %s of the safe %s", safe.chainName(), safe.Name)
+		}
+		if safe.Threshold == 0 || safe.Threshold > uint64(len(safe.Owners)) {
+			return fmt.Errorf("the threshold %d of the safe %s is not reachable by its %d owners", safe.Threshold, safe.Name, len(safe.Owners))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package safes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testConfig = `
+chains:
+  - name: optimism
+    rpc: https://mainnet.optimism.io
+safes:
+  - name: Security Council
+    address: 0xc2819DC788505Aac350142A7A707BF9D03E3Bd03
+    owners:
+      - 0x0000000000000000000000000000000000000001
+      - 0x0000000000000000000000000000000000000002
+    threshold: 2
+    modules:
+      - 0x0454092516c9A4d636d3CAfA1e82161376C8a748
+    guard: 0x24424336F04440b1c28685a38303aC33C9D14a25
+    version: 1.3.0
+  - name: L2 Admin
+    chain: optimism
+    address: 0x0000000000000000000000000000000000000003
+    owners:
+      - 0x0000000000000000000000000000000000000001
+    threshold: 1
+`
+
+func TestReadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "safes.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0o600); err != nil {
+		t.Fatalf("failed to write the config: %v", err)
+	}
+
+	config, err := ReadConfigFile(path)
+	if err != nil {
+		t.Fatalf("failed to read the config: %v", err)
+	}
+	if len(config.Chains) != 1 || len(config.Safes) != 2 {
+		t.Fatalf("expected 1 chain and 2 safes but got %d and %d", len(config.Chains), len(config.Safes))
+	}
+
+	council := config.Safes[0]
+	if council.chainName() != DefaultChain || config.Safes[1].chainName() != "optimism" {
+		t.Errorf("unexpected chains %s and %s", council.chainName(), config.Safes[1].chainName())
+	}
+	if council.Threshold != 2 || len(council.Owners) != 2 || len(council.Modules) != 1 || council.Version != "1.3.0" {
+		t.Errorf("unexpected expected state %+v", council)
+	}
+	if council.Guard != common.HexToAddress("0x24424336F04440b1c28685a38303aC33C9D14a25") || council.FallbackHandler != (common.Address{}) {
+		t.Errorf("unexpected guard %s and fallback handler %s", council.Guard, council.FallbackHandler)
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	owner := common.HexToAddress("0x1")
+
+	tests := []struct {
+		name   string
+		config SafesConfiguration
+		valid  bool
+	}{
+		{name: "valid config", config: SafesConfiguration{Safes: []SafeConfig{{Name: "a", Owners: []common.Address{owner}, Threshold: 1}}}, valid: true},
+		{name: "unnamed safe", config: SafesConfiguration{Safes: []SafeConfig{{Owners: []common.Address{owner}, Threshold: 1}}}},
+		{name: "duplicated safe", config: SafesConfiguration{Safes: []SafeConfig{{Name: "a", Owners: []common.Address{owner}, Threshold: 1}, {Name: "a", Owners: []common.Address{owner}, Threshold: 1}}}},
+		{name: "unknown chain", config: SafesConfiguration{Safes: []SafeConfig{{Name: "a", Chain: "base", Owners: []common.Address{owner}, Threshold: 1}}}},
+		{name: "duplicated chain", config: SafesConfiguration{Chains: []ChainConfig{{Name: DefaultChain, NodeURL: "http://localhost"}}}},
+		{name: "zero threshold", config: SafesConfiguration{Safes: []SafeConfig{{Name: "a", Owners: []common.Address{owner}}}}},
+		{name: "unreachable threshold", config: SafesConfiguration{Safes: []SafeConfig{{Name: "a", Owners: []common.Address{owner}, Threshold: 2}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.config.check(); (err == nil) != test.valid {
+				t.Errorf("Failed %s: expected valid %t but got %v", test.name, test.valid, err)
+			}
+		})
+	}
+}
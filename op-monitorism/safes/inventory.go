@@ -0,0 +1,80 @@
+package safes
+
+import (
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fields are the fields of the inventory of a safe, diffed against the expected state.
+var fields = []string{"owners", "threshold", "modules", "guard", "fallbackHandler", "version"}
+
+// inventory is the state of a safe observed at a block.
+type inventory struct {
+	owners          []common.Address
+	threshold       uint64
+	modules         []common.Address
+	guard           common.Address
+	fallbackHandler common.Address
+	version         string
+}
+
+// drift returns, for each field, whether the inventory differs from the expected state of the safe.
+// The owners and the modules are compared as sets, their order in the linked lists of the safe being meaningless.
+func (i *inventory) drift(expected SafeConfig) map[string]bool {
+	missingOwners, unexpectedOwners := diffAddresses(expected.Owners, i.owners)
+	missingModules, unexpectedModules := diffAddresses(expected.Modules, i.modules)
+	return map[string]bool{
+		"owners":          len(missingOwners) > 0 || len(unexpectedOwners) > 0,
+		"threshold":       i.threshold != expected.Threshold,
+		"modules":         len(missingModules) > 0 || len(unexpectedModules) > 0,
+		"guard":           i.guard != expected.Guard,
+		"fallbackHandler": i.fallbackHandler != expected.FallbackHandler,
+		"version":         len(expected.Version) > 0 && i.version != expected.Version,
+	}
+}
+
+// changes returns the fields which changed since the previous inventory.
+func (i *inventory) changes(previous *inventory) []string {
+	var changed []string
+	for field, drifted := range i.drift(previous.asConfig()) {
+		if drifted {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// asConfig returns the inventory as an expected state, to diff two inventories.
+func (i *inventory) asConfig() SafeConfig {
+	return SafeConfig{
+		Owners:          i.owners,
+		Threshold:       i.threshold,
+		Modules:         i.modules,
+		Guard:           i.guard,
+		FallbackHandler: i.fallbackHandler,
+		Version:         i.version,
+	}
+}
+
+// diffAddresses returns the expected addresses which are not observed and the observed ones which are not expected.
+func diffAddresses(expected, observed []common.Address) (missing, unexpected []common.Address) {
+	expectedSet := make(map[common.Address]bool, len(expected))
+	for _, address := range expected {
+		expectedSet[address] = true
+	}
+	observedSet := make(map[common.Address]bool, len(observed))
+	for _, address := range observed {
+		observedSet[address] = true
+		if !expectedSet[address] {
+			unexpected = append(unexpected, address)
+		}
+	}
+	for _, address := range expected {
+		if !observedSet[address] {
+			missing = append(missing, address)
+		}
+	}
+	return missing, unexpected
+}
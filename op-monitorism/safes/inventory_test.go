@@ -0,0 +1,70 @@
+package safes
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDrift(t *testing.T) {
+	a, b, c := common.HexToAddress("0x1"), common.HexToAddress("0x2"), common.HexToAddress("0x3")
+	expected := SafeConfig{Owners: []common.Address{a, b}, Threshold: 2, Modules: []common.Address{c}, Guard: c, Version: "1.3.0"}
+
+	tests := []struct {
+		name     string
+		inv      inventory
+		expected []string
+	}{
+		{name: "expected state", inv: inventory{owners: []common.Address{a, b}, threshold: 2, modules: []common.Address{c}, guard: c, version: "1.3.0"}},
+		{name: "reordered owners", inv: inventory{owners: []common.Address{b, a}, threshold: 2, modules: []common.Address{c}, guard: c, version: "1.3.0"}},
+		{name: "swapped owner", inv: inventory{owners: []common.Address{a, c}, threshold: 2, modules: []common.Address{c}, guard: c, version: "1.3.0"}, expected: []string{"owners"}},
+		{name: "lowered threshold", inv: inventory{owners: []common.Address{a, b}, threshold: 1, modules: []common.Address{c}, guard: c, version: "1.3.0"}, expected: []string{"threshold"}},
+		{name: "disabled module and guard", inv: inventory{owners: []common.Address{a, b}, threshold: 2, version: "1.3.0"}, expected: []string{"guard", "modules"}},
+		{name: "fallback handler and version", inv: inventory{owners: []common.Address{a, b}, threshold: 2, modules: []common.Address{c}, guard: c, fallbackHandler: a, version: "1.4.1"}, expected: []string{"fallbackHandler", "version"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			drift := test.inv.drift(expected)
+			var got []string
+			for _, field := range []string{"fallbackHandler", "guard", "modules", "owners", "threshold", "version"} {
+				if drift[field] {
+					got = append(got, field)
+				}
+			}
+			if len(got) != len(test.expected) {
+				t.Fatalf("Failed %s: expected %v but got %v", test.name, test.expected, got)
+			}
+			for i := range got {
+				if got[i] != test.expected[i] {
+					t.Errorf("Failed %s: expected %v but got %v", test.name, test.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestChanges(t *testing.T) {
+	a, b := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	previous := &inventory{owners: []common.Address{a}, threshold: 1, version: "1.3.0"}
+	current := &inventory{owners: []common.Address{a, b}, threshold: 2, version: "1.3.0"}
+
+	changes := current.changes(previous)
+	if len(changes) != 2 || changes[0] != "owners" || changes[1] != "threshold" {
+		t.Errorf("expected [owners threshold] but got %v", changes)
+	}
+	if changes := current.changes(current); len(changes) != 0 {
+		t.Errorf("expected no change but got %v", changes)
+	}
+}
+
+func TestDiffAddresses(t *testing.T) {
+	a, b, c := common.HexToAddress("0x1"), common.HexToAddress("0x2"), common.HexToAddress("0x3")
+	missing, unexpected := diffAddresses([]common.Address{a, b}, []common.Address{b, c})
+	if len(missing) != 1 || missing[0] != a {
+		t.Errorf("expected %s missing but got %v", a, missing)
+	}
+	if len(unexpected) != 1 || unexpected[0] != c {
+		t.Errorf("expected %s unexpected but got %v", c, unexpected)
+	}
+}
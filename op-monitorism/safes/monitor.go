@@ -0,0 +1,354 @@
+package safes
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "safes"
+	MetricsNamespace = "safes_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the safe, prefixed by its chain, and the field.
+	ChangedRule = "safe changed"
+	DriftRule   = "safe differs from the expected state"
+
+	// SafeABI is the subset of the Safe used by the monitor.
+	SafeABI = `[{"inputs":[],"name":"getOwners","outputs":[{"name":"","type":"address[]"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"getThreshold","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"start","type":"address"},{"name":"pageSize","type":"uint256"}],"name":"getModulesPaginated","outputs":[{"name":"array","type":"address[]"},{"name":"next","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"VERSION","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"}]`
+
+	// MaxModulesPageSize is the page size when listing the modules of a safe.
+	MaxModulesPageSize = 100
+)
+
+var (
+	safeABI = util.MustParseJSONABI(SafeABI)
+
+	// GuardStorageSlot is the storage slot of the guard in a Safe, `keccak256("guard_manager.guard.address")`.
+	GuardStorageSlot = common.HexToHash("0x4a204f620c8c5ccdca3fd54d003badd85ba500436a431f0cbda4f558c93c34c8")
+
+	// FallbackHandlerStorageSlot is the storage slot of the fallback handler in a Safe, `keccak256("fallback_manager.handler.address")`.
+	FallbackHandlerStorageSlot = common.HexToHash("0x6c9a6c4a39284e37ed1cf53d337577d14212a4870fb976a4366c693b939918d5")
+
+	// SentinelModules is the head of the linked list of the modules of a Safe.
+	SentinelModules = common.HexToAddress("0x1")
+)
+
+// safeTarget is a safe of the organization along with its inventory observed at the previous iteration.
+type safeTarget struct {
+	cfg      SafeConfig
+	contract *bind.BoundContract
+
+	// previous is `nil` before the first inventory.
+	previous *inventory
+	drifted  bool
+}
+
+// chain is a chain along with the safes inventoried on it.
+type chain struct {
+	name   string
+	client *ethclient.Client
+	safes  []*safeTarget
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	chains []*chain
+
+	// metrics
+	owners              *prometheus.GaugeVec
+	owner               *prometheus.GaugeVec
+	threshold           *prometheus.GaugeVec
+	modules             *prometheus.GaugeVec
+	module              *prometheus.GaugeVec
+	drift               *prometheus.GaugeVec
+	driftedSafes        prometheus.Gauge
+	stateChanges        *prometheus.CounterVec
+	checkedBlockNumber  *prometheus.GaugeVec
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating safes monitor...")
+
+	var chains []*chain
+	byName := make(map[string]*chain)
+	for _, chainCfg := range append([]ChainConfig{{Name: DefaultChain, NodeURL: cfg.L1NodeURL}}, cfg.Chains...) {
+		client, err := ethclient.Dial(chainCfg.NodeURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial the chain %s: %w", chainCfg.Name, err)
+		}
+		byName[chainCfg.Name] = &chain{name: chainCfg.Name, client: client}
+		chains = append(chains, byName[chainCfg.Name])
+	}
+
+	for _, safe := range cfg.Safes {
+		c, ok := byName[safe.chainName()]
+		if !ok {
+			return nil, fmt.Errorf("unknown chain %s of the safe %s", safe.chainName(), safe.Name)
+		}
+		c.safes = append(c.safes, &safeTarget{cfg: safe, contract: bind.NewBoundContract(safe.Address, *safeABI, c.client, nil, nil)})
+		log.Info("configured safe", "safe", safe.Name, "address", safe.Address, "chain", c.name, "owners", len(safe.Owners), "threshold", safe.Threshold)
+	}
+
+	return &Monitor{
+		log: log,
+
+		chains: chains,
+
+		owners: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "owners",
+			Help:      "number of owners of the safe",
+		}, []string{"safe", "chain"}),
+		owner: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "owner",
+			Help:      "owner of the safe, 1 if expected, 0 otherwise",
+		}, []string{"safe", "chain", "address"}),
+		threshold: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "threshold",
+			Help:      "threshold of the safe",
+		}, []string{"safe", "chain"}),
+		modules: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "modules",
+			Help:      "number of modules enabled on the safe",
+		}, []string{"safe", "chain"}),
+		module: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "module",
+			Help:      "module enabled on the safe, 1 if expected, 0 otherwise",
+		}, []string{"safe", "chain", "address"}),
+		drift: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "drift",
+			Help:      "1 if the field (owners, threshold, modules, guard, fallbackHandler, version) of the safe differs from the expected state, 0 otherwise",
+		}, []string{"safe", "chain", "field"}),
+		driftedSafes: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "driftedSafes",
+			Help:      "number of safes differing from the expected state",
+		}),
+		stateChanges: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "stateChanges",
+			Help:      "number of changes of the field of the safe between two iterations",
+		}, []string{"safe", "chain", "field"}),
+		checkedBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "checkedBlockNumber",
+			Help:      "height of the latest inventory of the chain",
+		}, []string{"chain"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	for _, c := range m.chains {
+		if len(c.safes) == 0 {
+			continue
+		}
+		latestHeight, err := c.client.BlockNumber(ctx)
+		if err != nil {
+			m.log.Error("failed to query latest block number", "chain", c.name, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues(c.name, "blockNumber").Inc()
+			continue
+		}
+
+		blockNumber := new(big.Int).SetUint64(latestHeight)
+		for _, safe := range c.safes {
+			inv, err := m.readInventory(ctx, c, safe, blockNumber)
+			if err != nil {
+				m.log.Error("failed to inventory the safe", "safe", safe.cfg.Name, "chain", c.name, "err", err)
+				m.unexpectedRpcErrors.WithLabelValues("safe", safe.cfg.Name).Inc()
+				continue
+			}
+			m.checkSafe(ctx, c, safe, inv)
+		}
+		m.checkedBlockNumber.WithLabelValues(c.name).Set(float64(latestHeight))
+	}
+
+	drifted := 0
+	for _, c := range m.chains {
+		for _, safe := range c.safes {
+			if safe.drifted {
+				drifted++
+			}
+		}
+	}
+	m.driftedSafes.Set(float64(drifted))
+}
+
+// checkSafe reports the inventory of the safe, its changes since the previous iteration and its drift from the expected state.
+func (m *Monitor) checkSafe(ctx context.Context, c *chain, safe *safeTarget, inv *inventory) {
+	name := safe.cfg.Name
+
+	if safe.previous != nil {
+		for _, field := range inv.changes(safe.previous) {
+			m.log.Warn("safe changed", "safe", name, "chain", c.name, "field", field)
+			m.stateChanges.WithLabelValues(name, c.name, field).Inc()
+			m.Emit(ctx, alert(ChangedRule, "P1", c, safe, field, fmt.Sprintf("%s of the safe %s on %s changed", field, name, c.name)))
+		}
+		_, removedOwners := diffAddresses(inv.owners, safe.previous.owners)
+		for _, owner := range removedOwners {
+			m.owner.DeleteLabelValues(name, c.name, owner.String())
+		}
+		_, removedModules := diffAddresses(inv.modules, safe.previous.modules)
+		for _, module := range removedModules {
+			m.module.DeleteLabelValues(name, c.name, module.String())
+		}
+	}
+	safe.previous = inv
+
+	m.owners.WithLabelValues(name, c.name).Set(float64(len(inv.owners)))
+	m.threshold.WithLabelValues(name, c.name).Set(float64(inv.threshold))
+	m.modules.WithLabelValues(name, c.name).Set(float64(len(inv.modules)))
+
+	_, unexpectedOwners := diffAddresses(safe.cfg.Owners, inv.owners)
+	for _, owner := range inv.owners {
+		m.owner.WithLabelValues(name, c.name, owner.String()).Set(1)
+	}
+	for _, owner := range unexpectedOwners {
+		m.owner.WithLabelValues(name, c.name, owner.String()).Set(0)
+	}
+	_, unexpectedModules := diffAddresses(safe.cfg.Modules, inv.modules)
+	for _, module := range inv.modules {
+		m.module.WithLabelValues(name, c.name, module.String()).Set(1)
+	}
+	for _, module := range unexpectedModules {
+		m.module.WithLabelValues(name, c.name, module.String()).Set(0)
+	}
+
+	drift := inv.drift(safe.cfg)
+	safe.drifted = false
+	for _, field := range fields {
+		if drift[field] {
+			safe.drifted = true
+		}
+		m.drift.WithLabelValues(name, c.name, field).Set(util.BoolToFloat(drift[field]))
+		m.Update(ctx, alert(DriftRule, "P0", c, safe, field, fmt.Sprintf("%s of the safe %s on %s differs from the expected state", field, name, c.name)), drift[field])
+	}
+	if safe.drifted {
+		missingOwners, _ := diffAddresses(safe.cfg.Owners, inv.owners)
+		missingModules, _ := diffAddresses(safe.cfg.Modules, inv.modules)
+		m.log.Error("safe differs from the expected state", "safe", name, "chain", c.name, "address", safe.cfg.Address,
+			"missing_owners", missingOwners, "unexpected_owners", unexpectedOwners, "threshold", inv.threshold, "expected_threshold", safe.cfg.Threshold,
+			"missing_modules", missingModules, "unexpected_modules", unexpectedModules, "guard", inv.guard, "expected_guard", safe.cfg.Guard,
+			"fallback_handler", inv.fallbackHandler, "expected_fallback_handler", safe.cfg.FallbackHandler, "version", inv.version)
+	}
+}
+
+// alert returns the alert of the rule about the field of the safe.
+func alert(rule, priority string, c *chain, safe *safeTarget, field string, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   c.name + "/" + safe.cfg.Name + "/" + field,
+		Summary:  summary,
+		Labels:   map[string]string{"address": safe.cfg.Address.String(), "chain": c.name, "safe": safe.cfg.Name},
+	}
+}
+
+// readInventory reads the owners, the threshold, the modules, the guard, the fallback handler and the version of the safe at the block.
+func (m *Monitor) readInventory(ctx context.Context, c *chain, safe *safeTarget, blockNumber *big.Int) (*inventory, error) {
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: blockNumber}
+	inv := &inventory{}
+
+	var out []interface{}
+	if err := safe.contract.Call(callOpts, &out, "getOwners"); err != nil {
+		return nil, fmt.Errorf("failed to query the owners: %w", err)
+	}
+	inv.owners = *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+
+	out = nil
+	if err := safe.contract.Call(callOpts, &out, "getThreshold"); err != nil {
+		return nil, fmt.Errorf("failed to query the threshold: %w", err)
+	}
+	inv.threshold = (*abi.ConvertType(out[0], new(*big.Int)).(**big.Int)).Uint64()
+
+	modules, err := listModules(safe.contract, callOpts)
+	if err != nil {
+		return nil, err
+	}
+	inv.modules = modules
+
+	guard, err := c.client.StorageAt(ctx, safe.cfg.Address, GuardStorageSlot, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the guard: %w", err)
+	}
+	inv.guard = common.BytesToAddress(guard)
+
+	handler, err := c.client.StorageAt(ctx, safe.cfg.Address, FallbackHandlerStorageSlot, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the fallback handler: %w", err)
+	}
+	inv.fallbackHandler = common.BytesToAddress(handler)
+
+	out = nil
+	if err := safe.contract.Call(callOpts, &out, "VERSION"); err != nil {
+		return nil, fmt.Errorf("failed to query the version: %w", err)
+	}
+	inv.version = *abi.ConvertType(out[0], new(string)).(*string)
+
+	return inv, nil
+}
+
+// listModules returns every module enabled on the safe by walking `getModulesPaginated`.
+// Before Safe v1.4.1, `next` is the first module of the next page and not the last one of the current page, it is added explicitly.
+func listModules(contract *bind.BoundContract, callOpts *bind.CallOpts) ([]common.Address, error) {
+	var modules []common.Address
+	seen := make(map[common.Address]bool)
+	start := SentinelModules
+	for {
+		var out []interface{}
+		if err := contract.Call(callOpts, &out, "getModulesPaginated", start, big.NewInt(MaxModulesPageSize)); err != nil {
+			return nil, fmt.Errorf("failed to query the modules: %w", err)
+		}
+		page := *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+		next := *abi.ConvertType(out[1], new(common.Address)).(*common.Address)
+
+		if next != SentinelModules && next != (common.Address{}) {
+			page = append(page, next)
+		}
+		for _, module := range page {
+			if !seen[module] {
+				seen[module] = true
+				modules = append(modules, module)
+			}
+		}
+		if next == SentinelModules || next == (common.Address{}) || next == start {
+			return modules, nil
+		}
+		start = next
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	for _, c := range m.chains {
+		c.client.Close()
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package safes
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var (
+	securityCouncil = common.HexToAddress("0xc2819DC788505Aac350142A7A707BF9D03E3Bd03")
+	owners          = []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+)
+
+// newTestMonitor returns the monitor of the Security Council expected with a threshold of 2 over its 2 owners, the safe
+// contract and the notifier of its alerts.
+func newTestMonitor(t *testing.T) (*Monitor, *fake.Contract, *fake.Notifier) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{})
+	node.Result("eth_getStorageAt", common.Hash{})
+	safe := node.Contract(securityCouncil, safeABI).
+		Returns("getOwners", owners).
+		Returns("getThreshold", big.NewInt(2)).
+		Returns("getModulesPaginated", []common.Address{}, SentinelModules).
+		Returns("VERSION", "1.3.0")
+
+	cfg := CLIConfig{L1NodeURL: node.URL, Safes: []SafeConfig{{Name: "Security Council", Address: securityCouncil, Owners: owners, Threshold: 2, Version: "1.3.0"}}}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, safe, notifier
+}
+
+func TestRun(t *testing.T) {
+	m, safe, _ := newTestMonitor(t)
+	ctx := context.Background()
+	name := "Security Council"
+
+	m.Run(ctx)
+	if count, threshold, drifted := testutil.ToFloat64(m.owners.WithLabelValues(name, "l1")), testutil.ToFloat64(m.threshold.WithLabelValues(name, "l1")), testutil.ToFloat64(m.driftedSafes); count != 2 || threshold != 2 || drifted != 0 {
+		t.Errorf("expected the 2 owners with a threshold of 2 and no drift but got %v, %v and %v", count, threshold, drifted)
+	}
+
+	// a replaced owner is counted as a change, reported unexpected, and removes the series of the previous owner.
+	unexpected := common.HexToAddress("0x03")
+	safe.Returns("getOwners", []common.Address{owners[0], unexpected})
+	m.Run(ctx)
+	if changes, drift, drifted := testutil.ToFloat64(m.stateChanges.WithLabelValues(name, "l1", "owners")), testutil.ToFloat64(m.drift.WithLabelValues(name, "l1", "owners")), testutil.ToFloat64(m.driftedSafes); changes != 1 || drift != 1 || drifted != 1 {
+		t.Errorf("expected 1 change drifting the owners but got %v, %v and %v", changes, drift, drifted)
+	}
+	if count := testutil.CollectAndCount(m.owner); count != 2 {
+		t.Errorf("expected the series of the 2 current owners only but got %d", count)
+	}
+	if known, owner := testutil.ToFloat64(m.owner.WithLabelValues(name, "l1", owners[0].String())), testutil.ToFloat64(m.owner.WithLabelValues(name, "l1", unexpected.String())); known != 1 || owner != 0 {
+		t.Errorf("expected the unexpected owner reported as 0 but got %v and %v", known, owner)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	m, safe, notifier := newTestMonitor(t)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a lowered threshold fires the change once, and the drift until the expected threshold is back.
+	safe.Returns("getThreshold", big.NewInt(1))
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(ChangedRule); len(alerts) != 1 || alerts[0].Entity != "l1/Security Council/threshold" || alerts[0].Priority != "P1" {
+		t.Fatalf("expected the changed threshold alert once but got %v", alerts)
+	}
+	if alerts := notifier.Alerts(DriftRule); len(alerts) != 1 || alerts[0].Priority != "P0" || alerts[0].Labels["address"] != securityCouncil.String() {
+		t.Fatalf("expected the threshold drift alert once but got %v", alerts)
+	}
+	safe.Returns("getThreshold", big.NewInt(2))
+	m.Run(ctx)
+	if firing := notifier.Firing(DriftRule); len(firing) != 0 {
+		t.Fatalf("expected the drift alert resolved but got %v", firing)
+	}
+
+	// an unexpected module fires the drift of the modules.
+	safe.Returns("getModulesPaginated", []common.Address{common.HexToAddress("0x0454092516c9A4d636d3CAfA1e82161376C8a748")}, SentinelModules)
+	m.Run(ctx)
+	if firing := notifier.Firing(DriftRule); len(firing) != 1 || len(notifier.Alerts(ChangedRule)) != 3 {
+		t.Fatalf("expected the modules drift alert but got %v", notifier.Alerts(""))
+	}
+}
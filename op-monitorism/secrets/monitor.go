@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"strings"
 
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/secrets/bindings"
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
 
@@ -19,13 +20,20 @@ import (
 )
 
 const (
+	MonitorName      = "secrets"
 	MetricsNamespace = "secrets_mon"
 
+	// InitiatedRule is the rule of the alert fired while a drip is initiated by a revealed secret, the entity being the drip.
+	InitiatedRule = "drip initiated by a revealed secret"
+
 	// ABI for CheckSecretsParams struct
 	CheckSecretsParamsABI = `[{"constant":true,"inputs":[],"name":"getTuple","outputs":[{"components":[{"name":"delay","type":"uint256"},{"name":"secretHashMustExist","type":"bytes32"},{"name":"secretHashMustNotExist","type":"bytes32"}],"name":"","type":"tuple"}],"payable":false,"stateMutability":"view","type":"function"}]`
 )
 
 type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
 	log log.Logger
 
 	l1Client *ethclient.Client
@@ -38,6 +46,7 @@ type Monitor struct {
 	// Metrics
 	highestBlockNumber     *prometheus.GaugeVec
 	revealedSecrets        *prometheus.GaugeVec
+	secondsBeforeDrip      *prometheus.GaugeVec
 	nodeConnectionFailures *prometheus.CounterVec
 }
 
@@ -74,6 +83,11 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 			Name:      "revealedSecrets",
 			Help:      "revealed secrets",
 		}, []string{"type", "drip", "hash"}),
+		secondsBeforeDrip: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "secondsBeforeDrip",
+			Help:      "seconds before the delay following the initiation secret expires and the drip becomes executable, negative once expired",
+		}, []string{"drip", "hash"}),
 		nodeConnectionFailures: m.NewCounterVec(prometheus.CounterOpts{
 			Namespace: MetricsNamespace,
 			Name:      "nodeConnectionFailures",
@@ -83,13 +97,14 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 }
 
 func (m *Monitor) Run(ctx context.Context) {
-	// Determine current L1 block height.
-	latestL1Height, err := m.l1Client.BlockNumber(ctx)
+	// Determine current L1 block height and timestamp.
+	latestL1Header, err := m.l1Client.HeaderByNumber(ctx, nil)
 	if err != nil {
-		m.log.Error("failed to query latest block number", "err", err)
-		m.nodeConnectionFailures.WithLabelValues("l1", "blockNumber").Inc()
+		m.log.Error("failed to query latest block header", "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l1", "headerByNumber").Inc()
 		return
 	}
+	latestL1Height, now := latestL1Header.Number.Uint64(), latestL1Header.Time
 
 	// Update metrics.
 	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestL1Height))
@@ -240,9 +255,32 @@ func (m *Monitor) Run(ctx context.Context) {
 			m.log.Info("revealed cancellation secret", "name", name, "hash", secretHex2)
 			m.revealedSecrets.WithLabelValues("cancellation", name, secretHex2).Set(1)
 		}
+
+		// Track the expiry of the delay once the initiation secret is revealed, the drip is cancelled by the cancellation secret.
+		initiated := exists1.Sign() > 0 && exists2.Sign() == 0
+		remaining := secondsBeforeDrip(exists1.Uint64(), checkparams.Delay.Uint64(), now)
+		if initiated {
+			m.log.Warn("drip initiated by a revealed secret", "name", name, "hash", secretHex1, "seconds_before_drip", remaining)
+			m.secondsBeforeDrip.WithLabelValues(name, secretHex1).Set(float64(remaining))
+		} else {
+			m.secondsBeforeDrip.DeleteLabelValues(name, secretHex1)
+		}
+		m.Update(ctx, alerts.Alert{
+			Monitor:  MonitorName,
+			Rule:     InitiatedRule,
+			Priority: "P1",
+			Entity:   name,
+			Summary:  fmt.Sprintf("drip %s initiated by a revealed secret, executable in %ds", name, remaining),
+			Labels:   map[string]string{"hash": secretHex1},
+		}, initiated)
 	}
 }
 
+// secondsBeforeDrip returns the seconds before the delay following the reveal of the initiation secret expires, negative once expired.
+func secondsBeforeDrip(revealedAt uint64, delay uint64, now uint64) int64 {
+	return int64(revealedAt+delay) - int64(now)
+}
+
 func (m *Monitor) Close(_ context.Context) error {
 	m.l1Client.Close()
 	return nil
@@ -0,0 +1,113 @@
+package secrets
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/secrets/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	drippie   = common.HexToAddress("0x03d7a5f5fb4e8ae56a5d2dae1d4b7a0c8de8b9a1")
+	dripcheck = common.HexToAddress("0x0a5b13e06f5fd47e20c1abf2b43e1a9ab8b7c0b1")
+
+	initiationHash   = crypto.Keccak256Hash([]byte("initiation"))
+	cancellationHash = crypto.Keccak256Hash([]byte("cancellation"))
+)
+
+func TestSecondsBeforeDrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		revealedAt uint64
+		delay      uint64
+		now        uint64
+		expected   int64
+	}{
+		{name: "Delay running", revealedAt: 1000, delay: 3600, now: 1600, expected: 3000},
+		{name: "Delay expiring now", revealedAt: 1000, delay: 3600, now: 4600, expected: 0},
+		{name: "Delay expired", revealedAt: 1000, delay: 3600, now: 5000, expected: -400},
+		{name: "No delay", revealedAt: 1000, delay: 0, now: 1000, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := secondsBeforeDrip(test.revealedAt, test.delay, test.now)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %d but got %d", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+// newTestMonitor returns the monitor of the drip `secret` of the Drippie, executable an hour after the reveal of its
+// initiation secret, the CheckSecrets serving the reveal timestamps, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, revealed map[common.Hash]int64) (*Monitor, *fake.Notifier) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{Time: 2000})
+
+	paramsABI, err := abi.JSON(strings.NewReader(CheckSecretsParamsABI))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	checkparams, err := paramsABI.Methods["getTuple"].Outputs.Pack(bindings.CheckSecretsParams{Delay: big.NewInt(3600), SecretHashMustExist: initiationHash, SecretHashMustNotExist: cancellationHash})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	config := bindings.DrippieDripConfig{Interval: big.NewInt(0), Dripcheck: dripcheck, Checkparams: checkparams, Actions: []bindings.DrippieDripAction{}}
+	node.Contract(drippie, util.MustParseABI(bindings.DrippieMetaData)).
+		Returns("getDripCount", big.NewInt(1)).
+		Returns("created", "secret").
+		Returns("drips", uint8(2), config, big.NewInt(0), big.NewInt(0)).
+		Returns("getDripStatus", uint8(2))
+	node.Contract(dripcheck, util.MustParseABI(bindings.CheckSecretsMetaData)).
+		Returns("name", "CheckSecrets").
+		Handle("revealedSecrets", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{big.NewInt(revealed[args[0].([32]byte)])}, nil
+		})
+
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), CLIConfig{L1NodeURL: node.URL, DrippieAddress: drippie})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRunAlerts(t *testing.T) {
+	revealed := make(map[common.Hash]int64)
+	m, notifier := newTestMonitor(t, revealed)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// the reveal of the initiation secret fires once, resolved by the reveal of the cancellation secret.
+	revealed[initiationHash] = 1000
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(InitiatedRule); len(alerts) != 1 || alerts[0].Entity != "secret" || alerts[0].Priority != "P1" || !strings.HasSuffix(alerts[0].Summary, "executable in 2600s") {
+		t.Fatalf("expected the initiated alert once but got %v", alerts)
+	}
+	revealed[cancellationHash] = 1500
+	m.Run(ctx)
+	if firing := notifier.Firing(InitiatedRule); len(firing) != 0 {
+		t.Fatalf("expected the initiated alert resolved but got %v", firing)
+	}
+}
@@ -0,0 +1,143 @@
+package signer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	SignersFlagName         = "signers"
+	SignerAddressesFlagName = "signers.addresses"
+	ChainIDFlagName         = "chain.id"
+	ProbeTimeoutFlagName    = "probe.timeout"
+
+	TLSCaCertFlagName = "tls.ca"
+	TLSCertFlagName   = "tls.cert"
+	TLSKeyFlagName    = "tls.key"
+)
+
+// Signer is an op-signer endpoint along with the address expected to sign through it.
+type Signer struct {
+	Name    string
+	URL     string
+	Address common.Address
+}
+
+type CLIConfig struct {
+	Signers []Signer
+
+	// ChainID is the chain ID of the test transactions signed by the probes.
+	ChainID      uint64
+	ProbeTimeout time.Duration
+
+	// TLSConfig is the client TLS configuration shared by the signers, disabled when empty.
+	TLSConfig optls.CLIConfig
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		ChainID:      ctx.Uint64(ChainIDFlagName),
+		ProbeTimeout: ctx.Duration(ProbeTimeoutFlagName),
+		TLSConfig: optls.CLIConfig{
+			TLSCaCert: ctx.String(TLSCaCertFlagName),
+			TLSCert:   ctx.String(TLSCertFlagName),
+			TLSKey:    ctx.String(TLSKeyFlagName),
+		},
+	}
+
+	names := make(map[string]int)
+	for _, signer := range ctx.StringSlice(SignersFlagName) {
+		name, url, err := util.ParseNamedValue(signer, "name=url")
+		if err != nil {
+			return cfg, err
+		}
+		if _, ok := names[name]; ok {
+			return cfg, fmt.Errorf("duplicated signer %s", name)
+		}
+		names[name] = len(cfg.Signers)
+		cfg.Signers = append(cfg.Signers, Signer{Name: name, URL: url})
+	}
+
+	addresses := make(map[string]bool)
+	for _, signerAddress := range ctx.StringSlice(SignerAddressesFlagName) {
+		name, address, err := util.ParseNamedValue(signerAddress, "name=address")
+		if err != nil {
+			return cfg, err
+		}
+		i, ok := names[name]
+		if !ok {
+			return cfg, fmt.Errorf("--%s: unknown signer %s", SignerAddressesFlagName, name)
+		}
+		if !common.IsHexAddress(address) {
+			return cfg, fmt.Errorf("--%s: %s is not a hex-encoded address", SignerAddressesFlagName, address)
+		}
+		cfg.Signers[i].Address = common.HexToAddress(address)
+		addresses[name] = true
+	}
+	for _, signer := range cfg.Signers {
+		if !addresses[signer.Name] {
+			return cfg, fmt.Errorf("--%s: no address for the signer %s", SignerAddressesFlagName, signer.Name)
+		}
+	}
+
+	if cfg.ChainID == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", ChainIDFlagName)
+	}
+	if err := cfg.TLSConfig.Check(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     SignersFlagName,
+			Usage:    "One or more op-signer endpoints formatted via `name=url`",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "SIGNERS"),
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:     SignerAddressesFlagName,
+			Usage:    "Address expected to sign through each signer formatted via `name=address`",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "SIGNERS_ADDRESSES"),
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:    ChainIDFlagName,
+			Usage:   "Chain ID of the test transactions signed by the probes",
+			Value:   1,
+			EnvVars: opservice.PrefixEnvVar(envVar, "CHAIN_ID"),
+		},
+		&cli.DurationFlag{
+			Name:    ProbeTimeoutFlagName,
+			Usage:   "Timeout of a probe",
+			Value:   10 * time.Second,
+			EnvVars: opservice.PrefixEnvVar(envVar, "PROBE_TIMEOUT"),
+		},
+		&cli.StringFlag{
+			Name:    TLSCaCertFlagName,
+			Usage:   "TLS CA cert path of the signers, TLS is disabled when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "TLS_CA"),
+		},
+		&cli.StringFlag{
+			Name:    TLSCertFlagName,
+			Usage:   "TLS client cert path",
+			EnvVars: opservice.PrefixEnvVar(envVar, "TLS_CERT"),
+		},
+		&cli.StringFlag{
+			Name:    TLSKeyFlagName,
+			Usage:   "TLS client key path",
+			EnvVars: opservice.PrefixEnvVar(envVar, "TLS_KEY"),
+		},
+	}
+}
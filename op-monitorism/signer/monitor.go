@@ -0,0 +1,278 @@
+package signer
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	opsigner "github.com/ethereum-optimism/optimism/op-service/signer"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+	"github.com/ethereum-optimism/optimism/op-service/tls/certman"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "signer"
+	MetricsNamespace = "signer_mon"
+
+	// Rules of the alerts emitted by the monitor, the entity being the signer.
+	UnavailableRule = "signer unable to sign"
+	UnhealthyRule   = "signer is not healthy"
+)
+
+type signerEndpoint struct {
+	Signer
+	client *rpc.Client
+
+	consecutiveFailures uint64
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	signers []*signerEndpoint
+
+	chainID      *big.Int
+	probeTimeout time.Duration
+
+	// metrics
+	available           *prometheus.GaugeVec
+	healthy             *prometheus.GaugeVec
+	latency             *prometheus.GaugeVec
+	latencyHistogram    *prometheus.HistogramVec
+	failures            *prometheus.CounterVec
+	consecutiveFailures *prometheus.GaugeVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating signer monitor...")
+
+	httpClient, err := newHTTPClient(log, cfg.TLSConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure tls: %w", err)
+	}
+
+	signers := make([]*signerEndpoint, len(cfg.Signers))
+	for i, s := range cfg.Signers {
+		// the dial is lazy for http, a signer down at startup is reported by the probes.
+		client, err := rpc.DialOptions(ctx, s.URL, rpc.WithHTTPClient(httpClient))
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial the signer %s: %w", s.Name, err)
+		}
+		signers[i] = &signerEndpoint{Signer: s, client: client}
+		log.Info("configured signer", "name", s.Name, "address", s.Address)
+	}
+
+	return &Monitor{
+		log: log,
+
+		signers: signers,
+
+		chainID:      new(big.Int).SetUint64(cfg.ChainID),
+		probeTimeout: cfg.ProbeTimeout,
+
+		available: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "available",
+			Help:      "1 if the signer returned a valid signature of the test payload by the expected address, 0 otherwise",
+		}, []string{"signer", "address"}),
+		healthy: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "healthy",
+			Help:      "1 if the signer answered health_status, 0 otherwise",
+		}, []string{"signer"}),
+		latency: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "latency",
+			Help:      "seconds taken by the last call of the method",
+		}, []string{"signer", "method"}),
+		latencyHistogram: m.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Name:      "latencyHistogram",
+			Help:      "seconds taken by the calls of the method",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"signer", "method"}),
+		failures: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "failures",
+			Help:      "number of failed probes by reason (unreachable, invalidSignature, payloadMismatch, unexpectedSigner)",
+		}, []string{"signer", "reason"}),
+		consecutiveFailures: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "consecutiveFailures",
+			Help:      "number of consecutive failed signatures of the signer",
+		}, []string{"signer"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	for _, s := range m.signers {
+		m.checkHealth(ctx, s)
+
+		reason := m.checkSignature(ctx, s)
+		if len(reason) > 0 {
+			s.consecutiveFailures++
+			m.failures.WithLabelValues(s.Name, reason).Inc()
+		} else {
+			s.consecutiveFailures = 0
+		}
+		m.available.WithLabelValues(s.Name, s.Address.String()).Set(util.BoolToFloat(len(reason) == 0))
+		m.consecutiveFailures.WithLabelValues(s.Name).Set(float64(s.consecutiveFailures))
+		m.Update(ctx, alert(UnavailableRule, "P1", s, fmt.Sprintf("signer %s unable to sign for %s: %s", s.Name, s.Address, reason)), len(reason) > 0)
+	}
+}
+
+// checkHealth reports whether the signer answers `health_status`, the version of the op-signer.
+func (m *Monitor) checkHealth(ctx context.Context, s *signerEndpoint) {
+	probeCtx, cancel := context.WithTimeout(ctx, m.probeTimeout)
+	defer cancel()
+
+	var status string
+	start := time.Now()
+	err := s.client.CallContext(probeCtx, &status, "health_status")
+	m.observeLatency(s, "health_status", time.Since(start))
+	if err != nil {
+		m.log.Warn("signer is not healthy", "signer", s.Name, "err", err)
+	}
+	m.healthy.WithLabelValues(s.Name).Set(util.BoolToFloat(err == nil))
+	m.Update(ctx, alert(UnhealthyRule, "P2", s, fmt.Sprintf("signer %s is not healthy: %v", s.Name, err)), err != nil)
+}
+
+// checkSignature requests the signature of a test transaction and returns the reason of the failure, empty when the signature is valid.
+func (m *Monitor) checkSignature(ctx context.Context, s *signerEndpoint) string {
+	probeCtx, cancel := context.WithTimeout(ctx, m.probeTimeout)
+	defer cancel()
+
+	payload := testTransaction(m.chainID, s.Address)
+	args := opsigner.NewTransactionArgsFromTransaction(m.chainID, &s.Address, payload)
+
+	var result hexutil.Bytes
+	start := time.Now()
+	err := s.client.CallContext(probeCtx, &result, "eth_signTransaction", args)
+	m.observeLatency(s, "eth_signTransaction", time.Since(start))
+	if err != nil {
+		m.log.Error("failed to sign the test payload", "signer", s.Name, "address", s.Address, "err", err)
+		return "unreachable"
+	}
+
+	var signed types.Transaction
+	if err := signed.UnmarshalBinary(result); err != nil {
+		m.log.Error("signer returned an invalid transaction", "signer", s.Name, "err", err)
+		return "invalidSignature"
+	}
+
+	reason, recovered := verifySignature(m.chainID, payload, &signed, s.Address)
+	if len(reason) > 0 {
+		m.log.Error("signer returned an invalid signature", "signer", s.Name, "reason", reason, "expected", s.Address, "recovered", recovered)
+	}
+	return reason
+}
+
+// alert returns the alert of the rule about the signer.
+func alert(rule, priority string, s *signerEndpoint, summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     rule,
+		Priority: priority,
+		Entity:   s.Name,
+		Summary:  summary,
+		Labels:   map[string]string{"address": s.Address.String()},
+	}
+}
+
+func (m *Monitor) observeLatency(s *signerEndpoint, method string, elapsed time.Duration) {
+	m.latency.WithLabelValues(s.Name, method).Set(elapsed.Seconds())
+	m.latencyHistogram.WithLabelValues(s.Name, method).Observe(elapsed.Seconds())
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	for _, s := range m.signers {
+		s.client.Close()
+	}
+	return nil
+}
+
+// testTransaction returns the test payload signed by the probes, a transaction that can never be included:
+// its nonce is the max nonce (EIP-2681) and it doesn't pay any fee.
+func testTransaction(chainID *big.Int, from common.Address) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     math.MaxUint64,
+		GasTipCap: new(big.Int),
+		GasFeeCap: new(big.Int),
+		Gas:       21_000,
+		To:        &from,
+		Value:     new(big.Int),
+	})
+}
+
+// verifySignature returns the reason why the signed transaction is not a signature of the payload by the expected address, empty when it is,
+// along with the recovered address.
+func verifySignature(chainID *big.Int, payload *types.Transaction, signed *types.Transaction, expected common.Address) (string, common.Address) {
+	txSigner := types.LatestSignerForChainID(chainID)
+	if signed.ChainId().Cmp(chainID) != 0 || txSigner.Hash(signed) != txSigner.Hash(payload) {
+		return "payloadMismatch", common.Address{}
+	}
+	recovered, err := types.Sender(txSigner, signed)
+	if err != nil {
+		return "invalidSignature", common.Address{}
+	}
+	if recovered != expected {
+		return "unexpectedSigner", recovered
+	}
+	return "", recovered
+}
+
+// newHTTPClient returns the http client of the signers, authenticated with the client certificate when the TLS is enabled.
+// The certificate is reloaded when renewed, as in the op-signer client.
+func newHTTPClient(log log.Logger, tlsConfig optls.CLIConfig) (*http.Client, error) {
+	if !tlsConfig.TLSEnabled() {
+		return http.DefaultClient, nil
+	}
+
+	caCert, err := os.ReadFile(tlsConfig.TLSCaCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tls.ca: %w", err)
+	}
+	caCertPool := x509.NewCertPool()
+	caCertPool.AppendCertsFromPEM(caCert)
+
+	cm, err := certman.New(log, tlsConfig.TLSCert, tlsConfig.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tls cert or key: %w", err)
+	}
+	if err := cm.Watch(); err != nil {
+		return nil, fmt.Errorf("failed to watch the tls cert: %w", err)
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				MinVersion: tls.VersionTLS13,
+				RootCAs:    caCertPool,
+				GetClientCertificate: func(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+					return cm.GetCertificate(nil)
+				},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,169 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestVerifySignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(10)
+	payload := testTransaction(chainID, address)
+
+	sign := func(tx *types.Transaction, chainID *big.Int) *types.Transaction {
+		signed, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), key)
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name     string
+		signed   *types.Transaction
+		expected common.Address
+		reason   string
+	}{
+		{name: "valid signature", signed: sign(payload, chainID), expected: address},
+		{name: "unexpected signer", signed: sign(payload, chainID), expected: common.HexToAddress("0x1"), reason: "unexpectedSigner"},
+		{name: "other payload", signed: sign(testTransaction(chainID, common.HexToAddress("0x1")), chainID), expected: address, reason: "payloadMismatch"},
+		{name: "other chain", signed: sign(testTransaction(big.NewInt(1), address), big.NewInt(1)), expected: address, reason: "payloadMismatch"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if reason, _ := verifySignature(chainID, payload, test.signed, test.expected); reason != test.reason {
+				t.Errorf("Failed %s: expected %q but got %q", test.name, test.reason, reason)
+			}
+		})
+	}
+}
+
+// newTestMonitor returns the monitor of the signer of the address on the chain 10, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *fake.Node, address common.Address) (*Monitor, *fake.Notifier) {
+	cfg := CLIConfig{Signers: []Signer{{Name: "batcher", URL: node.URL, Address: address}}, ChainID: 10, ProbeTimeout: time.Second}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	node := fake.NewNode(t)
+	node.Result("health_status", "v1.0.0")
+	signingKey := key
+	node.Handle("eth_signTransaction", func(_ []json.RawMessage) (interface{}, error) {
+		signed, err := types.SignTx(testTransaction(big.NewInt(10), address), types.LatestSignerForChainID(big.NewInt(10)), signingKey)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := signed.MarshalBinary()
+		return hexutil.Bytes(raw), err
+	})
+	m, _ := newTestMonitor(t, node, address)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if available, healthy := testutil.ToFloat64(m.available.WithLabelValues("batcher", address.String())), testutil.ToFloat64(m.healthy.WithLabelValues("batcher")); available != 1 || healthy != 1 {
+		t.Errorf("expected the signer available and healthy but got %v and %v", available, healthy)
+	}
+	if count := testutil.CollectAndCount(m.latencyHistogram); count != 2 {
+		t.Errorf("expected the latency of the 2 methods but got %d", count)
+	}
+
+	// the signatures by another key are counted as consecutive failures.
+	if signingKey, err = crypto.GenerateKey(); err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+	m.Run(ctx)
+	m.Run(ctx)
+	if failures, consecutive, available := testutil.ToFloat64(m.failures.WithLabelValues("batcher", "unexpectedSigner")), testutil.ToFloat64(m.consecutiveFailures.WithLabelValues("batcher")), testutil.ToFloat64(m.available.WithLabelValues("batcher", address.String())); failures != 2 || consecutive != 2 || available != 0 {
+		t.Errorf("expected 2 consecutive failures of the unavailable signer but got %v, %v and %v", failures, consecutive, available)
+	}
+
+	// a valid signature resets the consecutive failures, a failing health check only reports the signer unhealthy.
+	signingKey = key
+	node.Fail("health_status", errors.New("unhealthy"))
+	m.Run(ctx)
+	if consecutive, healthy := testutil.ToFloat64(m.consecutiveFailures.WithLabelValues("batcher")), testutil.ToFloat64(m.healthy.WithLabelValues("batcher")); consecutive != 0 || healthy != 0 {
+		t.Errorf("expected the failures reset and the signer unhealthy but got %v and %v", consecutive, healthy)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	node := fake.NewNode(t)
+	node.Result("health_status", "v1.0.0")
+	signingKey := key
+	node.Handle("eth_signTransaction", func(_ []json.RawMessage) (interface{}, error) {
+		signed, err := types.SignTx(testTransaction(big.NewInt(10), address), types.LatestSignerForChainID(big.NewInt(10)), signingKey)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := signed.MarshalBinary()
+		return hexutil.Bytes(raw), err
+	})
+	m, notifier := newTestMonitor(t, node, address)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a signature by another key fires once, resolved once signed by the expected key.
+	if signingKey, err = crypto.GenerateKey(); err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(UnavailableRule); len(alerts) != 1 || alerts[0].Entity != "batcher" || alerts[0].Priority != "P1" || !strings.HasSuffix(alerts[0].Summary, "unexpectedSigner") {
+		t.Fatalf("expected the unavailable alert once but got %v", alerts)
+	}
+	signingKey = key
+	m.Run(ctx)
+	if firing := notifier.Firing(UnavailableRule); len(firing) != 0 {
+		t.Fatalf("expected the unavailable alert resolved but got %v", firing)
+	}
+
+	// a failing health check fires the unhealthy alert alone.
+	node.Fail("health_status", errors.New("unhealthy"))
+	m.Run(ctx)
+	if alerts := notifier.Alerts(UnhealthyRule); len(alerts) != 1 || alerts[0].Priority != "P2" || len(notifier.Firing(UnavailableRule)) != 0 {
+		t.Fatalf("expected the unhealthy alert but got %v", alerts)
+	}
+}
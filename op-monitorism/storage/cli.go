@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodeURLFlagName          = "node.url"
+	InvariantsConfigFlagName = "invariants.config"
+	ProofsFlagName           = "proofs"
+)
+
+type CLIConfig struct {
+	NodeURL string
+
+	Invariants InvariantsConfiguration
+
+	// Proofs verifies the slots with eth_getProof against the state root of the block instead of trusting eth_getStorageAt.
+	Proofs bool
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		NodeURL: ctx.String(NodeURLFlagName),
+		Proofs:  ctx.Bool(ProofsFlagName),
+	}
+
+	invariants, err := ReadConfigFile(ctx.String(InvariantsConfigFlagName))
+	if err != nil {
+		return cfg, err
+	}
+	if len(invariants.Invariants) == 0 {
+		return cfg, fmt.Errorf("--%s: no invariant to monitor", InvariantsConfigFlagName)
+	}
+	if _, err := invariants.invariants(); err != nil {
+		return cfg, fmt.Errorf("--%s: %w", InvariantsConfigFlagName, err)
+	}
+	cfg.Invariants = invariants
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    NodeURLFlagName,
+			Usage:   "Node URL of the chain of the contracts",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     InvariantsConfigFlagName,
+			Usage:    "YAML file with the storage slots and their expected value or bounds",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "INVARIANTS_CONFIG"),
+			Required: true,
+		},
+		&cli.BoolFlag{
+			Name:    ProofsFlagName,
+			Usage:   "Verify the slots with eth_getProof against the state root of the block instead of eth_getStorageAt",
+			EnvVars: opservice.PrefixEnvVar(envVar, "PROOFS"),
+		},
+	}
+}
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// namedSlots are the well-known slots which can be configured by name instead of their hash.
+	namedSlots = map[string]common.Hash{
+		// `bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1)`
+		"eip1967.implementation": common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"),
+		// `bytes32(uint256(keccak256("eip1967.proxy.admin")) - 1)`
+		"eip1967.admin": common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103"),
+	}
+)
+
+// InvariantConfig is an invariant of a storage slot, either an expected value or bounds.
+// A packed variable is selected with `offset` and `size`, in bytes from the lowest-order byte of the slot.
+type InvariantConfig struct {
+	Name    string         `yaml:"name"`
+	Address common.Address `yaml:"address"`
+
+	// Slot is either a hex-encoded slot or a name of a well-known slot (`eip1967.implementation`, `eip1967.admin`).
+	Slot   string `yaml:"slot"`
+	Offset uint   `yaml:"offset"`
+	Size   uint   `yaml:"size"`
+
+	// Value is the hex-encoded expected value, Min and Max are the decimal or hex-encoded inclusive bounds.
+	Value string `yaml:"value"`
+	Min   string `yaml:"min"`
+	Max   string `yaml:"max"`
+}
+
+// InvariantsConfiguration is the content of the YAML file given with `--invariants.config`.
+//
+//	invariants:
+//	  - name: OptimismPortal implementation
+//	    address: 0xbEb5Fc579115071764c7423A4f12eDde41f106Ed
+//	    slot: eip1967.implementation
+//	    value: 0x2D778797049FE9259d947D1ED8e5442226dFB589
+//	  - name: OptimismPortal initialized
+//	    address: 0xbEb5Fc579115071764c7423A4f12eDde41f106Ed
+//	    slot: 0x0
+//	    size: 1
+//	    value: 0x1
+//	  - name: L2OutputOracle submission interval
+//	    address: 0xdfe97868233d1aa22e815a266982f2cf17685a27
+//	    slot: 0x4
+//	    min: 1
+//	    max: 3600
+type InvariantsConfiguration struct {
+	Invariants []InvariantConfig `yaml:"invariants"`
+}
+
+// invariant is a parsed InvariantConfig.
+type invariant struct {
+	name    string
+	address common.Address
+	slot    common.Hash
+	offset  uint
+	size    uint
+
+	// value is the expected value, min and max the bounds, nil when not set.
+	value *big.Int
+	min   *big.Int
+	max   *big.Int
+}
+
+// ReadConfigFile reads the invariants of the storage slots from a YAML file.
+func ReadConfigFile(path string) (InvariantsConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return InvariantsConfiguration{}, fmt.Errorf("failed to read the invariants config file %s: %w", path, err)
+	}
+
+	var config InvariantsConfiguration
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return InvariantsConfiguration{}, fmt.Errorf("failed to parse the invariants config file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// invariants returns the parsed invariants, ensuring they are uniquely named and have either an expected value or bounds.
+func (c InvariantsConfiguration) invariants() ([]invariant, error) {
+	names := make(map[string]bool)
+	invariants := make([]invariant, 0, len(c.Invariants))
+	for _, cfg := range c.Invariants {
+		if len(cfg.Name) == 0 {
+			return nil, fmt.Errorf("the invariant of the slot %s of %s has no name", cfg.Slot, cfg.Address)
+		}
+		if names[cfg.Name] {
+			return nil, fmt.Errorf("duplicated invariant %s", cfg.Name)
+		}
+		names[cfg.Name] = true
+
+		parsed, err := cfg.parse()
+		if err != nil {
+			return nil, fmt.Errorf("invalid invariant %s: %w", cfg.Name, err)
+		}
+		invariants = append(invariants, parsed)
+	}
+	return invariants, nil
+}
+
+func (c InvariantConfig) parse() (invariant, error) {
+	inv := invariant{name: c.Name, address: c.Address, offset: c.Offset, size: c.Size}
+
+	if slot, ok := namedSlots[c.Slot]; ok {
+		inv.slot = slot
+	} else {
+		slot, err := hexutil.DecodeBig(trimLeadingZeros(c.Slot))
+		if err != nil {
+			return inv, fmt.Errorf("the slot %q is neither hex-encoded nor a well-known slot", c.Slot)
+		}
+		inv.slot = common.BigToHash(slot)
+	}
+
+	if inv.size == 0 {
+		inv.size = 32 - inv.offset
+	}
+	if inv.offset+inv.size > 32 {
+		return inv, fmt.Errorf("the variable at offset %d of size %d overflows the slot", inv.offset, inv.size)
+	}
+
+	var err error
+	if len(c.Value) > 0 {
+		if len(c.Min) > 0 || len(c.Max) > 0 {
+			return inv, fmt.Errorf("both a value and bounds are set")
+		}
+		if inv.value, err = hexutil.DecodeBig(trimLeadingZeros(c.Value)); err != nil {
+			return inv, fmt.Errorf("the value %q is not hex-encoded: %w", c.Value, err)
+		}
+		return inv, nil
+	}
+
+	if len(c.Min) == 0 && len(c.Max) == 0 {
+		return inv, fmt.Errorf("neither a value nor bounds are set")
+	}
+	for bound, target := range map[string]**big.Int{c.Min: &inv.min, c.Max: &inv.max} {
+		if len(bound) == 0 {
+			continue
+		}
+		parsed, ok := new(big.Int).SetString(bound, 0)
+		if !ok {
+			return inv, fmt.Errorf("the bound %q is not a number", bound)
+		}
+		*target = parsed
+	}
+	if inv.min != nil && inv.max != nil && inv.min.Cmp(inv.max) > 0 {
+		return inv, fmt.Errorf("the min %s is above the max %s", inv.min, inv.max)
+	}
+	return inv, nil
+}
+
+// trimLeadingZeros trims the leading zeros of a hex-encoded value, the padded values (e.g. copied from a node) are not accepted by hexutil.
+func trimLeadingZeros(value string) string {
+	trimmed := strings.TrimLeft(strings.TrimPrefix(value, "0x"), "0")
+	if len(trimmed) == 0 {
+		trimmed = "0"
+	}
+	return "0x" + trimmed
+}
+
+// variable returns the variable of the invariant in the slot.
+func (i invariant) variable(word common.Hash) *big.Int {
+	return new(big.Int).SetBytes(word[32-i.offset-i.size : 32-i.offset])
+}
+
+// holds returns whether the variable satisfies the invariant.
+func (i invariant) holds(variable *big.Int) bool {
+	if i.value != nil {
+		return variable.Cmp(i.value) == 0
+	}
+	if i.min != nil && variable.Cmp(i.min) < 0 {
+		return false
+	}
+	if i.max != nil && variable.Cmp(i.max) > 0 {
+		return false
+	}
+	return true
+}
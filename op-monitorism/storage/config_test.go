@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+const testConfig = `
+invariants:
+  - name: OptimismPortal implementation
+    address: 0xbEb5Fc579115071764c7423A4f12eDde41f106Ed
+    slot: eip1967.implementation
+    value: 0x2D778797049FE9259d947D1ED8e5442226dFB589
+  - name: OptimismPortal initialized
+    address: 0xbEb5Fc579115071764c7423A4f12eDde41f106Ed
+    slot: 0x0
+    size: 1
+    value: 0x1
+  - name: L2OutputOracle submission interval
+    address: 0xdfe97868233d1aa22e815a266982f2cf17685a27
+    slot: 0x0000000000000000000000000000000000000000000000000000000000000004
+    min: 1
+    max: 0xe10
+`
+
+func TestInvariants(t *testing.T) {
+	var config InvariantsConfiguration
+	if err := yaml.Unmarshal([]byte(testConfig), &config); err != nil {
+		t.Fatalf("failed to parse the config: %v", err)
+	}
+	invariants, err := config.invariants()
+	if err != nil {
+		t.Fatalf("failed to parse the invariants: %v", err)
+	}
+	if len(invariants) != 3 {
+		t.Fatalf("expected 3 invariants but got %d", len(invariants))
+	}
+
+	implementation := invariants[0]
+	if implementation.slot != namedSlots["eip1967.implementation"] || implementation.offset != 0 || implementation.size != 32 {
+		t.Errorf("unexpected implementation slot %s at offset %d of size %d", implementation.slot, implementation.offset, implementation.size)
+	}
+	if implementation.value.Cmp(common.HexToAddress("0x2D778797049FE9259d947D1ED8e5442226dFB589").Big()) != 0 {
+		t.Errorf("unexpected implementation %s", implementation.value)
+	}
+	if invariants[1].size != 1 || invariants[1].value.Uint64() != 1 {
+		t.Errorf("unexpected initialized flag of size %d and value %s", invariants[1].size, invariants[1].value)
+	}
+	if interval := invariants[2]; interval.slot != common.BigToHash(big.NewInt(4)) || interval.min.Uint64() != 1 || interval.max.Uint64() != 3600 {
+		t.Errorf("unexpected submission interval slot %s between %s and %s", interval.slot, interval.min, interval.max)
+	}
+}
+
+func TestParseInvariant(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   InvariantConfig
+		valid bool
+	}{
+		{name: "expected value", cfg: InvariantConfig{Slot: "0x1", Value: "0x01"}, valid: true},
+		{name: "lower bound", cfg: InvariantConfig{Slot: "0x1", Min: "10"}, valid: true},
+		{name: "packed variable", cfg: InvariantConfig{Slot: "0x1", Offset: 20, Size: 12, Value: "0x0"}, valid: true},
+		{name: "unknown named slot", cfg: InvariantConfig{Slot: "eip1967.beacon", Value: "0x1"}},
+		{name: "overflowing variable", cfg: InvariantConfig{Slot: "0x1", Offset: 20, Size: 13, Value: "0x0"}},
+		{name: "value and bounds", cfg: InvariantConfig{Slot: "0x1", Value: "0x1", Max: "2"}},
+		{name: "no value nor bounds", cfg: InvariantConfig{Slot: "0x1"}},
+		{name: "inverted bounds", cfg: InvariantConfig{Slot: "0x1", Min: "2", Max: "1"}},
+		{name: "invalid bound", cfg: InvariantConfig{Slot: "0x1", Min: "one"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.cfg.parse(); (err == nil) != test.valid {
+				t.Errorf("Failed %s: expected valid %t but got %v", test.name, test.valid, err)
+			}
+		})
+	}
+}
+
+func TestHolds(t *testing.T) {
+	// initialized (uint8) at offset 0 and paused (bool) at offset 1 of the same slot.
+	word := common.HexToHash("0x0101")
+	initialized := invariant{offset: 0, size: 1, value: big.NewInt(1)}
+	paused := invariant{offset: 1, size: 1, value: big.NewInt(0)}
+	bounded := invariant{offset: 0, size: 32, min: big.NewInt(1), max: big.NewInt(0x100)}
+
+	tests := []struct {
+		name     string
+		inv      invariant
+		expected bool
+	}{
+		{name: "initialized", inv: initialized, expected: true},
+		{name: "paused", inv: paused, expected: false},
+		{name: "above max", inv: bounded, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.inv.holds(test.inv.variable(word)); got != test.expected {
+				t.Errorf("Failed %s: expected %t but got %t", test.name, test.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "storage"
+	MetricsNamespace = "storage_mon"
+
+	// ViolatedRule is the rule of the alert fired while a storage invariant is violated, the entity being the invariant.
+	ViolatedRule = "storage invariant violated"
+)
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	client *ethclient.Client
+
+	invariants []invariant
+	proofs     bool
+
+	// violated are the invariants violated at the previous iteration, by name.
+	violated map[string]bool
+
+	// metrics
+	value               *prometheus.GaugeVec
+	violation           *prometheus.GaugeVec
+	violations          *prometheus.CounterVec
+	violatedInvariants  prometheus.Gauge
+	invalidProofs       *prometheus.CounterVec
+	checkedBlockNumber  prometheus.Gauge
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating storage monitor...")
+
+	invariants, err := cfg.Invariants.invariants()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the invariants: %w", err)
+	}
+
+	client, err := ethclient.Dial(cfg.NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial node: %w", err)
+	}
+
+	for _, inv := range invariants {
+		log.Info("configured invariant", "name", inv.name, "address", inv.address, "slot", inv.slot, "offset", inv.offset, "size", inv.size, "value", inv.value, "min", inv.min, "max", inv.max)
+	}
+
+	return &Monitor{
+		log: log,
+
+		client: client,
+
+		invariants: invariants,
+		proofs:     cfg.Proofs,
+
+		violated: make(map[string]bool),
+
+		value: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "value",
+			Help:      "value of the variable of the invariant, approximated as a float",
+		}, []string{"invariant"}),
+		violation: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "violation",
+			Help:      "1 if the invariant is violated, 0 otherwise",
+		}, []string{"invariant", "address"}),
+		violations: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "violations",
+			Help:      "number of times the invariant started being violated",
+		}, []string{"invariant"}),
+		violatedInvariants: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "violatedInvariants",
+			Help:      "number of violated invariants",
+		}),
+		invalidProofs: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "invalidProofs",
+			Help:      "number of eth_getProof responses not matching the state root of the block",
+		}, []string{"address"}),
+		checkedBlockNumber: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "checkedBlockNumber",
+			Help:      "height of the latest check of the invariants",
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	header, err := m.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		m.log.Error("failed to query latest header", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("node", "headerByNumber").Inc()
+		return
+	}
+
+	var slots map[common.Address]map[common.Hash]common.Hash
+	if m.proofs {
+		slots = m.readProvenSlots(ctx, header.Number, header.Root)
+	} else {
+		slots = m.readSlots(ctx, header.Number)
+	}
+
+	for _, inv := range m.invariants {
+		word, ok := slots[inv.address][inv.slot]
+		if !ok { // the read failed, already reported.
+			continue
+		}
+
+		variable := inv.variable(word)
+		value, _ := new(big.Float).SetInt(variable).Float64()
+		m.value.WithLabelValues(inv.name).Set(value)
+
+		violated := !inv.holds(variable)
+		if violated {
+			m.log.Error("storage invariant violated", "invariant", inv.name, "address", inv.address, "slot", inv.slot, "variable", hexutil.EncodeBig(variable), "value", inv.value, "min", inv.min, "max", inv.max, "block", header.Number)
+			if !m.violated[inv.name] {
+				m.violations.WithLabelValues(inv.name).Inc()
+			}
+		}
+		m.violated[inv.name] = violated
+		m.violation.WithLabelValues(inv.name, inv.address.String()).Set(util.BoolToFloat(violated))
+		m.Update(ctx, alerts.Alert{
+			Monitor:  MonitorName,
+			Rule:     ViolatedRule,
+			Priority: "P0",
+			Entity:   inv.name,
+			Summary:  fmt.Sprintf("invariant %s violated by %s at slot %s of %s", inv.name, hexutil.EncodeBig(variable), inv.slot, inv.address),
+			Labels:   map[string]string{"address": inv.address.String(), "slot": inv.slot.String()},
+		}, violated)
+	}
+
+	violated := 0
+	for _, v := range m.violated {
+		if v {
+			violated++
+		}
+	}
+	m.violatedInvariants.Set(float64(violated))
+	m.checkedBlockNumber.Set(float64(header.Number.Uint64()))
+}
+
+// readSlots reads the slots of the invariants with eth_getStorageAt at the block, the slots which failed are not returned.
+func (m *Monitor) readSlots(ctx context.Context, blockNumber *big.Int) map[common.Address]map[common.Hash]common.Hash {
+	slots := make(map[common.Address]map[common.Hash]common.Hash)
+	for _, inv := range m.invariants {
+		if _, ok := slots[inv.address][inv.slot]; ok {
+			continue
+		}
+		word, err := m.client.StorageAt(ctx, inv.address, inv.slot, blockNumber)
+		if err != nil {
+			m.log.Error("failed to query the storage slot", "invariant", inv.name, "address", inv.address, "slot", inv.slot, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("node", "getStorageAt").Inc()
+			continue
+		}
+		if _, ok := slots[inv.address]; !ok {
+			slots[inv.address] = make(map[common.Hash]common.Hash)
+		}
+		slots[inv.address][inv.slot] = common.BytesToHash(word)
+	}
+	return slots
+}
+
+// readProvenSlots reads the slots of the invariants with a single eth_getProof per contract and verifies the proofs against the state root
+// of the block, the contracts whose query or proof failed are not returned.
+func (m *Monitor) readProvenSlots(ctx context.Context, blockNumber *big.Int, stateRoot common.Hash) map[common.Address]map[common.Hash]common.Hash {
+	keys := make(map[common.Address][]common.Hash)
+	seen := make(map[common.Address]map[common.Hash]bool)
+	for _, inv := range m.invariants {
+		if _, ok := seen[inv.address]; !ok {
+			seen[inv.address] = make(map[common.Hash]bool)
+		}
+		if !seen[inv.address][inv.slot] {
+			seen[inv.address][inv.slot] = true
+			keys[inv.address] = append(keys[inv.address], inv.slot)
+		}
+	}
+
+	slots := make(map[common.Address]map[common.Hash]common.Hash)
+	for address, addressKeys := range keys {
+		var proof eth.AccountResult
+		if err := m.client.Client().CallContext(ctx, &proof, "eth_getProof", address, addressKeys, hexutil.EncodeBig(blockNumber)); err != nil {
+			m.log.Error("failed to query the storage proof", "address", address, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("node", "getProof").Inc()
+			continue
+		}
+		if proof.Address != address || !provesKeys(proof, addressKeys) {
+			m.log.Error("storage proof doesn't match the request", "address", address, "proof_address", proof.Address, "slots", len(addressKeys), "proven_slots", len(proof.StorageProof))
+			m.invalidProofs.WithLabelValues(address.String()).Inc()
+			continue
+		}
+		if err := proof.Verify(stateRoot); err != nil {
+			m.log.Error("invalid storage proof", "address", address, "state_root", stateRoot, "err", err)
+			m.invalidProofs.WithLabelValues(address.String()).Inc()
+			continue
+		}
+
+		slots[address] = make(map[common.Hash]common.Hash, len(addressKeys))
+		for i, entry := range proof.StorageProof {
+			slots[address][addressKeys[i]] = common.BigToHash(entry.Value.ToInt())
+		}
+	}
+	return slots
+}
+
+// provesKeys returns whether the storage proof is a proof of the keys, in the same order.
+func provesKeys(proof eth.AccountResult, keys []common.Hash) bool {
+	if len(proof.StorageProof) != len(keys) {
+		return false
+	}
+	for i, entry := range proof.StorageProof {
+		if entry.Key != keys[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.client.Close()
+	return nil
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gopkg.in/yaml.v3"
+)
+
+// storageNode is a node serving the storage slots of the contracts.
+type storageNode struct {
+	*fake.Node
+
+	slots map[common.Address]map[common.Hash]common.Hash
+}
+
+func newStorageNode(t *testing.T) *storageNode {
+	n := &storageNode{Node: fake.NewNode(t), slots: make(map[common.Address]map[common.Hash]common.Hash)}
+	n.AddBlock(&types.Header{})
+	n.Handle("eth_getStorageAt", func(params []json.RawMessage) (interface{}, error) {
+		var address common.Address
+		var slot common.Hash
+		if err := fake.Param(params, 0, &address); err != nil {
+			return nil, err
+		}
+		if err := fake.Param(params, 1, &slot); err != nil {
+			return nil, err
+		}
+		return n.slots[address][slot], nil
+	})
+	return n
+}
+
+// set sets the slot of the contract.
+func (n *storageNode) set(address string, slot common.Hash, value common.Hash) {
+	contract := common.HexToAddress(address)
+	if n.slots[contract] == nil {
+		n.slots[contract] = make(map[common.Hash]common.Hash)
+	}
+	n.slots[contract][slot] = value
+}
+
+// newTestMonitor returns the monitor of the invariants of the test configuration, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *storageNode) (*Monitor, *fake.Notifier) {
+	var invariants InvariantsConfiguration
+	if err := yaml.Unmarshal([]byte(testConfig), &invariants); err != nil {
+		t.Fatalf("failed to parse the config: %v", err)
+	}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), CLIConfig{NodeURL: node.URL, Invariants: invariants})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	portal, oracle := "0xbEb5Fc579115071764c7423A4f12eDde41f106Ed", "0xdfe97868233d1aa22e815a266982f2cf17685a27"
+	node := newStorageNode(t)
+	node.set(portal, namedSlots["eip1967.implementation"], common.HexToHash("0x2D778797049FE9259d947D1ED8e5442226dFB589"))
+	node.set(portal, common.Hash{}, common.HexToHash("0x0101"))
+	node.set(oracle, common.HexToHash("0x4"), common.HexToHash("0x708"))
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if interval, initialized, violated := testutil.ToFloat64(m.value.WithLabelValues("L2OutputOracle submission interval")), testutil.ToFloat64(m.value.WithLabelValues("OptimismPortal initialized")), testutil.ToFloat64(m.violatedInvariants); interval != 1800 || initialized != 1 || violated != 0 {
+		t.Errorf("expected the interval of 1800 and the initialized flag without violation but got %v, %v and %v", interval, initialized, violated)
+	}
+
+	// an interval out of its bounds is counted once while violated.
+	node.set(oracle, common.HexToHash("0x4"), common.HexToHash("0xe11"))
+	m.Run(ctx)
+	m.Run(ctx)
+	if violations, violation, violated := testutil.ToFloat64(m.violations.WithLabelValues("L2OutputOracle submission interval")), testutil.ToFloat64(m.violation.WithLabelValues("L2OutputOracle submission interval", common.HexToAddress(oracle).String())), testutil.ToFloat64(m.violatedInvariants); violations != 1 || violation != 1 || violated != 1 {
+		t.Errorf("expected 1 violation of the interval but got %v, %v and %v", violations, violation, violated)
+	}
+
+	// an rpc error is counted for each slot, the violation being kept.
+	node.Fail("eth_getStorageAt", errors.New("unavailable"))
+	m.Run(ctx)
+	if errs, violated := testutil.ToFloat64(m.unexpectedRpcErrors.WithLabelValues("node", "getStorageAt")), testutil.ToFloat64(m.violatedInvariants); errs != 3 || violated != 1 {
+		t.Errorf("expected 3 rpc errors keeping the violation but got %v and %v", errs, violated)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	portal, implementation := "0xbEb5Fc579115071764c7423A4f12eDde41f106Ed", common.HexToHash("0x2D778797049FE9259d947D1ED8e5442226dFB589")
+	node := newStorageNode(t)
+	node.set(portal, namedSlots["eip1967.implementation"], implementation)
+	node.set(portal, common.Hash{}, common.HexToHash("0x0101"))
+	node.set("0xdfe97868233d1aa22e815a266982f2cf17685a27", common.HexToHash("0x4"), common.HexToHash("0x708"))
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// an unexpected implementation fires once, resolved once the expected one is back.
+	node.set(portal, namedSlots["eip1967.implementation"], common.HexToHash("0x01"))
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(ViolatedRule); len(alerts) != 1 || alerts[0].Entity != "OptimismPortal implementation" || alerts[0].Priority != "P0" || alerts[0].Labels["address"] != common.HexToAddress(portal).String() {
+		t.Fatalf("expected the violated alert once but got %v", alerts)
+	}
+	node.set(portal, namedSlots["eip1967.implementation"], implementation)
+	m.Run(ctx)
+	if firing := notifier.Firing(ViolatedRule); len(firing) != 0 {
+		t.Fatalf("expected the violated alert resolved but got %v", firing)
+	}
+
+	// a packed flag is violated while the other bytes of its slot are ignored.
+	node.set(portal, common.Hash{}, common.HexToHash("0x0100"))
+	m.Run(ctx)
+	if firing := notifier.Firing(ViolatedRule); len(firing) != 1 || firing[0] != "OptimismPortal initialized" {
+		t.Fatalf("expected the initialized flag violated but got %v", firing)
+	}
+}
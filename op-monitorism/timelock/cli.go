@@ -0,0 +1,85 @@
+package timelock
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	NodeURLFlagName = "node.url"
+
+	TimelockAddressFlagName  = "timelock.address"
+	StartBlockHeightFlagName = "start.block.height"
+	EventBlockRangeFlagName  = "event.block.range"
+	ABIFilesFlagName         = "abi.files"
+)
+
+type CLIConfig struct {
+	NodeURL string
+
+	TimelockAddress  common.Address
+	StartBlockHeight uint64
+	EventBlockRange  uint64
+
+	// ABIFiles are the JSON ABIs used to decode the queued calls in addition to the built-in admin functions.
+	ABIFiles []string
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		NodeURL:          ctx.String(NodeURLFlagName),
+		StartBlockHeight: ctx.Uint64(StartBlockHeightFlagName),
+		EventBlockRange:  ctx.Uint64(EventBlockRangeFlagName),
+		ABIFiles:         ctx.StringSlice(ABIFilesFlagName),
+	}
+
+	timelockAddress := ctx.String(TimelockAddressFlagName)
+	if !common.IsHexAddress(timelockAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", TimelockAddressFlagName)
+	}
+	cfg.TimelockAddress = common.HexToAddress(timelockAddress)
+
+	if cfg.EventBlockRange == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", EventBlockRangeFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    NodeURLFlagName,
+			Usage:   "Node URL of the chain of the timelock",
+			Value:   "127.0.0.1:8545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     TimelockAddressFlagName,
+			Usage:    "Address of the TimelockController contract",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "TIMELOCK"),
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:    StartBlockHeightFlagName,
+			Usage:   "Starting height to scan for queued operations, the latest block when not set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_BLOCK_HEIGHT"),
+		},
+		&cli.Uint64Flag{
+			Name:    EventBlockRangeFlagName,
+			Usage:   "Max block range when scanning for queued operations",
+			Value:   1000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "EVENT_BLOCK_RANGE"),
+		},
+		&cli.StringSliceFlag{
+			Name:    ABIFilesFlagName,
+			Usage:   "One or more JSON ABI files used to decode the queued calls in addition to the built-in admin functions",
+			EnvVars: opservice.PrefixEnvVar(envVar, "ABI_FILES"),
+		},
+	}
+}
@@ -0,0 +1,119 @@
+package timelock
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/util"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// TimelockControllerABI is the subset of the OpenZeppelin TimelockController used by the monitor.
+	TimelockControllerABI = `[{"anonymous":false,"inputs":[{"indexed":true,"name":"id","type":"bytes32"},{"indexed":true,"name":"index","type":"uint256"},{"indexed":false,"name":"target","type":"address"},{"indexed":false,"name":"value","type":"uint256"},{"indexed":false,"name":"data","type":"bytes"},{"indexed":false,"name":"predecessor","type":"bytes32"},{"indexed":false,"name":"delay","type":"uint256"}],"name":"CallScheduled","type":"event"},{"inputs":[{"name":"id","type":"bytes32"}],"name":"getTimestamp","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"getMinDelay","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+	// AdminFunctionsABI are the admin functions of the proxies, the access control and the OP Stack contracts decoded without `--abi.files`.
+	AdminFunctionsABI = `[` +
+		`{"inputs":[{"name":"_proxy","type":"address"},{"name":"_implementation","type":"address"}],"name":"upgrade","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"name":"_proxy","type":"address"},{"name":"_implementation","type":"address"},{"name":"_data","type":"bytes"}],"name":"upgradeAndCall","outputs":[],"stateMutability":"payable","type":"function"},` +
+		`{"inputs":[{"name":"_proxy","type":"address"},{"name":"_newAdmin","type":"address"}],"name":"changeProxyAdmin","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"name":"_implementation","type":"address"}],"name":"upgradeTo","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"name":"_implementation","type":"address"},{"name":"_data","type":"bytes"}],"name":"upgradeToAndCall","outputs":[],"stateMutability":"payable","type":"function"},` +
+		`{"inputs":[{"name":"_admin","type":"address"}],"name":"changeAdmin","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"name":"newOwner","type":"address"}],"name":"transferOwnership","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[],"name":"renounceOwnership","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"name":"role","type":"bytes32"},{"name":"account","type":"address"}],"name":"grantRole","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"name":"role","type":"bytes32"},{"name":"account","type":"address"}],"name":"revokeRole","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"name":"newDelay","type":"uint256"}],"name":"updateDelay","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"name":"_gameType","type":"uint32"},{"name":"_impl","type":"address"}],"name":"setImplementation","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"name":"_identifier","type":"string"}],"name":"pause","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[],"name":"unpause","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"name":"_batcherHash","type":"bytes32"}],"name":"setBatcherHash","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"name":"_unsafeBlockSigner","type":"address"}],"name":"setUnsafeBlockSigner","outputs":[],"stateMutability":"nonpayable","type":"function"},` +
+		`{"inputs":[{"name":"_gasLimit","type":"uint64"}],"name":"setGasLimit","outputs":[],"stateMutability":"nonpayable","type":"function"}` +
+		`]`
+)
+
+var (
+	timelockControllerABI = util.MustParseJSONABI(TimelockControllerABI)
+	adminFunctionsABI     = util.MustParseJSONABI(AdminFunctionsABI)
+)
+
+// readABIFile reads a JSON ABI (the bare ABI, not a compiler artifact).
+func readABIFile(path string) (*abi.ABI, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the ABI file %s: %w", path, err)
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the ABI file %s: %w", path, err)
+	}
+	return &parsed, nil
+}
+
+// callDecoder decodes the calldata of the queued calls from the selectors of the known ABIs.
+type callDecoder struct {
+	methods map[[4]byte]abi.Method
+}
+
+// newCallDecoder indexes the methods of the ABIs by selector, the first ABI wins on collisions.
+func newCallDecoder(abis ...*abi.ABI) *callDecoder {
+	decoder := &callDecoder{methods: make(map[[4]byte]abi.Method)}
+	for _, parsed := range abis {
+		for _, method := range parsed.Methods {
+			var selector [4]byte
+			copy(selector[:], method.ID)
+			if _, ok := decoder.methods[selector]; !ok {
+				decoder.methods[selector] = method
+			}
+		}
+	}
+	return decoder
+}
+
+// decode returns the signature of the called method and its formatted arguments.
+// The method is the hex-encoded selector when unknown (`known` is false), and empty for a plain transfer.
+func (d *callDecoder) decode(data []byte) (method string, args string, known bool) {
+	if len(data) == 0 {
+		return "", "", true
+	}
+	if len(data) < 4 {
+		return hexutil.Encode(data), "", false
+	}
+
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	abiMethod, ok := d.methods[selector]
+	if !ok {
+		return hexutil.Encode(selector[:]), hexutil.Encode(data[4:]), false
+	}
+
+	values, err := abiMethod.Inputs.Unpack(data[4:])
+	if err != nil {
+		return abiMethod.Sig, hexutil.Encode(data[4:]), false
+	}
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = formatValue(value)
+	}
+	return abiMethod.Sig, strings.Join(formatted, ", "), true
+}
+
+// formatValue formats a decoded argument, hex-encoding the bytes.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case []byte:
+		return hexutil.Encode(v)
+	case [32]byte:
+		return common.Hash(v).Hex()
+	case common.Address:
+		return v.Hex()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
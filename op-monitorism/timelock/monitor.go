@@ -0,0 +1,291 @@
+package timelock
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "timelock"
+	MetricsNamespace = "timelock_mon"
+
+	// ScheduledRule is the rule of the alert emitted for every scheduled call, the entity being the operation and the index of the call.
+	ScheduledRule = "call scheduled"
+
+	// DoneTimestamp is the timestamp of an executed operation in the TimelockController.
+	DoneTimestamp = 1
+)
+
+// queuedCall is a call of a queued operation, an operation scheduled with `scheduleBatch` has several calls.
+type queuedCall struct {
+	index  string
+	target common.Address
+	value  *big.Int
+	method string
+	args   string
+	known  bool
+}
+
+// operation is an operation scheduled in the timelock and neither executed nor cancelled yet.
+type operation struct {
+	id             common.Hash
+	calls          []*queuedCall
+	scheduledBlock uint64
+	txHash         common.Hash
+}
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	client          *ethclient.Client
+	timelock        *bind.BoundContract
+	timelockAddress common.Address
+	decoder         *callDecoder
+
+	nextHeight      uint64
+	eventBlockRange uint64
+
+	operations map[common.Hash]*operation
+
+	// metrics
+	highestBlockNumber     *prometheus.GaugeVec
+	minDelay               prometheus.Gauge
+	queuedOperations       prometheus.Gauge
+	secondsUntilExecutable *prometheus.GaugeVec
+	unknownCalls           prometheus.Gauge
+	operationsScheduled    prometheus.Counter
+	operationsCompleted    *prometheus.CounterVec
+	unexpectedRpcErrors    *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating timelock monitor...")
+
+	client, err := ethclient.Dial(cfg.NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial the node: %w", err)
+	}
+
+	abis := []*abi.ABI{adminFunctionsABI, timelockControllerABI}
+	for _, path := range cfg.ABIFiles {
+		parsed, err := readABIFile(path)
+		if err != nil {
+			return nil, err
+		}
+		abis = append(abis, parsed)
+	}
+
+	startingHeight := cfg.StartBlockHeight
+	if startingHeight == 0 {
+		if startingHeight, err = client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to query latest block number: %w", err)
+		}
+	}
+	log.Info("configured starting height", "height", startingHeight, "timelock", cfg.TimelockAddress)
+
+	return &Monitor{
+		log: log,
+
+		client:          client,
+		timelock:        bind.NewBoundContract(cfg.TimelockAddress, *timelockControllerABI, client, nil, nil),
+		timelockAddress: cfg.TimelockAddress,
+		decoder:         newCallDecoder(abis...),
+
+		nextHeight:      startingHeight,
+		eventBlockRange: cfg.EventBlockRange,
+
+		operations: make(map[common.Hash]*operation),
+
+		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "highestBlockNumber",
+			Help:      "observed heights (checked and known)",
+		}, []string{"type"}),
+		minDelay: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "minDelay",
+			Help:      "minimum delay in seconds of the timelock",
+		}),
+		queuedOperations: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "queuedOperations",
+			Help:      "number of operations scheduled and neither executed nor cancelled",
+		}),
+		secondsUntilExecutable: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "secondsUntilExecutable",
+			Help:      "seconds before the queued call becomes executable, negative once executable",
+		}, []string{"id", "index", "target", "method"}),
+		unknownCalls: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unknownCalls",
+			Help:      "number of queued calls whose calldata could not be decoded",
+		}),
+		operationsScheduled: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "operationsScheduled",
+			Help:      "number of operations scheduled",
+		}),
+		operationsCompleted: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "operationsCompleted",
+			Help:      "number of queued operations executed or cancelled",
+		}, []string{"state"}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	header, err := m.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		m.log.Error("failed to query latest header", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("node", "headerByNumber").Inc()
+		return
+	}
+	latestHeight := header.Number.Uint64()
+	m.highestBlockNumber.WithLabelValues("known").Set(float64(latestHeight))
+
+	var minDelay []interface{}
+	if err := m.timelock.Call(&bind.CallOpts{Context: ctx}, &minDelay, "getMinDelay"); err != nil {
+		m.log.Error("failed to query the min delay", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("timelock", "getMinDelay").Inc()
+	} else {
+		m.minDelay.Set(float64((*abi.ConvertType(minDelay[0], new(*big.Int)).(**big.Int)).Uint64()))
+	}
+
+	if m.nextHeight <= latestHeight {
+		toBlock := min(latestHeight, m.nextHeight+m.eventBlockRange-1)
+		if err := m.processScheduledCalls(ctx, m.nextHeight, toBlock); err != nil {
+			m.log.Error("failed to process the scheduled calls", "from", m.nextHeight, "to", toBlock, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("timelock", "CallScheduled").Inc()
+			return
+		}
+		m.highestBlockNumber.WithLabelValues("checked").Set(float64(toBlock))
+		m.nextHeight = toBlock + 1
+	}
+
+	m.checkOperations(ctx, header.Time)
+}
+
+// processScheduledCalls tracks the calls scheduled between the two blocks (inclusive).
+func (m *Monitor) processScheduledCalls(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	logs, err := m.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{m.timelockAddress},
+		Topics:    [][]common.Hash{{timelockControllerABI.Events["CallScheduled"].ID}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query the CallScheduled events: %w", err)
+	}
+
+	for _, vLog := range logs {
+		if len(vLog.Topics) != 3 {
+			return fmt.Errorf("unexpected number of topics in the CallScheduled event %s: %d", vLog.TxHash, len(vLog.Topics))
+		}
+		out, err := timelockControllerABI.Unpack("CallScheduled", vLog.Data)
+		if err != nil {
+			return fmt.Errorf("failed to unpack the CallScheduled event %s: %w", vLog.TxHash, err)
+		}
+
+		id := vLog.Topics[1]
+		op, ok := m.operations[id]
+		if !ok {
+			op = &operation{id: id, scheduledBlock: vLog.BlockNumber, txHash: vLog.TxHash}
+			m.operations[id] = op
+			m.operationsScheduled.Inc()
+		}
+
+		call := &queuedCall{
+			index:  new(big.Int).SetBytes(vLog.Topics[2].Bytes()).String(),
+			target: out[0].(common.Address),
+			value:  out[1].(*big.Int),
+		}
+		call.method, call.args, call.known = m.decoder.decode(out[2].([]byte))
+		op.calls = append(op.calls, call)
+
+		logFn, priority := m.log.Warn, "P2"
+		if !call.known {
+			logFn, priority = m.log.Error, "P1"
+		}
+		logFn("call scheduled", "id", id, "index", call.index, "target", call.target, "value", call.value, "method", call.method, "args", call.args, "delay", out[4].(*big.Int), "tx", vLog.TxHash)
+		m.Emit(ctx, alerts.Alert{
+			Monitor:  MonitorName,
+			Rule:     ScheduledRule,
+			Priority: priority,
+			Entity:   id.Hex() + "/" + call.index,
+			Summary:  fmt.Sprintf("call %s(%s) to %s scheduled with a delay of %ss", call.method, call.args, call.target, out[4].(*big.Int)),
+			Labels:   map[string]string{"known": strconv.FormatBool(call.known), "tx": vLog.TxHash.String()},
+		})
+	}
+	return nil
+}
+
+// checkOperations reports the time before the queued operations become executable, and forgets the executed or cancelled ones.
+func (m *Monitor) checkOperations(ctx context.Context, now uint64) {
+	unknown := 0
+	for id, op := range m.operations {
+		var out []interface{}
+		if err := m.timelock.Call(&bind.CallOpts{Context: ctx}, &out, "getTimestamp", id); err != nil {
+			m.log.Error("failed to query the timestamp of the operation", "id", id, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("timelock", "getTimestamp").Inc()
+			continue
+		}
+		readyAt := (*abi.ConvertType(out[0], new(*big.Int)).(**big.Int)).Uint64()
+
+		if readyAt <= DoneTimestamp {
+			state := "cancelled"
+			if readyAt == DoneTimestamp {
+				state = "executed"
+			}
+			m.log.Info("operation completed", "id", id, "state", state)
+			m.operationsCompleted.WithLabelValues(state).Inc()
+			for _, call := range op.calls {
+				m.secondsUntilExecutable.DeleteLabelValues(id.Hex(), call.index, call.target.String(), call.method)
+			}
+			delete(m.operations, id)
+			continue
+		}
+
+		for _, call := range op.calls {
+			if !call.known {
+				unknown++
+			}
+			m.secondsUntilExecutable.WithLabelValues(id.Hex(), call.index, call.target.String(), call.method).Set(float64(secondsUntilExecutable(readyAt, now)))
+		}
+	}
+
+	m.queuedOperations.Set(float64(len(m.operations)))
+	m.unknownCalls.Set(float64(unknown))
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.client.Close()
+	return nil
+}
+
+// secondsUntilExecutable returns the seconds before the operation ready at `readyAt` becomes executable, negative once executable.
+func secondsUntilExecutable(readyAt uint64, now uint64) int64 {
+	return int64(readyAt) - int64(now)
+}
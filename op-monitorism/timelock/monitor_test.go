@@ -0,0 +1,185 @@
+package timelock
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var timelock = common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+func TestDecode(t *testing.T) {
+	decoder := newCallDecoder(adminFunctionsABI)
+	proxy := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	implementation := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	upgrade, err := adminFunctionsABI.Pack("upgrade", proxy, implementation)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		data           []byte
+		expectedMethod string
+		expectedArgs   string
+		expectedKnown  bool
+	}{
+		{name: "Known method", data: upgrade, expectedMethod: "upgrade(address,address)", expectedArgs: proxy.Hex() + ", " + implementation.Hex(), expectedKnown: true},
+		{name: "Unknown selector", data: hexutil.MustDecode("0xdeadbeef01"), expectedMethod: "0xdeadbeef", expectedArgs: "0x01", expectedKnown: false},
+		{name: "Invalid arguments", data: upgrade[:20], expectedMethod: "upgrade(address,address)", expectedArgs: hexutil.Encode(upgrade[4:20]), expectedKnown: false},
+		{name: "Plain transfer", data: nil, expectedMethod: "", expectedArgs: "", expectedKnown: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			method, args, known := decoder.decode(test.data)
+			if method != test.expectedMethod || args != test.expectedArgs || known != test.expectedKnown {
+				t.Errorf("Failed %s: expected (%s, %s, %t) but got (%s, %s, %t)", test.name, test.expectedMethod, test.expectedArgs, test.expectedKnown, method, args, known)
+			}
+		})
+	}
+}
+
+func TestSecondsUntilExecutable(t *testing.T) {
+	tests := []struct {
+		name     string
+		readyAt  uint64
+		now      uint64
+		expected int64
+	}{
+		{name: "Delay running", readyAt: 5000, now: 1000, expected: 4000},
+		{name: "Executable now", readyAt: 5000, now: 5000, expected: 0},
+		{name: "Executable", readyAt: 5000, now: 6000, expected: -1000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := secondsUntilExecutable(test.readyAt, test.now)
+			if output != test.expected {
+				t.Errorf("Failed %s: expected %d but got %d", test.name, test.expected, output)
+			}
+		})
+	}
+}
+
+// schedule adds a block scheduling the calls of the operation in the timelock with a delay of a day.
+func schedule(t *testing.T, node *fake.Node, id common.Hash, target common.Address, calls ...[]byte) {
+	event := timelockControllerABI.Events["CallScheduled"]
+	header := node.AddBlock(&types.Header{Time: 1000})
+	for i, data := range calls {
+		packed, err := event.Inputs.NonIndexed().Pack(target, common.Big0, data, common.Hash{}, big.NewInt(86400))
+		if err != nil {
+			t.Fatalf("failed to pack the event: %v", err)
+		}
+		node.AddLogs(types.Log{
+			Address:     timelock,
+			Topics:      []common.Hash{event.ID, id, common.BigToHash(big.NewInt(int64(i)))},
+			Data:        packed,
+			BlockNumber: header.Number.Uint64(),
+			BlockHash:   header.Hash(),
+			TxHash:      common.BigToHash(header.Number),
+		})
+	}
+}
+
+// newTestMonitor returns the monitor of the timelock, the timelock contract and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *fake.Node) (*Monitor, *fake.Contract, *fake.Notifier) {
+	contract := node.Contract(timelock, timelockControllerABI).
+		Returns("getMinDelay", big.NewInt(86400)).
+		Returns("getTimestamp", big.NewInt(87400))
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), CLIConfig{NodeURL: node.URL, TimelockAddress: timelock, EventBlockRange: 100})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, contract, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{Time: 900})
+	m, contract, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if minDelay, queued := testutil.ToFloat64(m.minDelay), testutil.ToFloat64(m.queuedOperations); minDelay != 86400 || queued != 0 {
+		t.Errorf("expected a min delay of a day without queued operation but got %v and %v", minDelay, queued)
+	}
+
+	// the scheduled operation is queued with the time before each of its calls becomes executable.
+	upgrade, err := adminFunctionsABI.Pack("upgrade", common.HexToAddress("0x1111111111111111111111111111111111111111"), common.HexToAddress("0x2222222222222222222222222222222222222222"))
+	if err != nil {
+		t.Fatalf("failed to pack the call: %v", err)
+	}
+	id, target := common.HexToHash("0x0a"), common.HexToAddress("0x4444444444444444444444444444444444444444")
+	schedule(t, node, id, target, upgrade, []byte{0xde, 0xad, 0xbe, 0xef})
+	m.Run(ctx)
+	if scheduled, queued, unknown := testutil.ToFloat64(m.operationsScheduled), testutil.ToFloat64(m.queuedOperations), testutil.ToFloat64(m.unknownCalls); scheduled != 1 || queued != 1 || unknown != 1 {
+		t.Errorf("expected 1 operation scheduled and queued with 1 unknown call but got %v, %v and %v", scheduled, queued, unknown)
+	}
+	if count := testutil.CollectAndCount(m.secondsUntilExecutable); count != 2 {
+		t.Errorf("expected the time before executable of the 2 calls but got %d", count)
+	}
+	if seconds := testutil.ToFloat64(m.secondsUntilExecutable.WithLabelValues(id.Hex(), "0", target.String(), adminFunctionsABI.Methods["upgrade"].Sig)); seconds != 86400 {
+		t.Errorf("expected the upgrade executable in a day but got %v", seconds)
+	}
+
+	// the executed operation is counted and its series deleted.
+	contract.Returns("getTimestamp", big.NewInt(DoneTimestamp))
+	m.Run(ctx)
+	if executed, queued := testutil.ToFloat64(m.operationsCompleted.WithLabelValues("executed")), testutil.ToFloat64(m.queuedOperations); executed != 1 || queued != 0 {
+		t.Errorf("expected the operation executed and no longer queued but got %v and %v", executed, queued)
+	}
+	if count := testutil.CollectAndCount(m.secondsUntilExecutable); count != 0 {
+		t.Errorf("expected the series of the executed calls deleted but got %d", count)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := fake.NewNode(t)
+	node.AddBlock(&types.Header{Time: 900})
+	m, contract, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// every call of a batch is reported once, the unknown calls being paged.
+	upgrade, err := adminFunctionsABI.Pack("upgrade", common.HexToAddress("0x1111111111111111111111111111111111111111"), common.HexToAddress("0x2222222222222222222222222222222222222222"))
+	if err != nil {
+		t.Fatalf("failed to pack the call: %v", err)
+	}
+	id := common.HexToHash("0x0a")
+	schedule(t, node, id, common.HexToAddress("0x4444444444444444444444444444444444444444"), upgrade, []byte{0xde, 0xad, 0xbe, 0xef})
+	m.Run(ctx)
+	m.Run(ctx)
+	alerts := notifier.Alerts(ScheduledRule)
+	if len(alerts) != 2 || alerts[0].Entity != id.Hex()+"/0" || alerts[0].Priority != "P2" || alerts[0].Labels["known"] != "true" {
+		t.Fatalf("expected the known call scheduled once but got %v", alerts)
+	}
+	if alerts[1].Entity != id.Hex()+"/1" || alerts[1].Priority != "P1" || alerts[1].Labels["known"] != "false" {
+		t.Fatalf("expected the unknown call scheduled once but got %v", alerts[1])
+	}
+
+	// the executed operation is forgotten.
+	contract.Returns("getTimestamp", big.NewInt(DoneTimestamp))
+	m.Run(ctx)
+	if len(m.operations) != 0 {
+		t.Fatalf("expected the executed operation forgotten but got %v", m.operations)
+	}
+}
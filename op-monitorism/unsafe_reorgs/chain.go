@@ -0,0 +1,72 @@
+package unsafe_reorgs
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// unsafeChain is the contiguous range of the unsafe blocks seen by the monitor, by number.
+type unsafeChain struct {
+	hashes map[uint64]common.Hash
+
+	// lowest and highest are the bounds of the tracked blocks, meaningful when some blocks are tracked.
+	lowest  uint64
+	highest uint64
+}
+
+func newUnsafeChain() *unsafeChain {
+	return &unsafeChain{hashes: make(map[uint64]common.Hash)}
+}
+
+// replaced returns the number of tracked blocks no longer canonical with the new unsafe head, and the highest tracked block still
+// canonical, the blocks above the new head being dropped. The canonical chain is walked down from the new head until a tracked block
+// matches, through `canonical` returning the canonical hash at a height.
+func (c *unsafeChain) replaced(head uint64, canonical func(uint64) (common.Hash, error)) (uint64, uint64, error) {
+	if len(c.hashes) == 0 {
+		return 0, 0, nil
+	}
+
+	depth := uint64(0)
+	n := c.highest
+	if head < c.highest {
+		depth, n = c.highest-head, head
+	}
+	for ; n >= c.lowest; n-- {
+		hash, err := canonical(n)
+		if err != nil {
+			return 0, 0, err
+		}
+		if hash == c.hashes[n] {
+			return depth, n, nil
+		}
+		depth++
+		if n == 0 {
+			break
+		}
+	}
+	// no tracked block is canonical anymore, the reorg is deeper than the tracked blocks.
+	return depth, 0, nil
+}
+
+// truncate drops the tracked blocks above the height.
+func (c *unsafeChain) truncate(height uint64) {
+	for n := height + 1; n <= c.highest; n++ {
+		delete(c.hashes, n)
+	}
+	if len(c.hashes) == 0 {
+		c.lowest, c.highest = 0, 0
+		return
+	}
+	c.highest = min(c.highest, height)
+}
+
+// add tracks the block on top of the tracked blocks, restarting the tracking on a gap, and drops the blocks beyond the window.
+func (c *unsafeChain) add(number uint64, hash common.Hash, window uint64) {
+	if len(c.hashes) == 0 || number != c.highest+1 {
+		c.hashes = make(map[uint64]common.Hash)
+		c.lowest = number
+	}
+	c.hashes[number], c.highest = hash, number
+	for ; c.highest-c.lowest >= window; c.lowest++ {
+		delete(c.hashes, c.lowest)
+	}
+}
@@ -0,0 +1,84 @@
+package unsafe_reorgs
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// branch returns the canonical hashes of a chain forking from the tracked one at the height, 0 for no fork.
+func branch(fork uint64) func(uint64) (common.Hash, error) {
+	return func(n uint64) (common.Hash, error) {
+		if fork > 0 && n >= fork {
+			return common.BigToHash(new(big.Int).SetUint64(n + 1_000_000)), nil
+		}
+		return hashAt(n), nil
+	}
+}
+
+func TestReplaced(t *testing.T) {
+	tests := []struct {
+		name              string
+		head              uint64
+		fork              uint64
+		expectedDepth     uint64
+		expectedForkPoint uint64
+	}{
+		{name: "Extended chain", head: 120, expectedDepth: 0, expectedForkPoint: 110},
+		{name: "Replaced head", head: 110, fork: 110, expectedDepth: 1, expectedForkPoint: 109},
+		{name: "Replaced blocks", head: 112, fork: 108, expectedDepth: 3, expectedForkPoint: 107},
+		{name: "Rolled back head", head: 105, expectedDepth: 5, expectedForkPoint: 105},
+		{name: "Deeper than tracked", head: 110, fork: 50, expectedDepth: 11, expectedForkPoint: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chain := newUnsafeChain()
+			for n := uint64(90); n <= 110; n++ {
+				chain.add(n, hashAt(n), 11)
+			}
+			depth, forkPoint, err := chain.replaced(test.head, branch(test.fork))
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if depth != test.expectedDepth || forkPoint != test.expectedForkPoint {
+				t.Errorf("Failed %s: expected depth %d from %d but got %d from %d", test.name, test.expectedDepth, test.expectedForkPoint, depth, forkPoint)
+			}
+		})
+	}
+}
+
+func TestReplacedError(t *testing.T) {
+	chain := newUnsafeChain()
+	chain.add(10, hashAt(10), 10)
+	failing := func(uint64) (common.Hash, error) { return common.Hash{}, errors.New("unavailable") }
+	if _, _, err := chain.replaced(11, failing); err == nil {
+		t.Errorf("expected the error of the canonical chain")
+	}
+}
+
+func TestAddAndTruncate(t *testing.T) {
+	chain := newUnsafeChain()
+	for n := uint64(1); n <= 20; n++ {
+		chain.add(n, hashAt(n), 5)
+	}
+	if chain.lowest != 16 || chain.highest != 20 || len(chain.hashes) != 5 {
+		t.Errorf("expected the last 5 blocks but got %d to %d (%d blocks)", chain.lowest, chain.highest, len(chain.hashes))
+	}
+
+	chain.truncate(18)
+	if chain.highest != 18 || len(chain.hashes) != 3 {
+		t.Errorf("expected the blocks up to 18 but got %d to %d (%d blocks)", chain.lowest, chain.highest, len(chain.hashes))
+	}
+
+	chain.add(30, hashAt(30), 5) // gap, the tracking restarts.
+	if chain.lowest != 30 || chain.highest != 30 || len(chain.hashes) != 1 {
+		t.Errorf("expected the tracking to restart at 30 but got %d to %d (%d blocks)", chain.lowest, chain.highest, len(chain.hashes))
+	}
+}
+
+func hashAt(n uint64) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(n))
+}
@@ -0,0 +1,62 @@
+package unsafe_reorgs
+
+import (
+	"fmt"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	RollupNodeURLFlagName = "rollup.node.url"
+	L2NodeURLFlagName     = "l2.node.url"
+	TrackedBlocksFlagName = "tracked.blocks"
+)
+
+type CLIConfig struct {
+	RollupNodeURL string
+
+	// L2NodeURL is the execution node of the rollup node, the canonical blocks below the unsafe head being read from it.
+	L2NodeURL string
+
+	// TrackedBlocks is the number of unsafe blocks below the head tracked for a replacement, the max reorg depth detected.
+	TrackedBlocks uint64
+}
+
+func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	cfg := CLIConfig{
+		RollupNodeURL: ctx.String(RollupNodeURLFlagName),
+		L2NodeURL:     ctx.String(L2NodeURLFlagName),
+		TrackedBlocks: ctx.Uint64(TrackedBlocksFlagName),
+	}
+
+	if cfg.TrackedBlocks == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", TrackedBlocksFlagName)
+	}
+
+	return cfg, nil
+}
+
+func CLIFlags(envVar string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    RollupNodeURLFlagName,
+			Usage:   "Node URL of the rollup node following the unsafe head",
+			Value:   "127.0.0.1:7545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "ROLLUP_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    L2NodeURLFlagName,
+			Usage:   "Node URL of the execution node of the rollup node",
+			Value:   "127.0.0.1:9545",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL"),
+		},
+		&cli.Uint64Flag{
+			Name:    TrackedBlocksFlagName,
+			Usage:   "Number of unsafe blocks below the head tracked for a replacement, the max reorg depth detected",
+			Value:   256,
+			EnvVars: opservice.PrefixEnvVar(envVar, "TRACKED_BLOCKS"),
+		},
+	}
+}
@@ -0,0 +1,180 @@
+package unsafe_reorgs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MonitorName      = "unsafe_reorgs"
+	MetricsNamespace = "unsafe_reorgs_mon"
+
+	// ReorgRule is the rule of the alert emitted for every unsafe reorg, the entity being the fork point.
+	ReorgRule = "unsafe reorg"
+)
+
+type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
+	log log.Logger
+
+	rollupClient client.RPC
+	l2Client     *ethclient.Client
+
+	chain         *unsafeChain
+	trackedBlocks uint64
+
+	// metrics
+	unsafeHead          prometheus.Gauge
+	safeHead            prometheus.Gauge
+	reorgs              prometheus.Counter
+	reorgedBlocks       prometheus.Counter
+	lastReorgDepth      prometheus.Gauge
+	reorgDepth          prometheus.Histogram
+	unexpectedRpcErrors *prometheus.CounterVec
+}
+
+func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
+	log.Info("creating unsafe reorgs monitor...")
+
+	rollupClient, err := client.NewRPC(ctx, log, cfg.RollupNodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial the rollup node: %w", err)
+	}
+	l2Client, err := ethclient.Dial(cfg.L2NodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l2: %w", err)
+	}
+
+	return &Monitor{
+		log: log,
+
+		rollupClient: rollupClient,
+		l2Client:     l2Client,
+
+		chain:         newUnsafeChain(),
+		trackedBlocks: cfg.TrackedBlocks,
+
+		unsafeHead: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unsafeHead",
+			Help:      "block number of the unsafe head of the rollup node",
+		}),
+		safeHead: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "safeHead",
+			Help:      "block number of the safe head of the rollup node",
+		}),
+		reorgs: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "reorgs",
+			Help:      "number of reorgs of the unsafe chain",
+		}),
+		reorgedBlocks: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "reorgedBlocks",
+			Help:      "number of unsafe blocks replaced or dropped",
+		}),
+		lastReorgDepth: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "lastReorgDepth",
+			Help:      "number of unsafe blocks replaced or dropped by the last reorg",
+		}),
+		reorgDepth: m.NewHistogram(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Name:      "reorgDepth",
+			Help:      "number of unsafe blocks replaced or dropped by the reorgs",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 9),
+		}),
+		unexpectedRpcErrors: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unexpectedRpcErrors",
+			Help:      "number of unexpected rpc errors",
+		}, []string{"section", "name"}),
+	}, nil
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	var status eth.SyncStatus
+	if err := m.rollupClient.CallContext(ctx, &status, "optimism_syncStatus"); err != nil {
+		m.log.Error("failed to query the sync status", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("rollup", "syncStatus").Inc()
+		return
+	}
+	head := status.UnsafeL2
+	m.unsafeHead.Set(float64(head.Number))
+	m.safeHead.Set(float64(status.SafeL2.Number))
+
+	// the execution node may be ahead of the sync status, the hash of the head is the one seen by the rollup node.
+	canonical := func(n uint64) (common.Hash, error) {
+		if n == head.Number {
+			return head.Hash, nil
+		}
+		header, err := m.l2Client.HeaderByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return header.Hash(), nil
+	}
+
+	depth, forkPoint, err := m.chain.replaced(head.Number, canonical)
+	if err != nil {
+		m.log.Error("failed to compare the unsafe chain", "err", err)
+		m.unexpectedRpcErrors.WithLabelValues("l2", "headerByNumber").Inc()
+		return
+	}
+	if depth > 0 {
+		m.log.Warn("unsafe reorg", "depth", depth, "fork_point", forkPoint, "previous_head", m.chain.highest, "head", head.Number, "head_hash", head.Hash, "safe_head", status.SafeL2.Number)
+		m.reorgs.Inc()
+		m.reorgedBlocks.Add(float64(depth))
+		m.lastReorgDepth.Set(float64(depth))
+		m.reorgDepth.Observe(float64(depth))
+		m.Emit(ctx, alerts.Alert{
+			Monitor:  MonitorName,
+			Rule:     ReorgRule,
+			Priority: "P2",
+			Entity:   strconv.FormatUint(forkPoint, 10),
+			Summary:  fmt.Sprintf("unsafe reorg of %d blocks after block %d, new head %d (%s)", depth, forkPoint, head.Number, head.Hash),
+			Labels:   map[string]string{"depth": strconv.FormatUint(depth, 10)},
+		})
+		m.chain.truncate(forkPoint)
+	}
+
+	// track the new unsafe blocks, up to the tracked blocks below the head.
+	from := head.Number
+	if len(m.chain.hashes) > 0 {
+		from = m.chain.highest + 1
+	}
+	if head.Number >= m.trackedBlocks {
+		from = max(from, head.Number-m.trackedBlocks+1)
+	}
+	for n := from; n <= head.Number; n++ {
+		hash, err := canonical(n)
+		if err != nil {
+			m.log.Error("failed to query the unsafe block", "number", n, "err", err)
+			m.unexpectedRpcErrors.WithLabelValues("l2", "headerByNumber").Inc()
+			return
+		}
+		m.chain.add(n, hash, m.trackedBlocks)
+	}
+}
+
+func (m *Monitor) Close(_ context.Context) error {
+	m.rollupClient.Close()
+	m.l2Client.Close()
+	return nil
+}
@@ -0,0 +1,104 @@
+package unsafe_reorgs
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// l2Node is an execution node along with its rollup node, whose unsafe head is the last block added.
+type l2Node struct {
+	*fake.Node
+
+	head *types.Header
+}
+
+func newL2Node(t *testing.T) *l2Node {
+	n := &l2Node{Node: fake.NewNode(t)}
+	n.Handle("optimism_syncStatus", func(_ []json.RawMessage) (interface{}, error) {
+		return eth.SyncStatus{UnsafeL2: eth.L2BlockRef{Number: n.head.Number.Uint64(), Hash: n.head.Hash()}}, nil
+	})
+	return n
+}
+
+// addBlock adds the block as the unsafe head.
+func (n *l2Node) addBlock(header *types.Header) {
+	n.head = n.AddBlock(header)
+}
+
+// newTestMonitor returns the monitor of the last 10 unsafe blocks of the node, and the notifier of its alerts.
+func newTestMonitor(t *testing.T, node *l2Node) (*Monitor, *fake.Notifier) {
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), CLIConfig{RollupNodeURL: node.URL, L2NodeURL: node.URL, TrackedBlocks: 10})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+func TestRun(t *testing.T) {
+	node := newL2Node(t)
+	for i := 0; i < 4; i++ {
+		node.addBlock(&types.Header{})
+	}
+	m, _ := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	node.addBlock(&types.Header{})
+	node.addBlock(&types.Header{})
+	m.Run(ctx)
+	if head, reorgs := testutil.ToFloat64(m.unsafeHead), testutil.ToFloat64(m.reorgs); head != 5 || reorgs != 0 {
+		t.Errorf("expected the unsafe head 5 without reorg but got %v and %v", head, reorgs)
+	}
+
+	// the blocks 4 and 5 replaced by another branch are counted as a reorg of depth 2.
+	node.addBlock(&types.Header{Number: big.NewInt(4), Extra: []byte("reorg")})
+	node.addBlock(&types.Header{})
+	node.addBlock(&types.Header{})
+	m.Run(ctx)
+	if reorgs, blocks, depth := testutil.ToFloat64(m.reorgs), testutil.ToFloat64(m.reorgedBlocks), testutil.ToFloat64(m.lastReorgDepth); reorgs != 1 || blocks != 2 || depth != 2 {
+		t.Errorf("expected 1 reorg of 2 blocks but got %v, %v and %v", reorgs, blocks, depth)
+	}
+	if head := testutil.ToFloat64(m.unsafeHead); head != 6 {
+		t.Errorf("expected the unsafe head 6 but got %v", head)
+	}
+}
+
+func TestRunAlerts(t *testing.T) {
+	node := newL2Node(t)
+	for i := 0; i < 4; i++ {
+		node.addBlock(&types.Header{})
+	}
+	m, notifier := newTestMonitor(t, node)
+	ctx := context.Background()
+
+	m.Run(ctx)
+	node.addBlock(&types.Header{})
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// the block 4 replaced by another branch is reported once, at its fork point.
+	node.addBlock(&types.Header{Number: big.NewInt(4), Extra: []byte("reorg")})
+	node.addBlock(&types.Header{})
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(ReorgRule); len(alerts) != 1 || alerts[0].Entity != "3" || alerts[0].Priority != "P2" || alerts[0].Labels["depth"] != "1" {
+		t.Fatalf("expected the reorg alert once but got %v", alerts)
+	}
+}
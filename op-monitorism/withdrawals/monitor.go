@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ethereum-optimism/monitorism/op-monitorism/alerts"
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
@@ -19,8 +20,12 @@ import (
 )
 
 const (
+	MonitorName      = "withdrawals"
 	MetricsNamespace = "two_step_monitor"
 
+	// ForgeryRule is the rule of the alert emitted while a proven withdrawal was never sent on L2.
+	ForgeryRule = "forged withdrawal"
+
 	// event WithdrawalProven(bytes32 indexed withdrawalHash, address indexed from, address indexed to);
 	WithdrawalProvenEventABI = "WithdrawalProven(bytes32,address,address)"
 )
@@ -30,6 +35,9 @@ var (
 )
 
 type Monitor struct {
+	// Tracker emits the alerts of the monitor.
+	alerts.Tracker
+
 	log log.Logger
 
 	l1Client *ethclient.Client
@@ -169,6 +177,7 @@ func (m *Monitor) Run(ctx context.Context) {
 		if !seen {
 			m.log.Warn("forgery detected!!!!", "withdrawal_hash", withdrawalHash.String())
 			m.isDetectingForgeries.Set(1)
+			m.Update(ctx, m.forgeryAlert(fmt.Sprintf("proven withdrawal %s was never sent on L2, proven in transaction %s", withdrawalHash, provenWithdrawalLog.TxHash)), true)
 			return
 		}
 
@@ -180,9 +189,22 @@ func (m *Monitor) Run(ctx context.Context) {
 	// Update markers
 	m.nextL1Height = toBlockNumber + 1
 	m.isDetectingForgeries.Set(0)
+	m.Update(ctx, m.forgeryAlert("the proven withdrawals are validated"), false)
 	m.highestBlockNumber.WithLabelValues("checked").Set(float64(toBlockNumber))
 }
 
+// forgeryAlert returns the alert of a forged withdrawal, whose entity is the OptimismPortal as the monitor stops
+// at the first forgery until it is validated.
+func (m *Monitor) forgeryAlert(summary string) alerts.Alert {
+	return alerts.Alert{
+		Monitor:  MonitorName,
+		Rule:     ForgeryRule,
+		Priority: "P0",
+		Entity:   m.optimismPortalAddress.String(),
+		Summary:  summary,
+	}
+}
+
 func (m *Monitor) Close(_ context.Context) error {
 	m.l1Client.Close()
 	m.l2Client.Close()
@@ -0,0 +1,84 @@
+package withdrawals
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/internal/fake"
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var portal = common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+
+// newTestMonitor returns the monitor of the withdrawals proven on the L1 node, checked against the messages sent on L2,
+// and the notifier of its alerts.
+func newTestMonitor(t *testing.T, l1 *fake.Node, sent map[common.Hash]bool) (*Monitor, *fake.Notifier) {
+	l2 := fake.NewNode(t)
+	l2.AddBlock(&types.Header{})
+	messagePasserABI, err := bindings.L2ToL1MessagePasserMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	l2.Contract(predeploys.L2ToL1MessagePasserAddr, messagePasserABI).Handle("sentMessages", func(args []interface{}) ([]interface{}, error) {
+		return []interface{}{sent[args[0].([32]byte)]}, nil
+	})
+
+	cfg := CLIConfig{L1NodeURL: l1.URL, L2NodeURL: l2.URL, EventBlockRange: 100, OptimismPortalAddress: portal}
+	m, err := NewMonitor(context.Background(), log.New(), metrics.With(prometheus.NewRegistry()), cfg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+	notifier := &fake.Notifier{}
+	m.SetNotifier(notifier)
+	return m, notifier
+}
+
+// prove adds a block proving the withdrawal on the L1 node.
+func prove(node *fake.Node, withdrawalHash common.Hash) {
+	header := node.AddBlock(&types.Header{})
+	node.AddLogs(types.Log{
+		Address:     portal,
+		Topics:      []common.Hash{WithdrawalProvenEventABIHash, withdrawalHash, {}, {}},
+		BlockNumber: header.Number.Uint64(),
+		BlockHash:   header.Hash(),
+		TxHash:      common.BigToHash(header.Number),
+	})
+}
+
+func TestRunAlerts(t *testing.T) {
+	l1 := fake.NewNode(t)
+	l1.AddBlock(&types.Header{})
+	sent := map[common.Hash]bool{common.HexToHash("0x01"): true}
+	m, notifier := newTestMonitor(t, l1, sent)
+	ctx := context.Background()
+
+	prove(l1, common.HexToHash("0x01"))
+	m.Run(ctx)
+	if alerts := notifier.Alerts(""); len(alerts) != 0 {
+		t.Fatalf("expected no alert but got %v", alerts)
+	}
+
+	// a withdrawal proven without being sent fires once while the monitor retries its range.
+	prove(l1, common.HexToHash("0x02"))
+	m.Run(ctx)
+	m.Run(ctx)
+	if alerts := notifier.Alerts(ForgeryRule); len(alerts) != 1 || alerts[0].Entity != portal.String() || alerts[0].Priority != "P0" {
+		t.Fatalf("expected the forgery alert once but got %v", alerts)
+	}
+
+	// the withdrawal validated resolves the alert.
+	sent[common.HexToHash("0x02")] = true
+	m.Run(ctx)
+	if firing := notifier.Firing(ForgeryRule); len(firing) != 0 {
+		t.Fatalf("expected the forgery alert resolved but got %v", firing)
+	}
+}